@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/bench"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const benchUsage = `
+カオス注入・復旧なしで、ノードのストアとワーカープールの性能を単体で計測します。
+
+Examples:
+  # 全てのベンチマークを実行
+  chaos-kvs bench
+
+  # ノードのベンチマークのみ、8ワーカーで10秒ずつ実行
+  chaos-kvs bench --only node-get,node-set --workers 8 --duration 10s
+
+  # ワーカープールのCPUプロファイルを取得
+  chaos-kvs bench --only pool-submit --cpu-profile pool.out
+
+  # 結果をJSONで保存
+  chaos-kvs bench --output json > bench.json
+`
+
+// benchCommand はノードのストアとワーカープールのベンチマークを実行する
+func benchCommand(args []string) error {
+	defaults := bench.DefaultConfig()
+	fs := newFlagSet("bench", "bench [options]", benchUsage)
+	var (
+		duration   = fs.Duration("duration", defaults.Duration, "ベンチマークごとの実行時間")
+		workers    = fs.Int("workers", defaults.Workers, "並行して操作するゴルーチン・ワーカーの数（0でCPU数）")
+		keys       = fs.Int("keys", defaults.Keys, "使用するキーの数")
+		valueSize  = fs.Int("value-size", defaults.ValueSize, "書き込む値のバイト数")
+		writeRatio = fs.Float64("write-ratio", defaults.WriteRatio, "node-mixed の書き込みの比率")
+		batchSize  = fs.Int("batch-size", defaults.BatchSize, "pool-batch で1度に送信するジョブ数")
+		only       = fs.String("only", "", "実行するベンチマーク（カンマ区切り、空で全て: "+strings.Join(bench.Names(), ", ")+"）")
+		output     = fs.String("output", "text", "結果の出力形式 (text, json)")
+	)
+	profileOpts := addProfileFlags(fs)
+	if rest := parseArgs(fs, args); len(rest) > 0 {
+		return fmt.Errorf("bench は位置引数を取りません: %v", rest)
+	}
+
+	format, err := comparisonFormat(*output)
+	if err != nil {
+		return err
+	}
+
+	cfg := bench.Config{
+		Duration:   *duration,
+		Workers:    *workers,
+		Keys:       *keys,
+		ValueSize:  *valueSize,
+		WriteRatio: *writeRatio,
+		BatchSize:  *batchSize,
+	}
+	var names []string
+	for _, name := range strings.Split(*only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	// ノードの起動・停止などのログで結果が埋もれないようにする
+	logger.Default.SetLevel(logger.LevelWarn)
+
+	stopProfile, err := profileOpts.start()
+	if err != nil {
+		return err
+	}
+	defer stopProfile()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	results, err := bench.Run(ctx, cfg, names)
+	if err != nil {
+		return err
+	}
+
+	if format == scenario.ReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	fmt.Printf("%-12s %12s %14s %12s %12s %12s %12s\n",
+		"Benchmark", "Ops", "Ops/sec", "Avg", "P99", "Allocs/op", "Bytes/op")
+	for _, r := range results {
+		fmt.Printf("%-12s %12d %14.0f %12v %12v %12.2f %12.1f\n",
+			r.Name, r.Ops, r.OpsPerSec,
+			r.AvgLatency.Round(time.Nanosecond), r.P99Latency,
+			r.AllocsPerOp, r.BytesPerOp)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const capacityUsage = `
+シナリオを目標の秒間リクエスト数を変えて繰り返し実行し、SLO を満たす最大の目標を探索します。
+目標を --min-rps から倍々に上げ、満たさなくなったら最後に満たした目標との間を二分探索します。
+カオス注入なしで探索した後、シナリオでカオスが有効な場合はカオス注入ありでも探索し、両方の値を表示します。
+試行ではシナリオの duration・steps・assertions・通知先を使いません。
+
+Examples:
+  # P99 20ms 未満・エラー率1%未満で持続できる最大のスループットを探索
+  chaos-kvs capacity --preset resilience
+
+  # SLO と探索範囲を指定し、1回の試行を10秒にする
+  chaos-kvs capacity --config scenario.yaml --max-p99 10ms --max-error-rate 0.001 --max-rps 50000 --probe-duration 10s
+
+  # 結果をJSONで保存
+  chaos-kvs capacity --preset resilience --output json > capacity.json
+`
+
+// capacityCommand はカオス注入なし・ありで SLO を満たす最大のスループットを探索する
+func capacityCommand(args []string) error {
+	search := scenario.DefaultCapacitySearch()
+	fs := newFlagSet("capacity", "capacity [options]", capacityUsage)
+	var (
+		configFile = fs.String("config", "", "設定ファイルパスまたはURL (YAML/JSON)")
+		presetName = fs.String("preset", "", "プリセットシナリオ名 (basic, resilience, latency, stress, quick)")
+		nodes      = fs.Int("nodes", 0, "ノード数")
+		workers    = fs.Int("workers", 0, "クライアントワーカー数")
+		output     = fs.String("output", "text", "結果の出力形式 (text, json)")
+	)
+	fs.DurationVar(&search.SLO.MaxP99Latency, "max-p99", search.SLO.MaxP99Latency, "SLO の P99 レイテンシの上限（0で判定しない）")
+	fs.DurationVar(&search.SLO.MaxAvgLatency, "max-avg-latency", 0, "SLO の平均レイテンシの上限（0で判定しない）")
+	fs.Float64Var(&search.SLO.MaxErrorRate, "max-error-rate", search.SLO.MaxErrorRate, "SLO のエラー率の上限（0.01で1%、0で判定しない）")
+	fs.Float64Var(&search.MinRPS, "min-rps", search.MinRPS, "最初に試す秒間リクエスト数")
+	fs.Float64Var(&search.MaxRPS, "max-rps", search.MaxRPS, "試す秒間リクエスト数の上限")
+	fs.Float64Var(&search.Precision, "precision", search.Precision, "満たした目標と満たさなかった目標の差がこの比率以下になったら探索を終える")
+	fs.IntVar(&search.MaxProbes, "max-probes", search.MaxProbes, "カオスなし・ありそれぞれの試行数の上限")
+	fs.DurationVar(&search.ProbeDuration, "probe-duration", search.ProbeDuration, "1回の試行の実行時間")
+	fs.Float64Var(&search.MinAchieved, "min-achieved", search.MinAchieved, "目標に対して達成すべきスループットの比率（0で判定しない）")
+	configOpts := addConfigFlags(fs)
+	logOpts := addLogFlags(fs)
+	if rest := parseArgs(fs, args); len(rest) > 0 {
+		return fmt.Errorf("capacity は位置引数を取りません: %v", rest)
+	}
+
+	if err := logOpts.setup(); err != nil {
+		return err
+	}
+	format, err := comparisonFormat(*output)
+	if err != nil {
+		return err
+	}
+	if format != scenario.ReportText {
+		logger.Default.SetOutput(os.Stderr)
+	}
+
+	var flagOverrides config.Overrides
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "nodes":
+			flagOverrides.NodeCount = nodes
+		case "workers":
+			flagOverrides.ClientWorkers = workers
+		}
+	})
+	scenarioConfig, _, err := buildScenarioConfig(
+		*configFile, configOpts.loadOptions(), configOpts.profile, *presetName, flagOverrides,
+	)
+	if err != nil {
+		return fmt.Errorf("設定エラー: %w", err)
+	}
+	if err := search.Validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := scenario.FindCapacity(ctx, scenarioConfig, search)
+	if err != nil {
+		return fmt.Errorf("探索エラー: %w", err)
+	}
+
+	if format == scenario.ReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	_, err = fmt.Print(result.Report())
+	return err
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const compareUsage = `
+<base> <current> には serve --history-dir に保存された実行記録、または JSON 形式のレポートを指定します。
+serve --history-db のデータベースの場合は <db>#<id> で実行を指定します（id の省略で最新の実行）。
+回帰（閾値を超えた悪化）を検出した場合は終了コード 1 で終了します。
+
+Examples:
+  # 変更前後の結果を比較
+  chaos-kvs run --preset stress --output json > before.json
+  chaos-kvs run --preset stress --output json > after.json
+  chaos-kvs compare before.json after.json
+
+  # スループット 5% 以上の低下を回帰とし、比較結果をJSONで出力
+  chaos-kvs compare before.json after.json --max-throughput-drop 0.05 --output json
+
+  # 履歴データベースの過去の実行と最新の実行を比較
+  chaos-kvs compare history.db#20250101-120000-abcdef history.db
+`
+
+// errRegression は比較で回帰を検出したことを表す
+var errRegression = errors.New("回帰を検出しました")
+
+// compareCommand は2つの結果の指標を比較し、閾値を超えた悪化を回帰として報告する
+func compareCommand(args []string) error {
+	fs := newFlagSet("compare", "compare [options] <base> <current>", compareUsage)
+	output := fs.String("output", "text", "比較結果の出力形式 (text, json)")
+	thresholds := addThresholdFlags(fs)
+	rest := parseArgs(fs, args)
+	if len(rest) != 2 {
+		fs.Usage()
+		return fmt.Errorf("compare には比較する結果のファイルを2つ指定してください")
+	}
+
+	format, err := comparisonFormat(*output)
+	if err != nil {
+		return err
+	}
+	base, err := readResult(rest[0])
+	if err != nil {
+		return err
+	}
+	current, err := readResult(rest[1])
+	if err != nil {
+		return err
+	}
+
+	comparison := scenario.Compare(base, current, *thresholds)
+	comparison.Base = rest[0]
+	comparison.Current = rest[1]
+	if err := writeComparison(comparison, format); err != nil {
+		return err
+	}
+	if len(comparison.Regressions()) > 0 {
+		return errRegression
+	}
+	return nil
+}
+
+// addThresholdFlags は回帰とみなす閾値のフラグを登録する（compare・replay で共通）
+func addThresholdFlags(fs *flag.FlagSet) *scenario.Thresholds {
+	t := scenario.DefaultThresholds()
+	fs.Float64Var(&t.Throughput, "max-throughput-drop", t.Throughput, "回帰とみなすスループットの低下率（0.1で10%、0で判定しない）")
+	fs.Float64Var(&t.ErrorRate, "max-error-rate-increase", t.ErrorRate, "回帰とみなすエラー率の増加幅（0.01で1ポイント、0で判定しない）")
+	fs.Float64Var(&t.AvgLatency, "max-latency-increase", t.AvgLatency, "回帰とみなす平均レイテンシの増加率（0で判定しない）")
+	fs.Float64Var(&t.P99Latency, "max-p99-increase", t.P99Latency, "回帰とみなすP99レイテンシの増加率（0で判定しない）")
+	return &t
+}
+
+// comparisonFormat は比較結果の出力形式を解析する（text または json）
+func comparisonFormat(name string) (scenario.ReportFormat, error) {
+	format, err := scenario.ParseReportFormat(name)
+	if err != nil {
+		return "", err
+	}
+	if format != scenario.ReportText && format != scenario.ReportJSON {
+		return "", fmt.Errorf("比較結果の出力形式は text または json です: %s", name)
+	}
+	return format, nil
+}
+
+// writeComparison は比較結果を標準出力に書き出す
+func writeComparison(c *scenario.Comparison, format scenario.ReportFormat) error {
+	if format == scenario.ReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	}
+	_, err := fmt.Print(c.Report())
+	return err
+}
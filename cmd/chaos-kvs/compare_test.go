@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// captureStdout は fn の実行中に標準出力へ書き出された内容を返す
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	_ = w.Close()
+	return <-done
+}
+
+// writeJSON は v を JSON でテスト用のディレクトリに書き出し、そのパスを返す
+func writeJSON(t *testing.T, name string, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode %s: %v", name, err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// compareResult は比較用の実行結果を作成する（10秒間の requests 件のリクエスト）
+func compareResult(requests uint64, errorRate float64, p99 time.Duration) *scenario.Result {
+	return &scenario.Result{
+		ScenarioName:  "compare",
+		StartTime:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration:      10 * time.Second,
+		TotalRequests: requests,
+		ErrorRate:     errorRate,
+		AvgLatency:    time.Millisecond,
+		P99Latency:    p99,
+	}
+}
+
+func TestCompareCommand(t *testing.T) {
+	base := writeJSON(t, "base.json", compareResult(1000, 0.01, 10*time.Millisecond))
+	same := writeJSON(t, "same.json", compareResult(1000, 0.01, 10*time.Millisecond))
+	// 実行記録の形式（history.Run）も読み込める
+	slower := writeJSON(t, "slower.json", history.Run{
+		ID:     "run-1",
+		Result: compareResult(700, 0.05, 30*time.Millisecond), // スループット30%低下・エラー率4ポイント増加・P99 3倍
+	})
+
+	tests := []struct {
+		name       string
+		args       []string
+		regression bool
+		want       []string
+	}{
+		{
+			name: "no regression",
+			args: []string{base, same},
+			want: []string{"No regressions"},
+		},
+		{
+			name:       "regression",
+			args:       []string{base, slower},
+			regression: true,
+			want:       []string{"REGRESSION", "3 regression(s) detected"},
+		},
+		{
+			name:       "threshold disabled",
+			args:       []string{base, slower, "--max-p99-increase", "0", "--max-error-rate-increase", "0"},
+			regression: true,
+			want:       []string{"1 regression(s) detected"},
+		},
+		{
+			name: "thresholds above the change",
+			args: []string{"--max-throughput-drop", "0.5", "--max-error-rate-increase", "0.1", "--max-p99-increase", "5", base, slower},
+			want: []string{"No regressions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			out := captureStdout(t, func() { err = compareCommand(tt.args) })
+
+			if tt.regression {
+				if !errors.Is(err, errRegression) {
+					t.Fatalf("expected regression error, got %v", err)
+				}
+				if code := exitCode(err); code != exitFailure {
+					t.Errorf("expected exit code %d, got %d", exitFailure, code)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("expected output to contain %q:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareCommandJSON(t *testing.T) {
+	base := writeJSON(t, "base.json", compareResult(1000, 0.01, 10*time.Millisecond))
+	current := writeJSON(t, "current.json", compareResult(800, 0.01, 10*time.Millisecond))
+
+	var err error
+	out := captureStdout(t, func() { err = compareCommand([]string{base, current, "--output", "json"}) })
+	if !errors.Is(err, errRegression) {
+		t.Fatalf("expected regression error, got %v", err)
+	}
+
+	var c scenario.Comparison
+	if err := json.Unmarshal([]byte(out), &c); err != nil {
+		t.Fatalf("failed to decode comparison: %v\n%s", err, out)
+	}
+	if c.Base != base || c.Current != current {
+		t.Errorf("expected file names as base/current, got %q/%q", c.Base, c.Current)
+	}
+	regressions := c.Regressions()
+	if len(regressions) != 1 || regressions[0].Name != "Throughput" || regressions[0].Change != -0.2 {
+		t.Errorf("expected a 20%% throughput regression, got %+v", regressions)
+	}
+}
+
+func TestCompareCommandErrors(t *testing.T) {
+	base := writeJSON(t, "base.json", compareResult(1000, 0.01, 10*time.Millisecond))
+	invalid := writeJSON(t, "invalid.json", map[string]string{"foo": "bar"})
+
+	tests := map[string][]string{
+		"missing argument": {base},
+		"unknown format":   {base, base, "--output", "html"},
+		"missing file":     {base, filepath.Join(t.TempDir(), "missing.json")},
+		"not a result":     {base, invalid},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := compareCommand(args)
+			if err == nil || errors.Is(err, errRegression) {
+				t.Fatalf("expected input error, got %v", err)
+			}
+			if code := exitCode(err); code != exitFailure {
+				t.Errorf("expected exit code %d, got %d", exitFailure, code)
+			}
+		})
+	}
+}
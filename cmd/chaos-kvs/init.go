@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const initUsage = `
+質問に答えると chaos-kvs run --config で実行できるYAMLのシナリオファイルを作成します。
+各質問で Enter のみを入力すると [ ] 内のデフォルト値を使います。
+標準入力が終了した場合は残りの質問にデフォルト値を使います。
+
+Examples:
+  # 対話形式で scenario.yaml を作成
+  chaos-kvs init
+
+  # stress プリセットの値をデフォルトにして作成
+  chaos-kvs init stress.yaml --preset stress
+
+  # 全てデフォルト値で作成（既存のファイルを上書き）
+  chaos-kvs init --force < /dev/null
+`
+
+// initCommand は対話形式でシナリオファイルを作成する
+func initCommand(args []string) error {
+	fs := newFlagSet("init", "init [options] [file]", initUsage)
+	var (
+		presetName = fs.String("preset", "", "デフォルト値にするプリセット名 (basic, resilience, latency, stress, quick)")
+		force      = fs.Bool("force", false, "既存のファイルを上書きする")
+	)
+	rest := parseArgs(fs, args)
+	if len(rest) > 1 {
+		return fmt.Errorf("init に指定できるファイルは1つです: %v", rest)
+	}
+	path := "scenario.yaml"
+	if len(rest) == 1 {
+		path = rest[0]
+	}
+
+	base := scenario.DefaultConfig()
+	if *presetName != "" {
+		preset, ok := scenario.GetPreset(*presetName)
+		if !ok {
+			return fmt.Errorf("不明なプリセット: %s (利用可能: %v)", *presetName, scenario.ListPresets())
+		}
+		base = preset
+	}
+	if !*force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s は既に存在します（上書きする場合は --force を指定してください）", path)
+		}
+	}
+
+	fmt.Printf("ChaosKVS シナリオファイルを作成します: %s\n\n", path)
+	fileConfig := newWizard(os.Stdin, os.Stdout).run(base)
+	if err := fileConfig.Validate(); err != nil {
+		return fmt.Errorf("設定検証エラー: %w", err)
+	}
+	if _, err := fileConfig.ToScenarioConfig(); err != nil {
+		return fmt.Errorf("設定変換エラー: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := scenarioFileTemplate.Execute(&buf, fileConfig.Scenario); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("シナリオファイルを作成できません: %w", err)
+	}
+
+	fmt.Printf("\n%s を作成しました\n", path)
+	fmt.Printf("実行: chaos-kvs run --config %s\n", path)
+	return nil
+}
+
+// wizard は質問を表示して回答を読み取る
+type wizard struct {
+	in  *bufio.Scanner
+	out io.Writer
+	eof bool // 入力が終了したか（以降はデフォルト値を使う）
+}
+
+// newWizard は in から回答を読み取り、質問を out に表示するウィザードを作成する
+func newWizard(in io.Reader, out io.Writer) *wizard {
+	return &wizard{in: bufio.NewScanner(in), out: out}
+}
+
+// run は質問に答えてもらい、base をデフォルト値としたシナリオファイルの設定を返す
+func (w *wizard) run(base scenario.Config) *config.FileConfig {
+	sc := config.ScenarioConfig{
+		Name:        w.askString("シナリオ名", base.Name),
+		Description: w.askString("説明", base.Description),
+		NodeCount:   w.askInt("ノード数", base.NodeCount, 1),
+	}
+	sc.Duration = w.askDuration("実行時間 (例: 30s, 5m)", base.Duration).String()
+	sc.Client.Workers = w.askInt("クライアントワーカー数", base.ClientWorkers, 1)
+	sc.Client.WriteRatio = base.WriteRatio
+
+	fmt.Fprintln(w.out)
+	chaosEnabled := w.askBool("障害を注入しますか", base.EnableChaos)
+	sc.Chaos.Enabled = &chaosEnabled
+	sc.Chaos.Interval = base.ChaosInterval.String()
+	sc.Chaos.Targets = base.ChaosTargets
+	sc.Chaos.AttackTypes = attackTypeNames(base)
+	if chaosEnabled {
+		sc.Chaos.AttackTypes = w.askAttackTypes(sc.Chaos.AttackTypes)
+		sc.Chaos.Interval = w.askDuration("障害を注入する間隔", base.ChaosInterval).String()
+		sc.Chaos.Targets = w.askInt("1回に障害を注入するノード数", base.ChaosTargets, 1)
+	}
+	recoveryEnabled := w.askBool("停止したノードを自動で復旧しますか", base.EnableRecovery)
+	sc.Recovery.Enabled = &recoveryEnabled
+	sc.Recovery.Delay = base.RecoveryDelay.String()
+	sc.Recovery.MaxRetries = base.MaxRetries
+	if recoveryEnabled {
+		sc.Recovery.Delay = w.askDuration("復旧までの待機時間", base.RecoveryDelay).String()
+	}
+
+	fmt.Fprintln(w.out)
+	fmt.Fprintln(w.out, "SLO（満たさない場合 chaos-kvs run は終了コード3で終了します。Enter のみで判定しない）")
+	sc.Assertions.MaxErrorRate = w.askFloat("エラー率の上限 (例: 0.05 で5%)", base.Assertions.MaxErrorRate, 0, 1)
+	sc.Assertions.MaxP99Latency = w.askOptionalDuration("P99レイテンシの上限 (例: 50ms)", base.Assertions.MaxP99Latency)
+	sc.Assertions.MinThroughput = w.askFloat("スループットの下限 (秒間リクエスト数)", base.Assertions.MinThroughput, 0, 0)
+
+	return &config.FileConfig{Scenario: sc}
+}
+
+// attackTypeNames は設定の攻撃タイプを設定ファイルの名前で返す
+func attackTypeNames(cfg scenario.Config) []string {
+	names := make([]string, 0, len(cfg.AttackTypes))
+	for _, a := range cfg.AttackTypes {
+		names = append(names, a.String())
+	}
+	return names
+}
+
+// ask は質問を表示して回答を返す。空の回答・入力の終了では def を返す
+// valid が nil 以外のエラーを返す間は質問を繰り返す
+func (w *wizard) ask(question, def string, valid func(string) error) string {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", question)
+		}
+		if w.eof || !w.in.Scan() {
+			w.eof = true
+			fmt.Fprintln(w.out)
+			return def
+		}
+		answer := strings.TrimSpace(w.in.Text())
+		if answer == "" {
+			return def
+		}
+		if valid == nil {
+			return answer
+		}
+		err := valid(answer)
+		if err == nil {
+			return answer
+		}
+		fmt.Fprintf(w.out, "  %v\n", err)
+	}
+}
+
+// askString は文字列の回答を返す
+func (w *wizard) askString(question, def string) string {
+	return w.ask(question, def, nil)
+}
+
+// askInt は min 以上の整数の回答を返す
+func (w *wizard) askInt(question string, def, min int) int {
+	answer := w.ask(question, strconv.Itoa(def), func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min {
+			return fmt.Errorf("%d 以上の整数を入力してください", min)
+		}
+		return nil
+	})
+	n, _ := strconv.Atoi(answer)
+	return n
+}
+
+// askFloat は min 以上（max が0より大きければ max 以下）の数値の回答を返す
+// def が0の場合はデフォルト値を表示しない
+func (w *wizard) askFloat(question string, def, min, max float64) float64 {
+	defText := ""
+	if def != 0 {
+		defText = strconv.FormatFloat(def, 'f', -1, 64)
+	}
+	answer := w.ask(question, defText, func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		switch {
+		case err != nil || v < min:
+			return fmt.Errorf("%g 以上の数値を入力してください", min)
+		case max > 0 && v > max:
+			return fmt.Errorf("%g 以下の数値を入力してください", max)
+		}
+		return nil
+	})
+	if answer == "" {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(answer, 64)
+	return v
+}
+
+// askDuration は正の期間の回答を返す
+func (w *wizard) askDuration(question string, def time.Duration) time.Duration {
+	answer := w.ask(question, def.String(), validDuration)
+	d, _ := time.ParseDuration(answer)
+	return d
+}
+
+// askOptionalDuration は期間の回答を設定ファイルの文字列で返す。def が0で回答が空の場合は空文字列を返す
+func (w *wizard) askOptionalDuration(question string, def time.Duration) string {
+	defText := ""
+	if def > 0 {
+		defText = def.String()
+	}
+	return w.ask(question, defText, validDuration)
+}
+
+// validDuration は正の期間（例: 30s）かを検証する
+func validDuration(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return errors.New("正の期間を入力してください (例: 500ms, 30s, 5m)")
+	}
+	return nil
+}
+
+// askBool は y/n の回答を返す
+func (w *wizard) askBool(question string, def bool) bool {
+	defText := "y/N"
+	if def {
+		defText = "Y/n"
+	}
+	answer := w.ask(question, defText, func(s string) error {
+		if _, ok := parseYesNo(s); !ok {
+			return errors.New("y または n を入力してください")
+		}
+		return nil
+	})
+	if v, ok := parseYesNo(answer); ok {
+		return v
+	}
+	return def
+}
+
+// parseYesNo は y/n の回答を解析する
+func parseYesNo(s string) (value, ok bool) {
+	switch strings.ToLower(s) {
+	case "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// askAttackTypes はカンマ区切りの攻撃タイプの回答を返す
+func (w *wizard) askAttackTypes(def []string) []string {
+	answer := w.ask("障害の種類 (kill, suspend, delay, readonly, corrupt をカンマ区切り)", strings.Join(def, ","), func(s string) error {
+		if len(splitList(s)) == 0 {
+			return errors.New("障害の種類を1つ以上入力してください")
+		}
+		for _, t := range splitList(s) {
+			switch t {
+			case "kill", "suspend", "delay", "readonly", "corrupt":
+			default:
+				return fmt.Errorf("不明な障害の種類: %s", t)
+			}
+		}
+		return nil
+	})
+	return splitList(answer)
+}
+
+// splitList はカンマ区切りの値を小文字にして返す（空の要素は除く）
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// scenarioFileTemplate は init で作成するシナリオファイルのテンプレート
+var scenarioFileTemplate = template.Must(template.New("scenario").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"number": func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	},
+}).Parse(`# ChaosKVS シナリオ設定ファイル（chaos-kvs init で作成）
+# 実行: chaos-kvs run --config <このファイル>
+scenario:
+  name: {{quote .Name}}
+  description: {{quote .Description}}
+  duration: {{.Duration}}
+  node_count: {{.NodeCount}}
+
+  client:
+    workers: {{.Client.Workers}}
+    write_ratio: {{number .Client.WriteRatio}}
+
+  chaos:
+    enabled: {{.Chaos.Enabled}}
+    interval: {{.Chaos.Interval}}
+    targets: {{.Chaos.Targets}}
+    attack_types:
+{{- range .Chaos.AttackTypes}}
+      - {{.}}
+{{- end}}
+
+  recovery:
+    enabled: {{.Recovery.Enabled}}
+    delay: {{.Recovery.Delay}}
+    max_retries: {{.Recovery.MaxRetries}}
+
+  # 実行結果が満たすべき条件。満たさない場合 chaos-kvs run は終了コード3で終了する
+{{- with .Assertions}}
+{{- if or .MaxErrorRate .MaxP99Latency .MinThroughput}}
+  assertions:
+{{- if .MaxErrorRate}}
+    max_error_rate: {{number .MaxErrorRate}}
+{{- end}}
+{{- if .MaxP99Latency}}
+    max_p99_latency: {{.MaxP99Latency}}
+{{- end}}
+{{- if .MinThroughput}}
+    min_throughput: {{number .MinThroughput}}
+{{- end}}
+{{- else}}
+  # assertions:
+  #   max_error_rate: 0.05
+  #   max_p99_latency: 50ms
+  #   min_throughput: 1000
+{{- end}}
+{{- end}}
+`))
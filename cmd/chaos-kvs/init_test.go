@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// loadWizardFile はウィザードの設定をテンプレートで書き出し、config.LoadFile で読み込み直す
+func loadWizardFile(t *testing.T, fc *config.FileConfig) scenario.Config {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := scenarioFileTemplate.Execute(&buf, fc.Scenario); err != nil {
+		t.Fatalf("failed to render scenario file: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write scenario file: %v", err)
+	}
+
+	opts := config.DefaultLoadOptions()
+	opts.Strict = true
+	loaded, err := config.LoadFileWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("failed to load scenario file: %v\n%s", err, buf.String())
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Fatalf("scenario file is invalid: %v\n%s", err, buf.String())
+	}
+	cfg, err := loaded.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert scenario file: %v\n%s", err, buf.String())
+	}
+	return cfg
+}
+
+func TestWizard(t *testing.T) {
+	base := scenario.DefaultConfig()
+
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, cfg scenario.Config)
+	}{
+		{
+			name:  "defaults on empty input",
+			input: "",
+			check: func(t *testing.T, cfg scenario.Config) {
+				if cfg.Name != base.Name || cfg.NodeCount != base.NodeCount || cfg.Duration != base.Duration || cfg.ClientWorkers != base.ClientWorkers {
+					t.Errorf("expected base values, got name=%q nodes=%d duration=%v workers=%d", cfg.Name, cfg.NodeCount, cfg.Duration, cfg.ClientWorkers)
+				}
+				if cfg.EnableChaos != base.EnableChaos || cfg.EnableRecovery != base.EnableRecovery {
+					t.Errorf("expected base chaos/recovery, got %v/%v", cfg.EnableChaos, cfg.EnableRecovery)
+				}
+				if !reflect.DeepEqual(cfg.AttackTypes, base.AttackTypes) || cfg.ChaosInterval != base.ChaosInterval {
+					t.Errorf("expected base attacks, got %v every %v", cfg.AttackTypes, cfg.ChaosInterval)
+				}
+				if cfg.Assertions != (scenario.Assertions{}) {
+					t.Errorf("expected no assertions, got %+v", cfg.Assertions)
+				}
+			},
+		},
+		{
+			name: "all answers",
+			input: strings.Join([]string{
+				"my-test", "desc: with \"quotes\"", "7", "45s", "12",
+				"y", "kill, Delay", "3s", "2",
+				"y", "500ms",
+				"0.05", "50ms", "1000",
+			}, "\n") + "\n",
+			check: func(t *testing.T, cfg scenario.Config) {
+				if cfg.Name != "my-test" || cfg.Description != `desc: with "quotes"` {
+					t.Errorf("unexpected name/description: %q/%q", cfg.Name, cfg.Description)
+				}
+				if cfg.NodeCount != 7 || cfg.Duration != 45*time.Second || cfg.ClientWorkers != 12 {
+					t.Errorf("unexpected nodes=%d duration=%v workers=%d", cfg.NodeCount, cfg.Duration, cfg.ClientWorkers)
+				}
+				if !cfg.EnableChaos || cfg.ChaosInterval != 3*time.Second || cfg.ChaosTargets != 2 {
+					t.Errorf("unexpected chaos: enabled=%v interval=%v targets=%d", cfg.EnableChaos, cfg.ChaosInterval, cfg.ChaosTargets)
+				}
+				if want := []chaos.AttackType{chaos.AttackKill, chaos.AttackDelay}; !reflect.DeepEqual(cfg.AttackTypes, want) {
+					t.Errorf("expected attacks %v, got %v", want, cfg.AttackTypes)
+				}
+				if !cfg.EnableRecovery || cfg.RecoveryDelay != 500*time.Millisecond {
+					t.Errorf("unexpected recovery: enabled=%v delay=%v", cfg.EnableRecovery, cfg.RecoveryDelay)
+				}
+				want := scenario.Assertions{MaxErrorRate: 0.05, MaxP99Latency: 50 * time.Millisecond, MinThroughput: 1000}
+				if cfg.Assertions != want {
+					t.Errorf("expected assertions %+v, got %+v", want, cfg.Assertions)
+				}
+			},
+		},
+		{
+			name:  "chaos and recovery disabled",
+			input: "\n\n\n\n\nn\nn\n",
+			check: func(t *testing.T, cfg scenario.Config) {
+				if cfg.EnableChaos || cfg.EnableRecovery {
+					t.Errorf("expected chaos and recovery disabled, got %v/%v", cfg.EnableChaos, cfg.EnableRecovery)
+				}
+			},
+		},
+		{
+			name:  "eof part-way",
+			input: "partial\n\n3",
+			check: func(t *testing.T, cfg scenario.Config) {
+				if cfg.Name != "partial" || cfg.NodeCount != 3 {
+					t.Errorf("expected answered values, got name=%q nodes=%d", cfg.Name, cfg.NodeCount)
+				}
+				if cfg.Duration != base.Duration || cfg.ClientWorkers != base.ClientWorkers || cfg.EnableChaos != base.EnableChaos {
+					t.Errorf("expected defaults after eof, got duration=%v workers=%d chaos=%v", cfg.Duration, cfg.ClientWorkers, cfg.EnableChaos)
+				}
+			},
+		},
+		{
+			name:  "invalid answers are asked again",
+			input: "\n\nabc\n0\n4\nsoon\n-1s\n2m\n1.5\n\nmaybe\nn\nn\n2\n-0.1\n0.1\nfast\n\n",
+			check: func(t *testing.T, cfg scenario.Config) {
+				if cfg.NodeCount != 4 || cfg.Duration != 2*time.Minute || cfg.ClientWorkers != base.ClientWorkers {
+					t.Errorf("expected nodes=4 duration=2m workers=%d, got %d/%v/%d", base.ClientWorkers, cfg.NodeCount, cfg.Duration, cfg.ClientWorkers)
+				}
+				if cfg.EnableChaos || cfg.EnableRecovery {
+					t.Errorf("expected chaos and recovery disabled, got %v/%v", cfg.EnableChaos, cfg.EnableRecovery)
+				}
+				if cfg.Assertions.MaxErrorRate != 0.1 || cfg.Assertions.MaxP99Latency != 0 {
+					t.Errorf("unexpected assertions: %+v", cfg.Assertions)
+				}
+			},
+		},
+		{
+			name:  "eof after invalid answer",
+			input: "\n\nmany",
+			check: func(t *testing.T, cfg scenario.Config) {
+				if cfg.NodeCount != base.NodeCount {
+					t.Errorf("expected default node count %d, got %d", base.NodeCount, cfg.NodeCount)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := newWizard(strings.NewReader(tt.input), io.Discard).run(base)
+			tt.check(t, loadWizardFile(t, fc))
+		})
+	}
+}
+
+func TestWizardPreset(t *testing.T) {
+	base, ok := scenario.GetPreset("stress")
+	if !ok {
+		t.Fatal("stress preset not found")
+	}
+
+	cfg := loadWizardFile(t, newWizard(strings.NewReader(""), io.Discard).run(base))
+	if cfg.Name != base.Name || cfg.NodeCount != base.NodeCount || cfg.ClientWorkers != base.ClientWorkers || cfg.WriteRatio != base.WriteRatio {
+		t.Errorf("expected stress preset values, got name=%q nodes=%d workers=%d write=%v", cfg.Name, cfg.NodeCount, cfg.ClientWorkers, cfg.WriteRatio)
+	}
+	if !reflect.DeepEqual(cfg.AttackTypes, base.AttackTypes) {
+		t.Errorf("expected attacks %v, got %v", base.AttackTypes, cfg.AttackTypes)
+	}
+}
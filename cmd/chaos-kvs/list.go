@@ -0,0 +1,33 @@
+package main
+
+import "fmt"
+
+// listCommand は利用可能なプリセットを表示する
+func listCommand(args []string) error {
+	fs := newFlagSet("list", "list", "")
+	if rest := parseArgs(fs, args); len(rest) > 0 {
+		return fmt.Errorf("list は引数を取りません: %v", rest)
+	}
+
+	fmt.Println("利用可能なプリセットシナリオ:")
+	fmt.Println()
+
+	presets := []struct {
+		name string
+		desc string
+	}{
+		{"basic", "カオスなしの基本負荷テスト"},
+		{"resilience", "ノードkillと復旧のテスト"},
+		{"latency", "レイテンシ注入テスト"},
+		{"stress", "高負荷ストレステスト"},
+		{"quick", "短時間の動作確認（デフォルト）"},
+	}
+
+	for _, p := range presets {
+		fmt.Printf("  %-12s %s\n", p.name, p.desc)
+	}
+
+	fmt.Println()
+	fmt.Println("使用例: chaos-kvs run --preset quick")
+	return nil
+}
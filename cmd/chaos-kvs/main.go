@@ -2,243 +2,378 @@
 package main
 
 import (
-	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"chaos-kvs/internal/api"
-	"chaos-kvs/internal/config"
-	"chaos-kvs/internal/logger"
-	"chaos-kvs/internal/scenario"
+	"github.com/nyasuto/chaos-kvs/internal/procnode"
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
 )
 
 var (
 	version = "dev"
 )
 
-func main() {
-	// フラグ定義
-	var (
-		configFile     = flag.String("config", "", "設定ファイルパス (YAML/JSON)")
-		presetName     = flag.String("preset", "", "プリセットシナリオ名 (basic, resilience, latency, stress, quick)")
-		duration       = flag.Duration("duration", 0, "シナリオ実行時間 (例: 10s, 1m)")
-		nodes          = flag.Int("nodes", 0, "ノード数")
-		workers        = flag.Int("workers", 0, "クライアントワーカー数")
-		enableChaos    = flag.Bool("chaos", true, "カオス注入を有効化")
-		enableRecovery = flag.Bool("recovery", true, "自動復旧を有効化")
-		listPresets    = flag.Bool("list-presets", false, "利用可能なプリセットを表示")
-		showVersion    = flag.Bool("version", false, "バージョンを表示")
-		serverMode     = flag.Bool("server", false, "Web UI サーバーモードで起動")
-		serverAddr     = flag.String("addr", ":8080", "サーバーアドレス (例: :8080, 0.0.0.0:3000)")
-	)
-
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, `ChaosKVS - High-Concurrency In-Memory KVS Simulator
-
-Usage:
-  chaos-kvs [options]
+// 終了コード（CIのパイプラインで失敗の原因を区別できるよう分ける）
+const (
+	exitFailure          = 1   // 設定・実行環境などのエラー
+	exitUsage            = 2   // 不明なコマンド・フラグの誤り（flag パッケージと同じ）
+	exitAssertionsFailed = 3   // 実行結果が assertions を満たさなかった
+	exitAborted          = 4   // シナリオが設定時間の経過前に中断された
+	exitForced           = 130 // 2回目の中断シグナルで即座に終了した（128 + SIGINT）
+)
 
-Options:
-`)
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, `
-Examples:
-  # プリセットシナリオを実行
-  chaos-kvs --preset quick
+// exitCodeError は終了コードを指定するエラー
+type exitCodeError struct {
+	code int
+	err  error
+}
 
-  # 設定ファイルから実行
-  chaos-kvs --config scenario.yaml
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
 
-  # フラグでカスタマイズ
-  chaos-kvs --preset basic --duration 30s --nodes 10
+// exitCode はエラーに対応する終了コードを返す
+func exitCode(err error) int {
+	var e *exitCodeError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitFailure
+}
 
-  # プリセット一覧を表示
-  chaos-kvs --list-presets
+// command はサブコマンド
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
 
-  # Web UIサーバーモードで起動
-  chaos-kvs --server
+// commands はサブコマンドの一覧（ヘルプの表示順）
+var commands = []command{
+	{"run", "シナリオを実行してレポートを表示する", runCommand},
+	{"serve", "Web UI・APIサーバーを起動する", serveCommand},
+	{"report", "保存した実行記録からレポートを出力する", reportCommand},
+	{"replay", "保存した実行記録の設定で再実行して結果を比較する", replayCommand},
+	{"compare", "2つの結果の指標を比較して回帰を検出する", compareCommand},
+	{"diff", "2つのスナップショットを比較して失われた書き込み・不一致を検出する", diffCommand},
+	{"bench", "ノードのストアとワーカープールを単体でベンチマークする", benchCommand},
+	{"capacity", "SLO を満たす最大のスループットをカオスなし・ありで探索する", capacityCommand},
+	{"init", "対話形式でシナリオファイルを作成する", initCommand},
+	{"validate", "設定ファイルを検証する", validateCommand},
+	{"list", "利用可能なプリセットを表示する", listCommand},
+	{"version", "バージョンを表示する", versionCommand},
+}
 
-  # カスタムアドレスでサーバー起動
-  chaos-kvs --server --addr :3000
-`)
-	}
+// internalCommands はヘルプに表示しない内部用のサブコマンド
+var internalCommands = []command{
+	{"node-server", "ノードを1つ起動してHTTPで公開する（run --node-process の子プロセス）", nodeServerCommand},
+}
 
-	flag.Parse()
+func main() {
+	os.Exit(dispatch(os.Args[1:]))
+}
 
-	// バージョン表示
-	if *showVersion {
-		fmt.Printf("chaos-kvs version %s\n", version)
-		return
+// dispatch は引数のサブコマンドを実行し、終了コードを返す
+func dispatch(args []string) int {
+	// サブコマンドを省略した場合は run として扱う（chaos-kvs --preset quick など）
+	name := "run"
+	switch {
+	case len(args) == 0:
+	case isHelpFlag(args[0]):
+		name = "help"
+	case !strings.HasPrefix(args[0], "-"):
+		name, args = args[0], args[1:]
 	}
 
-	// プリセット一覧表示
-	if *listPresets {
-		printPresets()
-		return
+	if name == "help" {
+		usage()
+		return 0
 	}
 
-	// Web UIサーバーモード
-	if *serverMode {
-		if err := runServer(*serverAddr); err != nil {
-			logger.Error("", "サーバーエラー: %v", err)
-			os.Exit(1)
+	for _, c := range append(commands, internalCommands...) {
+		if c.name == name {
+			if err := c.run(args); err != nil {
+				logger.Error("", "%v", err)
+				return exitCode(err)
+			}
+			return 0
 		}
-		return
 	}
 
-	// シナリオ設定の決定
-	scenarioConfig, err := buildScenarioConfig(
-		*configFile, *presetName, *duration, *nodes, *workers, *enableChaos, *enableRecovery,
-	)
-	if err != nil {
-		logger.Error("", "設定エラー: %v", err)
-		os.Exit(1)
-	}
+	fmt.Fprintf(os.Stderr, "不明なコマンド: %s\n\n", name)
+	usage()
+	return exitUsage
+}
 
-	// シナリオ実行
-	if err := runScenario(scenarioConfig); err != nil {
-		logger.Error("", "シナリオ実行エラー: %v", err)
-		os.Exit(1)
+// isHelpFlag はトップレベルのヘルプ表示のフラグかを返す
+func isHelpFlag(arg string) bool {
+	switch arg {
+	case "-h", "-help", "--help":
+		return true
 	}
+	return false
 }
 
-// buildScenarioConfig はシナリオ設定を構築する
-func buildScenarioConfig(
-	configFile, presetName string,
-	duration time.Duration, nodes, workers int,
-	enableChaos, enableRecovery bool,
-) (scenario.Config, error) {
-	var cfg scenario.Config
+// usage はトップレベルのヘルプを表示する
+func usage() {
+	fmt.Fprintf(os.Stderr, `ChaosKVS - High-Concurrency In-Memory KVS Simulator
 
-	// 1. 設定ファイルから読み込み
-	if configFile != "" {
-		fileConfig, err := config.LoadFile(configFile)
-		if err != nil {
-			return cfg, fmt.Errorf("設定ファイル読み込みエラー: %w", err)
-		}
-		if err := fileConfig.Validate(); err != nil {
-			return cfg, fmt.Errorf("設定検証エラー: %w", err)
-		}
-		cfg, err = fileConfig.ToScenarioConfig()
-		if err != nil {
-			return cfg, fmt.Errorf("設定変換エラー: %w", err)
-		}
-	} else if presetName != "" {
-		// 2. プリセットから読み込み
-		preset, ok := scenario.GetPreset(presetName)
-		if !ok {
-			return cfg, fmt.Errorf("不明なプリセット: %s (利用可能: %v)", presetName, scenario.ListPresets())
-		}
-		cfg = preset
-	} else {
-		// 3. デフォルト（quickシナリオ）
-		cfg = scenario.QuickScenario()
-	}
+Usage:
+  chaos-kvs <command> [options]
 
-	// フラグでオーバーライド
-	if duration > 0 {
-		cfg.Duration = duration
-	}
-	if nodes > 0 {
-		cfg.NodeCount = nodes
+Commands:
+`)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
 	}
-	if workers > 0 {
-		cfg.ClientWorkers = workers
+	fmt.Fprintf(os.Stderr, `
+サブコマンドを省略した場合は run として扱います。
+各コマンドのオプションは chaos-kvs <command> --help で表示します。
+
+Examples:
+  chaos-kvs run --preset quick
+  chaos-kvs serve --addr :3000
+  chaos-kvs report runs/20250101-120000-abcdef.json --output markdown
+  chaos-kvs replay ./runs
+  chaos-kvs compare before.json after.json
+  chaos-kvs diff before.ndjson after.ndjson
+  chaos-kvs bench --duration 5s
+  chaos-kvs capacity --preset resilience --max-p99 20ms
+  chaos-kvs init scenario.yaml
+  chaos-kvs validate scenario.yaml --strict
+  chaos-kvs list
+`)
+}
+
+// newFlagSet はサブコマンドのフラグを作成する
+// usageText はオプション一覧の後に表示する説明（例など）
+func newFlagSet(name, synopsis, usageText string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n  chaos-kvs %s\n\nOptions:\n", synopsis)
+		fs.PrintDefaults()
+		if usageText != "" {
+			fmt.Fprint(os.Stderr, usageText)
+		}
 	}
+	return fs
+}
 
-	// フラグが明示的に指定された場合のみオーバーライド
-	cfg.EnableChaos = enableChaos
-	cfg.EnableRecovery = enableRecovery
+// parseArgs はフラグと位置引数を順不同で解析し、位置引数を返す
+// （chaos-kvs validate scenario.yaml --strict のように位置引数の後にフラグを書ける）
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		_ = fs.Parse(args) // ExitOnError のためエラーは返らない
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
 
-	return cfg, nil
+// configOptions は設定ファイルの読み込みのフラグ（run・validate で共通）
+type configOptions struct {
+	checksum string
+	cacheDir string
+	strict   bool
+	profile  string
 }
 
-// runScenario はシナリオを実行する
-func runScenario(cfg scenario.Config) error {
-	fmt.Println("ChaosKVS - High-Concurrency In-Memory KVS Simulator")
-	fmt.Println("====================================================")
-	fmt.Printf("Scenario: %s\n", cfg.Name)
-	fmt.Printf("Duration: %v\n", cfg.Duration)
-	fmt.Printf("Nodes: %d, Workers: %d\n", cfg.NodeCount, cfg.ClientWorkers)
-	fmt.Printf("Chaos: %v, Recovery: %v\n", cfg.EnableChaos, cfg.EnableRecovery)
-	fmt.Println("====================================================")
-	fmt.Println()
+// addConfigFlags は設定ファイルの読み込みのフラグを登録する
+func addConfigFlags(fs *flag.FlagSet) *configOptions {
+	o := &configOptions{}
+	fs.StringVar(&o.checksum, "config-checksum", "", "リモート設定の期待するSHA-256チェックサム")
+	fs.StringVar(&o.cacheDir, "config-cache-dir", "", "リモート設定のETagキャッシュディレクトリ")
+	fs.BoolVar(&o.strict, "strict", false, "設定ファイルの未知のフィールドをエラーにする")
+	fs.StringVar(&o.profile, "profile", "", "設定ファイル内のプロファイル名 (例: dev, ci, stress)")
+	return o
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// loadOptions は設定ファイルの読み込みオプションを返す
+func (o *configOptions) loadOptions() config.LoadOptions {
+	opts := config.DefaultLoadOptions()
+	opts.Strict = o.strict
+	opts.Checksum = o.checksum
+	opts.CacheDir = o.cacheDir
+	return opts
+}
 
-	// シグナルハンドリング
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+// logOptions はログ出力のフラグ（run・serve・replay で共通）
+type logOptions struct {
+	fs         *flag.FlagSet
+	level      string
+	quiet      bool
+	format     string
+	file       string
+	maxSizeMB  int
+	rotate     time.Duration
+	maxBackups int
+	maxAge     time.Duration
+	sample     int
+}
 
-	go func() {
-		<-sigCh
-		fmt.Println("\n中断シグナルを受信、シナリオを終了中...")
-		cancel()
-	}()
+// addLogFlags はログ出力のフラグを登録する
+func addLogFlags(fs *flag.FlagSet) *logOptions {
+	o := &logOptions{fs: fs}
+	level := os.Getenv("CHAOS_KVS_LOG_LEVEL")
+	if level == "" {
+		level = "info"
+	}
+	fs.StringVar(&o.level, "log-level", level, "出力するログの最低レベル (debug, info, warn, error)")
+	fs.BoolVar(&o.quiet, "quiet", false, "エラー以外のログと開始時の表示を出力せず、結果のみを表示する")
+	fs.BoolVar(&o.quiet, "q", false, "--quiet の短縮形")
+	fs.StringVar(&o.format, "log-format", os.Getenv("CHAOS_KVS_LOG_FORMAT"), "ログの出力形式 (text, kv, json)")
+	fs.StringVar(&o.file, "log-file", "", "ログの出力先ファイル（空で標準出力）")
+	fs.IntVar(&o.maxSizeMB, "log-max-size", 100, "--log-file をローテーションするサイズ (MB)")
+	fs.DurationVar(&o.rotate, "log-rotate", 0, "--log-file をローテーションする間隔 (例: 24h、0で無効)")
+	fs.IntVar(&o.maxBackups, "log-max-backups", 10, "残すローテーション済みログファイルの数")
+	fs.DurationVar(&o.maxAge, "log-max-age", 0, "ローテーション済みログファイルの保持期間 (例: 168h、0で無制限)")
+	fs.IntVar(&o.sample, "log-sample", 0, "同じメッセージを1秒ごとに最初の1件、以降はN件に1件だけ出力する（0で無効）")
+	return o
+}
 
-	// シナリオ実行
-	engine := scenario.New(cfg)
-	result, err := engine.Run(ctx)
+// setup はデフォルトのロガーにレベル・出力形式・間引きを設定する
+// --quiet の場合は --log-level に関わらずエラーのみを出力する
+func (o *logOptions) setup() error {
+	level, err := logger.ParseLevel(o.level)
 	if err != nil {
 		return err
 	}
-
-	// レポート出力
-	fmt.Println(result.Report())
-
+	if o.quiet {
+		level = logger.LevelError
+	}
+	format, err := logger.ParseFormat(o.format)
+	if err != nil {
+		return err
+	}
+	logger.Default.SetLevel(level)
+	logger.Default.SetFormat(format)
+	if o.sample > 0 {
+		sampling := logger.DefaultSamplingConfig()
+		sampling.Thereafter = o.sample
+		logger.Default.SetSampling(&sampling)
+	}
 	return nil
 }
 
-// printPresets は利用可能なプリセットを表示する
-func printPresets() {
-	fmt.Println("利用可能なプリセットシナリオ:")
-	fmt.Println()
-
-	presets := []struct {
-		name string
-		desc string
-	}{
-		{"basic", "カオスなしの基本負荷テスト"},
-		{"resilience", "ノードkillと復旧のテスト"},
-		{"latency", "レイテンシ注入テスト"},
-		{"stress", "高負荷ストレステスト"},
-		{"quick", "短時間の動作確認（デフォルト）"},
-	}
-
-	for _, p := range presets {
-		fmt.Printf("  %-12s %s\n", p.name, p.desc)
+// statusWriter は開始時の表示などの出力先を返す
+// 機械可読な形式では標準出力を結果のみにするため標準エラー出力、--quiet の場合は出力しない
+func (o *logOptions) statusWriter(format scenario.ReportFormat) io.Writer {
+	switch {
+	case o.quiet:
+		return io.Discard
+	case format != scenario.ReportText:
+		return os.Stderr
+	default:
+		return os.Stdout
 	}
+}
 
-	fmt.Println()
-	fmt.Println("使用例: chaos-kvs --preset quick")
+// explicit はコマンドラインで明示的に指定されたログファイルのフラグのみを設定として返す
+func (o *logOptions) explicit() config.LogConfig {
+	var c config.LogConfig
+	o.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "log-file":
+			c.File = o.file
+		case "log-max-size":
+			c.MaxSizeMB = o.maxSizeMB
+		case "log-rotate":
+			c.RotateInterval = o.rotate.String()
+		case "log-max-backups":
+			c.MaxBackups = o.maxBackups
+		case "log-max-age":
+			c.MaxAge = o.maxAge.String()
+		}
+	})
+	return c
 }
 
-// runServer はWeb UIサーバーを起動する
-func runServer(addr string) error {
-	fmt.Println("ChaosKVS - Web UI Server")
-	fmt.Println("========================")
-	fmt.Printf("Starting server on http://%s\n", addr)
-	fmt.Println("Press Ctrl+C to stop")
-	fmt.Println()
+// openLogFile はログファイルが指定されていれば、デフォルトのロガーの出力先をそのファイルに切り替える
+// 優先順位: デフォルト < 設定ファイル < フラグ。返す関数で元の出力先に戻してファイルを閉じる
+func openLogFile(fileLog, flagLog config.LogConfig) (func(), error) {
+	cfg, err := fileLog.ApplyTo(logger.DefaultFileConfig())
+	if err != nil {
+		return nil, err
+	}
+	if cfg, err = flagLog.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Path == "" {
+		return func() {}, nil
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	f, err := logger.OpenFile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	prev := logger.Default.Output()
+	logger.Default.SetOutput(f)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			logger.Default.SetOutput(prev)
+			_ = f.Close()
+		})
+	}, nil
+}
 
-	// シグナルハンドリング
+// handleInterrupt は1回目の中断シグナル（SIGINT/SIGTERM）で onFirst を呼んで終了処理を始め、
+// 2回目のシグナルで終了処理を待たずに即座に終了する。返す関数でシグナルの処理をやめる
+func handleInterrupt(onFirst func()) (stop func()) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan struct{})
 
 	go func() {
-		<-sigCh
-		fmt.Println("\n中断シグナルを受信、サーバーを終了中...")
-		cancel()
+		select {
+		case <-sigCh:
+		case <-done:
+			return
+		}
+		onFirst()
+
+		select {
+		case <-sigCh:
+			fmt.Fprintln(os.Stderr, "\n2回目の中断シグナルを受信、即座に終了します")
+			os.Exit(exitForced)
+		case <-done:
+		}
 	}()
 
-	server := api.NewServer(addr)
-	return server.Start(ctx)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+	}
+}
+
+// versionCommand はバージョンを表示する
+func versionCommand(args []string) error {
+	fs := newFlagSet("version", "version", "")
+	if rest := parseArgs(fs, args); len(rest) > 0 {
+		return fmt.Errorf("version は引数を取りません: %v", rest)
+	}
+	fmt.Printf("chaos-kvs version %s\n", version)
+	return nil
+}
+
+// nodeServerCommand はノードの子プロセスとしてノードを1つ起動し、停止シグナルを受けるまでHTTPで公開する
+func nodeServerCommand(args []string) error {
+	return procnode.RunServer(args)
 }
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// envTestMain が設定されている場合、テストバイナリはテストの代わりに main を実行する（終了コードの検証用）
+const envTestMain = "CHAOS_KVS_TEST_MAIN"
+
+// envTestInterrupt が設定されている場合は handleInterrupt のみを実行し、シグナルを待ち続ける
+const envTestInterrupt = "CHAOS_KVS_TEST_INTERRUPT"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envTestMain) == "1" {
+		main()
+		os.Exit(0)
+	}
+	if os.Getenv(envTestInterrupt) == "1" {
+		handleInterrupt(func() { fmt.Println("first signal") })
+		fmt.Println("ready")
+		select {}
+	}
+	os.Exit(m.Run())
+}
+
+// mainCommand は args を引数として main を実行する子プロセスのコマンドを返す
+func mainCommand(t *testing.T, args ...string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), envTestMain+"=1")
+	cmd.Dir = t.TempDir()
+	return cmd
+}
+
+// runMain は main を子プロセスで実行し、終了コードと標準出力・標準エラー出力を返す
+func runMain(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	cmd := mainCommand(t, args...)
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("failed to run main: %v", err)
+	}
+	return cmd.ProcessState.ExitCode(), string(out)
+}
+
+// fakeCommands は呼び出されたサブコマンドと引数を記録するコマンドに commands を差し替える
+func fakeCommands(t *testing.T, errs map[string]error) *[]string {
+	t.Helper()
+	var called []string
+	saved := commands
+	commands = nil
+	for _, c := range saved {
+		name := c.name
+		commands = append(commands, command{name: name, summary: c.summary, run: func(args []string) error {
+			called = append(called, strings.TrimSpace(name+" "+strings.Join(args, " ")))
+			return errs[name]
+		}})
+	}
+	t.Cleanup(func() { commands = saved })
+	return &called
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		called []string
+		code   int
+	}{
+		{"no arguments runs run", nil, []string{"run"}, 0},
+		{"flags run run", []string{"--preset", "quick"}, []string{"run --preset quick"}, 0},
+		{"subcommand", []string{"report", "a.json", "--output", "json"}, []string{"report a.json --output json"}, 0},
+		{"help", []string{"help"}, nil, 0},
+		{"help flag", []string{"--help"}, nil, 0},
+		{"unknown subcommand", []string{"frobnicate"}, nil, exitUsage},
+		{"failing command", []string{"validate", "bad.yaml"}, []string{"validate bad.yaml"}, exitFailure},
+		{"exit code error", []string{"compare", "a", "b"}, []string{"compare a b"}, exitAborted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := fakeCommands(t, map[string]error{
+				"validate": errors.New("invalid"),
+				"compare":  &exitCodeError{exitAborted, errors.New("aborted")},
+			})
+			if code := dispatch(tt.args); code != tt.code {
+				t.Errorf("expected exit code %d, got %d", tt.code, code)
+			}
+			if !reflect.DeepEqual(*called, tt.called) {
+				t.Errorf("expected calls %v, got %v", tt.called, *called)
+			}
+		})
+	}
+}
+
+func TestMainExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		want string
+	}{
+		{"version", []string{"version"}, 0, "chaos-kvs version"},
+		{"help", []string{"--help"}, 0, "Commands:"},
+		{"unknown subcommand", []string{"frobnicate"}, exitUsage, "不明なコマンド: frobnicate"},
+		{"unknown flag", []string{"run", "--no-such-flag"}, exitUsage, "no-such-flag"},
+		{"unknown flag without subcommand", []string{"--no-such-flag"}, exitUsage, "no-such-flag"},
+		{"command error", []string{"validate", "missing.yaml"}, exitFailure, "missing.yaml"},
+		{"run without subcommand", []string{"--preset", "no-such-preset"}, exitFailure, "no-such-preset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := runMain(t, tt.args...)
+			if code != tt.code {
+				t.Errorf("expected exit code %d, got %d\n%s", tt.code, code, out)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("expected output to contain %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestResultError(t *testing.T) {
+	failure := scenario.AssertionFailure{Metric: "error_rate", Threshold: 0.01, Value: 0.5}
+	tests := []struct {
+		name   string
+		result scenario.Result
+		code   int
+	}{
+		{"passed", scenario.Result{}, 0},
+		{"assertions failed", scenario.Result{AssertionFailures: []scenario.AssertionFailure{failure}}, exitAssertionsFailed},
+		{"step failed", scenario.Result{Steps: []scenario.StepResult{{Error: "node-1 not found"}}}, exitAssertionsFailed},
+		{"interrupted", scenario.Result{Interrupted: true}, exitAborted},
+		{"interrupted with failures", scenario.Result{Interrupted: true, AssertionFailures: []scenario.AssertionFailure{failure}}, exitAborted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := resultError(&tt.result)
+			if tt.code == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if code := exitCode(err); code != tt.code {
+				t.Errorf("expected exit code %d, got %d (%v)", tt.code, code, err)
+			}
+		})
+	}
+}
+
+// writeScenarioFile は assertions を含む短いシナリオファイルを作成し、そのパスを返す
+func writeScenarioFile(t *testing.T, assertions string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	data := "scenario:\n  duration: 300ms\n  node_count: 2\n  chaos:\n    enabled: false\n  assertions:\n" + assertions
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write scenario file: %v", err)
+	}
+	return path
+}
+
+func TestMainRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertions string
+		code       int
+	}{
+		{"assertions passed", "    max_error_rate: 1\n", 0},
+		{"assertions failed", "    min_throughput: 1000000000000\n", exitAssertionsFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := runMain(t, "run", "--config", writeScenarioFile(t, tt.assertions), "--quiet")
+			if code != tt.code {
+				t.Errorf("expected exit code %d, got %d\n%s", tt.code, code, out)
+			}
+		})
+	}
+}
+
+// startMain は cmd を開始し、標準出力・標準エラー出力の行を送るチャネルを返す
+func startMain(t *testing.T, cmd *exec.Cmd) <-chan string {
+	t.Helper()
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to open stdout: %v", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start main: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	return lines
+}
+
+// waitLine は want を含む行が出力されるまで待ち、それまでの出力を返す
+func waitLine(t *testing.T, lines <-chan string, want string) string {
+	t.Helper()
+	var out strings.Builder
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("output ended before %q:\n%s", want, out.String())
+			}
+			out.WriteString(line + "\n")
+			if strings.Contains(line, want) {
+				return out.String()
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %q:\n%s", want, out.String())
+		}
+	}
+}
+
+// exitStatus はプロセスの終了を待ち、終了コードを返す
+func exitStatus(t *testing.T, cmd *exec.Cmd, lines <-chan string) int {
+	t.Helper()
+	for range lines {
+	}
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("failed to wait for main: %v", err)
+		}
+	}
+	return cmd.ProcessState.ExitCode()
+}
+
+func TestMainInterruptGraceful(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signals are not supported on windows")
+	}
+
+	cmd := mainCommand(t, "run", "--preset", "quick", "--duration", "1m", "--nodes", "2", "--chaos=false")
+	lines := startMain(t, cmd)
+	waitLine(t, lines, "started ===")
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("failed to send interrupt: %v", err)
+	}
+	// 1回目のシグナルでは途中までの結果のレポートを出力し、中断の終了コードで終わる
+	waitLine(t, lines, "Total Requests")
+	if code := exitStatus(t, cmd, lines); code != exitAborted {
+		t.Errorf("expected exit code %d, got %d", exitAborted, code)
+	}
+}
+
+func TestMainInterruptForced(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signals are not supported on windows")
+	}
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), envTestInterrupt+"=1")
+	lines := startMain(t, cmd)
+	waitLine(t, lines, "ready")
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("failed to send interrupt: %v", err)
+	}
+	waitLine(t, lines, "first signal")
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("failed to send interrupt: %v", err)
+	}
+	if code := exitStatus(t, cmd, lines); code != exitForced {
+		t.Errorf("expected exit code %d, got %d", exitForced, code)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	rpprof "runtime/pprof"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+// profileOptions はプロファイリングのフラグ（run・serve・bench で共通）
+type profileOptions struct {
+	addr       string
+	cpuFile    string
+	heapFile   string
+	contention bool
+}
+
+// addProfileFlags はプロファイリングのフラグを登録する
+func addProfileFlags(fs *flag.FlagSet) *profileOptions {
+	o := &profileOptions{}
+	fs.StringVar(&o.addr, "pprof", "", "net/http/pprof を公開するアドレス (例: :6060、空で無効)")
+	fs.StringVar(&o.cpuFile, "cpu-profile", "", "実行中のCPUプロファイルを保存するファイル")
+	fs.StringVar(&o.heapFile, "heap-profile", "", "終了時のヒーププロファイルを保存するファイル")
+	fs.BoolVar(&o.contention, "pprof-contention", false, "ミューテックス・ブロッキングのプロファイルを有効化（オーバーヘッドあり）")
+	return o
+}
+
+// start はプロファイリングを開始し、停止してプロファイルを保存する関数を返す
+// 保存に失敗した場合は警告をログに出力する
+func (o *profileOptions) start() (func(), error) {
+	if o.contention {
+		runtime.SetMutexProfileFraction(1)
+		runtime.SetBlockProfileRate(1)
+	}
+
+	var server *http.Server
+	if o.addr != "" {
+		ln, err := net.Listen("tcp", o.addr)
+		if err != nil {
+			return nil, fmt.Errorf("pprof のアドレスで待ち受けできません: %w", err)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		server = &http.Server{Handler: mux}
+		go func() {
+			if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Warn("", "pprof server error: %v", err)
+			}
+		}()
+		logger.Info("", "pprof listening on http://%s/debug/pprof/", ln.Addr())
+	}
+
+	var cpu *os.File
+	if o.cpuFile != "" {
+		f, err := os.Create(o.cpuFile)
+		if err != nil {
+			closeServer(server)
+			return nil, fmt.Errorf("CPUプロファイルを作成できません: %w", err)
+		}
+		if err := rpprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			closeServer(server)
+			return nil, fmt.Errorf("CPUプロファイルを開始できません: %w", err)
+		}
+		cpu = f
+	}
+
+	return func() {
+		closeServer(server)
+		if cpu != nil {
+			rpprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				logger.Warn("", "CPUプロファイルを保存できません: %v", err)
+			} else {
+				logger.Info("", "Saved CPU profile to %s", o.cpuFile)
+			}
+		}
+		if o.heapFile != "" {
+			if err := writeHeapProfile(o.heapFile); err != nil {
+				logger.Warn("", "%v", err)
+			} else {
+				logger.Info("", "Saved heap profile to %s", o.heapFile)
+			}
+		}
+	}, nil
+}
+
+// closeServer は pprof のサーバーを停止する（起動していない場合は何もしない）
+func closeServer(server *http.Server) {
+	if server != nil {
+		_ = server.Close()
+	}
+}
+
+// writeHeapProfile はGC後のヒーププロファイルをファイルに保存する
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ヒーププロファイルを作成できません: %w", err)
+	}
+	runtime.GC()
+	if err := rpprof.WriteHeapProfile(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("ヒーププロファイルを保存できません: %w", err)
+	}
+	return f.Close()
+}
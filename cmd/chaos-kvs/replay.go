@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const replayUsage = `
+<run> には serve --history-dir に保存された実行記録のファイル、履歴ディレクトリ、
+または serve --history-db のデータベースを指定します。
+ディレクトリ・データベースの場合は最新の実行（--id 指定時はその実行）を再実行します。
+
+保存された設定で同じシナリオを実行し、元の結果と指標を比較します。
+元の実行でカオスモンキーが注入した攻撃は、同じノードに同じ種類の攻撃を同じ経過時間で注入して再生します
+（--random-chaos で元の設定どおり乱数で攻撃します）。
+リクエストのキーは実行ごとに乱数で決まるため、完全に同じ負荷は再現されません。
+元の設定の通知先（Slack・Discord）には投稿しません。
+
+Examples:
+  # 履歴ディレクトリの最新の実行を再実行して比較
+  chaos-kvs replay ./runs
+
+  # 特定の実行を再実行し、比較結果をJSONで出力
+  chaos-kvs replay runs/20250101-120000-abcdef.json --output json
+
+  # 攻撃は再生せず、元の設定で乱数で攻撃する
+  chaos-kvs replay ./runs --random-chaos
+`
+
+// replayCommand は保存した実行記録の設定でシナリオを再実行し、元の結果と比較する
+func replayCommand(args []string) error {
+	fs := newFlagSet("replay", "replay [options] <run>", replayUsage)
+	var (
+		runID       = fs.String("id", "", "履歴ディレクトリ・データベースから再実行する実行ID（空で最新）")
+		output      = fs.String("output", "text", "比較結果の出力形式 (text, json)")
+		randomChaos = fs.Bool("random-chaos", false, "記録された攻撃を再生せず、乱数で攻撃する")
+	)
+	thresholds := addThresholdFlags(fs)
+	logOpts := addLogFlags(fs)
+	rest := parseArgs(fs, args)
+	if len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("replay には実行記録のファイルまたは履歴ディレクトリを1つ指定してください")
+	}
+
+	if err := logOpts.setup(); err != nil {
+		return err
+	}
+	format, err := comparisonFormat(*output)
+	if err != nil {
+		return err
+	}
+	// JSON では標準出力を比較結果のみにする
+	if format == scenario.ReportJSON {
+		logger.Default.SetOutput(os.Stderr)
+	}
+
+	closeLog, err := openLogFile(config.LogConfig{}, logOpts.explicit())
+	if err != nil {
+		return fmt.Errorf("ログファイルエラー: %w", err)
+	}
+	defer closeLog()
+
+	run, err := loadRun(rest[0], *runID)
+	if err != nil {
+		return err
+	}
+	if run.Result != nil && run.Result.Interrupted {
+		logger.Warn("", "実行 %s は中断されたため、比較結果は参考値です", run.ID)
+	}
+
+	cfg := run.Config
+	cfg.Notifiers = nil
+	if !*randomChaos && cfg.EnableChaos && len(run.Result.Attacks) > 0 {
+		cfg.ChaosReplay = run.Result.Attacks
+	}
+
+	status := logOpts.statusWriter(format)
+	fmt.Fprintf(status, "Replaying run %s\n", run.ID)
+	if len(cfg.ChaosReplay) > 0 {
+		fmt.Fprintf(status, "Replaying %d recorded attacks\n", len(cfg.ChaosReplay))
+	}
+	fmt.Fprintln(status)
+	result, err := executeScenario(cfg, "", status)
+	if err != nil {
+		return fmt.Errorf("シナリオ実行エラー: %w", err)
+	}
+
+	comparison := scenario.Compare(run.Result, result, *thresholds)
+	comparison.Base = run.ID
+	comparison.Current = "replay"
+	if format == scenario.ReportText {
+		if err := result.WriteReport(os.Stdout, scenario.ReportText); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return writeComparison(comparison, format)
+}
+
+// loadRun は実行記録のファイル、または履歴ディレクトリ・データベースの実行記録を読み込む
+// ディレクトリ・データベースの場合は id の実行（空の場合は最新の実行）を返す
+func loadRun(path, id string) (*history.Run, error) {
+	if run, ok, err := storedRun(path, id); ok {
+		if err != nil {
+			return nil, err
+		}
+		return checkRun(run, path)
+	}
+
+	data, err := readInput(path)
+	if err != nil {
+		return nil, fmt.Errorf("実行記録を読み込めません: %w", err)
+	}
+	var run history.Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("実行記録を解析できません: %w", err)
+	}
+	if id != "" && run.ID != id {
+		return nil, fmt.Errorf("%s は実行 %s の記録ではありません", path, id)
+	}
+	return checkRun(&run, path)
+}
+
+// storedRun は path が履歴ディレクトリ・データベースの場合に id の実行（空の場合は最新の実行）を返す
+// どちらでもない場合は ok が false になる
+func storedRun(path, id string) (run *history.Run, ok bool, err error) {
+	var config history.Config
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		config.Dir = path
+	} else if history.IsDatabase(path) {
+		config.Database = path
+	} else {
+		return nil, false, nil
+	}
+
+	store, err := history.NewStore(config)
+	if err != nil {
+		return nil, true, err
+	}
+	defer func() { _ = store.Close() }()
+
+	if id == "" {
+		runs := store.List()
+		if len(runs) == 0 {
+			return nil, true, fmt.Errorf("%s に実行記録がありません", path)
+		}
+		id = runs[0].ID
+	}
+	run, found := store.Get(id)
+	if !found {
+		return nil, true, fmt.Errorf("実行 %s が %s に見つかりません", id, path)
+	}
+	return run, true, nil
+}
+
+// checkRun は再実行に必要な設定と結果が実行記録に含まれているかを確認する
+func checkRun(run *history.Run, path string) (*history.Run, error) {
+	if run.Result == nil || (run.Config.Duration <= 0 && len(run.Config.Steps) == 0) || run.Config.NodeCount <= 0 {
+		return nil, fmt.Errorf("%s は再実行できる実行記録ではありません", path)
+	}
+	return run, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const reportUsage = `
+<file> には serve --history-dir に保存された実行記録、または JSON 形式のレポートを指定します（- で標準入力）。
+serve --history-db のデータベースの場合は <db>#<id> で実行を指定します（id の省略で最新の実行）。
+
+Examples:
+  # 保存した実行記録のレポートを表示
+  chaos-kvs report runs/20250101-120000-abcdef.json
+
+  # Markdown に変換して保存
+  chaos-kvs report runs/20250101-120000-abcdef.json --output markdown > report.md
+
+  # 集計値のみをJSONで出力
+  chaos-kvs report runs/20250101-120000-abcdef.json --output json --summary
+
+  # 履歴データベースの実行のレポートを表示
+  chaos-kvs report history.db#20250101-120000-abcdef
+`
+
+// reportCommand は保存した実行記録からレポートを出力する
+func reportCommand(args []string) error {
+	fs := newFlagSet("report", "report [options] <file>", reportUsage)
+	output := fs.String("output", "text", "レポートの出力形式 (text, json, markdown, html)")
+	summaryOnly := fs.Bool("summary", false, "レポートを集計値のみにする（ノードごとの状態と直近の警告を除く）")
+	rest := parseArgs(fs, args)
+	if len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("report には実行記録のファイルを1つ指定してください")
+	}
+
+	format, err := scenario.ParseReportFormat(*output)
+	if err != nil {
+		return err
+	}
+	result, err := readResult(rest[0])
+	if err != nil {
+		return err
+	}
+	opts := reportOptions{format: format, summary: *summaryOnly}
+	return opts.write(os.Stdout, result)
+}
+
+// readInput はファイル（- の場合は標準入力）の内容を読み込む
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// reportOptions はレポートの出力形式（run・report で共通）
+type reportOptions struct {
+	format  scenario.ReportFormat
+	summary bool // 集計値のみを出力する
+}
+
+// write は結果をレポートとして書き出す
+func (o reportOptions) write(w io.Writer, result *scenario.Result) error {
+	if o.summary {
+		result = result.Summary()
+	}
+	return result.WriteReport(w, o.format)
+}
+
+// readResult は実行記録（history.Run）または JSON 形式のレポート（scenario.Result）を読み込む
+// 履歴データベースの場合は <db>#<id> で実行を指定する（id の省略で最新の実行）
+func readResult(path string) (*scenario.Result, error) {
+	db, id, _ := strings.Cut(path, "#")
+	if history.IsDatabase(db) {
+		run, _, err := storedRun(db, id)
+		if err != nil {
+			return nil, err
+		}
+		if run.Result == nil {
+			return nil, fmt.Errorf("実行 %s は完了していません", run.ID)
+		}
+		return run.Result, nil
+	}
+
+	data, err := readInput(path)
+	if err != nil {
+		return nil, fmt.Errorf("実行記録を読み込めません: %w", err)
+	}
+
+	var run history.Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("実行記録を解析できません: %w", err)
+	}
+	if run.Result != nil {
+		return run.Result, nil
+	}
+
+	var result scenario.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("実行記録を解析できません: %w", err)
+	}
+	if result.ScenarioName == "" && result.StartTime.IsZero() {
+		return nil, fmt.Errorf("%s は実行記録またはレポートではありません", path)
+	}
+	return &result, nil
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const runUsage = `
+Precedence:
+  デフォルト < プリセット < 設定ファイル < 環境変数 < フラグ
+
+Exit status:
+  0    シナリオが完了し、assertions を全て満たした
+  1    設定・実行環境などのエラー
+  2    フラグの誤り
+  3    実行結果が設定ファイルの assertions を満たさなかった
+  4    シナリオが中断された（SIGINT/SIGTERM。途中までの結果のレポートを出力する）
+  130  2回目の中断シグナルで集計を待たずに終了した
+
+Environment:
+  CHAOS_KVS_DURATION   シナリオ実行時間 (例: 30s)
+  CHAOS_KVS_NODES      ノード数
+  CHAOS_KVS_WORKERS    クライアントワーカー数
+  CHAOS_KVS_CHAOS      カオス注入を有効化 (true/false)
+  CHAOS_KVS_RECOVERY   自動復旧を有効化 (true/false)
+  CHAOS_KVS_LOG_LEVEL  --log-level のデフォルト値
+  CHAOS_KVS_LOG_FORMAT --log-format のデフォルト値
+
+Examples:
+  # プリセットシナリオを実行
+  chaos-kvs run --preset quick
+
+  # 設定ファイルから実行
+  chaos-kvs run --config scenario.yaml
+
+  # 設定ファイルのプロファイルを選択して実行
+  chaos-kvs run --config scenario.yaml --profile ci
+
+  # 未知のフィールド（typo）をエラーとして検出
+  chaos-kvs run --config scenario.yaml --strict
+
+  # リモートの設定ファイルから実行（チェックサム検証付き）
+  chaos-kvs run --config https://example.com/scenario.yaml --config-checksum <sha256>
+
+  # フラグでカスタマイズ
+  chaos-kvs run --preset basic --duration 30s --nodes 10
+
+  # 実行せずに、プロファイル・環境変数・フラグを適用した実行計画（段階・攻撃の時刻・ノード配置）を確認
+  chaos-kvs run --config scenario.yaml --profile stress --dry-run
+
+  # INFOログを抑えてレポートのみを表示
+  chaos-kvs run --preset quick --quiet
+
+  # 詳細なDEBUGログを出力
+  chaos-kvs run --preset quick --log-level debug
+
+  # CIでJSONのレポートを集計値のみ出力（ログは標準エラー出力に出力される）
+  chaos-kvs run --preset quick --output json --summary > result.json
+
+  # 多数のワーカーでの競合を調べる（実行中は go tool pprof http://localhost:6060/debug/pprof/mutex）
+  chaos-kvs run --preset stress --workers 500 --pprof :6060 --pprof-contention --cpu-profile cpu.out
+
+  # CIで実行結果の条件（設定ファイルの assertions）を満たさなければジョブを失敗させる
+  chaos-kvs run --config scenario.yaml --quiet || exit $?
+
+  # 障害注入中のクラスタに redis-benchmark で負荷をかける（redis-benchmark -p 6379 -t get,set）
+  chaos-kvs run --preset resilience --duration 1m --resp-addr :6379
+
+  # ノードへのリクエストをループバックのTCP経由にし、障害を接続のリセット・実際の遅延として観測する
+  chaos-kvs run --preset resilience --node-http
+
+  # ノードを子プロセスとして起動し、kill 攻撃で実際にプロセスを終了させる（復旧は再起動でデータを失う）
+  chaos-kvs run --preset resilience --node-process
+
+  # 攻撃を Toxiproxy で接続のリセット・遅延・帯域制限としてソケット層に注入する（toxiproxy-server を起動しておく）
+  chaos-kvs run --preset resilience --node-http --toxiproxy http://127.0.0.1:8474
+
+  # 障害注入中に読み書きした値の整合性（線形化可能性）を検査し、違反したキーをレポートに含める
+  chaos-kvs run --preset resilience --check-linearizability
+
+  # 実行をトレースとして Jaeger・Tempo に送る（OTLP/HTTP の 4318 番ポートで受け付けておく）
+  chaos-kvs run --preset resilience --trace-endpoint http://127.0.0.1:4318
+
+  # 以前の実行で注入された攻撃を同じノード・同じ時刻に再生し、見つけた障害の順序を回帰テストにする
+  chaos-kvs run --preset resilience --output json > found.json
+  chaos-kvs run --preset resilience --replay-attacks found.json
+
+  # シナリオのイベントをJSONLで保存（chaos-kvs serve --replay で再生できる）
+  chaos-kvs run --preset resilience --event-log events.jsonl
+`
+
+// runCommand はシナリオを実行する
+func runCommand(args []string) error {
+	fs := newFlagSet("run", "run [options]", runUsage)
+	var (
+		configFile     = fs.String("config", "", "設定ファイルパスまたはURL (YAML/JSON)")
+		presetName     = fs.String("preset", "", "プリセットシナリオ名 (basic, resilience, latency, stress, quick)")
+		duration       = fs.Duration("duration", 0, "シナリオ実行時間 (例: 10s, 1m)")
+		nodes          = fs.Int("nodes", 0, "ノード数")
+		workers        = fs.Int("workers", 0, "クライアントワーカー数")
+		enableChaos    = fs.Bool("chaos", true, "カオス注入を有効化（指定時のみ設定を上書き）")
+		enableRecovery = fs.Bool("recovery", true, "自動復旧を有効化（指定時のみ設定を上書き）")
+		eventLog       = fs.String("event-log", "", "シナリオ実行中のイベントをJSONLで保存するファイル")
+		respAddr       = fs.String("resp-addr", "", "クラスタをRESP（Redisプロトコル）で公開するアドレス (例: :6379)")
+		respNodePort   = fs.Int("resp-node-port", 0, "ノードごとにRESPで公開する開始ポート（node-N は指定値+N-1）")
+		nodeHTTP       = fs.Bool("node-http", false, "ノードごとにループバックのHTTPサーバーを立て、負荷生成をTCP経由にする")
+		nodeHTTPPort   = fs.Int("node-http-port", 0, "--node-http のノードごとの開始ポート（node-N は指定値+N-1、0で空いているポート）")
+		nodeProcess    = fs.Bool("node-process", false, "ノードごとに子プロセスを起動し、kill 攻撃で実際にプロセスを強制終了する")
+		toxiproxyURL   = fs.String("toxiproxy", "", "ノードへの接続を経由させる Toxiproxy のAPIのURL（--node-http・--node-process か設定ファイルの external と合わせて使う）")
+		linearizable   = fs.Bool("check-linearizability", false, "操作の履歴を記録し、終了時に線形化可能性を検査する")
+		traceEndpoint  = fs.String("trace-endpoint", "", "シナリオの段階・攻撃・復旧・サンプリングしたリクエストのトレースを送るOTLP/HTTPの受信先 (例: http://127.0.0.1:4318)")
+		output         = fs.String("output", "text", "レポートの出力形式 (text, json, markdown, html)")
+		summaryOnly    = fs.Bool("summary", false, "レポートを集計値のみにする（ノードごとの状態と直近の警告を除く）")
+		dryRun         = fs.Bool("dry-run", false, "シナリオを実行せず、適用後の設定から求めた実行計画を表示する")
+		replayAttacks  = fs.String("replay-attacks", "", "実行記録・JSONのレポート（<db>#<id> で履歴データベースの実行）で注入された攻撃を、乱数の代わりに同じ時刻に再生する")
+	)
+	configOpts := addConfigFlags(fs)
+	logOpts := addLogFlags(fs)
+	profileOpts := addProfileFlags(fs)
+	if rest := parseArgs(fs, args); len(rest) > 0 {
+		return fmt.Errorf("run は位置引数を取りません: %v（設定ファイルは --config で指定してください）", rest)
+	}
+
+	if err := logOpts.setup(); err != nil {
+		return err
+	}
+	format, err := scenario.ParseReportFormat(*output)
+	if err != nil {
+		return err
+	}
+	// 機械可読な形式では標準出力をレポートのみにする
+	if format != scenario.ReportText {
+		logger.Default.SetOutput(os.Stderr)
+	}
+
+	// シナリオ設定の決定
+	flagOverrides := explicitFlagOverrides(fs, duration, nodes, workers, enableChaos, enableRecovery,
+		respAddr, respNodePort, nodeHTTP, nodeHTTPPort, nodeProcess, toxiproxyURL, linearizable, traceEndpoint)
+	scenarioConfig, fileLog, err := buildScenarioConfig(
+		*configFile, configOpts.loadOptions(), configOpts.profile, *presetName, flagOverrides,
+	)
+	if err != nil {
+		return fmt.Errorf("設定エラー: %w", err)
+	}
+	if *replayAttacks != "" {
+		recorded, err := readResult(*replayAttacks)
+		if err != nil {
+			return err
+		}
+		if len(recorded.Attacks) == 0 {
+			return fmt.Errorf("%s に攻撃の記録がありません（--summary のレポートには含まれません）", *replayAttacks)
+		}
+		scenarioConfig.ChaosReplay = recorded.Attacks
+	}
+	if *dryRun {
+		fmt.Print(scenario.NewPlan(scenarioConfig).Report())
+		return nil
+	}
+
+	closeLog, err := openLogFile(fileLog, logOpts.explicit())
+	if err != nil {
+		return fmt.Errorf("ログファイルエラー: %w", err)
+	}
+	defer closeLog()
+
+	stopProfile, err := profileOpts.start()
+	if err != nil {
+		return err
+	}
+	defer stopProfile()
+
+	// シナリオ実行
+	opts := reportOptions{format: format, summary: *summaryOnly}
+	result, err := executeScenario(scenarioConfig, *eventLog, logOpts.statusWriter(format))
+	if err != nil {
+		return fmt.Errorf("シナリオ実行エラー: %w", err)
+	}
+	if err := opts.write(os.Stdout, result); err != nil {
+		return err
+	}
+	return resultError(result)
+}
+
+// resultError は中断された、または assertions・ステップを満たさなかった実行結果を対応する終了コードのエラーとして返す
+// 中断された場合は途中までの結果のため、assertions の判定より優先する
+func resultError(r *scenario.Result) error {
+	switch {
+	case r.Interrupted:
+		return &exitCodeError{exitAborted, errors.New("シナリオが中断されました")}
+	case !r.Passed():
+		if s := r.FailedStep(); s != nil && len(r.AssertionFailures) == 0 {
+			return &exitCodeError{exitAssertionsFailed, fmt.Errorf("ステップ %d が失敗しました: %s", s.Index+1, s.Error)}
+		}
+		return &exitCodeError{exitAssertionsFailed, fmt.Errorf("%d 件の assertions を満たしませんでした", len(r.AssertionFailures))}
+	}
+	return nil
+}
+
+// buildScenarioConfig はシナリオ設定を構築する
+// 優先順位: デフォルト < プリセット < 設定ファイル < 環境変数 < フラグ
+func buildScenarioConfig(
+	configFile string, loadOpts config.LoadOptions,
+	profileName, presetName string,
+	flagOverrides config.Overrides,
+) (scenario.Config, config.LogConfig, error) {
+	var cfg scenario.Config
+	var logConfig config.LogConfig
+
+	if profileName != "" && configFile == "" {
+		return cfg, logConfig, fmt.Errorf("--profile は --config と併用してください")
+	}
+
+	// 1. デフォルト / プリセット
+	switch {
+	case presetName != "":
+		preset, ok := scenario.GetPreset(presetName)
+		if !ok {
+			return cfg, logConfig, fmt.Errorf("不明なプリセット: %s (利用可能: %v)", presetName, scenario.ListPresets())
+		}
+		cfg = preset
+	case configFile != "":
+		cfg = scenario.DefaultConfig()
+	default:
+		// デフォルト（quickシナリオ）
+		cfg = scenario.QuickScenario()
+	}
+
+	// 2. 設定ファイル
+	if configFile != "" {
+		fileConfig, err := loadConfigFile(configFile, loadOpts, profileName)
+		if err != nil {
+			return cfg, logConfig, err
+		}
+		logConfig = fileConfig.Log
+		cfg, err = fileConfig.ApplyTo(cfg)
+		if err != nil {
+			return cfg, logConfig, fmt.Errorf("設定変換エラー: %w", err)
+		}
+	}
+
+	// 3. 環境変数
+	envOverrides, err := config.OverridesFromEnv(os.LookupEnv)
+	if err != nil {
+		return cfg, logConfig, fmt.Errorf("環境変数エラー: %w", err)
+	}
+	envOverrides.Apply(&cfg)
+
+	// 4. 明示的に指定されたフラグ
+	flagOverrides.Apply(&cfg)
+
+	return cfg, logConfig, nil
+}
+
+// loadConfigFile は設定ファイルを読み込み、プロファイルを適用して検証する
+func loadConfigFile(path string, loadOpts config.LoadOptions, profileName string) (*config.FileConfig, error) {
+	fileConfig, err := config.Load(path, loadOpts)
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイル読み込みエラー: %w", err)
+	}
+	if profileName != "" {
+		if err := fileConfig.ApplyProfile(profileName); err != nil {
+			return nil, fmt.Errorf("プロファイル適用エラー: %w", err)
+		}
+	}
+	if err := fileConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("設定検証エラー: %w", err)
+	}
+	return fileConfig, nil
+}
+
+// explicitFlagOverrides はコマンドラインで明示的に指定されたフラグのみを上書き値として返す
+func explicitFlagOverrides(
+	fs *flag.FlagSet,
+	duration *time.Duration, nodes, workers *int,
+	enableChaos, enableRecovery *bool,
+	respAddr *string, respNodePort *int,
+	nodeHTTP *bool, nodeHTTPPort *int, nodeProcess *bool,
+	toxiproxyURL *string, linearizable *bool, traceEndpoint *string,
+) config.Overrides {
+	var o config.Overrides
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "duration":
+			o.Duration = duration
+		case "nodes":
+			o.NodeCount = nodes
+		case "workers":
+			o.ClientWorkers = workers
+		case "chaos":
+			o.EnableChaos = enableChaos
+		case "recovery":
+			o.EnableRecovery = enableRecovery
+		case "resp-addr":
+			o.RESPAddr = respAddr
+		case "resp-node-port":
+			o.RESPNodePort = respNodePort
+		case "node-http":
+			o.NodeHTTP = nodeHTTP
+		case "node-http-port":
+			o.NodeHTTPPort = nodeHTTPPort
+		case "node-process":
+			o.NodeProcess = nodeProcess
+		case "toxiproxy":
+			o.ToxiproxyURL = toxiproxyURL
+		case "check-linearizability":
+			o.Linearizability = linearizable
+		case "trace-endpoint":
+			o.TraceEndpoint = traceEndpoint
+		}
+	})
+	return o
+}
+
+// executeScenario はシナリオを実行して結果を返す。開始時の表示などは status に書き出す
+// eventLog を指定した場合は実行中のイベントをJSONLで保存する
+func executeScenario(cfg scenario.Config, eventLog string, status io.Writer) (*scenario.Result, error) {
+	fmt.Fprintln(status, "ChaosKVS - High-Concurrency In-Memory KVS Simulator")
+	fmt.Fprintln(status, "====================================================")
+	fmt.Fprintf(status, "Scenario: %s\n", cfg.Name)
+	fmt.Fprintf(status, "Duration: %v\n", cfg.Duration)
+	fmt.Fprintf(status, "Nodes: %d, Workers: %d\n", cfg.NodeCount, cfg.ClientWorkers)
+	fmt.Fprintf(status, "Chaos: %v, Recovery: %v\n", cfg.EnableChaos, cfg.EnableRecovery)
+	if len(cfg.Steps) > 0 {
+		fmt.Fprintf(status, "Steps: %d\n", len(cfg.Steps))
+	}
+	fmt.Fprintln(status, "====================================================")
+	fmt.Fprintln(status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 1回目の中断シグナルでは途中までの結果を集計してレポートを出力し、2回目で即座に終了する
+	start := time.Now()
+	stopSignals := handleInterrupt(func() {
+		fmt.Fprintf(status, "\n中断シグナルを受信（経過 %v / %v）、シナリオを終了して途中までの結果を集計中...\n",
+			time.Since(start).Round(time.Second), cfg.Duration)
+		fmt.Fprintln(status, "もう一度 Ctrl+C を押すと集計を待たずに終了します")
+		cancel()
+	})
+	defer stopSignals()
+
+	// シナリオ実行
+	engine := scenario.New(cfg)
+	var recorder *history.Recorder
+	if eventLog != "" {
+		bus := events.NewBus()
+		engine.SetEventBus(bus)
+		recorder = history.NewRecorder(bus)
+	}
+	result, err := engine.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// イベントログ保存
+	if recorder != nil {
+		if err := saveEventLog(eventLog, recorder.Stop()); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// saveEventLog はイベントをJSONLでファイルに保存する
+func saveEventLog(path string, timeline []events.Event) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("イベントログを作成できません: %w", err)
+	}
+	if err := events.WriteLog(f, timeline); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	logger.Info("", "Saved %d events to %s", len(timeline), path)
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nyasuto/chaos-kvs/internal/api"
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+const serveUsage = `
+Environment:
+  CHAOS_KVS_READ_TOKEN      --read-token のデフォルト値
+  CHAOS_KVS_OPERATOR_TOKEN  --operator-token のデフォルト値
+  CHAOS_KVS_LOG_LEVEL       --log-level のデフォルト値
+  CHAOS_KVS_LOG_FORMAT      --log-format のデフォルト値
+
+Examples:
+  # Web UIサーバーを起動
+  chaos-kvs serve
+
+  # カスタムアドレスでサーバー起動
+  chaos-kvs serve --addr :3000
+
+  # HTTPに加えてgRPCでも操作・メトリクス配信を提供
+  chaos-kvs serve --grpc-addr :9090
+
+  # リバースプロキシの /chaos 配下で公開し、別ホストのダッシュボードを許可
+  chaos-kvs serve --base-path /chaos --cors-origins https://dash.example.com
+
+  # シナリオ開始・障害注入をクライアントごとに毎秒1回（連続5回）までに制限
+  chaos-kvs serve --rate-limit 1 --rate-burst 5
+
+  # POST /api/runs で並行実行できるシナリオを8件までに拡大
+  chaos-kvs serve --max-concurrent-runs 8
+
+  # カオス/復旧イベントを外部システムに10件ずつPOST
+  chaos-kvs serve --webhook-url https://hooks.example.com/chaos --webhook-batch 10
+
+  # chaos-kvs run --event-log で保存したイベントをWeb UIで再生（4倍速）
+  chaos-kvs serve --replay events.jsonl --replay-speed 4
+
+  # 実行履歴をディスクに保存してサーバー起動
+  chaos-kvs serve --history-dir ./runs
+
+  # 実行履歴・秒ごとのメトリクス・イベントを SQLite に保存（compare・replay に指定できる）
+  chaos-kvs serve --history-db history.db
+
+  # 長時間の運用でJSONログをファイルに出力（1日ごとにローテーション、7世代保持）
+  chaos-kvs serve --log-format json --log-file logs/chaos-kvs.log --log-rotate 24h --log-max-backups 7
+
+  # ゴルーチンリークの調査用に pprof を公開（go tool pprof http://localhost:6060/debug/pprof/goroutine）
+  chaos-kvs serve --pprof localhost:6060
+
+  # 外部公開時に操作系APIをトークンで保護
+  CHAOS_KVS_OPERATOR_TOKEN=secret chaos-kvs serve --addr 0.0.0.0:8080
+`
+
+// serveCommand はWeb UI・APIサーバーを起動する
+func serveCommand(args []string) error {
+	fs := newFlagSet("serve", "serve [options]", serveUsage)
+	var (
+		serverAddr    = fs.String("addr", ":8080", "サーバーアドレス (例: :8080, 0.0.0.0:3000)")
+		grpcAddr      = fs.String("grpc-addr", "", "gRPCサーバーアドレス (例: :9090、空で無効)")
+		basePath      = fs.String("base-path", "", "UIとAPIを配置するパスの接頭辞 (例: /chaos)")
+		corsOrigins   = fs.String("cors-origins", "", "クロスオリジンアクセスを許可するオリジン（カンマ区切り、* で全て）")
+		logRequests   = fs.Bool("log-requests", true, "リクエストごとのアクセスログを出力")
+		rateLimit     = fs.Float64("rate-limit", 0, "シナリオ開始・障害注入APIの1秒あたりの許可数（クライアントごと、0で無制限）")
+		rateBurst     = fs.Int("rate-burst", 5, "--rate-limit 指定時に連続して許可する最大数")
+		maxRuns       = fs.Int("max-concurrent-runs", 4, "同時に実行できるシナリオの最大数（0で無制限）")
+		historyDir    = fs.String("history-dir", "", "実行履歴を保存するディレクトリ")
+		historyDB     = fs.String("history-db", "", "実行履歴・秒ごとのメトリクス・イベントを保存する SQLite データベース（--history-dir とは併用不可）")
+		webhookURL    = fs.String("webhook-url", "", "カオス/復旧イベントをPOSTするURL")
+		webhookBatch  = fs.Int("webhook-batch", 1, "--webhook-url の1リクエストにまとめるイベント数")
+		replayFile    = fs.String("replay", "", "JSONLのイベントログを再生してUIに配信する")
+		replaySpeed   = fs.Float64("replay-speed", 1, "--replay の再生速度（2で2倍速、0で待機なし）")
+		readToken     = fs.String("read-token", os.Getenv("CHAOS_KVS_READ_TOKEN"), "参照系APIのBearerトークン（空で公開）")
+		operatorToken = fs.String("operator-token", os.Getenv("CHAOS_KVS_OPERATOR_TOKEN"), "シナリオ操作・障害注入APIのBearerトークン（空で公開）")
+	)
+	logOpts := addLogFlags(fs)
+	profileOpts := addProfileFlags(fs)
+	if rest := parseArgs(fs, args); len(rest) > 0 {
+		return fmt.Errorf("serve は位置引数を取りません: %v", rest)
+	}
+
+	if err := logOpts.setup(); err != nil {
+		return err
+	}
+	closeLog, err := openLogFile(config.LogConfig{}, logOpts.explicit())
+	if err != nil {
+		return fmt.Errorf("ログファイルエラー: %w", err)
+	}
+	defer closeLog()
+
+	stopProfile, err := profileOpts.start()
+	if err != nil {
+		return err
+	}
+	defer stopProfile()
+
+	serverConfig := api.DefaultConfig()
+	serverConfig.Addr = *serverAddr
+	serverConfig.GRPCAddr = *grpcAddr
+	serverConfig.BasePath = *basePath
+	serverConfig.LogRequests = *logRequests
+	serverConfig.RateLimit = *rateLimit
+	serverConfig.RateBurst = *rateBurst
+	serverConfig.MaxConcurrentRuns = *maxRuns
+	if *corsOrigins != "" {
+		serverConfig.CORSOrigins = strings.Split(*corsOrigins, ",")
+	}
+	serverConfig.HistoryDir = *historyDir
+	serverConfig.HistoryDB = *historyDB
+	serverConfig.WebhookURL = *webhookURL
+	serverConfig.WebhookBatchSize = *webhookBatch
+	serverConfig.ReadToken = *readToken
+	serverConfig.OperatorToken = *operatorToken
+	if *replayFile != "" {
+		replay, err := loadEventLog(*replayFile)
+		if err != nil {
+			return fmt.Errorf("イベントログエラー: %w", err)
+		}
+		serverConfig.Replay = replay
+		serverConfig.ReplaySpeed = *replaySpeed
+	}
+
+	if err := runServer(serverConfig); err != nil {
+		return fmt.Errorf("サーバーエラー: %w", err)
+	}
+	return nil
+}
+
+// loadEventLog はJSONLのイベントログを読み込む
+func loadEventLog(path string) ([]events.Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("イベントログを開けません: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return events.ReadLog(f)
+}
+
+// runServer はWeb UIサーバーを起動する
+func runServer(cfg api.Config) error {
+	server, err := api.NewServerWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("ChaosKVS - Web UI Server")
+	fmt.Println("========================")
+	fmt.Printf("Starting server on http://%s\n", cfg.Addr)
+	fmt.Println("Press Ctrl+C to stop")
+	fmt.Println()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 1回目の中断シグナルで実行中のシナリオを止めて終了し、2回目で即座に終了する
+	stopSignals := handleInterrupt(func() {
+		fmt.Println("\n中断シグナルを受信、サーバーを終了中...（もう一度 Ctrl+C で即座に終了）")
+		cancel()
+	})
+	defer stopSignals()
+
+	return server.Start(ctx)
+}
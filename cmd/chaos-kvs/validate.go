@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+const validateUsage = `
+Examples:
+  # 設定ファイルを検証
+  chaos-kvs validate scenario.yaml
+
+  # 未知のフィールド（typo）もエラーにし、プロファイルを適用した結果を検証
+  chaos-kvs validate scenario.yaml --strict --profile ci
+`
+
+// validateCommand は設定ファイルを検証し、適用後のシナリオ設定を表示する
+func validateCommand(args []string) error {
+	fs := newFlagSet("validate", "validate [options] <config>", validateUsage)
+	configOpts := addConfigFlags(fs)
+	rest := parseArgs(fs, args)
+	if len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("validate には設定ファイルを1つ指定してください")
+	}
+
+	path := rest[0]
+	fileConfig, err := loadConfigFile(path, configOpts.loadOptions(), configOpts.profile)
+	if err != nil {
+		return err
+	}
+	cfg, err := fileConfig.ApplyTo(scenario.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("設定変換エラー: %w", err)
+	}
+
+	fmt.Printf("%s: OK\n", path)
+	fmt.Printf("  Scenario: %s\n", cfg.Name)
+	fmt.Printf("  Duration: %v\n", cfg.Duration)
+	fmt.Printf("  Nodes: %d, Workers: %d\n", cfg.NodeCount, cfg.ClientWorkers)
+	fmt.Printf("  Chaos: %v, Recovery: %v\n", cfg.EnableChaos, cfg.EnableRecovery)
+	if len(cfg.Steps) > 0 {
+		fmt.Printf("  Steps: %d\n", len(cfg.Steps))
+	}
+	if names := fileConfig.ProfileNames(); len(names) > 0 {
+		fmt.Printf("  Profiles: %v\n", names)
+	}
+	return nil
+}
@@ -1,8 +1,17 @@
-module chaos-kvs
+module github.com/nyasuto/chaos-kvs
 
 go 1.25
 
 require (
-	golang.org/x/net v0.49.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/mattn/go-sqlite3 v1.14.52
+	golang.org/x/net v0.49.0
+	google.golang.org/grpc v1.79.0
+	google.golang.org/protobuf v1.36.10
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
 )
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Role はAPIトークンの権限
+type Role int
+
+const (
+	// RoleNone は未認証
+	RoleNone Role = iota
+	// RoleReader は参照系エンドポイントのみ利用可能
+	RoleReader
+	// RoleOperator はシナリオ操作・障害注入を含む全エンドポイントを利用可能
+	RoleOperator
+)
+
+// String はロールの文字列表現を返す
+func (r Role) String() string {
+	switch r {
+	case RoleReader:
+		return "reader"
+	case RoleOperator:
+		return "operator"
+	default:
+		return "none"
+	}
+}
+
+// tokenQueryParam はヘッダーを設定できないクライアント（WebSocket/EventSource）用のクエリパラメータ
+const tokenQueryParam = "token"
+
+// authenticator はBearerトークンからロールを判定する
+// トークンが設定されていないロールの操作は認証なしで許可される
+type authenticator struct {
+	readToken     string
+	operatorToken string
+}
+
+// enabled は認証が有効かどうかを返す
+func (a *authenticator) enabled() bool {
+	return a.readToken != "" || a.operatorToken != ""
+}
+
+// roleOf はリクエストのトークンに対応するロールを返す
+func (a *authenticator) roleOf(r *http.Request) Role {
+	return a.roleOfToken(requestToken(r))
+}
+
+// roleOfToken はトークンに対応するロールを返す
+func (a *authenticator) roleOfToken(token string) Role {
+	if token == "" {
+		return RoleNone
+	}
+	if tokenEqual(token, a.operatorToken) {
+		return RoleOperator
+	}
+	if tokenEqual(token, a.readToken) {
+		return RoleReader
+	}
+	return RoleNone
+}
+
+// allowed はリクエストが必要なロールを満たすかどうかを返す
+func (a *authenticator) allowed(r *http.Request, required Role) bool {
+	return a.allowedToken(requestToken(r), required)
+}
+
+// allowedToken はトークンが必要なロールを満たすかどうかを返す
+func (a *authenticator) allowedToken(token string, required Role) bool {
+	switch required {
+	case RoleOperator:
+		if a.operatorToken == "" {
+			return true
+		}
+	case RoleReader:
+		// 参照用トークン未設定時は参照系を公開し、操作系のみを保護する
+		if a.readToken == "" {
+			return true
+		}
+	default:
+		return true
+	}
+	return a.roleOfToken(token) >= required
+}
+
+// require は必要なロールを満たさないリクエストを拒否するハンドラーを返す
+func (a *authenticator) require(required Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.allowed(r, required) {
+			next(w, r)
+			return
+		}
+		if a.roleOf(r) == RoleNone {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="chaos-kvs"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Forbidden: "+required.String()+" role required", http.StatusForbidden)
+	}
+}
+
+// requestToken はAuthorizationヘッダーまたはクエリパラメータからトークンを取り出す
+func requestToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		return bearerToken(h)
+	}
+	return r.URL.Query().Get(tokenQueryParam)
+}
+
+// bearerToken は "Bearer <token>" 形式の値からトークンを取り出す
+func bearerToken(h string) string {
+	scheme, token, ok := strings.Cut(h, " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// tokenEqual はタイミング攻撃を避けてトークンを比較する
+func tokenEqual(got, want string) bool {
+	if want == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// AuthResponse は認証状態レスポンス
+type AuthResponse struct {
+	Enabled bool   `json:"enabled"`
+	Role    string `json:"role"`
+}
+
+// handleAuth は認証の有効/無効とリクエストのロールを返す（UIのトークン入力判定用）
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	resp := AuthResponse{Enabled: s.auth.enabled()}
+	switch {
+	case s.auth.allowed(r, RoleOperator):
+		resp.Role = RoleOperator.String()
+	case s.auth.allowed(r, RoleReader):
+		resp.Role = RoleReader.String()
+	default:
+		resp.Role = RoleNone.String()
+	}
+	s.writeJSON(w, resp)
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+const (
+	// nodePrefix は追加するノードのID接頭辞（シナリオが作成するノードと同じ）
+	nodePrefix = "node"
+	// maxClusterNodes は変更後のノード数の上限
+	maxClusterNodes = 100
+	// maxSnapshotBytes はアップロードできるスナップショットの最大サイズ
+	maxSnapshotBytes = 256 << 20
+)
+
+// ScaleRequest はノード数の変更リクエスト
+type ScaleRequest struct {
+	Nodes int `json:"nodes"`
+}
+
+// ScaleResponse はノード数の変更結果
+type ScaleResponse struct {
+	NodeCount int      `json:"node_count"`
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+}
+
+// runningCluster は実行中のシナリオのクラスタを返す
+func runningCluster(rn *run) (*cluster.Cluster, error) {
+	if rn == nil || !rn.isRunning() || rn.cluster() == nil {
+		return nil, newOpError(errConflict, "No scenario running")
+	}
+	return rn.cluster(), nil
+}
+
+// assignZones は追加したノードをノード数が最も少ないゾーンに配置する
+func assignZones(rn *run, c *cluster.Cluster, nodeIDs []string) {
+	if len(rn.config.Zones) == 0 {
+		return
+	}
+	for _, id := range nodeIDs {
+		if n, ok := c.GetNode(id); ok {
+			n.SetLabel(node.LabelZone, c.LeastUsedZone(rn.config.Zones))
+		}
+	}
+}
+
+// scaleCluster はクラスタのノード数を count に合わせる
+func scaleCluster(rn *run, count int) (ScaleResponse, error) {
+	if count < 1 || count > maxClusterNodes {
+		return ScaleResponse{}, newOpError(errInvalid, "Invalid node count: %d (must be 1-%d)", count, maxClusterNodes)
+	}
+	c, err := runningCluster(rn)
+	if err != nil {
+		return ScaleResponse{}, err
+	}
+
+	rn.scaleMu.Lock()
+	defer rn.scaleMu.Unlock()
+
+	added, removed, err := c.ScaleTo(count, nodePrefix)
+	assignZones(rn, c, added)
+	if err != nil {
+		return ScaleResponse{}, newOpError(errConflict, "%s", err.Error())
+	}
+
+	resp := ScaleResponse{NodeCount: c.Size(), Added: added, Removed: removed}
+	if resp.Added == nil {
+		resp.Added = []string{}
+	}
+	if resp.Removed == nil {
+		resp.Removed = []string{}
+	}
+	return resp, nil
+}
+
+// addClusterNode はノードを1つ追加して起動する
+func addClusterNode(rn *run) (NodeInfo, error) {
+	c, err := runningCluster(rn)
+	if err != nil {
+		return NodeInfo{}, err
+	}
+
+	rn.scaleMu.Lock()
+	defer rn.scaleMu.Unlock()
+
+	if c.Size() >= maxClusterNodes {
+		return NodeInfo{}, newOpError(errConflict, "Cluster already has %d nodes", maxClusterNodes)
+	}
+	nodes, err := c.AddNodes(1, nodePrefix)
+	if err != nil {
+		return NodeInfo{}, newOpError(errConflict, "%s", err.Error())
+	}
+	assignZones(rn, c, []string{nodes[0].ID()})
+	return newNodeInfo(nodes[0]), nil
+}
+
+// removeClusterNode はノードを停止してクラスタから削除する
+func removeClusterNode(rn *run, nodeID string) (NodeInfo, error) {
+	c, err := runningCluster(rn)
+	if err != nil {
+		return NodeInfo{}, err
+	}
+
+	rn.scaleMu.Lock()
+	defer rn.scaleMu.Unlock()
+
+	n, ok := c.GetNode(nodeID)
+	if !ok {
+		return NodeInfo{}, newOpError(errNotFound, "Node %s not found", nodeID)
+	}
+	if c.Size() <= 1 {
+		return NodeInfo{}, newOpError(errConflict, "Cannot remove the last node")
+	}
+	if err := c.RemoveNode(nodeID); err != nil {
+		return NodeInfo{}, newOpError(errConflict, "%s", err.Error())
+	}
+	return newNodeInfo(n), nil
+}
+
+// RebalanceResponse はキーの再配置の結果
+type RebalanceResponse = cluster.RebalanceStats
+
+// rebalanceCluster は稼働中のノードのキーをレプリカに移す
+// ノードの追加・削除では自動で行うため、停止していたノードの再起動後などに使う
+func rebalanceCluster(ctx context.Context, rn *run) (RebalanceResponse, error) {
+	c, err := runningCluster(rn)
+	if err != nil {
+		return RebalanceResponse{}, err
+	}
+
+	rn.scaleMu.Lock()
+	defer rn.scaleMu.Unlock()
+
+	stats, err := c.Rebalance(ctx)
+	if err != nil {
+		return stats, newOpError(errConflict, "%s", err.Error())
+	}
+	return stats, nil
+}
+
+// RestoreResponse はスナップショットの復元結果
+type RestoreResponse struct {
+	Nodes int `json:"nodes"`
+	Keys  int `json:"keys"`
+}
+
+// restoreSnapshot はスナップショットのデータをクラスタに復元する
+func restoreSnapshot(rn *run, snaps []cluster.NodeSnapshot) (RestoreResponse, error) {
+	c, err := runningCluster(rn)
+	if err != nil {
+		return RestoreResponse{}, err
+	}
+
+	rn.scaleMu.Lock()
+	defer rn.scaleMu.Unlock()
+
+	size := c.Size()
+	for _, snap := range snaps {
+		if _, ok := c.GetNode(snap.ID); !ok {
+			size++
+		}
+	}
+	if size > maxClusterNodes {
+		return RestoreResponse{}, newOpError(errInvalid, "Snapshot would grow the cluster to %d nodes (max %d)", size, maxClusterNodes)
+	}
+
+	keys, err := c.RestoreSnapshot(snaps)
+	if err != nil {
+		return RestoreResponse{}, newOpError(errConflict, "%s", err.Error())
+	}
+	return RestoreResponse{Nodes: len(snaps), Keys: keys}, nil
+}
+
+// handleSnapshotDownload は全ノードのデータをNDJSONでストリーミングする
+// 終了した実行でも、既定の実行であれば最後のデータを取得できる
+func (s *Server) handleSnapshotDownload(w http.ResponseWriter, r *http.Request, rn *run) {
+	if rn == nil || rn.cluster() == nil {
+		s.writeError(w, r, newOpError(errConflict, "No scenario running"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-snapshot.ndjson"`, rn.id))
+	if err := rn.cluster().WriteSnapshot(w); err != nil {
+		// ヘッダー送信後のためステータスは変更できない
+		logger.Warn("", "Snapshot download %s aborted: %v", requestIDFrom(r.Context()), err)
+	}
+}
+
+// handleSnapshotUpload はアップロードされたスナップショットを実行中のクラスタに復元する
+func (s *Server) handleSnapshotUpload(w http.ResponseWriter, r *http.Request, rn *run) {
+	snaps, err := cluster.ReadSnapshot(http.MaxBytesReader(w, r.Body, maxSnapshotBytes))
+	if err != nil {
+		http.Error(w, "Invalid snapshot: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := restoreSnapshot(rn, snaps)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, resp)
+}
+
+// handleClusterScale はクラスタのノード数を変更する
+func (s *Server) handleClusterScale(w http.ResponseWriter, r *http.Request, rn *run) {
+	var req ScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := scaleCluster(rn, req.Nodes)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, resp)
+}
+
+// handleClusterRebalance はキーをレプリカに移す
+func (s *Server) handleClusterRebalance(w http.ResponseWriter, r *http.Request, rn *run) {
+	resp, err := rebalanceCluster(r.Context(), rn)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, resp)
+}
+
+// handleClusterAddNode はノードを1つ追加する
+func (s *Server) handleClusterAddNode(w http.ResponseWriter, r *http.Request, rn *run) {
+	info, err := addClusterNode(rn)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, info)
+}
+
+// handleClusterRemoveNode はノードを削除する
+func (s *Server) handleClusterRemoveNode(w http.ResponseWriter, r *http.Request, rn *run) {
+	info, err := removeClusterNode(rn, r.PathValue("node"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, info)
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClusterScale(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	scale := func(body string) (*http.Response, ScaleResponse) {
+		t.Helper()
+		resp, err := http.Post(ts.URL+"/api/cluster/scale", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		var scaled ScaleResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&scaled); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp, scaled
+	}
+
+	if resp, _ := scale(`{"nodes":3}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 without scenario, got %d", resp.StatusCode)
+	}
+
+	startScenario(t, s, ts, `{"scenario":{"duration":"30s","node_count":2,"zones":["a","b"],"chaos":{"enabled":false}}}`)
+	defer stopScenario(t, ts)
+
+	resp, scaled := scale(`{"nodes":5}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if scaled.NodeCount != 5 || len(scaled.Added) != 3 || len(scaled.Removed) != 0 {
+		t.Errorf("unexpected scale up result: %+v", scaled)
+	}
+	if n, ok := s.currentCluster().GetNode("node-5"); !ok || n.Status() != node.StatusRunning || n.Label(node.LabelZone) == "" {
+		t.Error("expected node-5 to be running with a zone")
+	}
+
+	resp, scaled = scale(`{"nodes":1}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if scaled.NodeCount != 1 || len(scaled.Removed) != 4 || scaled.Added == nil {
+		t.Errorf("unexpected scale down result: %+v", scaled)
+	}
+
+	for _, body := range []string{`{"nodes":0}`, `{"nodes":1000}`, `nodes`} {
+		if resp, _ := scale(body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func TestClusterAddRemoveNode(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	remove := func(nodeID string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/cluster/nodes/"+nodeID, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	startScenario(t, s, ts, `{"scenario":{"duration":"30s","node_count":1,"chaos":{"enabled":false}}}`)
+	defer stopScenario(t, ts)
+
+	if resp := remove("node-1"); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 when removing the last node, got %d", resp.StatusCode)
+	}
+
+	resp, err := http.Post(ts.URL+"/api/cluster/nodes", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var info NodeInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if info.ID != "node-2" || info.Status != "running" {
+		t.Errorf("unexpected added node: %+v", info)
+	}
+
+	if resp := remove("node-1"); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if resp := remove("node-1"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for removed node, got %d", resp.StatusCode)
+	}
+	if size := s.currentCluster().Size(); size != 1 {
+		t.Errorf("expected 1 node, got %d", size)
+	}
+}
+
+func TestClusterRebalance(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	rebalance := func() (*http.Response, RebalanceResponse) {
+		t.Helper()
+		resp, err := http.Post(ts.URL+"/api/cluster/rebalance", "application/json", nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		var stats RebalanceResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp, stats
+	}
+
+	if resp, _ := rebalance(); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 without scenario, got %d", resp.StatusCode)
+	}
+
+	startScenario(t, s, ts, `{"scenario":{"duration":"30s","node_count":2,"client":{"workers":1,"target_rps":1},"chaos":{"enabled":false}}}`)
+	defer stopScenario(t, ts)
+
+	// 担当ノードでないノードに書いたキーを担当ノードに移す
+	c := s.currentCluster()
+	n, _ := c.GetNode("node-1")
+	n.Import(map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3"), "d": []byte("4")})
+	resp, stats := rebalance()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	for _, key := range n.Keys() {
+		if owner, _ := c.Owner(key); owner != n {
+			t.Errorf("key %s left on node-1, owned by %s (%+v)", key, owner.ID(), stats)
+		}
+	}
+}
+
+func TestClusterSnapshot(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	if resp, err := http.Get(ts.URL + "/api/cluster/snapshot"); err != nil {
+		t.Fatalf("request failed: %v", err)
+	} else {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("expected status 409 without scenario, got %d", resp.StatusCode)
+		}
+	}
+
+	startScenario(t, s, ts, `{"scenario":{"duration":"30s","node_count":2,"chaos":{"enabled":false}}}`)
+	defer stopScenario(t, ts)
+
+	// 負荷生成のキー（key-N）と重ならないキーで検証する
+	n, _ := s.currentCluster().GetNode("node-1")
+	n.Import(map[string][]byte{"snapshot": []byte("before")})
+
+	resp, err := http.Get(ts.URL + "/api/cluster/snapshot")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected ndjson, got %s", ct)
+	}
+
+	n.Import(map[string][]byte{"snapshot": []byte("after")})
+
+	resp, err = http.Post(ts.URL+"/api/cluster/snapshot", "application/x-ndjson", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var restored RestoreResponse
+	if err := json.NewDecoder(resp.Body).Decode(&restored); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if restored.Nodes != 2 {
+		t.Errorf("expected 2 nodes restored, got %+v", restored)
+	}
+	if v, _ := n.Get("snapshot"); string(v) != "before" {
+		t.Errorf("expected restored value 'before', got %q", v)
+	}
+
+	resp, err = http.Post(ts.URL+"/api/cluster/snapshot", "application/x-ndjson", strings.NewReader(`{"data":{}}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid snapshot, got %d", resp.StatusCode)
+	}
+}
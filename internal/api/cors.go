@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsMaxAge はプリフライト結果をブラウザがキャッシュする秒数
+const corsMaxAge = 600
+
+// corsPolicy は別オリジンのダッシュボードからのアクセスを許可する設定
+type corsPolicy struct {
+	origins map[string]bool
+	any     bool // "*" が指定された場合は全オリジンを許可
+}
+
+// newCORSPolicy は許可するオリジンの一覧からポリシーを作成する（空の場合はnil）
+func newCORSPolicy(origins []string) *corsPolicy {
+	p := &corsPolicy{origins: make(map[string]bool)}
+	for _, o := range origins {
+		o = strings.TrimRight(strings.TrimSpace(o), "/")
+		switch o {
+		case "":
+		case "*":
+			p.any = true
+		default:
+			p.origins[o] = true
+		}
+	}
+	if !p.any && len(p.origins) == 0 {
+		return nil
+	}
+	return p
+}
+
+// allowed はオリジンが許可されているかどうかを返す
+func (p *corsPolicy) allowed(origin string) bool {
+	return p.any || p.origins[origin]
+}
+
+// middleware はCORSヘッダーを付与し、プリフライトリクエストに応答する
+// 認証はBearerトークンで行うため、Cookie（credentials）は許可しない
+func (p *corsPolicy) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if !p.allowed(origin) {
+			// ヘッダーを付けなければブラウザ側で拒否される
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Expose-Headers", "X-Total-Count, "+requestIDHeader)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			h.Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// normalizeBasePath はベースパスを "/prefix" の形式にそろえる（ルートの場合は空文字列）
+func normalizeBasePath(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// mountBasePath はハンドラーをベースパス配下に配置する
+// "/prefix" は相対パスでUIを解決できるよう "/prefix/" にリダイレクトする
+func mountBasePath(basePath string, h http.Handler) http.Handler {
+	if basePath == "" {
+		return h
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(basePath+"/", http.StripPrefix(basePath, h))
+	mux.HandleFunc(basePath, func(w http.ResponseWriter, r *http.Request) {
+		target := basePath + "/"
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+	return mux
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServerWithConfig は設定を指定してテスト用のAPIサーバーを作成する
+func newTestServerWithConfig(t *testing.T, config Config) (*Server, *httptest.Server) {
+	t.Helper()
+
+	s, err := NewServerWithConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	handler, err := s.Handler()
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"chaos", "/chaos"},
+		{"/chaos/", "/chaos"},
+		{" /a/b ", "/a/b"},
+	}
+	for _, tt := range tests {
+		if got := normalizeBasePath(tt.in); got != tt.want {
+			t.Errorf("normalizeBasePath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCORS(t *testing.T) {
+	config := DefaultConfig()
+	config.CORSOrigins = []string{"https://dash.example.com/", " https://other.example.com"}
+	_, ts := newTestServerWithConfig(t, config)
+
+	do := func(method, origin string, preflight bool) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+"/api/nodes", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", "POST")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	resp := do(http.MethodGet, "https://dash.example.com", false)
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Errorf("expected allowed origin, got %q", got)
+	}
+	if got := resp.Header.Get("Access-Control-Expose-Headers"); !strings.Contains(got, "X-Total-Count") {
+		t.Errorf("expected X-Total-Count to be exposed, got %q", got)
+	}
+
+	resp = do(http.MethodOptions, "https://other.example.com", true)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected preflight status 204, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") {
+		t.Errorf("expected Authorization to be allowed, got %q", got)
+	}
+
+	resp = do(http.MethodGet, "https://evil.example.com", false)
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS header for unknown origin, got %q", got)
+	}
+
+	resp = do(http.MethodGet, "", false)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected same-origin request to pass without CORS headers")
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	if newCORSPolicy(nil) != nil || newCORSPolicy([]string{""}) != nil {
+		t.Error("expected nil policy without origins")
+	}
+
+	p := newCORSPolicy([]string{"*"})
+	if !p.allowed("https://anything.example.com") {
+		t.Error("expected wildcard to allow any origin")
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	config := DefaultConfig()
+	config.BasePath = "/chaos/"
+	_, ts := newTestServerWithConfig(t, config)
+
+	resp, err := http.Get(ts.URL + "/chaos/api/status")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 under base path, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/api/status")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 outside base path, got %d", resp.StatusCode)
+	}
+
+	// UIはリダイレクト後に配信される
+	resp, err = http.Get(ts.URL + "/chaos")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.Request.URL.Path != "/chaos/" {
+		t.Errorf("expected redirect to /chaos/, got %s", resp.Request.URL.Path)
+	}
+	if !strings.Contains(string(body), "<html") {
+		t.Error("expected index.html under base path")
+	}
+
+	// メトリクスのパスラベルにはベースパスを含めない
+	resp, err = http.Get(ts.URL + "/chaos/metrics")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if !strings.Contains(string(body), `path="/api/status"`) {
+		t.Errorf("expected metrics labelled without base path, got:\n%s", body)
+	}
+}
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// Grafana の JSON データソース（SimpleJSON / simpod-json-datasource）の契約を実装する
+// 時系列はイベント履歴の metrics_snapshot と scenario_finished から、
+// アノテーションはカオス・リカバリー等のイベントから組み立てるため Prometheus を必要としない
+
+// grafanaMetric はGrafanaから参照できるメトリクス
+type grafanaMetric struct {
+	name  string
+	value func(events.EventData) float64
+}
+
+// grafanaMetrics はメトリクス名の一覧（/search の応答順）
+var grafanaMetrics = []grafanaMetric{
+	{"rps", func(d events.EventData) float64 { return d.RPS }},
+	{"avg_latency_ms", func(d events.EventData) float64 { return d.AvgLatencyMs }},
+	{"p99_latency_ms", func(d events.EventData) float64 { return d.P99LatencyMs }},
+	{"error_rate", func(d events.EventData) float64 { return d.ErrorRate }},
+	{"requests", func(d events.EventData) float64 { return float64(d.Requests) }},
+	{"errors", func(d events.EventData) float64 { return float64(d.Errors) }},
+	{"node_count", func(d events.EventData) float64 { return float64(d.NodeCount) }},
+	{"nodes_running", func(d events.EventData) float64 { return float64(d.NodesRunning) }},
+	{"attacks", func(d events.EventData) float64 { return float64(d.Attacks) }},
+}
+
+// lookupGrafanaMetric は名前からメトリクスを取得する
+func lookupGrafanaMetric(name string) (grafanaMetric, bool) {
+	for _, m := range grafanaMetrics {
+		if m.name == name {
+			return m, true
+		}
+	}
+	return grafanaMetric{}, false
+}
+
+// GrafanaRange はGrafanaのダッシュボードの表示範囲
+type GrafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// GrafanaTarget はパネルのクエリ1件
+// Payload の run で実行IDを指定すると、その実行の系列だけを返す
+type GrafanaTarget struct {
+	Target  string         `json:"target"`
+	RefID   string         `json:"refId"`
+	Type    string         `json:"type,omitempty"`
+	Hide    bool           `json:"hide,omitempty"`
+	Payload GrafanaPayload `json:"payload,omitempty"`
+}
+
+// GrafanaPayload はクエリの追加パラメータ
+type GrafanaPayload struct {
+	Run string `json:"run,omitempty"`
+}
+
+// GrafanaQueryRequest は POST /api/grafana/query のリクエスト
+type GrafanaQueryRequest struct {
+	Range         GrafanaRange    `json:"range"`
+	IntervalMs    int64           `json:"intervalMs,omitempty"`
+	MaxDataPoints int             `json:"maxDataPoints,omitempty"`
+	Targets       []GrafanaTarget `json:"targets"`
+}
+
+// GrafanaSeries は時系列1本（datapoints は [値, UNIXミリ秒] の組）
+type GrafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// GrafanaMetricOption は POST /api/grafana/metrics の応答の要素
+type GrafanaMetricOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// GrafanaAnnotationQuery はアノテーションの設定
+// Query にはイベント種別をカンマ区切りで指定する（空でメトリクス以外の全イベント）
+type GrafanaAnnotationQuery struct {
+	Name   string `json:"name"`
+	Query  string `json:"query,omitempty"`
+	Enable bool   `json:"enable,omitempty"`
+}
+
+// GrafanaAnnotationRequest は POST /api/grafana/annotations のリクエスト
+type GrafanaAnnotationRequest struct {
+	Range      GrafanaRange           `json:"range"`
+	Annotation GrafanaAnnotationQuery `json:"annotation"`
+}
+
+// GrafanaAnnotation はアノテーション1件（時刻はUNIXミリ秒）
+// 攻撃は再開またはリカバリー成功までを TimeEnd の範囲で表す
+type GrafanaAnnotation struct {
+	Annotation GrafanaAnnotationQuery `json:"annotation"`
+	Time       int64                  `json:"time"`
+	TimeEnd    int64                  `json:"timeEnd,omitempty"`
+	Title      string                 `json:"title"`
+	Text       string                 `json:"text"`
+	Tags       []string               `json:"tags"`
+}
+
+// handleGrafanaHealth はデータソースの接続確認に応答する
+// Grafana は設定されたURLの末尾に / を付けて GET する
+func (s *Server) handleGrafanaHealth(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/grafana/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleGrafanaSearch はクエリエディタに表示するメトリクス名を返す
+func (s *Server) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, len(grafanaMetrics))
+	for i, m := range grafanaMetrics {
+		names[i] = m.name
+	}
+	s.writeJSON(w, names)
+}
+
+// handleGrafanaMetrics は新しいJSONデータソースプラグイン向けにメトリクス名を返す
+func (s *Server) handleGrafanaMetrics(w http.ResponseWriter, r *http.Request) {
+	options := make([]GrafanaMetricOption, len(grafanaMetrics))
+	for i, m := range grafanaMetrics {
+		options[i] = GrafanaMetricOption{Label: m.name, Value: m.name}
+	}
+	s.writeJSON(w, options)
+}
+
+// handleGrafanaQuery は表示範囲内のメトリクスの時系列を返す
+func (s *Server) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	var req GrafanaQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	snapshots := s.grafanaEvents(req.Range, events.EventMetricsSnapshot, events.EventScenarioFinished)
+
+	result := []GrafanaSeries{}
+	for _, target := range req.Targets {
+		if target.Hide || target.Target == "" {
+			continue
+		}
+		metric, ok := lookupGrafanaMetric(target.Target)
+		if !ok {
+			http.Error(w, "Unknown metric: "+target.Target, http.StatusBadRequest)
+			return
+		}
+		result = append(result, grafanaSeries(metric, snapshots, target.Payload.Run, req.MaxDataPoints)...)
+	}
+
+	s.writeJSON(w, result)
+}
+
+// grafanaSeries はメトリクスの時系列を実行ごとに組み立てる
+// 実行が複数ある場合は系列名に実行IDを付けて区別する
+func grafanaSeries(metric grafanaMetric, snapshots []events.Event, runID string, maxPoints int) []GrafanaSeries {
+	byRun := map[string][][2]float64{}
+	var runs []string
+	for _, e := range snapshots {
+		if runID != "" && e.RunID != runID {
+			continue
+		}
+		if _, ok := byRun[e.RunID]; !ok {
+			runs = append(runs, e.RunID)
+		}
+		point := [2]float64{metric.value(e.Data), float64(e.Timestamp.UnixMilli())}
+		byRun[e.RunID] = append(byRun[e.RunID], point)
+	}
+	sort.Strings(runs)
+
+	series := make([]GrafanaSeries, 0, len(runs))
+	for _, run := range runs {
+		name := metric.name
+		if len(runs) > 1 && run != "" {
+			name += " " + run
+		}
+		series = append(series, GrafanaSeries{
+			Target:     name,
+			Datapoints: downsample(byRun[run], maxPoints),
+		})
+	}
+	return series
+}
+
+// downsample は点の数が maxPoints 以下になるよう等間隔に間引く（最後の点は残す）
+func downsample(points [][2]float64, maxPoints int) [][2]float64 {
+	if maxPoints <= 0 || len(points) <= maxPoints {
+		return points
+	}
+	stride := (len(points) + maxPoints - 1) / maxPoints
+	result := make([][2]float64, 0, maxPoints)
+	for i := len(points) - 1; i >= 0; i -= stride {
+		result = append(result, points[i])
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// handleGrafanaAnnotations は表示範囲内のイベントをアノテーションとして返す
+func (s *Server) handleGrafanaAnnotations(w http.ResponseWriter, r *http.Request) {
+	var req GrafanaAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var types []events.EventType
+	for _, t := range strings.Split(req.Annotation.Query, ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		if !events.EventType(t).Valid() {
+			http.Error(w, "Unknown event type: "+t, http.StatusBadRequest)
+			return
+		}
+		types = append(types, events.EventType(t))
+	}
+	if len(types) == 0 {
+		for _, t := range events.Types {
+			if t != events.EventMetricsSnapshot {
+				types = append(types, t)
+			}
+		}
+	}
+
+	// 攻撃の終了時刻を求めるため、再開とリカバリー成功は指定がなくても取得する
+	list := s.grafanaEvents(req.Range, append(types, events.EventChaosResume, events.EventRecoverySuccess)...)
+	s.writeJSON(w, grafanaAnnotations(req.Annotation, list, types))
+}
+
+// grafanaAnnotations はイベントをアノテーションに変換する
+func grafanaAnnotations(query GrafanaAnnotationQuery, list []events.Event, types []events.EventType) []GrafanaAnnotation {
+	wanted := events.ByType(types...)
+	result := []GrafanaAnnotation{}
+	for i, e := range list {
+		if !wanted(e) {
+			continue
+		}
+		a := GrafanaAnnotation{
+			Annotation: query,
+			Time:       e.Timestamp.UnixMilli(),
+			Title:      string(e.Type),
+			Text:       annotationText(e),
+			Tags:       []string{string(e.Type)},
+		}
+		if e.NodeID != "" {
+			a.Tags = append(a.Tags, e.NodeID)
+		}
+		if e.RunID != "" {
+			a.Tags = append(a.Tags, e.RunID)
+		}
+		if e.Type == events.EventChaosAttack {
+			a.TimeEnd = attackEnd(list[i+1:], e)
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// attackEnd は攻撃が再開またはリカバリーで終わった時刻を返す（範囲内で終わっていなければ0）
+func attackEnd(after []events.Event, attack events.Event) int64 {
+	for _, e := range after {
+		if e.NodeID != attack.NodeID || e.RunID != attack.RunID {
+			continue
+		}
+		switch e.Type {
+		case events.EventChaosResume, events.EventRecoverySuccess:
+			return e.Timestamp.UnixMilli()
+		case events.EventChaosAttack:
+			return 0
+		}
+	}
+	return 0
+}
+
+// annotationText はアノテーションの本文を組み立てる
+func annotationText(e events.Event) string {
+	var parts []string
+	if e.NodeID != "" {
+		parts = append(parts, "node "+e.NodeID)
+	}
+	d := e.Data
+	if d.AttackType != "" {
+		parts = append(parts, "attack "+string(d.AttackType))
+	}
+	if d.DelayDuration != "" {
+		parts = append(parts, "delay "+d.DelayDuration)
+	}
+	if d.Scenario != "" {
+		parts = append(parts, "scenario "+d.Scenario)
+	}
+	if d.Metric != "" {
+		parts = append(parts, "metric "+d.Metric)
+	}
+	if d.Reason != "" {
+		parts = append(parts, "reason "+d.Reason)
+	}
+	if d.Error != "" {
+		parts = append(parts, "error "+d.Error)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// grafanaEvents は表示範囲内の指定した種別のイベントを古い順に返す
+func (s *Server) grafanaEvents(rng GrafanaRange, types ...events.EventType) []events.Event {
+	h := s.eventBus.History()
+	if h == nil {
+		return nil
+	}
+	q := events.HistoryQuery{Types: types, Until: rng.To}
+	if !rng.From.IsZero() {
+		// Since は指定時刻を含まないため、範囲の開始時刻ちょうどのイベントも含める
+		q.Since = rng.From.Add(-time.Nanosecond)
+	}
+	return h.Query(q)
+}
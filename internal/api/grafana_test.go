@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// postGrafana は /api/grafana 配下にJSONをPOSTして応答をデコードする
+func postGrafana(t *testing.T, url, path string, body any, out any) int {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to encode request: %v", err)
+	}
+	resp, err := http.Post(url+"/api/grafana"+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusOK && out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// snapshotAt は指定した時刻と実行のメトリクスのスナップショットイベントを作成する
+func snapshotAt(ts time.Time, runID string, rps float64) events.Event {
+	e := events.NewMetricsSnapshotEvent(events.MetricsSummary{RPS: rps, NodeCount: 3})
+	e.Timestamp = ts
+	e.RunID = runID
+	return e
+}
+
+func TestGrafanaHealthAndSearch(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/grafana/")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 for the health check, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/api/grafana/unknown")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown path, got %d", resp.StatusCode)
+	}
+
+	var names []string
+	postGrafana(t, ts.URL, "/search", map[string]string{"target": ""}, &names)
+	if len(names) != len(grafanaMetrics) || names[0] != "rps" {
+		t.Errorf("expected all metric names, got %v", names)
+	}
+
+	var options []GrafanaMetricOption
+	postGrafana(t, ts.URL, "/metrics", map[string]string{}, &options)
+	if len(options) != len(grafanaMetrics) || options[0] != (GrafanaMetricOption{Label: "rps", Value: "rps"}) {
+		t.Errorf("expected metric options, got %+v", options)
+	}
+}
+
+func TestGrafanaQuery(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	base := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	s.eventBus.Publish(snapshotAt(base, "run-1", 10))
+	s.eventBus.Publish(snapshotAt(base.Add(5*time.Second), "run-1", 20))
+	s.eventBus.Publish(snapshotAt(base.Add(5*time.Second), "run-2", 99))
+	s.eventBus.Publish(snapshotAt(base.Add(10*time.Second), "run-1", 30))
+	s.eventBus.Publish(snapshotAt(base.Add(time.Hour), "run-1", 40)) // 範囲外
+
+	rng := GrafanaRange{From: base, To: base.Add(10 * time.Second)}
+
+	var series []GrafanaSeries
+	postGrafana(t, ts.URL, "/query", GrafanaQueryRequest{
+		Range:   rng,
+		Targets: []GrafanaTarget{{Target: "rps", RefID: "A"}, {Target: "node_count", RefID: "B", Hide: true}},
+	}, &series)
+	if len(series) != 2 || series[0].Target != "rps run-1" || series[1].Target != "rps run-2" {
+		t.Fatalf("expected one rps series per run, got %+v", series)
+	}
+	want := [][2]float64{
+		{10, float64(base.UnixMilli())},
+		{20, float64(base.Add(5 * time.Second).UnixMilli())},
+		{30, float64(base.Add(10 * time.Second).UnixMilli())},
+	}
+	if len(series[0].Datapoints) != len(want) {
+		t.Fatalf("expected %d datapoints, got %v", len(want), series[0].Datapoints)
+	}
+	for i, p := range want {
+		if series[0].Datapoints[i] != p {
+			t.Errorf("datapoint %d: expected %v, got %v", i, p, series[0].Datapoints[i])
+		}
+	}
+
+	// 実行を絞り込むと系列名に実行IDを付けない
+	postGrafana(t, ts.URL, "/query", GrafanaQueryRequest{
+		Range:         rng,
+		MaxDataPoints: 2,
+		Targets:       []GrafanaTarget{{Target: "rps", Payload: GrafanaPayload{Run: "run-1"}}},
+	}, &series)
+	if len(series) != 1 || series[0].Target != "rps" {
+		t.Fatalf("expected a single rps series, got %+v", series)
+	}
+	if len(series[0].Datapoints) != 2 || series[0].Datapoints[1][0] != 30 {
+		t.Errorf("expected 2 datapoints ending with the latest, got %v", series[0].Datapoints)
+	}
+
+	if code := postGrafana(t, ts.URL, "/query", GrafanaQueryRequest{
+		Range:   rng,
+		Targets: []GrafanaTarget{{Target: "bogus"}},
+	}, nil); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown metric, got %d", code)
+	}
+}
+
+func TestGrafanaAnnotations(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	base := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	publish := func(e events.Event, offset time.Duration) {
+		e.Timestamp = base.Add(offset)
+		e.RunID = "run-1"
+		s.eventBus.Publish(e)
+	}
+	publish(events.NewChaosAttackEvent("node-1", events.AttackTypeSuspend), 0)
+	publish(snapshotAt(base, "run-1", 10), time.Second)
+	publish(events.NewChaosResumeEvent("node-1"), 3*time.Second)
+	publish(events.NewChaosAttackEvent("node-2", events.AttackTypeKill), 4*time.Second)
+
+	rng := GrafanaRange{From: base, To: base.Add(10 * time.Second)}
+
+	var list []GrafanaAnnotation
+	postGrafana(t, ts.URL, "/annotations", GrafanaAnnotationRequest{
+		Range:      rng,
+		Annotation: GrafanaAnnotationQuery{Name: "chaos", Query: "chaos_attack"},
+	}, &list)
+	if len(list) != 2 {
+		t.Fatalf("expected 2 attack annotations, got %+v", list)
+	}
+	first := list[0]
+	if first.Time != base.UnixMilli() || first.TimeEnd != base.Add(3*time.Second).UnixMilli() {
+		t.Errorf("expected the attack to span until the resume, got %+v", first)
+	}
+	if first.Title != "chaos_attack" || first.Annotation.Name != "chaos" {
+		t.Errorf("unexpected annotation: %+v", first)
+	}
+	if len(first.Tags) != 3 || first.Tags[1] != "node-1" || first.Tags[2] != "run-1" {
+		t.Errorf("expected type, node and run tags, got %v", first.Tags)
+	}
+	if list[1].TimeEnd != 0 {
+		t.Errorf("expected an unfinished attack to have no end, got %+v", list[1])
+	}
+
+	// 種別を指定しない場合はメトリクス以外の全イベント
+	postGrafana(t, ts.URL, "/annotations", GrafanaAnnotationRequest{Range: rng}, &list)
+	if len(list) != 3 {
+		t.Errorf("expected attacks and the resume, got %+v", list)
+	}
+
+	if code := postGrafana(t, ts.URL, "/annotations", GrafanaAnnotationRequest{
+		Range:      rng,
+		Annotation: GrafanaAnnotationQuery{Query: "bogus"},
+	}, nil); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown event type, got %d", code)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	points := make([][2]float64, 10)
+	for i := range points {
+		points[i] = [2]float64{float64(i), float64(i)}
+	}
+
+	if got := downsample(points, 0); len(got) != 10 {
+		t.Errorf("expected no downsampling without a limit, got %d points", len(got))
+	}
+	got := downsample(points, 3)
+	if len(got) > 3 || got[len(got)-1][0] != 9 {
+		t.Errorf("expected at most 3 points ending with the latest, got %v", got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i][1] <= got[i-1][1] {
+			t.Errorf("expected points in time order, got %v", got)
+		}
+	}
+}
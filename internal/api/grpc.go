@@ -0,0 +1,448 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+	chaoskvsv1 "github.com/nyasuto/chaos-kvs/proto/chaoskvs/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultStreamInterval は StreamMetrics の間隔が未指定の場合の配信間隔
+const defaultStreamInterval = 1 * time.Second
+
+// minStreamInterval は StreamMetrics で指定できる最小の配信間隔
+const minStreamInterval = 100 * time.Millisecond
+
+// grpcMethodRoles はgRPCメソッドごとに必要なロール（未登録はRoleReader）
+var grpcMethodRoles = map[string]Role{
+	chaoskvsv1.ChaosKVS_NodeAction_FullMethodName:    RoleOperator,
+	chaoskvsv1.ChaosKVS_StartScenario_FullMethodName: RoleOperator,
+	chaoskvsv1.ChaosKVS_StopScenario_FullMethodName:  RoleOperator,
+}
+
+// grpcLimitedMethods はレート制限の対象となるgRPCメソッド（HTTPの limit と同じ対象）
+var grpcLimitedMethods = map[string]bool{
+	chaoskvsv1.ChaosKVS_NodeAction_FullMethodName:    true,
+	chaoskvsv1.ChaosKVS_StartScenario_FullMethodName: true,
+}
+
+// grpcService は chaoskvsv1.ChaosKVSServer の実装
+// HTTPハンドラーと同じ操作を呼び出す
+type grpcService struct {
+	chaoskvsv1.UnimplementedChaosKVSServer
+	s *Server
+}
+
+// GRPCServer はAPIと同じ操作を提供するgRPCサーバーを作成する
+// 認証はHTTPと同じトークンを "authorization: Bearer <token>" メタデータで受け付ける
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(s.streamAuthInterceptor),
+	)
+	gs := grpc.NewServer(opts...)
+	chaoskvsv1.RegisterChaosKVSServer(gs, &grpcService{s: s})
+	return gs
+}
+
+// GetStatus はシナリオとクラスタの状態を返す
+func (g *grpcService) GetStatus(ctx context.Context, _ *chaoskvsv1.GetStatusRequest) (*chaoskvsv1.Status, error) {
+	st := runStatus(g.s.currentRun())
+	return &chaoskvsv1.Status{
+		Running:        st.Running,
+		ScenarioName:   st.ScenarioName,
+		NodeCount:      int32(st.NodeCount),
+		RunningNodes:   int32(st.RunningNodes),
+		StoppedNodes:   int32(st.StoppedNodes),
+		SuspendedNodes: int32(st.SuspendedNodes),
+	}, nil
+}
+
+// ListNodes はノード一覧を返す
+func (g *grpcService) ListNodes(ctx context.Context, req *chaoskvsv1.ListNodesRequest) (*chaoskvsv1.ListNodesResponse, error) {
+	// HTTPと同じ解釈になるようクエリパラメータに変換して解析する
+	values := url.Values{}
+	if len(req.GetStatus()) > 0 {
+		values.Set("status", strings.Join(req.GetStatus(), ","))
+	}
+	if len(req.GetZone()) > 0 {
+		values.Set("zone", strings.Join(req.GetZone(), ","))
+	}
+	for k, v := range req.GetLabels() {
+		values.Add("label", k+"="+v)
+	}
+	if req.GetSort() != "" {
+		values.Set("sort", req.GetSort())
+	}
+	if req.GetDesc() {
+		values.Set("order", "desc")
+	}
+	if req.GetLimit() != 0 {
+		values.Set("limit", strconv.Itoa(int(req.GetLimit())))
+	}
+	if req.GetOffset() != 0 {
+		values.Set("offset", strconv.Itoa(int(req.GetOffset())))
+	}
+
+	query, err := parseNodeQuery(values)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	nodes, total := listNodes(g.s.currentRun(), query)
+	resp := &chaoskvsv1.ListNodesResponse{Total: int32(total)}
+	for _, n := range nodes {
+		resp.Nodes = append(resp.Nodes, nodeToProto(n))
+	}
+	return resp, nil
+}
+
+// NodeAction はノードへの障害注入・復旧を行う
+func (g *grpcService) NodeAction(ctx context.Context, req *chaoskvsv1.NodeActionRequest) (*chaoskvsv1.Node, error) {
+	var action string
+	switch req.GetAction() {
+	case chaoskvsv1.NodeActionRequest_ACTION_KILL:
+		action = "kill"
+	case chaoskvsv1.NodeActionRequest_ACTION_SUSPEND:
+		action = "suspend"
+	case chaoskvsv1.NodeActionRequest_ACTION_RESUME:
+		action = "resume"
+	case chaoskvsv1.NodeActionRequest_ACTION_START:
+		action = "start"
+	case chaoskvsv1.NodeActionRequest_ACTION_DELAY:
+		action = "delay"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown action: %v", req.GetAction())
+	}
+
+	var delay *time.Duration
+	if req.GetDelay() != nil {
+		d := req.GetDelay().AsDuration()
+		delay = &d
+	}
+
+	info, err := nodeAction(g.s.currentRun(), req.GetNodeId(), action, delay)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return nodeToProto(info), nil
+}
+
+// StartScenario はシナリオをバックグラウンドで開始する
+func (g *grpcService) StartScenario(ctx context.Context, req *chaoskvsv1.StartScenarioRequest) (*chaoskvsv1.StartScenarioResponse, error) {
+	sr := ScenarioRequest{
+		Preset:   req.GetPreset(),
+		Scenario: scenarioConfigFromProto(req.GetScenario()),
+		Nodes:    int(req.GetNodes()),
+	}
+	if req.GetDuration() != nil {
+		sr.Duration = req.GetDuration().AsDuration().String()
+	}
+
+	resp, err := g.s.startScenario(sr, true)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &chaoskvsv1.StartScenarioResponse{Scenario: resp.Scenario, RunId: resp.RunID}, nil
+}
+
+// StopScenario は実行中のシナリオを中断し、途中までの結果を返す
+func (g *grpcService) StopScenario(ctx context.Context, _ *chaoskvsv1.StopScenarioRequest) (*chaoskvsv1.StopScenarioResponse, error) {
+	resp, err := stopRun(ctx, g.s.currentRun())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &chaoskvsv1.StopScenarioResponse{RunId: resp.RunID, Result: resultToProto(resp.Result)}, nil
+}
+
+// GetMetrics はクライアントメトリクスを返す
+func (g *grpcService) GetMetrics(ctx context.Context, _ *chaoskvsv1.GetMetricsRequest) (*chaoskvsv1.Metrics, error) {
+	return g.metrics(), nil
+}
+
+// StreamMetrics はメトリクスを一定間隔で配信する
+func (g *grpcService) StreamMetrics(req *chaoskvsv1.StreamMetricsRequest, stream grpc.ServerStreamingServer[chaoskvsv1.Metrics]) error {
+	interval := defaultStreamInterval
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+		if interval < minStreamInterval {
+			return status.Errorf(codes.InvalidArgument, "interval must be at least %v", minStreamInterval)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := stream.Send(g.metrics()); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-g.s.shutdown:
+			return status.Error(codes.Unavailable, "server shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamEvents はカオス/復旧イベントを配信する
+// types が指定された場合は一致するイベントのみを送る
+func (g *grpcService) StreamEvents(req *chaoskvsv1.StreamEventsRequest, stream grpc.ServerStreamingServer[chaoskvsv1.Event]) error {
+	types := make([]events.EventType, 0, len(req.GetTypes()))
+	for _, t := range req.GetTypes() {
+		types = append(types, events.EventType(t))
+	}
+
+	ch := g.s.eventBus.SubscribeFiltered(events.ByType(types...))
+	defer g.s.eventBus.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-g.s.shutdown:
+			return status.Error(codes.Unavailable, "server shutting down")
+		case event, ok := <-ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "server shutting down")
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// metrics は現在のメトリクスを返す
+func (g *grpcService) metrics() *chaoskvsv1.Metrics {
+	rn := g.s.currentRun()
+	m := &chaoskvsv1.Metrics{
+		Timestamp: timestamppb.Now(),
+		Running:   rn != nil && rn.isRunning(),
+	}
+	if snap := runMetrics(rn); snap != nil {
+		fillMetrics(m, snap)
+	}
+	return m
+}
+
+// fillMetrics はスナップショットの値を設定する
+func fillMetrics(m *chaoskvsv1.Metrics, snap *metrics.Snapshot) {
+	m.TotalRequests = snap.TotalRequests
+	m.SuccessRequests = snap.SuccessRequests
+	m.FailedRequests = snap.FailedRequests
+	m.Rps = snap.RPS
+	m.AvgLatency = durationpb.New(snap.AverageLatency)
+	m.P99Latency = durationpb.New(snap.P99Latency)
+	m.ErrorRate = snap.ErrorRate
+}
+
+// grpcError は操作エラーをgRPCステータスに変換する
+func grpcError(err error) error {
+	var opErr *opError
+	if !errors.As(err, &opErr) {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code := codes.FailedPrecondition
+	switch opErr.kind {
+	case errInvalid:
+		code = codes.InvalidArgument
+	case errNotFound:
+		code = codes.NotFound
+	case errTimeout:
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, opErr.msg)
+}
+
+// unaryAuthInterceptor はunary RPCの認証とレート制限を行う
+func (s *Server) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorizeGRPC(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	if s.limiter != nil && grpcLimitedMethods[info.FullMethod] {
+		var key string
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			key = clientKey(p.Addr.String())
+		}
+		if ok, wait := s.limiter.allow(key); !ok {
+			return nil, status.Errorf(codes.ResourceExhausted, "too many requests, retry after %v", wait.Round(time.Millisecond))
+		}
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor はstreaming RPCの認証を行う
+func (s *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorizeGRPC(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorizeGRPC はメタデータのトークンがメソッドに必要なロールを満たすか検証する
+func (s *Server) authorizeGRPC(ctx context.Context, method string) error {
+	required, ok := grpcMethodRoles[method]
+	if !ok {
+		required = RoleReader
+	}
+
+	token := metadataToken(ctx)
+	if s.auth.allowedToken(token, required) {
+		return nil
+	}
+	if s.auth.roleOfToken(token) == RoleNone {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return status.Errorf(codes.PermissionDenied, "%s role required", required)
+}
+
+// metadataToken は "authorization" メタデータからBearerトークンを取り出す
+func metadataToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return bearerToken(values[0])
+}
+
+// nodeToProto はノード情報をprotoに変換する
+func nodeToProto(n NodeInfo) *chaoskvsv1.Node {
+	return &chaoskvsv1.Node{
+		Id:     n.ID,
+		Status: n.Status,
+		Size:   int32(n.Size),
+		Delay:  durationpb.New(n.delay),
+		Zone:   n.Zone,
+		Labels: n.Labels,
+	}
+}
+
+// scenarioConfigFromProto はprotoのシナリオ設定を設定ファイルの形式に変換する
+// 未指定の項目は空のままにし、プリセットの値を上書きしない
+func scenarioConfigFromProto(pc *chaoskvsv1.ScenarioConfig) *config.ScenarioConfig {
+	if pc == nil {
+		return nil
+	}
+
+	sc := &config.ScenarioConfig{
+		Name:        pc.GetName(),
+		Description: pc.GetDescription(),
+		Duration:    durationString(pc.GetDuration()),
+		NodeCount:   int(pc.GetNodeCount()),
+		Zones:       pc.GetZones(),
+	}
+	if c := pc.GetClient(); c != nil {
+		sc.Client = config.ClientConfig{
+			Workers:    int(c.GetWorkers()),
+			WriteRatio: c.GetWriteRatio(),
+		}
+	}
+	if c := pc.GetChaos(); c != nil {
+		enabled := c.GetEnabled()
+		sc.Chaos = config.ChaosConfig{
+			Enabled:     &enabled,
+			Interval:    durationString(c.GetInterval()),
+			Targets:     int(c.GetTargets()),
+			AttackTypes: c.GetAttackTypes(),
+		}
+	}
+	if r := pc.GetRecovery(); r != nil {
+		enabled := r.GetEnabled()
+		sc.Recovery = config.RecoveryConfig{
+			Enabled:    &enabled,
+			Delay:      durationString(r.GetDelay()),
+			MaxRetries: int(r.GetMaxRetries()),
+		}
+	}
+	return sc
+}
+
+// durationString は未指定の場合に空文字列を返す
+func durationString(d *durationpb.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return d.AsDuration().String()
+}
+
+// resultToProto は実行結果をprotoに変換する
+func resultToProto(r *scenario.Result) *chaoskvsv1.Result {
+	if r == nil {
+		return nil
+	}
+	return &chaoskvsv1.Result{
+		ScenarioName:      r.ScenarioName,
+		StartTime:         timestamppb.New(r.StartTime),
+		EndTime:           timestamppb.New(r.EndTime),
+		Duration:          durationpb.New(r.Duration),
+		Interrupted:       r.Interrupted,
+		TotalRequests:     r.TotalRequests,
+		SuccessRequests:   r.SuccessRequests,
+		FailedRequests:    r.FailedRequests,
+		ErrorRate:         r.ErrorRate,
+		AvgLatency:        durationpb.New(r.AvgLatency),
+		P99Latency:        durationpb.New(r.P99Latency),
+		TotalAttacks:      r.TotalAttacks,
+		TotalRecoveries:   r.TotalRecoveries,
+		SuccessRecoveries: r.SuccessRecoveries,
+		FailedRecoveries:  r.FailedRecoveries,
+		FinalNodeStatus:   r.FinalNodeStatus,
+	}
+}
+
+// eventToProto はイベントをprotoに変換する
+func eventToProto(e events.Event) *chaoskvsv1.Event {
+	return &chaoskvsv1.Event{
+		Type:          string(e.Type),
+		Timestamp:     timestamppb.New(e.Timestamp),
+		NodeId:        e.NodeID,
+		AttackType:    string(e.Data.AttackType),
+		DelayDuration: e.Data.DelayDuration,
+		Attempt:       int32(e.Data.Attempt),
+		Error:         e.Data.Error,
+		Reason:        e.Data.Reason,
+		NodeCount:     int32(e.Data.NodeCount),
+		Added:         e.Data.Added,
+		Removed:       e.Data.Removed,
+		Errors:        e.Data.Errors,
+		Requests:      e.Data.Requests,
+		Window:        e.Data.Window,
+		Metric:        e.Data.Metric,
+		Threshold:     e.Data.Threshold,
+		Value:         e.Data.Value,
+		Rps:           e.Data.RPS,
+		AvgLatencyMs:  e.Data.AvgLatencyMs,
+		P99LatencyMs:  e.Data.P99LatencyMs,
+		ErrorRate:     e.Data.ErrorRate,
+		NodesRunning:  int32(e.Data.NodesRunning),
+		Attacks:       e.Data.Attacks,
+		Scenario:      e.Data.Scenario,
+	}
+}
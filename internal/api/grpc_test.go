@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	chaoskvsv1 "github.com/nyasuto/chaos-kvs/proto/chaoskvs/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newTestGRPCClient はインメモリ接続のgRPCクライアントを作成する
+func newTestGRPCClient(t *testing.T, s *Server) chaoskvsv1.ChaosKVSClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	gs := s.GRPCServer()
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return chaoskvsv1.NewChaosKVSClient(conn)
+}
+
+// withToken はBearerトークンを付与したコンテキストを返す
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestGRPCScenarioLifecycle(t *testing.T) {
+	s := NewServer("")
+	client := newTestGRPCClient(t, s)
+	ctx := context.Background()
+
+	st, err := client.GetStatus(ctx, &chaoskvsv1.GetStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if st.Running {
+		t.Error("expected no scenario running")
+	}
+
+	_, err = client.NodeAction(ctx, &chaoskvsv1.NodeActionRequest{
+		NodeId: "node-1",
+		Action: chaoskvsv1.NodeActionRequest_ACTION_KILL,
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition without scenario, got %v", err)
+	}
+
+	_, err = client.StartScenario(ctx, &chaoskvsv1.StartScenarioRequest{
+		Preset:   "missing",
+		Scenario: &chaoskvsv1.ScenarioConfig{},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for unknown preset, got %v", err)
+	}
+
+	start, err := client.StartScenario(ctx, &chaoskvsv1.StartScenarioRequest{
+		Preset:   "basic",
+		Duration: durationpb.New(30 * time.Second),
+		Scenario: &chaoskvsv1.ScenarioConfig{
+			NodeCount: 3,
+			Zones:     []string{"a", "b"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("StartScenario failed: %v", err)
+	}
+	if start.RunId == "" {
+		t.Error("expected run id")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if c := s.currentCluster(); c != nil && c.RunningCount() == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	nodes, err := client.ListNodes(ctx, &chaoskvsv1.ListNodesRequest{Zone: []string{"a"}})
+	if err != nil {
+		t.Fatalf("ListNodes failed: %v", err)
+	}
+	if nodes.Total != 2 || len(nodes.Nodes) != 2 {
+		t.Errorf("expected 2 nodes in zone a, got %d (%d)", len(nodes.Nodes), nodes.Total)
+	}
+	if _, err := client.ListNodes(ctx, &chaoskvsv1.ListNodesRequest{Sort: "name"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for invalid sort, got %v", err)
+	}
+
+	n, err := client.NodeAction(ctx, &chaoskvsv1.NodeActionRequest{
+		NodeId: "node-1",
+		Action: chaoskvsv1.NodeActionRequest_ACTION_DELAY,
+		Delay:  durationpb.New(20 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("NodeAction failed: %v", err)
+	}
+	if n.Delay.AsDuration() != 20*time.Millisecond {
+		t.Errorf("expected delay 20ms, got %v", n.Delay.AsDuration())
+	}
+
+	_, err = client.NodeAction(ctx, &chaoskvsv1.NodeActionRequest{
+		NodeId: "missing",
+		Action: chaoskvsv1.NodeActionRequest_ACTION_KILL,
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for unknown node, got %v", err)
+	}
+
+	stop, err := client.StopScenario(ctx, &chaoskvsv1.StopScenarioRequest{})
+	if err != nil {
+		t.Fatalf("StopScenario failed: %v", err)
+	}
+	if stop.RunId != start.RunId {
+		t.Errorf("expected run id %s, got %s", start.RunId, stop.RunId)
+	}
+	if stop.Result == nil || !stop.Result.Interrupted {
+		t.Errorf("expected interrupted result, got %v", stop.Result)
+	}
+}
+
+func TestGRPCStreamMetrics(t *testing.T) {
+	s := NewServer("")
+	client := newTestGRPCClient(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamMetrics(ctx, &chaoskvsv1.StreamMetricsRequest{Interval: durationpb.New(100 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("StreamMetrics failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		m, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("failed to receive metrics: %v", err)
+		}
+		if m.Timestamp == nil {
+			t.Error("expected timestamp")
+		}
+	}
+
+	stream, err = client.StreamMetrics(ctx, &chaoskvsv1.StreamMetricsRequest{Interval: durationpb.New(time.Millisecond)})
+	if err != nil {
+		t.Fatalf("StreamMetrics failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for short interval, got %v", err)
+	}
+}
+
+func TestGRPCStreamEvents(t *testing.T) {
+	s := NewServer("")
+	client := newTestGRPCClient(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamEvents(ctx, &chaoskvsv1.StreamEventsRequest{
+		Types: []string{string(events.EventRecoveryStart)},
+	})
+	if err != nil {
+		t.Fatalf("StreamEvents failed: %v", err)
+	}
+
+	// 購読が登録されるまで待つ
+	for s.eventBus.SubscriberCount() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	s.eventBus.Publish(events.NewChaosAttackEvent("node-1", events.AttackTypeKill))
+	s.eventBus.Publish(events.NewRecoveryStartEvent("node-1", 1))
+
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive event: %v", err)
+	}
+	if e.Type != string(events.EventRecoveryStart) || e.NodeId != "node-1" || e.Attempt != 1 {
+		t.Errorf("unexpected event: %v", e)
+	}
+
+	// シャットダウンでストリームが終了する
+	s.closeClients(ctx)
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected Unavailable after shutdown, got %v", err)
+	}
+}
+
+func TestGRPCAuth(t *testing.T) {
+	config := DefaultConfig()
+	config.ReadToken = "reader-secret"
+	config.OperatorToken = "operator-secret"
+	s, err := NewServerWithConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	client := newTestGRPCClient(t, s)
+	ctx := context.Background()
+
+	if _, err := client.GetStatus(ctx, &chaoskvsv1.GetStatusRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without token, got %v", err)
+	}
+	if _, err := client.GetStatus(withToken(ctx, "wrong"), &chaoskvsv1.GetStatusRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated with wrong token, got %v", err)
+	}
+	if _, err := client.GetStatus(withToken(ctx, "reader-secret"), &chaoskvsv1.GetStatusRequest{}); err != nil {
+		t.Errorf("expected reader to get status, got %v", err)
+	}
+	if _, err := client.StopScenario(withToken(ctx, "reader-secret"), &chaoskvsv1.StopScenarioRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for reader, got %v", err)
+	}
+	// 認可は通過し、シナリオ未実行で失敗する
+	if _, err := client.StopScenario(withToken(ctx, "operator-secret"), &chaoskvsv1.StopScenarioRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition for operator, got %v", err)
+	}
+
+	stream, err := client.StreamMetrics(ctx, &chaoskvsv1.StreamMetricsRequest{})
+	if err != nil {
+		t.Fatalf("StreamMetrics failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated for stream without token, got %v", err)
+	}
+}
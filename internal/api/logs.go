@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+// accessLogger はアクセスログの出力先
+// UIのポーリングで直近ログのバッファが埋まらないよう、バッファには記録しない
+var accessLogger = logger.New(os.Stdout, logger.LevelInfo)
+
+// parseLogQuery は GET /api/logs のクエリパラメータを解析する
+// since・until はRFC3339の時刻、または現在からの期間（例: 5m）、component はカンマ区切りのコンポーネント名を受け付ける
+func parseLogQuery(values url.Values, now time.Time) (logger.Query, error) {
+	q := logger.Query{MinLevel: logger.LevelDebug, NodeID: values.Get("node")}
+
+	if v := values.Get("level"); v != "" {
+		level, err := logger.ParseLevel(v)
+		if err != nil {
+			return q, fmt.Errorf("invalid level: %s", v)
+		}
+		q.MinLevel = level
+	}
+
+	if v := values.Get("component"); v != "" {
+		for _, c := range strings.Split(v, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				q.Components = append(q.Components, c)
+			}
+		}
+	}
+
+	var err error
+	if q.Since, err = timeParam(values, "since", now); err != nil {
+		return q, err
+	}
+	if q.Until, err = timeParam(values, "until", now); err != nil {
+		return q, err
+	}
+	if q.Limit, err = nonNegativeInt(values, "limit"); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// timeParam はRFC3339の時刻、または現在からの期間（例: 5m）のクエリパラメータを解析する
+// 指定がない場合はゼロ値を返す
+func timeParam(values url.Values, name string, now time.Time) (time.Time, error) {
+	v := values.Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s: %s (expected RFC3339 time or duration)", name, v)
+}
+
+// handleLogs は直近のログを古い順に返す
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseLogQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries := []logger.Entry{}
+	if s.logs != nil {
+		entries = s.logs.Query(query)
+	}
+
+	s.writeJSON(w, entries)
+}
+
+// forwardLogs は新しいログを購読しているWebSocketクライアントに送信する
+func (s *Server) forwardLogs(entryCh <-chan logger.Entry) {
+	for entry := range entryCh {
+		data, err := json.Marshal(map[string]interface{}{
+			"type":  "log",
+			"entry": entry,
+		})
+		if err != nil {
+			continue
+		}
+		s.broadcastLogWS(entry.Level, data)
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+
+	"golang.org/x/net/websocket"
+)
+
+// newTestLogServer は専用のログバッファを持つテストサーバーと、そこに記録するロガーを返す
+func newTestLogServer(t *testing.T) (*logger.Logger, *Server, string) {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.LogBuffer = logger.NewBuffer(10)
+	s, ts := newTestServerWithConfig(t, config)
+
+	l := logger.New(io.Discard, logger.LevelDebug)
+	l.SetBuffer(config.LogBuffer)
+	return l, s, ts.URL
+}
+
+func TestLogsEndpoint(t *testing.T) {
+	l, _, url := newTestLogServer(t)
+
+	l.With("chaos").Info("", "monkey started")
+	l.With("chaos").Warn("node-1", "killed node")
+	l.With("recovery").Error("", "recovery failed")
+
+	get := func(query string) (*http.Response, []logger.Entry) {
+		t.Helper()
+		resp, err := http.Get(url + "/api/logs" + query)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		var entries []logger.Entry
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp, entries
+	}
+
+	if _, entries := get(""); len(entries) != 3 || entries[0].Message != "monkey started" {
+		t.Errorf("expected all 3 entries oldest first, got %+v", entries)
+	}
+	if _, entries := get("?level=warn"); len(entries) != 2 || entries[0].NodeID != "node-1" {
+		t.Errorf("expected 2 warn+ entries, got %+v", entries)
+	}
+	if _, entries := get("?limit=1"); len(entries) != 1 || entries[0].Message != "recovery failed" {
+		t.Errorf("expected latest entry, got %+v", entries)
+	}
+	if _, entries := get("?node=node-1"); len(entries) != 1 || entries[0].Message != "killed node" {
+		t.Errorf("expected only node-1 entries, got %+v", entries)
+	}
+	if _, entries := get("?until=1h"); len(entries) != 0 {
+		t.Errorf("expected no entries older than an hour, got %+v", entries)
+	}
+	if _, entries := get("?component=recovery"); len(entries) != 1 || entries[0].Component != "recovery" {
+		t.Errorf("expected only recovery entries, got %+v", entries)
+	}
+	if _, entries := get("?component=chaos,recovery&level=warn"); len(entries) != 2 {
+		t.Errorf("expected 2 warn+ entries from chaos and recovery, got %+v", entries)
+	}
+	if _, entries := get("?since=1m"); len(entries) != 3 {
+		t.Errorf("expected 3 entries in the last minute, got %d", len(entries))
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if _, entries := get("?since=" + future); len(entries) != 0 {
+		t.Errorf("expected no entries after %s, got %d", future, len(entries))
+	}
+
+	for _, query := range []string{"?level=verbose", "?since=yesterday", "?until=later", "?limit=-1"} {
+		if resp, _ := get(query); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestWebSocketLogs(t *testing.T) {
+	l, s, url := newTestLogServer(t)
+
+	wsURL := "ws" + strings.TrimPrefix(url, "http") + "/ws?logs=warn"
+	ws, err := websocket.Dial(wsURL, "", url)
+	if err != nil {
+		t.Fatalf("failed to connect websocket: %v", err)
+	}
+	defer func() { _ = ws.Close() }()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.RLock()
+		n := len(s.wsClients)
+		s.mu.RUnlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for websocket registration")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	l.Info("", "below level")
+	l.Warn("node-2", "suspended node")
+
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg struct {
+		Type  string       `json:"type"`
+		Entry logger.Entry `json:"entry"`
+	}
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("failed to receive log message: %v", err)
+	}
+	if msg.Type != "log" || msg.Entry.Message != "suspended node" || msg.Entry.Level != logger.LevelWarn {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// nodeQuery は /api/nodes のフィルタ・ソート・ページネーション条件
+//
+//	status=running,suspended  状態でフィルタ（カンマ区切り）
+//	zone=zone-a,zone-b        ゾーンでフィルタ（カンマ区切り）
+//	label=key=value           ラベルでフィルタ（複数指定でAND）
+//	sort=id|status|size|memory|ops|delay 並び順のキー（latency は delay の別名）
+//	order=asc|desc            並び順
+//	limit=N&offset=M          ページネーション
+type nodeQuery struct {
+	statuses map[string]bool
+	zones    map[string]bool
+	labels   map[string]string
+	sortKey  string
+	desc     bool
+	limit    int // 0で無制限
+	offset   int
+}
+
+// parseNodeQuery はクエリパラメータを解析する
+func parseNodeQuery(values url.Values) (nodeQuery, error) {
+	q := nodeQuery{sortKey: "id"}
+
+	q.statuses = splitSet(values.Get("status"))
+	q.zones = splitSet(values.Get("zone"))
+
+	for _, l := range values["label"] {
+		key, value, ok := strings.Cut(l, "=")
+		if !ok || key == "" {
+			return q, fmt.Errorf("invalid label filter: %q (expected key=value)", l)
+		}
+		if q.labels == nil {
+			q.labels = make(map[string]string)
+		}
+		q.labels[key] = value
+	}
+
+	if v := values.Get("sort"); v != "" {
+		switch v {
+		case "id", "status", "size", "memory", "ops", "delay":
+			q.sortKey = v
+		case "latency":
+			q.sortKey = "delay"
+		default:
+			return q, fmt.Errorf("invalid sort key: %s", v)
+		}
+	}
+
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.desc = true
+	default:
+		return q, fmt.Errorf("invalid order: %s", values.Get("order"))
+	}
+
+	var err error
+	if q.limit, err = nonNegativeInt(values, "limit"); err != nil {
+		return q, err
+	}
+	if q.offset, err = nonNegativeInt(values, "offset"); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// splitSet はカンマ区切りの値を小文字のセットに変換する（空の場合はnil）
+func splitSet(v string) map[string]bool {
+	if v == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			set[strings.ToLower(s)] = true
+		}
+	}
+	return set
+}
+
+// nonNegativeInt はクエリパラメータを0以上の整数として解析する
+func nonNegativeInt(values url.Values, name string) (int, error) {
+	v := values.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", name, v)
+	}
+	return n, nil
+}
+
+// match はノードがフィルタ条件に一致するかどうかを返す
+func (q nodeQuery) match(n *node.Node) bool {
+	if q.statuses != nil && !q.statuses[n.Status().String()] {
+		return false
+	}
+	if q.zones != nil && !q.zones[strings.ToLower(n.Label(node.LabelZone))] {
+		return false
+	}
+	for key, value := range q.labels {
+		if n.Label(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// apply はフィルタ・ソート・ページネーションを適用し、ページとフィルタ後の総数を返す
+func (q nodeQuery) apply(nodes []*node.Node) ([]NodeInfo, int) {
+	infos := make([]NodeInfo, 0, len(nodes))
+	for _, n := range nodes {
+		if q.match(n) {
+			infos = append(infos, newNodeInfo(n))
+		}
+	}
+
+	less := q.less()
+	sort.SliceStable(infos, func(i, j int) bool {
+		if q.desc {
+			return less(infos[j], infos[i])
+		}
+		return less(infos[i], infos[j])
+	})
+
+	total := len(infos)
+	start := min(q.offset, total)
+	end := total
+	if q.limit > 0 {
+		end = min(start+q.limit, total)
+	}
+	return infos[start:end], total
+}
+
+// less はソートキーに応じた比較関数を返す（同値の場合はID順）
+func (q nodeQuery) less() func(a, b NodeInfo) bool {
+	byID := func(a, b NodeInfo) bool { return naturalLess(a.ID, b.ID) }
+
+	switch q.sortKey {
+	case "status":
+		return func(a, b NodeInfo) bool {
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+			return byID(a, b)
+		}
+	case "size":
+		return func(a, b NodeInfo) bool {
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+			return byID(a, b)
+		}
+	case "memory":
+		return func(a, b NodeInfo) bool {
+			if a.MemoryUsage != b.MemoryUsage {
+				return a.MemoryUsage < b.MemoryUsage
+			}
+			return byID(a, b)
+		}
+	case "ops":
+		return func(a, b NodeInfo) bool {
+			if a.OpsPerSecond != b.OpsPerSecond {
+				return a.OpsPerSecond < b.OpsPerSecond
+			}
+			return byID(a, b)
+		}
+	case "delay":
+		return func(a, b NodeInfo) bool {
+			if a.delay != b.delay {
+				return a.delay < b.delay
+			}
+			return byID(a, b)
+		}
+	default:
+		return byID
+	}
+}
+
+// naturalLess は末尾の数値を数値として比較する（node-2 < node-10）
+func naturalLess(a, b string) bool {
+	ap, an := splitNumericSuffix(a)
+	bp, bn := splitNumericSuffix(b)
+	if ap != bp || an < 0 || bn < 0 {
+		return a < b
+	}
+	return an < bn
+}
+
+// splitNumericSuffix は文字列を接頭辞と末尾の数値に分割する（数値がない場合は-1）
+func splitNumericSuffix(s string) (string, int) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(s[i:])
+	if err != nil {
+		return s, -1
+	}
+	return s[:i], n
+}
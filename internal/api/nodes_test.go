@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// newQueryTestNodes はフィルタ・ソート検証用のノードを作成する
+// node-1..node-12 をゾーン a/b/c に割り当て、node-3 を停止、node-5 に遅延を設定する
+// node-7 は2つのキー、node-9 はキー数の少ない代わりに大きな値を保持する
+func newQueryTestNodes(t *testing.T) []*node.Node {
+	t.Helper()
+
+	c := cluster.New()
+	_ = c.CreateNodes(12, "node")
+	c.AssignZones([]string{"a", "b", "c"})
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.StopAll() })
+
+	n3, _ := c.GetNode("node-3")
+	_ = n3.Stop()
+	n5, _ := c.GetNode("node-5")
+	n5.SetDelay(50 * time.Millisecond)
+	n7, _ := c.GetNode("node-7")
+	_ = n7.Set("k1", []byte("v"))
+	_ = n7.Set("k2", []byte("v"))
+	n7.SetLabel("rack", "r1")
+	n9, _ := c.GetNode("node-9")
+	_ = n9.Set("big", make([]byte, 1024))
+
+	return c.Nodes()
+}
+
+// nodeIDs はNodeInfoのIDを返す
+func nodeIDs(infos []NodeInfo) []string {
+	ids := make([]string, len(infos))
+	for i, info := range infos {
+		ids[i] = info.ID
+	}
+	return ids
+}
+
+func TestParseNodeQueryInvalid(t *testing.T) {
+	tests := []string{
+		"sort=name",
+		"order=up",
+		"limit=-1",
+		"offset=x",
+		"label=zone",
+	}
+
+	for _, raw := range tests {
+		values, _ := url.ParseQuery(raw)
+		if _, err := parseNodeQuery(values); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestNodeQueryApply(t *testing.T) {
+	nodes := newQueryTestNodes(t)
+
+	tests := []struct {
+		query    string
+		expected []string
+		total    int
+	}{
+		{"limit=3", []string{"node-1", "node-2", "node-3"}, 12},
+		{"offset=10", []string{"node-11", "node-12"}, 12},
+		{"offset=20", []string{}, 12},
+		{"status=stopped", []string{"node-3"}, 1},
+		{"zone=b&limit=2", []string{"node-2", "node-5"}, 4},
+		{"label=rack=r1", []string{"node-7"}, 1},
+		{"sort=size&order=desc&limit=1", []string{"node-7"}, 12},
+		{"sort=memory&order=desc&limit=1", []string{"node-9"}, 12},
+		{"sort=latency&order=desc&limit=1", []string{"node-5"}, 12},
+		{"status=running&zone=c&sort=id&order=desc", []string{"node-12", "node-9", "node-6"}, 3},
+	}
+
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.query)
+		q, err := parseNodeQuery(values)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", tt.query, err)
+		}
+
+		page, total := q.apply(nodes)
+		ids := nodeIDs(page)
+		if total != tt.total {
+			t.Errorf("%s: expected total %d, got %d", tt.query, tt.total, total)
+		}
+		if len(ids) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.query, tt.expected, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	if !naturalLess("node-2", "node-10") {
+		t.Error("expected node-2 < node-10")
+	}
+	if naturalLess("node-10", "node-2") {
+		t.Error("expected node-10 > node-2")
+	}
+	if !naturalLess("a-1", "b-0") {
+		t.Error("expected different prefixes to compare lexically")
+	}
+}
+
+func TestHandleNodesQuery(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	startScenario(t, s, ts, `{"scenario":{"duration":"30s","node_count":4,"zones":["east","west"]}}`)
+	defer stopScenario(t, ts)
+
+	resp, err := http.Get(ts.URL + "/api/nodes?zone=west&limit=1")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if got := resp.Header.Get("X-Total-Count"); got != "2" {
+		t.Errorf("expected X-Total-Count 2, got %s", got)
+	}
+	var nodes []NodeInfo
+	if err := json.NewDecoder(resp.Body).Decode(&nodes); err != nil {
+		t.Fatalf("failed to decode nodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Zone != "west" {
+		t.Errorf("unexpected nodes: %+v", nodes)
+	}
+
+	resp2, err := http.Get(ts.URL + "/api/nodes?sort=bogus")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp2.Body.Close()
+	if resp2.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid sort, got %d", resp2.StatusCode)
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec はHTTP APIのOpenAPIドキュメント
+// routes() とレスポンス型のJSONフィールドと一致することをテストで検証する
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// OpenAPISpec はOpenAPIドキュメント（YAML）を返す
+func OpenAPISpec() []byte {
+	return openAPISpec
+}
+
+// handleOpenAPI はOpenAPIドキュメントを返す
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPISpec)
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/client"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/lincheck"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIDoc はテストで検証するOpenAPIドキュメントの一部
+type openAPIDoc struct {
+	Paths      map[string]map[string]any `yaml:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]any `yaml:"properties"`
+		} `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// loadOpenAPIDoc は埋め込まれたOpenAPIドキュメントを解析する
+func loadOpenAPIDoc(t *testing.T) openAPIDoc {
+	t.Helper()
+
+	var doc openAPIDoc
+	if err := yaml.Unmarshal(OpenAPISpec(), &doc); err != nil {
+		t.Fatalf("failed to parse openapi.yaml: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	doc := loadOpenAPIDoc(t)
+	s := NewServer("")
+
+	registered := map[string]bool{}
+	for _, rt := range s.routes() {
+		method, path, ok := strings.Cut(rt.pattern, " ")
+		if !ok {
+			t.Errorf("route %q must specify a method", rt.pattern)
+			continue
+		}
+		key := strings.ToLower(method) + " " + path
+		registered[key] = true
+
+		if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("route %q is not documented in openapi.yaml", rt.pattern)
+		}
+	}
+
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			if !registered[method+" "+path] {
+				t.Errorf("openapi.yaml documents %s %s which is not routed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// jsonFields は構造体のJSONフィールド名を返す
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			fields = append(fields, jsonFields(f.Type)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			name, _, _ = strings.Cut(tag, ",")
+			if name == "-" {
+				continue
+			}
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	doc := loadOpenAPIDoc(t)
+
+	schemas := map[string]any{
+		"StatusResponse":           StatusResponse{},
+		"NodeInfo":                 NodeInfo{},
+		"NodeDetail":               NodeDetail{},
+		"NodeOps":                  NodeOps{},
+		"NodeActionRequest":        NodeActionRequest{},
+		"ScaleRequest":             ScaleRequest{},
+		"ScaleResponse":            ScaleResponse{},
+		"NodeSnapshot":             cluster.NodeSnapshot{},
+		"RestoreResponse":          RestoreResponse{},
+		"RebalanceResponse":        RebalanceResponse{},
+		"MetricsResponse":          MetricsResponse{},
+		"GrafanaRange":             GrafanaRange{},
+		"GrafanaTarget":            GrafanaTarget{},
+		"GrafanaPayload":           GrafanaPayload{},
+		"GrafanaQueryRequest":      GrafanaQueryRequest{},
+		"GrafanaSeries":            GrafanaSeries{},
+		"GrafanaMetricOption":      GrafanaMetricOption{},
+		"GrafanaAnnotationQuery":   GrafanaAnnotationQuery{},
+		"GrafanaAnnotationRequest": GrafanaAnnotationRequest{},
+		"GrafanaAnnotation":        GrafanaAnnotation{},
+		"ScenarioRequest":          ScenarioRequest{},
+		"ScenarioConfig":           config.ScenarioConfig{},
+		"StartResponse":            StartResponse{},
+		"StopResponse":             StopResponse{},
+		"PresetInfo":               PresetInfo{},
+		"Result":                   scenario.Result{},
+		"AssertionFailure":         scenario.AssertionFailure{},
+		"StepResult":               scenario.StepResult{},
+		"HotKeyReport":             client.HotKeyReport{},
+		"ReplicaReport":            scenario.ReplicaReport{},
+		"ScanReport":               scenario.ScanReport{},
+		"DurabilityReport":         scenario.DurabilityReport{},
+		"AutoscaleReport":          scenario.AutoscaleReport{},
+		"ChaosAttack":              chaos.Attack{},
+		"HotKey":                   client.HotKey{},
+		"CheckResult":              lincheck.CheckResult{},
+		"Violation":                lincheck.Violation{},
+		"Operation":                lincheck.Operation{},
+		"RunSummary":               history.Summary{},
+		"Run":                      history.Run{},
+		"Event":                    events.Event{},
+		"LogEntry":                 logger.Entry{},
+		"AuthResponse":             AuthResponse{},
+	}
+
+	for name, v := range schemas {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is not defined in openapi.yaml", name)
+			continue
+		}
+
+		var documented []string
+		for prop := range schema.Properties {
+			documented = append(documented, prop)
+		}
+		sort.Strings(documented)
+
+		actual := jsonFields(reflect.TypeOf(v))
+		if !reflect.DeepEqual(documented, actual) {
+			t.Errorf("schema %s properties %v do not match %T fields %v", name, documented, v, actual)
+		}
+	}
+}
+
+func TestHandleOpenAPI(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/openapi.yaml")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("expected content type application/yaml, got %s", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(string(body), "openapi: 3.") {
+		t.Errorf("unexpected body: %.40s", body)
+	}
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// HTTP と gRPC の両方から呼ばれる操作をまとめる
+
+// errorKind は操作エラーの種類
+type errorKind int
+
+const (
+	errInvalid  errorKind = iota // リクエスト不正
+	errNotFound                  // 対象が存在しない
+	errConflict                  // 現在の状態では実行できない
+	errTimeout                   // 完了待ちがタイムアウトした
+)
+
+// opError は操作エラー
+// 各トランスポートは kind をステータスコードに変換する
+type opError struct {
+	kind   errorKind
+	status int // HTTPステータス（0の場合は kind から決定）
+	msg    string
+}
+
+func (e *opError) Error() string {
+	return e.msg
+}
+
+// httpStatus はHTTPステータスコードを返す
+func (e *opError) httpStatus() int {
+	if e.status != 0 {
+		return e.status
+	}
+	switch e.kind {
+	case errInvalid:
+		return http.StatusBadRequest
+	case errNotFound:
+		return http.StatusNotFound
+	case errTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusConflict
+	}
+}
+
+func newOpError(kind errorKind, format string, args ...interface{}) *opError {
+	return &opError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// writeError は操作エラーをHTTPレスポンスとして書き込む
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		http.Error(w, opErr.msg, opErr.httpStatus())
+		return
+	}
+	if r.Context().Err() != nil {
+		// クライアントが切断済みのため応答しない
+		return
+	}
+	logger.Error("", "Request %s failed: %v", requestIDFrom(r.Context()), err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// runStatus は実行の状態を返す（未実行の場合は空の状態）
+func runStatus(rn *run) StatusResponse {
+	if rn == nil {
+		return StatusResponse{}
+	}
+	return rn.status()
+}
+
+// runMetrics は実行のクライアントメトリクスを返す（未実行の場合はnil）
+func runMetrics(rn *run) *metrics.Snapshot {
+	if rn == nil {
+		return nil
+	}
+	return rn.metrics()
+}
+
+// listNodes はクエリに一致するノードと、ページング前の件数を返す
+func listNodes(rn *run, query nodeQuery) ([]NodeInfo, int) {
+	var all []*node.Node
+	if rn != nil {
+		if c := rn.cluster(); c != nil {
+			all = c.Nodes()
+		}
+	}
+	return query.apply(all)
+}
+
+// nodeDetail はノードの詳細を返す
+// 実行が終了していても、既定の実行であれば最後の状態を返す
+func nodeDetail(rn *run, nodeID string) (NodeDetail, error) {
+	var n *node.Node
+	if rn != nil {
+		if c := rn.cluster(); c != nil {
+			n, _ = c.GetNode(nodeID)
+		}
+	}
+	if n == nil {
+		return NodeDetail{}, newOpError(errNotFound, "Node %s not found", nodeID)
+	}
+
+	stats := n.Stats()
+	return NodeDetail{
+		NodeInfo:     newNodeInfo(n),
+		Incarnations: n.Incarnations(),
+		Ops: NodeOps{
+			Gets:     stats.Gets,
+			Hits:     stats.Hits,
+			Misses:   stats.Misses,
+			Sets:     stats.Sets,
+			Deletes:  stats.Deletes,
+			Scans:    stats.Scans,
+			Rejected: stats.Rejected,
+			Shed:     stats.Shed,
+			Expired:  stats.Expired,
+			Evicted:  stats.Evicted,
+			Lost:     stats.Lost,
+
+			Conflicts: stats.Conflicts,
+			Refused:   stats.Refused,
+			Corrupted: stats.Corrupted,
+
+			HitRate: stats.HitRate(),
+		},
+		Incidents: rn.nodeIncidents(nodeID),
+	}, nil
+}
+
+// nodeAction はノードに障害注入・復旧操作を行う
+// delay は action が "delay" の場合のみ使用し、nil の場合はカオス設定の遅延を使う
+func nodeAction(rn *run, nodeID, action string, delay *time.Duration) (NodeInfo, error) {
+	if rn == nil || !rn.isRunning() || rn.cluster() == nil || rn.engine.Monkey() == nil {
+		return NodeInfo{}, newOpError(errConflict, "No scenario running")
+	}
+
+	c := rn.cluster()
+	monkey := rn.engine.Monkey()
+
+	n, ok := c.GetNode(nodeID)
+	if !ok {
+		return NodeInfo{}, newOpError(errNotFound, "Node %s not found", nodeID)
+	}
+
+	var err error
+	switch action {
+	case "kill":
+		err = monkey.Inject(nodeID, chaos.AttackKill)
+	case "suspend":
+		err = monkey.Inject(nodeID, chaos.AttackSuspend)
+	case "readonly":
+		err = monkey.Inject(nodeID, chaos.AttackReadOnly)
+	case "corrupt":
+		err = monkey.Inject(nodeID, chaos.AttackCorrupt)
+	case "resume":
+		err = monkey.Resume(nodeID)
+	case "start":
+		err = c.StartNode(nodeID)
+	case "delay":
+		if delay == nil {
+			err = monkey.Inject(nodeID, chaos.AttackDelay)
+			break
+		}
+		if *delay < 0 {
+			return NodeInfo{}, newOpError(errInvalid, "Invalid delay")
+		}
+		err = monkey.InjectDelay(nodeID, *delay)
+	default:
+		return NodeInfo{}, newOpError(errNotFound, "Unknown action: %s", action)
+	}
+
+	if err != nil {
+		return NodeInfo{}, newOpError(errConflict, "%s", err.Error())
+	}
+	return newNodeInfo(n), nil
+}
+
+// startScenario はシナリオをバックグラウンドで開始し、既定の実行にする
+// exclusive の場合は既定の実行が実行中であれば開始しない
+func (s *Server) startScenario(req ScenarioRequest, exclusive bool) (StartResponse, error) {
+	config, err := req.scenarioConfig()
+	if err != nil {
+		return StartResponse{}, newOpError(errInvalid, "Invalid scenario config: %v", err)
+	}
+
+	s.mu.Lock()
+	if exclusive && s.current != nil && s.current.isRunning() {
+		s.mu.Unlock()
+		return StartResponse{}, newOpError(errConflict, "Scenario already running")
+	}
+	active := 0
+	for _, rn := range s.runs {
+		if rn.isRunning() {
+			active++
+		}
+	}
+	if s.maxRuns > 0 && active >= s.maxRuns {
+		s.mu.Unlock()
+		return StartResponse{}, newOpError(errConflict, "Too many concurrent runs (max %d)", s.maxRuns)
+	}
+
+	rn := &run{
+		id:      history.NewRunID(time.Now()),
+		config:  config,
+		engine:  scenario.New(config),
+		bus:     events.NewBus(),
+		done:    make(chan struct{}),
+		running: true,
+	}
+	rn.engine.SetEventBus(rn.bus)
+	recorder := history.NewRecorder(rn.bus)
+	// 復旧イベントなどを取りこぼすと分析が狂うため、転送が追いつくまで待たせる
+	go rn.forwardEvents(rn.bus.SubscribeWith(events.SubscribeOptions{Policy: events.Block}), s.eventBus)
+
+	// 置き換えた既定の実行が終了済みであれば管理対象から外す
+	if prev := s.current; prev != nil && !prev.isRunning() {
+		delete(s.runs, prev.id)
+	}
+	s.runs[rn.id] = rn
+	s.current = rn
+	s.mu.Unlock()
+
+	// バックグラウンドで実行
+	go func() {
+		defer close(rn.done)
+
+		ctx := context.Background()
+		result, err := rn.engine.Run(ctx)
+		timeline := recorder.Stop()
+		rn.bus.Close()
+
+		if err != nil {
+			logger.Error("", "Scenario failed: %v", err)
+		} else {
+			logger.Info("", "Scenario completed: %d requests", result.TotalRequests)
+			record := &history.Run{ID: rn.id, Config: config, Result: result, Timeline: timeline}
+			if addErr := s.history.Add(record); addErr != nil {
+				logger.Warn("", "Failed to save run %s: %v", rn.id, addErr)
+			}
+		}
+
+		rn.finish(result)
+		s.releaseRun(rn)
+
+		s.broadcast(map[string]interface{}{
+			"type":   "scenario_complete",
+			"run_id": rn.id,
+			"result": result,
+		})
+	}()
+
+	return StartResponse{Status: "started", Scenario: config.Name, RunID: rn.id}, nil
+}
+
+// stopRun は実行中のシナリオを中断し、途中までの結果が確定するまで待つ
+func stopRun(ctx context.Context, rn *run) (StopResponse, error) {
+	if rn == nil || !rn.isRunning() {
+		// 既存クライアントとの互換のため、HTTPでは 400 を返す
+		return StopResponse{}, &opError{kind: errConflict, status: http.StatusBadRequest, msg: "No scenario running"}
+	}
+
+	rn.engine.Stop()
+
+	select {
+	case <-rn.done:
+	case <-time.After(stopTimeout):
+		return StopResponse{}, newOpError(errTimeout, "Timed out waiting for scenario to stop")
+	case <-ctx.Done():
+		return StopResponse{}, ctx.Err()
+	}
+
+	return StopResponse{Status: "stopped", RunID: rn.id, Result: rn.lastResult()}, nil
+}
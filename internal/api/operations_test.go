@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOpErrorStatus(t *testing.T) {
+	tests := []struct {
+		err      *opError
+		httpCode int
+		grpcCode codes.Code
+	}{
+		{newOpError(errInvalid, "bad"), http.StatusBadRequest, codes.InvalidArgument},
+		{newOpError(errNotFound, "missing"), http.StatusNotFound, codes.NotFound},
+		{newOpError(errConflict, "busy"), http.StatusConflict, codes.FailedPrecondition},
+		{newOpError(errTimeout, "slow"), http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{&opError{kind: errConflict, status: http.StatusBadRequest, msg: "idle"}, http.StatusBadRequest, codes.FailedPrecondition},
+	}
+
+	s := NewServer("")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.writeError(w, req, tt.err)
+		if w.Code != tt.httpCode {
+			t.Errorf("%s: expected HTTP %d, got %d", tt.err.msg, tt.httpCode, w.Code)
+		}
+		if code := status.Code(grpcError(tt.err)); code != tt.grpcCode {
+			t.Errorf("%s: expected gRPC %v, got %v", tt.err.msg, tt.grpcCode, code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	s.writeError(w, req, errors.New("boom"))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected HTTP 500 for unknown error, got %d", w.Code)
+	}
+	if code := status.Code(grpcError(context.Canceled)); code != codes.Canceled {
+		t.Errorf("expected Canceled, got %v", code)
+	}
+}
@@ -0,0 +1,403 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/worker"
+)
+
+// httpMetrics はHTTPサーバーのリクエストメトリクスを収集する
+type httpMetrics struct {
+	mu        sync.Mutex
+	requests  map[httpRequestKey]uint64
+	durations map[httpRouteKey]*durationSummary
+}
+
+// httpRequestKey はリクエスト数の集計キー
+type httpRequestKey struct {
+	method string
+	path   string
+	code   int
+}
+
+// httpRouteKey はレイテンシの集計キー
+type httpRouteKey struct {
+	method string
+	path   string
+}
+
+// durationSummary はレイテンシの合計と件数
+type durationSummary struct {
+	sum   time.Duration
+	count uint64
+}
+
+func newHTTPMetrics() *httpMetrics {
+	return &httpMetrics{
+		requests:  make(map[httpRequestKey]uint64),
+		durations: make(map[httpRouteKey]*durationSummary),
+	}
+}
+
+// observe はリクエストの結果を記録する
+func (m *httpMetrics) observe(method, path string, code int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[httpRequestKey{method: method, path: path, code: code}]++
+
+	rk := httpRouteKey{method: method, path: path}
+	ds, ok := m.durations[rk]
+	if !ok {
+		ds = &durationSummary{}
+		m.durations[rk] = ds
+	}
+	ds.sum += d
+	ds.count++
+}
+
+// middleware はリクエストごとにメトリクスを記録するハンドラーを返す
+// パスのラベルにはカーディナリティを抑えるためルーティングパターンを使用する
+func (m *httpMetrics) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		// パターンのメソッド部分（"GET /api/nodes" の "GET "）は method ラベルと重複するため除く
+		path := r.Pattern
+		if _, p, ok := strings.Cut(path, " "); ok {
+			path = p
+		}
+		if path == "" {
+			path = "unmatched"
+		}
+		m.observe(r.Method, path, rec.status, time.Since(start))
+	})
+}
+
+// statusRecorder はレスポンスのステータスコードを記録する
+// SSE と WebSocket のために Flusher と Hijacker を透過する
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// promWriter はPrometheusテキスト形式でメトリクスを書き出す
+type promWriter struct {
+	w io.Writer
+}
+
+// header はメトリクスの HELP と TYPE を書き出す
+func (p *promWriter) header(name, typ, help string) {
+	_, _ = fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample はサンプル値を書き出す（labels はキーと値の交互の並び）
+func (p *promWriter) sample(name string, value float64, labels ...string) {
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(labels) > 0 {
+		sb.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(labels[i])
+			sb.WriteString(`="`)
+			sb.WriteString(escapeLabelValue(labels[i+1]))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte('}')
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	sb.WriteByte('\n')
+	_, _ = io.WriteString(p.w, sb.String())
+}
+
+// metric は単一サンプルのメトリクスを書き出す
+func (p *promWriter) metric(name, typ, help string, value float64) {
+	p.header(name, typ, help)
+	p.sample(name, value)
+}
+
+// histogram はヒストグラムのバケット・合計・件数を書き出す
+func (p *promWriter) histogram(name, help string, s metrics.HistogramSnapshot) {
+	p.header(name, "histogram", help)
+	for i, bound := range s.Bounds {
+		p.sample(name+"_bucket", float64(s.Cumulative[i]), "le", strconv.FormatFloat(bound.Seconds(), 'g', -1, 64))
+	}
+	p.sample(name+"_bucket", float64(s.Count), "le", "+Inf")
+	p.sample(name+"_sum", s.Sum.Seconds())
+	p.sample(name+"_count", float64(s.Count))
+}
+
+// escapeLabelValue はラベル値をエスケープする
+func escapeLabelValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	return strings.ReplaceAll(v, `"`, `\"`)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// handlePrometheus はPrometheusテキスト形式でメトリクスを返す
+func (s *Server) handlePrometheus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p := &promWriter{w: w}
+
+	s.mu.RLock()
+	wsClients := len(s.wsClients)
+	sseClients := len(s.sseClients)
+	s.mu.RUnlock()
+
+	active := 0
+	for _, rn := range s.activeRuns() {
+		if rn.isRunning() {
+			active++
+		}
+	}
+
+	// シナリオ（個別の統計は既定の実行のもの）
+	rn := s.currentRun()
+	p.metric("chaoskvs_scenario_running", "gauge", "Whether a scenario is currently running.", boolToFloat(rn != nil && rn.isRunning()))
+	p.metric("chaoskvs_scenario_active_runs", "gauge", "Number of scenario runs currently in progress.", float64(active))
+
+	if rn != nil {
+		engine := rn.engine
+		// クライアントメトリクス
+		if m := engine.Metrics(); m != nil {
+			p.header("chaoskvs_client_requests_total", "counter", "Total client requests by result.")
+			p.sample("chaoskvs_client_requests_total", float64(m.SuccessRequests), "result", "success")
+			p.sample("chaoskvs_client_requests_total", float64(m.FailedRequests), "result", "failure")
+			p.metric("chaoskvs_client_rps", "gauge", "Current client requests per second.", m.RPS)
+			p.metric("chaoskvs_client_latency_avg_seconds", "gauge", "Average client request latency.", m.AverageLatency.Seconds())
+			p.metric("chaoskvs_client_latency_p99_seconds", "gauge", "P99 client request latency (sampled).", m.P99Latency.Seconds())
+			p.metric("chaoskvs_client_error_rate", "gauge", "Client request error rate (0-1).", m.ErrorRate)
+		}
+
+		// ワーカープール
+		if pm := engine.PoolMetrics(); pm != nil {
+			p.metric("chaoskvs_worker_pool_workers", "gauge", "Number of client worker goroutines.", float64(pm.Workers))
+			p.metric("chaoskvs_worker_pool_busy", "gauge", "Workers currently executing a job.", float64(pm.Busy))
+			p.metric("chaoskvs_worker_pool_utilization", "gauge", "Fraction of workers currently executing a job (0-1).", pm.Utilization())
+			p.header("chaoskvs_worker_pool_queue_depth", "gauge", "Jobs waiting in the worker pool queue by priority.")
+			for _, prio := range []worker.Priority{worker.PriorityHigh, worker.PriorityNormal, worker.PriorityLow} {
+				p.sample("chaoskvs_worker_pool_queue_depth", float64(pm.Queued[prio]), "priority", prio.String())
+			}
+			p.header("chaoskvs_worker_pool_jobs_total", "counter", "Submitted jobs by outcome.")
+			p.sample("chaoskvs_worker_pool_jobs_total", float64(pm.Accepted), "result", "accepted")
+			p.sample("chaoskvs_worker_pool_jobs_total", float64(pm.Rejected), "result", "rejected")
+			p.sample("chaoskvs_worker_pool_jobs_total", float64(pm.Dropped), "result", "dropped")
+			p.metric("chaoskvs_worker_pool_panics_total", "counter", "Jobs that panicked and were recovered.", float64(pm.Panics))
+			p.metric("chaoskvs_worker_pool_jobs_blocked_total", "counter", "Accepted jobs that waited for queue space.", float64(pm.Blocked))
+			p.histogram("chaoskvs_worker_pool_queue_seconds", "Time jobs spent queued before a worker picked them up.", pm.QueueTime)
+			p.histogram("chaoskvs_worker_pool_exec_seconds", "Time workers spent executing jobs.", pm.ExecTime)
+		}
+
+		// カオス統計
+		if cs := engine.ChaosStats(); cs != nil {
+			p.metric("chaoskvs_chaos_attacks_total", "counter", "Total chaos attacks executed.", float64(cs.TotalAttacks))
+			p.header("chaoskvs_chaos_attacks_by_type_total", "counter", "Chaos attacks by attack type.")
+			types := make([]string, 0, len(cs.ByType))
+			for t := range cs.ByType {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+			for _, t := range types {
+				p.sample("chaoskvs_chaos_attacks_by_type_total", float64(cs.ByType[t]), "type", t)
+			}
+		}
+
+		// 復旧統計
+		if rs := engine.RecoveryStats(); rs != nil {
+			p.metric("chaoskvs_recovery_attempts_total", "counter", "Total recovery attempts.", float64(rs.TotalRecoveries))
+			p.header("chaoskvs_recovery_results_total", "counter", "Recovery results by outcome.")
+			p.sample("chaoskvs_recovery_results_total", float64(rs.SuccessRecoveries), "result", "success")
+			p.sample("chaoskvs_recovery_results_total", float64(rs.FailedRecoveries), "result", "failed")
+			p.metric("chaoskvs_recovery_currently_failed", "gauge", "Nodes currently detected as failed.", float64(rs.CurrentlyFailed))
+		}
+
+		// ノード
+		if c := engine.Cluster(); c != nil {
+			nodes := c.Nodes()
+			sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+
+			counts := map[node.Status]int{}
+			for _, n := range nodes {
+				counts[n.Status()]++
+			}
+			p.header("chaoskvs_nodes", "gauge", "Number of nodes by status.")
+			for _, st := range []node.Status{node.StatusRunning, node.StatusSuspended, node.StatusReadOnly, node.StatusStopped} {
+				p.sample("chaoskvs_nodes", float64(counts[st]), "status", st.String())
+			}
+
+			p.header("chaoskvs_node_up", "gauge", "Whether the node is running (1) or not (0).")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_up", boolToFloat(n.Status() == node.StatusRunning), "node", n.ID())
+			}
+			p.header("chaoskvs_node_keys", "gauge", "Number of keys stored in the node.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_keys", float64(n.Size()), "node", n.ID())
+			}
+			p.header("chaoskvs_node_bytes", "gauge", "Total size of the keys and values stored in the node.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_bytes", float64(n.Bytes()), "node", n.ID())
+			}
+			p.header("chaoskvs_node_operations_total", "counter", "Operations the node accepted while running, by kind.")
+			for _, n := range nodes {
+				stats := n.Stats()
+				for _, op := range []struct {
+					kind  string
+					count uint64
+				}{{"get", stats.Gets}, {"set", stats.Sets}, {"delete", stats.Deletes}, {"scan", stats.Scans}} {
+					p.sample("chaoskvs_node_operations_total", float64(op.count), "node", n.ID(), "op", op.kind)
+				}
+			}
+			p.header("chaoskvs_node_get_hits_total", "counter", "Gets that found a value on the node.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_get_hits_total", float64(n.Stats().Hits), "node", n.ID())
+			}
+			p.header("chaoskvs_node_get_misses_total", "counter", "Gets that found no value on the node.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_get_misses_total", float64(n.Stats().Misses), "node", n.ID())
+			}
+			p.header("chaoskvs_node_rejected_total", "counter", "Operations rejected because the node was not running.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_rejected_total", float64(n.Stats().Rejected), "node", n.ID())
+			}
+			p.header("chaoskvs_node_memory_bytes", "gauge", "Estimated memory used by the data stored in the node.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_memory_bytes", float64(n.MemoryUsage()), "node", n.ID())
+			}
+			p.header("chaoskvs_node_evictions_total", "counter", "Keys evicted because the node exceeded its limits.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_evictions_total", float64(n.Stats().Evicted), "node", n.ID())
+			}
+			p.header("chaoskvs_node_lost_keys_total", "counter", "Keys lost because the node restarted without them in its durable state.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_lost_keys_total", float64(n.Stats().Lost), "node", n.ID())
+			}
+			p.header("chaoskvs_node_refused_total", "counter", "Key writes refused because the node would exceed its memory limit.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_refused_total", float64(n.Stats().Refused), "node", n.ID())
+			}
+			p.header("chaoskvs_node_corrupted_reads_total", "counter", "Reads answered with a deliberately corrupted value.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_corrupted_reads_total", float64(n.Stats().Corrupted), "node", n.ID())
+			}
+			p.header("chaoskvs_node_shed_total", "counter", "Operations shed because the node had too many in flight.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_shed_total", float64(n.Stats().Shed), "node", n.ID())
+			}
+			p.header("chaoskvs_node_delay_seconds", "gauge", "Injected response delay of the node.")
+			for _, n := range nodes {
+				p.sample("chaoskvs_node_delay_seconds", n.Delay().Seconds(), "node", n.ID())
+			}
+		}
+	}
+
+	// HTTPサーバー
+	p.metric("chaoskvs_http_websocket_clients", "gauge", "Connected WebSocket clients.", float64(wsClients))
+	p.metric("chaoskvs_http_sse_clients", "gauge", "Connected Server-Sent Events clients.", float64(sseClients))
+	p.metric("chaoskvs_event_subscribers", "gauge", "Active event bus subscribers.", float64(s.eventBus.SubscriberCount()))
+	p.metric("chaoskvs_events_dropped_total", "counter", "Events dropped because a subscriber fell behind.", float64(s.eventBus.TotalDropped()))
+	s.httpMetrics.write(p)
+}
+
+// write はHTTPメトリクスを書き出す
+func (m *httpMetrics) write(p *promWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reqKeys := make([]httpRequestKey, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		a, b := reqKeys[i], reqKeys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.code < b.code
+	})
+
+	p.header("chaoskvs_http_requests_total", "counter", "Total HTTP requests by method, route and status code.")
+	for _, k := range reqKeys {
+		p.sample("chaoskvs_http_requests_total", float64(m.requests[k]),
+			"method", k.method, "path", k.path, "code", strconv.Itoa(k.code))
+	}
+
+	routeKeys := make([]httpRouteKey, 0, len(m.durations))
+	for k := range m.durations {
+		routeKeys = append(routeKeys, k)
+	}
+	sort.Slice(routeKeys, func(i, j int) bool {
+		a, b := routeKeys[i], routeKeys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return a.method < b.method
+	})
+
+	p.header("chaoskvs_http_request_duration_seconds", "summary", "HTTP request duration by method and route.")
+	for _, k := range routeKeys {
+		ds := m.durations[k]
+		p.sample("chaoskvs_http_request_duration_seconds_sum", ds.sum.Seconds(), "method", k.method, "path", k.path)
+		p.sample("chaoskvs_http_request_duration_seconds_count", float64(ds.count), "method", k.method, "path", k.path)
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	chaoskvsv1 "github.com/nyasuto/chaos-kvs/proto/chaoskvs/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	if newRateLimiter(0, 5) != nil {
+		t.Error("expected nil limiter when rate is 0")
+	}
+
+	now := time.Unix(0, 0)
+	l := newRateLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.allow("a"); !ok {
+			t.Fatalf("expected request %d within burst to be allowed", i+1)
+		}
+	}
+	ok, wait := l.allow("a")
+	if ok {
+		t.Fatal("expected request beyond burst to be rejected")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("expected wait 500ms, got %v", wait)
+	}
+
+	// 別クライアントは独立して制限される
+	if ok, _ := l.allow("b"); !ok {
+		t.Error("expected other client to be allowed")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.allow("a"); !ok {
+		t.Error("expected request to be allowed after refill")
+	}
+	if ok, _ := l.allow("a"); ok {
+		t.Error("expected bucket to be empty again")
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(1, 1)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < maxLimiterClients; i++ {
+		l.allow(fmt.Sprintf("client-%d", i))
+	}
+	now = now.Add(time.Second)
+	l.allow("new")
+	if len(l.buckets) != 1 {
+		t.Errorf("expected refilled buckets to be pruned, got %d", len(l.buckets))
+	}
+}
+
+func TestRateLimitHTTP(t *testing.T) {
+	config := DefaultConfig()
+	config.RateLimit = 0.01
+	config.RateBurst = 1
+	_, ts := newTestServerWithConfig(t, config)
+
+	post := func(path string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(ts.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	if resp := post("/api/nodes/node-1/kill"); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected first request to reach handler (409), got %d", resp.StatusCode)
+	}
+	resp := post("/api/scenario/start")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+
+	// 停止と参照系は制限しない
+	if resp := post("/api/scenario/stop"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected stop to be unlimited (400), got %d", resp.StatusCode)
+	}
+}
+
+func TestRateLimitGRPC(t *testing.T) {
+	config := DefaultConfig()
+	config.RateLimit = 0.01
+	config.RateBurst = 1
+	s, err := NewServerWithConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	client := newTestGRPCClient(t, s)
+	ctx := context.Background()
+
+	req := &chaoskvsv1.NodeActionRequest{NodeId: "node-1", Action: chaoskvsv1.NodeActionRequest_ACTION_KILL}
+	if _, err := client.NodeAction(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected first request to reach handler, got %v", err)
+	}
+	if _, err := client.NodeAction(ctx, req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+	if _, err := client.GetStatus(ctx, &chaoskvsv1.GetStatusRequest{}); err != nil {
+		t.Errorf("expected GetStatus to be unlimited, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+// requestIDHeader はリクエストIDを受け渡すヘッダー
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen は受け入れるクライアント指定のリクエストIDの最大長
+const maxRequestIDLen = 64
+
+// requestIDKey はコンテキストにリクエストIDを格納するキー
+type requestIDKey struct{}
+
+// requestIDFrom はコンテキストのリクエストIDを返す（未設定の場合は空文字列）
+func requestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID はランダムなリクエストIDを生成する
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID はクライアント指定のリクエストIDをログに出力してよいか判定する
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestLogger はリクエストIDを付与し、リクエストごとにアクセスログを出力する
+// プロキシが X-Request-ID を付与している場合はその値を引き継ぐ
+func requestLogger(log *logger.Logger, next http.Handler) http.Handler {
+	log = log.With("http")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// クエリにはトークンが含まれる場合があるためパスのみを記録する
+		elapsed := time.Since(start).Round(time.Microsecond)
+		level := logger.LevelInfo
+		switch {
+		case rec.status >= http.StatusInternalServerError:
+			level = logger.LevelError
+		case rec.status >= http.StatusBadRequest:
+			level = logger.LevelWarn
+		}
+		log.Log(level, "", "%s %s %d %v id=%s remote=%s",
+			r.Method, r.URL.Path, rec.status, elapsed, id, r.RemoteAddr)
+	})
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	var seen string
+	h := requestLogger(logger.New(&buf, logger.LevelInfo), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestIDFrom(r.Context())
+		http.Error(w, "Node x not found", http.StatusNotFound)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/nodes/x/kill?token=secret", nil))
+
+	id := w.Header().Get(requestIDHeader)
+	if len(id) != 16 {
+		t.Fatalf("expected generated 16-char request id, got %q", id)
+	}
+	if seen != id {
+		t.Errorf("expected handler to see request id %q, got %q", id, seen)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"[WARN]", "POST /api/nodes/x/kill 404", "id=" + id} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log to contain %q, got %q", want, out)
+		}
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("expected query string not to be logged, got %q", out)
+	}
+}
+
+func TestRequestLoggerPropagatesID(t *testing.T) {
+	h := requestLogger(logger.New(io.Discard, logger.LevelInfo), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		in   string
+		keep bool
+	}{
+		{"proxy-abc_123", true},
+		{"has space", false},
+		{strings.Repeat("a", maxRequestIDLen+1), false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+		req.Header.Set(requestIDHeader, tt.in)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		if got := w.Header().Get(requestIDHeader); (got == tt.in) != tt.keep {
+			t.Errorf("request id %q: keep=%v, got %q", tt.in, tt.keep, got)
+		}
+	}
+}
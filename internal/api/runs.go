@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// maxNodeIncidents はノードごとに保持する直近のイベント数
+const maxNodeIncidents = 20
+
+// run はサーバーが管理するシナリオ実行1件
+// 各実行は独立したエンジン・クラスタ・イベントバスを持つ
+type run struct {
+	id     string
+	config scenario.Config
+	engine *scenario.Engine
+	bus    *events.Bus   // この実行のイベント（サーバーのバスへ run_id 付きで転送する）
+	done   chan struct{} // 実行終了時に閉じる
+
+	scaleMu sync.Mutex // ノードの追加・削除を直列化する
+
+	mu        sync.RWMutex
+	running   bool
+	result    *scenario.Result
+	incidents map[string][]events.Event // ノードごとの直近のイベント（古い順）
+}
+
+// isRunning は実行中かどうかを返す
+func (rn *run) isRunning() bool {
+	rn.mu.RLock()
+	defer rn.mu.RUnlock()
+	return rn.running
+}
+
+// finish は実行結果を記録し、実行中の状態を解除する
+func (rn *run) finish(result *scenario.Result) {
+	rn.mu.Lock()
+	rn.running = false
+	rn.result = result
+	rn.mu.Unlock()
+}
+
+// lastResult は実行結果を返す（実行中の場合はnil）
+func (rn *run) lastResult() *scenario.Result {
+	rn.mu.RLock()
+	defer rn.mu.RUnlock()
+	return rn.result
+}
+
+// cluster は実行のクラスタを返す（セットアップ前はnil）
+func (rn *run) cluster() *cluster.Cluster {
+	return rn.engine.Cluster()
+}
+
+// metrics はクライアントメトリクスを返す（セットアップ前はnil）
+func (rn *run) metrics() *metrics.Snapshot {
+	return rn.engine.Metrics()
+}
+
+// status は実行の状態を返す
+func (rn *run) status() StatusResponse {
+	resp := StatusResponse{
+		RunID:        rn.id,
+		Running:      rn.isRunning(),
+		ScenarioName: rn.config.Name,
+	}
+	if c := rn.cluster(); c != nil {
+		fillNodeCounts(&resp, c)
+	}
+	return resp
+}
+
+// recordIncident はノードのイベントを直近 maxNodeIncidents 件まで保持する
+func (rn *run) recordIncident(event events.Event) {
+	if event.NodeID == "" {
+		return
+	}
+
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	if rn.incidents == nil {
+		rn.incidents = make(map[string][]events.Event)
+	}
+	list := append(rn.incidents[event.NodeID], event)
+	if len(list) > maxNodeIncidents {
+		list = list[len(list)-maxNodeIncidents:]
+	}
+	rn.incidents[event.NodeID] = list
+}
+
+// nodeIncidents はノードの直近のイベントを古い順に返す
+func (rn *run) nodeIncidents(nodeID string) []events.Event {
+	rn.mu.RLock()
+	defer rn.mu.RUnlock()
+	return append([]events.Event{}, rn.incidents[nodeID]...)
+}
+
+// forwardEvents は実行のイベントに run_id を付けてサーバーのバスへ転送する
+// 実行のバスが閉じられると終了する
+func (rn *run) forwardEvents(ch <-chan events.Event, to *events.Bus) {
+	for event := range ch {
+		event.RunID = rn.id
+		rn.recordIncident(event)
+		to.Publish(event)
+	}
+}
+
+// currentRun は既定の実行（最後に開始した実行）を返す（未実行の場合はnil）
+// run_id を指定しない既存のエンドポイントはこの実行を対象にする
+func (s *Server) currentRun() *run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// lookupRun は実行中または既定の実行をIDで取得する
+func (s *Server) lookupRun(id string) (*run, error) {
+	s.mu.RLock()
+	rn, ok := s.runs[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, newOpError(errNotFound, "Run %s not found", id)
+	}
+	return rn, nil
+}
+
+// activeRuns は実行中の実行と既定の実行をID順に返す
+func (s *Server) activeRuns() []*run {
+	s.mu.RLock()
+	runs := make([]*run, 0, len(s.runs))
+	for _, rn := range s.runs {
+		runs = append(runs, rn)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].id < runs[j].id })
+	return runs
+}
+
+// releaseRun は終了した実行を管理対象から外す（既定の実行は残す）
+func (s *Server) releaseRun(rn *run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != rn {
+		delete(s.runs, rn.id)
+	}
+}
+
+// withCurrentRun は既定の実行（未実行の場合はnil）をハンドラーに渡す
+func (s *Server) withCurrentRun(h func(http.ResponseWriter, *http.Request, *run)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h(w, r, s.currentRun())
+	}
+}
+
+// withRun はパスの {id} で指定された実行をハンドラーに渡す
+func (s *Server) withRun(h func(http.ResponseWriter, *http.Request, *run)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rn, err := s.lookupRun(r.PathValue("id"))
+		if err != nil {
+			s.writeError(w, r, err)
+			return
+		}
+		h(w, r, rn)
+	}
+}
+
+// handleRunStart は他の実行と並行して新しい実行を開始する
+func (s *Server) handleRunStart(w http.ResponseWriter, r *http.Request) {
+	var req ScenarioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.startScenario(req, false)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, resp)
+}
+
+// handleActiveRuns は実行中の実行と既定の実行の状態を返す
+func (s *Server) handleActiveRuns(w http.ResponseWriter, r *http.Request) {
+	runs := s.activeRuns()
+	resp := make([]StatusResponse, 0, len(runs))
+	for _, rn := range runs {
+		resp = append(resp, rn.status())
+	}
+	s.writeJSON(w, resp)
+}
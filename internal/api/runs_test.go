@@ -0,0 +1,239 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startRun は POST /api/runs で実行を開始し、ノードが作成されるまで待機する
+func startRun(t *testing.T, s *Server, ts *httptest.Server, body string) string {
+	t.Helper()
+
+	resp, err := http.Post(ts.URL+"/api/runs", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to start run: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var start StartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&start); err != nil {
+		t.Fatalf("failed to decode start response: %v", err)
+	}
+
+	rn, err := s.lookupRun(start.RunID)
+	if err != nil {
+		t.Fatalf("run not registered: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if c := rn.cluster(); c != nil && c.RunningCount() > 0 {
+			return start.RunID
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for run setup")
+	return ""
+}
+
+func TestConcurrentRuns(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	first := startRun(t, s, ts, `{"preset":"basic","duration":"30s","nodes":2}`)
+	second := startRun(t, s, ts, `{"preset":"basic","duration":"30s","nodes":3}`)
+	if first == second {
+		t.Fatalf("expected distinct run IDs, got %s twice", first)
+	}
+
+	resp, err := http.Get(ts.URL + "/api/runs/active")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var active []StatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&active); err != nil {
+		t.Fatalf("failed to decode active runs: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(active) != 2 {
+		t.Fatalf("expected 2 active runs, got %d", len(active))
+	}
+
+	// 実行ごとに独立したクラスタを持つ
+	resp, err = http.Get(ts.URL + "/api/runs/" + first + "/status")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var status StatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	_ = resp.Body.Close()
+	if status.RunID != first || status.NodeCount != 2 || !status.Running {
+		t.Errorf("unexpected status for first run: %+v", status)
+	}
+
+	// 既存のエンドポイントは最後に開始した実行を対象にする
+	resp, err = http.Get(ts.URL + "/api/status")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	_ = resp.Body.Close()
+	if status.RunID != second || status.NodeCount != 3 {
+		t.Errorf("expected legacy status to target latest run, got %+v", status)
+	}
+
+	// 既存の開始エンドポイントは既定の実行が実行中なら競合する
+	resp, err = http.Post(ts.URL+"/api/scenario/start", "application/json", strings.NewReader(`{"preset":"basic"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 for legacy start, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(ts.URL+"/api/runs/"+first+"/nodes/node-1/kill", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var info NodeInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode node: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || info.Status != "stopped" {
+		t.Fatalf("unexpected run node action response: %d %+v", resp.StatusCode, info)
+	}
+
+	resp, err = http.Post(ts.URL+"/api/runs/"+first+"/stop", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var stop StopResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stop); err != nil {
+		t.Fatalf("failed to decode stop response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if stop.RunID != first || stop.Result == nil {
+		t.Errorf("unexpected stop response: %+v", stop)
+	}
+
+	// 終了した実行は既定の実行でなければ管理対象から外れる
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := s.lookupRun(first); err != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	resp, err = http.Get(ts.URL + "/api/runs/" + first + "/status")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for finished run, got %d", resp.StatusCode)
+	}
+
+	stopScenario(t, ts)
+}
+
+func TestConcurrentRunsLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxConcurrentRuns = 1
+	s, ts := newTestServerWithConfig(t, config)
+
+	startRun(t, s, ts, `{"preset":"basic","duration":"30s","nodes":2}`)
+	defer stopScenario(t, ts)
+
+	resp, err := http.Post(ts.URL+"/api/runs", "application/json", strings.NewReader(`{"preset":"basic"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 over the limit, got %d", resp.StatusCode)
+	}
+}
+
+func TestRunNotFound(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	for _, path := range []string{"/api/runs/missing/status", "/api/runs/missing/nodes", "/api/runs/missing/metrics"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected status 404, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	get := func(path string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	id := startRun(t, s, ts, `{"scenario":{"duration":"30s","node_count":2,"chaos":{"enabled":false}}}`)
+	if resp, _ := get("/api/runs/" + id + "/report"); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 while running, got %d", resp.StatusCode)
+	}
+
+	resp, err := http.Post(ts.URL+"/api/runs/"+id+"/stop", "application/json", nil)
+	if err != nil {
+		t.Fatalf("failed to stop run: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	tests := []struct {
+		format      string
+		contentType string
+		contains    string
+	}{
+		{"", "text/plain", "SCENARIO REPORT"},
+		{"json", "application/json", `"TotalRequests"`},
+		{"md", "text/markdown", "## Traffic Metrics"},
+		{"html", "text/html", "<h2>Final Node Status</h2>"},
+	}
+	for _, tt := range tests {
+		resp, body := get("/api/runs/" + id + "/report?format=" + tt.format)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%q: expected status 200, got %d", tt.format, resp.StatusCode)
+			continue
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+			t.Errorf("%q: expected content type %s, got %s", tt.format, tt.contentType, ct)
+		}
+		if !strings.Contains(body, tt.contains) {
+			t.Errorf("%q: expected report to contain %q", tt.format, tt.contains)
+		}
+	}
+
+	if resp, _ := get("/api/runs/" + id + "/report?format=pdf"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown format, got %d", resp.StatusCode)
+	}
+	if resp, _ := get("/api/runs/missing/report"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown run, got %d", resp.StatusCode)
+	}
+}
@@ -6,166 +6,546 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
-	"chaos-kvs/internal/cluster"
-	"chaos-kvs/internal/events"
-	"chaos-kvs/internal/logger"
-	"chaos-kvs/internal/scenario"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
 
 	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
 )
 
 //go:embed static/*
 var staticFiles embed.FS
 
+// Config はAPIサーバーの設定
+type Config struct {
+	Addr       string // リッスンアドレス
+	GRPCAddr   string // gRPCのリッスンアドレス（空でgRPCを無効化）
+	HistoryDir string // 実行履歴の保存先（空でメモリのみ）
+	HistoryDB  string // 実行履歴を保存する SQLite データベース（空で使用しない、HistoryDir とは併用不可）
+	MaxRuns    int    // 保持する実行履歴の最大数
+
+	// リバースプロキシ・別ホストのダッシュボード向け
+	BasePath    string   // UIとAPIを配置するパスの接頭辞（例: /chaos、空でルート）
+	CORSOrigins []string // クロスオリジンアクセスを許可するオリジン（"*" で全て、空で無効）
+
+	LogRequests bool           // リクエストごとのアクセスログを出力する
+	LogBuffer   *logger.Buffer // GET /api/logs・WebSocketで配信する直近のログ（nilで無効）
+
+	EventHistorySize int // GET /api/events で参照できる直近のイベント数（0で無効）
+
+	MaxConcurrentRuns int // 同時に実行できるシナリオの最大数
+
+	// イベントをPOSTする外部のWebhook（空で無効）
+	WebhookURL       string
+	WebhookBatchSize int // 1リクエストにまとめるイベント数（1以下で1件ずつ）
+
+	// 起動時に再生して配信する記録済みのイベント（UIの確認・開発用）
+	Replay      []events.Event
+	ReplaySpeed float64 // 再生速度（1で記録時と同じ間隔、0以下で待機なし）
+
+	// シナリオ開始・障害注入のレート制限（クライアントごと、RateLimit が0で無効）
+	RateLimit float64 // 1秒あたりの許可数
+	RateBurst int     // 連続して許可する最大数
+
+	// 認証（空の場合はそのロールの保護を行わない）
+	ReadToken     string // 参照系エンドポイント用トークン
+	OperatorToken string // シナリオ操作・障害注入用トークン（参照系も利用可能）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Addr:        ":8080",
+		HistoryDir:  "",
+		MaxRuns:     history.DefaultConfig().MaxRuns,
+		LogRequests: true,
+		LogBuffer:   logger.Recent,
+
+		EventHistorySize: events.DefaultHistorySize,
+
+		MaxConcurrentRuns: 4,
+	}
+}
+
 // Server はAPIサーバー
 type Server struct {
-	addr     string
-	cluster  *cluster.Cluster
-	engine   *scenario.Engine
-	config   scenario.Config
-	eventBus *events.Bus
-
-	mu        sync.RWMutex
-	running   bool
-	wsClients map[*websocket.Conn]bool
+	addr        string
+	grpcAddr    string
+	maxRuns     int // 同時実行数の上限
+	eventBus    *events.Bus
+	httpMetrics *httpMetrics
+	history     *history.Store
+	auth        *authenticator
+	basePath    string
+	cors        *corsPolicy
+	logRequests bool
+	logs        *logger.Buffer
+	limiter     *rateLimiter
+	replay      []events.Event
+	replaySpeed float64
+	webhook     events.WebhookConfig
+	forwardOnce sync.Once
+
+	mu         sync.RWMutex
+	runs       map[string]*run // 実行中の実行と既定の実行
+	current    *run            // 既定の実行（最後に開始した実行）
+	wsClients  map[*wsClient]struct{}
+	sseClients map[chan sseMessage]struct{}
+	closing    bool          // シャットダウン中（新規のストリーム接続を受け付けない）
+	shutdown   chan struct{} // シャットダウン開始時に閉じる（gRPCストリームの終了用）
 
 	server *http.Server
 }
 
-// NewServer は新しいAPIサーバーを作成する
+// NewServer は新しいAPIサーバーを作成する（実行履歴はメモリのみに保持）
 func NewServer(addr string) *Server {
+	config := DefaultConfig()
+	config.Addr = addr
+	s, _ := NewServerWithConfig(config) // HistoryDir が空の場合は失敗しない
+	return s
+}
+
+// NewServerWithConfig は設定を指定してAPIサーバーを作成する
+func NewServerWithConfig(config Config) (*Server, error) {
+	store, err := history.NewStore(history.Config{
+		Dir:      config.HistoryDir,
+		Database: config.HistoryDB,
+		MaxRuns:  config.MaxRuns,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventBus := events.NewBus()
+	if config.EventHistorySize > 0 {
+		eventBus.SetHistory(events.NewHistory(config.EventHistorySize))
+	}
+
 	return &Server{
-		addr:      addr,
-		wsClients: make(map[*websocket.Conn]bool),
-		eventBus:  events.NewBus(),
+		addr:        config.Addr,
+		grpcAddr:    config.GRPCAddr,
+		maxRuns:     config.MaxConcurrentRuns,
+		runs:        make(map[string]*run),
+		wsClients:   make(map[*wsClient]struct{}),
+		sseClients:  make(map[chan sseMessage]struct{}),
+		shutdown:    make(chan struct{}),
+		eventBus:    eventBus,
+		httpMetrics: newHTTPMetrics(),
+		history:     store,
+		auth: &authenticator{
+			readToken:     config.ReadToken,
+			operatorToken: config.OperatorToken,
+		},
+		basePath:    normalizeBasePath(config.BasePath),
+		cors:        newCORSPolicy(config.CORSOrigins),
+		logRequests: config.LogRequests,
+		logs:        config.LogBuffer,
+		limiter:     newRateLimiter(config.RateLimit, config.RateBurst),
+		replay:      config.Replay,
+		replaySpeed: config.ReplaySpeed,
+		webhook:     webhookConfig(config),
+	}, nil
+}
+
+// webhookConfig はWebhookの送信設定を返す（URLが空の場合は無効）
+func webhookConfig(config Config) events.WebhookConfig {
+	if config.WebhookURL == "" {
+		return events.WebhookConfig{}
+	}
+	wc := events.DefaultWebhookConfig(config.WebhookURL)
+	if config.WebhookBatchSize > 1 {
+		wc.BatchSize = config.WebhookBatchSize
 	}
+	return wc
 }
 
-// Start はサーバーを開始する
-func (s *Server) Start(ctx context.Context) error {
-	mux := http.NewServeMux()
+// route はAPIエンドポイントの定義
+type route struct {
+	pattern string // ServeMuxのパターン（"METHOD /path"）
+	role    Role   // 必要なロール
+	handler http.HandlerFunc
+}
 
-	// API routes
-	mux.HandleFunc("/api/status", s.handleStatus)
-	mux.HandleFunc("/api/nodes", s.handleNodes)
-	mux.HandleFunc("/api/metrics", s.handleMetrics)
-	mux.HandleFunc("/api/scenario/start", s.handleScenarioStart)
-	mux.HandleFunc("/api/scenario/stop", s.handleScenarioStop)
-	mux.HandleFunc("/api/presets", s.handlePresets)
+// routes は全てのAPIエンドポイントを返す
+// openapi.yaml はこの一覧と一致している必要がある（テストで検証）
+func (s *Server) routes() []route {
+	return []route{
+		// 既定の実行（最後に開始した実行）が対象
+		{"GET /api/status", RoleReader, s.withCurrentRun(s.handleStatus)},
+		{"GET /api/nodes", RoleReader, s.withCurrentRun(s.handleNodes)},
+		{"GET /api/nodes/{node}", RoleReader, s.withCurrentRun(s.handleNode)},
+		{"POST /api/nodes/{node}/{action}", RoleOperator, s.limit(s.withCurrentRun(s.handleNodeAction))},
+		{"GET /api/metrics", RoleReader, s.withCurrentRun(s.handleMetrics)},
+		{"POST /api/cluster/scale", RoleOperator, s.limit(s.withCurrentRun(s.handleClusterScale))},
+		{"POST /api/cluster/rebalance", RoleOperator, s.limit(s.withCurrentRun(s.handleClusterRebalance))},
+		{"POST /api/cluster/nodes", RoleOperator, s.limit(s.withCurrentRun(s.handleClusterAddNode))},
+		{"DELETE /api/cluster/nodes/{node}", RoleOperator, s.limit(s.withCurrentRun(s.handleClusterRemoveNode))},
+		{"GET /api/cluster/snapshot", RoleReader, s.withCurrentRun(s.handleSnapshotDownload)},
+		{"POST /api/cluster/snapshot", RoleOperator, s.limit(s.withCurrentRun(s.handleSnapshotUpload))},
+		{"POST /api/scenario/start", RoleOperator, s.limit(s.handleScenarioStart)},
+		{"POST /api/scenario/stop", RoleOperator, s.withCurrentRun(s.handleScenarioStop)},
+		{"GET /api/presets", RoleReader, s.handlePresets},
+		{"GET /api/logs", RoleReader, s.handleLogs},
+		{"GET /api/events", RoleReader, s.handleEvents},
+
+		// 実行履歴と、run_id を指定した並行実行の操作
+		{"GET /api/runs", RoleReader, s.handleRuns},
+		{"POST /api/runs", RoleOperator, s.limit(s.handleRunStart)},
+		{"GET /api/runs/active", RoleReader, s.handleActiveRuns},
+		{"GET /api/runs/{id}", RoleReader, s.handleRun},
+		{"GET /api/runs/{id}/report", RoleReader, s.handleRunReport},
+		{"GET /api/runs/{id}/status", RoleReader, s.withRun(s.handleStatus)},
+		{"GET /api/runs/{id}/nodes", RoleReader, s.withRun(s.handleNodes)},
+		{"GET /api/runs/{id}/nodes/{node}", RoleReader, s.withRun(s.handleNode)},
+		{"POST /api/runs/{id}/nodes/{node}/{action}", RoleOperator, s.limit(s.withRun(s.handleNodeAction))},
+		{"POST /api/runs/{id}/cluster/scale", RoleOperator, s.limit(s.withRun(s.handleClusterScale))},
+		{"POST /api/runs/{id}/cluster/rebalance", RoleOperator, s.limit(s.withRun(s.handleClusterRebalance))},
+		{"POST /api/runs/{id}/cluster/nodes", RoleOperator, s.limit(s.withRun(s.handleClusterAddNode))},
+		{"DELETE /api/runs/{id}/cluster/nodes/{node}", RoleOperator, s.limit(s.withRun(s.handleClusterRemoveNode))},
+		{"GET /api/runs/{id}/cluster/snapshot", RoleReader, s.withRun(s.handleSnapshotDownload)},
+		{"POST /api/runs/{id}/cluster/snapshot", RoleOperator, s.limit(s.withRun(s.handleSnapshotUpload))},
+		{"GET /api/runs/{id}/metrics", RoleReader, s.withRun(s.handleMetrics)},
+		{"POST /api/runs/{id}/stop", RoleOperator, s.withRun(s.handleScenarioStop)},
+		{"GET /api/auth", RoleNone, s.handleAuth},
+		{"GET /api/openapi.yaml", RoleNone, s.handleOpenAPI},
+
+		// Prometheus
+		{"GET /metrics", RoleReader, s.handlePrometheus},
+
+		// Grafana JSON データソース（URLに /api/grafana を設定する）
+		{"GET /api/grafana/", RoleReader, s.handleGrafanaHealth},
+		{"POST /api/grafana/search", RoleReader, s.handleGrafanaSearch},
+		{"POST /api/grafana/metrics", RoleReader, s.handleGrafanaMetrics},
+		{"POST /api/grafana/query", RoleReader, s.handleGrafanaQuery},
+		{"POST /api/grafana/annotations", RoleReader, s.handleGrafanaAnnotations},
+
+		// WebSocket / Server-Sent Events
+		// ブラウザはヘッダーを設定できないため ?token= でも認証できる
+		{"GET /ws", RoleReader, websocket.Handler(s.handleWebSocket).ServeHTTP},
+		{"GET /api/stream", RoleReader, s.handleStream},
+	}
+}
 
-	// WebSocket
-	mux.Handle("/ws", websocket.Handler(s.handleWebSocket))
+// Handler はAPIルーティングを設定したハンドラーを返す
+func (s *Server) Handler() (http.Handler, error) {
+	mux := http.NewServeMux()
+
+	for _, rt := range s.routes() {
+		mux.HandleFunc(rt.pattern, s.auth.require(rt.role, rt.handler))
+	}
 
 	// Static files
 	staticFS, err := fs.Sub(staticFiles, "static")
 	if err != nil {
-		return fmt.Errorf("failed to get static files: %w", err)
+		return nil, fmt.Errorf("failed to get static files: %w", err)
 	}
 	mux.Handle("/", http.FileServer(http.FS(staticFS)))
 
+	// イベントバスの購読は1つに集約し、全クライアントへブロードキャストする
+	// ログも同様に1つの購読をログを要求したクライアントへ配信する
+	// ライブ表示では最新のイベントを優先し、配信が遅れた場合は古いものから捨てる
+	s.forwardOnce.Do(func() {
+		go s.forwardEvents(s.eventBus.SubscribeWith(events.SubscribeOptions{Policy: events.DropOldest}))
+		if s.logs != nil {
+			entryCh, cancel := s.logs.Subscribe(wsBufferSize)
+			go s.forwardLogs(entryCh)
+			go func() {
+				<-s.shutdown
+				cancel()
+			}()
+		}
+	})
+
+	// メトリクスのパスラベルはベースパスを除いたパターンで記録する
+	handler := mountBasePath(s.basePath, s.httpMetrics.middleware(mux))
+	if s.cors != nil {
+		handler = s.cors.middleware(handler)
+	}
+	if s.logRequests {
+		accessLogger.SetOutput(logger.Default.Output())
+		accessLogger.SetFormat(logger.Default.Format())
+		handler = requestLogger(accessLogger, handler)
+	}
+	return handler, nil
+}
+
+// Start はサーバーを開始する
+func (s *Server) Start(ctx context.Context) error {
+	handler, err := s.Handler()
+	if err != nil {
+		return err
+	}
+
 	s.server = &http.Server{
 		Addr:    s.addr,
-		Handler: mux,
+		Handler: handler,
+	}
+
+	var grpcServer *grpc.Server
+	if s.grpcAddr != "" {
+		lis, err := net.Listen("tcp", s.grpcAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", s.grpcAddr, err)
+		}
+		grpcServer = s.GRPCServer()
+		logger.Info("", "gRPC Server starting on %s", lis.Addr())
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.Error("", "gRPC server error: %v", err)
+			}
+		}()
+	}
+
+	var webhook *events.WebhookSink
+	if s.webhook.URL != "" {
+		if webhook, err = events.NewWebhookSink(s.eventBus, s.webhook); err != nil {
+			return err
+		}
+		logger.Info("", "Forwarding events to webhook %s", s.webhook.URL)
 	}
 
 	// バックグラウンドでメトリクス配信
 	go s.broadcastLoop(ctx)
 
-	logger.Info("", "API Server starting on http://%s", s.addr)
+	if len(s.replay) > 0 {
+		go s.replayEvents(ctx)
+	}
+
+	logger.Info("", "API Server starting on http://%s%s/", s.addr, s.basePath)
 
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		<-ctx.Done()
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
+		// Shutdown はハイジャックされたWebSocket接続や継続中のSSEを待たないため先に閉じる
+		s.closeClients(shutdownCtx)
+		if grpcServer != nil {
+			stopGRPC(shutdownCtx, grpcServer)
+		}
 		_ = s.server.Shutdown(shutdownCtx)
+		s.eventBus.Close()
+		// 送信待ちのイベントを送り切る
+		if webhook != nil {
+			if err := webhook.Close(shutdownCtx); err != nil {
+				logger.Warn("", "Webhook shutdown: %v", err)
+			}
+		}
 	}()
 
 	if err := s.server.ListenAndServe(); err != http.ErrServerClosed {
 		return err
 	}
-	return nil
+	<-stopped
+	return s.history.Close()
+}
+
+// stopGRPC は処理中のRPCの完了を待ってgRPCサーバーを停止する
+// ストリーミングRPCはクライアントが切断するまで終わらないため、期限を過ぎたら強制停止する
+func stopGRPC(ctx context.Context, gs *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		gs.Stop()
+	}
 }
 
 // StatusResponse はステータスレスポンス
 type StatusResponse struct {
+	RunID          string `json:"run_id,omitempty"`
 	Running        bool   `json:"running"`
 	ScenarioName   string `json:"scenario_name,omitempty"`
 	NodeCount      int    `json:"node_count"`
 	RunningNodes   int    `json:"running_nodes"`
 	StoppedNodes   int    `json:"stopped_nodes"`
 	SuspendedNodes int    `json:"suspended_nodes"`
+	ReadOnlyNodes  int    `json:"readonly_nodes"`
 }
 
-func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request, rn *run) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	resp := StatusResponse{
-		Running: s.running,
-	}
+	s.writeJSON(w, runStatus(rn))
+}
 
-	if s.config.Name != "" {
-		resp.ScenarioName = s.config.Name
+// currentCluster は既定の実行のクラスタを返す（未実行の場合はnil）
+func (s *Server) currentCluster() *cluster.Cluster {
+	if rn := s.currentRun(); rn != nil {
+		return rn.cluster()
 	}
+	return nil
+}
 
-	if s.cluster != nil {
-		resp.NodeCount = s.cluster.Size()
-		resp.RunningNodes = s.cluster.RunningCount()
-		for _, n := range s.cluster.Nodes() {
-			switch n.Status().String() {
-			case "Stopped":
-				resp.StoppedNodes++
-			case "Suspended":
-				resp.SuspendedNodes++
-			}
+// fillNodeCounts はクラスタのノード状態別の数を設定する
+func fillNodeCounts(resp *StatusResponse, c *cluster.Cluster) {
+	resp.NodeCount = c.Size()
+	resp.RunningNodes = c.RunningCount()
+	for _, n := range c.Nodes() {
+		switch n.Status() {
+		case node.StatusStopped:
+			resp.StoppedNodes++
+		case node.StatusSuspended:
+			resp.SuspendedNodes++
+		case node.StatusReadOnly:
+			resp.ReadOnlyNodes++
 		}
 	}
-
-	s.writeJSON(w, resp)
 }
 
 // NodeInfo はノード情報
 type NodeInfo struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
-	Size   int    `json:"size"`
-	Delay  string `json:"delay,omitempty"`
+	ID     string            `json:"id"`
+	Status string            `json:"status"`
+	Size   int               `json:"size"`
+	Delay  string            `json:"delay,omitempty"`
+	Zone   string            `json:"zone,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	MemoryUsage  int64   `json:"memory_usage"` // 保持するデータのメモリ使用量の概算（バイト）
+	OpsPerSecond float64 `json:"ops_per_sec"`  // 直近の秒間の読み書きの操作数（偏ったノードの把握用）
+
+	delay time.Duration // ソート用
 }
 
-func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request) {
+// handleNodes はノード一覧を返す
+// フィルタ・ソート・ページネーションは nodeQuery を参照。フィルタ後の総数を X-Total-Count に設定する
+func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request, rn *run) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	query, err := parseNodeQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	nodes, total := listNodes(rn, query)
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.writeJSON(w, nodes)
+}
+
+// newNodeInfo はノードからNodeInfoを作成する
+func newNodeInfo(n *node.Node) NodeInfo {
+	info := NodeInfo{
+		ID:     n.ID(),
+		Status: n.Status().String(),
+		Size:   n.Size(),
+		delay:  n.Delay(),
+
+		MemoryUsage:  n.MemoryUsage(),
+		OpsPerSecond: n.OpsPerSecond(),
+	}
+	if info.delay > 0 {
+		info.Delay = info.delay.String()
+	}
+	if labels := n.Labels(); len(labels) > 0 {
+		info.Zone = labels[node.LabelZone]
+		info.Labels = labels
+	}
+	return info
+}
+
+// NodeDetail はノードの詳細（UIのドリルダウン表示用）
+type NodeDetail struct {
+	NodeInfo
+	Incarnations int            `json:"incarnations"` // 起動した回数（再起動のたびに増える）
+	Ops          NodeOps        `json:"ops"`
+	Incidents    []events.Event `json:"incidents"` // 直近の障害注入・復旧イベント（古い順）
+}
+
+// NodeOps はノードが受け付けた操作の回数
+type NodeOps struct {
+	Gets     uint64 `json:"gets"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Sets     uint64 `json:"sets"`
+	Deletes  uint64 `json:"deletes"`
+	Scans    uint64 `json:"scans"`
+	Rejected uint64 `json:"rejected"` // 稼働中でないため失敗した操作
+	Shed     uint64 `json:"shed"`     // 同時に処理する操作の上限を超えたため打ち切った操作
+	Expired  uint64 `json:"expired"`  // TTL の経過で削除したキー
+	Evicted  uint64 `json:"evicted"`  // ノードの上限を超えたため削除したキー
+	Lost     uint64 `json:"lost"`     // 再起動でスナップショットに含まれていなかったため失ったキー
+
+	Conflicts uint64 `json:"conflicts"` // CRDT のマージで LWW により解決した値の競合
+	Refused   uint64 `json:"refused"`   // メモリ使用量の上限を超えるため拒否したキーの書き込み
+	Corrupted uint64 `json:"corrupted"` // corrupt 攻撃により値を壊して返した読み取り
+
+	HitRate float64 `json:"hit_rate"` // get のうち値が見つかった割合
+}
+
+// handleNode はノードの状態・操作回数・直近のイベントを返す
+func (s *Server) handleNode(w http.ResponseWriter, r *http.Request, rn *run) {
+	detail, err := nodeDetail(rn, r.PathValue("node"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, detail)
+}
+
+// NodeActionRequest はノード操作リクエスト（delay のみ使用）
+type NodeActionRequest struct {
+	Delay string `json:"delay,omitempty"`
+}
 
-	var nodes []NodeInfo
-	if s.cluster != nil {
-		for _, n := range s.cluster.Nodes() {
-			info := NodeInfo{
-				ID:     n.ID(),
-				Status: n.Status().String(),
-				Size:   n.Size(),
+// handleNodeAction は個別ノードへの障害注入・復旧を行う
+// POST /api/nodes/{node}/{kill|suspend|readonly|corrupt|resume|delay|start}
+func (s *Server) handleNodeAction(w http.ResponseWriter, r *http.Request, rn *run) {
+	nodeID := r.PathValue("node")
+	action := r.PathValue("action")
+
+	var delay *time.Duration
+	if action == "delay" {
+		var req NodeActionRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
 			}
-			if d := n.Delay(); d > 0 {
-				info.Delay = d.String()
+		}
+		if req.Delay != "" {
+			d, err := time.ParseDuration(req.Delay)
+			if err != nil || d < 0 {
+				http.Error(w, "Invalid delay", http.StatusBadRequest)
+				return
 			}
-			nodes = append(nodes, info)
+			delay = &d
 		}
 	}
 
-	s.writeJSON(w, nodes)
+	info, err := nodeAction(rn, nodeID, action, delay)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, info)
 }
 
 // MetricsResponse はメトリクスレスポンス
@@ -179,38 +559,88 @@ type MetricsResponse struct {
 	ErrorRate       float64 `json:"error_rate"`
 }
 
-func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request, rn *run) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	s.mu.RLock()
-	engine := s.engine
-	s.mu.RUnlock()
-
 	resp := MetricsResponse{}
-
-	if engine != nil {
-		if m := engine.Metrics(); m != nil {
-			resp.TotalRequests = m.TotalRequests
-			resp.SuccessRequests = m.SuccessRequests
-			resp.FailedRequests = m.FailedRequests
-			resp.RPS = m.RPS
-			resp.AvgLatencyMs = float64(m.AverageLatency.Microseconds()) / 1000.0
-			resp.P99LatencyMs = float64(m.P99Latency.Microseconds()) / 1000.0
-			resp.ErrorRate = m.ErrorRate
-		}
+	if m := runMetrics(rn); m != nil {
+		resp.TotalRequests = m.TotalRequests
+		resp.SuccessRequests = m.SuccessRequests
+		resp.FailedRequests = m.FailedRequests
+		resp.RPS = m.RPS
+		resp.AvgLatencyMs = float64(m.AverageLatency.Microseconds()) / 1000.0
+		resp.P99LatencyMs = float64(m.P99Latency.Microseconds()) / 1000.0
+		resp.ErrorRate = m.ErrorRate
 	}
 
 	s.writeJSON(w, resp)
 }
 
 // ScenarioRequest はシナリオ開始リクエスト
+// Scenario には設定ファイルの scenario セクションと同じスキーマで完全な設定を指定できる
+// 優先順位: プリセット < Scenario < Duration/Nodes
 type ScenarioRequest struct {
-	Preset   string `json:"preset"`
-	Duration string `json:"duration,omitempty"`
-	Nodes    int    `json:"nodes,omitempty"`
+	Preset   string                 `json:"preset"`
+	Scenario *config.ScenarioConfig `json:"scenario,omitempty"`
+	Duration string                 `json:"duration,omitempty"`
+	Nodes    int                    `json:"nodes,omitempty"`
+}
+
+// scenarioConfig はリクエストからシナリオ設定を構築する
+func (req *ScenarioRequest) scenarioConfig() (scenario.Config, error) {
+	// プリセット取得
+	cfg, ok := scenario.GetPreset(req.Preset)
+	if !ok {
+		if req.Preset != "" && req.Scenario != nil {
+			return cfg, fmt.Errorf("unknown preset: %s", req.Preset)
+		}
+		if req.Scenario != nil {
+			cfg = scenario.DefaultConfig()
+		} else {
+			cfg = scenario.QuickScenario()
+		}
+	}
+
+	// 完全なシナリオ設定
+	if req.Scenario != nil {
+		// 外部のプロセス・ノードの子プロセスのコマンドはサーバー上で実行するため、リクエストからは受け付けない
+		if len(req.Scenario.External) > 0 {
+			return cfg, fmt.Errorf("scenario.external can only be set in a local config file")
+		}
+		if len(req.Scenario.NodeProcess.Command) > 0 {
+			return cfg, fmt.Errorf("scenario.node_process.command can only be set in a local config file")
+		}
+		// スナップショット・ログはサーバー上のディレクトリに書き出すため、リクエストからは一時ディレクトリのみ使える
+		if req.Scenario.Durability.Dir != "" {
+			return cfg, fmt.Errorf("scenario.durability.dir can only be set in a local config file")
+		}
+		fileConfig := config.FileConfig{Scenario: *req.Scenario}
+		if err := fileConfig.Validate(); err != nil {
+			return cfg, err
+		}
+		var err error
+		cfg, err = fileConfig.ApplyTo(cfg)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	// オーバーライド
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid duration: %w", err)
+		}
+		cfg.Duration = d
+	}
+	if req.Nodes > 0 {
+		cfg.NodeCount = req.Nodes
+	}
+
+	return cfg, nil
 }
 
 func (s *Server) handleScenarioStart(w http.ResponseWriter, r *http.Request) {
@@ -225,76 +655,88 @@ func (s *Server) handleScenarioStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.mu.Lock()
-	if s.running {
-		s.mu.Unlock()
-		http.Error(w, "Scenario already running", http.StatusConflict)
+	// 既存のクライアントとの互換のため、既定の実行が終わるまで新しい実行を受け付けない
+	resp, err := s.startScenario(req, true)
+	if err != nil {
+		s.writeError(w, r, err)
 		return
 	}
 
-	// プリセット取得
-	config, ok := scenario.GetPreset(req.Preset)
-	if !ok {
-		config = scenario.QuickScenario()
-	}
+	s.writeJSON(w, resp)
+}
 
-	// オーバーライド
-	if req.Duration != "" {
-		if d, err := time.ParseDuration(req.Duration); err == nil {
-			config.Duration = d
-		}
-	}
-	if req.Nodes > 0 {
-		config.NodeCount = req.Nodes
-	}
+// StartResponse はシナリオ開始レスポンス
+type StartResponse struct {
+	Status   string `json:"status"`
+	Scenario string `json:"scenario"`
+	RunID    string `json:"run_id"`
+}
 
-	s.config = config
-	s.cluster = cluster.New()
-	s.engine = scenario.New(config)
-	s.engine.SetEventBus(s.eventBus)
-	s.running = true
-	s.mu.Unlock()
+// stopTimeout はシナリオ停止を待機する最大時間
+const stopTimeout = 10 * time.Second
 
-	// バックグラウンドで実行
-	go func() {
-		ctx := context.Background()
-		result, err := s.engine.Run(ctx)
+// StopResponse はシナリオ停止レスポンス
+type StopResponse struct {
+	Status string           `json:"status"`
+	RunID  string           `json:"run_id,omitempty"`
+	Result *scenario.Result `json:"result,omitempty"`
+}
 
-		s.mu.Lock()
-		s.running = false
-		s.mu.Unlock()
+func (s *Server) handleScenarioStop(w http.ResponseWriter, r *http.Request, rn *run) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		if err != nil {
-			logger.Error("", "Scenario failed: %v", err)
-		} else {
-			logger.Info("", "Scenario completed: %d requests", result.TotalRequests)
-		}
+	resp, err := stopRun(r.Context(), rn)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
 
-		s.broadcast(map[string]interface{}{
-			"type":   "scenario_complete",
-			"result": result,
-		})
-	}()
+	s.writeJSON(w, resp)
+}
 
-	s.writeJSON(w, map[string]string{"status": "started", "scenario": config.Name})
+// handleRuns は完了したシナリオ実行の一覧を新しい順に返す
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.history.List())
 }
 
-func (s *Server) handleScenarioStop(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// handleRun は実行結果とイベントタイムラインを返す
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	run, ok := s.history.Get(id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Run %s not found", id), http.StatusNotFound)
 		return
 	}
+	s.writeJSON(w, run)
+}
 
-	s.mu.Lock()
-	if !s.running {
-		s.mu.Unlock()
-		http.Error(w, "No scenario running", http.StatusBadRequest)
+// handleRunReport は完了した実行のレポートを ?format=text|json|md|html で返す
+func (s *Server) handleRunReport(w http.ResponseWriter, r *http.Request) {
+	format, err := scenario.ParseReportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	// Note: Would need to add cancellation support to scenario.Engine
-	s.mu.Unlock()
 
-	s.writeJSON(w, map[string]string{"status": "stop requested"})
+	id := r.PathValue("id")
+	run, ok := s.history.Get(id)
+	if !ok || run.Result == nil {
+		if _, err := s.lookupRun(id); err == nil {
+			s.writeError(w, r, newOpError(errConflict, "Run %s has not completed", id))
+			return
+		}
+		http.Error(w, fmt.Sprintf("Run %s not found", id), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s-report.%s"`, id, format.Extension()))
+	if err := run.Result.WriteReport(w, format); err != nil {
+		logger.Warn("", "Report %s aborted: %v", requestIDFrom(r.Context()), err)
+	}
 }
 
 // PresetInfo はプリセット情報
@@ -320,60 +762,43 @@ func (s *Server) handlePresets(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, presets)
 }
 
-// WebSocket handling
-func (s *Server) handleWebSocket(ws *websocket.Conn) {
-	s.mu.Lock()
-	s.wsClients[ws] = true
-	s.mu.Unlock()
-
-	// Subscribe to events
-	eventCh := s.eventBus.Subscribe()
-
-	defer func() {
-		s.eventBus.Unsubscribe(eventCh)
-		s.mu.Lock()
-		delete(s.wsClients, ws)
-		s.mu.Unlock()
-		_ = ws.Close()
-	}()
-
-	// Forward events to this client
-	go func() {
-		for event := range eventCh {
-			msg := map[string]interface{}{
-				"type":  "event",
-				"event": event,
-			}
-			if err := websocket.JSON.Send(ws, msg); err != nil {
-				return
-			}
-		}
-	}()
-
-	// Keep connection alive
-	for {
-		var msg string
-		if err := websocket.Message.Receive(ws, &msg); err != nil {
-			break
-		}
+// forwardEvents はカオス/復旧イベントを種別付きで全クライアントへ配信する
+// バスがクローズされると終了する
+func (s *Server) forwardEvents(eventCh <-chan events.Event) {
+	for event := range eventCh {
+		s.broadcast(eventMessage(event))
 	}
 }
 
-func (s *Server) broadcast(data interface{}) {
-	s.mu.RLock()
-	clients := make([]*websocket.Conn, 0, len(s.wsClients))
-	for ws := range s.wsClients {
-		clients = append(clients, ws)
+// eventMessage はイベントをクライアント向けメッセージに変換する
+// event_type でUIが種別ごとにタイムラインを描画できるようにする
+func eventMessage(event events.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "event",
+		"event_type": event.Type,
+		"event":      event,
 	}
-	s.mu.RUnlock()
+}
 
+// broadcast は全てのWebSocket/SSEクライアントにメッセージを送信する
+// data は "type" キーを持つ map であることを想定し、SSEのイベント名として使用する
+func (s *Server) broadcast(data map[string]interface{}) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return
 	}
 
-	for _, ws := range clients {
-		_ = websocket.Message.Send(ws, string(jsonData))
+	s.broadcastWS(jsonData)
+
+	msgType, _ := data["type"].(string)
+	s.broadcastSSE(msgType, jsonData)
+}
+
+// replayEvents は記録済みのイベントをイベントバスに発行する
+func (s *Server) replayEvents(ctx context.Context) {
+	logger.Info("", "Replaying %d events (speed: %v)", len(s.replay), s.replaySpeed)
+	if n, err := events.Replay(ctx, s.eventBus, s.replay, s.replaySpeed); err == nil {
+		logger.Info("", "Replay finished (%d events)", n)
 	}
 }
 
@@ -386,37 +811,13 @@ func (s *Server) broadcastLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.mu.RLock()
-			if !s.running {
-				s.mu.RUnlock()
+			rn := s.currentRun()
+			if rn == nil || !rn.isRunning() {
 				continue
 			}
 
-			status := StatusResponse{
-				Running: s.running,
-			}
-			if s.config.Name != "" {
-				status.ScenarioName = s.config.Name
-			}
-
-			engine := s.engine
-			s.mu.RUnlock()
-
-			// Get cluster info from engine
-			if engine != nil {
-				if c := engine.Cluster(); c != nil {
-					status.NodeCount = c.Size()
-					status.RunningNodes = c.RunningCount()
-					for _, n := range c.Nodes() {
-						switch n.Status().String() {
-						case "Stopped":
-							status.StoppedNodes++
-						case "Suspended":
-							status.SuspendedNodes++
-						}
-					}
-				}
-			}
+			status := rn.status()
+			engine := rn.engine
 
 			// Build the full status update
 			msg := map[string]interface{}{
@@ -425,23 +826,21 @@ func (s *Server) broadcastLoop(ctx context.Context) {
 			}
 
 			// Add chaos stats
-			if engine != nil {
-				if cs := engine.ChaosStats(); cs != nil {
-					msg["chaos_stats"] = cs
-				}
-				if rs := engine.RecoveryStats(); rs != nil {
-					msg["recovery_stats"] = rs
-				}
-				if m := engine.Metrics(); m != nil {
-					msg["metrics"] = MetricsResponse{
-						TotalRequests:   m.TotalRequests,
-						SuccessRequests: m.SuccessRequests,
-						FailedRequests:  m.FailedRequests,
-						RPS:             m.RPS,
-						AvgLatencyMs:    float64(m.AverageLatency.Microseconds()) / 1000.0,
-						P99LatencyMs:    float64(m.P99Latency.Microseconds()) / 1000.0,
-						ErrorRate:       m.ErrorRate,
-					}
+			if cs := engine.ChaosStats(); cs != nil {
+				msg["chaos_stats"] = cs
+			}
+			if rs := engine.RecoveryStats(); rs != nil {
+				msg["recovery_stats"] = rs
+			}
+			if m := engine.Metrics(); m != nil {
+				msg["metrics"] = MetricsResponse{
+					TotalRequests:   m.TotalRequests,
+					SuccessRequests: m.SuccessRequests,
+					FailedRequests:  m.FailedRequests,
+					RPS:             m.RPS,
+					AvgLatencyMs:    float64(m.AverageLatency.Microseconds()) / 1000.0,
+					P99LatencyMs:    float64(m.P99Latency.Microseconds()) / 1000.0,
+					ErrorRate:       m.ErrorRate,
 				}
 			}
 
@@ -0,0 +1,564 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+
+	"golang.org/x/net/websocket"
+)
+
+// newTestServer はテスト用のAPIサーバーを作成する
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+
+	s := NewServer("")
+	handler, err := s.Handler()
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// startScenario はシナリオを開始し、ノードが作成されるまで待機する
+func startScenario(t *testing.T, s *Server, ts *httptest.Server, body string) {
+	t.Helper()
+
+	resp, err := http.Post(ts.URL+"/api/scenario/start", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to start scenario: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if c := s.currentCluster(); c != nil && c.RunningCount() > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for scenario setup")
+}
+
+// stopScenario はシナリオを停止する
+func stopScenario(t *testing.T, ts *httptest.Server) StopResponse {
+	t.Helper()
+
+	resp, err := http.Post(ts.URL+"/api/scenario/stop", "application/json", nil)
+	if err != nil {
+		t.Fatalf("failed to stop scenario: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var stop StopResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stop); err != nil {
+		t.Fatalf("failed to decode stop response: %v", err)
+	}
+	return stop
+}
+
+func TestScenarioStop(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	resp, err := http.Post(ts.URL+"/api/scenario/stop", "application/json", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 without running scenario, got %d", resp.StatusCode)
+	}
+
+	startScenario(t, s, ts, `{"preset":"basic","duration":"30s","nodes":2}`)
+
+	stop := stopScenario(t, ts)
+	if stop.Status != "stopped" {
+		t.Errorf("expected status 'stopped', got '%s'", stop.Status)
+	}
+	if stop.Result == nil {
+		t.Fatal("expected partial result")
+	}
+	if !stop.Result.Interrupted {
+		t.Error("expected result to be interrupted")
+	}
+}
+
+func TestNodeAction(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	post := func(path, body string) (*http.Response, NodeInfo) {
+		t.Helper()
+		resp, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		var info NodeInfo
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp, info
+	}
+
+	// シナリオ未実行
+	if resp, _ := post("/api/nodes/node-1/kill", ""); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 without scenario, got %d", resp.StatusCode)
+	}
+
+	startScenario(t, s, ts, `{"preset":"basic","duration":"30s","nodes":2}`)
+	defer stopScenario(t, ts)
+
+	tests := []struct {
+		path   string
+		body   string
+		status string
+		delay  string
+	}{
+		{"/api/nodes/node-1/kill", "", "stopped", ""},
+		{"/api/nodes/node-1/start", "", "running", ""},
+		{"/api/nodes/node-1/suspend", "", "suspended", ""},
+		{"/api/nodes/node-1/resume", "", "running", ""},
+		{"/api/nodes/node-1/delay", `{"delay":"20ms"}`, "running", "20ms"},
+		{"/api/nodes/node-1/delay", `{"delay":"0s"}`, "running", ""},
+	}
+
+	for _, tt := range tests {
+		resp, info := post(tt.path, tt.body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tt.path, resp.StatusCode)
+		}
+		if info.Status != tt.status {
+			t.Errorf("%s: expected status '%s', got '%s'", tt.path, tt.status, info.Status)
+		}
+		if info.Delay != tt.delay {
+			t.Errorf("%s: expected delay '%s', got '%s'", tt.path, tt.delay, info.Delay)
+		}
+	}
+
+	if resp, _ := post("/api/nodes/node-1/resume", ""); resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409 when resuming running node, got %d", resp.StatusCode)
+	}
+	if resp, _ := post("/api/nodes/missing/kill", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown node, got %d", resp.StatusCode)
+	}
+	if resp, _ := post("/api/nodes/node-1/explode", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown action, got %d", resp.StatusCode)
+	}
+	if resp, _ := post("/api/nodes/node-1/delay", `{"delay":"soon"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid delay, got %d", resp.StatusCode)
+	}
+}
+
+func TestNodeDetail(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	get := func(path string) (*http.Response, NodeDetail) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		var detail NodeDetail
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp, detail
+	}
+
+	if resp, _ := get("/api/nodes/node-1"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 without scenario, got %d", resp.StatusCode)
+	}
+
+	// 自動の障害注入・復旧が起きないよう手動操作のみにする
+	startScenario(t, s, ts, `{"scenario":{"duration":"30s","node_count":2,"chaos":{"enabled":false},"recovery":{"enabled":false}}}`)
+	defer stopScenario(t, ts)
+
+	for _, action := range []string{"kill", "start"} {
+		resp, err := http.Post(ts.URL+"/api/nodes/node-1/"+action, "application/json", nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", action, resp.StatusCode)
+		}
+	}
+
+	// イベントは非同期に記録される
+	var detail NodeDetail
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		var resp *http.Response
+		resp, detail = get("/api/nodes/node-1")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", resp.StatusCode)
+		}
+		if len(detail.Incidents) >= 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if detail.ID != "node-1" || detail.Status != "running" {
+		t.Errorf("unexpected node: %+v", detail.NodeInfo)
+	}
+	if detail.Incarnations != 2 {
+		t.Errorf("expected 2 incarnations, got %d", detail.Incarnations)
+	}
+	if len(detail.Incidents) != 2 || detail.Incidents[0].Data.AttackType != events.AttackTypeKill ||
+		detail.Incidents[1].Type != events.EventNodeStarted {
+		t.Errorf("expected kill and start incidents, got %+v", detail.Incidents)
+	}
+	if detail.Ops.Gets+detail.Ops.Sets+detail.Ops.Rejected == 0 {
+		t.Error("expected node operations to be counted")
+	}
+	if detail.Ops.Hits+detail.Ops.Misses != detail.Ops.Gets || detail.Ops.HitRate < 0 || detail.Ops.HitRate > 1 {
+		t.Errorf("expected hits and misses to add up to the gets, got %+v", detail.Ops)
+	}
+
+	if resp, _ := get("/api/nodes/missing"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown node, got %d", resp.StatusCode)
+	}
+}
+
+func TestStream(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/stream")
+	if err != nil {
+		t.Fatalf("failed to connect stream: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected content type text/event-stream, got '%s'", ct)
+	}
+
+	// 接続登録を待つ
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.RLock()
+		n := len(s.sseClients)
+		s.mu.RUnlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.broadcast(map[string]interface{}{"type": "status", "status": StatusResponse{NodeCount: 3}})
+	s.eventBus.Publish(events.NewChaosAttackEvent("node-1", events.AttackTypeKill))
+
+	reader := bufio.NewReader(resp.Body)
+	got := map[string]string{}
+	var eventName string
+	for len(got) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventName = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			got[eventName] = strings.TrimPrefix(line, "data: ")
+		}
+	}
+
+	if !strings.Contains(got["status"], `"node_count":3`) {
+		t.Errorf("unexpected status payload: %s", got["status"])
+	}
+	if !strings.Contains(got["event"], `"node_id":"node-1"`) {
+		t.Errorf("unexpected event payload: %s", got["event"])
+	}
+	if !strings.Contains(got["event"], `"event_type":"chaos_attack"`) {
+		t.Errorf("expected event payload to be tagged with its type: %s", got["event"])
+	}
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	startScenario(t, s, ts, `{"preset":"basic","duration":"30s","nodes":2}`)
+	defer stopScenario(t, ts)
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to get metrics: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type: %s", resp.Header.Get("Content-Type"))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	text := string(body)
+
+	expected := []string{
+		"chaoskvs_scenario_running 1",
+		`chaoskvs_client_requests_total{result="success"}`,
+		"chaoskvs_chaos_attacks_total 0",
+		`chaoskvs_nodes{status="running"} 2`,
+		`chaoskvs_node_up{node="node-1"} 1`,
+		`chaoskvs_node_evictions_total{node="node-1"} 0`,
+		`chaoskvs_node_shed_total{node="node-1"} 0`,
+		`chaoskvs_node_operations_total{node="node-1",op="get"}`,
+		"# TYPE chaoskvs_node_get_misses_total counter",
+		`chaoskvs_node_lost_keys_total{node="node-1"} 0`,
+		"# TYPE chaoskvs_node_bytes gauge",
+		`chaoskvs_http_requests_total{method="POST",path="/api/scenario/start",code="200"} 1`,
+		"# TYPE chaoskvs_http_request_duration_seconds summary",
+		"chaoskvs_events_dropped_total 0",
+		"# TYPE chaoskvs_worker_pool_exec_seconds histogram",
+		`chaoskvs_worker_pool_queue_seconds_bucket{le="+Inf"}`,
+		`chaoskvs_worker_pool_queue_depth{priority="high"} 0`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(text, e) {
+			t.Errorf("expected metrics to contain %q", e)
+		}
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/runs/missing")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown run, got %d", resp.StatusCode)
+	}
+
+	startScenario(t, s, ts, `{"preset":"quick","duration":"30s","nodes":2}`)
+	if err := s.currentRun().engine.Monkey().Inject("node-1", chaos.AttackKill); err != nil {
+		t.Fatalf("failed to inject attack: %v", err)
+	}
+	stop := stopScenario(t, ts)
+	if stop.RunID == "" {
+		t.Fatal("expected stop response to contain run ID")
+	}
+
+	resp, err = http.Get(ts.URL + "/api/runs")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var runs []history.Summary
+	err = json.NewDecoder(resp.Body).Decode(&runs)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to decode runs: %v", err)
+	}
+	if len(runs) != 1 || runs[0].ID != stop.RunID {
+		t.Fatalf("expected run %s in list, got %+v", stop.RunID, runs)
+	}
+	if !runs[0].Interrupted {
+		t.Error("expected run to be marked as interrupted")
+	}
+
+	resp, err = http.Get(ts.URL + "/api/runs/" + stop.RunID)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var run history.Run
+	err = json.NewDecoder(resp.Body).Decode(&run)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to decode run: %v", err)
+	}
+	if run.Result == nil || run.Config.NodeCount != 2 {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	found := false
+	for _, e := range run.Timeline {
+		if e.Type == events.EventChaosAttack && e.NodeID == "node-1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected timeline to contain kill attack, got %+v", run.Timeline)
+	}
+}
+
+func TestScenarioStartFullConfig(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	body := `{
+		"scenario": {
+			"name": "custom",
+			"duration": "30s",
+			"node_count": 3,
+			"client": {"workers": 2, "write_ratio": 0.8},
+			"chaos": {"enabled": true, "interval": "1h", "attack_types": ["suspend"]},
+			"recovery": {"enabled": false}
+		}
+	}`
+	startScenario(t, s, ts, body)
+	defer stopScenario(t, ts)
+
+	cfg := s.currentRun().config
+
+	if cfg.Name != "custom" || cfg.NodeCount != 3 || cfg.ClientWorkers != 2 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.WriteRatio != 0.8 {
+		t.Errorf("expected write ratio 0.8, got %v", cfg.WriteRatio)
+	}
+	if !cfg.EnableChaos || cfg.EnableRecovery {
+		t.Errorf("expected chaos enabled and recovery disabled, got %v/%v", cfg.EnableChaos, cfg.EnableRecovery)
+	}
+	if len(cfg.AttackTypes) != 1 || cfg.AttackTypes[0] != chaos.AttackSuspend {
+		t.Errorf("expected suspend attack only, got %v", cfg.AttackTypes)
+	}
+}
+
+func TestScenarioStartInvalidConfig(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	tests := []string{
+		`{"scenario": {"chaos": {"attack_types": ["explode"]}}}`,
+		`{"scenario": {"duration": "forever"}}`,
+		`{"preset": "quick", "duration": "forever"}`,
+		`{"scenario": {"client": {"write_ratio": 2}}}`,
+		`{"preset": "missing", "scenario": {}}`,
+		`{"scenario": {"external": [{"id": "redis-1", "addr": "127.0.0.1:6379", "start": "touch /tmp/pwned"}]}}`,
+		`{"scenario": {"node_process": {"enabled": true, "command": ["sh", "-c", "touch /tmp/pwned"]}}}`,
+		`{"scenario": {"durability": {"mode": "wal", "dir": "/etc/chaos-kvs"}}}`,
+	}
+
+	for _, body := range tests {
+		resp, err := http.Post(ts.URL+"/api/scenario/start", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func TestWebSocketEvents(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	ws := dialWebSocket(t, s, ts)
+
+	s.eventBus.Publish(events.NewRecoverySuccessEvent("node-2"))
+
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg struct {
+		Type      string       `json:"type"`
+		EventType string       `json:"event_type"`
+		Event     events.Event `json:"event"`
+	}
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("failed to receive message: %v", err)
+	}
+	if msg.Type != "event" || msg.EventType != string(events.EventRecoverySuccess) {
+		t.Errorf("unexpected message tags: type=%s event_type=%s", msg.Type, msg.EventType)
+	}
+	if msg.Event.NodeID != "node-2" {
+		t.Errorf("expected node-2, got %s", msg.Event.NodeID)
+	}
+}
+
+func TestReplayEvents(t *testing.T) {
+	config := DefaultConfig()
+	config.Replay = []events.Event{
+		events.NewChaosAttackEvent("node-1", events.AttackTypeKill),
+		events.NewRecoverySuccessEvent("node-1"),
+	}
+	s, err := NewServerWithConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	ch := s.eventBus.Subscribe()
+	s.replayEvents(context.Background())
+
+	for _, want := range []events.EventType{events.EventChaosAttack, events.EventRecoverySuccess} {
+		select {
+		case e := <-ch:
+			if e.Type != want {
+				t.Errorf("expected %s, got %s", want, e.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+}
+
+func TestServerWebhook(t *testing.T) {
+	received := make(chan events.Event, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event events.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			received <- event
+		}
+	}))
+	defer hook.Close()
+
+	config := DefaultConfig()
+	config.Addr = "127.0.0.1:0"
+	config.LogRequests = false
+	config.WebhookURL = hook.URL
+	s, err := NewServerWithConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Start(ctx) }()
+
+	// Start がWebhookを購読するまで待機
+	deadline := time.Now().Add(time.Second)
+	for s.eventBus.SubscriberCount() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.eventBus.Publish(events.NewChaosAttackEvent("node-1", events.AttackTypeKill))
+
+	select {
+	case event := <-received:
+		if event.NodeID != "node-1" || event.Type != events.EventChaosAttack {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for webhook delivery")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected clean shutdown, got %v", err)
+	}
+}
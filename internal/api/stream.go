@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// sseBufferSize はSSEクライアントごとの送信バッファサイズ
+	sseBufferSize = 64
+	// sseHeartbeatInterval はSSE接続維持用のコメント送信間隔
+	sseHeartbeatInterval = 15 * time.Second
+)
+
+// sseMessage はSSEクライアントに送信するメッセージ
+type sseMessage struct {
+	Type string
+	Data []byte
+}
+
+// handleStream はServer-Sent Eventsでステータス・メトリクス・イベントを配信する
+// WebSocketと同じペイロードを data に、メッセージ種別を event に設定する
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	ch := make(chan sseMessage, sseBufferSize)
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		http.Error(w, "Server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	s.sseClients[ch] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.sseClients, ch)
+		s.mu.Unlock()
+	}()
+
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case msg, ok := <-ch:
+			if !ok {
+				// シャットダウン
+				return
+			}
+			if err := writeSSE(w, msg); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE はSSE形式でメッセージを書き込む
+func writeSSE(w http.ResponseWriter, msg sseMessage) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, msg.Data)
+	return err
+}
+
+// broadcastSSE は全てのSSEクライアントにメッセージを送信する
+// 送信バッファが一杯のクライアントにはメッセージを破棄する
+func (s *Server) broadcastSSE(msgType string, data []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for ch := range s.sseClients {
+		select {
+		case ch <- sseMessage{Type: msgType, Data: data}:
+		default:
+		}
+	}
+}
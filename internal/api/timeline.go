@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// parseEventQuery は GET /api/events のクエリパラメータを解析する
+func parseEventQuery(values url.Values, now time.Time) (events.HistoryQuery, error) {
+	q := events.HistoryQuery{
+		NodeID: values.Get("node"),
+		RunID:  values.Get("run"),
+	}
+
+	for _, t := range strings.Split(values.Get("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			q.Types = append(q.Types, events.EventType(t))
+		}
+	}
+
+	var err error
+	if q.Since, err = timeParam(values, "since", now); err != nil {
+		return q, err
+	}
+	if q.Until, err = timeParam(values, "until", now); err != nil {
+		return q, err
+	}
+	if q.Limit, err = nonNegativeInt(values, "limit"); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// handleEvents は全ての実行の直近のイベントを古い順に返す
+// 後から接続したクライアントがタイムラインを埋めるのに使う
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	query, err := parseEventQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := []events.Event{}
+	if h := s.eventBus.History(); h != nil {
+		result = h.Query(query)
+	}
+
+	s.writeJSON(w, result)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+func TestEventsEndpoint(t *testing.T) {
+	config := DefaultConfig()
+	config.EventHistorySize = 3
+	s, ts := newTestServerWithConfig(t, config)
+
+	attack := events.NewChaosAttackEvent("node-1", events.AttackTypeKill)
+	attack.RunID = "run-1"
+	s.eventBus.Publish(events.NewRecoveryStartEvent("node-0", 1))
+	s.eventBus.Publish(attack)
+	s.eventBus.Publish(events.NewRecoveryStartEvent("node-1", 1))
+	s.eventBus.Publish(events.NewRecoverySuccessEvent("node-1"))
+
+	get := func(query string) (*http.Response, []events.Event) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/api/events" + query)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		var list []events.Event
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp, list
+	}
+
+	if _, list := get(""); len(list) != 3 || list[0].Type != events.EventChaosAttack {
+		t.Errorf("expected the newest 3 events oldest first, got %+v", list)
+	}
+	if _, list := get("?type=recovery_start,recovery_success&node=node-1"); len(list) != 2 {
+		t.Errorf("expected 2 recovery events for node-1, got %+v", list)
+	}
+	if _, list := get("?run=run-1"); len(list) != 1 || list[0].Type != events.EventChaosAttack {
+		t.Errorf("expected attack for run-1, got %+v", list)
+	}
+	if _, list := get("?limit=1"); len(list) != 1 || list[0].Type != events.EventRecoverySuccess {
+		t.Errorf("expected latest event, got %+v", list)
+	}
+	if resp, list := get("?until=1h"); resp.StatusCode != http.StatusOK || list == nil || len(list) != 0 {
+		t.Errorf("expected empty list for events older than an hour, got %+v", list)
+	}
+
+	for _, query := range []string{"?since=yesterday", "?until=later", "?limit=-1"} {
+		if resp, _ := get(query); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// wsBufferSize はWebSocketクライアントごとの送信バッファサイズ
+	wsBufferSize = 64
+	// wsWriteTimeout は1メッセージの送信に許容する最大時間
+	wsWriteTimeout = 5 * time.Second
+	// wsPingInterval は接続維持用のpingフレーム送信間隔
+	wsPingInterval = 20 * time.Second
+)
+
+// wsClient は接続中のWebSocketクライアント
+// 送信は専用のゴルーチンで行い、遅いクライアントが broadcast を妨げないようにする
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte   // 送信待ちメッセージ（クローズで送信終了）
+	done chan struct{} // 送信ゴルーチンの終了通知
+
+	logs     bool         // ログを購読しているかどうか（?logs=<level> で指定）
+	logLevel logger.Level // 購読するログの最小レベル
+}
+
+// newWSClient は新しいWebSocketクライアントを作成する
+func newWSClient(conn *websocket.Conn) *wsClient {
+	return &wsClient{
+		conn: conn,
+		send: make(chan []byte, wsBufferSize),
+		done: make(chan struct{}),
+	}
+}
+
+// writeLoop はキューのメッセージと定期的なpingを送信する
+// send がクローズされると残りのメッセージを送信し、クローズフレームを送って接続を閉じる
+func (c *wsClient) writeLoop() {
+	defer close(c.done)
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			if !ok {
+				_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+				_ = c.conn.Close() // クローズフレームを送信して切断
+				return
+			}
+			if err := c.write(websocket.TextFrame, msg); err != nil {
+				_ = c.conn.Close()
+				return
+			}
+		case <-ping.C:
+			// pongはライブラリが受信時に処理するため、到達確認は書き込みタイムアウトで行う
+			if err := c.write(websocket.PingFrame, nil); err != nil {
+				_ = c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// write は書き込みタイムアウト付きでフレームを送信する
+func (c *wsClient) write(payloadType byte, msg []byte) error {
+	if err := c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	c.conn.PayloadType = payloadType
+	_, err := c.conn.Write(msg)
+	return err
+}
+
+// handleWebSocket はWebSocketクライアントを登録し、切断まで受信を続ける
+// ?logs=<level> を指定すると、そのレベル以上のログも type: log で配信する
+func (s *Server) handleWebSocket(ws *websocket.Conn) {
+	c := newWSClient(ws)
+	if v := ws.Request().URL.Query().Get("logs"); v != "" {
+		if level, err := logger.ParseLevel(v); err == nil {
+			c.logs = true
+			c.logLevel = level
+		}
+	}
+
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		_ = ws.Close()
+		return
+	}
+	s.wsClients[c] = struct{}{}
+	s.mu.Unlock()
+
+	go c.writeLoop()
+
+	// クライアントからのメッセージは使用しないが、切断検知のため受信を続ける
+	for {
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			break
+		}
+	}
+
+	s.removeWSClient(c)
+	<-c.done
+}
+
+// removeWSClient はクライアントを登録解除し、送信ゴルーチンを終了させる
+func (s *Server) removeWSClient(c *wsClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.wsClients[c]; ok {
+		delete(s.wsClients, c)
+		close(c.send)
+	}
+}
+
+// broadcastWS は全てのWebSocketクライアントの送信キューにメッセージを追加する
+// 送信キューが一杯のクライアントにはメッセージを破棄する
+func (s *Server) broadcastWS(data []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for c := range s.wsClients {
+		select {
+		case c.send <- data:
+		default:
+		}
+	}
+}
+
+// broadcastLogWS はログを購読しているWebSocketクライアントにメッセージを送信する
+func (s *Server) broadcastLogWS(level logger.Level, data []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for c := range s.wsClients {
+		if !c.logs || level < c.logLevel {
+			continue
+		}
+		select {
+		case c.send <- data:
+		default:
+		}
+	}
+}
+
+// closeClients は新規接続の受付を停止し、全てのWebSocket/SSEクライアントを切断する
+// WebSocketクライアントには送信待ちのメッセージを送り切った後にクローズフレームを送信する
+func (s *Server) closeClients(ctx context.Context) {
+	s.mu.Lock()
+	if !s.closing {
+		close(s.shutdown)
+	}
+	s.closing = true
+	clients := make([]*wsClient, 0, len(s.wsClients))
+	for c := range s.wsClients {
+		delete(s.wsClients, c)
+		close(c.send)
+		clients = append(clients, c)
+	}
+	for ch := range s.sseClients {
+		delete(s.sseClients, ch)
+		close(ch)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
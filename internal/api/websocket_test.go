@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// dialWebSocket はテストサーバーにWebSocket接続し、登録されるまで待機する
+func dialWebSocket(t *testing.T, s *Server, ts *httptest.Server) *websocket.Conn {
+	t.Helper()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	ws, err := websocket.Dial(wsURL, "", ts.URL)
+	if err != nil {
+		t.Fatalf("failed to connect websocket: %v", err)
+	}
+	t.Cleanup(func() { _ = ws.Close() })
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.RLock()
+		n := len(s.wsClients)
+		s.mu.RUnlock()
+		if n > 0 {
+			return ws
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for websocket registration")
+	return nil
+}
+
+func TestWebSocketCloseClients(t *testing.T) {
+	s, ts := newTestServer(t)
+	ws := dialWebSocket(t, s, ts)
+
+	// 送信待ちのメッセージはクローズ前に送り切る
+	s.broadcast(map[string]interface{}{"type": "status", "status": StatusResponse{NodeCount: 7}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.closeClients(ctx)
+
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg string
+	if err := websocket.Message.Receive(ws, &msg); err != nil {
+		t.Fatalf("expected queued message before close, got error: %v", err)
+	}
+	if !strings.Contains(msg, `"node_count":7`) {
+		t.Errorf("unexpected message: %s", msg)
+	}
+	if err := websocket.Message.Receive(ws, &msg); err != io.EOF {
+		t.Errorf("expected close frame (EOF), got %v", err)
+	}
+
+	s.mu.RLock()
+	n := len(s.wsClients)
+	s.mu.RUnlock()
+	if n != 0 {
+		t.Errorf("expected no websocket clients after close, got %d", n)
+	}
+
+	// シャットダウン後の新規接続は即座に切断される
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	late, err := websocket.Dial(wsURL, "", ts.URL)
+	if err != nil {
+		t.Fatalf("failed to connect websocket: %v", err)
+	}
+	defer func() { _ = late.Close() }()
+	_ = late.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := websocket.Message.Receive(late, &msg); err != io.EOF {
+		t.Errorf("expected new connection to be closed, got %v", err)
+	}
+}
+
+func TestWebSocketSlowClient(t *testing.T) {
+	s, ts := newTestServer(t)
+	_ = dialWebSocket(t, s, ts) // 受信しないクライアント
+
+	start := time.Now()
+	payload := map[string]interface{}{"type": "status", "padding": strings.Repeat("x", 16*1024)}
+	for i := 0; i < 10*wsBufferSize; i++ {
+		s.broadcast(payload)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("broadcast blocked by slow client for %v", elapsed)
+	}
+}
+
+func TestWebSocketPing(t *testing.T) {
+	s, ts := newTestServer(t)
+	ws := dialWebSocket(t, s, ts)
+
+	s.mu.RLock()
+	var c *wsClient
+	for client := range s.wsClients {
+		c = client
+	}
+	s.mu.RUnlock()
+
+	// pingフレームはクライアント側で透過的に処理され、後続のメッセージは通常通り届く
+	if err := c.write(websocket.PingFrame, nil); err != nil {
+		t.Fatalf("failed to write ping: %v", err)
+	}
+	s.broadcast(map[string]interface{}{"type": "status"})
+
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg string
+	if err := websocket.Message.Receive(ws, &msg); err != nil {
+		t.Fatalf("failed to receive message after ping: %v", err)
+	}
+	if !strings.Contains(msg, `"type":"status"`) {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
@@ -0,0 +1,269 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/worker"
+)
+
+// latencySampleEvery は何回の操作ごとにレイテンシをP99計算用のサンプルとして残すか
+const latencySampleEvery = 16
+
+// maxLatencySamples はゴルーチンごとに残すレイテンシのサンプル数の上限
+const maxLatencySamples = 10000
+
+// Config はベンチマークの設定
+type Config struct {
+	Duration   time.Duration // ベンチマークごとの実行時間
+	Workers    int           // 並行して操作するゴルーチン・ワーカーの数（0でCPU数）
+	Keys       int           // 使用するキーの数
+	ValueSize  int           // 書き込む値のバイト数
+	WriteRatio float64       // node-mixed の書き込みの比率
+	BatchSize  int           // pool-batch で1度に送信するジョブ数
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Duration:   3 * time.Second,
+		Workers:    0, // CPU数
+		Keys:       10000,
+		ValueSize:  64,
+		WriteRatio: 0.5,
+		BatchSize:  32,
+	}
+}
+
+// Result は1つのベンチマークの結果
+// レイテンシは計測のオーバーヘッド（time.Now の呼び出し）を含む
+type Result struct {
+	Name        string        `json:"name"`
+	Ops         uint64        `json:"ops"`
+	Duration    time.Duration `json:"duration"`
+	OpsPerSec   float64       `json:"ops_per_sec"`
+	AvgLatency  time.Duration `json:"avg_latency"`
+	P99Latency  time.Duration `json:"p99_latency"`
+	AllocsPerOp float64       `json:"allocs_per_op"`
+	BytesPerOp  float64       `json:"bytes_per_op"`
+}
+
+// benchmark は名前付きのベンチマーク
+type benchmark struct {
+	name string
+	run  func(ctx context.Context, cfg Config) []*recorder
+}
+
+// benchmarks は実行できるベンチマーク（実行順）
+var benchmarks = []benchmark{
+	{"node-get", func(ctx context.Context, cfg Config) []*recorder { return runNode(ctx, cfg, 0) }},
+	{"node-set", func(ctx context.Context, cfg Config) []*recorder { return runNode(ctx, cfg, 1) }},
+	{"node-mixed", func(ctx context.Context, cfg Config) []*recorder { return runNode(ctx, cfg, cfg.WriteRatio) }},
+	{"pool-submit", func(ctx context.Context, cfg Config) []*recorder { return runPool(ctx, cfg, 1) }},
+	{"pool-batch", func(ctx context.Context, cfg Config) []*recorder { return runPool(ctx, cfg, cfg.BatchSize) }},
+}
+
+// Names は実行できるベンチマークの名前を返す
+func Names() []string {
+	names := make([]string, len(benchmarks))
+	for i, b := range benchmarks {
+		names[i] = b.name
+	}
+	return names
+}
+
+// Run は names のベンチマーク（空の場合は全て）を順に実行する
+// ctx がキャンセルされた場合は実行済みの結果を返す
+func Run(ctx context.Context, cfg Config, names []string) ([]Result, error) {
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.Keys <= 0 {
+		cfg.Keys = 1
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+
+	selected := benchmarks
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			i := slices.IndexFunc(benchmarks, func(b benchmark) bool { return b.name == name })
+			if i < 0 {
+				return nil, fmt.Errorf("unknown benchmark: %s (available: %v)", name, Names())
+			}
+			selected = append(selected, benchmarks[i])
+		}
+	}
+
+	var results []Result
+	for _, b := range selected {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, measure(ctx, cfg, b))
+	}
+	return results, nil
+}
+
+// measure はベンチマークを実行し、操作数・レイテンシ・アロケーションを集計する
+func measure(ctx context.Context, cfg Config, b benchmark) Result {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	recorders := b.run(ctx, cfg)
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	result := Result{Name: b.name, Duration: elapsed}
+	var sum time.Duration
+	var samples []time.Duration
+	for _, r := range recorders {
+		result.Ops += r.ops
+		sum += r.sum
+		samples = append(samples, r.samples...)
+	}
+	if result.Ops == 0 {
+		return result
+	}
+
+	ops := float64(result.Ops)
+	result.OpsPerSec = ops / elapsed.Seconds()
+	result.AvgLatency = sum / time.Duration(result.Ops)
+	result.P99Latency = percentile(samples, 0.99)
+	result.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / ops
+	result.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / ops
+	return result
+}
+
+// recorder はゴルーチンごとの操作数とレイテンシ（共有のカウンタによる競合を避けるため）
+type recorder struct {
+	ops     uint64
+	sum     time.Duration
+	samples []time.Duration
+}
+
+// record は1回の操作のレイテンシを記録する
+func (r *recorder) record(d time.Duration) {
+	r.ops++
+	r.sum += d
+	if r.ops%latencySampleEvery == 0 && len(r.samples) < maxLatencySamples {
+		r.samples = append(r.samples, d)
+	}
+}
+
+// percentile はサンプルの p 分位の値を返す
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	slices.Sort(samples)
+	idx := int(float64(len(samples)) * p)
+	if idx >= len(samples) {
+		idx = len(samples) - 1
+	}
+	return samples[idx]
+}
+
+// runNode は Workers 個のゴルーチンから1つのノードに Get・Set を繰り返す
+// writeRatio は Set の比率（0で Get のみ、1で Set のみ）
+func runNode(ctx context.Context, cfg Config, writeRatio float64) []*recorder {
+	n := node.New("bench")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	keys := make([]string, cfg.Keys)
+	value := make([]byte, cfg.ValueSize)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+		_ = n.Set(keys[i], value)
+	}
+
+	recorders := make([]*recorder, cfg.Workers)
+	var wg sync.WaitGroup
+	for w := range recorders {
+		r := &recorder{}
+		recorders[w] = r
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(w), uint64(time.Now().UnixNano())))
+			for ctx.Err() == nil {
+				key := keys[rng.IntN(len(keys))]
+				start := time.Now()
+				if rng.Float64() < writeRatio {
+					_ = n.Set(key, value)
+				} else {
+					n.Get(key)
+				}
+				r.record(time.Since(start))
+			}
+		}()
+	}
+	wg.Wait()
+	return recorders
+}
+
+// runPool は空のジョブをワーカープールに送信し続ける
+// レイテンシは送信から実行開始までの時間。batch が2以上の場合は SubmitBatch で送信する
+func runPool(ctx context.Context, cfg Config, batch int) []*recorder {
+	poolConfig := worker.DefaultPoolConfig()
+	poolConfig.NumWorkers = cfg.Workers
+	pool := worker.NewPoolWithConfig(poolConfig)
+	pool.Start(context.Background())
+
+	// ジョブはどのワーカーでも実行されうるため、記録はアトミックに行う
+	var ops, sumNs atomic.Uint64
+	var mu sync.Mutex
+	sampled := &recorder{}
+
+	job := func(submitted time.Time) worker.Job {
+		return func(context.Context) {
+			d := time.Since(submitted)
+			n := ops.Add(1)
+			sumNs.Add(uint64(d))
+			if n%latencySampleEvery == 0 {
+				mu.Lock()
+				if len(sampled.samples) < maxLatencySamples {
+					sampled.samples = append(sampled.samples, d)
+				}
+				mu.Unlock()
+			}
+		}
+	}
+
+	jobs := make([]worker.Job, batch)
+	for ctx.Err() == nil {
+		now := time.Now()
+		if batch == 1 {
+			pool.Submit(job(now))
+			continue
+		}
+		for i := range jobs {
+			jobs[i] = job(now)
+		}
+		pool.SubmitBatch(jobs)
+	}
+
+	// キューに残ったジョブは計測に含めない
+	pool.Stop()
+
+	sampled.ops = ops.Load()
+	sampled.sum = time.Duration(sumNs.Load())
+	return []*recorder{sampled}
+}
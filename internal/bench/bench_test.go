@@ -0,0 +1,69 @@
+package bench
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Duration = 50 * time.Millisecond
+	cfg.Workers = 2
+	cfg.Keys = 100
+
+	results, err := Run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != len(Names()) {
+		t.Fatalf("expected %d results, got %d", len(Names()), len(results))
+	}
+	for i, r := range results {
+		if r.Name != Names()[i] {
+			t.Errorf("result %d: expected %s, got %s", i, Names()[i], r.Name)
+		}
+		if r.Ops == 0 || r.OpsPerSec <= 0 {
+			t.Errorf("%s: expected operations, got %+v", r.Name, r)
+		}
+		if r.AvgLatency <= 0 {
+			t.Errorf("%s: expected positive average latency, got %v", r.Name, r.AvgLatency)
+		}
+	}
+}
+
+func TestRunSelected(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Duration = 20 * time.Millisecond
+
+	results, err := Run(context.Background(), cfg, []string{"pool-batch", "node-get"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Name != "pool-batch" || results[1].Name != "node-get" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	if _, err := Run(context.Background(), cfg, []string{"disk"}); err == nil {
+		t.Error("expected error for unknown benchmark")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if results, _ := Run(ctx, cfg, nil); len(results) != 0 {
+		t.Errorf("expected no results after cancellation, got %d", len(results))
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i))
+	}
+	if got := percentile(samples, 0.99); got != 100 {
+		t.Errorf("p99 = %v, want 100", got)
+	}
+	if got := percentile(nil, 0.99); got != 0 {
+		t.Errorf("p99 of no samples = %v, want 0", got)
+	}
+}
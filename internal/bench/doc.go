@@ -0,0 +1,30 @@
+// Package bench benchmarks the core data path in isolation.
+//
+// Unlike a scenario, a benchmark runs without chaos injection, recovery or
+// the client load generator, so changes in the raw performance of the node
+// store and the worker pool are not hidden by scenario noise.
+//
+// # Benchmarks
+//
+//   - node-get, node-set, node-mixed: Workers goroutines issue Get/Set on a
+//     single node over Keys keys (node-mixed uses WriteRatio)
+//   - pool-submit, pool-batch: empty jobs submitted to a worker pool one at a
+//     time or BatchSize at a time; latency is the time from submission to the
+//     start of execution
+//
+// # Usage
+//
+//	cfg := bench.DefaultConfig()
+//	cfg.Duration = time.Second
+//	results, err := bench.Run(ctx, cfg, []string{"node-get", "pool-submit"})
+//	for _, r := range results {
+//	    fmt.Printf("%s: %.0f ops/s, p99 %v, %.1f allocs/op\n",
+//	        r.Name, r.OpsPerSec, r.P99Latency, r.AllocsPerOp)
+//	}
+//
+// Each result reports operations per second, average and P99 latency (which
+// include the cost of timing each operation), and heap allocations per
+// operation measured with runtime.ReadMemStats.
+//
+// The command line exposes this as chaos-kvs bench.
+package bench
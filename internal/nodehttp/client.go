@@ -0,0 +1,138 @@
+package nodehttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// Client はノードのHTTPサーバーにリクエストするクライアント
+// node.Node の GetContext・SetContext と同じ形で呼び出せる
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient は baseURL（Server.URL）のサーバーにリクエストするクライアントを作成する
+// maxIdleConns は再利用のために保持する接続の数（通常は並行してリクエストする数）
+func NewClient(baseURL string, maxIdleConns int) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = maxIdleConns
+	return &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Transport: transport},
+	}
+}
+
+// keyURL はキーのURLを返す
+func (c *Client) keyURL(key string) string {
+	return c.baseURL + "/kv/" + url.PathEscape(key)
+}
+
+// do はリクエストを送り、本文を読み切った応答を返す
+func (c *Client) do(ctx context.Context, method, key string, body []byte) (int, []byte, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.keyURL(key), r)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
+
+// GetContext はキーの値を取得する。キーがない場合は false を返す
+func (c *Client) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	status, data, err := c.do(ctx, http.MethodGet, key, nil)
+	switch {
+	case err != nil:
+		return nil, false, err
+	case status == http.StatusNotFound:
+		return nil, false, nil
+	case status != http.StatusOK:
+		return nil, false, statusError(status, data)
+	}
+	return data, true, nil
+}
+
+// SetContext はキーに値を設定する
+func (c *Client) SetContext(ctx context.Context, key string, value []byte) error {
+	status, data, err := c.do(ctx, http.MethodPut, key, value)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent {
+		return statusError(status, data)
+	}
+	return nil
+}
+
+// Delete はキーを削除する
+func (c *Client) Delete(ctx context.Context, key string) error {
+	status, data, err := c.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent {
+		return statusError(status, data)
+	}
+	return nil
+}
+
+// SetDelay はノードの遅延を設定する（0で解除、サーバーの Config.Control が必要）
+func (c *Client) SetDelay(ctx context.Context, d time.Duration) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/delay", strings.NewReader(d.String()))
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return statusError(resp.StatusCode, data)
+	}
+	return nil
+}
+
+// Close は保持している接続を閉じる
+func (c *Client) Close() {
+	c.http.CloseIdleConnections()
+}
+
+// statusError は想定外のステータスの応答をエラーにする
+// メモリの上限による拒否（507）は node.ErrCapacity を、読み取り専用による拒否（403）は node.ErrReadOnly を包む
+func statusError(status int, body []byte) error {
+	switch status {
+	case http.StatusInsufficientStorage:
+		return fmt.Errorf("%w: %s", node.ErrCapacity, bytes.TrimSpace(body))
+	case http.StatusForbidden:
+		return fmt.Errorf("%w: %s", node.ErrReadOnly, bytes.TrimSpace(body))
+	}
+	return fmt.Errorf("unexpected status %d: %s", status, bytes.TrimSpace(body))
+}
@@ -0,0 +1,105 @@
+package nodehttp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClient(t *testing.T) {
+	s, n := startTestServer(t)
+	c := NewClient(s.URL(), 4)
+	defer c.Close()
+	ctx := context.Background()
+
+	if _, ok, err := c.GetContext(ctx, "missing"); err != nil || ok {
+		t.Errorf("expected miss without error, got ok=%v err=%v", ok, err)
+	}
+	if err := c.SetContext(ctx, "a b/c", []byte("value")); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if value, ok := n.Get("a b/c"); !ok || string(value) != "value" {
+		t.Errorf("expected key with escaped characters to be stored, got %q (exists: %v)", value, ok)
+	}
+	value, ok, err := c.GetContext(ctx, "a b/c")
+	if err != nil || !ok || string(value) != "value" {
+		t.Errorf("expected value, got %q ok=%v err=%v", value, ok, err)
+	}
+	if err := c.Delete(ctx, "a b/c"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := n.Get("a b/c"); ok {
+		t.Error("expected key to be deleted")
+	}
+}
+
+func TestClientNodeStopped(t *testing.T) {
+	s, n := startTestServer(t)
+	c := NewClient(s.URL(), 4)
+	defer c.Close()
+	ctx := context.Background()
+
+	if err := c.SetContext(ctx, "k", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetContext(ctx, "k"); err == nil {
+		t.Error("expected error for stopped node")
+	}
+	if err := c.SetContext(ctx, "k", []byte("v")); err == nil {
+		t.Error("expected error for stopped node")
+	}
+}
+
+func TestClientContextCanceled(t *testing.T) {
+	s, n := startTestServer(t)
+	n.SetDelay(time.Second)
+	c := NewClient(s.URL(), 4)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.GetContext(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClientCapacity(t *testing.T) {
+	s, n := startTestServer(t)
+	c := NewClient(s.URL(), 4)
+	defer c.Close()
+
+	n.SetLimits(node.Limits{MaxMemory: 1})
+	if err := c.SetContext(context.Background(), "k", []byte("v")); !errors.Is(err, node.ErrCapacity) {
+		t.Errorf("expected ErrCapacity for a full node, got %v", err)
+	}
+}
+
+func TestClientReadOnly(t *testing.T) {
+	s, n := startTestServer(t)
+	c := NewClient(s.URL(), 4)
+	defer c.Close()
+
+	ctx := context.Background()
+	if err := c.SetContext(ctx, "k", []byte("v")); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if err := n.SetReadOnly(true); err != nil {
+		t.Fatalf("SetReadOnly failed: %v", err)
+	}
+
+	if value, ok, err := c.GetContext(ctx, "k"); err != nil || !ok || string(value) != "v" {
+		t.Errorf("expected a read-only node to answer reads, got %q, %v, %v", value, ok, err)
+	}
+	if err := c.SetContext(ctx, "k", []byte("w")); !errors.Is(err, node.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly for a write, got %v", err)
+	}
+	if err := c.Delete(ctx, "k"); !errors.Is(err, node.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly for a delete, got %v", err)
+	}
+}
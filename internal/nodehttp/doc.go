@@ -0,0 +1,33 @@
+// Package nodehttp exposes a single simulated node as a small HTTP key-value
+// service, so the load generator can reach nodes over a real loopback TCP
+// connection instead of calling them in-process.
+//
+// # Basic Usage
+//
+//	s := nodehttp.New(n, nodehttp.DefaultConfig())
+//	if err := s.Start(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer s.Stop()
+//
+//	c := nodehttp.NewClient(s.URL(), 64)
+//	defer c.Close()
+//	err := c.SetContext(ctx, "key", []byte("value"))
+//
+// # Endpoints
+//
+//   - GET /kv/{key} answers 200 with the raw value, or 404 for a missing key
+//   - PUT /kv/{key} stores the request body and answers 204
+//   - DELETE /kv/{key} answers 204
+//
+// # Chaos
+//
+// Faults injected into the node surface as network conditions: a delayed
+// node holds the HTTP response, so the client sees real latency on the
+// socket, and a stopped or suspended node aborts the connection with a TCP
+// reset instead of answering, the way a crashed process would look to a
+// remote client. Client turns a reset into an error, while the in-process
+// node API reports a read from a stopped node as a miss. A read-only node
+// keeps answering reads and refuses writes with 403, which Client reports
+// as an error wrapping node.ErrReadOnly.
+package nodehttp
@@ -0,0 +1,229 @@
+package nodehttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// log はコンポーネント名 "nodehttp" を付けてログを出力する子ロガー
+var log = logger.With("nodehttp")
+
+// maxValueSize は PUT で受け付ける値の最大サイズ
+const maxValueSize = 16 << 20
+
+// Config はノードのHTTPサーバーの設定
+type Config struct {
+	Addr string // リッスンアドレス（デフォルトはループバックの空いているポート）
+
+	// Control は PUT /delay でノードの遅延を設定できるようにするか（別のプロセスで動かすノード用）
+	Control bool
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Addr: "127.0.0.1:0",
+	}
+}
+
+// Server は1つのノードのKVSをHTTPで公開するサーバー
+//
+//	GET    /kv/{key}  200 と値、キーがなければ 404
+//	PUT    /kv/{key}  本文を値として設定し 204
+//	DELETE /kv/{key}  204
+//	PUT    /delay     本文（10ms などの時間、0で解除）をノードの遅延として設定し 204（Config.Control の場合のみ）
+//
+// ノードが稼働中でない（停止・一時停止）場合は応答せずに接続をリセットする
+type Server struct {
+	node   *node.Node
+	config Config
+
+	running  atomic.Bool
+	listener net.Listener
+	server   *http.Server
+	wg       sync.WaitGroup
+
+	resets atomic.Uint64
+}
+
+// New はノード n を公開するHTTPサーバーを作成する
+func New(n *node.Node, config Config) *Server {
+	return &Server{node: n, config: config}
+}
+
+// Handler はルーティングを設定したハンドラーを返す
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /kv/{key}", s.handleGet)
+	mux.HandleFunc("PUT /kv/{key}", s.handlePut)
+	mux.HandleFunc("DELETE /kv/{key}", s.handleDelete)
+	if s.config.Control {
+		mux.HandleFunc("PUT /delay", s.handleDelay)
+	}
+	return mux
+}
+
+// Start はリッスンを開始し、バックグラウンドでリクエストを処理する
+// ctx が終了するか Stop を呼ぶまで処理する
+func (s *Server) Start(ctx context.Context) error {
+	if s.running.Swap(true) {
+		return fmt.Errorf("http server for %s is already running", s.node.ID())
+	}
+
+	lis, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		s.running.Store(false)
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+	s.listener = lis
+	s.server = &http.Server{
+		Handler:     s.Handler(),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(s.node.ID(), "HTTP server failed: %v", err)
+		}
+	}()
+
+	log.Info(s.node.ID(), "HTTP server listening on %s", lis.Addr())
+	return nil
+}
+
+// Stop はサーバーを停止する。処理中のリクエストの接続も閉じる
+func (s *Server) Stop() {
+	if !s.running.Swap(false) {
+		return
+	}
+	_ = s.server.Close()
+	s.wg.Wait()
+
+	log.Info(s.node.ID(), "HTTP server stopped (connection resets: %d)", s.resets.Load())
+}
+
+// URL はサーバーのベースURLを返す（開始前は空）
+func (s *Server) URL() string {
+	if s.listener == nil {
+		return ""
+	}
+	return "http://" + s.listener.Addr().String()
+}
+
+// Resets はノードが稼働中でないためにリセットした接続の数を返す
+func (s *Server) Resets() uint64 {
+	return s.resets.Load()
+}
+
+// handleGet はキーの値を返す
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !s.available(w) {
+		return
+	}
+	value, ok, err := s.node.GetContext(r.Context(), r.PathValue("key"))
+	if errors.Is(err, node.ErrOverloaded) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		return // クライアントの切断
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(value)
+}
+
+// handlePut は本文をキーの値として設定する
+func (s *Server) handlePut(w http.ResponseWriter, r *http.Request) {
+	value, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValueSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !s.available(w) {
+		return
+	}
+	if err := s.node.SetContext(r.Context(), r.PathValue("key"), value); errors.Is(err, node.ErrCapacity) {
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+		return
+	} else if errors.Is(err, node.ErrReadOnly) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDelete はキーを削除する
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !s.available(w) {
+		return
+	}
+	if err := s.node.DeleteContext(r.Context(), r.PathValue("key")); errors.Is(err, node.ErrReadOnly) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDelay はノードの遅延を設定する
+func (s *Server) handleDelay(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(string(body)))
+	if err != nil || d < 0 {
+		http.Error(w, "invalid delay: "+string(body), http.StatusBadRequest)
+		return
+	}
+	s.node.SetDelay(d)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// available はノードが稼働中（読み取り専用を含む）かを返す
+// 稼働中でない場合は、落ちたプロセスと同様にクライアントから見えるよう接続をリセット（RST）する
+func (s *Server) available(w http.ResponseWriter) bool {
+	if s.node.Status().Available() {
+		return true
+	}
+	s.resets.Add(1)
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "node is not running", http.StatusServiceUnavailable)
+		return false
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		http.Error(w, "node is not running", http.StatusServiceUnavailable)
+		return false
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0) // Close で FIN ではなく RST を送る
+	}
+	_ = conn.Close()
+	return false
+}
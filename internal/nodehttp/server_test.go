@@ -0,0 +1,192 @@
+package nodehttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// startTestServer はノードを起動し、空いているポートでHTTPサーバーを起動する
+func startTestServer(t *testing.T) (*Server, *node.Node) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	n := node.New("node-1")
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := New(n, DefaultConfig())
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(s.Stop)
+	return s, n
+}
+
+// request はリクエストを送り、ステータスと本文を返す
+func request(t *testing.T, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+func TestServerEndpoints(t *testing.T) {
+	s, n := startTestServer(t)
+	url := s.URL() + "/kv/foo"
+
+	if status, _ := request(t, http.MethodGet, url, ""); status != http.StatusNotFound {
+		t.Errorf("GET missing key: expected 404, got %d", status)
+	}
+	if status, _ := request(t, http.MethodPut, url, "bar"); status != http.StatusNoContent {
+		t.Errorf("PUT: expected 204, got %d", status)
+	}
+	if value, ok := n.Get("foo"); !ok || string(value) != "bar" {
+		t.Errorf("expected node to store bar, got %q (exists: %v)", value, ok)
+	}
+	if status, body := request(t, http.MethodGet, url, ""); status != http.StatusOK || body != "bar" {
+		t.Errorf("GET: expected 200 bar, got %d %q", status, body)
+	}
+	if status, _ := request(t, http.MethodDelete, url, ""); status != http.StatusNoContent {
+		t.Errorf("DELETE: expected 204, got %d", status)
+	}
+	if _, ok := n.Get("foo"); ok {
+		t.Error("expected key to be deleted")
+	}
+	if status, _ := request(t, http.MethodPost, url, "x"); status != http.StatusMethodNotAllowed {
+		t.Errorf("POST: expected 405, got %d", status)
+	}
+}
+
+func TestServerResetsWhenNodeStopped(t *testing.T) {
+	s, n := startTestServer(t)
+	if err := n.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := http.Get(s.URL() + "/kv/foo")
+	if err == nil {
+		t.Fatal("expected connection error for suspended node")
+	}
+	if s.Resets() != 1 {
+		t.Errorf("expected 1 reset, got %d", s.Resets())
+	}
+
+	if err := n.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := request(t, http.MethodGet, s.URL()+"/kv/foo", ""); status != http.StatusNotFound {
+		t.Errorf("expected 404 after resume, got %d", status)
+	}
+}
+
+func TestServerDelay(t *testing.T) {
+	s, n := startTestServer(t)
+	n.SetDelay(50 * time.Millisecond)
+
+	start := time.Now()
+	request(t, http.MethodGet, s.URL()+"/kv/foo", "")
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected response to be delayed, took %v", elapsed)
+	}
+}
+
+func TestServerOverloaded(t *testing.T) {
+	s, n := startTestServer(t)
+	n.SetDelay(300 * time.Millisecond)
+	n.SetAdmission(node.Admission{MaxInFlight: 1})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = n.GetContext(context.Background(), "foo")
+	}()
+	for n.InFlight() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if status, _ := request(t, http.MethodGet, s.URL()+"/kv/foo", ""); status != http.StatusServiceUnavailable {
+		t.Errorf("GET on an overloaded node: expected 503, got %d", status)
+	}
+	<-done
+}
+
+func TestServerControl(t *testing.T) {
+	s, n := startTestServer(t)
+	if status, _ := request(t, http.MethodPut, s.URL()+"/delay", "10ms"); status != http.StatusMethodNotAllowed && status != http.StatusNotFound {
+		t.Errorf("expected delay endpoint to be disabled by default, got %d", status)
+	}
+
+	config := DefaultConfig()
+	config.Control = true
+	control := New(n, config)
+	if err := control.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer control.Stop()
+
+	if status, _ := request(t, http.MethodPut, control.URL()+"/delay", "30ms"); status != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", status)
+	}
+	if n.Delay() != 30*time.Millisecond {
+		t.Errorf("expected delay 30ms, got %v", n.Delay())
+	}
+	if status, _ := request(t, http.MethodPut, control.URL()+"/delay", "soon"); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid delay, got %d", status)
+	}
+
+	c := NewClient(control.URL(), 1)
+	defer c.Close()
+	if err := c.SetDelay(context.Background(), 0); err != nil || n.Delay() != 0 {
+		t.Errorf("expected delay to be cleared, got %v (%v)", n.Delay(), err)
+	}
+}
+
+func TestServerStartStop(t *testing.T) {
+	n := node.New("node-1")
+	s := New(n, DefaultConfig())
+	if s.URL() != "" {
+		t.Errorf("expected empty URL before start, got %q", s.URL())
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err == nil {
+		t.Error("expected error on second start")
+	}
+	url := s.URL()
+	s.Stop()
+	s.Stop() // 2回目は何もしない
+
+	if _, err := http.Get(url + "/kv/foo"); err == nil {
+		t.Error("expected connection error after stop")
+	}
+}
+
+func TestServerListenError(t *testing.T) {
+	s, _ := startTestServer(t)
+	config := DefaultConfig()
+	config.Addr = strings.TrimPrefix(s.URL(), "http://")
+
+	other := New(node.New("node-2"), config)
+	if err := other.Start(context.Background()); err == nil {
+		other.Stop()
+		t.Fatal("expected listen error for address in use")
+	}
+}
@@ -0,0 +1,32 @@
+// Package procnode runs each node as a separate OS process, so that a kill
+// attack terminates a real process and recovery restarts it, losing the data
+// held in memory as a restarted service would.
+//
+// # Basic Usage
+//
+//	config := procnode.DefaultConfig()
+//	config.ID = "node-1"
+//	config.Command = []string{exe, "node-server"} // exe runs procnode.RunServer
+//
+//	n := node.New(config.ID)
+//	p := procnode.New(config)
+//	n.SetBackend(p)
+//	if err := n.Start(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	// send the load to p.URL() with a nodehttp.Client
+//
+// A Process is a node.Backend. Start spawns the command with --id and --addr
+// appended and waits for the child to report the address it listens on; the
+// child keeps that address across restarts so clients need not reconnect
+// elsewhere. Stop kills the process (SIGKILL), Suspend and Resume send SIGSTOP
+// and SIGCONT (Unix only), and SetDelay is forwarded to the child over HTTP.
+//
+// # Child Processes
+//
+// The child runs RunServer, which serves the node with nodehttp and its
+// control endpoint until it receives SIGINT or SIGTERM. It writes one line,
+// "listening on <addr>", to standard output once it is ready, and its logs
+// (warnings and above) to standard error. On Linux the child is killed when
+// the parent exits.
+package procnode
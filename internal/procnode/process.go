@@ -0,0 +1,263 @@
+package procnode
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/nodehttp"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// log はコンポーネント名 "procnode" を付けてログを出力する子ロガー
+var log = logger.With("procnode")
+
+// Config はノードのプロセスの設定
+type Config struct {
+	ID      string   // ノードID
+	Command []string // 子プロセスのコマンドと引数（--id・--addr を追加して実行し、RunServer を動かす）
+
+	// Addr は初回の起動で子プロセスが待ち受けるアドレス（再起動では初回と同じアドレスを使う）
+	Addr string
+
+	// StartTimeout は子プロセスが待ち受けを開始するまでの上限時間
+	StartTimeout time.Duration
+
+	// ControlTimeout は子プロセスへの遅延の設定の上限時間
+	ControlTimeout time.Duration
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Addr:           "127.0.0.1:0",
+		StartTimeout:   10 * time.Second,
+		ControlTimeout: 5 * time.Second,
+	}
+}
+
+// Process はノードを動かす子プロセス
+type Process struct {
+	config Config
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	exited  chan struct{} // 実行中の子プロセスが終了すると閉じる
+	addr    string        // 子プロセスが待ち受けるアドレス（初回の起動で決まる）
+	control *nodehttp.Client
+}
+
+// Ensure Process implements node.Backend
+var _ node.Backend = (*Process)(nil)
+
+// New はノードのプロセスを作成する（起動はしない）
+func New(config Config) *Process {
+	return &Process{config: config}
+}
+
+// ID はノードIDを返す
+func (p *Process) ID() string {
+	return p.config.ID
+}
+
+// Addr は子プロセスが待ち受けるアドレスを返す（初回の起動前は空）
+func (p *Process) Addr() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.addr
+}
+
+// URL は子プロセスのHTTPサーバーのベースURLを返す（初回の起動前は空）
+func (p *Process) URL() string {
+	if addr := p.Addr(); addr != "" {
+		return "http://" + addr
+	}
+	return ""
+}
+
+// Pid は実行中の子プロセスのIDを返す（実行中でない場合は0）
+func (p *Process) Pid() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.aliveLocked() {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// Start は子プロセスを起動し、待ち受けを開始するまで待つ
+func (p *Process) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.aliveLocked() {
+		return nil
+	}
+	if len(p.config.Command) == 0 {
+		return fmt.Errorf("no command configured for node process %s", p.config.ID)
+	}
+	addr := p.addr
+	if addr == "" {
+		addr = p.config.Addr
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	args := append(append([]string(nil), p.config.Command[1:]...), "--id", p.config.ID, "--addr", addr)
+	cmd := exec.Command(p.config.Command[0], args...)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = sysProcAttr()
+	err = cmd.Start()
+	_ = w.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start node process %s: %w", p.config.ID, err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	listening, err := waitReady(ctx, r, exited, p.config.StartTimeout)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		<-exited
+		return fmt.Errorf("node process %s did not start: %w", p.config.ID, err)
+	}
+
+	p.cmd, p.exited = cmd, exited
+	if p.addr == "" {
+		p.addr = listening
+		p.control = nodehttp.NewClient("http://"+listening, 1)
+	}
+	log.Debug(p.config.ID, "Node process started (pid: %d, addr: %s)", cmd.Process.Pid, listening)
+	return nil
+}
+
+// waitReady は子プロセスが待ち受けを知らせる行を読み、アドレスを返す
+// 以降の標準出力は読み捨てる
+func waitReady(ctx context.Context, r *os.File, exited <-chan struct{}, timeout time.Duration) (string, error) {
+	lines := make(chan string, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if addr, ok := strings.CutPrefix(scanner.Text(), readyPrefix); ok {
+				lines <- addr
+				break
+			}
+		}
+	}()
+
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+	select {
+	case addr, ok := <-lines:
+		if !ok {
+			return "", errors.New("process exited before listening")
+		}
+		return addr, nil
+	case <-exited:
+		return "", errors.New("process exited before listening")
+	case <-timer:
+		return "", fmt.Errorf("timed out after %v", timeout)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Stop は子プロセスを強制終了（SIGKILL）し、終了を待つ。実行中でない場合は何もしない
+func (p *Process) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.aliveLocked() {
+		return nil
+	}
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill node process %s: %w", p.config.ID, err)
+	}
+	<-p.exited
+	log.Debug(p.config.ID, "Node process killed (pid: %d)", p.cmd.Process.Pid)
+	return nil
+}
+
+// Suspend は子プロセスを一時停止する（SIGSTOP）
+// プロセスが実際に停止するまで待ってから返す
+func (p *Process) Suspend() error {
+	if err := p.signal(suspendProcess); err != nil {
+		return err
+	}
+	if err := waitStopped(p.Pid(), p.config.ControlTimeout); err != nil {
+		return fmt.Errorf("failed to suspend node process %s: %w", p.config.ID, err)
+	}
+	return nil
+}
+
+// Resume は一時停止した子プロセスを再開する（SIGCONT）
+func (p *Process) Resume() error {
+	return p.signal(resumeProcess)
+}
+
+// signal は実行中の子プロセスに send でシグナルを送る
+func (p *Process) signal(send func(*os.Process) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.aliveLocked() {
+		return fmt.Errorf("node process %s is not running", p.config.ID)
+	}
+	return send(p.cmd.Process)
+}
+
+// SetDelay は子プロセスのノードに遅延を設定する（0で解除）
+func (p *Process) SetDelay(d time.Duration) error {
+	p.mu.Lock()
+	control := p.control
+	alive := p.aliveLocked()
+	p.mu.Unlock()
+
+	if !alive || control == nil {
+		return fmt.Errorf("node process %s is not running", p.config.ID)
+	}
+	ctx := context.Background()
+	if p.config.ControlTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.ControlTimeout)
+		defer cancel()
+	}
+	if err := control.SetDelay(ctx, d); err != nil {
+		return fmt.Errorf("failed to set delay on node process %s: %w", p.config.ID, err)
+	}
+	return nil
+}
+
+// aliveLocked は子プロセスが実行中かを返す（mu を保持して呼ぶ）
+func (p *Process) aliveLocked() bool {
+	if p.cmd == nil {
+		return false
+	}
+	select {
+	case <-p.exited:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,185 @@
+package procnode
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/nodehttp"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// helperEnv はテストバイナリを子プロセスのノードとして動かす環境変数
+const helperEnv = "CHAOS_KVS_PROCNODE_HELPER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		if err := RunServer(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestProcess はテストバイナリ自身を子プロセスとして起動するプロセスを作成する
+func newTestProcess(t *testing.T) *Process {
+	t.Helper()
+	t.Setenv(helperEnv, "1")
+
+	config := DefaultConfig()
+	config.ID = "node-1"
+	config.Command = []string{os.Args[0]}
+	p := New(config)
+	t.Cleanup(func() { _ = p.Stop() })
+	return p
+}
+
+func TestProcessLifecycle(t *testing.T) {
+	p := newTestProcess(t)
+	ctx := context.Background()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p.Pid() == 0 || p.Addr() == "" {
+		t.Fatalf("expected running process, got pid %d addr %q", p.Pid(), p.Addr())
+	}
+	addr := p.Addr()
+
+	c := nodehttp.NewClient(p.URL(), 1)
+	defer c.Close()
+	if err := c.SetContext(ctx, "key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 強制終了でメモリ上のデータは失われる
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Pid() != 0 {
+		t.Error("expected process to be stopped")
+	}
+	if _, _, err := c.GetContext(ctx, "key"); err == nil {
+		t.Error("expected request to a killed process to fail")
+	}
+
+	// 再起動しても同じアドレスで待ち受ける
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p.Addr() != addr {
+		t.Errorf("expected restart on %s, got %s", addr, p.Addr())
+	}
+	if _, found, err := c.GetContext(ctx, "key"); err != nil || found {
+		t.Errorf("expected empty node after restart, got found=%v err=%v", found, err)
+	}
+
+	// 停止済みのプロセスの停止は何もしない
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Stop(); err != nil {
+		t.Errorf("expected second stop to succeed: %v", err)
+	}
+}
+
+func TestProcessSuspendResume(t *testing.T) {
+	p := newTestProcess(t)
+	ctx := context.Background()
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	c := nodehttp.NewClient(p.URL(), 1)
+	defer c.Close()
+
+	if err := p.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	_, _, err := c.GetContext(reqCtx, "key")
+	cancel()
+	if err == nil {
+		t.Error("expected request to a suspended process to time out")
+	}
+
+	if err := p.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetContext(ctx, "key"); err != nil {
+		t.Errorf("expected request after resume to succeed: %v", err)
+	}
+}
+
+func TestProcessSetDelay(t *testing.T) {
+	p := newTestProcess(t)
+	ctx := context.Background()
+
+	if err := p.SetDelay(time.Millisecond); err == nil {
+		t.Error("expected delay on a process that is not running to fail")
+	}
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetDelay(100 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	c := nodehttp.NewClient(p.URL(), 1)
+	defer c.Close()
+	start := time.Now()
+	if _, _, err := c.GetContext(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected delayed response, took %v", elapsed)
+	}
+}
+
+func TestProcessAsNodeBackend(t *testing.T) {
+	p := newTestProcess(t)
+	n := node.New(p.ID())
+	n.SetBackend(p)
+
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if p.Pid() == 0 {
+		t.Fatal("expected node start to spawn the process")
+	}
+	if err := n.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Pid() != 0 {
+		t.Error("expected node stop to kill the process")
+	}
+}
+
+func TestProcessStartErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.ID = "node-1"
+	if err := New(config).Start(context.Background()); err == nil || !strings.Contains(err.Error(), "no command") {
+		t.Errorf("expected missing command error, got %v", err)
+	}
+
+	// 待ち受けを知らせずに終了するコマンド
+	config.Command = []string{"sh", "-c", "exit 0", "sh"}
+	if err := New(config).Start(context.Background()); err == nil || !strings.Contains(err.Error(), "exited before listening") {
+		t.Errorf("expected early exit error, got %v", err)
+	}
+
+	// 待ち受けを知らせないコマンド
+	config.Command = []string{"sh", "-c", "exec sleep 5", "sh"}
+	config.StartTimeout = 100 * time.Millisecond
+	start := time.Now()
+	if err := New(config).Start(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected start timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("process was not killed on timeout (took %v)", elapsed)
+	}
+}
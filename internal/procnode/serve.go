@@ -0,0 +1,62 @@
+package procnode
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/nyasuto/chaos-kvs/internal/nodehttp"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// readyPrefix は子プロセスが待ち受けを開始したことを知らせる行の接頭辞
+const readyPrefix = "listening on "
+
+// Serve はノード id を起動して addr でHTTPで公開し、ctx が終了するまで処理する
+// 待ち受けを開始したら、そのアドレスを ready に1行で書き出す
+func Serve(ctx context.Context, id, addr string, ready io.Writer) error {
+	n := node.New(id)
+	if err := n.Start(ctx); err != nil {
+		return err
+	}
+	defer func() { _ = n.Stop() }()
+
+	config := nodehttp.DefaultConfig()
+	config.Addr = addr
+	config.Control = true
+	s := nodehttp.New(n, config)
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+	defer s.Stop()
+
+	if _, err := fmt.Fprintf(ready, "%s%s\n", readyPrefix, strings.TrimPrefix(s.URL(), "http://")); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// RunServer は子プロセスとして引数（--id・--addr）を解析し、SIGINT・SIGTERM を受けるまで Serve を実行する
+// 標準出力は待ち受けの通知のみに使い、ログ（警告以上）は標準エラー出力に書き出す
+func RunServer(args []string) error {
+	fs := flag.NewFlagSet("node-server", flag.ContinueOnError)
+	id := fs.String("id", "node", "ノードID")
+	addr := fs.String("addr", "127.0.0.1:0", "リッスンアドレス")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	logger.Default.SetOutput(os.Stderr)
+	logger.Default.SetLevel(logger.LevelWarn)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return Serve(ctx, *id, *addr, os.Stdout)
+}
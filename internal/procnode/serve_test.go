@@ -0,0 +1,58 @@
+package procnode
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/nodehttp"
+)
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, "node-1", "127.0.0.1:0", w)
+		_ = w.Close()
+	}()
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, ok := strings.CutPrefix(strings.TrimSpace(line), readyPrefix)
+	if !ok {
+		t.Fatalf("unexpected ready line %q", line)
+	}
+
+	c := nodehttp.NewClient("http://"+addr, 1)
+	defer c.Close()
+	if err := c.SetContext(ctx, "key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	// 制御用のエンドポイントが有効
+	if err := c.SetDelay(ctx, 0); err != nil {
+		t.Errorf("expected control endpoint: %v", err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after cancel")
+	}
+}
+
+func TestRunServerInvalidFlag(t *testing.T) {
+	if err := RunServer([]string{"--unknown"}); err == nil {
+		t.Error("expected invalid flag error")
+	}
+}
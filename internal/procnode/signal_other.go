@@ -0,0 +1,21 @@
+//go:build !unix
+
+package procnode
+
+import (
+	"errors"
+	"os"
+)
+
+// errSuspendUnsupported はプロセスの一時停止ができないプラットフォームのエラー
+var errSuspendUnsupported = errors.New("suspending a node process is not supported on this platform")
+
+// suspendProcess はプロセスを一時停止する（このプラットフォームでは未対応）
+func suspendProcess(*os.Process) error {
+	return errSuspendUnsupported
+}
+
+// resumeProcess は一時停止したプロセスを再開する（このプラットフォームでは未対応）
+func resumeProcess(*os.Process) error {
+	return errSuspendUnsupported
+}
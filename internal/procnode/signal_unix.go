@@ -0,0 +1,18 @@
+//go:build unix
+
+package procnode
+
+import (
+	"os"
+	"syscall"
+)
+
+// suspendProcess はプロセスを一時停止する
+func suspendProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGSTOP)
+}
+
+// resumeProcess は一時停止したプロセスを再開する
+func resumeProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGCONT)
+}
@@ -0,0 +1,54 @@
+package procnode
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// sysProcAttr は親プロセスの終了時に子プロセスも終了させる属性を返す
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+}
+
+// waitStopped はプロセスの全スレッドが SIGSTOP で停止するまで待つ
+// シグナルは非同期に届くため、送信直後のリクエストが処理されないようにする
+func waitStopped(pid int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		stopped, err := allThreadsStopped(pid)
+		if err != nil || stopped {
+			return err
+		}
+		if timeout > 0 && time.Now().After(deadline) {
+			return fmt.Errorf("process %d did not stop within %v", pid, timeout)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// allThreadsStopped は /proc/<pid>/task 以下の全スレッドの状態が停止（T）かを返す
+func allThreadsStopped(pid int) (bool, error) {
+	stats, err := filepath.Glob(fmt.Sprintf("/proc/%d/task/*/stat", pid))
+	if err != nil || len(stats) == 0 {
+		return false, fmt.Errorf("failed to read threads of process %d", pid)
+	}
+	for _, path := range stats {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		// 形式は "pid (comm) state ..."（comm は空白や括弧を含みうるため最後の ')' の後を読む）
+		i := bytes.LastIndexByte(data, ')')
+		if i < 0 || i+2 >= len(data) {
+			return false, fmt.Errorf("unexpected format of %s", path)
+		}
+		if data[i+2] != 'T' {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package procnode
+
+import (
+	"syscall"
+	"time"
+)
+
+// sysProcAttr は子プロセスの属性を返す（Linux 以外では親の終了に合わせた終了はしない）
+func sysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// waitStopped はプロセスが停止するまで待つ（Linux 以外では停止を確認できないため待たない）
+func waitStopped(int, time.Duration) error {
+	return nil
+}
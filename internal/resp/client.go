@@ -0,0 +1,216 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// Client はRESPサーバー（Redis など）にGET・SET・DELを送るクライアント
+// node.Node の GetContext・SetContext と同じ形で呼び出せるため、外部のプロセスへの負荷生成に使う
+type Client struct {
+	addr    string
+	maxIdle int
+	dialer  net.Dialer
+
+	mu     sync.Mutex
+	idle   []*clientConn
+	closed bool
+}
+
+// clientConn はクライアントの1つの接続
+type clientConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    writer
+}
+
+// ServerError はサーバーが返したエラー応答（-ERR ...）
+// 接続は正常なため、再利用できる
+type ServerError string
+
+func (e ServerError) Error() string {
+	return string(e)
+}
+
+// Is はメモリの上限による拒否（-OOM ...）を node.ErrCapacity、読み取り専用による拒否（-READONLY ...）を node.ErrReadOnly とみなす
+func (e ServerError) Is(target error) bool {
+	switch target {
+	case node.ErrCapacity:
+		return strings.HasPrefix(string(e), "OOM ")
+	case node.ErrReadOnly:
+		return strings.HasPrefix(string(e), "READONLY ")
+	}
+	return false
+}
+
+// NewClient は addr のサーバーに接続するクライアントを作成する
+// maxIdle は再利用のために保持する接続の数（通常は並行してリクエストする数）
+func NewClient(addr string, maxIdle int) *Client {
+	return &Client{addr: addr, maxIdle: max(maxIdle, 1)}
+}
+
+// Addr は接続先のアドレスを返す
+func (c *Client) Addr() string {
+	return c.addr
+}
+
+// GetContext はキーの値を取得する。キーがない場合は false を返す
+func (c *Client) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+// SetContext はキーに値を設定する
+func (c *Client) SetContext(ctx context.Context, key string, value []byte) error {
+	_, err := c.do(ctx, "SET", key, string(value))
+	return err
+}
+
+// Delete はキーを削除する。キーがない場合もエラーにしない
+func (c *Client) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", key)
+	return err
+}
+
+// Ping はサーバーが応答するかを確かめる
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Close は保持している接続を閉じる。以降の呼び出しでは毎回接続し直す
+func (c *Client) Close() {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle = nil
+	c.closed = true
+	c.mu.Unlock()
+
+	for _, cc := range idle {
+		_ = cc.conn.Close()
+	}
+}
+
+// do はコマンドを送り、応答を返す。null の応答は nil、単純文字列・整数はその文字列を返す
+// 通信のエラー・ctx の終了の場合は接続を閉じ、再利用しない
+func (c *Client) do(ctx context.Context, args ...string) ([]byte, error) {
+	cc, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// ctx の終了で読み書きを中断する
+	deadline, hasDeadline := ctx.Deadline()
+	_ = cc.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		_ = cc.conn.SetDeadline(time.Now())
+	})
+
+	reply, err := cc.roundTrip(args)
+	interrupted := !stop()
+	if interrupted && ctx.Err() != nil {
+		err = ctx.Err()
+	} else if err != nil && hasDeadline && !time.Now().Before(deadline) {
+		// ctx の期限切れは、ctx が終了するより先に接続の期限切れとして現れうる
+		interrupted = true
+		err = context.DeadlineExceeded
+	}
+
+	var serverErr ServerError
+	if interrupted || (err != nil && !errors.As(err, &serverErr)) {
+		_ = cc.conn.Close()
+		return nil, err
+	}
+	c.put(cc)
+	return reply, err
+}
+
+// get は保持している接続を取り出す。なければ新しく接続する
+func (c *Client) get(ctx context.Context) (*clientConn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		cc := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cc, nil
+	}
+	c.mu.Unlock()
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	return &clientConn{conn: conn, r: bufio.NewReader(conn), w: newWriter(conn)}, nil
+}
+
+// put は接続を再利用のために保持する。上限を超える場合は閉じる
+func (c *Client) put(cc *clientConn) {
+	c.mu.Lock()
+	if !c.closed && len(c.idle) < c.maxIdle {
+		c.idle = append(c.idle, cc)
+		cc = nil
+	}
+	c.mu.Unlock()
+
+	if cc != nil {
+		_ = cc.conn.Close()
+	}
+}
+
+// roundTrip はコマンドをマルチバルク形式で送り、応答を1つ読み込む
+func (cc *clientConn) roundTrip(args []string) ([]byte, error) {
+	cc.w.arrayLen(len(args))
+	for _, arg := range args {
+		cc.w.bulk([]byte(arg))
+	}
+	if err := cc.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(cc.r)
+}
+
+// readReply は配列以外の応答を1つ読み込む
+func readReply(r *bufio.Reader) ([]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, protocolError("empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return append([]byte(nil), line[1:]...), nil
+	case '-':
+		return nil, ServerError(line[1:])
+	case '$':
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil || size > maxBulkLen {
+			return nil, protocolError("invalid bulk length")
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+		return value[:size], nil
+	default:
+		return nil, protocolError(fmt.Sprintf("unexpected reply '%s'", printable(line)))
+	}
+}
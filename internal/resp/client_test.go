@@ -0,0 +1,158 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/kvstest"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClient(t *testing.T) {
+	s, c := startTestServer(t, 2)
+	cl := NewClient(s.Addr(), 2)
+	defer cl.Close()
+	ctx := context.Background()
+
+	if err := cl.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if _, ok, err := cl.GetContext(ctx, "missing"); err != nil || ok {
+		t.Errorf("expected miss without error, got ok=%v err=%v", ok, err)
+	}
+	if err := cl.SetContext(ctx, "foo", []byte("bar\r\nbaz")); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	value, ok, err := cl.GetContext(ctx, "foo")
+	if err != nil || !ok || string(value) != "bar\r\nbaz" {
+		t.Errorf("expected value, got %q ok=%v err=%v", value, ok, err)
+	}
+	if err := cl.SetContext(ctx, "empty", nil); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok, err := cl.GetContext(ctx, "empty"); err != nil || !ok || len(value) != 0 {
+		t.Errorf("expected empty value, got %q ok=%v err=%v", value, ok, err)
+	}
+
+	if err := cl.Delete(ctx, "foo"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok, err := cl.GetContext(ctx, "foo"); err != nil || ok {
+		t.Errorf("expected deleted key to miss, got ok=%v err=%v", ok, err)
+	}
+	if err := cl.Delete(ctx, "foo"); err != nil {
+		t.Errorf("expected deleting a missing key to succeed, got %v", err)
+	}
+	if err := cl.SetContext(ctx, "foo", []byte("bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	// サーバーのエラー応答は ServerError として返し、接続は再利用する
+	for _, n := range c.Nodes() {
+		if err := n.Suspend(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var serverErr ServerError
+	if _, _, err := cl.GetContext(ctx, "foo"); !errors.As(err, &serverErr) {
+		t.Errorf("expected server error, got %v", err)
+	}
+	if len(cl.idle) != 1 {
+		t.Errorf("expected connection to be kept after a server error, got %d idle", len(cl.idle))
+	}
+}
+
+func TestClientContextCanceled(t *testing.T) {
+	s, c := startTestServer(t, 1)
+	c.Nodes()[0].SetDelay(time.Second)
+	cl := NewClient(s.Addr(), 2)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := cl.GetContext(ctx, "foo"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if len(cl.idle) != 0 {
+		t.Error("interrupted connection should not be reused")
+	}
+}
+
+func TestClientConnectionRefused(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	cl := NewClient(addr, 1)
+	if err := cl.Ping(context.Background()); err == nil {
+		t.Error("expected error for closed port")
+	}
+}
+
+// TestClientProperties はクラスタにシャーディングしたストアを kvstest で検査する
+func TestClientProperties(t *testing.T) {
+	config := kvstest.DefaultConfig()
+	config.Iterations = 20
+	config.Seed = 1
+	kvstest.Run(t, func(t testing.TB) kvstest.Store {
+		s, c := startTestServer(t, 3)
+		cl := NewClient(s.Addr(), 1)
+		t.Cleanup(cl.Close)
+		return kvstest.Funcs{
+			GetFunc:    cl.GetContext,
+			SetFunc:    cl.SetContext,
+			DeleteFunc: cl.Delete,
+			SizeFunc: func(context.Context) (int, error) {
+				size := 0
+				for _, n := range c.Nodes() {
+					size += n.Size()
+				}
+				return size, nil
+			},
+		}
+	}, config)
+}
+
+func TestClientCapacity(t *testing.T) {
+	s, c := startTestServer(t, 1)
+	cl := NewClient(s.Addr(), 1)
+	defer cl.Close()
+
+	c.Nodes()[0].SetLimits(node.Limits{MaxMemory: 1})
+	if err := cl.SetContext(context.Background(), "k", []byte("v")); !errors.Is(err, node.ErrCapacity) {
+		t.Errorf("expected ErrCapacity from an OOM reply, got %v", err)
+	}
+	if errors.Is(ServerError("ERR node is not running"), node.ErrCapacity) {
+		t.Error("expected other server errors not to match ErrCapacity")
+	}
+}
+
+func TestClientReadOnly(t *testing.T) {
+	s, c := startTestServer(t, 1)
+	cl := NewClient(s.Addr(), 1)
+	defer cl.Close()
+
+	ctx := context.Background()
+	if err := cl.SetContext(ctx, "k", []byte("v")); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if err := c.Nodes()[0].SetReadOnly(true); err != nil {
+		t.Fatalf("SetReadOnly failed: %v", err)
+	}
+
+	if value, ok, err := cl.GetContext(ctx, "k"); err != nil || !ok || string(value) != "v" {
+		t.Errorf("expected a read-only node to answer GET, got %q, %v, %v", value, ok, err)
+	}
+	if err := cl.SetContext(ctx, "k", []byte("w")); !errors.Is(err, node.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from a READONLY reply, got %v", err)
+	}
+	if err := cl.Delete(ctx, "k"); !errors.Is(err, node.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly for DEL, got %v", err)
+	}
+}
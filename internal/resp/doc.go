@@ -0,0 +1,40 @@
+// Package resp exposes simulated nodes over a subset of RESP, the Redis
+// serialization protocol, so real Redis clients and tools such as redis-cli
+// and redis-benchmark can be pointed at the simulated cluster.
+//
+// # Basic Usage
+//
+//	config := resp.DefaultConfig()
+//	config.Addr = ":6379"
+//	s := resp.New(config, resp.ClusterRouter(c))
+//	if err := s.Start(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer s.Stop()
+//
+// A Router decides which node handles a key. ClusterRouter spreads keys over
+// every node in a cluster with the cluster's consistent-hash cluster.Router
+// (adding or removing a node only moves the keys that node owns), and
+// NodeRouter sends every key to a single node, for one listener per node.
+//
+// # Commands
+//
+//   - PING [message], ECHO message
+//   - GET key, SET key value (no EX/NX/... options), DEL key [key ...]
+//   - QUIT
+//   - COMMAND and CONFIG reply with an empty array, so clients that probe the
+//     server on connect keep working
+//
+// Both multi-bulk requests and inline commands (PING\r\n, as sent by
+// redis-benchmark for the *_INLINE tests) are accepted; inline commands do
+// not support quoting. Pipelined commands are answered in a single write once
+// every buffered command has been processed.
+//
+// # Chaos
+//
+// Commands go through the same node API as the internal load generator, so
+// injected faults are visible to external clients: a delayed node answers
+// late, and commands for a stopped or suspended node fail with
+// "-ERR node <id> is <status>". A read-only node answers GET and refuses SET
+// and DEL with "-READONLY ...", as a Redis read-only replica does.
+package resp
@@ -0,0 +1,152 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// readBufferSize は接続ごとの読み込みバッファのサイズ（インラインコマンドの1行の上限を兼ねる）
+	readBufferSize = 64 << 10
+
+	// maxBulkLen はバルク文字列の最大長
+	maxBulkLen = 64 << 20
+
+	// maxArgs はコマンドの引数の最大数
+	maxArgs = 1 << 16
+)
+
+// protocolError はクライアントが送ったデータがRESPとして不正であることを表す
+// 応答したうえで接続を閉じる
+type protocolError string
+
+func (e protocolError) Error() string {
+	return "Protocol error: " + string(e)
+}
+
+// readCommand はコマンドを1つ読み込み、コマンド名と引数を返す
+// マルチバルク形式（*N\r\n$len\r\n...）とインライン形式（PING\r\n）を受け付ける
+// 空のコマンド（空行、*0）の場合は nil を返す
+func readCommand(r *bufio.Reader) ([][]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, nil
+	}
+	if line[0] != '*' {
+		// インライン形式（引用符には対応しない）。line は次の読み込みで上書きされるためコピーする
+		fields := bytes.Fields(line)
+		args := make([][]byte, len(fields))
+		for i, f := range fields {
+			args[i] = append([]byte(nil), f...)
+		}
+		return args, nil
+	}
+
+	n, err := strconv.Atoi(string(line[1:]))
+	if err != nil || n > maxArgs {
+		return nil, protocolError("invalid multibulk length")
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	args := make([][]byte, 0, n)
+	for range n {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, protocolError(fmt.Sprintf("expected '$', got '%s'", printable(line)))
+		}
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, protocolError("invalid bulk length")
+		}
+
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, err
+		}
+		if arg[size] != '\r' || arg[size+1] != '\n' {
+			return nil, protocolError("bulk string is not terminated by CRLF")
+		}
+		args = append(args, arg[:size])
+	}
+	return args, nil
+}
+
+// readLine は \r\n（または \n）で終わる1行を、改行を除いて返す
+// 返すスライスは次の読み込みまでの間のみ有効
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, protocolError("too big inline request")
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nil
+}
+
+// printable はエラーメッセージに含めるため、先頭の数バイトを返す
+func printable(b []byte) string {
+	if len(b) > 16 {
+		b = b[:16]
+	}
+	q := strconv.Quote(string(b))
+	return q[1 : len(q)-1]
+}
+
+// writer はRESPの応答を書き込む
+// 書き込みのエラーは Flush で返る
+type writer struct {
+	*bufio.Writer
+}
+
+// newWriter は w に応答を書き込む writer を作成する
+func newWriter(w io.Writer) writer {
+	return writer{bufio.NewWriter(w)}
+}
+
+// simple は単純文字列（+OK）を書き込む
+func (w writer) simple(s string) {
+	_, _ = w.WriteString("+" + s + "\r\n")
+}
+
+// error はエラー（-ERR ...）を書き込む。msg はエラーの種類（ERR など）から始める
+func (w writer) error(msg string) {
+	_, _ = w.WriteString("-" + msg + "\r\n")
+}
+
+// integer は整数（:1）を書き込む
+func (w writer) integer(n int64) {
+	_, _ = w.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+// bulk はバルク文字列を書き込む。nil の場合は null（$-1）を書き込む
+func (w writer) bulk(b []byte) {
+	if b == nil {
+		_, _ = w.WriteString("$-1\r\n")
+		return
+	}
+	_, _ = w.WriteString("$" + strconv.Itoa(len(b)) + "\r\n")
+	_, _ = w.Write(b)
+	_, _ = w.WriteString("\r\n")
+}
+
+// arrayLen は配列の要素数（*N）を書き込む。続けて N 個の要素を書き込む
+func (w writer) arrayLen(n int) {
+	_, _ = w.WriteString("*" + strconv.Itoa(n) + "\r\n")
+}
@@ -0,0 +1,100 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"*1\r\n$4\r\nPING\r\n", []string{"PING"}},
+		{"*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$0\r\n\r\n", []string{"SET", "key", ""}},
+		{"*2\r\n$3\r\nGET\r\n$4\r\na\r\nb\r\n", []string{"GET", "a\r\nb"}},
+		{"PING\r\n", []string{"PING"}},
+		{"  SET  key value\n", []string{"SET", "key", "value"}},
+		{"\r\n", nil},
+		{"*0\r\n", nil},
+	}
+
+	for _, tt := range tests {
+		args, err := readCommand(bufio.NewReader(strings.NewReader(tt.input)))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if len(args) != len(tt.want) {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.want, args)
+			continue
+		}
+		for i := range args {
+			if string(args[i]) != tt.want[i] {
+				t.Errorf("%q: expected %q, got %q", tt.input, tt.want, args)
+			}
+		}
+	}
+}
+
+func TestReadCommandPipelined(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("SET a 1\r\n*2\r\n$3\r\nGET\r\n$1\r\na\r\n"))
+
+	first, err := readCommand(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := readCommand(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// インライン形式の引数は後続の読み込みで上書きされない
+	if string(bytes.Join(first, []byte(" "))) != "SET a 1" || string(second[0]) != "GET" {
+		t.Errorf("unexpected commands: %q, %q", first, second)
+	}
+	if _, err := readCommand(r); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestReadCommandProtocolError(t *testing.T) {
+	for _, input := range []string{
+		"*x\r\n",
+		"*1\r\n+PING\r\n",
+		"*1\r\n$-1\r\n",
+		"*1\r\n$4\r\nPINGXX",
+		"*1\r\n$999999999999\r\n",
+		strings.Repeat("a", readBufferSize+1),
+	} {
+		r := bufio.NewReaderSize(strings.NewReader(input), readBufferSize)
+		_, err := readCommand(r)
+		var perr protocolError
+		if !errors.As(err, &perr) {
+			t.Errorf("%.20q: expected protocol error, got %v", input, err)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	w.simple("OK")
+	w.error("ERR bad")
+	w.integer(3)
+	w.bulk([]byte("v"))
+	w.bulk(nil)
+	w.bulk([]byte{})
+	w.arrayLen(0)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "+OK\r\n-ERR bad\r\n:3\r\n$1\r\nv\r\n$-1\r\n$0\r\n\r\n*0\r\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// errNoNodes はキーを担当できるノードがないことを表す
+var errNoNodes = errors.New("no nodes in cluster")
+
+// Router はキーを担当するノードを返す
+type Router interface {
+	Route(key string) (*node.Node, error)
+}
+
+// singleNode は全てのキーを1つのノードで扱う
+type singleNode struct {
+	node *node.Node
+}
+
+// NodeRouter は全てのキーを n で扱う Router を返す（ノードごとのリスナー用）
+func NodeRouter(n *node.Node) Router {
+	return singleNode{node: n}
+}
+
+// Route は常に同じノードを返す
+func (r singleNode) Route(string) (*node.Node, error) {
+	return r.node, nil
+}
+
+// clusterRouter はクラスタの cluster.Router でノードに振り分ける
+type clusterRouter struct {
+	cluster *cluster.Cluster
+}
+
+// ClusterRouter は c のノードに振り分ける Router を返す
+// 負荷生成のクライアントと同じく cluster.Router のコンシステントハッシュを使うため、同じキーは同じノードが扱う
+func ClusterRouter(c *cluster.Cluster) Router {
+	return clusterRouter{cluster: c}
+}
+
+// Route はキーを担当するノードを返す
+func (r clusterRouter) Route(key string) (*node.Node, error) {
+	n, ok := r.cluster.Owner(key)
+	if !ok {
+		return nil, errNoNodes
+	}
+	return n, nil
+}
+
+// available はノードが操作を受け付けられるかを返す
+// 停止中のノードの Get はエラーにならず値なしを返すため、応答前に確認する
+func available(n *node.Node) error {
+	if status := n.Status(); !status.Available() {
+		return fmt.Errorf("node %s is %s", n.ID(), status)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package resp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClusterRouter(t *testing.T) {
+	c := cluster.New()
+	if err := c.CreateNodes(3, "node"); err != nil {
+		t.Fatal(err)
+	}
+	r := ClusterRouter(c)
+
+	counts := make(map[string]int)
+	for i := range 300 {
+		key := fmt.Sprintf("key-%d", i)
+		n, err := r.Route(key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		again, _ := r.Route(key)
+		if again != n {
+			t.Fatalf("key %s routed to %s and %s", key, n.ID(), again.ID())
+		}
+		counts[n.ID()]++
+	}
+	if len(counts) != 3 {
+		t.Errorf("expected keys on all 3 nodes, got %v", counts)
+	}
+
+	// ノードの追加に追従する
+	if _, err := c.AddNodes(1, "node"); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for i := range 300 {
+		n, _ := r.Route(fmt.Sprintf("key-%d", i))
+		seen[n.ID()] = true
+	}
+	if !seen["node-4"] {
+		t.Error("expected keys to be routed to the added node")
+	}
+}
+
+func TestClusterRouterEmpty(t *testing.T) {
+	if _, err := ClusterRouter(cluster.New()).Route("key"); err == nil {
+		t.Error("expected error for empty cluster")
+	}
+}
+
+func TestNodeRouter(t *testing.T) {
+	n := node.New("node-1")
+	r := NodeRouter(n)
+	if got, _ := r.Route("any"); got != n {
+		t.Errorf("expected node-1, got %v", got)
+	}
+
+	if err := available(n); err == nil {
+		t.Error("stopped node should not be available")
+	}
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := available(n); err != nil {
+		t.Errorf("running node should be available: %v", err)
+	}
+}
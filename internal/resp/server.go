@@ -0,0 +1,360 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// log はコンポーネント名 "resp" を付けてログを出力する子ロガー
+var log = logger.With("resp")
+
+// Config はRESPサーバーの設定
+type Config struct {
+	Addr        string        // リッスンアドレス（例: :6379、:0 で空いているポート）
+	IdleTimeout time.Duration // この期間コマンドを受信しない接続を閉じる（0で無制限）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Addr: ":6379",
+	}
+}
+
+// Stats はサーバーの統計情報
+type Stats struct {
+	Connections uint64 // 現在の接続数
+	Commands    uint64 // 処理したコマンドの数
+	Errors      uint64 // エラーを返したコマンドの数
+}
+
+// Server はノードのKVSをRESP（Redisプロトコル）で公開するTCPサーバー
+type Server struct {
+	config Config
+	router Router
+
+	running  atomic.Bool
+	listener net.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+
+	commands, errors atomic.Uint64
+}
+
+// New はRESPサーバーを作成する。キーは router が返すノードで扱う
+func New(config Config, router Router) *Server {
+	return &Server{
+		config: config,
+		router: router,
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+// Start はリッスンを開始し、バックグラウンドで接続を受け付ける
+// ctx が終了するか Stop を呼ぶまで受け付ける
+func (s *Server) Start(ctx context.Context) error {
+	if s.running.Swap(true) {
+		return fmt.Errorf("resp server is already running")
+	}
+
+	lis, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		s.running.Store(false)
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+	s.listener = lis
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(2)
+	go s.acceptLoop()
+	go func() {
+		defer s.wg.Done()
+		<-s.ctx.Done()
+		s.closeAll()
+	}()
+
+	log.Info("", "RESP server listening on %s", lis.Addr())
+	return nil
+}
+
+// Stop はリッスンをやめ、全ての接続を閉じて終了を待つ
+func (s *Server) Stop() {
+	if !s.running.Swap(false) {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	log.Info("", "RESP server stopped (commands: %d)", s.commands.Load())
+}
+
+// Addr は実際にリッスンしているアドレスを返す（開始前は設定のアドレス）
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return s.config.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Stats は統計情報を返す
+func (s *Server) Stats() Stats {
+	s.mu.Lock()
+	conns := len(s.conns)
+	s.mu.Unlock()
+	return Stats{
+		Connections: uint64(conns),
+		Commands:    s.commands.Load(),
+		Errors:      s.errors.Load(),
+	}
+}
+
+// acceptLoop はリスナーが閉じられるまで接続を受け付ける
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.ctx.Err() == nil {
+				log.Error("", "Accept failed: %v", err)
+				s.cancel()
+			}
+			return
+		}
+
+		s.mu.Lock()
+		if s.ctx.Err() != nil {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// closeAll はリスナーと全ての接続を閉じる
+func (s *Server) closeAll() {
+	_ = s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+// serveConn は接続が閉じられるまでコマンドを処理する
+// パイプライン化されたコマンドは、受信済みのコマンドを処理し終えてからまとめて応答を送る
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReaderSize(conn, readBufferSize)
+	w := newWriter(conn)
+	for {
+		if s.config.IdleTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(s.config.IdleTimeout))
+		}
+		args, err := readCommand(r)
+		if err != nil {
+			var perr protocolError
+			if errors.As(err, &perr) {
+				w.error("ERR " + perr.Error())
+				_ = w.Flush()
+			} else if !errors.Is(err, io.EOF) && s.ctx.Err() == nil {
+				log.Debug("", "Connection from %s closed: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := s.execute(w, args)
+		if quit || r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+		if quit {
+			return
+		}
+	}
+}
+
+// execute はコマンドを実行して応答を書き込む。接続を閉じる場合は true を返す
+func (s *Server) execute(w writer, args [][]byte) (quit bool) {
+	s.commands.Add(1)
+	name := strings.ToUpper(string(args[0]))
+	args = args[1:]
+
+	fail := func(msg string) {
+		s.errors.Add(1)
+		w.error(msg)
+	}
+	wrongArgs := func() {
+		fail(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+	}
+
+	switch name {
+	case "PING":
+		switch len(args) {
+		case 0:
+			w.simple("PONG")
+		case 1:
+			w.bulk(args[0])
+		default:
+			wrongArgs()
+		}
+
+	case "ECHO":
+		if len(args) != 1 {
+			wrongArgs()
+			return false
+		}
+		w.bulk(args[0])
+
+	case "GET":
+		if len(args) != 1 {
+			wrongArgs()
+			return false
+		}
+		value, err := s.get(string(args[0]))
+		if err != nil {
+			fail("ERR " + err.Error())
+			return false
+		}
+		w.bulk(value)
+
+	case "SET":
+		// EX・NX などのオプションには対応しない
+		if len(args) < 2 {
+			wrongArgs()
+			return false
+		}
+		if len(args) > 2 {
+			fail("ERR syntax error")
+			return false
+		}
+		if err := s.set(string(args[0]), args[1]); errors.Is(err, node.ErrCapacity) {
+			fail("OOM " + err.Error()) // Redis の maxmemory と同じ応答
+			return false
+		} else if errors.Is(err, node.ErrReadOnly) {
+			fail("READONLY " + err.Error()) // Redis の読み取り専用レプリカと同じ応答
+			return false
+		} else if err != nil {
+			fail("ERR " + err.Error())
+			return false
+		}
+		w.simple("OK")
+
+	case "DEL":
+		if len(args) == 0 {
+			wrongArgs()
+			return false
+		}
+		deleted := 0
+		for _, key := range args {
+			ok, err := s.del(string(key))
+			if errors.Is(err, node.ErrReadOnly) {
+				fail("READONLY " + err.Error())
+				return false
+			} else if err != nil {
+				fail("ERR " + err.Error())
+				return false
+			}
+			if ok {
+				deleted++
+			}
+		}
+		w.integer(int64(deleted))
+
+	case "COMMAND", "CONFIG":
+		// redis-cli・redis-benchmark が接続時に送るため、空の配列を返す
+		w.arrayLen(0)
+
+	case "QUIT":
+		w.simple("OK")
+		return true
+
+	default:
+		fail(fmt.Sprintf("ERR unknown command '%s'", printable([]byte(strings.ToLower(name)))))
+	}
+	return false
+}
+
+// get はキーを担当するノードから値を取得する。キーがない場合は nil を返す
+// ノードに注入された遅延はそのまま応答に反映される
+func (s *Server) get(key string) ([]byte, error) {
+	n, err := s.router.Route(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := available(n); err != nil {
+		return nil, err
+	}
+	value, ok, err := n.GetContext(s.ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return value, nil
+}
+
+// set はキーを担当するノードに値を設定する
+func (s *Server) set(key string, value []byte) error {
+	n, err := s.router.Route(key)
+	if err != nil {
+		return err
+	}
+	return n.SetContext(s.ctx, key, value)
+}
+
+// del はキーを担当するノードからキーを削除し、キーが存在したかを返す
+// 存在の確認と削除のそれぞれがノードの遅延を待つため、遅延を注入したノードでは GET の2倍の時間がかかる
+func (s *Server) del(key string) (bool, error) {
+	n, err := s.router.Route(key)
+	if err != nil {
+		return false, err
+	}
+	if err := available(n); err != nil {
+		return false, err
+	}
+	_, existed, err := n.GetContext(s.ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if err := n.DeleteContext(s.ctx, key); err != nil {
+		return false, err
+	}
+	return existed, nil
+}
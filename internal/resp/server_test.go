@@ -0,0 +1,192 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+)
+
+// startTestServer はクラスタを作成し、空いているポートでRESPサーバーを起動する
+func startTestServer(t testing.TB, nodes int) (*Server, *cluster.Cluster) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c := cluster.New()
+	if err := c.CreateNodes(nodes, "node"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.Addr = "127.0.0.1:0"
+	s := New(config, ClusterRouter(c))
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(s.Stop)
+	return s, c
+}
+
+// roundTrip は request を送り、n 個の応答の行を読み込む
+func roundTrip(t *testing.T, conn net.Conn, r *bufio.Reader, request string, lines int) []string {
+	t.Helper()
+	if _, err := io.WriteString(conn, request); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var got []string
+	for range lines {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read reply: %v (got %q)", err, got)
+		}
+		got = append(got, strings.TrimSuffix(line, "\r\n"))
+	}
+	return got
+}
+
+func TestServerCommands(t *testing.T) {
+	s, _ := startTestServer(t, 3)
+
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	tests := []struct {
+		request string
+		want    []string
+	}{
+		{"PING\r\n", []string{"+PONG"}},
+		{"*2\r\n$4\r\nPING\r\n$2\r\nhi\r\n", []string{"$2", "hi"}},
+		{"*3\r\n$3\r\nSET\r\n$3\r\nfoo\r\n$3\r\nbar\r\n", []string{"+OK"}},
+		{"*2\r\n$3\r\nGET\r\n$3\r\nfoo\r\n", []string{"$3", "bar"}},
+		{"GET missing\r\n", []string{"$-1"}},
+		{"*3\r\n$3\r\nDEL\r\n$3\r\nfoo\r\n$7\r\nmissing\r\n", []string{":1"}},
+		{"GET foo\r\n", []string{"$-1"}},
+		{"SET foo\r\n", []string{"-ERR wrong number of arguments for 'set' command"}},
+		{"SET foo bar EX 10\r\n", []string{"-ERR syntax error"}},
+		{"FLUSHALL\r\n", []string{"-ERR unknown command 'flushall'"}},
+		{"CONFIG GET save\r\n", []string{"*0"}},
+	}
+	for _, tt := range tests {
+		got := roundTrip(t, conn, r, tt.request, len(tt.want))
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%q: expected %q, got %q", tt.request, tt.want, got)
+		}
+	}
+
+	stats := s.Stats()
+	if stats.Connections != 1 || stats.Commands != uint64(len(tests)) || stats.Errors != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if got := roundTrip(t, conn, r, "QUIT\r\n", 1); got[0] != "+OK" {
+		t.Errorf("expected +OK, got %q", got)
+	}
+	if _, err := r.ReadByte(); err != io.EOF {
+		t.Errorf("expected connection to be closed after QUIT, got %v", err)
+	}
+}
+
+func TestServerPipeline(t *testing.T) {
+	s, _ := startTestServer(t, 2)
+
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	var request strings.Builder
+	for range 100 {
+		request.WriteString("SET k v\r\nGET k\r\n")
+	}
+	got := roundTrip(t, conn, bufio.NewReader(conn), request.String(), 300)
+	for i := 0; i < len(got); i += 3 {
+		if got[i] != "+OK" || got[i+1] != "$1" || got[i+2] != "v" {
+			t.Fatalf("unexpected replies at %d: %q", i, got[i:i+3])
+		}
+	}
+}
+
+func TestServerNodeDown(t *testing.T) {
+	s, c := startTestServer(t, 1)
+	n, _ := c.GetNode("node-1")
+
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	if err := n.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range []string{"GET k\r\n", "SET k v\r\n", "DEL k\r\n"} {
+		got := roundTrip(t, conn, r, cmd, 1)
+		if !strings.HasPrefix(got[0], "-ERR node node-1 is") {
+			t.Errorf("%q: expected node error, got %q", cmd, got)
+		}
+	}
+
+	if err := n.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if got := roundTrip(t, conn, r, "SET k v\r\n", 1); got[0] != "+OK" {
+		t.Errorf("expected +OK after resume, got %q", got)
+	}
+}
+
+func TestServerProtocolError(t *testing.T) {
+	s, _ := startTestServer(t, 1)
+
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	got := roundTrip(t, conn, r, "*1\r\n+PING\r\n", 1)
+	if !strings.HasPrefix(got[0], "-ERR Protocol error") {
+		t.Errorf("expected protocol error, got %q", got)
+	}
+	if _, err := r.ReadByte(); err != io.EOF {
+		t.Errorf("expected connection to be closed, got %v", err)
+	}
+}
+
+func TestServerStop(t *testing.T) {
+	s, _ := startTestServer(t, 1)
+
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	roundTrip(t, conn, bufio.NewReader(conn), "PING\r\n", 1)
+
+	s.Stop()
+	if s.Stats().Connections != 0 {
+		t.Errorf("expected all connections to be closed, got %+v", s.Stats())
+	}
+	if _, err := net.DialTimeout("tcp", s.Addr(), time.Second); err == nil {
+		t.Error("expected dial to fail after Stop")
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Errorf("expected restart to succeed: %v", err)
+	}
+}
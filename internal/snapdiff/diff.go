@@ -0,0 +1,311 @@
+package snapdiff
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+)
+
+// Category はキーの差分の分類
+type Category string
+
+const (
+	CategoryLost      Category = "lost"      // 残っているノードのいずれにもなくなった書き込み
+	CategoryDivergent Category = "divergent" // 比較先でノードの間の値が一致しないキー
+	CategoryChurn     Category = "churn"     // 追加・一貫した上書き・ノードの削除による想定内の変化
+)
+
+// Change はキーの変化の種類
+type Change string
+
+const (
+	ChangeAdded       Change = "added"        // 比較先にのみある
+	ChangeUpdated     Change = "updated"      // 全てのノードで同じ新しい値になった
+	ChangeNodeRemoved Change = "node-removed" // 保持していた全てのノードが比較先にない
+	ChangeMissing     Change = "missing"      // 比較先のどのノードにもない
+	ChangeConflicting Change = "conflicting"  // 比較先でノードごとに値が異なる
+)
+
+// DefaultMaxKeys は Report.Keys に含めるキーの数のデフォルトの上限
+const DefaultMaxKeys = 100
+
+// Options は差分の設定
+type Options struct {
+	// MaxKeys は Report.Keys に含めるキーの数の上限（0以下で全て含める）
+	// 失われた書き込み・不一致・想定内の変化の順に含める
+	MaxKeys int
+}
+
+// DefaultOptions はデフォルトの設定を返す
+func DefaultOptions() Options {
+	return Options{MaxKeys: DefaultMaxKeys}
+}
+
+// KeyDiff は1つのキーの差分
+type KeyDiff struct {
+	Key      string   `json:"key"`
+	Category Category `json:"category"`
+	Change   Change   `json:"change"`
+	Before   []string `json:"before,omitempty"` // 比較元でキーを保持していたノード
+	After    []string `json:"after,omitempty"`  // 比較先でキーを保持しているノード
+	Values   int      `json:"values,omitempty"` // 比較先の異なる値の数（divergent の場合）
+}
+
+// NodeDiff は1つのノードの差分
+type NodeDiff struct {
+	ID      string `json:"id"`
+	Before  int    `json:"before"`  // 比較元のキーの数（比較元にない場合は0）
+	After   int    `json:"after"`   // 比較先のキーの数（比較先にない場合は0）
+	Dropped int    `json:"dropped"` // 比較元で保持していて比較先で保持していないキーの数
+	Removed bool   `json:"removed"` // 比較先にないノードか
+	Added   bool   `json:"added"`   // 比較元にないノードか
+}
+
+// Report は2つのスナップショットの差分
+type Report struct {
+	Before     string `json:"before,omitempty"` // 比較元の名前（ファイル名など）
+	After      string `json:"after,omitempty"`  // 比較先の名前
+	BeforeKeys int    `json:"before_keys"`      // 比較元のいずれかのノードにあるキーの数
+	AfterKeys  int    `json:"after_keys"`       // 比較先のいずれかのノードにあるキーの数
+
+	Unchanged int `json:"unchanged"`
+	Lost      int `json:"lost"`
+	Divergent int `json:"divergent"`
+	Churn     int `json:"churn"`
+
+	Nodes     []NodeDiff `json:"nodes"`
+	Keys      []KeyDiff  `json:"keys"`      // 差分のあるキー（Options.MaxKeys まで）
+	Truncated bool       `json:"truncated"` // 上限により Keys を省略したか
+}
+
+// Clean は失われた書き込みと不一致がないかを返す
+func (r *Report) Clean() bool {
+	return r.Lost == 0 && r.Divergent == 0
+}
+
+// holders はキーを保持しているノードとその値
+type holders map[string][]byte
+
+// nodes はキーを保持しているノードを ID 順に返す
+func (h holders) nodes() []string {
+	ids := make([]string, 0, len(h))
+	for id := range h {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// distinct は異なる値の数を返す
+func (h holders) distinct() int {
+	var values [][]byte
+	for _, v := range h {
+		seen := false
+		for _, u := range values {
+			if bytes.Equal(u, v) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			values = append(values, v)
+		}
+	}
+	return len(values)
+}
+
+// contains は value を保持しているノードがあるかを返す
+func (h holders) contains(value []byte) bool {
+	for _, v := range h {
+		if bytes.Equal(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// index はスナップショットをキーごとの保持ノードに変換する
+func index(snaps []cluster.NodeSnapshot) map[string]holders {
+	keys := make(map[string]holders)
+	for _, snap := range snaps {
+		for key, value := range snap.Data {
+			if keys[key] == nil {
+				keys[key] = make(holders)
+			}
+			keys[key][snap.ID] = value
+		}
+	}
+	return keys
+}
+
+// Diff は before から after への差分を求める
+func Diff(before, after []cluster.NodeSnapshot, opts Options) *Report {
+	beforeKeys, afterKeys := index(before), index(after)
+	present := make(map[string]bool, len(after))
+	for _, snap := range after {
+		present[snap.ID] = true
+	}
+
+	r := &Report{BeforeKeys: len(beforeKeys), AfterKeys: len(afterKeys)}
+	var diffs []KeyDiff
+	classify := func(key string) {
+		b, a := beforeKeys[key], afterKeys[key]
+		d := KeyDiff{Key: key, Before: b.nodes(), After: a.nodes()}
+		switch {
+		case len(a) > 0 && a.distinct() > 1:
+			d.Category, d.Change, d.Values = CategoryDivergent, ChangeConflicting, a.distinct()
+		case len(a) == 0:
+			d.Category, d.Change = CategoryLost, ChangeMissing
+			if !anyPresent(d.Before, present) {
+				d.Category, d.Change = CategoryChurn, ChangeNodeRemoved
+			}
+		case len(b) == 0:
+			d.Category, d.Change = CategoryChurn, ChangeAdded
+		default:
+			// 比較先の値は1つだけ
+			for _, v := range a {
+				if b.contains(v) {
+					r.Unchanged++
+					return
+				}
+			}
+			d.Category, d.Change = CategoryChurn, ChangeUpdated
+		}
+		switch d.Category {
+		case CategoryLost:
+			r.Lost++
+		case CategoryDivergent:
+			r.Divergent++
+		case CategoryChurn:
+			r.Churn++
+		}
+		diffs = append(diffs, d)
+	}
+	for key := range beforeKeys {
+		classify(key)
+	}
+	for key := range afterKeys {
+		if _, ok := beforeKeys[key]; !ok {
+			classify(key)
+		}
+	}
+
+	// 重要な分類から順に、同じ分類の中はキーの順に並べる
+	rank := map[Category]int{CategoryLost: 0, CategoryDivergent: 1, CategoryChurn: 2}
+	sort.Slice(diffs, func(i, j int) bool {
+		if rank[diffs[i].Category] != rank[diffs[j].Category] {
+			return rank[diffs[i].Category] < rank[diffs[j].Category]
+		}
+		return diffs[i].Key < diffs[j].Key
+	})
+	if opts.MaxKeys > 0 && len(diffs) > opts.MaxKeys {
+		diffs, r.Truncated = diffs[:opts.MaxKeys], true
+	}
+	r.Keys = diffs
+	r.Nodes = diffNodes(before, after)
+	return r
+}
+
+// anyPresent は ids のいずれかが present に含まれるかを返す
+func anyPresent(ids []string, present map[string]bool) bool {
+	for _, id := range ids {
+		if present[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// diffNodes はノードごとのキーの数と、保持しなくなったキーの数を求める
+func diffNodes(before, after []cluster.NodeSnapshot) []NodeDiff {
+	byID := make(map[string]*NodeDiff)
+	var order []string
+	get := func(id string) *NodeDiff {
+		if d, ok := byID[id]; ok {
+			return d
+		}
+		d := &NodeDiff{ID: id}
+		byID[id] = d
+		order = append(order, id)
+		return d
+	}
+
+	afterData := make(map[string]map[string][]byte, len(after))
+	for _, snap := range after {
+		afterData[snap.ID] = snap.Data
+		d := get(snap.ID)
+		d.After = len(snap.Data)
+		d.Added = true
+	}
+	for _, snap := range before {
+		d := get(snap.ID)
+		d.Before = len(snap.Data)
+		d.Added = false
+		data, ok := afterData[snap.ID]
+		d.Removed = !ok
+		for key := range snap.Data {
+			if _, held := data[key]; !held {
+				d.Dropped++
+			}
+		}
+	}
+
+	nodes := make([]NodeDiff, 0, len(order))
+	sort.Strings(order)
+	for _, id := range order {
+		nodes = append(nodes, *byID[id])
+	}
+	return nodes
+}
+
+// Text は差分を表形式でフォーマットして返す
+func (r *Report) Text() string {
+	var b strings.Builder
+	if r.Before != "" || r.After != "" {
+		fmt.Fprintf(&b, "SNAPSHOT DIFF: %s -> %s\n", r.Before, r.After)
+	} else {
+		b.WriteString("SNAPSHOT DIFF\n")
+	}
+	fmt.Fprintf(&b, "  Keys:       %d -> %d\n", r.BeforeKeys, r.AfterKeys)
+	fmt.Fprintf(&b, "  Unchanged:  %d\n", r.Unchanged)
+	fmt.Fprintf(&b, "  Lost:       %d\n", r.Lost)
+	fmt.Fprintf(&b, "  Divergent:  %d\n", r.Divergent)
+	fmt.Fprintf(&b, "  Churn:      %d\n", r.Churn)
+
+	b.WriteString("\nNODES\n")
+	fmt.Fprintf(&b, "  %-20s %10s %10s %10s\n", "Node", "Before", "After", "Dropped")
+	for _, n := range r.Nodes {
+		mark := ""
+		switch {
+		case n.Removed:
+			mark = "  removed"
+		case n.Added:
+			mark = "  added"
+		}
+		fmt.Fprintf(&b, "  %-20s %10d %10d %10d%s\n", n.ID, n.Before, n.After, n.Dropped, mark)
+	}
+
+	if len(r.Keys) > 0 {
+		b.WriteString("\nKEYS\n")
+		for _, k := range r.Keys {
+			detail := fmt.Sprintf("before on [%s], after on [%s]", strings.Join(k.Before, ","), strings.Join(k.After, ","))
+			if k.Values > 0 {
+				detail += fmt.Sprintf(", %d distinct values", k.Values)
+			}
+			fmt.Fprintf(&b, "  %-10s %-13s %-24s %s\n", k.Category, k.Change, k.Key, detail)
+		}
+		if r.Truncated {
+			b.WriteString("  ... (truncated)\n")
+		}
+	}
+
+	if r.Clean() {
+		b.WriteString("\nNo lost writes or divergence detected\n")
+	} else {
+		fmt.Fprintf(&b, "\n%d lost write(s) and %d divergent key(s) detected\n", r.Lost, r.Divergent)
+	}
+	return b.String()
+}
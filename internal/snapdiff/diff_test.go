@@ -0,0 +1,100 @@
+package snapdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+)
+
+// snapshot はキーと値の組からノードのスナップショットを作成する
+func snapshot(id string, kv ...string) cluster.NodeSnapshot {
+	data := make(map[string][]byte, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		data[kv[i]] = []byte(kv[i+1])
+	}
+	return cluster.NodeSnapshot{ID: id, Data: data}
+}
+
+func TestDiff(t *testing.T) {
+	before := []cluster.NodeSnapshot{
+		snapshot("node-1", "same", "v", "lost", "v", "updated", "v1", "split", "v"),
+		snapshot("node-2", "same", "v", "moved", "v", "split", "v"),
+		snapshot("node-3", "scaled-in", "v"),
+	}
+	after := []cluster.NodeSnapshot{
+		snapshot("node-1", "same", "v", "updated", "v2", "split", "a", "added", "v"),
+		snapshot("node-2", "same", "v", "split", "b"),
+		snapshot("node-4", "moved", "v"),
+	}
+
+	r := Diff(before, after, DefaultOptions())
+	if r.BeforeKeys != 6 || r.AfterKeys != 5 {
+		t.Errorf("expected 6 -> 5 keys, got %d -> %d", r.BeforeKeys, r.AfterKeys)
+	}
+	if r.Unchanged != 2 || r.Lost != 1 || r.Divergent != 1 || r.Churn != 3 {
+		t.Errorf("unexpected counts: %+v", r)
+	}
+	if r.Clean() {
+		t.Error("expected the diff to report lost writes")
+	}
+
+	want := []struct {
+		key      string
+		category Category
+		change   Change
+	}{
+		{"lost", CategoryLost, ChangeMissing},
+		{"split", CategoryDivergent, ChangeConflicting},
+		{"added", CategoryChurn, ChangeAdded},
+		{"scaled-in", CategoryChurn, ChangeNodeRemoved},
+		{"updated", CategoryChurn, ChangeUpdated},
+	}
+	if len(r.Keys) != len(want) {
+		t.Fatalf("expected %d key diffs, got %+v", len(want), r.Keys)
+	}
+	for i, w := range want {
+		if k := r.Keys[i]; k.Key != w.key || k.Category != w.category || k.Change != w.change {
+			t.Errorf("key diff %d: expected %s %s %s, got %+v", i, w.key, w.category, w.change, k)
+		}
+	}
+	if r.Keys[1].Values != 2 {
+		t.Errorf("expected 2 distinct values for the divergent key, got %d", r.Keys[1].Values)
+	}
+
+	nodes := map[string]NodeDiff{}
+	for _, n := range r.Nodes {
+		nodes[n.ID] = n
+	}
+	if n := nodes["node-1"]; n.Before != 4 || n.After != 4 || n.Dropped != 1 {
+		t.Errorf("unexpected node-1 diff: %+v", n)
+	}
+	if n := nodes["node-2"]; n.Dropped != 1 {
+		t.Errorf("expected node-2 to drop the moved key, got %+v", n)
+	}
+	if !nodes["node-3"].Removed || !nodes["node-4"].Added {
+		t.Errorf("expected node-3 to be removed and node-4 added, got %+v", r.Nodes)
+	}
+}
+
+func TestDiffClean(t *testing.T) {
+	snaps := []cluster.NodeSnapshot{snapshot("node-1", "a", "1"), snapshot("node-2", "b", "2")}
+	r := Diff(snaps, snaps, DefaultOptions())
+	if !r.Clean() || r.Unchanged != 2 || len(r.Keys) != 0 {
+		t.Errorf("expected identical snapshots to be clean, got %+v", r)
+	}
+	if !strings.Contains(r.Text(), "No lost writes or divergence detected") {
+		t.Errorf("unexpected report:\n%s", r.Text())
+	}
+}
+
+func TestDiffMaxKeys(t *testing.T) {
+	after := []cluster.NodeSnapshot{snapshot("node-1", "a", "1", "b", "2", "c", "3")}
+	r := Diff(nil, after, Options{MaxKeys: 2})
+	if len(r.Keys) != 2 || !r.Truncated || r.Churn != 3 {
+		t.Errorf("expected 2 of 3 added keys, got %+v", r)
+	}
+	if !strings.Contains(r.Text(), "(truncated)") {
+		t.Errorf("expected the report to mention the truncation:\n%s", r.Text())
+	}
+}
@@ -0,0 +1,29 @@
+// Package snapdiff compares two cluster snapshots, typically one taken before
+// chaos is injected and one taken after recovery, and reports which keys were
+// lost, which diverged between nodes and which changed as expected.
+//
+// # Categories
+//
+// Every key that appears in either snapshot is classified once:
+//
+//   - Lost: the key was held by a node before, and no node holds it after,
+//     although a node that held it is still in the cluster.
+//   - Divergent: the nodes holding the key after disagree on its value.
+//   - Churn: the key was added, consistently overwritten, or disappeared
+//     together with every node that held it (scale-in).
+//
+// Keys whose value is unchanged are only counted. Per node, the report also
+// counts keys that the node held before but no longer holds after, even if
+// another node still has them.
+//
+// # Usage
+//
+//	before, _ := cluster.ReadSnapshot(beforeFile)
+//	after, _ := cluster.ReadSnapshot(afterFile)
+//	report := snapdiff.Diff(before, after, snapdiff.DefaultOptions())
+//	fmt.Print(report.Text())
+//
+// The load generator never deletes keys, so a lost key means a write that
+// was acknowledged before the first snapshot did not survive. TTL expiry and
+// eviction under node limits also remove keys and show up as lost.
+package snapdiff
@@ -0,0 +1,505 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Action is a node operation accepted by NodeAction.
+type Action string
+
+// Node actions.
+const (
+	ActionKill     Action = "kill"
+	ActionSuspend  Action = "suspend"
+	ActionReadOnly Action = "readonly"
+	ActionCorrupt  Action = "corrupt"
+	ActionResume   Action = "resume"
+	ActionStart    Action = "start"
+	ActionDelay    Action = "delay"
+)
+
+// Config holds client settings.
+type Config struct {
+	Token      string        // Bearer token (empty for unauthenticated servers)
+	Timeout    time.Duration // Per-request timeout when HTTPClient is nil
+	HTTPClient *http.Client  // Custom HTTP client (optional)
+}
+
+// DefaultConfig returns the default client settings.
+func DefaultConfig() Config {
+	return Config{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Client is a ChaosKVS API client.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// New creates a client for the server at baseURL (e.g. "http://localhost:8080",
+// or "https://proxy.example.com/chaos" when the server runs with a base path).
+func New(baseURL string, config Config) *Client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   config.Token,
+		http:    httpClient,
+	}
+}
+
+// Error is returned for non-2xx responses.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("chaos-kvs api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// NodeQuery holds the filter, sort, and pagination options for Nodes.
+type NodeQuery struct {
+	Status []string          // Filter by status (running, suspended, readonly, stopped)
+	Zone   []string          // Filter by zone
+	Labels map[string]string // Filter by labels (all must match)
+	Sort   string            // id, status, size, delay
+	Desc   bool              // Sort in descending order
+	Limit  int               // Maximum number of nodes (0 for all)
+	Offset int
+}
+
+// values encodes the query as URL parameters.
+func (q NodeQuery) values() url.Values {
+	v := url.Values{}
+	if len(q.Status) > 0 {
+		v.Set("status", strings.Join(q.Status, ","))
+	}
+	if len(q.Zone) > 0 {
+		v.Set("zone", strings.Join(q.Zone, ","))
+	}
+	for key, value := range q.Labels {
+		v.Add("label", key+"="+value)
+	}
+	if q.Sort != "" {
+		v.Set("sort", q.Sort)
+	}
+	if q.Desc {
+		v.Set("order", "desc")
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	return v
+}
+
+// NodePage is a page of nodes with the total count before pagination.
+type NodePage struct {
+	Nodes []NodeInfo
+	Total int
+}
+
+// Status returns the scenario and cluster status.
+func (c *Client) Status(ctx context.Context) (*StatusResponse, error) {
+	var resp StatusResponse
+	if _, err := c.do(ctx, http.MethodGet, "/api/status", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Nodes returns the nodes matching the query.
+func (c *Client) Nodes(ctx context.Context, q NodeQuery) (*NodePage, error) {
+	path := "/api/nodes"
+	if v := q.values(); len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var page NodePage
+	header, err := c.do(ctx, http.MethodGet, path, nil, &page.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	page.Total = len(page.Nodes)
+	if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil {
+		page.Total = total
+	}
+	return &page, nil
+}
+
+// Node returns a node with its operation counts and recent incidents.
+func (c *Client) Node(ctx context.Context, nodeID string) (*NodeDetail, error) {
+	var resp NodeDetail
+	if _, err := c.do(ctx, http.MethodGet, "/api/nodes/"+url.PathEscape(nodeID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// NodeAction runs an operation on a node and returns its state afterwards.
+// ActionDelay applies the server's default delay; use InjectDelay for a specific amount.
+func (c *Client) NodeAction(ctx context.Context, nodeID string, action Action) (*NodeInfo, error) {
+	return c.nodeAction(ctx, nodeID, action, nil)
+}
+
+// InjectDelay sets a response delay on a node. A zero delay clears it.
+func (c *Client) InjectDelay(ctx context.Context, nodeID string, d time.Duration) (*NodeInfo, error) {
+	return c.nodeAction(ctx, nodeID, ActionDelay, &NodeActionRequest{Delay: d.String()})
+}
+
+// nodeAction sends a node operation request.
+func (c *Client) nodeAction(ctx context.Context, nodeID string, action Action, body any) (*NodeInfo, error) {
+	path := fmt.Sprintf("/api/nodes/%s/%s", url.PathEscape(nodeID), url.PathEscape(string(action)))
+
+	var resp NodeInfo
+	if _, err := c.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ScaleCluster changes the number of nodes in the running scenario's cluster.
+// New nodes are started right away; scaling down removes the highest-numbered nodes.
+func (c *Client) ScaleCluster(ctx context.Context, nodes int) (*ScaleResponse, error) {
+	var resp ScaleResponse
+	if _, err := c.do(ctx, http.MethodPost, "/api/cluster/scale", ScaleRequest{Nodes: nodes}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Rebalance moves keys in the running scenario's cluster to the nodes that
+// own them. Adding and removing nodes already does this.
+func (c *Client) Rebalance(ctx context.Context) (*RebalanceResponse, error) {
+	var resp RebalanceResponse
+	if _, err := c.do(ctx, http.MethodPost, "/api/cluster/rebalance", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddNode adds and starts a node in the running scenario's cluster.
+func (c *Client) AddNode(ctx context.Context) (*NodeInfo, error) {
+	var resp NodeInfo
+	if _, err := c.do(ctx, http.MethodPost, "/api/cluster/nodes", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveNode stops a node and removes it from the running scenario's cluster.
+func (c *Client) RemoveNode(ctx context.Context, nodeID string) (*NodeInfo, error) {
+	var resp NodeInfo
+	if _, err := c.do(ctx, http.MethodDelete, "/api/cluster/nodes/"+url.PathEscape(nodeID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Snapshot writes an export of all node data to w as NDJSON (one node per line).
+func (c *Client) Snapshot(ctx context.Context, w io.Writer) error {
+	resp, err := c.send(ctx, http.MethodGet, "/api/cluster/snapshot", "", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces node data in the running scenario's cluster with a
+// snapshot read from r, as produced by Snapshot.
+func (c *Client) RestoreSnapshot(ctx context.Context, r io.Reader) (*RestoreResponse, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/api/cluster/snapshot", "application/x-ndjson", r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var restored RestoreResponse
+	if err := json.NewDecoder(resp.Body).Decode(&restored); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &restored, nil
+}
+
+// Metrics returns the client metrics of the running scenario.
+func (c *Client) Metrics(ctx context.Context) (*MetricsResponse, error) {
+	var resp MetricsResponse
+	if _, err := c.do(ctx, http.MethodGet, "/api/metrics", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StartScenario starts a scenario in the background.
+func (c *Client) StartScenario(ctx context.Context, req ScenarioRequest) (*StartResponse, error) {
+	var resp StartResponse
+	if _, err := c.do(ctx, http.MethodPost, "/api/scenario/start", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StopScenario interrupts the running scenario and returns the partial result.
+func (c *Client) StopScenario(ctx context.Context) (*StopResponse, error) {
+	var resp StopResponse
+	if _, err := c.do(ctx, http.MethodPost, "/api/scenario/stop", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StartRun starts a scenario alongside any runs already in progress.
+// The new run becomes the target of the endpoints without a run ID.
+func (c *Client) StartRun(ctx context.Context, req ScenarioRequest) (*StartResponse, error) {
+	var resp StartResponse
+	if _, err := c.do(ctx, http.MethodPost, "/api/runs", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ActiveRuns returns the status of the runs in progress and of the latest run.
+func (c *Client) ActiveRuns(ctx context.Context) ([]StatusResponse, error) {
+	var resp []StatusResponse
+	if _, err := c.do(ctx, http.MethodGet, "/api/runs/active", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RunStatus returns the status of a run in progress.
+func (c *Client) RunStatus(ctx context.Context, id string) (*StatusResponse, error) {
+	var resp StatusResponse
+	if _, err := c.do(ctx, http.MethodGet, "/api/runs/"+url.PathEscape(id)+"/status", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StopRun interrupts a run and returns the partial result.
+func (c *Client) StopRun(ctx context.Context, id string) (*StopResponse, error) {
+	var resp StopResponse
+	if _, err := c.do(ctx, http.MethodPost, "/api/runs/"+url.PathEscape(id)+"/stop", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Presets returns the available preset scenarios.
+func (c *Client) Presets(ctx context.Context) ([]PresetInfo, error) {
+	var resp []PresetInfo
+	if _, err := c.do(ctx, http.MethodGet, "/api/presets", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// LogQuery filters the server's recent log lines.
+type LogQuery struct {
+	Level      string    // Minimum level: debug, info, warn, error (empty for all)
+	NodeID     string    // Only lines about this node
+	Since      time.Time // Only lines after this time (zero for all)
+	Until      time.Time // Only lines at or before this time (zero for all)
+	Components []string  // Only lines from these components, e.g. recovery (empty for all)
+	Limit      int       // Keep only the newest lines (0 for all)
+}
+
+// Logs returns the server's recent log lines, oldest first.
+func (c *Client) Logs(ctx context.Context, q LogQuery) ([]LogEntry, error) {
+	v := url.Values{}
+	if q.Level != "" {
+		v.Set("level", q.Level)
+	}
+	if q.NodeID != "" {
+		v.Set("node", q.NodeID)
+	}
+	if !q.Since.IsZero() {
+		v.Set("since", q.Since.Format(time.RFC3339Nano))
+	}
+	if !q.Until.IsZero() {
+		v.Set("until", q.Until.Format(time.RFC3339Nano))
+	}
+	if len(q.Components) > 0 {
+		v.Set("component", strings.Join(q.Components, ","))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	path := "/api/logs"
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var resp []LogEntry
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EventQuery filters the server's recent events.
+type EventQuery struct {
+	Types  []string  // Event types (empty for all)
+	NodeID string    // Only events for this node
+	RunID  string    // Only events from this run
+	Since  time.Time // Only events after this time (zero for all)
+	Until  time.Time // Only events at or before this time (zero for all)
+	Limit  int       // Keep only the newest events (0 for all)
+}
+
+// Events returns the server's recent events across all runs, oldest first.
+func (c *Client) Events(ctx context.Context, q EventQuery) ([]Event, error) {
+	v := url.Values{}
+	if len(q.Types) > 0 {
+		v.Set("type", strings.Join(q.Types, ","))
+	}
+	if q.NodeID != "" {
+		v.Set("node", q.NodeID)
+	}
+	if q.RunID != "" {
+		v.Set("run", q.RunID)
+	}
+	if !q.Since.IsZero() {
+		v.Set("since", q.Since.Format(time.RFC3339Nano))
+	}
+	if !q.Until.IsZero() {
+		v.Set("until", q.Until.Format(time.RFC3339Nano))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	path := "/api/events"
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var resp []Event
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Runs returns the completed runs, newest first.
+func (c *Client) Runs(ctx context.Context) ([]RunSummary, error) {
+	var resp []RunSummary
+	if _, err := c.do(ctx, http.MethodGet, "/api/runs", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Run returns a completed run with its event timeline.
+func (c *Client) Run(ctx context.Context, id string) (*Run, error) {
+	var resp Run
+	if _, err := c.do(ctx, http.MethodGet, "/api/runs/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Report writes the report of a completed run to w in the given format
+// (text, json, md or html; empty for text).
+func (c *Client) Report(ctx context.Context, id, format string, w io.Writer) error {
+	path := "/api/runs/" + url.PathEscape(id) + "/report"
+	if format != "" {
+		path += "?format=" + url.QueryEscape(format)
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read report: %w", err)
+	}
+	return nil
+}
+
+// Auth reports whether authentication is enabled and the client's role.
+func (c *Client) Auth(ctx context.Context) (*AuthResponse, error) {
+	var resp AuthResponse
+	if _, err := c.do(ctx, http.MethodGet, "/api/auth", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) (http.Header, error) {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	resp, err := c.send(ctx, method, path, contentType, reader)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.Header, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// send sends a request and returns the response for 2xx status codes.
+// Other status codes are returned as *Error. The caller must close the body.
+func (c *Client) send(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	return resp, nil
+}
@@ -0,0 +1,297 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/api"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+// newTestClient はテスト用のAPIサーバーとクライアントを作成する
+func newTestClient(t *testing.T, serverConfig api.Config, clientConfig Config) *Client {
+	t.Helper()
+
+	s, err := api.NewServerWithConfig(serverConfig)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	handler, err := s.Handler()
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	return New(ts.URL+"/", clientConfig)
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.Timeout != 30*time.Second {
+		t.Errorf("expected timeout 30s, got %v", config.Timeout)
+	}
+	if config.Token != "" {
+		t.Error("expected empty token")
+	}
+}
+
+func TestClientScenarioLifecycle(t *testing.T) {
+	c := newTestClient(t, api.DefaultConfig(), DefaultConfig())
+	ctx := context.Background()
+
+	presets, err := c.Presets(ctx)
+	if err != nil {
+		t.Fatalf("failed to get presets: %v", err)
+	}
+	if len(presets) == 0 {
+		t.Error("expected presets")
+	}
+
+	start, err := c.StartScenario(ctx, ScenarioRequest{
+		Scenario: &ScenarioConfig{Duration: "30s", NodeCount: 3, Zones: []string{"a", "b"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to start scenario: %v", err)
+	}
+	if start.RunID == "" {
+		t.Error("expected run ID")
+	}
+
+	// ノードが起動するまで待機
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if status, err := c.Status(ctx); err == nil && status.RunningNodes == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	page, err := c.Nodes(ctx, NodeQuery{Zone: []string{"a"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to list nodes: %v", err)
+	}
+	if page.Total != 2 || len(page.Nodes) != 1 {
+		t.Errorf("expected 1 of 2 nodes, got %d of %d", len(page.Nodes), page.Total)
+	}
+
+	info, err := c.NodeAction(ctx, "node-1", ActionSuspend)
+	if err != nil {
+		t.Fatalf("failed to suspend node: %v", err)
+	}
+	if info.Status != "suspended" {
+		t.Errorf("expected suspended, got %s", info.Status)
+	}
+
+	detail, err := c.Node(ctx, "node-1")
+	if err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	if detail.ID != "node-1" || detail.Incarnations < 1 {
+		t.Errorf("unexpected node detail: %+v", detail)
+	}
+
+	info, err = c.InjectDelay(ctx, "node-2", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to inject delay: %v", err)
+	}
+	if info.Delay != "20ms" {
+		t.Errorf("expected delay 20ms, got %s", info.Delay)
+	}
+
+	scaled, err := c.ScaleCluster(ctx, 4)
+	if err != nil {
+		t.Fatalf("failed to scale cluster: %v", err)
+	}
+	if scaled.NodeCount != 4 {
+		t.Errorf("expected 4 nodes, got %d", scaled.NodeCount)
+	}
+	if _, err := c.RemoveNode(ctx, scaled.Added[0]); err != nil {
+		t.Errorf("failed to remove node: %v", err)
+	}
+
+	var snapshot bytes.Buffer
+	if err := c.Snapshot(ctx, &snapshot); err != nil {
+		t.Fatalf("failed to download snapshot: %v", err)
+	}
+	restored, err := c.RestoreSnapshot(ctx, &snapshot)
+	if err != nil {
+		t.Fatalf("failed to restore snapshot: %v", err)
+	}
+	if restored.Nodes != 3 {
+		t.Errorf("expected 3 nodes restored, got %d", restored.Nodes)
+	}
+
+	if _, err := c.Metrics(ctx); err != nil {
+		t.Errorf("failed to get metrics: %v", err)
+	}
+
+	stop, err := c.StopScenario(ctx)
+	if err != nil {
+		t.Fatalf("failed to stop scenario: %v", err)
+	}
+	if stop.Result == nil || !stop.Result.Interrupted {
+		t.Error("expected interrupted result")
+	}
+
+	runs, err := c.Runs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list runs: %v", err)
+	}
+	if len(runs) != 1 || runs[0].ID != start.RunID {
+		t.Fatalf("expected run %s, got %+v", start.RunID, runs)
+	}
+
+	run, err := c.Run(ctx, start.RunID)
+	if err != nil {
+		t.Fatalf("failed to get run: %v", err)
+	}
+	if len(run.Timeline) == 0 {
+		t.Error("expected timeline events")
+	}
+
+	recent, err := c.Events(ctx, EventQuery{RunID: start.RunID, Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to get events: %v", err)
+	}
+	if len(recent) != 1 || recent[0].RunID != start.RunID {
+		t.Errorf("expected latest event for run %s, got %+v", start.RunID, recent)
+	}
+
+	var report bytes.Buffer
+	if err := c.Report(ctx, start.RunID, "md", &report); err != nil {
+		t.Fatalf("failed to get report: %v", err)
+	}
+	if !strings.HasPrefix(report.String(), "# Scenario Report") {
+		t.Errorf("unexpected markdown report: %q", report.String())
+	}
+}
+
+func TestClientConcurrentRuns(t *testing.T) {
+	c := newTestClient(t, api.DefaultConfig(), DefaultConfig())
+	ctx := context.Background()
+
+	req := ScenarioRequest{Scenario: &ScenarioConfig{Duration: "30s", NodeCount: 2}}
+	first, err := c.StartRun(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to start first run: %v", err)
+	}
+	second, err := c.StartRun(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to start second run: %v", err)
+	}
+
+	active, err := c.ActiveRuns(ctx)
+	if err != nil {
+		t.Fatalf("failed to list active runs: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("expected 2 active runs, got %d", len(active))
+	}
+
+	status, err := c.RunStatus(ctx, first.RunID)
+	if err != nil {
+		t.Fatalf("failed to get run status: %v", err)
+	}
+	if status.RunID != first.RunID {
+		t.Errorf("expected run %s, got %s", first.RunID, status.RunID)
+	}
+
+	for _, id := range []string{first.RunID, second.RunID} {
+		if _, err := c.StopRun(ctx, id); err != nil {
+			t.Errorf("failed to stop run %s: %v", id, err)
+		}
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	serverConfig := api.DefaultConfig()
+	serverConfig.OperatorToken = "op-secret"
+	ctx := context.Background()
+
+	anonymous := newTestClient(t, serverConfig, DefaultConfig())
+
+	_, err := anonymous.StopScenario(ctx)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 error, got %v", err)
+	}
+
+	_, err = anonymous.Run(ctx, "missing")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+
+	clientConfig := DefaultConfig()
+	clientConfig.Token = "op-secret"
+	operator := newTestClient(t, serverConfig, clientConfig)
+
+	auth, err := operator.Auth(ctx)
+	if err != nil {
+		t.Fatalf("failed to get auth: %v", err)
+	}
+	if !auth.Enabled || auth.Role != "operator" {
+		t.Errorf("unexpected auth: %+v", auth)
+	}
+
+	// 認証は通るが、実行中のシナリオがないため400
+	_, err = operator.StopScenario(ctx)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 error, got %v", err)
+	}
+}
+
+func TestClientLogs(t *testing.T) {
+	serverConfig := api.DefaultConfig()
+	serverConfig.LogBuffer = logger.NewBuffer(10)
+	c := newTestClient(t, serverConfig, DefaultConfig())
+	ctx := context.Background()
+
+	l := logger.New(io.Discard, logger.LevelInfo)
+	l.SetBuffer(serverConfig.LogBuffer)
+	l.Info("", "started")
+	since := time.Now()
+	time.Sleep(time.Millisecond)
+	l.Warn("node-1", "killed")
+	l.With("recovery").Error("", "failed")
+
+	entries, err := c.Logs(ctx, LogQuery{Level: "warn", Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to get logs: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Message != "failed" {
+		t.Errorf("expected newest warn+ entry, got %+v", entries)
+	}
+
+	entries, err = c.Logs(ctx, LogQuery{Since: since})
+	if err != nil {
+		t.Fatalf("failed to get logs: %v", err)
+	}
+	if len(entries) != 2 || entries[0].NodeID != "node-1" {
+		t.Errorf("expected 2 entries since mark, got %+v", entries)
+	}
+
+	entries, err = c.Logs(ctx, LogQuery{NodeID: "node-1", Until: time.Now()})
+	if err != nil {
+		t.Fatalf("failed to get logs: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Message != "killed" {
+		t.Errorf("expected only node-1 entries, got %+v", entries)
+	}
+
+	entries, err = c.Logs(ctx, LogQuery{Components: []string{"recovery"}})
+	if err != nil {
+		t.Fatalf("failed to get logs: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Component != "recovery" {
+		t.Errorf("expected only recovery entries, got %+v", entries)
+	}
+}
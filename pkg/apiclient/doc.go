@@ -0,0 +1,41 @@
+// Package apiclient provides a typed Go client for the ChaosKVS HTTP API.
+//
+// The client covers every JSON endpoint described in the server's OpenAPI
+// document (served at /api/openapi.yaml): scenario control, node fault
+// injection, metrics, and run history. Request and response types
+// are declared here with the same JSON fields as the server's, so the
+// package only depends on public packages.
+//
+// # Basic Usage
+//
+//	c := apiclient.New("http://localhost:8080", apiclient.DefaultConfig())
+//
+//	start, err := c.StartScenario(ctx, apiclient.ScenarioRequest{Preset: "quick"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Inject a fault into a node
+//	_, _ = c.NodeAction(ctx, "node-1", apiclient.ActionKill)
+//
+//	// Stop early and get the partial result
+//	stop, _ := c.StopScenario(ctx)
+//	fmt.Println(stop.Result.TotalRequests)
+//
+//	run, _ := c.Run(ctx, start.RunID)
+//	fmt.Println(len(run.Timeline))
+//
+// # Authentication
+//
+// Set Config.Token when the server is started with --read-token or
+// --operator-token:
+//
+//	config := apiclient.DefaultConfig()
+//	config.Token = os.Getenv("CHAOS_KVS_OPERATOR_TOKEN")
+//	c := apiclient.New(baseURL, config)
+//
+// # Errors
+//
+// Non-2xx responses are returned as *Error, which carries the HTTP status
+// code and the server's message.
+package apiclient
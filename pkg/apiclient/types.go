@@ -0,0 +1,144 @@
+package apiclient
+
+import (
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/config"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/history"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// Types that the server already takes from public packages.
+type (
+	RebalanceResponse = cluster.RebalanceStats
+	ScenarioConfig    = config.ScenarioConfig
+	RunSummary        = history.Summary
+	Run               = history.Run
+	LogEntry          = logger.Entry
+	Event             = events.Event
+)
+
+// StatusResponse is returned by Status.
+type StatusResponse struct {
+	RunID          string `json:"run_id,omitempty"`
+	Running        bool   `json:"running"`
+	ScenarioName   string `json:"scenario_name,omitempty"`
+	NodeCount      int    `json:"node_count"`
+	RunningNodes   int    `json:"running_nodes"`
+	StoppedNodes   int    `json:"stopped_nodes"`
+	SuspendedNodes int    `json:"suspended_nodes"`
+	ReadOnlyNodes  int    `json:"readonly_nodes"`
+}
+
+// NodeInfo describes a node in the running cluster.
+type NodeInfo struct {
+	ID     string            `json:"id"`
+	Status string            `json:"status"`
+	Size   int               `json:"size"`
+	Delay  string            `json:"delay,omitempty"`
+	Zone   string            `json:"zone,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	MemoryUsage  int64   `json:"memory_usage"` // approximate bytes held by the node
+	OpsPerSecond float64 `json:"ops_per_sec"`  // reads and writes in the last second
+}
+
+// NodeDetail is a node with its counters and recent fault events.
+type NodeDetail struct {
+	NodeInfo
+	Incarnations int     `json:"incarnations"` // times the node has been started
+	Ops          NodeOps `json:"ops"`
+	Incidents    []Event `json:"incidents"` // recent fault injections and recoveries, oldest first
+}
+
+// NodeOps counts the operations a node has handled.
+type NodeOps struct {
+	Gets     uint64 `json:"gets"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Sets     uint64 `json:"sets"`
+	Deletes  uint64 `json:"deletes"`
+	Scans    uint64 `json:"scans"`
+	Rejected uint64 `json:"rejected"`
+	Shed     uint64 `json:"shed"`
+	Expired  uint64 `json:"expired"`
+	Evicted  uint64 `json:"evicted"`
+	Lost     uint64 `json:"lost"`
+
+	Conflicts uint64 `json:"conflicts"`
+	Refused   uint64 `json:"refused"`
+	Corrupted uint64 `json:"corrupted"`
+
+	HitRate float64 `json:"hit_rate"`
+}
+
+// NodeActionRequest is the body of a node action (only delay uses it).
+type NodeActionRequest struct {
+	Delay string `json:"delay,omitempty"`
+}
+
+// ScaleRequest is the body of Scale.
+type ScaleRequest struct {
+	Nodes int `json:"nodes"`
+}
+
+// ScaleResponse is returned by Scale.
+type ScaleResponse struct {
+	NodeCount int      `json:"node_count"`
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+}
+
+// RestoreResponse is returned by Restore.
+type RestoreResponse struct {
+	Nodes int `json:"nodes"`
+	Keys  int `json:"keys"`
+}
+
+// MetricsResponse is returned by Metrics.
+type MetricsResponse struct {
+	TotalRequests   uint64  `json:"total_requests"`
+	SuccessRequests uint64  `json:"success_requests"`
+	FailedRequests  uint64  `json:"failed_requests"`
+	RPS             float64 `json:"rps"`
+	AvgLatencyMs    float64 `json:"avg_latency_ms"`
+	P99LatencyMs    float64 `json:"p99_latency_ms"`
+	ErrorRate       float64 `json:"error_rate"`
+}
+
+// ScenarioRequest is the body of StartScenario. Scenario takes the same
+// schema as the scenario section of a config file; Duration and Nodes
+// override both the preset and Scenario.
+type ScenarioRequest struct {
+	Preset   string          `json:"preset"`
+	Scenario *ScenarioConfig `json:"scenario,omitempty"`
+	Duration string          `json:"duration,omitempty"`
+	Nodes    int             `json:"nodes,omitempty"`
+}
+
+// StartResponse is returned by StartScenario.
+type StartResponse struct {
+	Status   string `json:"status"`
+	Scenario string `json:"scenario"`
+	RunID    string `json:"run_id"`
+}
+
+// StopResponse is returned by StopScenario.
+type StopResponse struct {
+	Status string           `json:"status"`
+	RunID  string           `json:"run_id,omitempty"`
+	Result *scenario.Result `json:"result,omitempty"`
+}
+
+// PresetInfo describes a built-in scenario preset.
+type PresetInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// AuthResponse reports whether auth is enabled and the caller's role.
+type AuthResponse struct {
+	Enabled bool   `json:"enabled"`
+	Role    string `json:"role"`
+}
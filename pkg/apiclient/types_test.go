@@ -0,0 +1,64 @@
+package apiclient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/internal/api"
+)
+
+// jsonFields は構造体の JSON フィールド名と型を返す（埋め込みは展開し、apiclient・api の構造体は入れ子のフィールドまで辿る）
+func jsonFields(t reflect.Type, prefix string, fields map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		ft := f.Type
+		if f.Anonymous {
+			jsonFields(ft, prefix, fields)
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		name = prefix + name
+		if ft.Kind() == reflect.Struct && (ft.PkgPath() == reflect.TypeOf(Client{}).PkgPath() || ft.PkgPath() == reflect.TypeOf(api.Config{}).PkgPath()) {
+			jsonFields(ft, name+".", fields)
+			continue
+		}
+		fields[name] = ft.String()
+	}
+}
+
+func TestTypesMatchServer(t *testing.T) {
+	pairs := []struct {
+		client, server any
+	}{
+		{StatusResponse{}, api.StatusResponse{}},
+		{NodeInfo{}, api.NodeInfo{}},
+		{NodeDetail{}, api.NodeDetail{}},
+		{NodeActionRequest{}, api.NodeActionRequest{}},
+		{ScaleRequest{}, api.ScaleRequest{}},
+		{ScaleResponse{}, api.ScaleResponse{}},
+		{RestoreResponse{}, api.RestoreResponse{}},
+		{MetricsResponse{}, api.MetricsResponse{}},
+		{ScenarioRequest{}, api.ScenarioRequest{}},
+		{StartResponse{}, api.StartResponse{}},
+		{StopResponse{}, api.StopResponse{}},
+		{PresetInfo{}, api.PresetInfo{}},
+		{AuthResponse{}, api.AuthResponse{}},
+	}
+
+	for _, p := range pairs {
+		ct, st := reflect.TypeOf(p.client), reflect.TypeOf(p.server)
+		got, want := map[string]string{}, map[string]string{}
+		jsonFields(ct, "", got)
+		jsonFields(st, "", want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s fields %v do not match server %s fields %v", ct.Name(), got, st, want)
+		}
+	}
+}
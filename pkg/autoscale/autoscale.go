@@ -0,0 +1,265 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+)
+
+// log はコンポーネント名 "autoscale" を付けてログを出力する子ロガー
+var log = logger.With("autoscale")
+
+// Config は Controller の設定
+type Config struct {
+	MinNodes int // 減らすときの下限（1未満は1）
+	MaxNodes int // 増やすときの上限（MinNodes 未満は MinNodes）
+	Step     int // 1回に増減するノード数（0以下で1）
+
+	// 増やす条件（区間の値がどちらかを超えたら増やす、0の項目は使わない）
+	ScaleUpRPS float64       // 1ノードあたりのRPS
+	ScaleUpP99 time.Duration // P99レイテンシ（ヒストグラムのバケットの上限で比べる）
+
+	// ScaleDownRPS は1ノードあたりのRPSがこれを下回り、増やす条件を満たさない区間で減らす（0で減らさない）
+	ScaleDownRPS float64
+
+	Interval time.Duration // 負荷を評価する区間
+	Cooldown time.Duration // 増減した後、次に増減するまで待つ時間
+	Prefix   string        // 追加するノードのIDの接頭辞
+	Clock    clock.Clock   // 区間と待ち時間を測る時計（nilで実時間）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		MinNodes:     1,
+		MaxNodes:     10,
+		Step:         1,
+		ScaleUpRPS:   1000,
+		ScaleUpP99:   50 * time.Millisecond,
+		ScaleDownRPS: 200,
+		Interval:     2 * time.Second,
+		Cooldown:     5 * time.Second,
+		Prefix:       "node",
+	}
+}
+
+// Validate は設定が正しいかを確かめる
+func (c Config) Validate() error {
+	if c.MinNodes < 0 || c.MaxNodes < 0 || c.Step < 0 {
+		return fmt.Errorf("autoscale node counts must be non-negative")
+	}
+	if c.MaxNodes > 0 && c.MaxNodes < c.MinNodes {
+		return fmt.Errorf("autoscale max nodes (%d) must not be less than min nodes (%d)", c.MaxNodes, c.MinNodes)
+	}
+	if c.ScaleUpRPS < 0 || c.ScaleDownRPS < 0 || c.ScaleUpP99 < 0 {
+		return fmt.Errorf("autoscale thresholds must be non-negative")
+	}
+	if c.ScaleUpRPS > 0 && c.ScaleDownRPS >= c.ScaleUpRPS {
+		return fmt.Errorf("autoscale scale-down RPS (%g) must be less than scale-up RPS (%g)", c.ScaleDownRPS, c.ScaleUpRPS)
+	}
+	if c.Interval <= 0 || c.Cooldown < 0 {
+		return fmt.Errorf("autoscale interval must be positive and cooldown non-negative")
+	}
+	return nil
+}
+
+// Stats は増減の集計
+type Stats struct {
+	ScaleUps   uint64 // ノードを増やした回数
+	ScaleDowns uint64 // ノードを減らした回数
+	Failures   uint64 // 失敗した増減の回数
+
+	MinNodes int // 評価したときの最小のノード数
+	MaxNodes int // 評価したときの最大のノード数
+
+	LastRPS float64       // 直近の区間の1ノードあたりのRPS
+	LastP99 time.Duration // 直近の区間のP99レイテンシ
+}
+
+// Controller はクライアントのメトリクスを区間ごとに評価し、クラスタのノード数を増減する
+type Controller struct {
+	config  Config
+	cluster *cluster.Cluster
+	metrics *metrics.Metrics
+	clock   clock.Clock
+
+	running atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	// 評価ループだけが触る
+	last      metrics.HistogramSnapshot // 前の区間の終わりのレイテンシのヒストグラム
+	lastAt    time.Time
+	coolUntil time.Time
+
+	mu    sync.RWMutex
+	stats Stats
+}
+
+// New は c のノード数を m のリクエストの負荷に合わせて増減する Controller を作成する
+func New(c *cluster.Cluster, m *metrics.Metrics, config Config) *Controller {
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.Real()
+	}
+	config.MinNodes = max(config.MinNodes, 1)
+	config.MaxNodes = max(config.MaxNodes, config.MinNodes)
+	if config.Step <= 0 {
+		config.Step = 1
+	}
+	return &Controller{
+		config:  config,
+		cluster: c,
+		metrics: m,
+		clock:   clk,
+	}
+}
+
+// Start は評価を開始する
+func (c *Controller) Start(ctx context.Context) {
+	if c.running.Swap(true) {
+		return
+	}
+
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.last = c.metrics.Latency()
+	c.lastAt = c.clock.Now()
+
+	c.wg.Add(1)
+	go c.loop()
+
+	log.Info("", "Autoscaler started (nodes: %d-%d, interval: %v, cooldown: %v)",
+		c.config.MinNodes, c.config.MaxNodes, c.config.Interval, c.config.Cooldown)
+}
+
+// Stop は評価を停止する
+func (c *Controller) Stop() {
+	if !c.running.Swap(false) {
+		return
+	}
+
+	c.cancel()
+	c.wg.Wait()
+
+	stats := c.Stats()
+	log.Info("", "Autoscaler stopped (scale-ups: %d, scale-downs: %d)", stats.ScaleUps, stats.ScaleDowns)
+}
+
+// loop は Interval ごとに負荷を評価する
+func (c *Controller) loop() {
+	defer c.wg.Done()
+
+	ticker := c.clock.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.Chan():
+			c.evaluate()
+		}
+	}
+}
+
+// evaluate は前回からの区間のRPS・P99を求め、必要であればノード数を増減する
+// ノード数が MinNodes・MaxNodes の範囲を外れている場合は負荷によらず範囲に戻す
+func (c *Controller) evaluate() {
+	now := c.clock.Now()
+	latency := c.metrics.Latency()
+	window := latency.Sub(c.last)
+	elapsed := now.Sub(c.lastAt)
+	c.last, c.lastAt = latency, now
+
+	size := c.cluster.Size()
+	var (
+		rps float64
+		p99 time.Duration
+	)
+	if elapsed > 0 {
+		rps = float64(window.Count) / elapsed.Seconds() / float64(max(size, 1))
+	}
+	if window.Count > 0 {
+		p99 = window.Quantile(0.99)
+	}
+
+	c.mu.Lock()
+	if c.stats.MinNodes == 0 || size < c.stats.MinNodes {
+		c.stats.MinNodes = size
+	}
+	c.stats.MaxNodes = max(c.stats.MaxNodes, size)
+	c.stats.LastRPS = rps
+	c.stats.LastP99 = p99
+	c.mu.Unlock()
+
+	if now.Before(c.coolUntil) {
+		return
+	}
+
+	target, reason := c.target(size, rps, p99, window.Count)
+	if target == size {
+		return
+	}
+
+	added, removed, err := c.cluster.ScaleTo(target, c.config.Prefix)
+	c.coolUntil = now.Add(c.config.Cooldown)
+	if err != nil {
+		c.mu.Lock()
+		c.stats.Failures++
+		c.mu.Unlock()
+		log.Warn("", "Autoscaler failed to scale from %d to %d nodes (%s): %v", size, target, reason, err)
+		return
+	}
+
+	c.mu.Lock()
+	if len(added) > 0 {
+		c.stats.ScaleUps++
+	}
+	if len(removed) > 0 {
+		c.stats.ScaleDowns++
+	}
+	c.stats.MaxNodes = max(c.stats.MaxNodes, target)
+	c.stats.MinNodes = min(c.stats.MinNodes, target)
+	c.mu.Unlock()
+
+	log.Info("", "Autoscaler scaled from %d to %d nodes: %s", size, target, reason)
+}
+
+// target は区間の負荷から目標のノード数とその理由を返す（変えない場合は size）
+// リクエストのない区間はノードの障害で負荷が届かなかった可能性があるため、減らさない
+func (c *Controller) target(size int, rps float64, p99 time.Duration, requests uint64) (int, string) {
+	cfg := c.config
+	switch {
+	case size < cfg.MinNodes:
+		return cfg.MinNodes, fmt.Sprintf("below the minimum of %d", cfg.MinNodes)
+	case size > cfg.MaxNodes:
+		return cfg.MaxNodes, fmt.Sprintf("above the maximum of %d", cfg.MaxNodes)
+	case cfg.ScaleUpRPS > 0 && rps > cfg.ScaleUpRPS:
+		return min(size+cfg.Step, cfg.MaxNodes), fmt.Sprintf("%.0f req/s per node above %.0f", rps, cfg.ScaleUpRPS)
+	case cfg.ScaleUpP99 > 0 && p99 > cfg.ScaleUpP99:
+		return min(size+cfg.Step, cfg.MaxNodes), fmt.Sprintf("p99 %v above %v", p99, cfg.ScaleUpP99)
+	case cfg.ScaleDownRPS > 0 && requests > 0 && rps < cfg.ScaleDownRPS:
+		return max(size-cfg.Step, cfg.MinNodes), fmt.Sprintf("%.0f req/s per node below %.0f", rps, cfg.ScaleDownRPS)
+	}
+	return size, ""
+}
+
+// IsRunning は実行中かどうかを返す
+func (c *Controller) IsRunning() bool {
+	return c.running.Load()
+}
+
+// Stats は増減の集計を返す
+func (c *Controller) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stats
+}
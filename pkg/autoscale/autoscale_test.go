@@ -0,0 +1,187 @@
+package autoscale
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+)
+
+// newSimulatedController は仮想時計で動く、ループを開始していない Controller を作成する
+// テストは evaluations で区間を1つずつ進めるため、実時間を待たない
+func newSimulatedController(t *testing.T, nodes int, config Config) (*Controller, *cluster.Cluster, *metrics.Metrics, *clock.Simulated) {
+	t.Helper()
+	c := cluster.New()
+	if err := c.CreateNodes(nodes, "node"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.StopAll() })
+
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	config.Clock = clk
+	m := metrics.New()
+	a := New(c, m, config)
+	a.last = m.Latency()
+	a.lastAt = clk.Now()
+	return a, c, m, clk
+}
+
+// evaluation は rps（クラスタ全体）のリクエストを latency で記録しながら1区間進め、評価する
+func evaluation(a *Controller, m *metrics.Metrics, clk *clock.Simulated, rps int, latency time.Duration) {
+	for range rps * int(a.config.Interval/time.Second) {
+		m.RecordSuccess(latency)
+	}
+	clk.Advance(a.config.Interval)
+	a.evaluate()
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.MinNodes = 2
+	config.MaxNodes = 4
+	config.ScaleUpRPS = 100
+	config.ScaleDownRPS = 20
+	config.ScaleUpP99 = 50 * time.Millisecond
+	config.Interval = time.Second
+	config.Cooldown = 0
+	return config
+}
+
+func TestControllerScalesUpOnRPS(t *testing.T) {
+	a, c, m, clk := newSimulatedController(t, 2, testConfig())
+
+	// 1ノードあたり 50 req/s では変えない
+	evaluation(a, m, clk, 100, time.Millisecond)
+	if c.Size() != 2 {
+		t.Fatalf("expected 2 nodes under moderate load, got %d", c.Size())
+	}
+
+	// 1ノードあたり 150 req/s で増やし、MaxNodes で止める
+	for range 4 {
+		evaluation(a, m, clk, 300*c.Size()/2, time.Millisecond)
+	}
+	if c.Size() != 4 {
+		t.Errorf("expected to grow to the maximum of 4 nodes, got %d", c.Size())
+	}
+	stats := a.Stats()
+	if stats.ScaleUps != 2 || stats.MinNodes != 2 || stats.MaxNodes != 4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestControllerScalesUpOnP99(t *testing.T) {
+	a, c, m, clk := newSimulatedController(t, 2, testConfig())
+
+	evaluation(a, m, clk, 100, 100*time.Millisecond)
+	if c.Size() != 3 {
+		t.Errorf("expected a node to be added for a slow p99, got %d nodes", c.Size())
+	}
+	if p99 := a.Stats().LastP99; p99 != 100*time.Millisecond {
+		t.Errorf("expected the window p99 to be 100ms, got %v", p99)
+	}
+}
+
+func TestControllerScalesDown(t *testing.T) {
+	a, c, m, clk := newSimulatedController(t, 4, testConfig())
+
+	// リクエストのない区間では減らさない
+	evaluation(a, m, clk, 0, 0)
+	if c.Size() != 4 {
+		t.Fatalf("expected no change without requests, got %d nodes", c.Size())
+	}
+
+	for range 4 {
+		evaluation(a, m, clk, 10, time.Millisecond)
+	}
+	if c.Size() != 2 {
+		t.Errorf("expected to shrink to the minimum of 2 nodes, got %d", c.Size())
+	}
+	if stats := a.Stats(); stats.ScaleDowns != 2 {
+		t.Errorf("expected 2 scale-downs, got %+v", stats)
+	}
+	if _, ok := c.GetNode("node-1"); !ok {
+		t.Error("expected the lowest numbered nodes to be kept")
+	}
+}
+
+func TestControllerCooldown(t *testing.T) {
+	config := testConfig()
+	config.Cooldown = 3 * time.Second
+	a, c, m, clk := newSimulatedController(t, 2, config)
+
+	evaluation(a, m, clk, 400, time.Millisecond)
+	evaluation(a, m, clk, 600, time.Millisecond)
+	if c.Size() != 3 {
+		t.Fatalf("expected one scale-up during the cooldown, got %d nodes", c.Size())
+	}
+	evaluation(a, m, clk, 600, time.Millisecond)
+	evaluation(a, m, clk, 600, time.Millisecond)
+	if c.Size() != 4 {
+		t.Errorf("expected another scale-up after the cooldown, got %d nodes", c.Size())
+	}
+}
+
+func TestControllerKeepsBounds(t *testing.T) {
+	a, c, m, clk := newSimulatedController(t, 1, testConfig())
+
+	evaluation(a, m, clk, 0, 0)
+	if c.Size() != 2 {
+		t.Errorf("expected to grow to the minimum of 2 nodes, got %d", c.Size())
+	}
+}
+
+func TestControllerStartStop(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := testConfig()
+	config.Interval = 10 * time.Millisecond
+	m := metrics.New()
+	a := New(c, m, config)
+	a.Start(context.Background())
+	if !a.IsRunning() {
+		t.Error("expected the controller to be running")
+	}
+	for range 1000 {
+		m.RecordSuccess(time.Millisecond)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Size() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	a.Stop()
+	if a.IsRunning() {
+		t.Error("expected the controller to be stopped")
+	}
+	if c.Size() < 3 {
+		t.Errorf("expected the controller to add a node, got %d nodes", c.Size())
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("expected the default config to be valid: %v", err)
+	}
+	for name, modify := range map[string]func(*Config){
+		"max below min":    func(c *Config) { c.MinNodes, c.MaxNodes = 5, 3 },
+		"negative step":    func(c *Config) { c.Step = -1 },
+		"overlapping rps":  func(c *Config) { c.ScaleDownRPS = c.ScaleUpRPS },
+		"zero interval":    func(c *Config) { c.Interval = 0 },
+		"negative latency": func(c *Config) { c.ScaleUpP99 = -time.Millisecond },
+	} {
+		config := DefaultConfig()
+		modify(&config)
+		if err := config.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package autoscale grows and shrinks a cluster with the load the client
+// generates, so a scenario can test elasticity while chaos is injected.
+//
+// # Basic Usage
+//
+//	config := autoscale.DefaultConfig()
+//	config.MinNodes = 3
+//	config.MaxNodes = 8
+//	config.ScaleUpRPS = 500     // add a node above 500 req/s per node
+//	config.ScaleDownRPS = 100   // remove one below 100 req/s per node
+//	config.ScaleUpP99 = 20 * time.Millisecond
+//
+//	controller := autoscale.New(c, client.Metrics(), config)
+//	controller.Start(ctx)
+//	defer controller.Stop()
+//
+// # Decisions
+//
+// Every Interval the Controller takes the requests recorded since the last
+// evaluation from the client's metrics and computes the requests per second
+// per node and the p99 latency of that window. The p99 is read from the
+// latency histogram, so it is the upper bound of the bucket it falls in.
+// When either is above its scale-up threshold the cluster grows by Step
+// nodes; when the rate is below ScaleDownRPS and neither threshold is
+// crossed, it shrinks by Step. The node count always stays between MinNodes
+// and MaxNodes, and after each change the Controller waits Cooldown before
+// it changes the count again, giving the rebalance and the load time to
+// settle.
+//
+// The Controller scales with Cluster.ScaleTo, which rebalances keys onto
+// added nodes and hands the keys of removed nodes to their new replicas.
+// Stopped and suspended nodes still count towards the node count, so
+// killing a node does not by itself add one, while a delay attack that
+// pushes the p99 over ScaleUpP99 does. A window without any requests never
+// shrinks the cluster.
+package autoscale
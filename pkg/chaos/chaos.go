@@ -0,0 +1,631 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// log はコンポーネント名 "chaos" を付けてログを出力する子ロガー
+var log = logger.With("chaos")
+
+// AttackType は障害の種類を表す
+type AttackType int
+
+const (
+	AttackKill AttackType = iota
+	AttackSuspend
+	AttackDelay
+	AttackReadOnly // 読み取りは受け付けたまま書き込みだけを失敗させる
+	AttackCorrupt  // 読み取りの一部で値を壊して返す
+)
+
+func (a AttackType) String() string {
+	switch a {
+	case AttackKill:
+		return "kill"
+	case AttackSuspend:
+		return "suspend"
+	case AttackDelay:
+		return "delay"
+	case AttackReadOnly:
+		return "readonly"
+	case AttackCorrupt:
+		return "corrupt"
+	default:
+		return "unknown"
+	}
+}
+
+// Config はChaosMonkeyの設定
+type Config struct {
+	Interval      time.Duration // 攻撃間隔
+	TargetCount   int           // 同時攻撃対象数
+	AttackTypes   []AttackType  // 有効な攻撃タイプ
+	DelayDuration time.Duration // Delay攻撃時の遅延時間
+	SuspendTime   time.Duration // Suspend・ReadOnly攻撃の継続時間（0で手動Resume）
+	Clock         clock.Clock   // 攻撃間隔と Suspend の継続時間を測る時計（nilで実時間、SetConfig では変更できない）
+	Replay        []Attack      // 再生する攻撃の記録（空で Interval ごとにランダムに攻撃する、SetConfig では変更できない）
+
+	// CorruptRate は Corrupt攻撃で値を1バイト壊して返す読み取りの割合（0〜1）
+	CorruptRate float64
+}
+
+// Attack はカオスモンキーが注入した1回の攻撃の記録
+// 同時に複数のノードを攻撃した場合は At が同じ記録が対象の数だけ並ぶ
+type Attack struct {
+	At     time.Duration     `json:"at"` // Start からの経過時間（ナノ秒）
+	NodeID string            `json:"node_id"`
+	Type   events.AttackType `json:"type"`
+	Delay  time.Duration     `json:"delay,omitempty"` // Delay攻撃の遅延時間（ナノ秒）
+	Rate   float64           `json:"rate,omitempty"`  // Corrupt攻撃で値を壊す読み取りの割合
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Interval:      5 * time.Second,
+		TargetCount:   1,
+		AttackTypes:   []AttackType{AttackKill, AttackSuspend, AttackDelay},
+		DelayDuration: 100 * time.Millisecond,
+		SuspendTime:   3 * time.Second,
+		CorruptRate:   0.1,
+	}
+}
+
+// Stats はカオス攻撃の統計情報
+type Stats struct {
+	TotalAttacks uint64            `json:"total_attacks"`
+	ByType       map[string]uint64 `json:"attacks_by_type"`
+}
+
+// Monkey はカオスエンジニアリングを実行する
+type Monkey struct {
+	config   Config
+	cluster  *cluster.Cluster
+	eventBus *events.Bus
+	clock    clock.Clock // New で決め、SetConfig では変更しない
+
+	running atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu           sync.RWMutex
+	attackCount  uint64
+	attackByType map[AttackType]uint64
+	lastAttack   time.Time
+	suspendedIDs map[string]time.Time
+	started      time.Time // Start した時刻（記録の At の基準）
+	recorded     []Attack
+}
+
+// New は新しいChaosMonkeyを作成する
+func New(c *cluster.Cluster, config Config) *Monkey {
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.Real()
+	}
+	return &Monkey{
+		clock:        clk,
+		config:       config,
+		cluster:      c,
+		suspendedIDs: make(map[string]time.Time),
+		attackByType: make(map[AttackType]uint64),
+	}
+}
+
+// SetEventBus はイベントバスを設定する
+func (m *Monkey) SetEventBus(bus *events.Bus) {
+	m.eventBus = bus
+}
+
+// publishEvent はイベントを発行する
+func (m *Monkey) publishEvent(event events.Event) {
+	if m.eventBus != nil {
+		m.eventBus.Publish(event)
+	}
+}
+
+// Start はカオス注入を開始する
+func (m *Monkey) Start(ctx context.Context) {
+	if m.running.Swap(true) {
+		return
+	}
+
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	m.mu.Lock()
+	m.started = m.clock.Now()
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	if len(m.config.Replay) > 0 {
+		go m.replayLoop(m.config.Replay)
+	} else {
+		go m.attackLoop()
+	}
+
+	if m.config.SuspendTime > 0 {
+		m.wg.Add(1)
+		go m.resumeLoop()
+	}
+
+	log.Info("", "ChaosMonkey started (interval: %v, targets: %d)",
+		m.config.Interval, m.config.TargetCount)
+}
+
+// Stop はカオス注入を停止する
+func (m *Monkey) Stop() {
+	if !m.running.Swap(false) {
+		return
+	}
+
+	m.cancel()
+	m.wg.Wait()
+
+	// 残っているsuspendedノードをresumeする
+	m.resumeAll()
+
+	log.Info("", "ChaosMonkey stopped (total attacks: %d)", m.attackCount)
+}
+
+// attackLoop は定期的に攻撃を実行する
+func (m *Monkey) attackLoop() {
+	defer m.wg.Done()
+
+	ticker := m.clock.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.Chan():
+			m.attack()
+		}
+	}
+}
+
+// resumeLoop はsuspendされたノードを自動的にresumeする
+func (m *Monkey) resumeLoop() {
+	defer m.wg.Done()
+
+	ticker := m.clock.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.Chan():
+			m.checkAndResume()
+		}
+	}
+}
+
+// attack は攻撃を実行する
+func (m *Monkey) attack() {
+	targets := m.selectTargets()
+	if len(targets) == 0 {
+		return
+	}
+
+	attackType := m.selectAttackType()
+
+	for _, n := range targets {
+		if err := m.executeAttack(n, attackType); err == nil {
+			m.record(n.ID(), attackType, m.config.DelayDuration)
+		}
+	}
+
+	m.mu.Lock()
+	m.attackCount++
+	m.lastAttack = m.clock.Now()
+	m.mu.Unlock()
+}
+
+// replayLoop は記録された攻撃を Start からの経過時間に合わせて順に注入する
+// 全ての攻撃を注入した後はランダムな攻撃を行わない
+func (m *Monkey) replayLoop(attacks []Attack) {
+	defer m.wg.Done()
+
+	for i := 0; i < len(attacks); {
+		// 同時に注入した攻撃をまとめて1回の攻撃として扱う
+		at := attacks[i].At
+		j := i + 1
+		for j < len(attacks) && attacks[j].At == at {
+			j++
+		}
+
+		m.mu.RLock()
+		wait := at - m.clock.Since(m.started)
+		m.mu.RUnlock()
+		if err := clock.Sleep(m.ctx, m.clock, wait); err != nil {
+			return
+		}
+		for _, a := range attacks[i:j] {
+			m.replay(a)
+		}
+
+		m.mu.Lock()
+		m.attackCount++
+		m.lastAttack = m.clock.Now()
+		m.mu.Unlock()
+		i = j
+	}
+	log.Info("", "ChaosMonkey: replayed %d attacks", len(attacks))
+}
+
+// replay は記録された1回の攻撃を注入する
+func (m *Monkey) replay(a Attack) {
+	n, exists := m.cluster.GetNode(a.NodeID)
+	if !exists {
+		log.Warn("", "ChaosMonkey: node %s of the recorded attack not found in cluster", a.NodeID)
+		return
+	}
+
+	var err error
+	switch a.Type {
+	case events.AttackTypeKill:
+		err = m.attackKill(n)
+	case events.AttackTypeSuspend:
+		err = m.attackSuspend(n)
+	case events.AttackTypeDelay:
+		err = m.attackDelay(n, a.Delay)
+	case events.AttackTypeReadOnly:
+		err = m.attackReadOnly(n)
+	case events.AttackTypeCorrupt:
+		err = m.attackCorrupt(n, a.Rate)
+	default:
+		log.Warn("", "ChaosMonkey: unknown recorded attack type: %s", a.Type)
+		return
+	}
+	if err == nil {
+		m.recordAttack(a)
+	}
+}
+
+// record は注入した攻撃を記録する
+func (m *Monkey) record(nodeID string, attackType AttackType, delay time.Duration) {
+	a := Attack{NodeID: nodeID}
+	switch attackType {
+	case AttackKill:
+		a.Type = events.AttackTypeKill
+	case AttackSuspend:
+		a.Type = events.AttackTypeSuspend
+	case AttackDelay:
+		a.Type = events.AttackTypeDelay
+		a.Delay = delay
+	case AttackReadOnly:
+		a.Type = events.AttackTypeReadOnly
+	case AttackCorrupt:
+		a.Type = events.AttackTypeCorrupt
+		a.Rate = m.config.CorruptRate
+	}
+	m.recordAttack(a)
+}
+
+// recordAttack は Start からの経過時間を At に設定して攻撃を記録する
+func (m *Monkey) recordAttack(a Attack) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a.At = m.clock.Since(m.started)
+	m.recorded = append(m.recorded, a)
+}
+
+// Recorded はランダムな攻撃・再生した攻撃の記録のコピーを注入した順に返す
+// Inject などの手動の注入は含まない。Config.Replay に渡すと同じ順序・間隔で再生できる
+func (m *Monkey) Recorded() []Attack {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Attack(nil), m.recorded...)
+}
+
+// selectTargets は攻撃対象のノードを選択する
+func (m *Monkey) selectTargets() []*node.Node {
+	nodes := m.cluster.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	// 稼働中のノードのみを対象とする
+	running := make([]*node.Node, 0)
+	for _, n := range nodes {
+		if n.Status() == node.StatusRunning {
+			running = append(running, n)
+		}
+	}
+
+	if len(running) == 0 {
+		return nil
+	}
+
+	// ターゲット数を調整
+	count := m.config.TargetCount
+	if count > len(running) {
+		count = len(running)
+	}
+
+	// ランダムに選択
+	rand.Shuffle(len(running), func(i, j int) {
+		running[i], running[j] = running[j], running[i]
+	})
+
+	return running[:count]
+}
+
+// selectAttackType は攻撃タイプをランダムに選択する
+func (m *Monkey) selectAttackType() AttackType {
+	if len(m.config.AttackTypes) == 0 {
+		return AttackKill
+	}
+	return m.config.AttackTypes[rand.Intn(len(m.config.AttackTypes))]
+}
+
+// executeAttack は指定された攻撃を実行する
+func (m *Monkey) executeAttack(n *node.Node, attackType AttackType) error {
+	switch attackType {
+	case AttackKill:
+		return m.attackKill(n)
+	case AttackSuspend:
+		return m.attackSuspend(n)
+	case AttackDelay:
+		return m.attackDelay(n, m.config.DelayDuration)
+	case AttackReadOnly:
+		return m.attackReadOnly(n)
+	case AttackCorrupt:
+		return m.attackCorrupt(n, m.config.CorruptRate)
+	default:
+		return fmt.Errorf("unknown attack type: %s", attackType)
+	}
+}
+
+// attackKill はノードを強制停止する
+func (m *Monkey) attackKill(n *node.Node) error {
+	if err := n.Kill(); err != nil {
+		log.Warn("", "ChaosMonkey: failed to kill node %s: %v", n.ID(), err)
+		return err
+	}
+	log.Event(logger.LevelWarn, n.ID(), string(events.EventChaosAttack), "ChaosMonkey: killed node %s", n.ID())
+	m.publishEvent(events.NewChaosAttackEvent(n.ID(), events.AttackTypeKill))
+
+	m.mu.Lock()
+	m.attackByType[AttackKill]++
+	m.mu.Unlock()
+	return nil
+}
+
+// attackSuspend はノードを一時停止する
+func (m *Monkey) attackSuspend(n *node.Node) error {
+	if err := n.Suspend(); err != nil {
+		log.Warn("", "ChaosMonkey: failed to suspend node %s: %v", n.ID(), err)
+		return err
+	}
+
+	m.mu.Lock()
+	m.suspendedIDs[n.ID()] = m.clock.Now()
+	m.attackByType[AttackSuspend]++
+	m.mu.Unlock()
+
+	log.Event(logger.LevelWarn, n.ID(), string(events.EventChaosAttack), "ChaosMonkey: suspended node %s", n.ID())
+	m.publishEvent(events.NewChaosAttackEvent(n.ID(), events.AttackTypeSuspend))
+	return nil
+}
+
+// attackReadOnly はノードを読み取り専用にし、書き込みだけを失敗させる
+// Suspend と同じく SuspendTime の経過後に書き込みを再開させる
+func (m *Monkey) attackReadOnly(n *node.Node) error {
+	if err := n.SetReadOnly(true); err != nil {
+		log.Warn("", "ChaosMonkey: failed to make node %s read-only: %v", n.ID(), err)
+		return err
+	}
+
+	m.mu.Lock()
+	m.suspendedIDs[n.ID()] = m.clock.Now()
+	m.attackByType[AttackReadOnly]++
+	m.mu.Unlock()
+
+	log.Event(logger.LevelWarn, n.ID(), string(events.EventChaosAttack), "ChaosMonkey: made node %s read-only", n.ID())
+	m.publishEvent(events.NewChaosAttackEvent(n.ID(), events.AttackTypeReadOnly))
+	return nil
+}
+
+// restore は一時停止・読み取り専用にしたノードを稼働中に戻す
+func restore(n *node.Node) error {
+	if n.Status() == node.StatusReadOnly {
+		return n.SetReadOnly(false)
+	}
+	return n.Resume()
+}
+
+// attackDelay はノードに遅延を注入する
+func (m *Monkey) attackDelay(n *node.Node, d time.Duration) error {
+	n.SetDelay(d)
+	log.Event(logger.LevelWarn, n.ID(), string(events.EventChaosAttack), "ChaosMonkey: injected %v delay to node %s", d, n.ID())
+	m.publishEvent(events.NewChaosAttackEventWithDelay(n.ID(), d))
+
+	m.mu.Lock()
+	m.attackByType[AttackDelay]++
+	m.mu.Unlock()
+	return nil
+}
+
+// attackCorrupt はノードの読み取りのうち rate の割合で値を壊して返すようにする
+// Delay と同じく手動で解除するまで続く
+func (m *Monkey) attackCorrupt(n *node.Node, rate float64) error {
+	n.SetCorruption(rate)
+	log.Event(logger.LevelWarn, n.ID(), string(events.EventChaosAttack), "ChaosMonkey: corrupting %.0f%% of reads on node %s", rate*100, n.ID())
+	m.publishEvent(events.NewChaosAttackEventWithCorruption(n.ID(), rate))
+
+	m.mu.Lock()
+	m.attackByType[AttackCorrupt]++
+	m.mu.Unlock()
+	return nil
+}
+
+// checkAndResume はsuspend時間が経過したノードをresumeする
+func (m *Monkey) checkAndResume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	for nodeID, suspendTime := range m.suspendedIDs {
+		if now.Sub(suspendTime) >= m.config.SuspendTime {
+			if n, exists := m.cluster.GetNode(nodeID); exists {
+				if err := restore(n); err == nil {
+					log.Event(logger.LevelInfo, nodeID, string(events.EventChaosResume), "ChaosMonkey: auto-resumed node %s", nodeID)
+					m.publishEvent(events.NewChaosResumeEvent(nodeID))
+				}
+			}
+			delete(m.suspendedIDs, nodeID)
+		}
+	}
+}
+
+// resumeAll は全てのsuspendedノードをresumeする
+func (m *Monkey) resumeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for nodeID := range m.suspendedIDs {
+		if n, exists := m.cluster.GetNode(nodeID); exists {
+			if err := restore(n); err == nil {
+				log.Info("", "ChaosMonkey: resumed node %s on shutdown", nodeID)
+			}
+		}
+	}
+	m.suspendedIDs = make(map[string]time.Time)
+}
+
+// IsRunning は実行中かどうかを返す
+func (m *Monkey) IsRunning() bool {
+	return m.running.Load()
+}
+
+// AttackCount は攻撃回数を返す
+func (m *Monkey) AttackCount() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.attackCount
+}
+
+// SetConfig は設定を更新する
+func (m *Monkey) SetConfig(config Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	config.Replay = m.config.Replay
+	m.config = config
+}
+
+// Stats は攻撃統計を返す
+func (m *Monkey) Stats() Stats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byType := make(map[string]uint64)
+	for t, count := range m.attackByType {
+		byType[t.String()] = count
+	}
+
+	return Stats{
+		TotalAttacks: m.attackCount,
+		ByType:       byType,
+	}
+}
+
+// Inject は指定ノードに手動で障害を注入する
+// Delay攻撃の場合は設定された DelayDuration が、Corrupt攻撃の場合は CorruptRate が使用される
+func (m *Monkey) Inject(nodeID string, attackType AttackType) error {
+	n, exists := m.cluster.GetNode(nodeID)
+	if !exists {
+		return fmt.Errorf("node %s not found in cluster", nodeID)
+	}
+
+	if err := m.executeAttack(n, attackType); err != nil {
+		return err
+	}
+
+	m.recordManualAttack()
+	return nil
+}
+
+// InjectDelay は指定ノードに任意の遅延を手動で注入する
+// d が 0 の場合は遅延をクリアする
+func (m *Monkey) InjectDelay(nodeID string, d time.Duration) error {
+	n, exists := m.cluster.GetNode(nodeID)
+	if !exists {
+		return fmt.Errorf("node %s not found in cluster", nodeID)
+	}
+
+	if d <= 0 {
+		n.SetDelay(0)
+		log.Info("", "ChaosMonkey: cleared delay on node %s", nodeID)
+		return nil
+	}
+
+	if err := m.attackDelay(n, d); err != nil {
+		return err
+	}
+
+	m.recordManualAttack()
+	return nil
+}
+
+// InjectCorruption は指定ノードの読み取りのうち rate の割合で値を壊して返すようにする
+// rate が 0 の場合は破損をクリアする
+func (m *Monkey) InjectCorruption(nodeID string, rate float64) error {
+	n, exists := m.cluster.GetNode(nodeID)
+	if !exists {
+		return fmt.Errorf("node %s not found in cluster", nodeID)
+	}
+
+	if rate <= 0 {
+		n.SetCorruption(0)
+		log.Info("", "ChaosMonkey: cleared corruption on node %s", nodeID)
+		return nil
+	}
+
+	if err := m.attackCorrupt(n, rate); err != nil {
+		return err
+	}
+
+	m.recordManualAttack()
+	return nil
+}
+
+// Resume は一時停止中・読み取り専用のノードを手動で稼働中に戻す
+func (m *Monkey) Resume(nodeID string) error {
+	n, exists := m.cluster.GetNode(nodeID)
+	if !exists {
+		return fmt.Errorf("node %s not found in cluster", nodeID)
+	}
+
+	if err := restore(n); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.suspendedIDs, nodeID)
+	m.mu.Unlock()
+
+	log.Event(logger.LevelInfo, nodeID, string(events.EventChaosResume), "ChaosMonkey: manually resumed node %s", nodeID)
+	m.publishEvent(events.NewChaosResumeEvent(nodeID))
+	return nil
+}
+
+// recordManualAttack は手動注入を攻撃回数に記録する
+func (m *Monkey) recordManualAttack() {
+	m.mu.Lock()
+	m.attackCount++
+	m.lastAttack = m.clock.Now()
+	m.mu.Unlock()
+}
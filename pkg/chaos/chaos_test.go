@@ -0,0 +1,526 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.Interval != 5*time.Second {
+		t.Errorf("expected interval 5s, got %v", config.Interval)
+	}
+	if config.TargetCount != 1 {
+		t.Errorf("expected target count 1, got %d", config.TargetCount)
+	}
+	if len(config.AttackTypes) != 3 {
+		t.Errorf("expected 3 attack types, got %d", len(config.AttackTypes))
+	}
+}
+
+func TestAttackTypeString(t *testing.T) {
+	tests := []struct {
+		attack   AttackType
+		expected string
+	}{
+		{AttackKill, "kill"},
+		{AttackSuspend, "suspend"},
+		{AttackDelay, "delay"},
+		{AttackReadOnly, "readonly"},
+		{AttackCorrupt, "corrupt"},
+		{AttackType(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attack.String(); got != tt.expected {
+			t.Errorf("AttackType(%d).String() = %s, want %s", tt.attack, got, tt.expected)
+		}
+	}
+}
+
+func TestNewMonkey(t *testing.T) {
+	c := cluster.New()
+	config := DefaultConfig()
+
+	monkey := New(c, config)
+
+	if monkey == nil {
+		t.Fatal("expected non-nil monkey")
+	}
+	if monkey.IsRunning() {
+		t.Error("expected monkey to not be running initially")
+	}
+}
+
+func TestMonkeyStartStop(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.Interval = 100 * time.Millisecond
+
+	monkey := New(c, config)
+
+	ctx := context.Background()
+	monkey.Start(ctx)
+
+	if !monkey.IsRunning() {
+		t.Error("expected monkey to be running after Start")
+	}
+
+	// 少し待ってから停止
+	time.Sleep(50 * time.Millisecond)
+
+	monkey.Stop()
+
+	if monkey.IsRunning() {
+		t.Error("expected monkey to not be running after Stop")
+	}
+}
+
+func TestMonkeyAttackKill(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.Interval = 50 * time.Millisecond
+	config.TargetCount = 1
+	config.AttackTypes = []AttackType{AttackKill}
+
+	monkey := New(c, config)
+
+	ctx := context.Background()
+	monkey.Start(ctx)
+
+	// 攻撃が発生するまで待つ
+	time.Sleep(100 * time.Millisecond)
+
+	monkey.Stop()
+
+	// 少なくとも1つのノードがStoppedになっているはず
+	stoppedCount := 0
+	for _, n := range c.Nodes() {
+		if n.Status() == node.StatusStopped {
+			stoppedCount++
+		}
+	}
+
+	if stoppedCount == 0 {
+		t.Error("expected at least one node to be killed")
+	}
+}
+
+func TestMonkeyAttackSuspend(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.Interval = 50 * time.Millisecond
+	config.TargetCount = 1
+	config.AttackTypes = []AttackType{AttackSuspend}
+	config.SuspendTime = 200 * time.Millisecond
+
+	monkey := New(c, config)
+
+	ctx := context.Background()
+	monkey.Start(ctx)
+
+	// 攻撃が発生するまで待つ
+	time.Sleep(100 * time.Millisecond)
+
+	// 少なくとも1つのノードがSuspendedになっているはず
+	suspendedCount := 0
+	for _, n := range c.Nodes() {
+		if n.Status() == node.StatusSuspended {
+			suspendedCount++
+		}
+	}
+
+	if suspendedCount == 0 {
+		t.Error("expected at least one node to be suspended")
+	}
+
+	// 自動復旧を待つ
+	time.Sleep(300 * time.Millisecond)
+
+	monkey.Stop()
+
+	// 停止後、すべてのsuspendedノードがresumeされているはず
+	for _, n := range c.Nodes() {
+		if n.Status() == node.StatusSuspended {
+			t.Error("expected all suspended nodes to be resumed after Stop")
+		}
+	}
+}
+
+func TestMonkeyAttackReadOnly(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.Interval = time.Hour
+	config.SuspendTime = 50 * time.Millisecond
+
+	monkey := New(c, config)
+	if err := monkey.Inject("node-1", AttackReadOnly); err != nil {
+		t.Fatalf("Inject failed: %v", err)
+	}
+
+	n, _ := c.GetNode("node-1")
+	if n.Status() != node.StatusReadOnly {
+		t.Fatalf("expected node-1 to be read-only, got %s", n.Status())
+	}
+	if err := n.Set("key", []byte("value")); !errors.Is(err, node.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if got := monkey.Stats().ByType["readonly"]; got != 1 {
+		t.Errorf("expected 1 readonly attack, got %d", got)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	monkey.checkAndResume()
+
+	if n.Status() != node.StatusRunning {
+		t.Errorf("expected node-1 to be writable again, got %s", n.Status())
+	}
+}
+
+func TestMonkeyAttackDelay(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.Interval = 50 * time.Millisecond
+	config.TargetCount = 1
+	config.AttackTypes = []AttackType{AttackDelay}
+	config.DelayDuration = 50 * time.Millisecond
+
+	monkey := New(c, config)
+
+	ctx := context.Background()
+	monkey.Start(ctx)
+
+	// 攻撃が発生するまで待つ
+	time.Sleep(100 * time.Millisecond)
+
+	monkey.Stop()
+
+	// ノードに遅延が設定されているはず
+	nodes := c.Nodes()
+	if len(nodes) > 0 {
+		if nodes[0].Delay() != config.DelayDuration {
+			t.Errorf("expected delay %v, got %v", config.DelayDuration, nodes[0].Delay())
+		}
+	}
+}
+
+func TestMonkeyAttackCount(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.Interval = 30 * time.Millisecond
+	config.TargetCount = 1
+	config.AttackTypes = []AttackType{AttackDelay} // Delayはノードを停止しない
+
+	monkey := New(c, config)
+
+	ctx := context.Background()
+	monkey.Start(ctx)
+
+	// 複数回の攻撃を待つ
+	time.Sleep(100 * time.Millisecond)
+
+	monkey.Stop()
+
+	if monkey.AttackCount() == 0 {
+		t.Error("expected at least one attack to be executed")
+	}
+}
+
+func TestMonkeyNoTargets(t *testing.T) {
+	c := cluster.New()
+	// ノードを追加しない
+
+	config := DefaultConfig()
+	config.Interval = 50 * time.Millisecond
+
+	monkey := New(c, config)
+
+	ctx := context.Background()
+	monkey.Start(ctx)
+
+	time.Sleep(100 * time.Millisecond)
+
+	monkey.Stop()
+
+	// ターゲットがないので攻撃は0回
+	if monkey.AttackCount() != 0 {
+		t.Errorf("expected 0 attacks with no targets, got %d", monkey.AttackCount())
+	}
+}
+
+func TestMonkeySetConfig(t *testing.T) {
+	c := cluster.New()
+	config := DefaultConfig()
+
+	monkey := New(c, config)
+
+	newConfig := Config{
+		Interval:    1 * time.Second,
+		TargetCount: 5,
+	}
+	monkey.SetConfig(newConfig)
+
+	if monkey.config.TargetCount != 5 {
+		t.Errorf("expected target count 5, got %d", monkey.config.TargetCount)
+	}
+}
+
+func TestMonkeyInject(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	monkey := New(c, DefaultConfig())
+
+	if err := monkey.Inject("node-1", AttackKill); err != nil {
+		t.Fatalf("failed to inject kill: %v", err)
+	}
+	n1, _ := c.GetNode("node-1")
+	if n1.Status() != node.StatusStopped {
+		t.Errorf("expected node-1 to be stopped, got %v", n1.Status())
+	}
+
+	// 停止済みノードへのkillは失敗する
+	if err := monkey.Inject("node-1", AttackKill); err == nil {
+		t.Error("expected error when killing stopped node")
+	}
+
+	if err := monkey.Inject("node-2", AttackSuspend); err != nil {
+		t.Fatalf("failed to inject suspend: %v", err)
+	}
+	if err := monkey.Resume("node-2"); err != nil {
+		t.Errorf("failed to resume node: %v", err)
+	}
+	n2, _ := c.GetNode("node-2")
+	if n2.Status() != node.StatusRunning {
+		t.Errorf("expected node-2 to be running, got %v", n2.Status())
+	}
+
+	if err := monkey.Inject("missing", AttackKill); err == nil {
+		t.Error("expected error for non-existent node")
+	}
+
+	stats := monkey.Stats()
+	if stats.TotalAttacks != 2 {
+		t.Errorf("expected 2 attacks, got %d", stats.TotalAttacks)
+	}
+	if stats.ByType["kill"] != 1 || stats.ByType["suspend"] != 1 {
+		t.Errorf("unexpected attacks by type: %v", stats.ByType)
+	}
+}
+
+func TestMonkeyInjectDelay(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	monkey := New(c, DefaultConfig())
+
+	if err := monkey.InjectDelay("node-1", 30*time.Millisecond); err != nil {
+		t.Fatalf("failed to inject delay: %v", err)
+	}
+	n, _ := c.GetNode("node-1")
+	if n.Delay() != 30*time.Millisecond {
+		t.Errorf("expected delay 30ms, got %v", n.Delay())
+	}
+
+	if err := monkey.InjectDelay("node-1", 0); err != nil {
+		t.Fatalf("failed to clear delay: %v", err)
+	}
+	if n.Delay() != 0 {
+		t.Errorf("expected delay to be cleared, got %v", n.Delay())
+	}
+	if monkey.AttackCount() != 1 {
+		t.Errorf("expected 1 attack, got %d", monkey.AttackCount())
+	}
+}
+
+func TestMonkeyInjectCorruption(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.CorruptRate = 1
+	monkey := New(c, config)
+
+	if err := monkey.Inject("node-1", AttackCorrupt); err != nil {
+		t.Fatalf("failed to inject corruption: %v", err)
+	}
+	n, _ := c.GetNode("node-1")
+	if n.Corruption() != 1 {
+		t.Errorf("expected corruption rate 1, got %v", n.Corruption())
+	}
+	_ = n.Set("key", []byte("value"))
+	if value, _ := n.Get("key"); string(value) == "value" {
+		t.Error("expected a corrupted read")
+	}
+
+	if err := monkey.InjectCorruption("node-1", 0); err != nil {
+		t.Fatalf("failed to clear corruption: %v", err)
+	}
+	if n.Corruption() != 0 {
+		t.Errorf("expected corruption to be cleared, got %v", n.Corruption())
+	}
+	if got := monkey.Stats().ByType["corrupt"]; got != 1 {
+		t.Errorf("expected 1 corrupt attack, got %d", got)
+	}
+}
+
+// waitAttacks は攻撃回数が n に達するまで待つ
+func waitAttacks(t *testing.T, m *Monkey, n uint64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for m.AttackCount() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d attacks, got %d", n, m.AttackCount())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMonkeyRecorded(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	config := DefaultConfig()
+	config.Interval = time.Second
+	config.TargetCount = 2
+	config.AttackTypes = []AttackType{AttackDelay} // Delayはノードを停止しない
+	config.DelayDuration = 10 * time.Millisecond
+	config.SuspendTime = 0
+	config.Clock = clk
+
+	monkey := New(c, config)
+	monkey.Start(context.Background())
+	defer monkey.Stop()
+
+	_ = monkey.Inject("node-1", AttackKill) // 手動の注入は記録しない
+	_ = c.StartAll(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the monkey to wait on the clock: %v", err)
+	}
+	for i := range 2 {
+		clk.Advance(time.Second)
+		waitAttacks(t, monkey, uint64(i+2))
+	}
+
+	recorded := monkey.Recorded()
+	if len(recorded) != 4 {
+		t.Fatalf("expected 4 recorded attacks, got %+v", recorded)
+	}
+	for i, a := range recorded {
+		if want := time.Duration(i/2+1) * time.Second; a.At != want {
+			t.Errorf("attack %d: expected at %v, got %v", i, want, a.At)
+		}
+		if a.Type != events.AttackTypeDelay || a.Delay != config.DelayDuration || a.NodeID == "" {
+			t.Errorf("attack %d: unexpected record %+v", i, a)
+		}
+	}
+	if recorded[0].NodeID == recorded[1].NodeID {
+		t.Errorf("expected distinct targets in one attack, got %+v", recorded[:2])
+	}
+}
+
+func TestMonkeyReplay(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	config := DefaultConfig()
+	config.Interval = time.Millisecond // 再生中はランダムに攻撃しない
+	config.SuspendTime = 0
+	config.Clock = clk
+	config.Replay = []Attack{
+		{At: time.Second, NodeID: "node-1", Type: events.AttackTypeKill},
+		{At: time.Second, NodeID: "node-2", Type: events.AttackTypeDelay, Delay: 50 * time.Millisecond},
+		{At: 3 * time.Second, NodeID: "node-9", Type: events.AttackTypeKill},
+		{At: 3 * time.Second, NodeID: "node-3", Type: events.AttackTypeSuspend},
+	}
+
+	monkey := New(c, config)
+	monkey.Start(context.Background())
+	defer monkey.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the replay to wait on the clock: %v", err)
+	}
+	clk.Advance(time.Second)
+	waitAttacks(t, monkey, 1)
+
+	n1, _ := c.GetNode("node-1")
+	n2, _ := c.GetNode("node-2")
+	n3, _ := c.GetNode("node-3")
+	if n1.Status() != node.StatusStopped || n2.Delay() != 50*time.Millisecond || n3.Status() != node.StatusRunning {
+		t.Errorf("unexpected state after the first attack: %v, %v, %v", n1.Status(), n2.Delay(), n3.Status())
+	}
+
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the replay to wait for the next attack: %v", err)
+	}
+	clk.Advance(2 * time.Second)
+	waitAttacks(t, monkey, 2)
+	if n3.Status() != node.StatusSuspended {
+		t.Errorf("expected node-3 to be suspended, got %v", n3.Status())
+	}
+
+	// 存在しないノードへの攻撃は注入せず、記録しない
+	recorded := monkey.Recorded()
+	want := []Attack{config.Replay[0], config.Replay[1], config.Replay[3]}
+	if len(recorded) != len(want) {
+		t.Fatalf("expected %d recorded attacks, got %+v", len(want), recorded)
+	}
+	for i := range want {
+		if recorded[i] != want[i] {
+			t.Errorf("attack %d: expected %+v, got %+v", i, want[i], recorded[i])
+		}
+	}
+	if stats := monkey.Stats(); stats.TotalAttacks != 2 || stats.ByType["kill"] != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -8,6 +8,8 @@
 // - Kill: ノードを強制停止
 // - Suspend: ノードを一時停止（リクエストを受け付けなくなる）
 // - Delay: ノードのレスポンスに遅延を注入
+// - ReadOnly: ノードを読み取り専用にする（Get は成功し、書き込みだけが失敗する）
+// - Corrupt: 読み取りのうち CorruptRate の割合で、値を1バイト壊して返す（格納した値は変わらない）
 //
 // # 使用例
 //
@@ -18,4 +20,12 @@
 //	monkey := chaos.New(cluster, config)
 //	monkey.Start(ctx)
 //	defer monkey.Stop()
+//
+// # 記録と再生
+//
+// Monkey はランダムに注入した攻撃の対象・種類・Start からの経過時間を記録する。
+// Recorded の記録を別の実行の Config.Replay に渡すと、ランダムに選ぶ代わりに
+// 同じ攻撃を同じ間隔で注入するため、探索で見つけた障害の順序を回帰テストとして再現できる。
+//
+//	config.Replay = previous.Recorded()
 package chaos
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+	"github.com/nyasuto/chaos-kvs/pkg/worker"
+)
+
+// BatchKV は複数のキーをまとめて読み書きできるリクエストの送り先
+// *node.Node はそのまま BatchKV として使える。実装しない KV にはキーごとに送る
+type BatchKV interface {
+	MGetContext(ctx context.Context, keys []string) (map[string][]byte, error)
+	MSetContext(ctx context.Context, entries map[string][]byte) error
+}
+
+// mget は kv でキーの値をまとめて取得する（BatchKV でない場合はキーごとに取得する）
+func mget(ctx context.Context, kv KV, keys []string) (map[string][]byte, error) {
+	if b, ok := kv.(BatchKV); ok {
+		return b.MGetContext(ctx, keys)
+	}
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, found, err := kv.GetContext(ctx, key)
+		if err != nil {
+			return values, err
+		}
+		if found {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// mset は kv でキーに値をまとめて設定する（BatchKV でない場合はキーごとに設定する）
+func mset(ctx context.Context, kv KV, entries map[string][]byte) error {
+	if b, ok := kv.(BatchKV); ok {
+		return b.MSetContext(ctx, entries)
+	}
+	for key, value := range entries {
+		if err := kv.SetContext(ctx, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// randomKeys はキーの範囲から、router で同じノードが担当する重複のない count 個のキーを選び、担当ノードとともに返す
+// ランダムに選んだキーから範囲を順にたどるため、担当するキーが少ない場合はその数まで
+// バッチのキーはすべて同じ名前空間に付ける
+func (c *Client) randomKeys(router *cluster.Router, count int) (*node.Node, []string) {
+	ns := c.randomNamespace()
+	start := rand.Intn(c.config.KeyRange)
+	first := node.NamespaceKey(ns, fmt.Sprintf("key-%d", start))
+	owner, _ := router.Owner(first)
+	keys := []string{first}
+	for i := 1; i < c.config.KeyRange && len(keys) < count; i++ {
+		key := node.NamespaceKey(ns, fmt.Sprintf("key-%d", (start+i)%c.config.KeyRange))
+		if n, _ := router.Owner(key); n == owner {
+			keys = append(keys, key)
+		}
+	}
+	return owner, keys
+}
+
+// createBatchJob はノード n に keys をまとめて読み書きするリクエストジョブを作成する
+// バッチ全体を1リクエストとして計測する
+func (c *Client) createBatchJob(n *node.Node, keys []string, isWrite bool) worker.Job {
+	kv := c.kv(n)
+	return func(ctx context.Context) {
+		var span *tracing.Span
+		if c.traceRoot != nil {
+			name := "MGET"
+			if isWrite {
+				name = "MSET"
+			}
+			if span = c.traceRoot.Sample(name); span != nil {
+				span.SetKind(tracing.KindClient)
+				span.SetAttributes(tracing.String("node.id", n.ID()), tracing.Int("kv.batch_size", int64(len(keys))))
+			}
+		}
+
+		start := time.Now()
+		err := c.cluster.Forward(ctx, n)
+		switch {
+		case err != nil:
+		case isWrite:
+			entries := make(map[string][]byte, len(keys))
+			for _, key := range keys {
+				value := make([]byte, c.config.ValueSize)
+				if _, randErr := cryptorand.Read(value); randErr != nil {
+					log.Warn("", "Failed to generate random value: %v", randErr)
+				}
+				entries[key] = value
+			}
+			err = mset(ctx, kv, entries)
+		default:
+			_, err = mget(ctx, kv, keys)
+		}
+
+		latency := time.Since(start)
+		if span != nil {
+			span.SetError(err)
+			span.End()
+		}
+		if errors.Is(err, context.Canceled) || err != nil && c.ctx.Err() != nil {
+			return // 停止・シナリオの終了による中断は記録しない
+		}
+		if err != nil {
+			c.recordFailure(err, latency)
+		} else {
+			c.metrics.RecordSuccess(latency)
+		}
+		if c.keyStats != nil {
+			for _, key := range keys {
+				if c.keyStats.sample() {
+					c.keyStats.Record(key, n.ID(), err != nil)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClientBatchSize(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.BatchSize = 8
+	config.KeyRange = 100
+	client := New(c, config)
+	snapshot := client.RunRequests(ctx, 50)
+
+	// バッチ全体を1リクエストとして数え、ノードにはキーごとの操作として届く
+	var ops uint64
+	for _, n := range c.Nodes() {
+		stats := n.Stats()
+		ops += stats.Gets + stats.Sets
+	}
+	if snapshot.TotalRequests < 50 || snapshot.FailedRequests != 0 {
+		t.Fatalf("expected at least 50 successful batches, got %d (%d failed)", snapshot.TotalRequests, snapshot.FailedRequests)
+	}
+	if ops < snapshot.TotalRequests*8 {
+		t.Errorf("expected 8 keys per batch, got %d operations for %d batches", ops, snapshot.TotalRequests)
+	}
+}
+
+func TestClientBatchFallback(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	// BatchKV でない送り先にはキーごとに送る
+	kv := &failingKV{}
+	config := DefaultConfig()
+	config.BatchSize = 4
+	client := New(c, config)
+	client.SetTransport(func(*node.Node) KV { return kv })
+
+	snapshot := client.RunRequests(ctx, 20)
+	if kv.calls.Load() == 0 || snapshot.FailedRequests == 0 {
+		t.Errorf("expected the batches to go through the transport, got %d calls", kv.calls.Load())
+	}
+}
+
+func TestClientRandomKeys(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	config := DefaultConfig()
+	config.KeyRange = 5
+	client := New(c, config)
+
+	n, keys := client.randomKeys(c.Router(), 10)
+	if n == nil || len(keys) != 5 {
+		t.Fatalf("expected the batch to be capped at the key range, got %v", keys)
+	}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if seen[key] {
+			t.Errorf("expected distinct keys, got %v", keys)
+		}
+		seen[key] = true
+	}
+}
+
+func TestClientRandomKeysSameOwner(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	config := DefaultConfig()
+	config.KeyRange = 1000
+	client := New(c, config)
+
+	// バッチのキーはすべて返したノードが担当する
+	for range 20 {
+		n, keys := client.randomKeys(c.Router(), 8)
+		if len(keys) != 8 {
+			t.Fatalf("expected 8 keys, got %v", keys)
+		}
+		for _, key := range keys {
+			if owner, _ := c.Owner(key); owner != n {
+				t.Errorf("key %s is owned by %s, not %s", key, owner.ID(), n.ID())
+			}
+		}
+	}
+}
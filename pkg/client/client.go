@@ -0,0 +1,598 @@
+package client
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/lincheck"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+	"github.com/nyasuto/chaos-kvs/pkg/worker"
+)
+
+// log はコンポーネント名 "client" を付けてログを出力する子ロガー
+var log = logger.With("client")
+
+// Config はClientの設定
+type Config struct {
+	NumWorkers    int     // ワーカー数（0でCPU数）
+	WriteRatio    float64 // Write比率（0.0〜1.0）
+	KeyRange      int     // キーの範囲（0〜KeyRange-1）
+	ValueSize     int     // 値のサイズ（バイト）
+	RequestsLimit uint64  // リクエスト上限（0で無制限）
+
+	// TargetRPS は生成するリクエストの秒間の目標数（0でワーカーが処理できるだけ生成する）
+	// ワーカーが追いつかない場合は目標を下回る
+	TargetRPS float64
+
+	// RequestTimeout は1リクエストの上限時間（0で無制限）
+	// 注入された遅延でこれを超えたリクエストは失敗として記録する
+	RequestTimeout time.Duration
+
+	// MaxInFlight は注入された遅延をワーカーを使わずに待つリクエストの同時の上限（0以下でワーカーで待つ）
+	// 遅延はノードのタイマーで待ち（node.GetAsync・SetAsync）、ワーカーはすぐに次のリクエストに移る
+	// 上限に達している間、ワーカーは空きができるまで次のリクエストを送らない
+	// 送り先を SetTransport で変えた場合と、レプリカ・転送の遅延のあるリクエストはワーカーで待つ
+	MaxInFlight int
+
+	// BatchSize は1リクエストでまとめて読み書きするキーの数（0・1で1キーずつ）
+	// 2以上の場合は MGet・MSet で1つのノードに BatchSize 個のキーを送り、まとめて1リクエストとして計測する
+	// 操作の履歴（SetRecorder）には記録しない
+	BatchSize int
+
+	// ScanRatio はキーの前方一致のスキャンとして送るリクエストの割合（0.0〜1.0、残りを WriteRatio で読み書きに分ける）
+	// ランダムなキーを前方一致の条件とし、キーの昇順に最大 ScanLimit 件（0で DefaultScanLimit）を読み取る
+	// スキャンは ScanMetrics にも記録し、操作の履歴（SetRecorder）には記録しない
+	ScanRatio float64
+	ScanLimit int
+
+	// KeySampleRate はキーごとのアクセス数を記録するリクエストの割合（0で記録しない）
+	// 集計は KeyStats で参照し、ホットキーと攻撃中のノードの過負荷の関係を調べるのに使う
+	KeySampleRate float64
+
+	// Namespaces はリクエストを振り分ける名前空間（空で名前空間を付けない）
+	// リクエストごとにランダムに1つ選び、node.NamespaceKey で名前空間を付けたキーを送る
+	// 複数のテナントが同じノードを共有する状況の再現に使う
+	Namespaces []string
+
+	// エラーバースト検知（イベントバス設定時のみ、ErrorBurstRate が0で無効）
+	ErrorBurstRate   float64       // バーストとみなす区間内のエラー率
+	ErrorBurstWindow time.Duration // エラー率を計算する区間
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		NumWorkers:    0,   // CPU数
+		WriteRatio:    0.5, // 50% Write
+		KeyRange:      10000,
+		ValueSize:     100,
+		RequestsLimit: 0,
+
+		MaxInFlight:      DefaultMaxInFlight,
+		ErrorBurstRate:   0.1,
+		ErrorBurstWindow: time.Second,
+	}
+}
+
+// DefaultMaxInFlight はワーカーを使わずに遅延を待つリクエストの同時の上限の既定値
+const DefaultMaxInFlight = 1024
+
+// KV はリクエストの送り先（ノードを直接呼び出すか、ネットワーク越しに呼び出すか）
+// *node.Node はそのまま KV として使える
+type KV interface {
+	GetContext(ctx context.Context, key string) ([]byte, bool, error)
+	SetContext(ctx context.Context, key string, value []byte) error
+}
+
+// requestBatchSize はワーカープールにまとめて送信するリクエスト数
+const requestBatchSize = 32
+
+// Client は負荷生成器
+type Client struct {
+	config    Config
+	cluster   *cluster.Cluster
+	pool      *worker.Pool
+	metrics   *metrics.Metrics
+	eventBus  *events.Bus
+	transport func(n *node.Node) KV
+	recorder  *lincheck.Recorder
+	traceRoot *tracing.Span
+	keyStats  *KeyStats     // KeySampleRate が0の場合は nil
+	targetRPS atomic.Uint64 // 秒間の目標数の float64 のビット列
+
+	// scanMetrics はスキャンだけのメトリクス（ScanRatio が0の場合は nil）
+	scanMetrics *metrics.Metrics
+
+	// capacityFailures はノードのメモリ使用量の上限（node.ErrCapacity）で失敗したリクエストの数
+	capacityFailures atomic.Uint64
+
+	// inflight はワーカーを使わずに遅延を待つリクエストの枠（MaxInFlight が0以下の場合は nil）
+	// pending はその完了を Stop で待つ
+	inflight chan struct{}
+	pending  sync.WaitGroup
+
+	running atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// New は新しいClientを作成する
+func New(c *cluster.Cluster, config Config) *Client {
+	poolConfig := worker.DefaultPoolConfig()
+	poolConfig.NumWorkers = config.NumWorkers
+	poolConfig.JobTimeout = config.RequestTimeout
+
+	cl := &Client{
+		config:  config,
+		cluster: c,
+		pool:    worker.NewPoolWithConfig(poolConfig),
+		metrics: metrics.New(),
+	}
+	if config.KeySampleRate > 0 {
+		cl.keyStats = NewKeyStats(config.KeySampleRate)
+	}
+	if config.ScanRatio > 0 {
+		cl.scanMetrics = metrics.New()
+	}
+	if config.MaxInFlight > 0 {
+		cl.inflight = make(chan struct{}, config.MaxInFlight)
+	}
+	cl.SetTargetRPS(config.TargetRPS)
+	return cl
+}
+
+// SetEventBus はエラーバーストのイベントを発行するバスを設定する
+func (c *Client) SetEventBus(bus *events.Bus) {
+	c.eventBus = bus
+}
+
+// SetTransport はノードへのリクエストの送り先を返す関数を設定する（Start の前に呼ぶ）
+// nil（デフォルト）の場合はノードを直接呼び出す
+func (c *Client) SetTransport(transport func(n *node.Node) KV) {
+	c.transport = transport
+}
+
+// SetRecorder は操作の履歴を記録するレコーダーを設定する（Start の前に呼ぶ）
+// 停止・シナリオの終了で中断された操作も、結果が不明な操作として記録する
+func (c *Client) SetRecorder(r *lincheck.Recorder) {
+	c.recorder = r
+}
+
+// SetTraceParent はサンプリングしたリクエストを parent の子スパンとして記録するよう設定する（Start の前に呼ぶ）
+func (c *Client) SetTraceParent(parent *tracing.Span) {
+	c.traceRoot = parent
+}
+
+// kv はノード n へのリクエストの送り先を返す
+func (c *Client) kv(n *node.Node) KV {
+	if c.transport == nil {
+		return n
+	}
+	return c.transport(n)
+}
+
+// Start は負荷生成を開始する
+func (c *Client) Start(ctx context.Context) {
+	if c.running.Swap(true) {
+		return // Already running
+	}
+
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.pool.Start(c.ctx)
+
+	log.Info("", "Client started (workers: %d, write_ratio: %.1f%%)",
+		c.pool.NumWorkers(), c.config.WriteRatio*100)
+
+	// リクエスト生成ループ
+	c.wg.Add(1)
+	go c.generateRequests()
+
+	if c.eventBus != nil && c.config.ErrorBurstRate > 0 && c.config.ErrorBurstWindow > 0 {
+		c.wg.Add(1)
+		go c.watchErrorBursts()
+	}
+}
+
+// watchErrorBursts は区間ごとのエラー率を監視し、バーストに入った時点でイベントを発行する
+// バースト中は区間ごとに発行せず、エラー率が閾値を下回るまで次のバーストとみなさない
+func (c *Client) watchErrorBursts() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.config.ErrorBurstWindow)
+	defer ticker.Stop()
+
+	lastTotal, lastFailed := c.metrics.TotalRequests(), c.metrics.FailedRequests()
+	inBurst := false
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		total, failed := c.metrics.TotalRequests(), c.metrics.FailedRequests()
+		requests, errors := total-lastTotal, failed-lastFailed
+		lastTotal, lastFailed = total, failed
+
+		// 遅れて届いた tick で区間が空になった場合は、バーストの状態を変えない
+		if requests == 0 {
+			continue
+		}
+		bursting := errors > 0 && float64(errors)/float64(requests) >= c.config.ErrorBurstRate
+		if bursting && !inBurst {
+			log.Warn("", "Client error burst: %d/%d requests failed in %v", errors, requests, c.config.ErrorBurstWindow)
+			c.eventBus.Publish(events.NewClientErrorBurstEvent(errors, requests, c.config.ErrorBurstWindow))
+		}
+		inBurst = bursting
+	}
+}
+
+// generateRequests はリクエストを生成し続ける
+func (c *Client) generateRequests() {
+	defer c.wg.Done()
+
+	router := c.cluster.Router()
+	if len(router.Nodes()) == 0 {
+		log.Error("", "No nodes available in cluster")
+		return
+	}
+	c.declareNamespaces(router)
+
+	batch := make([]worker.Job, 0, requestBatchSize)
+	var pace pacer
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+
+		// 目標の秒間リクエスト数がある場合は、経過時間に応じた数だけ生成する
+		count := requestBatchSize
+		if rps := c.TargetRPS(); rps > 0 {
+			var wait time.Duration
+			if count, wait = pace.due(time.Now(), rps, requestBatchSize); count == 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-c.ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				continue
+			}
+		} else {
+			pace.reset()
+		}
+
+		// ノードの追加・削除に追従する（全ノードが削除された場合は直前の振り分けのまま）
+		if latest := c.cluster.Router(); len(latest.Nodes()) > 0 && latest != router {
+			router = latest
+			c.declareNamespaces(router)
+		}
+
+		// リクエスト上限チェック
+		if c.config.RequestsLimit > 0 && c.metrics.TotalRequests() >= c.config.RequestsLimit {
+			return
+		}
+
+		// ジョブをまとめて生成し、1度に送信する
+		// リクエストはキーを担当するノードに送るため、停止したノードのキーだけが失敗する
+		batch = batch[:0]
+		for range count {
+			if c.config.ScanRatio > 0 && rand.Float64() < c.config.ScanRatio {
+				prefix := c.randomKey(c.randomNamespace())
+				n, _ := router.Owner(prefix)
+				batch = append(batch, c.createScanJob(n, prefix))
+				continue
+			}
+			isWrite := rand.Float64() < c.config.WriteRatio
+			if c.config.BatchSize > 1 {
+				n, keys := c.randomKeys(router, c.config.BatchSize)
+				batch = append(batch, c.createBatchJob(n, keys, isWrite))
+				continue
+			}
+			key := c.randomKey(c.randomNamespace())
+			batch = append(batch, c.createJob(router.Replicas(key, c.cluster.ReplicationFactor()), key, isWrite))
+		}
+		if c.pool.SubmitBatch(batch) < len(batch) {
+			return
+		}
+	}
+}
+
+// declareNamespaces は設定された名前空間を振り分け先の全ノードに登録し、ノードの NamespaceStats に数えさせる
+// 登録済みのノードでは何もしない
+func (c *Client) declareNamespaces(router *cluster.Router) {
+	for _, n := range router.Nodes() {
+		for _, ns := range c.config.Namespaces {
+			if _, err := n.Namespace(ns); err != nil {
+				log.Warn("", "Client skipped namespace on %s: %v", n.ID(), err)
+			}
+		}
+	}
+}
+
+// randomNamespace は設定された名前空間から1つ選ぶ（設定がない場合は空）
+func (c *Client) randomNamespace() string {
+	if len(c.config.Namespaces) == 0 {
+		return ""
+	}
+	return c.config.Namespaces[rand.Intn(len(c.config.Namespaces))]
+}
+
+// randomKey はキーの範囲から選んだキーに名前空間 ns を付ける
+func (c *Client) randomKey(ns string) string {
+	return node.NamespaceKey(ns, fmt.Sprintf("key-%d", rand.Intn(c.config.KeyRange)))
+}
+
+// createJob はキーを保持するノード replicas へのリクエストジョブを作成する（先頭が担当ノード）
+// 書き込みは全てのノードに送り、応答を待つノードの数はクラスタの Consistency に従う
+// 担当ノードだけに直接送るリクエストは、注入された遅延をワーカーを使わずに待つ（sendAsync）
+func (c *Client) createJob(replicas []*node.Node, key string, isWrite bool) worker.Job {
+	n := replicas[0]
+	return func(ctx context.Context) {
+		var call time.Duration
+		recording := false
+		if c.recorder != nil {
+			call, recording = c.recorder.Begin(isWrite)
+		}
+
+		var span *tracing.Span
+		if c.traceRoot != nil {
+			name := "GET"
+			if isWrite {
+				name = "SET"
+			}
+			if span = c.traceRoot.Sample(name); span != nil {
+				span.SetKind(tracing.KindClient)
+				span.SetAttributes(tracing.String("node.id", n.ID()), tracing.String("kv.key", key))
+			}
+		}
+
+		start := time.Now()
+		var value []byte
+		if isWrite {
+			value = make([]byte, c.config.ValueSize)
+			if _, randErr := cryptorand.Read(value); randErr != nil {
+				log.Warn("", "Failed to generate random value: %v", randErr)
+			}
+		}
+
+		// finish は読み書きの結果を記録する（n は記録とホットキーに使う、値を返したノード）
+		finish := func(n *node.Node, read []byte, found bool, err error) {
+			latency := time.Since(start)
+			if !isWrite {
+				value = read
+			}
+			if span != nil {
+				if !isWrite {
+					span.SetAttributes(tracing.Bool("kv.found", found))
+				}
+				span.SetError(err)
+				span.End()
+			}
+			if recording {
+				op := lincheck.Operation{Node: n.ID(), Key: key, Kind: lincheck.KindGet, Found: found, Failed: err != nil, Call: call}
+				if isWrite {
+					op.Kind = lincheck.KindSet
+				}
+				if isWrite || found {
+					op.Value = lincheck.Hash(value)
+				}
+				c.recorder.End(op)
+			}
+			if errors.Is(err, context.Canceled) || err != nil && c.ctx.Err() != nil {
+				return // 停止・シナリオの終了による中断は記録しない
+			}
+			if err != nil {
+				c.recordFailure(err, latency)
+			} else {
+				c.metrics.RecordSuccess(latency)
+			}
+			if c.keyStats != nil && c.keyStats.sample() {
+				c.keyStats.Record(key, n.ID(), err != nil)
+			}
+		}
+
+		if c.inflight != nil && c.transport == nil && len(replicas) == 1 && c.cluster.CoordinatorLatency(n) <= 0 {
+			c.sendAsync(ctx, n, key, value, isWrite, finish)
+			return
+		}
+
+		// コーディネーターからノードへの転送の遅延はリクエストのレイテンシに含める
+		if isWrite {
+			err := c.cluster.WriteReplicas(ctx, replicas, func(ctx context.Context, r *node.Node) error {
+				return c.kv(r).SetContext(ctx, key, value)
+			})
+			finish(n, nil, false, err)
+			return
+		}
+		// Get: 値は履歴の記録にのみ使用する。記録とホットキーには値を返したノードを使う
+		read, err := c.cluster.ReadReplicas(ctx, replicas, func(ctx context.Context, r *node.Node) ([]byte, bool, error) {
+			return c.kv(r).GetContext(ctx, key)
+		})
+		finish(read.Node, read.Value, read.Found, err)
+	}
+}
+
+// sendAsync はノード n に直接読み書きを送り、注入された遅延をワーカーを使わずにノードのタイマーで待ってから finish を呼び出す
+// 同時に待つリクエストが MaxInFlight に達している場合は、ジョブの ctx が終わるまで空きを待つ
+// リクエストはジョブの終了ではなく Stop と RequestTimeout で打ち切る
+func (c *Client) sendAsync(ctx context.Context, n *node.Node, key string, value []byte, isWrite bool, finish func(n *node.Node, read []byte, found bool, err error)) {
+	select {
+	case c.inflight <- struct{}{}:
+	case <-ctx.Done():
+		finish(n, nil, false, ctx.Err())
+		return
+	}
+
+	reqCtx, cancel := c.ctx, context.CancelFunc(func() {})
+	if c.config.RequestTimeout > 0 {
+		reqCtx, cancel = context.WithTimeout(c.ctx, c.config.RequestTimeout)
+	}
+	c.pending.Add(1)
+	done := func(read []byte, found bool, err error) {
+		cancel()
+		<-c.inflight
+		finish(n, read, found, err)
+		c.pending.Done()
+	}
+
+	if isWrite {
+		n.SetAsync(reqCtx, key, value, func(err error) { done(nil, false, err) })
+		return
+	}
+	// 読み取りを受け付けないノードは ReadReplicas と同じく呼び出さずに失敗とする
+	if status := n.Status(); !status.Available() {
+		done(nil, false, fmt.Errorf("node %s is %s", n.ID(), status))
+		return
+	}
+	n.GetAsync(reqCtx, key, done)
+}
+
+// Stop は負荷生成を停止する
+func (c *Client) Stop() {
+	if !c.running.Swap(false) {
+		return // Not running
+	}
+
+	c.cancel()
+	c.wg.Wait()
+	c.pool.Stop()
+	c.pending.Wait()
+
+	log.Info("", "Client stopped")
+}
+
+// SetWorkers は同時実行するワーカー数を変更する（実行中も可、0以下でCPU数）
+// ランププロファイルなどでシナリオの途中から負荷を変えるために使う
+func (c *Client) SetWorkers(n int) {
+	c.pool.Resize(n)
+}
+
+// SetTargetRPS は生成するリクエストの秒間の目標数を変更する（実行中も可、0以下で上限なし）
+func (c *Client) SetTargetRPS(rps float64) {
+	c.targetRPS.Store(math.Float64bits(max(rps, 0)))
+}
+
+// TargetRPS は現在の秒間の目標数を返す（0で上限なし）
+func (c *Client) TargetRPS() float64 {
+	return math.Float64frombits(c.targetRPS.Load())
+}
+
+// Workers は現在のワーカー数を返す
+func (c *Client) Workers() int {
+	return c.pool.NumWorkers()
+}
+
+// PoolMetrics はワーカープールのメトリクスを返す
+func (c *Client) PoolMetrics() worker.PoolMetrics {
+	return c.pool.Metrics()
+}
+
+// Metrics はメトリクスを返す
+func (c *Client) Metrics() *metrics.Metrics {
+	return c.metrics
+}
+
+// ScanMetrics はスキャンのリクエストだけのメトリクスを返す（ScanRatio が0の場合は nil）
+func (c *Client) ScanMetrics() *metrics.Metrics {
+	return c.scanMetrics
+}
+
+// CapacityFailures はノードのメモリ使用量の上限（node.ErrCapacity）で失敗したリクエストの数を返す
+// 失敗は Metrics にも含まれる
+func (c *Client) CapacityFailures() uint64 {
+	return c.capacityFailures.Load()
+}
+
+// recordFailure は失敗したリクエストを記録し、容量による失敗を区別して数える
+func (c *Client) recordFailure(err error, latency time.Duration) {
+	c.metrics.RecordFailure(latency)
+	if errors.Is(err, node.ErrCapacity) {
+		c.capacityFailures.Add(1)
+	}
+}
+
+// KeyStats はキーごとのアクセス数の集計を返す（KeySampleRate が0の場合は nil）
+func (c *Client) KeyStats() *KeyStats {
+	return c.keyStats
+}
+
+// IsRunning は実行中かどうかを返す
+func (c *Client) IsRunning() bool {
+	return c.running.Load()
+}
+
+// RunFor は指定時間だけ負荷生成を実行する
+func (c *Client) RunFor(ctx context.Context, duration time.Duration) *metrics.Snapshot {
+	c.Start(ctx)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+
+	c.Stop()
+
+	snapshot := c.metrics.Snapshot()
+	return &snapshot
+}
+
+// RunRequests は指定数のリクエストを実行する
+func (c *Client) RunRequests(ctx context.Context, count uint64) *metrics.Snapshot {
+	c.config.RequestsLimit = count
+	c.Start(ctx)
+	c.wg.Wait()
+	c.Stop()
+
+	snapshot := c.metrics.Snapshot()
+	return &snapshot
+}
+
+// maxPaceWait は目標の秒間リクエスト数で次の生成まで待つ上限（目標の変更を反映するため）
+const maxPaceWait = 100 * time.Millisecond
+
+// pacer は目標の秒間リクエスト数に合わせて、経過時間から生成する数を決める
+type pacer struct {
+	last   time.Time
+	credit float64 // 生成できるリクエスト数（端数を持ち越す）
+}
+
+// due は now までに生成すべき数（max まで）を返す。0の場合は次の1件までの待ち時間も返す
+// 遅れを一度に取り戻さないよう、持ち越す数は max までにする
+func (p *pacer) due(now time.Time, rps float64, limit int) (int, time.Duration) {
+	if p.last.IsZero() {
+		p.last = now
+		p.credit = 1
+	}
+	p.credit = min(p.credit+now.Sub(p.last).Seconds()*rps, float64(limit))
+	p.last = now
+
+	n := int(p.credit)
+	p.credit -= float64(n)
+	if n > 0 {
+		return n, 0
+	}
+	wait := time.Duration((1 - p.credit) / rps * float64(time.Second))
+	return 0, min(wait, maxPaceWait)
+}
+
+// reset は目標がなくなったときに持ち越しを破棄する
+func (p *pacer) reset() {
+	*p = pacer{}
+}
@@ -0,0 +1,534 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/lincheck"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+)
+
+func TestDefaultClientConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.WriteRatio != 0.5 {
+		t.Errorf("expected WriteRatio 0.5, got %f", config.WriteRatio)
+	}
+	if config.KeyRange != 10000 {
+		t.Errorf("expected KeyRange 10000, got %d", config.KeyRange)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	c := cluster.New()
+	config := DefaultConfig()
+	client := New(c, config)
+
+	if client.IsRunning() {
+		t.Error("expected client to not be running initially")
+	}
+}
+
+func TestClientStartStop(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	client := New(c, config)
+
+	client.Start(ctx)
+	if !client.IsRunning() {
+		t.Error("expected client to be running after Start")
+	}
+
+	// Give it time to run some requests
+	time.Sleep(50 * time.Millisecond)
+
+	client.Stop()
+	if client.IsRunning() {
+		t.Error("expected client to not be running after Stop")
+	}
+
+	// Should have some metrics
+	if client.Metrics().TotalRequests() == 0 {
+		t.Error("expected some requests to be recorded")
+	}
+}
+
+func TestClientRunFor(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	client := New(c, config)
+
+	snapshot := client.RunFor(ctx, 100*time.Millisecond)
+
+	if snapshot.TotalRequests == 0 {
+		t.Error("expected some requests")
+	}
+	if snapshot.Elapsed < 100*time.Millisecond {
+		t.Errorf("expected at least 100ms elapsed, got %v", snapshot.Elapsed)
+	}
+}
+
+func TestClientRunRequests(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.RequestsLimit = 100
+	client := New(c, config)
+
+	snapshot := client.RunRequests(ctx, 100)
+
+	// Should have at least 100 requests (may have more due to race)
+	if snapshot.TotalRequests < 100 {
+		t.Errorf("expected at least 100 requests, got %d", snapshot.TotalRequests)
+	}
+}
+
+func TestClientWithNoNodes(t *testing.T) {
+	c := cluster.New()
+	config := DefaultConfig()
+	client := New(c, config)
+
+	ctx := context.Background()
+	client.Start(ctx)
+
+	time.Sleep(50 * time.Millisecond)
+	client.Stop()
+
+	// Should have 0 requests since there are no nodes
+	if client.Metrics().TotalRequests() != 0 {
+		t.Errorf("expected 0 requests with no nodes, got %d", client.Metrics().TotalRequests())
+	}
+}
+
+func TestClientFollowsClusterMembership(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	client := New(c, DefaultConfig())
+	client.Start(ctx)
+	defer client.Stop()
+
+	added, err := c.AddNodes(1, "node")
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stats := added[0].Stats(); stats.Gets+stats.Sets > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected requests to reach the added node")
+}
+
+func TestClientRoutesKeysToOwner(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.KeyRange = 300
+	config.WriteRatio = 1
+	New(c, config).RunRequests(ctx, 300)
+
+	// 各ノードには担当するキーだけが書き込まれる
+	for _, n := range c.Nodes() {
+		if len(n.Keys()) == 0 {
+			t.Errorf("expected keys on %s", n.ID())
+		}
+		for _, key := range n.Keys() {
+			if owner, _ := c.Owner(key); owner != n {
+				t.Errorf("key %s written to %s, owned by %s", key, n.ID(), owner.ID())
+			}
+		}
+	}
+}
+
+func TestClientReplicationFactor(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+	c.SetReplicationFactor(2)
+
+	config := DefaultConfig()
+	config.KeyRange = 100
+	config.WriteRatio = 1
+	New(c, config).RunRequests(ctx, 200)
+
+	// 書き込みはキーを保持する全てのノードに届く
+	for _, n := range c.Nodes() {
+		for _, key := range n.Keys() {
+			if !slices.Contains(c.ReplicaSet(key), n) {
+				t.Errorf("key %s written to %s outside its replicas", key, n.ID())
+			}
+		}
+	}
+	for i := range 100 {
+		key := fmt.Sprintf("key-%d", i)
+		replicas := c.ReplicaSet(key)
+		_, first := replicas[0].Get(key)
+		_, second := replicas[1].Get(key)
+		if first != second {
+			t.Errorf("expected %s on both replicas", key)
+		}
+	}
+
+	// 1つのノードが停止しても、読み取りは残りのレプリカで成功する
+	_ = c.Nodes()[0].Stop()
+	config.WriteRatio = 0
+	snapshot := New(c, config).RunRequests(ctx, 200)
+	if snapshot.FailedRequests != 0 {
+		t.Errorf("expected reads to fail over to another replica, got %d failures", snapshot.FailedRequests)
+	}
+}
+
+func TestClientErrorBurst(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	bus := events.NewBus()
+	ch := bus.SubscribeFiltered(events.ByType(events.EventClientErrorBurst))
+
+	config := DefaultConfig()
+	config.NumWorkers = 2
+	config.ErrorBurstWindow = 20 * time.Millisecond
+	client := New(c, config)
+	client.SetEventBus(bus)
+	client.Start(ctx)
+	defer client.Stop()
+
+	// 全ノードを停止するとリクエストが失敗し続ける
+	for _, n := range c.Nodes() {
+		_ = n.Stop()
+	}
+
+	select {
+	case event := <-ch:
+		if event.Data.Errors == 0 || event.Data.Requests < event.Data.Errors || event.Data.Window != "20ms" {
+			t.Errorf("unexpected burst event: %+v", event.Data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for error burst event")
+	}
+
+	// バースト中は区間ごとに発行しない
+	time.Sleep(100 * time.Millisecond)
+	if n := len(ch); n != 0 {
+		t.Errorf("expected a single event per burst, got %d more", n)
+	}
+}
+
+func TestClientAsyncDelay(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+	c.Nodes()[0].SetDelay(20 * time.Millisecond)
+
+	// 1つのワーカーでも、遅延を待つ間に次のリクエストを送る
+	config := DefaultConfig()
+	config.NumWorkers = 1
+	config.MaxInFlight = 32
+	client := New(c, config)
+	snapshot := client.RunFor(ctx, 200*time.Millisecond)
+	if snapshot.TotalRequests <= 20 {
+		t.Errorf("expected more requests than one worker can wait out in sequence, got %d", snapshot.TotalRequests)
+	}
+	if snapshot.FailedRequests != 0 {
+		t.Errorf("expected no failures, got %d", snapshot.FailedRequests)
+	}
+
+	// MaxInFlight が0の場合はワーカーで遅延を待つ
+	config.MaxInFlight = 0
+	client = New(c, config)
+	snapshot = client.RunFor(ctx, 200*time.Millisecond)
+	if snapshot.TotalRequests > 11 {
+		t.Errorf("expected one worker to wait out each delay, got %d requests", snapshot.TotalRequests)
+	}
+}
+
+func TestClientSetWorkers(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.NumWorkers = 2
+	client := New(c, config)
+	client.Start(ctx)
+	defer client.Stop()
+
+	client.SetWorkers(6)
+	if client.Workers() != 6 {
+		t.Errorf("expected 6 workers, got %d", client.Workers())
+	}
+	before := client.Metrics().TotalRequests()
+	time.Sleep(30 * time.Millisecond)
+	if client.Metrics().TotalRequests() <= before {
+		t.Error("expected requests to continue after resizing")
+	}
+}
+
+// failingKV は呼び出し回数を数え、常にエラーを返す KV
+type failingKV struct {
+	calls atomic.Uint64
+}
+
+func (f *failingKV) GetContext(context.Context, string) ([]byte, bool, error) {
+	f.calls.Add(1)
+	return nil, false, errors.New("connection reset")
+}
+
+func (f *failingKV) SetContext(context.Context, string, []byte) error {
+	f.calls.Add(1)
+	return errors.New("connection reset")
+}
+
+func TestClientSetTransport(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	kv := &failingKV{}
+	client := New(c, DefaultConfig())
+	client.SetTransport(func(n *node.Node) KV {
+		if n.ID() == "node-1" {
+			return kv
+		}
+		return n
+	})
+
+	snapshot := client.RunRequests(ctx, 200)
+
+	if kv.calls.Load() == 0 {
+		t.Fatal("expected requests to go through the transport")
+	}
+	if snapshot.FailedRequests == 0 || snapshot.FailedRequests >= snapshot.TotalRequests {
+		t.Errorf("expected only requests to node-1 to fail, got %d/%d", snapshot.FailedRequests, snapshot.TotalRequests)
+	}
+}
+
+func TestClientSetRecorder(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.KeyRange = 10
+	client := New(c, config)
+	recorder := lincheck.NewRecorder(lincheck.DefaultConfig())
+	client.SetRecorder(recorder)
+
+	snapshot := client.RunRequests(ctx, 500)
+
+	ops := recorder.Operations()
+	if uint64(len(ops)) < snapshot.TotalRequests {
+		t.Fatalf("expected at least %d recorded operations, got %d", snapshot.TotalRequests, len(ops))
+	}
+	var reads, writes int
+	for _, op := range ops {
+		switch op.Kind {
+		case lincheck.KindGet:
+			reads++
+		case lincheck.KindSet:
+			writes++
+		}
+	}
+	if reads == 0 || writes == 0 {
+		t.Errorf("expected both reads and writes, got %d reads and %d writes", reads, writes)
+	}
+
+	result := lincheck.Check(ops, lincheck.DefaultMaxSteps)
+	if !result.Linearizable {
+		t.Errorf("expected a healthy cluster to be linearizable, got %+v", result.Violations)
+	}
+}
+
+func TestClientSetTraceParent(t *testing.T) {
+	// OTLP/HTTPの受信先の代わりに、スパンの名前と親を記録する
+	type span struct {
+		Name         string `json:"name"`
+		ParentSpanID string `json:"parentSpanId"`
+		Kind         int    `json:"kind"`
+	}
+	var mu sync.Mutex
+	var spans []span
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []span `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}))
+	defer ts.Close()
+
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	tracingConfig := tracing.DefaultConfig()
+	tracingConfig.Endpoint = ts.URL
+	tracingConfig.SampleRate = 1
+	tracer := tracing.New(tracingConfig)
+	load := tracer.Start("load")
+
+	client := New(c, DefaultConfig())
+	client.SetTraceParent(load)
+	client.RunRequests(ctx, 100)
+	if err := tracer.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(spans) < 100 {
+		t.Fatalf("expected every request to be traced, got %d spans", len(spans))
+	}
+	for _, s := range spans {
+		if (s.Name != "GET" && s.Name != "SET") || s.ParentSpanID != load.SpanID().String() || s.Kind != int(tracing.KindClient) {
+			t.Fatalf("unexpected request span: %+v", s)
+		}
+	}
+}
+
+func TestPacer(t *testing.T) {
+	var p pacer
+	start := time.Unix(0, 0)
+
+	if n, _ := p.due(start, 100, 32); n != 1 {
+		t.Errorf("expected the first request immediately, got %d", n)
+	}
+	n, wait := p.due(start.Add(5*time.Millisecond), 100, 32)
+	if n != 0 || wait != 5*time.Millisecond {
+		t.Errorf("expected to wait 5ms for the next request, got %d after %v", n, wait)
+	}
+	if n, _ := p.due(start.Add(105*time.Millisecond), 100, 32); n != 10 {
+		t.Errorf("expected 10 requests after 100ms at 100 rps, got %d", n)
+	}
+	if n, _ := p.due(start.Add(10*time.Second), 100, 32); n != 32 {
+		t.Errorf("expected the backlog to be capped at 32, got %d", n)
+	}
+	if _, wait := p.due(start.Add(10*time.Second), 0.1, 32); wait != maxPaceWait {
+		t.Errorf("expected the wait to be capped at %v, got %v", maxPaceWait, wait)
+	}
+}
+
+func TestClientTargetRPS(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.TargetRPS = 200
+	client := New(c, config)
+	if client.TargetRPS() != 200 {
+		t.Errorf("expected target 200, got %f", client.TargetRPS())
+	}
+
+	snapshot := client.RunFor(ctx, 500*time.Millisecond)
+	if snapshot.TotalRequests < 50 || snapshot.TotalRequests > 150 {
+		t.Errorf("expected about 100 requests at 200 rps for 500ms, got %d", snapshot.TotalRequests)
+	}
+
+	client.SetTargetRPS(-1)
+	if client.TargetRPS() != 0 {
+		t.Errorf("expected a negative target to disable pacing, got %f", client.TargetRPS())
+	}
+}
+
+func TestClientNamespaces(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.KeyRange = 100
+	config.Namespaces = []string{"tenant-a", "tenant-b"}
+	client := New(c, config)
+	client.RunRequests(ctx, 500)
+
+	// すべてのリクエストが設定した名前空間のどれかに届く
+	var nsOps, ops uint64
+	seen := make(map[string]bool)
+	for _, n := range c.Nodes() {
+		for ns, stats := range n.NamespaceStats() {
+			if ns != "tenant-a" && ns != "tenant-b" {
+				t.Errorf("unexpected namespace %q", ns)
+			}
+			seen[ns] = true
+			nsOps += stats.Gets + stats.Sets
+		}
+		stats := n.Stats()
+		ops += stats.Gets + stats.Sets
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected load spread across both namespaces, got %v", seen)
+	}
+	if nsOps != ops {
+		t.Errorf("expected every request to carry a namespace, got %d of %d operations", nsOps, ops)
+	}
+}
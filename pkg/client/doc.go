@@ -0,0 +1,70 @@
+// Package client provides a load generator for stress testing the cluster.
+//
+// The Client generates read/write traffic against a cluster at a configurable
+// rate and ratio. It collects metrics about the generated load.
+//
+// # Basic Usage
+//
+//	c := cluster.New()
+//	c.CreateNodes(5, "node")
+//	c.StartAll(ctx)
+//
+//	config := client.DefaultConfig()
+//	config.WriteRatio = 0.3 // 30% writes, 70% reads
+//	cl := client.New(c, config)
+//
+//	// Run for a duration
+//	snap := cl.RunFor(ctx, 10*time.Second)
+//	fmt.Printf("Total: %d, RPS: %.2f\n", snap.TotalRequests, snap.RPS)
+//
+//	// Or run a fixed number of requests
+//	snap := cl.RunRequests(ctx, 10000)
+//
+// # Configuration
+//
+// The Config struct allows tuning:
+//   - NumWorkers: parallel workers (0 = CPU count)
+//   - WriteRatio: fraction of write operations (0.0 to 1.0)
+//   - KeyRange: key space size
+//   - ValueSize: size of values in bytes
+//   - RequestsLimit: max requests (0 = unlimited)
+//   - KeySampleRate: fraction of requests whose key is counted (0 = off)
+//   - TargetRPS: requests per second across all workers (0 = unlimited);
+//     SetTargetRPS changes it while the client is running
+//   - BatchSize: keys read or written per request with MGet/MSet
+//     (0 or 1 = one key per request); a batch is measured as one request
+//   - ScanRatio, ScanLimit: share of requests sent as prefix scans and the
+//     keys each scan returns; scans are also measured in ScanMetrics
+//   - Namespaces: namespaces each request picks one of at random; keys are
+//     sent as node.NamespaceKey(ns, key), and a batch stays in one namespace
+//   - MaxInFlight: requests that may wait out a node's injected delay at
+//     once without holding a worker (0 = workers wait); it applies to
+//     requests sent straight to a single node, which use node.GetAsync and
+//     node.SetAsync, while replicated, forwarded and transported requests
+//     still wait in their worker
+//
+// # Routing
+//
+// Each request goes to the node that owns its key in the cluster's
+// consistent-hash Router, so a killed node makes exactly its keys
+// unavailable while the rest of the key space keeps working. A batch is
+// built from keys owned by one node, and a scan goes to the owner of its
+// prefix. With a cluster replication factor above one, a write goes to every
+// node of the key's replica set and a read to any of them, waiting for as
+// many replicas as the cluster's Consistency asks for (see
+// cluster.WriteReplicas and cluster.ReadReplicas).
+//
+// # Hot Keys
+//
+// With KeySampleRate set, the client counts sampled accesses per key and
+// node. KeyStats().Report(n) returns the n most accessed keys, the node that
+// served most of each key's requests, and the Gini coefficient of the
+// per-key counts as a skew measure:
+//
+//	config.KeySampleRate = 0.1
+//	cl := client.New(c, config)
+//	cl.RunFor(ctx, 10*time.Second)
+//	for _, k := range cl.KeyStats().Report(5).TopKeys {
+//	    fmt.Println(k.Key, k.Accesses, k.TopNode)
+//	}
+package client
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// DefaultHotKeys はホットキーレポートに含めるキー数のデフォルト
+const DefaultHotKeys = 10
+
+// KeyStats はサンプリングしたリクエストのキーごと・ノードごとのアクセス数を集計する
+type KeyStats struct {
+	rate float64 // 記録するリクエストの割合
+
+	mu      sync.Mutex
+	keys    map[string]*keyCounter
+	sampled uint64
+}
+
+// keyCounter は1キーのアクセス数
+type keyCounter struct {
+	accesses uint64
+	failures uint64
+	nodes    map[string]uint64 // ノードIDごとのアクセス数
+}
+
+// HotKeyReport はアクセス数の多いキーとキー空間の偏りの集計
+type HotKeyReport struct {
+	SampleRate   float64  `json:"sample_rate"`   // 記録したリクエストの割合
+	Sampled      uint64   `json:"sampled"`       // 記録したリクエスト数
+	DistinctKeys int      `json:"distinct_keys"` // 記録したリクエストのキーの種類
+	Skew         float64  `json:"skew"`          // キーごとのアクセス数のジニ係数（0で均等、1に近いほど一部のキーに集中）
+	TopKeys      []HotKey `json:"top_keys"`      // アクセス数の多い順
+}
+
+// HotKey は1キーのアクセスの集計
+type HotKey struct {
+	Key      string  `json:"key"`
+	Accesses uint64  `json:"accesses"` // 記録したアクセス数
+	Share    float64 `json:"share"`    // 記録した全アクセスに占める割合
+	Failures uint64  `json:"failures"` // 失敗したアクセス数
+	TopNode  string  `json:"top_node"` // 最も多くアクセスを受けたノード
+}
+
+// NewKeyStats は rate の割合（0〜1）のリクエストを記録する集計を作成する
+func NewKeyStats(rate float64) *KeyStats {
+	return &KeyStats{
+		rate: rate,
+		keys: make(map[string]*keyCounter),
+	}
+}
+
+// sample は今回のリクエストを記録するかを無作為に決める
+func (s *KeyStats) sample() bool {
+	return s.rate >= 1 || rand.Float64() < s.rate
+}
+
+// Record はノード nodeID への key のアクセスを記録する
+func (s *KeyStats) Record(key, nodeID string, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.keys[key]
+	if !ok {
+		c = &keyCounter{nodes: make(map[string]uint64)}
+		s.keys[key] = c
+	}
+	c.accesses++
+	if failed {
+		c.failures++
+	}
+	c.nodes[nodeID]++
+	s.sampled++
+}
+
+// Report はアクセス数の多い順に topN 件（0以下で DefaultHotKeys）のキーを含む集計を返す
+func (s *KeyStats) Report(topN int) *HotKeyReport {
+	if topN <= 0 {
+		topN = DefaultHotKeys
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &HotKeyReport{
+		SampleRate:   s.rate,
+		Sampled:      s.sampled,
+		DistinctKeys: len(s.keys),
+		TopKeys:      []HotKey{},
+	}
+	if s.sampled == 0 {
+		return report
+	}
+
+	keys := make([]string, 0, len(s.keys))
+	for key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := s.keys[keys[i]], s.keys[keys[j]]
+		if a.accesses != b.accesses {
+			return a.accesses > b.accesses
+		}
+		return keys[i] < keys[j]
+	})
+
+	report.Skew = gini(keys, s.keys)
+	for _, key := range keys[:min(topN, len(keys))] {
+		c := s.keys[key]
+		report.TopKeys = append(report.TopKeys, HotKey{
+			Key:      key,
+			Accesses: c.accesses,
+			Share:    float64(c.accesses) / float64(s.sampled),
+			Failures: c.failures,
+			TopNode:  c.topNode(),
+		})
+	}
+	return report
+}
+
+// topNode は最も多くアクセスを受けたノードを返す（同数の場合はIDの小さい方）
+func (c *keyCounter) topNode() string {
+	var top string
+	for id, n := range c.nodes {
+		if top == "" || n > c.nodes[top] || n == c.nodes[top] && id < top {
+			top = id
+		}
+	}
+	return top
+}
+
+// gini はアクセス数の多い順に並んだキーのアクセス数のジニ係数を返す
+func gini(sorted []string, counts map[string]*keyCounter) float64 {
+	n := len(sorted)
+	if n < 2 {
+		return 0
+	}
+	// 少ない順の i 番目（1始まり）の重みを i として合計する
+	var total, weighted float64
+	for i, key := range sorted {
+		x := float64(counts[key].accesses)
+		total += x
+		weighted += float64(n-i) * x
+	}
+	return 2*weighted/(float64(n)*total) - float64(n+1)/float64(n)
+}
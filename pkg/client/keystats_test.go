@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+)
+
+func TestKeyStatsReport(t *testing.T) {
+	s := NewKeyStats(1)
+	for range 6 {
+		s.Record("key-1", "node-2", false)
+	}
+	s.Record("key-1", "node-1", true)
+	s.Record("key-2", "node-1", false)
+	s.Record("key-2", "node-3", false)
+	s.Record("key-3", "node-1", false)
+
+	report := s.Report(2)
+	if report.Sampled != 10 || report.DistinctKeys != 3 || report.SampleRate != 1 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if len(report.TopKeys) != 2 {
+		t.Fatalf("expected 2 top keys, got %+v", report.TopKeys)
+	}
+	hot := report.TopKeys[0]
+	if hot.Key != "key-1" || hot.Accesses != 7 || hot.Failures != 1 || hot.TopNode != "node-2" || hot.Share != 0.7 {
+		t.Errorf("unexpected hottest key: %+v", hot)
+	}
+	if second := report.TopKeys[1]; second.Key != "key-2" || second.TopNode != "node-1" {
+		t.Errorf("expected ties to pick the smaller node ID, got %+v", second)
+	}
+	// アクセス数 1, 2, 7 のジニ係数
+	if want := 2*(1*1+2*2+3*7.0)/(3*10) - 4.0/3; math.Abs(report.Skew-want) > 1e-9 {
+		t.Errorf("expected skew %f, got %f", want, report.Skew)
+	}
+}
+
+func TestKeyStatsSkew(t *testing.T) {
+	uniform := NewKeyStats(1)
+	for i := range 100 {
+		uniform.Record(string(rune('a'+i%10)), "node-1", false)
+	}
+	if skew := uniform.Report(0).Skew; math.Abs(skew) > 1e-9 {
+		t.Errorf("expected no skew for uniform access, got %f", skew)
+	}
+	if got := len(uniform.Report(0).TopKeys); got != DefaultHotKeys {
+		t.Errorf("expected %d top keys by default, got %d", DefaultHotKeys, got)
+	}
+
+	empty := NewKeyStats(0.5).Report(5)
+	if empty.Sampled != 0 || empty.Skew != 0 || len(empty.TopKeys) != 0 {
+		t.Errorf("expected an empty report, got %+v", empty)
+	}
+}
+
+func TestClientKeyStats(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	if New(c, DefaultConfig()).KeyStats() != nil {
+		t.Error("expected key stats to be disabled by default")
+	}
+
+	config := DefaultConfig()
+	config.KeyRange = 5
+	config.KeySampleRate = 1
+	cl := New(c, config)
+	snapshot := cl.RunRequests(ctx, 200)
+
+	report := cl.KeyStats().Report(3)
+	if report.Sampled == 0 || report.Sampled > snapshot.TotalRequests {
+		t.Errorf("expected up to %d sampled requests, got %d", snapshot.TotalRequests, report.Sampled)
+	}
+	if report.DistinctKeys > 5 || len(report.TopKeys) != 3 {
+		t.Errorf("expected keys from the key range, got %+v", report)
+	}
+	for _, k := range report.TopKeys {
+		if k.TopNode != "node-1" && k.TopNode != "node-2" {
+			t.Errorf("unexpected node for %s: %s", k.Key, k.TopNode)
+		}
+	}
+}
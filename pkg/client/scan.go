@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+	"github.com/nyasuto/chaos-kvs/pkg/worker"
+)
+
+// DefaultScanLimit は Config.ScanLimit が0の場合に1回のスキャンで返すキーの上限
+const DefaultScanLimit = 100
+
+// ScanKV はキーの前方一致で範囲を読み取れるリクエストの送り先
+// *node.Node はそのまま ScanKV として使える
+type ScanKV interface {
+	ScanContext(ctx context.Context, prefix string, limit int) ([]node.Entry, error)
+}
+
+// scan は kv で prefix で始まるキーを最大 limit 件読み取り、読み取ったキーの数を返す
+// ScanKV でない場合は prefix のキーを1つ取得する
+func scan(ctx context.Context, kv KV, prefix string, limit int) (int, error) {
+	if s, ok := kv.(ScanKV); ok {
+		entries, err := s.ScanContext(ctx, prefix, limit)
+		return len(entries), err
+	}
+	_, found, err := kv.GetContext(ctx, prefix)
+	if found {
+		return 1, err
+	}
+	return 0, err
+}
+
+// scanLimit は1回のスキャンで返すキーの上限を返す
+func (c *Client) scanLimit() int {
+	if c.config.ScanLimit > 0 {
+		return c.config.ScanLimit
+	}
+	return DefaultScanLimit
+}
+
+// createScanJob はノード n で prefix で始まるキーを読み取るリクエストジョブを作成する
+// 全体のメトリクスに加えて ScanMetrics にも記録する
+func (c *Client) createScanJob(n *node.Node, prefix string) worker.Job {
+	kv := c.kv(n)
+	return func(ctx context.Context) {
+		var span *tracing.Span
+		if c.traceRoot != nil {
+			if span = c.traceRoot.Sample("SCAN"); span != nil {
+				span.SetKind(tracing.KindClient)
+				span.SetAttributes(tracing.String("node.id", n.ID()), tracing.String("kv.prefix", prefix))
+			}
+		}
+
+		start := time.Now()
+		var keys int
+		err := c.cluster.Forward(ctx, n)
+		if err == nil {
+			keys, err = scan(ctx, kv, prefix, c.scanLimit())
+		}
+
+		latency := time.Since(start)
+		if span != nil {
+			span.SetAttributes(tracing.Int("kv.keys", int64(keys)))
+			span.SetError(err)
+			span.End()
+		}
+		if errors.Is(err, context.Canceled) || err != nil && c.ctx.Err() != nil {
+			return // 停止・シナリオの終了による中断は記録しない
+		}
+		if err != nil {
+			c.metrics.RecordFailure(latency)
+			c.scanMetrics.RecordFailure(latency)
+		} else {
+			c.metrics.RecordSuccess(latency)
+			c.scanMetrics.RecordSuccess(latency)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClientScanRatio(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	config := DefaultConfig()
+	config.ScanRatio = 1
+	config.ScanLimit = 10
+	config.KeyRange = 100
+	client := New(c, config)
+	snapshot := client.RunRequests(ctx, 50)
+
+	var scans uint64
+	for _, n := range c.Nodes() {
+		stats := n.Stats()
+		scans += stats.Scans
+		if stats.Gets+stats.Sets != 0 {
+			t.Errorf("expected only scans on %s, got %+v", n.ID(), stats)
+		}
+	}
+	if scans < snapshot.TotalRequests || snapshot.FailedRequests != 0 {
+		t.Errorf("expected every request to be a scan, got %d scans for %d requests (%d failed)", scans, snapshot.TotalRequests, snapshot.FailedRequests)
+	}
+	if m := client.ScanMetrics(); m == nil || m.TotalRequests() != snapshot.TotalRequests {
+		t.Errorf("expected the scans in the scan metrics, got %v", m)
+	}
+}
+
+func TestClientScanFallback(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	// ScanKV でない送り先には Get を送る
+	kv := &failingKV{}
+	config := DefaultConfig()
+	config.ScanRatio = 1
+	client := New(c, config)
+	client.SetTransport(func(*node.Node) KV { return kv })
+
+	snapshot := client.RunRequests(ctx, 20)
+	if kv.calls.Load() == 0 || snapshot.FailedRequests == 0 {
+		t.Errorf("expected the scans to go through the transport, got %d calls", kv.calls.Load())
+	}
+	if New(c, DefaultConfig()).ScanMetrics() != nil {
+		t.Error("expected no scan metrics without scans")
+	}
+}
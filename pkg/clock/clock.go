@@ -0,0 +1,111 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock は現在時刻の取得とタイマーを抽象化する
+// ノードの遅延、カオスの攻撃間隔、ヘルスチェック、シナリオの実行時間はこのインターフェースを通して時間を扱う
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	// AfterFunc は d 経過後に f を別のゴルーチンで実行する
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer は time.Timer に相当する
+type Timer interface {
+	Chan() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker は time.Ticker に相当する
+type Ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real は実時間の Clock を返す
+func Real() Clock {
+	return realClock{}
+}
+
+// realClock は time パッケージをそのまま使う Clock
+type realClock struct{}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) Chan() <-chan time.Time     { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (t realTicker) Chan() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()                  { t.t.Stop() }
+func (t realTicker) Reset(d time.Duration)  { t.t.Reset(d) }
+
+// Sleep は c の時間で d だけ待つ
+// 待機中に ctx がキャンセルされた場合はそのエラーを返す
+func Sleep(ctx context.Context, c Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.Chan():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WithTimeout は c の時間で d 経過後にキャンセルされるコンテキストを返す
+// 期限に達した場合の Err は context.DeadlineExceeded になる
+func WithTimeout(parent context.Context, c Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := c.(realClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	timer := c.AfterFunc(d, func() { cancel(context.DeadlineExceeded) })
+	return timeoutContext{ctx}, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+// timeoutContext は期限切れによるキャンセルを Err で DeadlineExceeded として返す
+// Deadline は実時間と比較されうるため親のものをそのまま返す
+type timeoutContext struct {
+	context.Context
+}
+
+func (c timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
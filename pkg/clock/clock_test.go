@@ -0,0 +1,51 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRealClock(t *testing.T) {
+	clk := Real()
+
+	start := clk.Now()
+	timer := clk.NewTimer(time.Millisecond)
+	<-timer.Chan()
+	if clk.Since(start) < time.Millisecond {
+		t.Error("expected at least 1ms to pass")
+	}
+
+	ticker := clk.NewTicker(time.Millisecond)
+	<-ticker.Chan()
+	ticker.Stop()
+
+	fired := make(chan struct{})
+	clk.AfterFunc(time.Millisecond, func() { close(fired) })
+	<-fired
+}
+
+func TestSleep(t *testing.T) {
+	if err := Sleep(context.Background(), Real(), time.Millisecond); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, Real(), time.Hour); err != context.Canceled {
+		t.Errorf("expected canceled, got %v", err)
+	}
+	if err := Sleep(ctx, Real(), 0); err != context.Canceled {
+		t.Errorf("expected canceled for a zero sleep on a canceled context, got %v", err)
+	}
+}
+
+func TestWithTimeoutReal(t *testing.T) {
+	ctx, cancel := WithTimeout(context.Background(), Real(), time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", ctx.Err())
+	}
+}
@@ -0,0 +1,29 @@
+// Package clock abstracts time so that delays, tickers and timeouts can run
+// on a virtual clock.
+//
+// Components that wait on time (node delay injection, the chaos monkey's
+// attack interval, the recovery manager's health checks and the scenario's
+// duration) take a Clock. Real returns the wall clock and is used when none
+// is configured.
+//
+// # Simulated Time
+//
+// A Simulated clock only moves when Advance is called. Timers and tickers
+// fire in deadline order as the virtual time passes them, so a test can
+// cover minutes of chaos in microseconds without time.Sleep:
+//
+//	clk := clock.NewSimulated(time.Now())
+//	cfg := recovery.DefaultConfig()
+//	cfg.Clock = clk
+//	m := recovery.New(c, cfg)
+//	m.Start(ctx)
+//
+//	// Wait for the health check ticker to be registered, then run it
+//	_ = clk.BlockUntil(ctx, 1)
+//	clk.Advance(cfg.HealthCheckInterval)
+//
+// Timers fire by sending on their channel (or by starting a goroutine for
+// AfterFunc), so the goroutine that receives still runs asynchronously.
+// BlockUntil waits until a given number of timers are registered, which is
+// the usual way to know that a goroutine has reached its next wait.
+package clock
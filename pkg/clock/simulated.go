@@ -0,0 +1,228 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Simulated は Advance で明示的に進める仮想時計
+// タイマーとティッカーは仮想時刻が期限に達したときに発火するため、
+// テストは実時間を待たずに遅延・攻撃間隔・ヘルスチェック・実行時間を再現できる
+type Simulated struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*simTimer
+	changed chan struct{} // タイマーの登録・解除のたびに閉じて作り直す
+}
+
+// NewSimulated は start を現在時刻とする仮想時計を作成する
+func NewSimulated(start time.Time) *Simulated {
+	return &Simulated{now: start, changed: make(chan struct{})}
+}
+
+// Now は仮想時刻を返す
+func (s *Simulated) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Since は仮想時刻での t からの経過時間を返す
+func (s *Simulated) Since(t time.Time) time.Duration {
+	return s.Now().Sub(t)
+}
+
+// NewTimer は d 経過後に発火するタイマーを作成する
+func (s *Simulated) NewTimer(d time.Duration) Timer {
+	return s.add(&simTimer{clock: s, c: make(chan time.Time, 1)}, d)
+}
+
+// NewTicker は d ごとに発火するティッカーを作成する
+func (s *Simulated) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return simTicker{s.add(&simTimer{clock: s, c: make(chan time.Time, 1), period: d}, d)}
+}
+
+// AfterFunc は d 経過後に f を別のゴルーチンで実行する
+func (s *Simulated) AfterFunc(d time.Duration, f func()) Timer {
+	return s.add(&simTimer{clock: s, fn: f}, d)
+}
+
+// add はタイマーを登録する
+func (s *Simulated) add(t *simTimer, d time.Duration) *simTimer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule(t, d)
+	return t
+}
+
+// schedule は現在の仮想時刻から d 後にタイマーを登録する（期限が過ぎていれば即座に発火する）
+// 呼び出し側で mu を保持すること
+func (s *Simulated) schedule(t *simTimer, d time.Duration) {
+	t.when = s.now.Add(d)
+	if d <= 0 {
+		t.fire(s.now)
+		if t.period == 0 {
+			return
+		}
+		t.when = s.now.Add(t.period)
+	}
+	s.timers = append(s.timers, t)
+	s.notify()
+}
+
+// remove はタイマーの登録を解除し、登録されていたかを返す
+// 呼び出し側で mu を保持すること
+func (s *Simulated) remove(t *simTimer) bool {
+	for i, other := range s.timers {
+		if other == t {
+			s.timers = append(s.timers[:i], s.timers[i+1:]...)
+			s.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// notify は BlockUntil で待機しているゴルーチンを起こす
+func (s *Simulated) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Advance は仮想時刻を d だけ進め、その間に期限を迎えたタイマーを時刻順に発火する
+// 受信側が追いつかないティッカーは time.Ticker と同様に間の値を捨てるため、
+// 各周期の処理を確実に実行したい場合は1周期ずつ進める
+func (s *Simulated) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target := s.now.Add(d)
+	for {
+		next := s.earliest()
+		if next == nil || next.when.After(target) {
+			break
+		}
+		s.now = next.when
+		next.fire(s.now)
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			s.remove(next)
+		}
+	}
+	s.now = target
+}
+
+// AdvanceToNext は次のタイマーの期限まで仮想時刻を進め、進めた時間を返す
+// 登録されたタイマーがない場合は何もせず0を返す
+func (s *Simulated) AdvanceToNext() time.Duration {
+	s.mu.Lock()
+	next := s.earliest()
+	if next == nil {
+		s.mu.Unlock()
+		return 0
+	}
+	d := next.when.Sub(s.now)
+	s.mu.Unlock()
+
+	s.Advance(d)
+	return d
+}
+
+// earliest は期限が最も早いタイマーを返す
+// 呼び出し側で mu を保持すること
+func (s *Simulated) earliest() *simTimer {
+	var next *simTimer
+	for _, t := range s.timers {
+		if next == nil || t.when.Before(next.when) {
+			next = t
+		}
+	}
+	return next
+}
+
+// Waiters は登録されているタイマーとティッカーの数を返す
+func (s *Simulated) Waiters() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+// BlockUntil は登録されたタイマーとティッカーが n 個以上になるまで待つ
+// 別のゴルーチンが待機を始めてから Advance するために使う
+func (s *Simulated) BlockUntil(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		count, changed := len(s.timers), s.changed
+		s.mu.Unlock()
+		if count >= n {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// simTimer は Simulated のタイマー・ティッカー・AfterFunc
+type simTimer struct {
+	clock  *Simulated
+	when   time.Time
+	period time.Duration // ティッカーの間隔（0でタイマー）
+	c      chan time.Time
+	fn     func()
+}
+
+// fire はタイマーを発火する
+// time.Ticker と同様に、受信されていない値がある場合は新しい値を捨てる
+func (t *simTimer) fire(now time.Time) {
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+func (t *simTimer) Chan() <-chan time.Time {
+	return t.c
+}
+
+// Stop はタイマーを止め、発火前に止めたかを返す
+func (t *simTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+// Reset はタイマーを現在の仮想時刻から d 後に再設定する
+// ティッカーの場合は間隔も d に変更する
+func (t *simTimer) Reset(d time.Duration) bool {
+	s := t.clock
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := s.remove(t)
+	if t.period > 0 {
+		if d <= 0 {
+			panic("clock: non-positive interval for Ticker.Reset")
+		}
+		t.period = d
+	}
+	s.schedule(t, d)
+	return active
+}
+
+// simTicker は Ticker のメソッドの形に合わせて simTimer を包む
+type simTicker struct{ *simTimer }
+
+func (t simTicker) Reset(d time.Duration) { t.simTimer.Reset(d) }
+func (t simTicker) Stop()                 { t.simTimer.Stop() }
@@ -0,0 +1,182 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// received はチャネルに値が届いているかを返す
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case v := <-c:
+		return v, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestSimulatedNow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := NewSimulated(start)
+
+	if !clk.Now().Equal(start) {
+		t.Errorf("expected %v, got %v", start, clk.Now())
+	}
+	clk.Advance(90 * time.Second)
+	if got := clk.Since(start); got != 90*time.Second {
+		t.Errorf("expected 90s to have passed, got %v", got)
+	}
+}
+
+func TestSimulatedTimer(t *testing.T) {
+	start := time.Unix(0, 0)
+	clk := NewSimulated(start)
+	timer := clk.NewTimer(time.Second)
+
+	clk.Advance(999 * time.Millisecond)
+	if _, ok := received(timer.Chan()); ok {
+		t.Fatal("expected the timer not to fire before its deadline")
+	}
+
+	clk.Advance(time.Millisecond)
+	v, ok := received(timer.Chan())
+	if !ok || !v.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected the timer to fire at the deadline, got %v (%v)", v, ok)
+	}
+	if clk.Waiters() != 0 {
+		t.Errorf("expected a fired timer to be removed, got %d waiters", clk.Waiters())
+	}
+	if timer.Stop() {
+		t.Error("expected Stop to report that the timer already fired")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("expected Reset of a fired timer to report it was inactive")
+	}
+	if !timer.Stop() {
+		t.Error("expected Stop to report that the reset timer was active")
+	}
+	clk.Advance(time.Hour)
+	if _, ok := received(timer.Chan()); ok {
+		t.Error("expected a stopped timer not to fire")
+	}
+}
+
+func TestSimulatedTicker(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+	ticker := clk.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := range 3 {
+		clk.Advance(time.Second)
+		if _, ok := received(ticker.Chan()); !ok {
+			t.Fatalf("expected tick %d", i+1)
+		}
+	}
+
+	// 受信されない間の値は捨てられる
+	clk.Advance(5 * time.Second)
+	if v, ok := received(ticker.Chan()); !ok || !v.Equal(time.Unix(4, 0)) {
+		t.Errorf("expected only the first pending tick to be kept, got %v (%v)", v, ok)
+	}
+	if _, ok := received(ticker.Chan()); ok {
+		t.Error("expected later ticks to be dropped")
+	}
+
+	ticker.Reset(10 * time.Second)
+	clk.Advance(9 * time.Second)
+	if _, ok := received(ticker.Chan()); ok {
+		t.Error("expected no tick before the new interval")
+	}
+	clk.Advance(time.Second)
+	if _, ok := received(ticker.Chan()); !ok {
+		t.Error("expected a tick at the new interval")
+	}
+
+	ticker.Stop()
+	clk.Advance(time.Minute)
+	if _, ok := received(ticker.Chan()); ok {
+		t.Error("expected a stopped ticker not to tick")
+	}
+}
+
+func TestSimulatedFiresInOrder(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+	order := make(chan string, 3)
+	clk.AfterFunc(3*time.Second, func() { order <- "c" })
+	clk.AfterFunc(time.Second, func() { order <- "a" })
+	long := clk.NewTimer(2 * time.Second)
+
+	if got := clk.AdvanceToNext(); got != time.Second {
+		t.Fatalf("expected to advance to the first deadline, got %v", got)
+	}
+	if v := <-order; v != "a" {
+		t.Errorf("expected the earliest function first, got %s", v)
+	}
+
+	clk.Advance(2 * time.Second)
+	if v, ok := received(long.Chan()); !ok || !v.Equal(time.Unix(2, 0)) {
+		t.Errorf("expected the timer to fire at its own deadline, got %v (%v)", v, ok)
+	}
+	if v := <-order; v != "c" {
+		t.Errorf("expected the last function, got %s", v)
+	}
+	if got := clk.AdvanceToNext(); got != 0 {
+		t.Errorf("expected nothing to advance to, got %v", got)
+	}
+}
+
+func TestSimulatedBlockUntil(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Sleep(context.Background(), clk, time.Minute)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the sleeper to register a timer: %v", err)
+	}
+	clk.Advance(time.Minute)
+	if err := <-done; err != nil {
+		t.Errorf("expected the sleep to finish, got %v", err)
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if err := clk.BlockUntil(short, 1); err == nil {
+		t.Error("expected BlockUntil to give up without timers")
+	}
+}
+
+func TestSimulatedWithTimeout(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+
+	ctx, cancel := WithTimeout(context.Background(), clk, time.Hour)
+	defer cancel()
+
+	clk.Advance(59 * time.Minute)
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected the context to be alive before the timeout")
+	default:
+	}
+
+	clk.Advance(time.Minute)
+	<-ctx.Done()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", ctx.Err())
+	}
+
+	ctx, cancel = WithTimeout(context.Background(), clk, time.Hour)
+	cancel()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("expected canceled, got %v", ctx.Err())
+	}
+	if clk.Waiters() != 0 {
+		t.Errorf("expected cancel to stop the timer, got %d waiters", clk.Waiters())
+	}
+}
@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// Owner はキーを担当するノードを返す（ノードがない場合は false）
+// Router のコンシステントハッシュで振り分けるため、ノードの追加・削除で担当が変わるキーは一部だけになる
+func (c *Cluster) Owner(key string) (*node.Node, bool) {
+	return c.Router().Owner(key)
+}
+
+// MGet は複数のキーの値を担当ノードごとにまとめて取得し、存在するキーの値を返す
+// ノードへの取得は並行して行い、稼働中でないノードが担当するキーは値なしになる
+// 失敗したノードがある場合は、取得できた値とノードごとのエラーをまとめたものを返す
+func (c *Cluster) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	router := c.Router()
+	if len(router.Nodes()) == 0 {
+		return nil, fmt.Errorf("no nodes in cluster")
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		values = make(map[string][]byte, len(keys))
+		errs   []error
+	)
+	for n, group := range router.groupKeys(keys) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := n.MGetContext(ctx, group)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("node %s: %w", n.ID(), err))
+				return
+			}
+			for key, value := range got {
+				values[key] = value
+			}
+		}()
+	}
+	wg.Wait()
+	return values, errors.Join(errs...)
+}
+
+// MSet は複数のキーに値を担当ノードごとにまとめて設定する
+// ノードへの書き込みは並行して行い、失敗したノードのエラーをまとめて返す（他のノードへの書き込みは取り消さない）
+func (c *Cluster) MSet(ctx context.Context, entries map[string][]byte) error {
+	router := c.Router()
+	if len(router.Nodes()) == 0 {
+		return fmt.Errorf("no nodes in cluster")
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for n, group := range router.groupKeys(keys) {
+		batch := make(map[string][]byte, len(group))
+		for _, key := range group {
+			batch[key] = entries[key]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := n.MSetContext(ctx, batch); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("node %s: %w", n.ID(), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestClusterOwner(t *testing.T) {
+	c := New()
+	if _, ok := c.Owner("key"); ok {
+		t.Error("expected no owner in an empty cluster")
+	}
+	_ = c.CreateNodes(3, "node")
+	first, _ := c.Owner("key")
+	again, _ := c.Owner("key")
+	if first == nil || first != again {
+		t.Errorf("expected a stable owner, got %v and %v", first, again)
+	}
+}
+
+func TestClusterMGetMSet(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	defer func() { _ = c.StopAll() }()
+
+	entries := make(map[string][]byte)
+	keys := make([]string, 0, 30)
+	for i := range 30 {
+		key := fmt.Sprintf("key-%d", i)
+		entries[key] = []byte(fmt.Sprint(i))
+		keys = append(keys, key)
+	}
+	if err := c.MSet(context.Background(), entries); err != nil {
+		t.Fatalf("failed to set keys: %v", err)
+	}
+
+	// 各キーは担当ノードにのみ書き込まれる
+	total := 0
+	for _, n := range c.Nodes() {
+		total += n.Size()
+	}
+	if total != 30 {
+		t.Errorf("expected 30 keys across the nodes, got %d", total)
+	}
+	for _, key := range keys {
+		owner, _ := c.Owner(key)
+		if _, ok := owner.Get(key); !ok {
+			t.Errorf("expected %s on its owner %s", key, owner.ID())
+		}
+	}
+
+	values, err := c.MGet(context.Background(), append(keys, "missing"))
+	if err != nil {
+		t.Fatalf("failed to get keys: %v", err)
+	}
+	if len(values) != 30 || string(values["key-7"]) != "7" {
+		t.Errorf("expected the 30 set keys, got %d", len(values))
+	}
+
+	// 停止したノードへの書き込みは失敗し、他のノードへの書き込みは残る
+	owner, _ := c.Owner("key-0")
+	_ = owner.Stop()
+	if err := c.MSet(context.Background(), entries); err == nil {
+		t.Error("expected the batch to fail on the stopped node")
+	}
+	values, _ = c.MGet(context.Background(), keys)
+	if _, ok := values["key-0"]; ok || len(values) == 0 {
+		t.Errorf("expected only the keys of running nodes, got %d", len(values))
+	}
+}
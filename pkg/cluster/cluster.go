@@ -0,0 +1,505 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// log はコンポーネント名 "cluster" を付けてログを出力する子ロガー
+var log = logger.With("cluster")
+
+// Manager はクラスタ管理の基本操作を定義するインターフェース
+type Manager interface {
+	AddNode(n *node.Node) error
+	RemoveNode(nodeID string) error
+	GetNode(nodeID string) (*node.Node, bool)
+	Nodes() []*node.Node
+	StartAll(ctx context.Context) error
+	StopAll() error
+	Size() int
+	RunningCount() int
+}
+
+// Ensure Cluster implements Manager
+var _ Manager = (*Cluster)(nil)
+
+// Cluster は複数のノードを管理する
+type Cluster struct {
+	mu    sync.RWMutex
+	nodes map[string]*node.Node
+	ctx   context.Context
+
+	generation atomic.Uint64 // ノードの追加・削除のたびに増える
+	eventBus   *events.Bus
+	clock      clock.Clock    // 追加するノードに設定する時計（nil でノードの既定のまま）
+	limits     node.Limits    // 追加するノードに設定するデータの上限
+	admission  node.Admission // 追加するノードに設定する同時に処理する操作の上限
+	store      node.StoreKind // 追加するノードに設定するデータの保持方法（空でノードの既定のまま）
+
+	// durability は追加するノードに設定するデータの永続性（Path はスナップショットのディレクトリ）
+	durability node.Durability
+
+	// latency はゾーン・ノードの間の通信の遅延（nil で遅延なし）
+	// 負荷生成のリクエストごとに参照するため、ロックを取らずに読めるようにする
+	latency atomic.Pointer[LatencyMatrix]
+
+	// router はキーを担当ノードに振り分ける Router（世代が変わったら作り直す）
+	router atomic.Pointer[Router]
+
+	// replication はキーを書き込むノードの数（0・1で担当ノードのみ）
+	replication atomic.Int32
+
+	// consistency はレプリカへの読み書きで応答を待つノードの数（nil で ConsistencyOne）
+	consistency atomic.Pointer[Consistency]
+
+	// replicaWrites は WriteReplicas が応答を待たずに続ける書き込み（StartAll から StopAll まで有効）
+	replicaWrites replicaWriteLimit
+}
+
+// New は新しいクラスタを作成する
+func New() *Cluster {
+	return &Cluster{
+		nodes: make(map[string]*node.Node),
+	}
+}
+
+// SetEventBus はノードの起動・停止・スケールのイベントを発行するバスを設定する
+// StartAll による一斉起動ではイベントを発行しない
+func (c *Cluster) SetEventBus(bus *events.Bus) {
+	c.eventBus = bus
+}
+
+// SetClock は既存のノードと以降に追加するノードに時計を設定する
+func (c *Cluster) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clk
+	for _, n := range c.nodes {
+		n.SetClock(clk)
+	}
+}
+
+// SetNodeLimits は既存のノードと以降に追加するノードにデータの上限を設定する（ゼロ値で解除）
+func (c *Cluster) SetNodeLimits(l node.Limits) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limits = l
+	for _, n := range c.nodes {
+		n.SetLimits(l)
+	}
+}
+
+// SetNodeAdmission は既存のノードと以降に追加するノードに同時に処理する操作の上限を設定する（ゼロ値で解除）
+func (c *Cluster) SetNodeAdmission(a node.Admission) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.admission = a
+	for _, n := range c.nodes {
+		n.SetAdmission(a)
+	}
+}
+
+// SetNodeDurability は既存のノードと以降に追加するノードにデータの永続性を設定する
+// node.DurabilitySnapshot の場合、d.Path をディレクトリとしてノードごとに <ノードID>.json に書き出す
+func (c *Cluster) SetNodeDurability(d node.Durability) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.durability = d
+	for _, n := range c.nodes {
+		n.SetDurability(c.nodeDurability(n.ID()))
+	}
+}
+
+// nodeDurability はノード id に設定するデータの永続性を返す
+func (c *Cluster) nodeDurability(id string) node.Durability {
+	d := c.durability
+	switch d.Mode {
+	case node.DurabilitySnapshot:
+		d.Path = filepath.Join(d.Path, id+".json")
+	case node.DurabilityWAL:
+		d.Path = filepath.Join(d.Path, id+".wal")
+	}
+	return d
+}
+
+// publishEvent はイベントを発行する
+func (c *Cluster) publishEvent(event events.Event) {
+	if c.eventBus != nil {
+		c.eventBus.Publish(event)
+	}
+}
+
+// AddNode はクラスタにノードを追加する
+func (c *Cluster) AddNode(n *node.Node) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.nodes[n.ID()]; exists {
+		return fmt.Errorf("node %s already exists in cluster", n.ID())
+	}
+
+	if c.clock != nil {
+		n.SetClock(c.clock)
+	}
+	if c.limits != (node.Limits{}) {
+		n.SetLimits(c.limits)
+	}
+	if c.admission.Enabled() {
+		n.SetAdmission(c.admission)
+	}
+	if c.durability != (node.Durability{}) {
+		n.SetDurability(c.nodeDurability(n.ID()))
+	}
+	if c.store != "" {
+		n.SetStoreKind(c.store)
+	}
+	c.nodes[n.ID()] = n
+	c.generation.Add(1)
+	log.Info("", "Node %s added to cluster", n.ID())
+	return nil
+}
+
+// RemoveNode はクラスタからノードを削除する
+// StartAll 済みのクラスタでは、削除したノードが持っていたキーを残りのノードのレプリカに移す（Rebalance）
+func (c *Cluster) RemoveNode(nodeID string) error {
+	c.mu.Lock()
+	n, exists := c.nodes[nodeID]
+	if !exists {
+		c.mu.Unlock()
+		return fmt.Errorf("node %s not found in cluster", nodeID)
+	}
+
+	ctx := c.ctx
+	var data map[string][]byte
+	if ctx != nil {
+		data = n.Export()
+	}
+	// 一時停止中・読み取り専用のノードも止め、バックグラウンドの処理を残さない
+	if n.Status() != node.StatusStopped {
+		if err := n.Stop(); err != nil {
+			log.Warn("", "Failed to stop node %s during removal: %v", nodeID, err)
+		}
+	}
+
+	delete(c.nodes, nodeID)
+	c.generation.Add(1)
+	c.mu.Unlock()
+	log.Info("", "Node %s removed from cluster", nodeID)
+	c.publishEvent(events.NewNodeStoppedEvent(nodeID, "removed"))
+
+	if ctx != nil {
+		if _, err := c.rebalance(ctx, data); err != nil {
+			log.Warn("", "Failed to rebalance keys of removed node %s: %v", nodeID, err)
+		}
+	}
+	return nil
+}
+
+// GetNode はノードIDでノードを取得する
+func (c *Cluster) GetNode(nodeID string) (*node.Node, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n, exists := c.nodes[nodeID]
+	return n, exists
+}
+
+// Nodes は全てのノードを返す
+func (c *Cluster) Nodes() []*node.Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	nodes := make([]*node.Node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// Generation はメンバー構成の世代を返す
+// ノードの追加・削除のたびに増えるため、ノード一覧をキャッシュする側の更新判定に使う
+func (c *Cluster) Generation() uint64 {
+	return c.generation.Load()
+}
+
+// StartAll は全てのノードを起動する
+func (c *Cluster) StartAll(ctx context.Context) error {
+	c.mu.Lock()
+	c.ctx = ctx
+	c.replicaWrites.start(ctx)
+	nodes := make([]*node.Node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		nodes = append(nodes, n)
+	}
+	c.mu.Unlock()
+
+	log.Info("", "Starting all nodes in cluster (count: %d)", len(nodes))
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(nodes))
+
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *node.Node) {
+			defer wg.Done()
+			if err := n.Start(ctx); err != nil {
+				errCh <- err
+			}
+		}(n)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		log.Error("", "Failed to start %d nodes", len(errs))
+		return fmt.Errorf("failed to start %d nodes", len(errs))
+	}
+
+	log.Info("", "All nodes started successfully")
+	return nil
+}
+
+// StopAll は全てのノードを停止する
+func (c *Cluster) StopAll() error {
+	c.mu.Lock()
+	c.replicaWrites.stop()
+	nodes := make([]*node.Node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		nodes = append(nodes, n)
+	}
+	c.mu.Unlock()
+
+	log.Info("", "Stopping all nodes in cluster (count: %d)", len(nodes))
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(nodes))
+
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *node.Node) {
+			defer wg.Done()
+			if err := n.Stop(); err != nil {
+				errCh <- err
+			}
+		}(n)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		log.Warn("", "Failed to stop %d nodes (may already be stopped)", len(errs))
+	}
+
+	log.Info("", "All nodes stopped")
+	return nil
+}
+
+// StartNode は停止中のノードを個別に起動する
+// StartAll で渡されたコンテキストを使用する（未起動の場合は Background）
+func (c *Cluster) StartNode(nodeID string) error {
+	c.mu.RLock()
+	n, exists := c.nodes[nodeID]
+	ctx := c.ctx
+	c.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("node %s not found in cluster", nodeID)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := n.Start(ctx); err != nil {
+		return err
+	}
+	c.publishEvent(events.NewNodeStartedEvent(nodeID))
+	return nil
+}
+
+// Size はクラスタ内のノード数を返す
+func (c *Cluster) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.nodes)
+}
+
+// RunningCount は実行中のノード数を返す
+func (c *Cluster) RunningCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	count := 0
+	for _, n := range c.nodes {
+		if n.Status() == node.StatusRunning {
+			count++
+		}
+	}
+	return count
+}
+
+// StoppedCount は停止中のノード数を返す
+func (c *Cluster) StoppedCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	count := 0
+	for _, n := range c.nodes {
+		if n.Status() == node.StatusStopped {
+			count++
+		}
+	}
+	return count
+}
+
+// CreateNodes は指定された数のノードを作成してクラスタに追加する
+func (c *Cluster) CreateNodes(count int, prefix string) error {
+	log.Info("", "Creating %d nodes with prefix '%s'", count, prefix)
+
+	for i := range count {
+		nodeID := fmt.Sprintf("%s-%d", prefix, i+1)
+		n := node.New(nodeID)
+		if err := c.AddNode(n); err != nil {
+			return err
+		}
+	}
+
+	log.Info("", "Created %d nodes successfully", count)
+	return nil
+}
+
+// sortedNodes はノードをID順に返す
+// 長さ→辞書順で並べ、node-2 が node-10 より前になるようにする
+func (c *Cluster) sortedNodes() []*node.Node {
+	nodes := c.Nodes()
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i].ID(), nodes[j].ID()
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return nodes
+}
+
+// AddNodes は prefix 付きの新しいノードを count 個作成して追加する
+// 番号は既存ノードの最大番号の続きから振る。StartAll 済みのクラスタでは追加したノードを起動し、
+// 追加したノードが担当するキーを移す（Rebalance）
+func (c *Cluster) AddNodes(count int, prefix string) ([]*node.Node, error) {
+	next := 1
+	for _, n := range c.Nodes() {
+		if num, ok := strings.CutPrefix(n.ID(), prefix+"-"); ok {
+			if i, err := strconv.Atoi(num); err == nil && i >= next {
+				next = i + 1
+			}
+		}
+	}
+
+	c.mu.RLock()
+	ctx := c.ctx
+	c.mu.RUnlock()
+	started := ctx != nil
+
+	added := make([]*node.Node, 0, count)
+	for i := range count {
+		n := node.New(fmt.Sprintf("%s-%d", prefix, next+i))
+		if err := c.AddNode(n); err != nil {
+			return added, err
+		}
+		added = append(added, n)
+		if started {
+			if err := c.StartNode(n.ID()); err != nil {
+				return added, err
+			}
+		}
+	}
+	if started {
+		if _, err := c.Rebalance(ctx); err != nil {
+			log.Warn("", "Failed to rebalance keys onto added nodes: %v", err)
+		}
+	}
+	return added, nil
+}
+
+// ScaleTo はノード数を count に合わせる
+// 増やす場合は AddNodes でノードを追加し、減らす場合は番号の大きいノードから停止して削除する
+func (c *Cluster) ScaleTo(count int, prefix string) (added, removed []string, err error) {
+	nodes := c.sortedNodes()
+	defer func() {
+		if len(added) > 0 || len(removed) > 0 {
+			c.publishEvent(events.NewNodeScaledEvent(c.Size(), added, removed))
+		}
+	}()
+
+	if diff := count - len(nodes); diff > 0 {
+		newNodes, addErr := c.AddNodes(diff, prefix)
+		for _, n := range newNodes {
+			added = append(added, n.ID())
+		}
+		return added, nil, addErr
+	}
+
+	for i := len(nodes) - 1; i >= count; i-- {
+		if err := c.RemoveNode(nodes[i].ID()); err != nil {
+			return nil, removed, err
+		}
+		removed = append(removed, nodes[i].ID())
+	}
+	return nil, removed, nil
+}
+
+// LeastUsedZone はノード数が最も少ないゾーンを返す（同数の場合は先に指定したゾーン）
+// 追加したノードのゾーンを決めるのに使う。zones が空の場合は空文字を返す
+func (c *Cluster) LeastUsedZone(zones []string) string {
+	if len(zones) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int, len(zones))
+	for _, n := range c.Nodes() {
+		counts[n.Label(node.LabelZone)]++
+	}
+	best := zones[0]
+	for _, z := range zones[1:] {
+		if counts[z] < counts[best] {
+			best = z
+		}
+	}
+	return best
+}
+
+// AssignZones はノードをID順にラウンドロビンでゾーンへ割り当てる
+// 各ノードには node.LabelZone ラベルが設定される
+func (c *Cluster) AssignZones(zones []string) {
+	if len(zones) == 0 {
+		return
+	}
+
+	nodes := c.sortedNodes()
+	for i, n := range nodes {
+		n.SetLabel(node.LabelZone, zones[i%len(zones)])
+	}
+	log.Info("", "Assigned %d nodes to %d zones", len(nodes), len(zones))
+}
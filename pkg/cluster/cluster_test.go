@@ -0,0 +1,393 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestNewCluster(t *testing.T) {
+	c := New()
+
+	if c.Size() != 0 {
+		t.Errorf("expected size 0, got %d", c.Size())
+	}
+}
+
+func TestClusterAddRemoveNode(t *testing.T) {
+	c := New()
+	n := node.New("test-node-1")
+
+	// Add
+	if err := c.AddNode(n); err != nil {
+		t.Errorf("failed to add node: %v", err)
+	}
+
+	if c.Size() != 1 {
+		t.Errorf("expected size 1, got %d", c.Size())
+	}
+
+	// Add duplicate should fail
+	if err := c.AddNode(n); err == nil {
+		t.Error("expected error when adding duplicate node")
+	}
+
+	// Get
+	retrieved, ok := c.GetNode("test-node-1")
+	if !ok {
+		t.Error("expected to find node")
+	}
+	if retrieved.ID() != n.ID() {
+		t.Errorf("expected ID %s, got %s", n.ID(), retrieved.ID())
+	}
+
+	// Remove
+	if err := c.RemoveNode("test-node-1"); err != nil {
+		t.Errorf("failed to remove node: %v", err)
+	}
+
+	if c.Size() != 0 {
+		t.Errorf("expected size 0, got %d", c.Size())
+	}
+
+	// Remove non-existent should fail
+	if err := c.RemoveNode("test-node-1"); err == nil {
+		t.Error("expected error when removing non-existent node")
+	}
+}
+
+func TestClusterRemoveStopsNode(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	// 一時停止中・読み取り専用のノードも停止してから取り除く
+	suspended, _ := c.GetNode("node-1")
+	_ = suspended.Suspend()
+	readOnly, _ := c.GetNode("node-2")
+	_ = readOnly.SetReadOnly(true)
+	for _, n := range []*node.Node{suspended, readOnly} {
+		if err := c.RemoveNode(n.ID()); err != nil {
+			t.Fatalf("failed to remove %s: %v", n.ID(), err)
+		}
+		if n.Status() != node.StatusStopped {
+			t.Errorf("expected removed node %s to be stopped, got %v", n.ID(), n.Status())
+		}
+	}
+}
+
+func TestClusterStartStopAll(t *testing.T) {
+	c := New()
+	ctx := context.Background()
+
+	_ = c.CreateNodes(5, "node")
+
+	// Start all
+	if err := c.StartAll(ctx); err != nil {
+		t.Errorf("failed to start all: %v", err)
+	}
+
+	if c.RunningCount() != 5 {
+		t.Errorf("expected 5 running, got %d", c.RunningCount())
+	}
+
+	if c.StoppedCount() != 0 {
+		t.Errorf("expected 0 stopped, got %d", c.StoppedCount())
+	}
+
+	// Stop all
+	if err := c.StopAll(); err != nil {
+		t.Errorf("failed to stop all: %v", err)
+	}
+
+	if c.RunningCount() != 0 {
+		t.Errorf("expected 0 running, got %d", c.RunningCount())
+	}
+
+	if c.StoppedCount() != 5 {
+		t.Errorf("expected 5 stopped, got %d", c.StoppedCount())
+	}
+}
+
+func TestClusterNodes(t *testing.T) {
+	c := New()
+
+	_ = c.CreateNodes(3, "node")
+
+	nodes := c.Nodes()
+	if len(nodes) != 3 {
+		t.Errorf("expected 3 nodes, got %d", len(nodes))
+	}
+}
+
+func TestClusterCreateNodes(t *testing.T) {
+	c := New()
+
+	if err := c.CreateNodes(10, "test"); err != nil {
+		t.Errorf("failed to create nodes: %v", err)
+	}
+
+	if c.Size() != 10 {
+		t.Errorf("expected size 10, got %d", c.Size())
+	}
+
+	// Verify nodes exist
+	for i := 1; i <= 10; i++ {
+		nodeID := fmt.Sprintf("test-%d", i)
+		if _, ok := c.GetNode(nodeID); !ok {
+			t.Errorf("expected node %s to exist", nodeID)
+		}
+	}
+}
+
+func TestClusterConcurrentAccess(t *testing.T) {
+	c := New()
+	ctx := context.Background()
+
+	_ = c.CreateNodes(10, "node")
+	_ = c.StartAll(ctx)
+
+	var wg sync.WaitGroup
+
+	// Concurrent reads
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = c.Nodes()
+			_ = c.Size()
+			_ = c.RunningCount()
+		}()
+	}
+
+	wg.Wait()
+	_ = c.StopAll()
+}
+
+func TestClusterStartNode(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	n, _ := c.GetNode("node-1")
+	_ = n.Stop()
+
+	if err := c.StartNode("node-1"); err != nil {
+		t.Errorf("failed to start node: %v", err)
+	}
+	if n.Status() != node.StatusRunning {
+		t.Errorf("expected status Running, got %v", n.Status())
+	}
+
+	// 既に起動中のノード
+	if err := c.StartNode("node-1"); err == nil {
+		t.Error("expected error when starting running node")
+	}
+
+	// 存在しないノード
+	if err := c.StartNode("missing"); err == nil {
+		t.Error("expected error when starting non-existent node")
+	}
+}
+
+func TestClusterAssignZones(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(5, "node")
+
+	c.AssignZones([]string{"a", "b"})
+
+	counts := map[string]int{}
+	for _, n := range c.Nodes() {
+		counts[n.Label(node.LabelZone)]++
+	}
+	if counts["a"] != 3 || counts["b"] != 2 {
+		t.Errorf("unexpected zone distribution: %v", counts)
+	}
+	if n, _ := c.GetNode("node-2"); n.Label(node.LabelZone) != "b" {
+		t.Errorf("expected node-2 in zone b, got %s", n.Label(node.LabelZone))
+	}
+
+	// 空のゾーン指定では何もしない
+	c.AssignZones(nil)
+	if n, _ := c.GetNode("node-1"); n.Label(node.LabelZone) == "" {
+		t.Error("expected zone label to be kept")
+	}
+}
+
+func TestClusterScaleTo(t *testing.T) {
+	c := New()
+	if err := c.CreateNodes(3, "node"); err != nil {
+		t.Fatalf("failed to create nodes: %v", err)
+	}
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	gen := c.Generation()
+
+	// 途中の番号が欠けていても最大番号の続きから振る
+	if err := c.RemoveNode("node-2"); err != nil {
+		t.Fatalf("failed to remove node: %v", err)
+	}
+	added, removed, err := c.ScaleTo(4, "node")
+	if err != nil {
+		t.Fatalf("failed to scale up: %v", err)
+	}
+	if fmt.Sprint(added) != "[node-4 node-5]" || len(removed) != 0 {
+		t.Errorf("expected node-4 and node-5 to be added, got added=%v removed=%v", added, removed)
+	}
+	if n, _ := c.GetNode("node-5"); n.Status() != node.StatusRunning {
+		t.Errorf("expected added node to be started, got %v", n.Status())
+	}
+	if c.Generation() == gen {
+		t.Error("expected generation to change")
+	}
+
+	added, removed, err = c.ScaleTo(2, "node")
+	if err != nil {
+		t.Fatalf("failed to scale down: %v", err)
+	}
+	if fmt.Sprint(removed) != "[node-5 node-4]" || len(added) != 0 {
+		t.Errorf("expected highest nodes to be removed, got added=%v removed=%v", added, removed)
+	}
+	if c.Size() != 2 {
+		t.Errorf("expected size 2, got %d", c.Size())
+	}
+}
+
+func TestClusterAddNodesBeforeStart(t *testing.T) {
+	c := New()
+	nodes, err := c.AddNodes(2, "node")
+	if err != nil {
+		t.Fatalf("failed to add nodes: %v", err)
+	}
+	for _, n := range nodes {
+		if n.Status() != node.StatusStopped {
+			t.Errorf("expected %s to stay stopped before StartAll, got %v", n.ID(), n.Status())
+		}
+	}
+}
+
+func TestClusterSetNodeLimits(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	limits := node.Limits{MaxKeys: 100, MaxBytes: 1 << 20, MaxMemory: 2 << 20}
+	c.SetNodeLimits(limits)
+
+	added, err := c.AddNodes(1, "node")
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+	for _, n := range append(c.Nodes(), added...) {
+		if n.Limits() != limits {
+			t.Errorf("expected limits %+v on %s, got %+v", limits, n.ID(), n.Limits())
+		}
+	}
+
+	c.SetNodeLimits(node.Limits{})
+	for _, n := range c.Nodes() {
+		if n.Limits() != (node.Limits{}) {
+			t.Errorf("expected the limits of %s to be cleared, got %+v", n.ID(), n.Limits())
+		}
+	}
+}
+
+func TestClusterSetNodeAdmission(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	admission := node.Admission{MaxInFlight: 8, ReservedReads: 2}
+	c.SetNodeAdmission(admission)
+
+	added, err := c.AddNodes(1, "node")
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+	for _, n := range append(c.Nodes(), added...) {
+		if n.Admission() != admission {
+			t.Errorf("expected admission %+v on %s, got %+v", admission, n.ID(), n.Admission())
+		}
+	}
+
+	c.SetNodeAdmission(node.Admission{})
+	for _, n := range c.Nodes() {
+		if n.Admission().Enabled() {
+			t.Errorf("expected the admission control of %s to be cleared, got %+v", n.ID(), n.Admission())
+		}
+	}
+}
+
+func TestClusterSetNodeDurability(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(1, "node")
+	dir := t.TempDir()
+	c.SetNodeDurability(node.Durability{Mode: node.DurabilitySnapshot, Path: dir})
+
+	added, err := c.AddNodes(1, "node")
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+	for _, n := range append(c.Nodes(), added...) {
+		d := n.Durability()
+		if d.Mode != node.DurabilitySnapshot || d.Path != filepath.Join(dir, n.ID()+".json") {
+			t.Errorf("expected a snapshot file per node on %s, got %+v", n.ID(), d)
+		}
+	}
+
+	c.SetNodeDurability(node.Durability{Mode: node.DurabilityWAL, Path: dir, Sync: node.WALSyncAlways})
+	for _, n := range c.Nodes() {
+		d := n.Durability()
+		if d.Path != filepath.Join(dir, n.ID()+".wal") || d.Sync != node.WALSyncAlways {
+			t.Errorf("expected a WAL file per node on %s, got %+v", n.ID(), d)
+		}
+	}
+}
+
+func TestClusterLeastUsedZone(t *testing.T) {
+	c := New()
+	if err := c.CreateNodes(3, "node"); err != nil {
+		t.Fatalf("failed to create nodes: %v", err)
+	}
+	c.AssignZones([]string{"a", "b"})
+
+	if zone := c.LeastUsedZone([]string{"a", "b"}); zone != "b" {
+		t.Errorf("expected zone b, got %s", zone)
+	}
+	if zone := c.LeastUsedZone(nil); zone != "" {
+		t.Errorf("expected empty zone, got %s", zone)
+	}
+}
+
+func TestClusterLifecycleEvents(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	bus := events.NewBus()
+	ch := bus.Subscribe()
+
+	// StartAll による一斉起動は発行しない
+	c.SetEventBus(bus)
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	if _, _, err := c.ScaleTo(3, "node"); err != nil {
+		t.Fatalf("failed to scale up: %v", err)
+	}
+	if _, _, err := c.ScaleTo(2, "node"); err != nil {
+		t.Fatalf("failed to scale down: %v", err)
+	}
+
+	var got []string
+	for len(ch) > 0 {
+		e := <-ch
+		got = append(got, fmt.Sprintf("%s:%s", e.Type, e.NodeID))
+	}
+	want := "[node_started:node-3 node_scaled: node_stopped:node-3 node_scaled:]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("expected %s, got %v", want, got)
+	}
+}
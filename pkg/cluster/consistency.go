@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConsistencyLevel はレプリカへの読み書きで応答を待つノードの数の決め方
+type ConsistencyLevel string
+
+const (
+	ConsistencyOne    ConsistencyLevel = "one"    // 1つのノードの応答で完了する（既定）
+	ConsistencyQuorum ConsistencyLevel = "quorum" // 過半数のノードの応答で完了する
+	ConsistencyAll    ConsistencyLevel = "all"    // 全てのノードの応答で完了する
+)
+
+// ParseConsistencyLevel は文字列の整合性レベルをパースする（空で ConsistencyOne）
+func ParseConsistencyLevel(s string) (ConsistencyLevel, error) {
+	switch ConsistencyLevel(strings.ToLower(s)) {
+	case "", ConsistencyOne:
+		return ConsistencyOne, nil
+	case ConsistencyQuorum:
+		return ConsistencyQuorum, nil
+	case ConsistencyAll:
+		return ConsistencyAll, nil
+	default:
+		return "", fmt.Errorf("unknown consistency level: %s", s)
+	}
+}
+
+// Required は replicas 個のノードのうち応答を待つノードの数を返す
+func (l ConsistencyLevel) Required(replicas int) int {
+	switch l {
+	case ConsistencyQuorum:
+		return replicas/2 + 1
+	case ConsistencyAll:
+		return replicas
+	default:
+		return min(replicas, 1)
+	}
+}
+
+// Consistency は書き込み（W）と読み取り（R）それぞれで応答を待つノードの数（ゼロ値でどちらも ConsistencyOne）
+// W と R の合計がレプリカの数を超える組み合わせ（QUORUM と QUORUM など）では、読み取りが最後に成功した書き込みのノードを必ず含む
+type Consistency struct {
+	Write ConsistencyLevel
+	Read  ConsistencyLevel
+}
+
+// String は "W=quorum R=one" の形式で返す
+func (c Consistency) String() string {
+	w, _ := ParseConsistencyLevel(string(c.Write))
+	r, _ := ParseConsistencyLevel(string(c.Read))
+	return fmt.Sprintf("W=%s R=%s", w, r)
+}
+
+// Validate は書き込み・読み取りの整合性レベルが正しいかを確かめる
+func (c Consistency) Validate() error {
+	if _, err := ParseConsistencyLevel(string(c.Write)); err != nil {
+		return fmt.Errorf("invalid write consistency: %w", err)
+	}
+	if _, err := ParseConsistencyLevel(string(c.Read)); err != nil {
+		return fmt.Errorf("invalid read consistency: %w", err)
+	}
+	return nil
+}
+
+// SetConsistency は WriteReplicas・ReadReplicas で応答を待つノードの数を設定する
+func (c *Cluster) SetConsistency(consistency Consistency) {
+	c.consistency.Store(&consistency)
+}
+
+// Consistency は WriteReplicas・ReadReplicas で応答を待つノードの数を返す
+func (c *Cluster) Consistency() Consistency {
+	if p := c.consistency.Load(); p != nil {
+		return *p
+	}
+	return Consistency{}
+}
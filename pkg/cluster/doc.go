@@ -0,0 +1,126 @@
+// Package cluster provides multi-node cluster management.
+//
+// A Cluster manages multiple Node instances, providing operations for
+// batch starting/stopping and node discovery.
+//
+// # Basic Usage
+//
+//	c := cluster.New()
+//
+//	// Create and add nodes
+//	if err := c.CreateNodes(5, "node"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Start all nodes
+//	if err := c.StartAll(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer c.StopAll()
+//
+//	// Access individual nodes
+//	if n, ok := c.GetNode("node-1"); ok {
+//	    n.Set("key", []byte("value"))
+//	}
+//
+// # Snapshots
+//
+// WriteSnapshot exports every node's data as NDJSON (one NodeSnapshot per
+// line). ReadSnapshot and RestoreSnapshot load it back, creating any nodes
+// that are missing from the cluster:
+//
+//	var buf bytes.Buffer
+//	_ = c.WriteSnapshot(&buf)
+//	snaps, _ := cluster.ReadSnapshot(&buf)
+//	_, _ = other.RestoreSnapshot(snaps)
+//
+// # Routing and Batches
+//
+// A Router places every node on a hash ring with DefaultVirtualNodes virtual
+// nodes each and maps a key to the first node clockwise from the key's hash,
+// so adding or removing a node only moves the keys it owns. Router returns
+// one for the current members, rebuilt when nodes are added or removed, and
+// Owner uses it:
+//
+//	if n, ok := c.Owner("user:42"); ok {
+//	    fmt.Println("owned by", n.ID())
+//	}
+//
+// MGet and MSet split a batch by owner and call each node's MGet or MSet in
+// parallel. The load generator and the RESP cluster listener route through
+// the same Router, so a key always reaches the same node.
+//
+// # Replica Sets
+//
+// SetReplicationFactor(n) keeps each key on n nodes: ReplicaSet returns the
+// owner followed by the next distinct nodes clockwise on the ring.
+// WriteReplicas sends a write to every replica in parallel, and ReadReplicas
+// reads from replicas in random order, skipping replicas that are not
+// available and failing over to the rest. Both wait for the Forward delay of
+// each node they call. Batches (MGet, MSet) and the RESP cluster listener
+// still use the owner only.
+//
+// SetConsistency tunes how many replicas must answer: a write succeeds once
+// Write (W) replicas accept it, and a read collects Read (R) answers and
+// returns the value most replicas agree on. Each level is ConsistencyOne,
+// ConsistencyQuorum (a majority) or ConsistencyAll:
+//
+//	c.SetReplicationFactor(3)
+//	c.SetConsistency(cluster.Consistency{
+//	    Write: cluster.ConsistencyQuorum,
+//	    Read:  cluster.ConsistencyQuorum,
+//	})
+//
+// Values carry no timestamps, so a read that gets as many stale answers as
+// fresh ones returns the first that arrived.
+//
+// Writes to the replicas a write did not wait for keep running after it
+// returns, until StopAll. At most MaxBackgroundReplicaWrites calls may have
+// such writes in flight; beyond that the leftover writes are cancelled and
+// counted by DroppedReplicaWrites.
+//
+// # Rebalancing
+//
+// Rebalance moves keys to the replicas the current Router assigns them:
+// replicas missing a key receive it, and nodes that are no longer replicas
+// drop it once a running replica holds it. Stopped nodes are skipped and
+// catch up on a later Rebalance. Keys are copied only to replicas that still
+// lack them (node.MSetMissing) and dropped only if unchanged since they were
+// read (node.MDeleteUnchanged), so writes that land during a rebalance are
+// kept; both run in one batch per node without the injected delay. Once StartAll has run, AddNodes and
+// RemoveNode rebalance on their own, and RemoveNode hands the removed node's
+// keys to their new replicas, so nodes can join and leave while a scenario
+// is running:
+//
+//	if _, err := c.AddNodes(1, "node"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	stats, _ := c.Rebalance(ctx) // nothing left to move
+//	fmt.Println(stats.Copied, stats.Removed)
+//
+// # Replicated Stores
+//
+// With SetStoreKind(node.StoreCRDT), Sync merges the states of all running
+// nodes into each of them (anti-entropy). Stopped and suspended nodes are
+// left out and catch up on the next Sync after they come back. Divergence
+// counts keys whose values differ between running nodes for either store.
+//
+// # Latency Matrix
+//
+// SetLatencyMatrix models a multi-datacenter topology by delaying traffic
+// between nodes according to their zone labels (IntraZone, InterZone), with
+// Links overriding specific zone or node pairs. SyncContext delivers each
+// node's state to the others after that delay, and Forward waits for the
+// delay from the Coordinator zone or node before a request reaches a node:
+//
+//	c.SetLatencyMatrix(cluster.LatencyMatrix{
+//	    IntraZone:   time.Millisecond,
+//	    InterZone:   80 * time.Millisecond,
+//	    Coordinator: "us-east",
+//	})
+//
+// # Thread Safety
+//
+// All cluster operations are thread-safe and can be called concurrently.
+// Node starting and stopping is performed in parallel for efficiency.
+package cluster
@@ -0,0 +1,157 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// LatencyMatrix はゾーン・ノードの間の通信の遅延
+// ノードの間のデータの同期（Sync）と、コーディネーターから各ノードへの負荷生成のリクエストの転送（Forward）に加える
+type LatencyMatrix struct {
+	IntraZone time.Duration // 同じゾーンのノードの間（ゾーンのないノード同士を含む）
+	InterZone time.Duration // 異なるゾーンのノードの間
+
+	// Links は特定のゾーン・ノードの組の遅延（向きによらない）
+	// IntraZone・InterZone より優先し、ゾーンよりノードを指定したものを優先する
+	Links []Link
+
+	// Coordinator は負荷生成のリクエストを受け付けて各ノードに転送するゾーンまたはノード（空で転送に遅延を加えない）
+	Coordinator string
+}
+
+// Link は2つのゾーンまたはノードの間の遅延
+type Link struct {
+	From  string        // ゾーンまたはノードのID
+	To    string        // ゾーンまたはノードのID
+	Delay time.Duration // 片道の遅延
+}
+
+// endpoint は遅延を求める通信の端点（ゾーンのみの場合は ID が空）
+type endpoint struct {
+	id   string
+	zone string
+}
+
+// endpointOf はノードの端点を返す
+func endpointOf(n *node.Node) endpoint {
+	return endpoint{id: n.ID(), zone: n.Label(node.LabelZone)}
+}
+
+// match は name が端点のノードまたはゾーンを指すかと、その優先度（ノードで2、ゾーンで1）を返す
+func (e endpoint) match(name string) int {
+	switch {
+	case e.id != "" && name == e.id:
+		return 2
+	case name == e.zone:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Enabled は遅延が設定されているかを返す
+func (m LatencyMatrix) Enabled() bool {
+	return m.IntraZone > 0 || m.InterZone > 0 || len(m.Links) > 0
+}
+
+// Validate は遅延が負でなく、全ての Link に両端が指定されているかを検証する
+func (m LatencyMatrix) Validate() error {
+	if m.IntraZone < 0 || m.InterZone < 0 {
+		return fmt.Errorf("latency must be non-negative")
+	}
+	for i, l := range m.Links {
+		if l.From == "" || l.To == "" {
+			return fmt.Errorf("latency link %d requires from and to", i+1)
+		}
+		if l.Delay < 0 {
+			return fmt.Errorf("latency link %d must be non-negative", i+1)
+		}
+	}
+	return nil
+}
+
+// Between はノード from から to への通信の遅延を返す（同じノードの場合は0）
+func (m LatencyMatrix) Between(from, to *node.Node) time.Duration {
+	if from.ID() == to.ID() {
+		return 0
+	}
+	return m.latency(endpointOf(from), endpointOf(to))
+}
+
+// latency は端点の間の遅延を、最も具体的に一致する Link、ゾーンの一致の順に求める
+func (m LatencyMatrix) latency(a, b endpoint) time.Duration {
+	best, delay := 0, time.Duration(0)
+	for _, l := range m.Links {
+		for _, pair := range [2][2]string{{l.From, l.To}, {l.To, l.From}} {
+			ma, mb := a.match(pair[0]), b.match(pair[1])
+			if ma > 0 && mb > 0 && ma+mb > best {
+				best, delay = ma+mb, l.Delay
+			}
+		}
+	}
+	if best > 0 {
+		return delay
+	}
+	if a.zone == b.zone {
+		return m.IntraZone
+	}
+	return m.InterZone
+}
+
+// SetLatencyMatrix はゾーン・ノードの間の通信の遅延を設定する（ゼロ値で解除）
+func (c *Cluster) SetLatencyMatrix(m LatencyMatrix) {
+	if !m.Enabled() {
+		c.latency.Store(nil)
+		return
+	}
+	c.latency.Store(&m)
+}
+
+// LatencyMatrix はゾーン・ノードの間の通信の遅延を返す
+func (c *Cluster) LatencyMatrix() LatencyMatrix {
+	if m := c.latency.Load(); m != nil {
+		return *m
+	}
+	return LatencyMatrix{}
+}
+
+// timeSource はクラスタの時計を返す（未設定の場合は実時間）
+func (c *Cluster) timeSource() clock.Clock {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.clock == nil {
+		return clock.Real()
+	}
+	return c.clock
+}
+
+// CoordinatorLatency はコーディネーターからノード to への転送の遅延を返す
+// Coordinator がノードの ID の場合はそのノードから、それ以外の場合はそのゾーンからの遅延になる
+func (c *Cluster) CoordinatorLatency(to *node.Node) time.Duration {
+	m := c.latency.Load()
+	if m == nil || m.Coordinator == "" {
+		return 0
+	}
+	from := endpoint{zone: m.Coordinator}
+	if n, ok := c.GetNode(m.Coordinator); ok {
+		if n.ID() == to.ID() {
+			return 0
+		}
+		from = endpointOf(n)
+	}
+	return m.latency(from, endpointOf(to))
+}
+
+// Forward はコーディネーターからノード to への転送の遅延だけ待つ
+// 待機中に ctx がキャンセルされた場合はそのエラーを返す
+func (c *Cluster) Forward(ctx context.Context, to *node.Node) error {
+	d := c.CoordinatorLatency(to)
+	if d <= 0 {
+		return nil
+	}
+	return clock.Sleep(ctx, c.timeSource(), d)
+}
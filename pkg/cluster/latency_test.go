@@ -0,0 +1,155 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// newZonedNode はゾーンを設定したノードを作成する
+func newZonedNode(id, zone string) *node.Node {
+	n := node.New(id)
+	n.SetLabel(node.LabelZone, zone)
+	return n
+}
+
+func TestLatencyMatrixBetween(t *testing.T) {
+	m := LatencyMatrix{
+		IntraZone: time.Millisecond,
+		InterZone: 80 * time.Millisecond,
+		Links: []Link{
+			{From: "us", To: "eu", Delay: 120 * time.Millisecond},
+			{From: "node-3", To: "eu", Delay: 200 * time.Millisecond},
+		},
+	}
+	n1 := newZonedNode("node-1", "us")
+	n2 := newZonedNode("node-2", "us")
+	n3 := newZonedNode("node-3", "us")
+	n4 := newZonedNode("node-4", "eu")
+	n5 := newZonedNode("node-5", "ap")
+
+	tests := []struct {
+		name     string
+		from, to *node.Node
+		want     time.Duration
+	}{
+		{"same node", n1, n1, 0},
+		{"intra zone", n1, n2, time.Millisecond},
+		{"inter zone", n1, n5, 80 * time.Millisecond},
+		{"zone link", n1, n4, 120 * time.Millisecond},
+		{"zone link reversed", n4, n1, 120 * time.Millisecond},
+		{"node link over zone link", n4, n3, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := m.Between(tt.from, tt.to); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestLatencyMatrixValidate(t *testing.T) {
+	valid := LatencyMatrix{InterZone: time.Millisecond, Links: []Link{{From: "a", To: "b", Delay: time.Millisecond}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected a valid matrix, got %v", err)
+	}
+	for _, m := range []LatencyMatrix{
+		{IntraZone: -1},
+		{Links: []Link{{From: "a", Delay: time.Millisecond}}},
+		{Links: []Link{{From: "a", To: "b", Delay: -1}}},
+	} {
+		if err := m.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", m)
+		}
+	}
+}
+
+func TestClusterCoordinatorLatency(t *testing.T) {
+	c := New()
+	_ = c.AddNode(newZonedNode("node-1", "us"))
+	_ = c.AddNode(newZonedNode("node-2", "eu"))
+	n1, _ := c.GetNode("node-1")
+	n2, _ := c.GetNode("node-2")
+
+	if got := c.CoordinatorLatency(n2); got != 0 {
+		t.Errorf("expected no latency without a matrix, got %v", got)
+	}
+
+	c.SetLatencyMatrix(LatencyMatrix{IntraZone: time.Millisecond, InterZone: 80 * time.Millisecond, Coordinator: "us"})
+	if got := c.CoordinatorLatency(n1); got != time.Millisecond {
+		t.Errorf("expected intra-zone latency from the coordinator zone, got %v", got)
+	}
+	if got := c.CoordinatorLatency(n2); got != 80*time.Millisecond {
+		t.Errorf("expected inter-zone latency from the coordinator zone, got %v", got)
+	}
+
+	c.SetLatencyMatrix(LatencyMatrix{InterZone: 80 * time.Millisecond, Coordinator: "node-2"})
+	if got := c.CoordinatorLatency(n2); got != 0 {
+		t.Errorf("expected no latency to the coordinator node itself, got %v", got)
+	}
+	if got := c.CoordinatorLatency(n1); got != 80*time.Millisecond {
+		t.Errorf("expected latency from the coordinator node's zone, got %v", got)
+	}
+
+	c.SetLatencyMatrix(LatencyMatrix{})
+	if c.LatencyMatrix().Enabled() || c.CoordinatorLatency(n1) != 0 {
+		t.Error("expected the matrix to be cleared")
+	}
+}
+
+func TestClusterSyncLatency(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	c := New()
+	c.SetClock(clk)
+	c.SetStoreKind(node.StoreCRDT)
+	_ = c.AddNode(newZonedNode("node-1", "us"))
+	_ = c.AddNode(newZonedNode("node-2", "us"))
+	_ = c.AddNode(newZonedNode("node-3", "eu"))
+	c.SetLatencyMatrix(LatencyMatrix{IntraZone: time.Millisecond, InterZone: 80 * time.Millisecond})
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	defer func() { _ = c.StopAll() }()
+
+	n1, _ := c.GetNode("node-1")
+	n2, _ := c.GetNode("node-2")
+	n3, _ := c.GetNode("node-3")
+	_ = n2.Set("us", []byte("2"))
+	_ = n3.Set("eu", []byte("3"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan int, 1)
+	go func() {
+		changed, err := c.SyncContext(ctx)
+		if err != nil {
+			t.Errorf("failed to sync: %v", err)
+		}
+		done <- changed
+	}()
+
+	// ノードごとに最初の配送を待つ
+	if err := clk.BlockUntil(ctx, 3); err != nil {
+		t.Fatalf("sync did not start: %v", err)
+	}
+	clk.Advance(time.Millisecond)
+	if err := clk.BlockUntil(ctx, 3); err != nil {
+		t.Fatalf("sync did not continue: %v", err)
+	}
+	if _, ok := n1.Get("us"); !ok {
+		t.Error("expected the intra-zone state to arrive after 1ms")
+	}
+	if _, ok := n1.Get("eu"); ok {
+		t.Error("expected the inter-zone state to be still in flight")
+	}
+
+	clk.Advance(79 * time.Millisecond)
+	if changed := <-done; changed != 4 {
+		t.Errorf("expected 4 changed keys, got %d", changed)
+	}
+	if got := c.Divergence(); got != 0 {
+		t.Errorf("expected the nodes to converge, got %d divergent keys", got)
+	}
+}
@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// RebalanceStats は Rebalance で移したキーの数
+type RebalanceStats struct {
+	Copied  int `json:"copied"`  // キーを持っていなかったレプリカに書き込んだ数
+	Removed int `json:"removed"` // レプリカでなくなったノードから削除した数
+}
+
+// Rebalance は稼働中のノードのキーを Router が決めるレプリカ（ReplicaSet）に移す
+// キーを持たないレプリカには書き込み、レプリカでなくなったノードからは、稼働中のレプリカに書き込めたキーだけを削除する
+// 稼働していないノードは対象にせず、戻った後の Rebalance でキーを受け取る
+// 書き込みはレプリカがキーを持っていない場合だけ（MSetMissing）、削除は値を読んだときのままの場合だけ行う（MDeleteUnchanged）ため、
+// 移している間にクライアントが書き込んだ値を失わない。ノードごとに1度にまとめ、注入された遅延は待たない
+// 読み取り専用のノードには書き込めず、キーを削除しない
+func (c *Cluster) Rebalance(ctx context.Context) (RebalanceStats, error) {
+	return c.rebalance(ctx, nil)
+}
+
+// rebalance は Rebalance と同じくキーを移し、クラスタから削除したノードのデータ orphans もレプリカに書き込む
+func (c *Cluster) rebalance(ctx context.Context, orphans map[string][]byte) (RebalanceStats, error) {
+	router := c.Router()
+	factor := c.ReplicationFactor()
+	nodes := c.runningNodes()
+	held := make(map[*node.Node]map[string][]byte, len(nodes))
+	for _, n := range nodes {
+		held[n] = n.Export()
+	}
+
+	writes := make(map[*node.Node]map[string][]byte)
+	drops := make(map[*node.Node][]string)
+	// place は key を持たない稼働中のレプリカへの書き込みを予定し、キーを持つレプリカが残るかを返す
+	place := func(key string, value []byte, replicas []*node.Node) bool {
+		kept := false
+		for _, r := range replicas {
+			data, running := held[r]
+			if !running {
+				continue
+			}
+			kept = true
+			if _, ok := data[key]; ok {
+				continue
+			}
+			if _, ok := writes[r][key]; ok {
+				continue
+			}
+			if writes[r] == nil {
+				writes[r] = make(map[string][]byte)
+			}
+			writes[r][key] = value
+		}
+		return kept
+	}
+	for _, n := range nodes {
+		for key, value := range held[n] {
+			replicas := router.Replicas(key, factor)
+			if place(key, value, replicas) && !slices.Contains(replicas, n) {
+				drops[n] = append(drops[n], key)
+			}
+		}
+	}
+	for key, value := range orphans {
+		place(key, value, router.Replicas(key, factor))
+	}
+
+	var (
+		stats  RebalanceStats
+		errs   []error
+		failed = make(map[string]bool)
+	)
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	for _, n := range nodes {
+		if len(writes[n]) == 0 {
+			continue
+		}
+		copied, err := n.MSetMissing(writes[n])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", n.ID(), err))
+			for key := range writes[n] {
+				failed[key] = true
+			}
+			continue
+		}
+		stats.Copied += copied
+	}
+	for _, n := range nodes {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		unchanged := make(map[string][]byte, len(drops[n]))
+		for _, key := range drops[n] {
+			if !failed[key] {
+				unchanged[key] = held[n][key]
+			}
+		}
+		if len(unchanged) == 0 {
+			continue
+		}
+		removed, err := n.MDeleteUnchanged(unchanged)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", n.ID(), err))
+			continue
+		}
+		stats.Removed += removed
+	}
+
+	if stats.Copied > 0 || stats.Removed > 0 {
+		log.Info("", "Rebalanced keys across %d nodes: %d copied, %d removed", len(nodes), stats.Copied, stats.Removed)
+	}
+	return stats, errors.Join(errs...)
+}
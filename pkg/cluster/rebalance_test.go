@@ -0,0 +1,161 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// ownedBy はノードのキーが全てそのノードのレプリカに含まれるかを返す
+func ownedBy(t *testing.T, c *Cluster) {
+	t.Helper()
+	for _, n := range c.Nodes() {
+		for _, key := range n.Keys() {
+			if !slices.Contains(c.ReplicaSet(key), n) {
+				t.Errorf("key %s left on %s outside its replicas", key, n.ID())
+			}
+		}
+	}
+}
+
+func TestClusterRebalance(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	// 担当ノードでないノードに書いたキーも担当ノードに移す
+	first := c.Nodes()[0]
+	for i := range 100 {
+		_ = first.Set(fmt.Sprintf("key-%d", i), []byte("v"))
+	}
+	stats, err := c.Rebalance(ctx)
+	if err != nil {
+		t.Fatalf("Rebalance failed: %v", err)
+	}
+	if stats.Copied == 0 || stats.Copied != stats.Removed {
+		t.Errorf("expected keys to be moved to their owner, got %+v", stats)
+	}
+	ownedBy(t, c)
+
+	// 配置が正しければ何も移さない
+	if stats, _ := c.Rebalance(ctx); stats != (RebalanceStats{}) {
+		t.Errorf("expected nothing to move, got %+v", stats)
+	}
+}
+
+func TestClusterAddRemoveRebalances(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	for i := range 300 {
+		key := fmt.Sprintf("key-%d", i)
+		owner, _ := c.Owner(key)
+		_ = owner.Set(key, []byte("v"))
+	}
+
+	added, err := c.AddNodes(1, "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added[0].Keys()) == 0 {
+		t.Error("expected the added node to take over keys")
+	}
+	ownedBy(t, c)
+
+	// 削除したノードのキーは残りのノードに移り、失われない
+	if err := c.RemoveNode("node-2"); err != nil {
+		t.Fatal(err)
+	}
+	ownedBy(t, c)
+	total := 0
+	for _, n := range c.Nodes() {
+		total += len(n.Keys())
+	}
+	if total != 300 {
+		t.Errorf("expected all 300 keys to survive the removal, got %d", total)
+	}
+}
+
+func TestClusterRebalanceReplicas(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+	c.SetReplicationFactor(2)
+
+	for i := range 50 {
+		key := fmt.Sprintf("key-%d", i)
+		owner, _ := c.Owner(key)
+		_ = owner.Set(key, []byte("v"))
+	}
+
+	// 停止したレプリカには書き込まず、キーを持つノードから削除しない
+	stopped := c.Nodes()[0]
+	_ = stopped.Stop()
+	if _, err := c.Rebalance(ctx); err != nil {
+		t.Fatalf("Rebalance failed: %v", err)
+	}
+	_ = stopped.Start(ctx)
+	if _, err := c.Rebalance(ctx); err != nil {
+		t.Fatalf("Rebalance failed: %v", err)
+	}
+	for i := range 50 {
+		key := fmt.Sprintf("key-%d", i)
+		for _, r := range c.ReplicaSet(key) {
+			if _, ok := r.Get(key); !ok {
+				t.Errorf("expected %s on replica %s", key, r.ID())
+			}
+		}
+	}
+	ownedBy(t, c)
+}
+
+func TestClusterRebalanceKeepsNewerWrites(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+
+	// 担当ノードでないノードに古い値を置き、担当ノードには新しい値がある
+	key := "key"
+	owner, _ := c.Owner(key)
+	var other *node.Node
+	for _, n := range c.Nodes() {
+		if n != owner {
+			other = n
+		}
+	}
+	_ = other.Set(key, []byte("old"))
+	_ = owner.Set(key, []byte("new"))
+	// 遅延を注入しても Rebalance は待たない
+	other.SetDelay(time.Second)
+	owner.SetDelay(time.Second)
+
+	start := time.Now()
+	stats, err := c.Rebalance(ctx)
+	if err != nil {
+		t.Fatalf("Rebalance failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected Rebalance to skip the injected delay, took %v", elapsed)
+	}
+	if stats.Copied != 0 || stats.Removed != 1 {
+		t.Errorf("expected only the stale copy to be removed, got %+v", stats)
+	}
+	other.SetDelay(0)
+	owner.SetDelay(0)
+	if value, _ := owner.Get(key); string(value) != "new" {
+		t.Errorf("expected the owner to keep the newer value, got %q", value)
+	}
+}
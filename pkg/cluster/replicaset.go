@@ -0,0 +1,291 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// SetReplicationFactor はキーを書き込むノードの数を設定する（0・1で担当ノードのみ）
+// キーは Router で担当ノードから時計回りに並ぶ factor 個のノードに書き込み、読み取りはそのどれからでも行う
+func (c *Cluster) SetReplicationFactor(factor int) {
+	c.replication.Store(int32(max(factor, 1)))
+}
+
+// ReplicationFactor はキーを書き込むノードの数を返す
+func (c *Cluster) ReplicationFactor() int {
+	return max(int(c.replication.Load()), 1)
+}
+
+// ReplicaSet はキーを保持するノードを返す（先頭が担当ノード、ノードが少ない場合はノードの数まで）
+func (c *Cluster) ReplicaSet(key string) []*node.Node {
+	return c.Router().Replicas(key, c.ReplicationFactor())
+}
+
+// MaxBackgroundReplicaWrites は WriteReplicas が応答を待たずに続ける書き込みを同時に持てる呼び出しの数
+const MaxBackgroundReplicaWrites = 1024
+
+// replicaWriteLimit は WriteReplicas が応答を待たずに続ける書き込みの寿命と数を管理する（ゼロ値で使える）
+type replicaWriteLimit struct {
+	mu      sync.Mutex
+	ctx     context.Context // StartAll から StopAll まで有効（nil で期限なし）
+	cancel  context.CancelFunc
+	running atomic.Int64  // 続けている書き込みのある呼び出しの数
+	dropped atomic.Uint64 // 上限を超えたため打ち切った呼び出しの数
+}
+
+// start は ctx が終わるか stop されるまで続く寿命を設定する
+func (l *replicaWriteLimit) start(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.ctx, l.cancel = context.WithCancel(ctx)
+}
+
+// stop は続けている書き込みを全て打ち切る
+func (l *replicaWriteLimit) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+// lifetime は続ける書き込みの寿命を返す（StartAll の前は Background）
+func (l *replicaWriteLimit) lifetime() context.Context {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ctx == nil {
+		return context.Background()
+	}
+	return l.ctx
+}
+
+// acquire は続ける書き込みの枠を1つ確保する（上限に達している場合は false）
+func (l *replicaWriteLimit) acquire() bool {
+	if l.running.Add(1) > MaxBackgroundReplicaWrites {
+		l.running.Add(-1)
+		l.dropped.Add(1)
+		return false
+	}
+	return true
+}
+
+// replicaWrite は1回の WriteReplicas のノードへの書き込みの状態
+// 呼び出し元が応答を待たなくなった後も続く書き込みは、全て終わるまで replicaWriteLimit の枠を使う
+type replicaWrite struct {
+	limit  *replicaWriteLimit
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	pending  int  // 終わっていないノードへの書き込みの数
+	detached bool // 枠を確保して呼び出し元から切り離した
+}
+
+// done はノードへの書き込みが1つ終わったことを記録し、全て終わったら寿命と枠を解放する
+func (w *replicaWrite) done() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending--; w.pending > 0 {
+		return
+	}
+	w.cancel()
+	if w.detached {
+		w.limit.running.Add(-1)
+	}
+}
+
+// detach は呼び出し元が応答を待たなくなったときに呼ぶ
+// 終わっていない書き込みは枠を確保できれば続け、確保できなければ打ち切る
+func (w *replicaWrite) detach() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == 0 {
+		return
+	}
+	if w.detached = w.limit.acquire(); !w.detached {
+		w.cancel()
+	}
+}
+
+// DroppedReplicaWrites は続ける書き込みの数が MaxBackgroundReplicaWrites に達していたため、
+// 応答を待たない残りのノードへの書き込みを打ち切った WriteReplicas の呼び出しの数を返す
+func (c *Cluster) DroppedReplicaWrites() uint64 {
+	return c.replicaWrites.dropped.Load()
+}
+
+// WriteReplicas は replicas の全てのノードに write を並行して呼び出す
+// ノードごとにコーディネーターからの転送の遅延を待ってから呼び出し、Consistency().Write の数のノードが成功した時点で nil を返す
+// 必要な数のノードが成功できなくなった場合はノードごとのエラーをまとめて返す
+// 残りのノードへの書き込みは ctx がキャンセルされても StopAll まで続ける。ただし続けている書き込みのある呼び出しが
+// MaxBackgroundReplicaWrites に達している場合は打ち切り、DroppedReplicaWrites に数える
+func (c *Cluster) WriteReplicas(ctx context.Context, replicas []*node.Node, write func(ctx context.Context, n *node.Node) error) error {
+	if len(replicas) == 0 {
+		return fmt.Errorf("no nodes in cluster")
+	}
+	if len(replicas) == 1 {
+		return c.call(ctx, replicas[0], write)
+	}
+
+	need := c.Consistency().Write.Required(len(replicas))
+	results := make(chan error, len(replicas))
+
+	// ノードへの書き込みは呼び出し元の ctx の値を引き継ぎ、キャンセルはクラスタの寿命に従う
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(c.replicaWrites.lifetime(), cancel)
+	w := &replicaWrite{
+		limit:   &c.replicaWrites,
+		cancel:  func() { stop(); cancel() },
+		pending: len(replicas),
+	}
+	for _, n := range replicas {
+		go func() {
+			defer w.done()
+			if err := c.call(detached, n, write); err != nil {
+				results <- fmt.Errorf("node %s: %w", n.ID(), err)
+				return
+			}
+			results <- nil
+		}()
+	}
+	defer w.detach()
+
+	acks := 0
+	var errs []error
+	for range replicas {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-results:
+			if err == nil {
+				if acks++; acks >= need {
+					return nil
+				}
+				continue
+			}
+			if errs = append(errs, err); len(errs) > len(replicas)-need {
+				return errors.Join(errs...)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReplicaRead は ReadReplicas で読み取った値と、その値を返したノード
+type ReplicaRead struct {
+	Node  *node.Node
+	Value []byte
+	Found bool
+}
+
+// ReadReplicas は replicas からランダムな順に Consistency().Read の数のノードに read を並行して呼び出し、読み取った値を返す
+// 失敗したノードの代わりに残りのノードを順に試す。停止中のノードの Get はエラーにならず値なしを返すため、
+// 読み取りを受け付けないノードは呼び出さずに失敗とする
+// 複数のノードから読んだ場合は、値のあるノードのうち最も多くのノードが返した値を選ぶ
+// 必要な数のノードが成功できなかった場合は、最後に試したノードとノードごとのエラーをまとめて返す
+func (c *Cluster) ReadReplicas(ctx context.Context, replicas []*node.Node, read func(ctx context.Context, n *node.Node) ([]byte, bool, error)) (ReplicaRead, error) {
+	if len(replicas) == 0 {
+		return ReplicaRead{}, fmt.Errorf("no nodes in cluster")
+	}
+
+	type result struct {
+		read ReplicaRead
+		err  error
+	}
+	results := make(chan result, len(replicas))
+	order := rand.Perm(len(replicas))
+	var (
+		last *node.Node
+		errs []error
+	)
+	// send は次のノードに読み取りを送る（送れるノードが残っていない場合は false）
+	send := func() bool {
+		for len(order) > 0 {
+			n := replicas[order[0]]
+			order = order[1:]
+			last = n
+			if status := n.Status(); !status.Available() {
+				errs = append(errs, fmt.Errorf("node %s is %s", n.ID(), status))
+				continue
+			}
+			go func() {
+				var r result
+				r.read.Node = n
+				if r.err = c.Forward(ctx, n); r.err == nil {
+					r.read.Value, r.read.Found, r.err = read(ctx, n)
+				}
+				if r.err != nil {
+					r.err = fmt.Errorf("node %s: %w", n.ID(), r.err)
+				}
+				results <- r
+			}()
+			return true
+		}
+		return false
+	}
+
+	need := c.Consistency().Read.Required(len(replicas))
+	pending := 0
+	for range need {
+		if send() {
+			pending++
+		}
+	}
+	var reads []ReplicaRead
+	for pending > 0 {
+		var r result
+		select {
+		case <-ctx.Done():
+			return ReplicaRead{Node: last}, ctx.Err()
+		case r = <-results:
+		}
+		pending--
+		if r.err != nil {
+			errs = append(errs, r.err)
+			if send() {
+				pending++
+			}
+			continue
+		}
+		if reads = append(reads, r.read); len(reads) == need {
+			return resolveReads(reads), nil
+		}
+	}
+	return ReplicaRead{Node: last}, errors.Join(errs...)
+}
+
+// resolveReads は複数のノードから読んだ値のうち、値のあるノードで最も多く返された値を選ぶ（同数の場合は先に応答したノード）
+func resolveReads(reads []ReplicaRead) ReplicaRead {
+	best, votes := reads[0], 0
+	for _, r := range reads {
+		if !r.Found {
+			continue
+		}
+		count := 0
+		for _, other := range reads {
+			if other.Found && bytes.Equal(other.Value, r.Value) {
+				count++
+			}
+		}
+		if count > votes {
+			best, votes = r, count
+		}
+	}
+	return best
+}
+
+// call はコーディネーターからノード n への転送の遅延を待ってから op を呼び出す
+func (c *Cluster) call(ctx context.Context, n *node.Node, op func(ctx context.Context, n *node.Node) error) error {
+	if err := c.Forward(ctx, n); err != nil {
+		return err
+	}
+	return op(ctx, n)
+}
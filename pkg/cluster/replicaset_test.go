@@ -0,0 +1,258 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestRouterReplicas(t *testing.T) {
+	nodes := []*node.Node{node.New("node-1"), node.New("node-2"), node.New("node-3")}
+	r := NewRouter(nodes, 0)
+
+	for i := range 100 {
+		key := fmt.Sprintf("key-%d", i)
+		replicas := r.Replicas(key, 2)
+		owner, _ := r.Owner(key)
+		if len(replicas) != 2 || replicas[0] != owner || replicas[1] == owner {
+			t.Fatalf("expected the owner and one other node for %s, got %v", key, replicas)
+		}
+	}
+	if replicas := r.Replicas("key", 5); len(replicas) != 3 {
+		t.Errorf("expected the replicas to be capped at the node count, got %d", len(replicas))
+	}
+	if replicas := NewRouter(nil, 0).Replicas("key", 3); replicas != nil {
+		t.Errorf("expected no replicas without nodes, got %v", replicas)
+	}
+}
+
+func TestClusterReplicationFactor(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+
+	if f := c.ReplicationFactor(); f != 1 {
+		t.Errorf("expected factor 1 by default, got %d", f)
+	}
+	if replicas := c.ReplicaSet("key"); len(replicas) != 1 {
+		t.Errorf("expected only the owner, got %d nodes", len(replicas))
+	}
+	c.SetReplicationFactor(2)
+	if replicas := c.ReplicaSet("key"); len(replicas) != 2 {
+		t.Errorf("expected 2 replicas, got %d", len(replicas))
+	}
+}
+
+func TestClusterWriteReplicas(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+	c.SetReplicationFactor(3)
+	c.SetConsistency(Consistency{Write: ConsistencyAll})
+
+	replicas := c.ReplicaSet("key")
+	set := func(ctx context.Context, n *node.Node) error { return n.SetContext(ctx, "key", []byte("v")) }
+	if err := c.WriteReplicas(ctx, replicas, set); err != nil {
+		t.Fatalf("WriteReplicas failed: %v", err)
+	}
+	for _, n := range replicas {
+		if _, ok := n.Get("key"); !ok {
+			t.Errorf("expected the key on %s", n.ID())
+		}
+	}
+
+	// ALL は1つでも書き込めなければ失敗し、ONE は1つでも書き込めれば成功とする
+	_ = replicas[0].Stop()
+	if err := c.WriteReplicas(ctx, replicas, set); err == nil {
+		t.Error("expected the write to fail with a stopped replica")
+	}
+	c.SetConsistency(Consistency{})
+	_ = replicas[1].Stop()
+	if err := c.WriteReplicas(ctx, replicas, set); err != nil {
+		t.Errorf("expected the write to succeed on the running replica, got %v", err)
+	}
+	_ = replicas[2].Stop()
+	if err := c.WriteReplicas(ctx, replicas, set); err == nil {
+		t.Error("expected an error when every replica is stopped")
+	}
+}
+
+func TestClusterWriteReplicasBackground(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	_ = c.StartAll(context.Background())
+	c.SetReplicationFactor(2)
+
+	replicas := c.ReplicaSet("key")
+	cancelled := make(chan struct{}, 2)
+	// 担当ノード以外への書き込みは ctx が終わるまで戻らない
+	write := func(ctx context.Context, n *node.Node) error {
+		if n == replicas[0] {
+			return nil
+		}
+		<-ctx.Done()
+		cancelled <- struct{}{}
+		return ctx.Err()
+	}
+
+	// 呼び出し元の ctx がキャンセルされても続け、StopAll で打ち切る
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.WriteReplicas(ctx, replicas, write); err != nil {
+		t.Fatalf("WriteReplicas failed: %v", err)
+	}
+	cancel()
+	select {
+	case <-cancelled:
+		t.Fatal("expected the remaining write to outlive the caller's context")
+	case <-time.After(20 * time.Millisecond):
+	}
+	_ = c.StopAll()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected StopAll to cancel the remaining write")
+	}
+
+	// 続けている書き込みが上限に達している場合は打ち切る
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+	c.replicaWrites.running.Store(MaxBackgroundReplicaWrites)
+	if err := c.WriteReplicas(context.Background(), replicas, write); err != nil {
+		t.Fatalf("WriteReplicas failed: %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the remaining write to be dropped over the limit")
+	}
+	if got := c.DroppedReplicaWrites(); got != 1 {
+		t.Errorf("expected 1 dropped write, got %d", got)
+	}
+}
+
+func TestClusterReadReplicas(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+	c.SetReplicationFactor(2)
+
+	replicas := c.ReplicaSet("key")
+	var calls atomic.Int32
+	get := func(ctx context.Context, n *node.Node) ([]byte, bool, error) {
+		calls.Add(1)
+		return n.GetContext(ctx, "key")
+	}
+
+	// 停止したノードは呼び出さずに残りのノードから読む
+	_ = replicas[0].Stop()
+	for range 10 {
+		read, err := c.ReadReplicas(ctx, replicas, get)
+		if err != nil || read.Node != replicas[1] {
+			t.Fatalf("expected the read to be served by %s, got %v (%v)", replicas[1].ID(), read.Node, err)
+		}
+	}
+	if calls.Load() != 10 {
+		t.Errorf("expected the stopped replica to be skipped, got %d calls", calls.Load())
+	}
+
+	_ = replicas[1].Stop()
+	if _, err := c.ReadReplicas(ctx, replicas, get); err == nil {
+		t.Error("expected an error when every replica is stopped")
+	}
+	if _, err := c.ReadReplicas(ctx, nil, get); err == nil {
+		t.Error("expected an error without replicas")
+	}
+
+	failing := errors.New("boom")
+	_ = replicas[0].Start(ctx)
+	if _, err := c.ReadReplicas(ctx, replicas[:1], func(context.Context, *node.Node) ([]byte, bool, error) { return nil, false, failing }); !errors.Is(err, failing) {
+		t.Errorf("expected the read error, got %v", err)
+	}
+}
+
+func TestClusterQuorum(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	ctx := context.Background()
+	_ = c.StartAll(ctx)
+	defer func() { _ = c.StopAll() }()
+	c.SetReplicationFactor(3)
+	c.SetConsistency(Consistency{Write: ConsistencyQuorum, Read: ConsistencyQuorum})
+
+	replicas := c.ReplicaSet("key")
+	set := func(ctx context.Context, n *node.Node) error { return n.SetContext(ctx, "key", []byte("new")) }
+	get := func(ctx context.Context, n *node.Node) ([]byte, bool, error) { return n.GetContext(ctx, "key") }
+
+	// 1つのノードが停止しても過半数で読み書きできる
+	_ = replicas[0].Stop()
+	if err := c.WriteReplicas(ctx, replicas, set); err != nil {
+		t.Fatalf("expected the quorum write to succeed, got %v", err)
+	}
+	if read, err := c.ReadReplicas(ctx, replicas, get); err != nil || string(read.Value) != "new" {
+		t.Fatalf("expected the quorum read to succeed, got %q (%v)", read.Value, err)
+	}
+
+	// 書き込みを逃したノードを含んでも、値のある過半数のノードの値を選ぶ
+	_ = replicas[0].Start(ctx)
+	for range 10 {
+		read, err := c.ReadReplicas(ctx, replicas, get)
+		if err != nil || !read.Found || string(read.Value) != "new" {
+			t.Fatalf("expected the quorum read to return the new value, got %q (%v)", read.Value, err)
+		}
+	}
+	_ = replicas[0].Set("key", []byte("old"))
+	c.SetConsistency(Consistency{Write: ConsistencyQuorum, Read: ConsistencyAll})
+	if read, err := c.ReadReplicas(ctx, replicas, get); err != nil || string(read.Value) != "new" {
+		t.Fatalf("expected the value of most replicas, got %q (%v)", read.Value, err)
+	}
+	c.SetConsistency(Consistency{Write: ConsistencyQuorum, Read: ConsistencyQuorum})
+
+	_ = replicas[1].Stop()
+	_ = replicas[2].Stop()
+	if err := c.WriteReplicas(ctx, replicas, set); err == nil {
+		t.Error("expected the quorum write to fail without a majority")
+	}
+	if read, err := c.ReadReplicas(ctx, replicas, get); err == nil || read.Node == nil {
+		t.Errorf("expected the quorum read to fail with the last tried node, got %v (%v)", read.Node, err)
+	}
+}
+
+func TestConsistencyLevel(t *testing.T) {
+	tests := []struct {
+		level    string
+		replicas int
+		want     int
+	}{
+		{"", 3, 1},
+		{"one", 0, 0},
+		{"QUORUM", 3, 2},
+		{"quorum", 4, 3},
+		{"all", 3, 3},
+	}
+	for _, tt := range tests {
+		level, err := ParseConsistencyLevel(tt.level)
+		if err != nil {
+			t.Fatalf("ParseConsistencyLevel(%q) failed: %v", tt.level, err)
+		}
+		if got := level.Required(tt.replicas); got != tt.want {
+			t.Errorf("%q of %d replicas: expected %d, got %d", tt.level, tt.replicas, tt.want, got)
+		}
+	}
+	if _, err := ParseConsistencyLevel("two"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if err := (Consistency{Read: "two"}).Validate(); err == nil {
+		t.Error("expected an invalid read consistency to be rejected")
+	}
+	if s := (Consistency{Write: ConsistencyQuorum}).String(); s != "W=quorum R=one" {
+		t.Errorf("unexpected string: %s", s)
+	}
+}
@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// SetStoreKind は既存のノードと以降に追加するノードのデータの保持方法を設定する
+func (c *Cluster) SetStoreKind(kind node.StoreKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = kind
+	for _, n := range c.nodes {
+		n.SetStoreKind(kind)
+	}
+}
+
+// runningNodes は稼働中のノードを ID 順に返す
+// 停止・一時停止したノードは他のノードから到達できないものとして扱う
+func (c *Cluster) runningNodes() []*node.Node {
+	var nodes []*node.Node
+	for _, n := range c.sortedNodes() {
+		if n.Status() == node.StatusRunning {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Sync は稼働中のノードの状態を互いにマージし（アンチエントロピー）、各ノードで値が変わったキーの数の合計を返す
+// 全てのノードの状態をまとめてから各ノードにマージするため、同期の間に書き込みがなければ稼働中のノードは同じデータになる
+// StoreCRDT でないノードが稼働中の場合はエラーを返す
+func (c *Cluster) Sync() (int, error) {
+	return c.SyncContext(context.Background())
+}
+
+// SyncContext は Sync と同じく稼働中のノードの状態を互いにマージする
+// 遅延を設定した場合は、同期を始めた時点の各ノードの状態をノードの間の遅延の後にそれぞれのノードにマージし、全て届くまで待つ
+// 待機中に ctx がキャンセルされた場合は、それまでに値が変わったキーの数とそのエラーを返す
+func (c *Cluster) SyncContext(ctx context.Context) (int, error) {
+	nodes := c.runningNodes()
+	states := make([]node.CRDTState, len(nodes))
+	for i, n := range nodes {
+		if states[i] = n.State(); states[i] == nil {
+			return 0, fmt.Errorf("node %s does not use the %s store", n.ID(), node.StoreCRDT)
+		}
+	}
+
+	if m := c.LatencyMatrix(); m.Enabled() {
+		return c.deliver(ctx, m, nodes, states)
+	}
+
+	merged := make(node.CRDTState)
+	for _, state := range states {
+		for key, r := range state {
+			if m, ok := merged[key]; ok {
+				m.Merge(r)
+			} else {
+				merged[key] = r
+			}
+		}
+	}
+	changed := 0
+	for _, n := range nodes {
+		count, err := n.Merge(merged)
+		if err != nil {
+			return changed, err
+		}
+		changed += count
+	}
+	return changed, nil
+}
+
+// deliver は各ノードの状態を他のノードに m の遅延の後にマージし、値が変わったキーの数の合計を返す
+// 状態はマージで書き換えないため、複数のノードへの配送で共有する
+func (c *Cluster) deliver(ctx context.Context, m LatencyMatrix, nodes []*node.Node, states []node.CRDTState) (int, error) {
+	clk := c.timeSource()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		changed  int
+		firstErr error
+	)
+	for _, target := range nodes {
+		// 遅延の短い送信元から順に届く
+		sources := make([]int, 0, len(nodes)-1)
+		for i, n := range nodes {
+			if n.ID() != target.ID() {
+				sources = append(sources, i)
+			}
+		}
+		sort.SliceStable(sources, func(a, b int) bool {
+			return m.Between(nodes[sources[a]], target) < m.Between(nodes[sources[b]], target)
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var elapsed time.Duration
+			for _, i := range sources {
+				d := m.Between(nodes[i], target)
+				err := clock.Sleep(ctx, clk, d-elapsed)
+				elapsed = d
+				count := 0
+				if err == nil {
+					count, err = target.Merge(states[i])
+				}
+				mu.Lock()
+				changed += count
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return changed, firstErr
+}
+
+// Divergence は稼働中のノードの間で値が一致しないキー（一部のノードにしかないキーを含む）の数を返す
+// 保持方法にかかわらず計測でき、StoreMap と StoreCRDT の収束の違いを比べられる
+func (c *Cluster) Divergence() int {
+	nodes := c.runningNodes()
+	if len(nodes) < 2 {
+		return 0
+	}
+	data := make([]map[string][]byte, len(nodes))
+	keys := make(map[string]struct{})
+	for i, n := range nodes {
+		data[i] = n.Export()
+		for key := range data[i] {
+			keys[key] = struct{}{}
+		}
+	}
+
+	divergent := 0
+	for key := range keys {
+		first, ok := data[0][key]
+		for _, d := range data[1:] {
+			value, exists := d[key]
+			if exists != ok || !bytes.Equal(value, first) {
+				divergent++
+				break
+			}
+		}
+	}
+	return divergent
+}
@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClusterSetStoreKind(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	c.SetStoreKind(node.StoreCRDT)
+
+	added, err := c.AddNodes(1, "node")
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+	for _, n := range append(c.Nodes(), added...) {
+		if n.StoreKind() != node.StoreCRDT {
+			t.Errorf("expected the %s store on %s, got %s", node.StoreCRDT, n.ID(), n.StoreKind())
+		}
+	}
+}
+
+func TestClusterSync(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(3, "node")
+	c.SetStoreKind(node.StoreCRDT)
+	if err := c.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	defer func() { _ = c.StopAll() }()
+
+	n1, _ := c.GetNode("node-1")
+	n2, _ := c.GetNode("node-2")
+	n3, _ := c.GetNode("node-3")
+	_ = n1.Set("a", []byte("1"))
+	_ = n2.Set("b", []byte("2"))
+	if got := c.Divergence(); got != 2 {
+		t.Errorf("expected 2 divergent keys, got %d", got)
+	}
+
+	// 一時停止したノードは同期に参加しない
+	_ = n3.Suspend()
+	changed, err := c.Sync()
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("expected 2 changed keys, got %d", changed)
+	}
+	if got := c.Divergence(); got != 0 {
+		t.Errorf("expected the running nodes to converge, got %d divergent keys", got)
+	}
+	if n3.Size() != 0 {
+		t.Errorf("expected the suspended node to miss the sync, got %d keys", n3.Size())
+	}
+
+	_ = n3.Resume()
+	if got := c.Divergence(); got != 2 {
+		t.Errorf("expected the resumed node to diverge on 2 keys, got %d", got)
+	}
+	if _, err := c.Sync(); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if value, _ := n3.Get("b"); string(value) != "2" || c.Divergence() != 0 {
+		t.Errorf("expected the resumed node to catch up, got %q", value)
+	}
+}
+
+func TestClusterSyncMapStore(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	n1, _ := c.GetNode("node-1")
+	_ = n1.Set("a", []byte("1"))
+	if _, err := c.Sync(); err == nil {
+		t.Error("expected syncing map stores to fail")
+	}
+	if got := c.Divergence(); got != 1 {
+		t.Errorf("expected 1 divergent key, got %d", got)
+	}
+}
@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// DefaultVirtualNodes はクラスタの Router で1ノードあたりにリング上に置く仮想ノードの数
+const DefaultVirtualNodes = 128
+
+// vnode はリング上の仮想ノード
+type vnode struct {
+	hash uint32
+	node *node.Node
+}
+
+// Router は仮想ノードを使うコンシステントハッシュでキーを担当ノードに振り分ける
+// ノードの追加・削除で担当が変わるキーは、そのノードが担当する分だけになる
+// 作成後は変更しないため、並行して呼び出せる
+type Router struct {
+	ring       []vnode // ハッシュの昇順
+	nodes      []*node.Node
+	generation uint64 // 作成したときのクラスタの世代（Cluster.Router のキャッシュ用）
+}
+
+// NewRouter は nodes をリングに並べた Router を作成する
+// virtualNodes は1ノードあたりの仮想ノードの数（0以下で DefaultVirtualNodes）
+func NewRouter(nodes []*node.Node, virtualNodes int) *Router {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	r := &Router{
+		ring:  make([]vnode, 0, len(nodes)*virtualNodes),
+		nodes: nodes,
+	}
+	for _, n := range nodes {
+		for i := range virtualNodes {
+			r.ring = append(r.ring, vnode{hash: hashKey(n.ID() + "#" + strconv.Itoa(i)), node: n})
+		}
+	}
+	// ハッシュが衝突した場合もノードの順序によらず同じリングになるよう、IDでも並べる
+	sort.Slice(r.ring, func(i, j int) bool {
+		if r.ring[i].hash != r.ring[j].hash {
+			return r.ring[i].hash < r.ring[j].hash
+		}
+		return r.ring[i].node.ID() < r.ring[j].node.ID()
+	})
+	return r
+}
+
+// hashKey はキーとリング上の位置の FNV-1a ハッシュを返す
+func hashKey(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// Owner はキーを担当するノードを返す（ノードがない場合は false）
+// キーのハッシュからリングを時計回りにたどり、最初の仮想ノードのノードが担当する
+func (r *Router) Owner(key string) (*node.Node, bool) {
+	if len(r.ring) == 0 {
+		return nil, false
+	}
+	h := hashKey(key)
+	i := sort.Search(len(r.ring), func(i int) bool { return r.ring[i].hash >= h })
+	if i == len(r.ring) {
+		i = 0
+	}
+	return r.ring[i].node, true
+}
+
+// Replicas はキーを保持する count 個のノードを返す（先頭が担当ノード、ノードが少ない場合はノードの数まで）
+// 担当ノードからリングを時計回りにたどり、まだ選んでいないノードを順に選ぶ
+func (r *Router) Replicas(key string, count int) []*node.Node {
+	count = min(max(count, 1), len(r.nodes))
+	if count == 0 {
+		return nil
+	}
+	h := hashKey(key)
+	start := sort.Search(len(r.ring), func(i int) bool { return r.ring[i].hash >= h })
+	replicas := make([]*node.Node, 0, count)
+	for i := 0; i < len(r.ring) && len(replicas) < count; i++ {
+		n := r.ring[(start+i)%len(r.ring)].node
+		if !slices.Contains(replicas, n) {
+			replicas = append(replicas, n)
+		}
+	}
+	return replicas
+}
+
+// Nodes はリングに並べたノードを返す
+func (r *Router) Nodes() []*node.Node {
+	return r.nodes
+}
+
+// groupKeys はキーを担当ノードごとにまとめる
+func (r *Router) groupKeys(keys []string) map[*node.Node][]string {
+	groups := make(map[*node.Node][]string)
+	for _, key := range keys {
+		n, _ := r.Owner(key)
+		groups[n] = append(groups[n], key)
+	}
+	return groups
+}
+
+// Router は現在のノードを並べた Router を返す
+// ノードの追加・削除までは同じ Router を使い回す
+func (c *Cluster) Router() *Router {
+	generation := c.generation.Load()
+	if r := c.router.Load(); r != nil && r.generation == generation {
+		return r
+	}
+	r := NewRouter(c.sortedNodes(), DefaultVirtualNodes)
+	r.generation = generation
+	c.router.Store(r)
+	return r
+}
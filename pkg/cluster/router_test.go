@@ -0,0 +1,84 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestRouterEmpty(t *testing.T) {
+	if _, ok := NewRouter(nil, 0).Owner("key"); ok {
+		t.Error("expected no owner without nodes")
+	}
+}
+
+func TestRouterSpreadsKeys(t *testing.T) {
+	nodes := []*node.Node{node.New("node-1"), node.New("node-2"), node.New("node-3")}
+	r := NewRouter(nodes, 0)
+
+	counts := make(map[string]int)
+	for i := range 3000 {
+		n, ok := r.Owner(fmt.Sprintf("key-%d", i))
+		if !ok {
+			t.Fatal("expected an owner")
+		}
+		counts[n.ID()]++
+	}
+	for _, n := range nodes {
+		if counts[n.ID()] < 500 {
+			t.Errorf("expected keys to be spread over the nodes, got %v", counts)
+		}
+	}
+
+	// ノードの順序によらず同じ振り分けになる
+	reversed := NewRouter([]*node.Node{nodes[2], nodes[1], nodes[0]}, 0)
+	for i := range 100 {
+		key := fmt.Sprintf("key-%d", i)
+		a, _ := r.Owner(key)
+		b, _ := reversed.Owner(key)
+		if a != b {
+			t.Fatalf("key %s owned by %s and %s", key, a.ID(), b.ID())
+		}
+	}
+}
+
+func TestRouterMovesOnlyNewOwnerKeys(t *testing.T) {
+	nodes := []*node.Node{node.New("node-1"), node.New("node-2"), node.New("node-3")}
+	before := NewRouter(nodes, 0)
+	added := node.New("node-4")
+	after := NewRouter(append(nodes, added), 0)
+
+	moved := 0
+	for i := range 3000 {
+		key := fmt.Sprintf("key-%d", i)
+		a, _ := before.Owner(key)
+		b, _ := after.Owner(key)
+		if a == b {
+			continue
+		}
+		if b != added {
+			t.Fatalf("key %s moved from %s to %s, not to the added node", key, a.ID(), b.ID())
+		}
+		moved++
+	}
+	if moved == 0 || moved > 1500 {
+		t.Errorf("expected about a quarter of the keys to move, got %d of 3000", moved)
+	}
+}
+
+func TestClusterRouter(t *testing.T) {
+	c := New()
+	_ = c.CreateNodes(2, "node")
+
+	r := c.Router()
+	if c.Router() != r {
+		t.Error("expected the router to be reused until the membership changes")
+	}
+	if _, err := c.AddNodes(1, "node"); err != nil {
+		t.Fatal(err)
+	}
+	if latest := c.Router(); latest == r || len(latest.Nodes()) != 3 {
+		t.Errorf("expected a new router with 3 nodes, got %d", len(latest.Nodes()))
+	}
+}
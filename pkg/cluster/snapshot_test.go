@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+func TestClusterSnapshotRoundTrip(t *testing.T) {
+	src := New()
+	if err := src.CreateNodes(2, "node"); err != nil {
+		t.Fatalf("failed to create nodes: %v", err)
+	}
+	src.AssignZones([]string{"a", "b"})
+	if err := src.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	n1, _ := src.GetNode("node-1")
+	_ = n1.Set("k1", []byte{0, 1, 2})
+	n2, _ := src.GetNode("node-2")
+	_ = n2.Set("k2", []byte("v2"))
+
+	var buf bytes.Buffer
+	if err := src.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("expected one line per node, got %d", lines)
+	}
+
+	snaps, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+
+	// node-2 だけのクラスタに復元すると node-1 が作成される
+	dst := New()
+	_ = dst.AddNode(node.New("node-2"))
+	if err := dst.StartAll(context.Background()); err != nil {
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	keys, err := dst.RestoreSnapshot(snaps)
+	if err != nil {
+		t.Fatalf("failed to restore snapshot: %v", err)
+	}
+	if keys != 2 || dst.Size() != 2 {
+		t.Errorf("expected 2 keys on 2 nodes, got %d keys on %d nodes", keys, dst.Size())
+	}
+
+	restored, _ := dst.GetNode("node-1")
+	if restored.Status() != node.StatusRunning {
+		t.Errorf("expected created node to be started, got %v", restored.Status())
+	}
+	if v, ok := restored.Get("k1"); !ok || !bytes.Equal(v, []byte{0, 1, 2}) {
+		t.Errorf("expected binary value to round-trip, got %v", v)
+	}
+	if zone := restored.Label(node.LabelZone); zone != "a" {
+		t.Errorf("expected zone a, got %q", zone)
+	}
+}
+
+func TestReadSnapshotInvalid(t *testing.T) {
+	tests := []string{
+		`{"id":"node-1","data":{}`,
+		`{"data":{}}`,
+		"{\"id\":\"node-1\"}\n{\"id\":\"node-1\"}",
+		`{"id":"node-1","data":{"k":"not base64!"}}`,
+	}
+	for _, input := range tests {
+		if _, err := ReadSnapshot(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+
+	snaps, err := ReadSnapshot(strings.NewReader(""))
+	if err != nil || len(snaps) != 0 {
+		t.Errorf("expected empty snapshot, got %v, %v", snaps, err)
+	}
+}
@@ -0,0 +1,1087 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/autoscale"
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/external"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+	"github.com/nyasuto/chaos-kvs/pkg/toxiproxy"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig は設定ファイルの構造
+type FileConfig struct {
+	Scenario ScenarioConfig `yaml:"scenario" json:"scenario"`
+
+	// Profiles は環境ごとの上書き設定（dev, ci, stress など）
+	// 各プロファイルは scenario と同じスキーマの部分的な値を持つ
+	Profiles map[string]map[string]any `yaml:"profiles" json:"profiles"`
+
+	// Log はログファイルへの出力設定（省略時は標準出力）
+	Log LogConfig `yaml:"log" json:"log"`
+
+	strict bool // 厳格モードで読み込まれたか（プロファイル適用時にも使用）
+}
+
+// ScenarioConfig はシナリオ設定
+type ScenarioConfig struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	Duration    string   `yaml:"duration" json:"duration"`
+	NodeCount   int      `yaml:"node_count" json:"node_count"`
+	Zones       []string `yaml:"zones" json:"zones"`
+
+	// NodeLimits はノードごとのデータの上限（省略時は無制限、超えると最も長く使われていないキーを削除する）
+	NodeLimits NodeLimitsConfig `yaml:"node_limits" json:"node_limits"`
+
+	// NodeAdmission はノードごとに同時に処理する操作の上限（省略時は無制限、超えた操作は過負荷のエラーで失敗させる）
+	NodeAdmission NodeAdmissionConfig `yaml:"node_admission" json:"node_admission"`
+
+	// Store はノードのデータの保持方法（map, crdt。省略時は map）
+	// crdt ではノードごとの書き込みを sync_interval ごとにマージし、結果にノードの間の収束の状況を含める
+	Store        string `yaml:"store" json:"store"`
+	SyncInterval string `yaml:"sync_interval" json:"sync_interval"` // crdt のノードの状態をマージする間隔（省略時は1s、負の値で同期しない）
+
+	// ReplicationFactor はキーを書き込むノードの数（省略時は1で担当ノードのみ、読み取りはそのどれかから行う）
+	ReplicationFactor *int `yaml:"replication_factor" json:"replication_factor"`
+
+	// Consistency はレプリカへの読み書きで応答を待つノードの数（one, quorum, all。省略時は one）
+	Consistency ConsistencyConfig `yaml:"consistency" json:"consistency"`
+
+	// Durability はノードのデータの永続性（省略時は停止してもメモリ上のデータを保持する）
+	Durability DurabilityConfig `yaml:"durability" json:"durability"`
+
+	// Latency はゾーン・ノードの間の通信の遅延（ノードの状態の同期とコーディネーターからのリクエストの転送に加える）
+	Latency LatencyConfig `yaml:"latency" json:"latency"`
+
+	Client   ClientConfig   `yaml:"client" json:"client"`
+	Chaos    ChaosConfig    `yaml:"chaos" json:"chaos"`
+	Recovery RecoveryConfig `yaml:"recovery" json:"recovery"`
+
+	// Autoscale は負荷生成のRPS・P99に合わせてノード数を増減する設定（max_nodes を省略すると増減しない）
+	Autoscale AutoscaleConfig `yaml:"autoscale" json:"autoscale"`
+
+	Notifications []NotificationConfig `yaml:"notifications" json:"notifications"`
+
+	// ReportWarnings はレポートに含める直近の警告ログの件数（0で含めない）
+	ReportWarnings int `yaml:"report_warnings" json:"report_warnings"`
+
+	// Assertions は実行結果が満たすべき条件（満たさない場合 run は終了コード3で終了する）
+	Assertions AssertionsConfig `yaml:"assertions" json:"assertions"`
+
+	// RESP はノードをRESP（Redisプロトコル）で公開する設定（省略時は公開しない）
+	RESP RESPConfig `yaml:"resp" json:"resp"`
+
+	// NodeHTTP はノードごとにHTTPサーバーを立て、負荷生成をループバックのTCP経由にする設定
+	NodeHTTP NodeHTTPConfig `yaml:"node_http" json:"node_http"`
+
+	// NodeProcess はノードごとに子プロセスを起動し、kill 攻撃で実際にプロセスを終了させる設定
+	NodeProcess NodeProcessConfig `yaml:"node_process" json:"node_process"`
+
+	// External はシェルコマンドで操作する外部のプロセスをノードとして使う設定（指定時は node_count を無視する）
+	// コマンドを実行するため、APIのシナリオ開始リクエストでは指定できない
+	External []ExternalConfig `yaml:"external" json:"external"`
+
+	// Toxiproxy はノードへの接続をToxiproxyのプロキシ経由にし、攻撃をソケット層の障害として注入する設定
+	// node_http・node_process・external のいずれかと合わせて使う
+	Toxiproxy ToxiproxyConfig `yaml:"toxiproxy" json:"toxiproxy"`
+
+	// Linearizability は操作の履歴を記録し、終了時に線形化可能性を検査する設定
+	Linearizability LinearizabilityConfig `yaml:"linearizability" json:"linearizability"`
+
+	// Tracing はシナリオの段階・攻撃・復旧・サンプリングしたリクエストをトレースとしてOTLPで送る設定
+	Tracing TracingConfig `yaml:"tracing" json:"tracing"`
+
+	// Steps は負荷の開始後に順に実行するステップ（負荷・待機・障害の注入と除去・条件の判定）
+	// duration を省略した場合は全てのステップを終えた時点で終了する
+	Steps []StepConfig `yaml:"steps" json:"steps"`
+}
+
+// NodeLimitsConfig はノードごとのデータの上限の設定（0の項目は制限しない）
+type NodeLimitsConfig struct {
+	MaxKeys  int   `yaml:"max_keys" json:"max_keys"`   // キー数の上限
+	MaxBytes int64 `yaml:"max_bytes" json:"max_bytes"` // キーと値の合計バイト数の上限
+
+	// MaxMemory はメモリ使用量の概算の上限（超える書き込みはキーを削除せずに失敗させる）
+	MaxMemory int64 `yaml:"max_memory" json:"max_memory"`
+}
+
+// NodeAdmissionConfig はノードごとに同時に処理する操作の上限の設定（0で制限しない）
+type NodeAdmissionConfig struct {
+	MaxInFlight   int `yaml:"max_in_flight" json:"max_in_flight"`   // 同時に処理する操作の上限
+	ReservedReads int `yaml:"reserved_reads" json:"reserved_reads"` // max_in_flight のうち読み取りだけに使う枠
+}
+
+// DurabilityConfig はノードのデータの永続性の設定
+type DurabilityConfig struct {
+	Mode     string `yaml:"mode" json:"mode"`         // memory, ephemeral, snapshot, wal（省略時は memory）
+	Dir      string `yaml:"dir" json:"dir"`           // snapshot のスナップショット・wal のログを書き出すディレクトリ（省略時は一時ディレクトリ、APIのリクエストでは指定できない）
+	Interval string `yaml:"interval" json:"interval"` // snapshot のスナップショット・wal のログを書き出す間隔（省略時は1s）
+	Sync     string `yaml:"sync" json:"sync"`         // wal のログを書き出す頻度（interval, always, none。省略時は interval）
+
+	// LossRatio は memory のノードが再起動のたびにランダムに失うキーの割合（0〜1、省略時は0で全て保持する）
+	LossRatio *float64 `yaml:"loss_ratio" json:"loss_ratio"`
+}
+
+// ConsistencyConfig は書き込み（W）と読み取り（R）で応答を待つノードの数の設定
+type ConsistencyConfig struct {
+	Write string `yaml:"write" json:"write"` // one, quorum, all（省略時は one）
+	Read  string `yaml:"read" json:"read"`   // one, quorum, all（省略時は one）
+}
+
+// LatencyConfig はゾーン・ノードの間の通信の遅延の設定（省略した項目は遅延なし）
+type LatencyConfig struct {
+	IntraZone   string              `yaml:"intra_zone" json:"intra_zone"`   // 同じゾーンのノードの間（例: 1ms）
+	InterZone   string              `yaml:"inter_zone" json:"inter_zone"`   // 異なるゾーンのノードの間（例: 80ms）
+	Links       []LatencyLinkConfig `yaml:"links" json:"links"`             // 特定のゾーン・ノードの組の遅延（向きによらない）
+	Coordinator string              `yaml:"coordinator" json:"coordinator"` // 負荷生成のリクエストを受け付けるゾーンまたはノード
+}
+
+// LatencyLinkConfig は2つのゾーンまたはノードの間の遅延の設定
+type LatencyLinkConfig struct {
+	From  string `yaml:"from" json:"from"`
+	To    string `yaml:"to" json:"to"`
+	Delay string `yaml:"delay" json:"delay"`
+}
+
+// toLatencyMatrix はゾーン・ノードの間の遅延の設定を変換する
+func (c LatencyConfig) toLatencyMatrix() (cluster.LatencyMatrix, error) {
+	m := cluster.LatencyMatrix{Coordinator: c.Coordinator}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"latency.intra_zone", c.IntraZone, &m.IntraZone},
+		{"latency.inter_zone", c.InterZone, &m.InterZone},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return m, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		*d.dst = v
+	}
+	for i, l := range c.Links {
+		delay, err := time.ParseDuration(l.Delay)
+		if err != nil {
+			return m, fmt.Errorf("invalid latency.links[%d].delay: %w", i, err)
+		}
+		m.Links = append(m.Links, cluster.Link{From: l.From, To: l.To, Delay: delay})
+	}
+	if err := m.Validate(); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// RESPConfig はRESP（Redisプロトコル）リスナーの設定
+type RESPConfig struct {
+	Addr     string `yaml:"addr" json:"addr"`           // クラスタ全体のリスナーのアドレス（例: :6379）
+	NodePort int    `yaml:"node_port" json:"node_port"` // ノードごとのリスナーの開始ポート（node-N は node_port+N-1）
+}
+
+// NodeHTTPConfig はノードごとのHTTPサーバーの設定
+type NodeHTTPConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	Port    int  `yaml:"port" json:"port"` // 開始ポート（node-N は port+N-1、0で空いているポート）
+}
+
+// NodeProcessConfig はノードの子プロセスの設定
+type NodeProcessConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Command は子プロセスのコマンドと引数（--id・--addr を追加して実行する、省略時は chaos-kvs node-server）
+	// コマンドを実行するため、APIのシナリオ開始リクエストでは指定できない
+	Command []string `yaml:"command" json:"command"`
+}
+
+// ToxiproxyConfig はToxiproxyの設定（url を省略すると使わない）
+type ToxiproxyConfig struct {
+	URL       string `yaml:"url" json:"url"`             // APIのURL（例: http://127.0.0.1:8474）
+	Listen    string `yaml:"listen" json:"listen"`       // プロキシが待ち受けるホスト（省略時は127.0.0.1）
+	Bandwidth int    `yaml:"bandwidth" json:"bandwidth"` // delay 攻撃で合わせてかける帯域の上限（KB/秒、0で制限しない）
+	Timeout   string `yaml:"timeout" json:"timeout"`     // 1つのAPIリクエストの上限時間（省略時は5s）
+}
+
+// TracingConfig はトレースの送信先の設定（endpoint を省略すると送らない）
+type TracingConfig struct {
+	Endpoint    string            `yaml:"endpoint" json:"endpoint"`         // OTLP/HTTPの受信先（例: http://127.0.0.1:4318）
+	ServiceName string            `yaml:"service_name" json:"service_name"` // リソース属性 service.name（省略時は chaos-kvs）
+	SampleRate  float64           `yaml:"sample_rate" json:"sample_rate"`   // トレースするリクエストの割合（0で0.01）
+	Headers     map[string]string `yaml:"headers" json:"headers"`           // リクエストに追加するヘッダー（認証など）
+}
+
+// AutoscaleConfig は負荷に合わせてノード数を増減する設定（省略した項目は autoscale.DefaultConfig の値）
+type AutoscaleConfig struct {
+	MinNodes     int     `yaml:"min_nodes" json:"min_nodes"`           // ノード数の下限（省略時は1）
+	MaxNodes     int     `yaml:"max_nodes" json:"max_nodes"`           // ノード数の上限
+	Step         int     `yaml:"step" json:"step"`                     // 1回に増減するノード数（省略時は1）
+	ScaleUpRPS   float64 `yaml:"scale_up_rps" json:"scale_up_rps"`     // 1ノードあたりのRPSがこれを超えたら増やす（省略時は1000）
+	ScaleDownRPS float64 `yaml:"scale_down_rps" json:"scale_down_rps"` // 1ノードあたりのRPSがこれを下回ったら減らす（省略時は200）
+	ScaleUpP99   string  `yaml:"scale_up_p99" json:"scale_up_p99"`     // P99がこれを超えたら増やす（省略時は50ms、0sで使わない）
+	Interval     string  `yaml:"interval" json:"interval"`             // 負荷を評価する区間（省略時は2s）
+	Cooldown     string  `yaml:"cooldown" json:"cooldown"`             // 増減した後、次に増減するまで待つ時間（省略時は5s）
+}
+
+// LinearizabilityConfig は線形化可能性の検査の設定
+type LinearizabilityConfig struct {
+	Enabled       bool `yaml:"enabled" json:"enabled"`
+	MaxOperations int  `yaml:"max_operations" json:"max_operations"` // 記録する操作の上限（0で100000）
+}
+
+// ExternalConfig はシェルコマンドで操作する外部のプロセス（Redis のコンテナなど）の設定
+// コマンドは sh -c で実行し、環境変数 CHAOS_KVS_NODE・CHAOS_KVS_ADDR・CHAOS_KVS_DELAY_MS を渡す
+type ExternalConfig struct {
+	ID      string `yaml:"id" json:"id"`           // ノードID
+	Addr    string `yaml:"addr" json:"addr"`       // RESPで接続するアドレス（例: 127.0.0.1:6379）
+	Start   string `yaml:"start" json:"start"`     // 起動（省略時は外部で起動済みとみなす）
+	Stop    string `yaml:"stop" json:"stop"`       // 強制停止（kill 攻撃）
+	Suspend string `yaml:"suspend" json:"suspend"` // 一時停止（suspend 攻撃）
+	Resume  string `yaml:"resume" json:"resume"`   // 再開
+	Delay   string `yaml:"delay" json:"delay"`     // 遅延の注入（CHAOS_KVS_DELAY_MS が0で解除）
+	Timeout string `yaml:"timeout" json:"timeout"` // 1つのコマンドの上限時間（省略時は30s）
+}
+
+// ClientConfig はクライアント設定
+type ClientConfig struct {
+	Workers       int     `yaml:"workers" json:"workers"`
+	WriteRatio    float64 `yaml:"write_ratio" json:"write_ratio"`
+	KeySampleRate float64 `yaml:"key_sample_rate" json:"key_sample_rate"` // キーごとのアクセス数を記録するリクエストの割合（0で記録しない）
+	HotKeys       int     `yaml:"hot_keys" json:"hot_keys"`               // レポートに含めるアクセス数の多いキーの数（0でデフォルト）
+	TargetRPS     float64 `yaml:"target_rps" json:"target_rps"`           // 秒間リクエスト数の目標（0で上限なし）
+	BatchSize     int     `yaml:"batch_size" json:"batch_size"`           // 1リクエストでまとめて読み書きするキーの数（0・1で1キーずつ）
+	ScanRatio     float64 `yaml:"scan_ratio" json:"scan_ratio"`           // キーの前方一致のスキャンとして送るリクエストの割合（0で送らない）
+	ScanLimit     int     `yaml:"scan_limit" json:"scan_limit"`           // 1回のスキャンで読み取るキーの上限（0で100）
+
+	// Namespaces はリクエストを振り分ける名前空間（省略時は名前空間を付けない）
+	Namespaces []string `yaml:"namespaces" json:"namespaces"`
+}
+
+// ChaosConfig はカオス設定（enabled を省略するとベースの設定を維持する）
+type ChaosConfig struct {
+	Enabled     *bool    `yaml:"enabled" json:"enabled"`
+	Interval    string   `yaml:"interval" json:"interval"`
+	Targets     int      `yaml:"targets" json:"targets"`
+	AttackTypes []string `yaml:"attack_types" json:"attack_types"`
+	SuspendTime string   `yaml:"suspend_time" json:"suspend_time"`
+	DelayAmount string   `yaml:"delay_amount" json:"delay_amount"`
+}
+
+// RecoveryConfig は復旧設定（enabled を省略するとベースの設定を維持する）
+type RecoveryConfig struct {
+	Enabled    *bool  `yaml:"enabled" json:"enabled"`
+	Delay      string `yaml:"delay" json:"delay"`
+	MaxRetries int    `yaml:"max_retries" json:"max_retries"`
+}
+
+// AssertionsConfig は実行結果が満たすべき条件（省略・0の項目は判定しない）
+type AssertionsConfig struct {
+	MaxErrorRate  float64 `yaml:"max_error_rate" json:"max_error_rate"`   // エラー率の上限（0.01で1%）
+	MaxAvgLatency string  `yaml:"max_avg_latency" json:"max_avg_latency"` // 平均レイテンシの上限（例: 5ms）
+	MaxP99Latency string  `yaml:"max_p99_latency" json:"max_p99_latency"` // P99レイテンシの上限（例: 50ms）
+	MinThroughput float64 `yaml:"min_throughput" json:"min_throughput"`   // スループット（秒間リクエスト数）の下限
+}
+
+// ApplyTo は指定された項目で base を上書きした条件を返す
+func (c AssertionsConfig) ApplyTo(base scenario.Assertions) (scenario.Assertions, error) {
+	if c.MaxErrorRate > 0 {
+		base.MaxErrorRate = c.MaxErrorRate
+	}
+	if c.MaxAvgLatency != "" {
+		d, err := time.ParseDuration(c.MaxAvgLatency)
+		if err != nil {
+			return base, fmt.Errorf("invalid assertions.max_avg_latency: %w", err)
+		}
+		base.MaxAvgLatency = d
+	}
+	if c.MaxP99Latency != "" {
+		d, err := time.ParseDuration(c.MaxP99Latency)
+		if err != nil {
+			return base, fmt.Errorf("invalid assertions.max_p99_latency: %w", err)
+		}
+		base.MaxP99Latency = d
+	}
+	if c.MinThroughput > 0 {
+		base.MinThroughput = c.MinThroughput
+	}
+	return base, nil
+}
+
+// NotificationConfig はSlack・Discordへの通知設定
+type NotificationConfig struct {
+	Type     string   `yaml:"type" json:"type"`         // slack または discord
+	URL      string   `yaml:"url" json:"url"`           // Incoming Webhook のURL
+	Events   []string `yaml:"events" json:"events"`     // 通知するイベント種別（省略時は開始・終了・SLO違反）
+	Template string   `yaml:"template" json:"template"` // メッセージの text/template（省略可）
+}
+
+// LogConfig はログファイルの出力とローテーションの設定
+type LogConfig struct {
+	File           string `yaml:"file" json:"file"`                       // 出力先のファイル
+	MaxSizeMB      int    `yaml:"max_size_mb" json:"max_size_mb"`         // ローテーションするサイズ（MB）
+	RotateInterval string `yaml:"rotate_interval" json:"rotate_interval"` // ローテーションする間隔（例: 24h）
+	MaxBackups     int    `yaml:"max_backups" json:"max_backups"`         // 残すローテーション済みファイル数
+	MaxAge         string `yaml:"max_age" json:"max_age"`                 // ローテーション済みファイルの保持期間（例: 168h）
+}
+
+// ApplyTo は指定された項目で base を上書きしたログファイル設定を返す
+func (c LogConfig) ApplyTo(base logger.FileConfig) (logger.FileConfig, error) {
+	if c.File != "" {
+		base.Path = c.File
+	}
+	if c.MaxSizeMB > 0 {
+		base.MaxSize = int64(c.MaxSizeMB) << 20
+	}
+	if c.MaxBackups > 0 {
+		base.MaxBackups = c.MaxBackups
+	}
+	if c.RotateInterval != "" {
+		d, err := time.ParseDuration(c.RotateInterval)
+		if err != nil {
+			return base, fmt.Errorf("invalid log.rotate_interval: %w", err)
+		}
+		base.RotateInterval = d
+	}
+	if c.MaxAge != "" {
+		d, err := time.ParseDuration(c.MaxAge)
+		if err != nil {
+			return base, fmt.Errorf("invalid log.max_age: %w", err)
+		}
+		base.MaxAge = d
+	}
+	return base, nil
+}
+
+// LoadOptions は設定読み込みのオプション
+type LoadOptions struct {
+	Strict   bool          // 未知のフィールドをエラーにする
+	Checksum string        // リモート設定の期待するSHA-256チェックサム（16進数、空で検証なし）
+	CacheDir string        // リモート設定のETagキャッシュの保存先（空でキャッシュなし）
+	Timeout  time.Duration // リモート設定取得のHTTPタイムアウト
+}
+
+// DefaultLoadOptions はデフォルト設定を返す
+func DefaultLoadOptions() LoadOptions {
+	return LoadOptions{
+		Strict:  false,
+		Timeout: 10 * time.Second,
+	}
+}
+
+// LoadFile は設定ファイルを読み込む
+func LoadFile(path string) (*FileConfig, error) {
+	return LoadFileWithOptions(path, DefaultLoadOptions())
+}
+
+// LoadFileWithOptions はオプションを指定して設定ファイルを読み込む
+func LoadFileWithOptions(path string, opts LoadOptions) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return parse(data, strings.ToLower(filepath.Ext(path)), opts.Strict)
+}
+
+// parse は拡張子に応じて設定データをパースする
+// strict が true の場合、未知のフィールドを含む設定はエラーになる
+func parse(data []byte, ext string, strict bool) (*FileConfig, error) {
+	config := FileConfig{strict: strict}
+
+	switch ext {
+	case ".yaml", ".yml":
+		if err := decodeYAML(data, &config, strict); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if strict {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", ext)
+	}
+
+	return &config, nil
+}
+
+// decodeYAML はYAMLをデコードする（空のドキュメントはエラーにしない）
+func decodeYAML(data []byte, out any, strict bool) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(strict)
+	if err := dec.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// ProfileNames は定義されているプロファイル名をソートして返す
+func (f *FileConfig) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile は指定されたプロファイルの値をベース設定に上書きする
+// プロファイルに含まれないフィールドはベース設定の値が維持される
+func (f *FileConfig) ApplyProfile(name string) error {
+	profile, ok := f.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile: %s (available: %v)", name, f.ProfileNames())
+	}
+
+	data, err := yaml.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to encode profile %s: %w", name, err)
+	}
+	if err := decodeYAML(data, &f.Scenario, f.strict); err != nil {
+		return fmt.Errorf("failed to apply profile %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ToScenarioConfig はFileConfigをscenario.Configに変換する
+func (f *FileConfig) ToScenarioConfig() (scenario.Config, error) {
+	return f.ApplyTo(scenario.DefaultConfig())
+}
+
+// ApplyTo はベースとなるシナリオ設定にファイルの値を上書きする
+// 未指定（ゼロ値・nil）のフィールドはベースの値が維持される
+func (f *FileConfig) ApplyTo(base scenario.Config) (scenario.Config, error) {
+	sc := f.Scenario
+	config := base
+
+	if sc.Name != "" {
+		config.Name = sc.Name
+	}
+	if sc.Description != "" {
+		config.Description = sc.Description
+	}
+	if sc.Duration != "" {
+		d, err := time.ParseDuration(sc.Duration)
+		if err != nil {
+			return config, fmt.Errorf("invalid duration: %w", err)
+		}
+		config.Duration = d
+	}
+	if sc.NodeCount > 0 {
+		config.NodeCount = sc.NodeCount
+	}
+	if len(sc.Zones) > 0 {
+		config.Zones = sc.Zones
+	}
+	if sc.NodeLimits.MaxKeys > 0 {
+		config.NodeLimits.MaxKeys = sc.NodeLimits.MaxKeys
+	}
+	if sc.NodeLimits.MaxBytes > 0 {
+		config.NodeLimits.MaxBytes = sc.NodeLimits.MaxBytes
+	}
+	if sc.NodeLimits.MaxMemory > 0 {
+		config.NodeLimits.MaxMemory = sc.NodeLimits.MaxMemory
+	}
+	if sc.NodeAdmission.MaxInFlight > 0 {
+		config.NodeAdmission = node.Admission{
+			MaxInFlight:   sc.NodeAdmission.MaxInFlight,
+			ReservedReads: sc.NodeAdmission.ReservedReads,
+		}
+	}
+	if sc.Store != "" {
+		store, err := node.ParseStoreKind(sc.Store)
+		if err != nil {
+			return config, fmt.Errorf("invalid store: %w", err)
+		}
+		config.Store = store
+	}
+	if sc.ReplicationFactor != nil {
+		config.ReplicationFactor = *sc.ReplicationFactor
+	}
+	if sc.Consistency.Write != "" {
+		write, err := cluster.ParseConsistencyLevel(sc.Consistency.Write)
+		if err != nil {
+			return config, fmt.Errorf("invalid consistency.write: %w", err)
+		}
+		config.Consistency.Write = write
+	}
+	if sc.Consistency.Read != "" {
+		read, err := cluster.ParseConsistencyLevel(sc.Consistency.Read)
+		if err != nil {
+			return config, fmt.Errorf("invalid consistency.read: %w", err)
+		}
+		config.Consistency.Read = read
+	}
+	if sc.SyncInterval != "" {
+		d, err := time.ParseDuration(sc.SyncInterval)
+		if err != nil {
+			return config, fmt.Errorf("invalid sync_interval: %w", err)
+		}
+		config.SyncInterval = d
+	}
+	if sc.Durability.Mode != "" {
+		mode, err := node.ParseDurabilityMode(sc.Durability.Mode)
+		if err != nil {
+			return config, fmt.Errorf("invalid durability.mode: %w", err)
+		}
+		sync, err := node.ParseWALSync(sc.Durability.Sync)
+		if err != nil {
+			return config, fmt.Errorf("invalid durability.sync: %w", err)
+		}
+		config.Durability = node.Durability{Mode: mode, Path: sc.Durability.Dir, Sync: sync}
+	}
+	if sc.Durability.Interval != "" {
+		d, err := time.ParseDuration(sc.Durability.Interval)
+		if err != nil {
+			return config, fmt.Errorf("invalid durability.interval: %w", err)
+		}
+		config.Durability.Interval = d
+	}
+	if sc.Durability.LossRatio != nil {
+		config.Durability.EphemeralLossRatio = *sc.Durability.LossRatio
+	}
+	latency, err := sc.Latency.toLatencyMatrix()
+	if err != nil {
+		return config, err
+	}
+	if latency.Enabled() || latency.Coordinator != "" {
+		config.Latency = latency
+	}
+
+	// Client設定
+	if sc.Client.Workers > 0 {
+		config.ClientWorkers = sc.Client.Workers
+	}
+	if sc.Client.WriteRatio > 0 {
+		config.WriteRatio = sc.Client.WriteRatio
+	}
+	if sc.Client.KeySampleRate > 0 {
+		config.KeySampleRate = sc.Client.KeySampleRate
+	}
+	if sc.Client.HotKeys > 0 {
+		config.HotKeys = sc.Client.HotKeys
+	}
+	if sc.Client.TargetRPS > 0 {
+		config.TargetRPS = sc.Client.TargetRPS
+	}
+	if sc.Client.BatchSize > 0 {
+		config.BatchSize = sc.Client.BatchSize
+	}
+	if sc.Client.ScanRatio > 0 {
+		config.ScanRatio = sc.Client.ScanRatio
+	}
+	if sc.Client.ScanLimit > 0 {
+		config.ScanLimit = sc.Client.ScanLimit
+	}
+	if sc.Client.Namespaces != nil {
+		config.Namespaces = sc.Client.Namespaces
+	}
+
+	// Chaos設定
+	if sc.Chaos.Enabled != nil {
+		config.EnableChaos = *sc.Chaos.Enabled
+	}
+	if sc.Chaos.Interval != "" {
+		d, err := time.ParseDuration(sc.Chaos.Interval)
+		if err != nil {
+			return config, fmt.Errorf("invalid chaos interval: %w", err)
+		}
+		config.ChaosInterval = d
+	}
+	if sc.Chaos.Targets > 0 {
+		config.ChaosTargets = sc.Chaos.Targets
+	}
+	if len(sc.Chaos.AttackTypes) > 0 {
+		attacks, err := parseAttackTypes(sc.Chaos.AttackTypes)
+		if err != nil {
+			return config, err
+		}
+		config.AttackTypes = attacks
+	}
+
+	// Recovery設定
+	if sc.Recovery.Enabled != nil {
+		config.EnableRecovery = *sc.Recovery.Enabled
+	}
+	if sc.Recovery.Delay != "" {
+		d, err := time.ParseDuration(sc.Recovery.Delay)
+		if err != nil {
+			return config, fmt.Errorf("invalid recovery delay: %w", err)
+		}
+		config.RecoveryDelay = d
+	}
+	if sc.Recovery.MaxRetries > 0 {
+		config.MaxRetries = sc.Recovery.MaxRetries
+	}
+
+	// 通知設定
+	if len(sc.Notifications) > 0 {
+		notifiers, err := parseNotifications(sc.Notifications)
+		if err != nil {
+			return config, err
+		}
+		config.Notifiers = notifiers
+	}
+	if sc.ReportWarnings > 0 {
+		config.ReportWarnings = sc.ReportWarnings
+	}
+
+	// 実行結果の条件
+	assertions, err := sc.Assertions.ApplyTo(config.Assertions)
+	if err != nil {
+		return config, err
+	}
+	config.Assertions = assertions
+
+	// RESPリスナー
+	if sc.RESP.Addr != "" {
+		config.RESPAddr = sc.RESP.Addr
+	}
+	if sc.RESP.NodePort > 0 {
+		config.RESPNodePort = sc.RESP.NodePort
+	}
+
+	// 外部のプロセス
+	if len(sc.External) > 0 {
+		externals, err := parseExternal(sc.External)
+		if err != nil {
+			return config, err
+		}
+		config.External = externals
+	}
+
+	// ノードごとのHTTPサーバー
+	if sc.NodeHTTP.Enabled {
+		config.NodeHTTP = true
+		config.NodeHTTPPort = sc.NodeHTTP.Port
+	}
+
+	// ノードの子プロセス
+	if sc.NodeProcess.Enabled {
+		config.NodeProcess = true
+		config.NodeCommand = sc.NodeProcess.Command
+	}
+
+	// Toxiproxy
+	if sc.Toxiproxy.URL != "" {
+		proxies, err := parseToxiproxy(sc.Toxiproxy)
+		if err != nil {
+			return config, err
+		}
+		config.Toxiproxy = proxies
+	}
+
+	// 線形化可能性の検査
+	if sc.Linearizability.Enabled {
+		config.Linearizability = true
+		config.HistoryLimit = sc.Linearizability.MaxOperations
+	}
+
+	// ノード数の増減
+	if sc.Autoscale.MaxNodes > 0 {
+		autoscaling, err := parseAutoscale(sc.Autoscale)
+		if err != nil {
+			return config, err
+		}
+		config.Autoscale = autoscaling
+	}
+
+	// トレース
+	if sc.Tracing.Endpoint != "" {
+		traces, err := parseTracing(sc.Tracing)
+		if err != nil {
+			return config, err
+		}
+		config.Tracing = traces
+	}
+
+	// ステップ
+	if len(sc.Steps) > 0 {
+		steps, err := parseSteps(sc.Steps)
+		if err != nil {
+			return config, err
+		}
+		config.Steps = steps
+		if sc.Duration == "" {
+			config.Duration = 0 // 全てのステップを終えるまで実行する
+		}
+	}
+
+	return config, nil
+}
+
+// parseAttackTypes は文字列の攻撃タイプをパースする
+func parseAttackTypes(types []string) ([]chaos.AttackType, error) {
+	var attacks []chaos.AttackType
+
+	for _, t := range types {
+		switch strings.ToLower(t) {
+		case "kill":
+			attacks = append(attacks, chaos.AttackKill)
+		case "suspend":
+			attacks = append(attacks, chaos.AttackSuspend)
+		case "delay":
+			attacks = append(attacks, chaos.AttackDelay)
+		case "readonly":
+			attacks = append(attacks, chaos.AttackReadOnly)
+		case "corrupt":
+			attacks = append(attacks, chaos.AttackCorrupt)
+		default:
+			return nil, fmt.Errorf("unknown attack type: %s", t)
+		}
+	}
+
+	return attacks, nil
+}
+
+// parseNotifications は通知設定を通知先の設定に変換する
+func parseNotifications(notifications []NotificationConfig) ([]events.NotifierConfig, error) {
+	notifiers := make([]events.NotifierConfig, 0, len(notifications))
+	for i, n := range notifications {
+		notifier := events.NotifierConfig{
+			Kind:     events.NotifierKind(strings.ToLower(n.Type)),
+			URL:      n.URL,
+			Template: n.Template,
+		}
+		for _, t := range n.Events {
+			notifier.Types = append(notifier.Types, events.EventType(t))
+		}
+		if err := notifier.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid notifications[%d]: %w", i, err)
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
+
+// parseExternal は外部のプロセスの設定を変換する
+func parseExternal(list []ExternalConfig) ([]external.Config, error) {
+	seen := make(map[string]bool, len(list))
+	externals := make([]external.Config, 0, len(list))
+	for i, e := range list {
+		if e.ID == "" {
+			return nil, fmt.Errorf("external[%d].id is required", i)
+		}
+		if seen[e.ID] {
+			return nil, fmt.Errorf("external[%d].id %q is duplicated", i, e.ID)
+		}
+		seen[e.ID] = true
+		if _, _, err := net.SplitHostPort(e.Addr); err != nil {
+			return nil, fmt.Errorf("external[%d].addr must be host:port: %w", i, err)
+		}
+		// 止めたプロセスを戻せないと復旧も終了時の復元もできないため、対になるコマンドを必須にする
+		if e.Stop != "" && e.Start == "" {
+			return nil, fmt.Errorf("external[%d].start is required when stop is set", i)
+		}
+		if e.Suspend != "" && e.Resume == "" {
+			return nil, fmt.Errorf("external[%d].resume is required when suspend is set", i)
+		}
+
+		config := external.DefaultConfig()
+		config.ID = e.ID
+		config.Addr = e.Addr
+		config.Commands = external.Commands{
+			Start:   e.Start,
+			Stop:    e.Stop,
+			Suspend: e.Suspend,
+			Resume:  e.Resume,
+			Delay:   e.Delay,
+		}
+		if e.Timeout != "" {
+			d, err := time.ParseDuration(e.Timeout)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("invalid external[%d].timeout: %q", i, e.Timeout)
+			}
+			config.Timeout = d
+		}
+		externals = append(externals, config)
+	}
+	return externals, nil
+}
+
+// parseToxiproxy はToxiproxyの設定を変換する
+func parseToxiproxy(c ToxiproxyConfig) (toxiproxy.Config, error) {
+	config := toxiproxy.DefaultConfig()
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return config, fmt.Errorf("toxiproxy.url must be an http(s) URL: %q", c.URL)
+	}
+	config.URL = c.URL
+	if c.Listen != "" {
+		config.Listen = c.Listen
+	}
+	if c.Bandwidth < 0 {
+		return config, fmt.Errorf("toxiproxy.bandwidth must be non-negative")
+	}
+	config.Bandwidth = c.Bandwidth
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil || d < 0 {
+			return config, fmt.Errorf("invalid toxiproxy.timeout: %q", c.Timeout)
+		}
+		config.Timeout = d
+	}
+	return config, nil
+}
+
+// parseAutoscale はノード数の増減の設定を変換する
+func parseAutoscale(c AutoscaleConfig) (autoscale.Config, error) {
+	config := autoscale.DefaultConfig()
+	config.MaxNodes = c.MaxNodes
+	if c.MinNodes > 0 {
+		config.MinNodes = c.MinNodes
+	}
+	if c.Step > 0 {
+		config.Step = c.Step
+	}
+	if c.ScaleUpRPS > 0 {
+		config.ScaleUpRPS = c.ScaleUpRPS
+	}
+	if c.ScaleDownRPS > 0 {
+		config.ScaleDownRPS = c.ScaleDownRPS
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"autoscale.scale_up_p99", c.ScaleUpP99, &config.ScaleUpP99},
+		{"autoscale.interval", c.Interval, &config.Interval},
+		{"autoscale.cooldown", c.Cooldown, &config.Cooldown},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		*d.dst = v
+	}
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// parseTracing はトレースの設定を変換する
+func parseTracing(c TracingConfig) (tracing.Config, error) {
+	config := tracing.DefaultConfig()
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return config, fmt.Errorf("tracing.endpoint must be an http(s) URL: %q", c.Endpoint)
+	}
+	config.Endpoint = c.Endpoint
+	if c.ServiceName != "" {
+		config.ServiceName = c.ServiceName
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return config, fmt.Errorf("tracing.sample_rate must be between 0 and 1")
+	}
+	if c.SampleRate > 0 {
+		config.SampleRate = c.SampleRate
+	}
+	config.Headers = c.Headers
+	return config, nil
+}
+
+// Validate は設定を検証する
+func (f *FileConfig) Validate() error {
+	sc := f.Scenario
+
+	if sc.NodeCount < 0 {
+		return fmt.Errorf("node_count must be non-negative")
+	}
+
+	if l := sc.NodeLimits; l.MaxKeys < 0 || l.MaxBytes < 0 || l.MaxMemory < 0 {
+		return fmt.Errorf("node_limits must be non-negative")
+	}
+
+	if a := sc.NodeAdmission; a.MaxInFlight < 0 || a.ReservedReads < 0 {
+		return fmt.Errorf("node_admission must be non-negative")
+	} else if a.ReservedReads > 0 && a.ReservedReads >= a.MaxInFlight {
+		return fmt.Errorf("node_admission.reserved_reads must be less than max_in_flight")
+	}
+
+	if _, err := node.ParseStoreKind(sc.Store); err != nil {
+		return fmt.Errorf("store must be map or crdt: %w", err)
+	}
+
+	if sc.ReplicationFactor != nil && *sc.ReplicationFactor < 0 {
+		return fmt.Errorf("replication_factor must be non-negative")
+	}
+	if _, err := cluster.ParseConsistencyLevel(sc.Consistency.Write); err != nil {
+		return fmt.Errorf("consistency.write must be one, quorum or all: %w", err)
+	}
+	if _, err := cluster.ParseConsistencyLevel(sc.Consistency.Read); err != nil {
+		return fmt.Errorf("consistency.read must be one, quorum or all: %w", err)
+	}
+
+	if _, err := node.ParseDurabilityMode(sc.Durability.Mode); err != nil {
+		return fmt.Errorf("durability.mode must be memory, ephemeral, snapshot or wal: %w", err)
+	}
+	if _, err := node.ParseWALSync(sc.Durability.Sync); err != nil {
+		return fmt.Errorf("durability.sync must be interval, always or none: %w", err)
+	}
+	if r := sc.Durability.LossRatio; r != nil && (*r < 0 || *r > 1) {
+		return fmt.Errorf("durability.loss_ratio must be between 0 and 1")
+	}
+
+	if sc.Client.Workers < 0 {
+		return fmt.Errorf("client.workers must be non-negative")
+	}
+
+	if sc.Client.WriteRatio < 0 || sc.Client.WriteRatio > 1 {
+		return fmt.Errorf("client.write_ratio must be between 0 and 1")
+	}
+
+	if sc.Client.KeySampleRate < 0 || sc.Client.KeySampleRate > 1 {
+		return fmt.Errorf("client.key_sample_rate must be between 0 and 1")
+	}
+
+	if sc.Client.HotKeys < 0 {
+		return fmt.Errorf("client.hot_keys must be non-negative")
+	}
+
+	if sc.Client.TargetRPS < 0 {
+		return fmt.Errorf("client.target_rps must be non-negative")
+	}
+
+	if sc.Client.BatchSize < 0 {
+		return fmt.Errorf("client.batch_size must be non-negative")
+	}
+
+	if sc.Client.ScanRatio < 0 || sc.Client.ScanRatio > 1 {
+		return fmt.Errorf("client.scan_ratio must be between 0 and 1")
+	}
+
+	if sc.Client.ScanLimit < 0 {
+		return fmt.Errorf("client.scan_limit must be non-negative")
+	}
+
+	for _, ns := range sc.Client.Namespaces {
+		if ns == "" || strings.Contains(ns, node.NamespaceSeparator) {
+			return fmt.Errorf("client.namespaces must be non-empty and must not contain %q: %q", node.NamespaceSeparator, ns)
+		}
+	}
+
+	if sc.Chaos.Targets < 0 {
+		return fmt.Errorf("chaos.targets must be non-negative")
+	}
+
+	if sc.Recovery.MaxRetries < 0 {
+		return fmt.Errorf("recovery.max_retries must be non-negative")
+	}
+
+	if sc.ReportWarnings < 0 {
+		return fmt.Errorf("report_warnings must be non-negative")
+	}
+
+	if sc.Assertions.MaxErrorRate < 0 || sc.Assertions.MaxErrorRate > 1 {
+		return fmt.Errorf("assertions.max_error_rate must be between 0 and 1")
+	}
+	if sc.Assertions.MinThroughput < 0 {
+		return fmt.Errorf("assertions.min_throughput must be non-negative")
+	}
+	assertions, err := sc.Assertions.ApplyTo(scenario.Assertions{})
+	if err != nil {
+		return err
+	}
+	if assertions.MaxAvgLatency < 0 || assertions.MaxP99Latency < 0 {
+		return fmt.Errorf("assertions.max_avg_latency and assertions.max_p99_latency must be non-negative")
+	}
+
+	if sc.RESP.NodePort < 0 || sc.RESP.NodePort+max(sc.NodeCount, 1)-1 > 65535 {
+		return fmt.Errorf("resp.node_port must leave a port for every node within 1-65535")
+	}
+	if sc.NodeHTTP.Port < 0 || sc.NodeHTTP.Port+max(sc.NodeCount, 1)-1 > 65535 {
+		return fmt.Errorf("node_http.port must leave a port for every node within 1-65535")
+	}
+
+	if sc.Toxiproxy.URL != "" {
+		if _, err := parseToxiproxy(sc.Toxiproxy); err != nil {
+			return err
+		}
+		if !sc.NodeHTTP.Enabled && !sc.NodeProcess.Enabled && len(sc.External) == 0 {
+			return fmt.Errorf("toxiproxy requires node_http, node_process or external")
+		}
+	}
+
+	if sc.NodeProcess.Enabled {
+		if len(sc.NodeProcess.Command) > 0 && sc.NodeProcess.Command[0] == "" {
+			return fmt.Errorf("node_process.command must start with a program")
+		}
+		if sc.RESP != (RESPConfig{}) || sc.NodeHTTP.Enabled || len(sc.External) > 0 {
+			return fmt.Errorf("node_process cannot be combined with resp, node_http or external")
+		}
+	}
+
+	if a := sc.Autoscale; a.MinNodes < 0 || a.MaxNodes < 0 || a.Step < 0 || a.ScaleUpRPS < 0 || a.ScaleDownRPS < 0 {
+		return fmt.Errorf("autoscale must be non-negative")
+	}
+	if sc.Autoscale.MaxNodes > 0 {
+		if _, err := parseAutoscale(sc.Autoscale); err != nil {
+			return err
+		}
+		if len(sc.External) > 0 {
+			return fmt.Errorf("autoscale cannot be combined with external")
+		}
+	}
+
+	if sc.Tracing.Endpoint != "" {
+		if _, err := parseTracing(sc.Tracing); err != nil {
+			return err
+		}
+	}
+
+	if sc.Linearizability.MaxOperations < 0 {
+		return fmt.Errorf("linearizability.max_operations must be non-negative")
+	}
+
+	if len(sc.External) > 0 {
+		if _, err := parseExternal(sc.External); err != nil {
+			return err
+		}
+		if sc.RESP != (RESPConfig{}) || sc.NodeHTTP.Enabled {
+			return fmt.Errorf("external cannot be combined with resp or node_http")
+		}
+	}
+
+	if _, err := parseSteps(sc.Steps); err != nil {
+		return err
+	}
+
+	if f.Log.MaxSizeMB < 0 || f.Log.MaxBackups < 0 {
+		return fmt.Errorf("log.max_size_mb and log.max_backups must be non-negative")
+	}
+	if _, err := f.Log.ApplyTo(logger.FileConfig{}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,1112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// ptr は値のポインタを返す（省略可能なフィールドの指定用）
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestLoadFileYAML(t *testing.T) {
+	content := `
+scenario:
+  name: test-scenario
+  description: Test scenario
+  duration: 10s
+  node_count: 5
+  client:
+    workers: 10
+    write_ratio: 0.5
+  chaos:
+    enabled: true
+    interval: 2s
+    targets: 1
+    attack_types:
+      - kill
+      - suspend
+  recovery:
+    enabled: true
+    delay: 1s
+    max_retries: 3
+`
+	tmpFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	cfg, err := LoadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Scenario.Name != "test-scenario" {
+		t.Errorf("expected name 'test-scenario', got '%s'", cfg.Scenario.Name)
+	}
+	if cfg.Scenario.NodeCount != 5 {
+		t.Errorf("expected node_count 5, got %d", cfg.Scenario.NodeCount)
+	}
+	if e := cfg.Scenario.Chaos.Enabled; e == nil || !*e {
+		t.Error("expected chaos to be enabled")
+	}
+}
+
+func TestLoadFileJSON(t *testing.T) {
+	content := `{
+  "scenario": {
+    "name": "json-test",
+    "duration": "5s",
+    "node_count": 3,
+    "client": {
+      "workers": 5
+    },
+    "chaos": {
+      "enabled": false
+    },
+    "recovery": {
+      "enabled": true
+    }
+  }
+}`
+	tmpFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	cfg, err := LoadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Scenario.Name != "json-test" {
+		t.Errorf("expected name 'json-test', got '%s'", cfg.Scenario.Name)
+	}
+	if e := cfg.Scenario.Chaos.Enabled; e == nil || *e {
+		t.Error("expected chaos to be disabled")
+	}
+}
+
+func TestLoadFileNotFound(t *testing.T) {
+	_, err := LoadFile("/nonexistent/config.yaml")
+	if err == nil {
+		t.Error("expected error for nonexistent file")
+	}
+}
+
+func TestLoadFileUnsupportedFormat(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "config.txt")
+	if err := os.WriteFile(tmpFile, []byte("test"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	_, err := LoadFile(tmpFile)
+	if err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestToScenarioConfig(t *testing.T) {
+	cfg := &FileConfig{
+		Scenario: ScenarioConfig{
+			Name:          "test",
+			Description:   "Test",
+			Duration:      "10s",
+			NodeCount:     5,
+			Zones:         []string{"a", "b"},
+			NodeLimits:    NodeLimitsConfig{MaxKeys: 1000, MaxBytes: 1 << 20, MaxMemory: 2 << 20},
+			NodeAdmission: NodeAdmissionConfig{MaxInFlight: 64, ReservedReads: 16},
+			Durability:    DurabilityConfig{Mode: "snapshot", Dir: "/tmp/snapshots", Interval: "500ms", LossRatio: ptr(0.2)},
+			Store:         "crdt",
+			SyncInterval:  "500ms",
+
+			ReplicationFactor: ptr(3),
+			Consistency:       ConsistencyConfig{Write: "QUORUM", Read: "all"},
+			Client: ClientConfig{
+				Workers:       10,
+				WriteRatio:    0.7,
+				KeySampleRate: 0.2,
+				HotKeys:       5,
+				TargetRPS:     500,
+				BatchSize:     16,
+				ScanRatio:     0.1,
+				ScanLimit:     50,
+				Namespaces:    []string{"tenant-a", "tenant-b"},
+			},
+			Chaos: ChaosConfig{
+				Enabled:     ptr(true),
+				Interval:    "2s",
+				Targets:     2,
+				AttackTypes: []string{"kill", "delay"},
+			},
+			Recovery: RecoveryConfig{
+				Enabled:    ptr(true),
+				Delay:      "1s",
+				MaxRetries: 5,
+			},
+		},
+	}
+
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+
+	if scenarioCfg.Name != "test" {
+		t.Errorf("expected name 'test', got '%s'", scenarioCfg.Name)
+	}
+	if scenarioCfg.NodeCount != 5 {
+		t.Errorf("expected node count 5, got %d", scenarioCfg.NodeCount)
+	}
+	if len(scenarioCfg.Zones) != 2 {
+		t.Errorf("expected 2 zones, got %v", scenarioCfg.Zones)
+	}
+	if l := scenarioCfg.NodeLimits; l.MaxKeys != 1000 || l.MaxBytes != 1<<20 || l.MaxMemory != 2<<20 {
+		t.Errorf("expected node limits of 1000 keys, 1MiB and 2MiB of memory, got %+v", scenarioCfg.NodeLimits)
+	}
+	if d := scenarioCfg.Durability; d.Mode != node.DurabilitySnapshot || d.Path != "/tmp/snapshots" || d.Interval != 500*time.Millisecond || d.EphemeralLossRatio != 0.2 {
+		t.Errorf("expected snapshots every 500ms in /tmp/snapshots, got %+v", d)
+	}
+	if a := scenarioCfg.NodeAdmission; a.MaxInFlight != 64 || a.ReservedReads != 16 {
+		t.Errorf("expected admission of 64 in-flight operations with 16 for reads, got %+v", a)
+	}
+	if scenarioCfg.Store != node.StoreCRDT || scenarioCfg.SyncInterval != 500*time.Millisecond {
+		t.Errorf("expected the crdt store synced every 500ms, got %q and %v", scenarioCfg.Store, scenarioCfg.SyncInterval)
+	}
+	if scenarioCfg.ReplicationFactor != 3 {
+		t.Errorf("expected replication factor 3, got %d", scenarioCfg.ReplicationFactor)
+	}
+	if want := (cluster.Consistency{Write: cluster.ConsistencyQuorum, Read: cluster.ConsistencyAll}); scenarioCfg.Consistency != want {
+		t.Errorf("expected %v, got %v", want, scenarioCfg.Consistency)
+	}
+	if scenarioCfg.ClientWorkers != 10 {
+		t.Errorf("expected workers 10, got %d", scenarioCfg.ClientWorkers)
+	}
+	if scenarioCfg.WriteRatio != 0.7 {
+		t.Errorf("expected write ratio 0.7, got %f", scenarioCfg.WriteRatio)
+	}
+	if scenarioCfg.KeySampleRate != 0.2 || scenarioCfg.HotKeys != 5 {
+		t.Errorf("expected key sample rate 0.2 and 5 hot keys, got %f and %d", scenarioCfg.KeySampleRate, scenarioCfg.HotKeys)
+	}
+	if scenarioCfg.TargetRPS != 500 {
+		t.Errorf("expected target rps 500, got %f", scenarioCfg.TargetRPS)
+	}
+	if scenarioCfg.BatchSize != 16 {
+		t.Errorf("expected batch size 16, got %d", scenarioCfg.BatchSize)
+	}
+	if scenarioCfg.ScanRatio != 0.1 || scenarioCfg.ScanLimit != 50 {
+		t.Errorf("expected 10%% scans of 50 keys, got %f and %d", scenarioCfg.ScanRatio, scenarioCfg.ScanLimit)
+	}
+	if len(scenarioCfg.Namespaces) != 2 || scenarioCfg.Namespaces[1] != "tenant-b" {
+		t.Errorf("expected 2 namespaces, got %v", scenarioCfg.Namespaces)
+	}
+	if !scenarioCfg.EnableChaos {
+		t.Error("expected chaos to be enabled")
+	}
+	if len(scenarioCfg.AttackTypes) != 2 {
+		t.Errorf("expected 2 attack types, got %d", len(scenarioCfg.AttackTypes))
+	}
+}
+
+func TestToScenarioConfigInvalidDuration(t *testing.T) {
+	cfg := &FileConfig{
+		Scenario: ScenarioConfig{
+			Duration: "invalid",
+		},
+	}
+
+	_, err := cfg.ToScenarioConfig()
+	if err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestToScenarioConfigInvalidAttackType(t *testing.T) {
+	cfg := &FileConfig{
+		Scenario: ScenarioConfig{
+			Chaos: ChaosConfig{
+				Enabled:     ptr(true),
+				AttackTypes: []string{"unknown"},
+			},
+		},
+	}
+
+	_, err := cfg.ToScenarioConfig()
+	if err == nil {
+		t.Error("expected error for invalid attack type")
+	}
+}
+
+func TestToScenarioConfigNotifications(t *testing.T) {
+	data := []byte(`
+scenario:
+  notifications:
+    - type: Slack
+      url: https://hooks.slack.com/services/T/B/X
+    - type: discord
+      url: https://discord.com/api/webhooks/1/abc
+      events: [slo_violation, recovery_failed]
+      template: "{{.Type}} on {{.NodeID}}"
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+
+	if len(scenarioCfg.Notifiers) != 2 {
+		t.Fatalf("expected 2 notifiers, got %d", len(scenarioCfg.Notifiers))
+	}
+	if n := scenarioCfg.Notifiers[0]; n.Kind != events.NotifierSlack || len(n.Types) != 0 {
+		t.Errorf("unexpected slack notifier: %+v", n)
+	}
+	if n := scenarioCfg.Notifiers[1]; n.Kind != events.NotifierDiscord || len(n.Types) != 2 || n.Template == "" {
+		t.Errorf("unexpected discord notifier: %+v", n)
+	}
+
+	for _, bad := range []NotificationConfig{
+		{Type: "teams", URL: "https://example.com"},
+		{Type: "slack"},
+		{Type: "slack", URL: "https://example.com", Events: []string{"node_quarantined"}},
+	} {
+		cfg := &FileConfig{Scenario: ScenarioConfig{Notifications: []NotificationConfig{bad}}}
+		if _, err := cfg.ToScenarioConfig(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestLogConfig(t *testing.T) {
+	data := []byte(`
+log:
+  file: logs/chaos.log
+  max_size_mb: 5
+  rotate_interval: 24h
+  max_age: 168h
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	fileCfg, err := cfg.Log.ApplyTo(logger.DefaultFileConfig())
+	if err != nil {
+		t.Fatalf("failed to apply log config: %v", err)
+	}
+	want := logger.FileConfig{
+		Path:           "logs/chaos.log",
+		MaxSize:        5 << 20,
+		RotateInterval: 24 * time.Hour,
+		MaxBackups:     logger.DefaultFileConfig().MaxBackups,
+		MaxAge:         168 * time.Hour,
+	}
+	if fileCfg != want {
+		t.Errorf("expected %+v, got %+v", want, fileCfg)
+	}
+
+	for _, bad := range []LogConfig{
+		{RotateInterval: "daily"},
+		{MaxAge: "1w"},
+		{MaxBackups: -1},
+	} {
+		cfg := &FileConfig{Log: bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestAssertionsConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  assertions:
+    max_error_rate: 0.05
+    max_p99_latency: 50ms
+    min_throughput: 1000
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	want := scenario.Assertions{MaxErrorRate: 0.05, MaxP99Latency: 50 * time.Millisecond, MinThroughput: 1000}
+	if scenarioCfg.Assertions != want {
+		t.Errorf("expected %+v, got %+v", want, scenarioCfg.Assertions)
+	}
+
+	for _, bad := range []AssertionsConfig{
+		{MaxErrorRate: 1.5},
+		{MinThroughput: -1},
+		{MaxAvgLatency: "fast"},
+		{MaxP99Latency: "-1ms"},
+	} {
+		cfg := &FileConfig{Scenario: ScenarioConfig{Assertions: bad}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestRESPConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  node_count: 3
+  resp:
+    addr: ":6379"
+    node_port: 7001
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	if scenarioCfg.RESPAddr != ":6379" || scenarioCfg.RESPNodePort != 7001 {
+		t.Errorf("unexpected RESP config: %q, %d", scenarioCfg.RESPAddr, scenarioCfg.RESPNodePort)
+	}
+
+	for _, bad := range []ScenarioConfig{
+		{RESP: RESPConfig{NodePort: -1}},
+		{NodeCount: 3, RESP: RESPConfig{NodePort: 65534}},
+	} {
+		cfg := &FileConfig{Scenario: bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad.RESP)
+		}
+	}
+}
+
+func TestLatencyConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  zones: [us-east, eu-west]
+  latency:
+    intra_zone: 1ms
+    inter_zone: 80ms
+    coordinator: us-east
+    links:
+      - {from: us-east, to: ap-south, delay: 150ms}
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	want := cluster.LatencyMatrix{
+		IntraZone:   time.Millisecond,
+		InterZone:   80 * time.Millisecond,
+		Links:       []cluster.Link{{From: "us-east", To: "ap-south", Delay: 150 * time.Millisecond}},
+		Coordinator: "us-east",
+	}
+	got := scenarioCfg.Latency
+	if got.IntraZone != want.IntraZone || got.InterZone != want.InterZone || got.Coordinator != want.Coordinator ||
+		len(got.Links) != 1 || got.Links[0] != want.Links[0] {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	for _, bad := range []LatencyConfig{
+		{InterZone: "fast"},
+		{IntraZone: "-1ms"},
+		{Links: []LatencyLinkConfig{{From: "a", Delay: "1ms"}}},
+		{Links: []LatencyLinkConfig{{From: "a", To: "b"}}},
+	} {
+		cfg := &FileConfig{Scenario: ScenarioConfig{Latency: bad}}
+		if _, err := cfg.ToScenarioConfig(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestNodeHTTPConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  node_count: 3
+  node_http:
+    enabled: true
+    port: 8101
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	if !scenarioCfg.NodeHTTP || scenarioCfg.NodeHTTPPort != 8101 {
+		t.Errorf("unexpected node HTTP config: %v, %d", scenarioCfg.NodeHTTP, scenarioCfg.NodeHTTPPort)
+	}
+
+	for _, bad := range []ScenarioConfig{
+		{NodeHTTP: NodeHTTPConfig{Enabled: true, Port: -1}},
+		{NodeCount: 3, NodeHTTP: NodeHTTPConfig{Enabled: true, Port: 65534}},
+	} {
+		cfg := &FileConfig{Scenario: bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad.NodeHTTP)
+		}
+	}
+}
+
+func TestToxiproxyConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  node_http:
+    enabled: true
+  toxiproxy:
+    url: http://127.0.0.1:8474
+    listen: 0.0.0.0
+    bandwidth: 128
+    timeout: 2s
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	tp := scenarioCfg.Toxiproxy
+	if tp.URL != "http://127.0.0.1:8474" || tp.Listen != "0.0.0.0" || tp.Bandwidth != 128 || tp.Timeout != 2*time.Second {
+		t.Errorf("unexpected toxiproxy config: %+v", tp)
+	}
+
+	nodeHTTP := NodeHTTPConfig{Enabled: true}
+	for _, bad := range []ScenarioConfig{
+		{Toxiproxy: ToxiproxyConfig{URL: "http://127.0.0.1:8474"}},
+		{NodeHTTP: nodeHTTP, Toxiproxy: ToxiproxyConfig{URL: "127.0.0.1:8474"}},
+		{NodeHTTP: nodeHTTP, Toxiproxy: ToxiproxyConfig{URL: "http://127.0.0.1:8474", Bandwidth: -1}},
+		{NodeHTTP: nodeHTTP, Toxiproxy: ToxiproxyConfig{URL: "http://127.0.0.1:8474", Timeout: "soon"}},
+	} {
+		cfg := &FileConfig{Scenario: bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad.Toxiproxy)
+		}
+	}
+}
+
+func TestNodeProcessConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  node_process:
+    enabled: true
+    command: [/usr/local/bin/chaos-kvs, node-server]
+  toxiproxy:
+    url: http://127.0.0.1:8474
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	if !scenarioCfg.NodeProcess || strings.Join(scenarioCfg.NodeCommand, " ") != "/usr/local/bin/chaos-kvs node-server" {
+		t.Errorf("unexpected node process config: %v %v", scenarioCfg.NodeProcess, scenarioCfg.NodeCommand)
+	}
+
+	nodeProcess := NodeProcessConfig{Enabled: true}
+	for _, bad := range []ScenarioConfig{
+		{NodeProcess: NodeProcessConfig{Enabled: true, Command: []string{""}}},
+		{NodeProcess: nodeProcess, NodeHTTP: NodeHTTPConfig{Enabled: true}},
+		{NodeProcess: nodeProcess, RESP: RESPConfig{Addr: ":6379"}},
+		{NodeProcess: nodeProcess, External: []ExternalConfig{{ID: "redis-1", Addr: "127.0.0.1:6379"}}},
+	} {
+		cfg := &FileConfig{Scenario: bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestTracingConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  tracing:
+    endpoint: http://127.0.0.1:4318
+    service_name: chaos-lab
+    sample_rate: 0.25
+    headers:
+      Authorization: Basic dGVtcG86c2VjcmV0
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	tc := scenarioCfg.Tracing
+	if tc.Endpoint != "http://127.0.0.1:4318" || tc.ServiceName != "chaos-lab" || tc.SampleRate != 0.25 ||
+		tc.Headers["Authorization"] == "" || tc.BatchSize == 0 {
+		t.Errorf("unexpected tracing config: %+v", tc)
+	}
+
+	for _, bad := range []TracingConfig{
+		{Endpoint: "127.0.0.1:4318"},
+		{Endpoint: "http://127.0.0.1:4318", SampleRate: 2},
+	} {
+		cfg := &FileConfig{Scenario: ScenarioConfig{Tracing: bad}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestAutoscaleConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  autoscale:
+    min_nodes: 3
+    max_nodes: 8
+    scale_up_rps: 500
+    scale_up_p99: 0s
+    cooldown: 10s
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	a := scenarioCfg.Autoscale
+	if a.MinNodes != 3 || a.MaxNodes != 8 || a.Step != 1 || a.ScaleUpRPS != 500 || a.ScaleDownRPS != 200 ||
+		a.ScaleUpP99 != 0 || a.Interval != 2*time.Second || a.Cooldown != 10*time.Second {
+		t.Errorf("unexpected autoscale config: %+v", a)
+	}
+
+	// max_nodes を省略すると増減しない
+	scenarioCfg, err = (&FileConfig{}).ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	if scenarioCfg.Autoscale.MaxNodes != 0 {
+		t.Errorf("expected autoscaling to be disabled, got %+v", scenarioCfg.Autoscale)
+	}
+
+	for _, bad := range []AutoscaleConfig{
+		{MinNodes: 5, MaxNodes: 3},
+		{MaxNodes: 4, ScaleUpRPS: 100},
+		{MaxNodes: 4, Interval: "soon"},
+		{MaxNodes: -1},
+	} {
+		cfg := &FileConfig{Scenario: ScenarioConfig{Autoscale: bad}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestLinearizabilityConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  linearizability:
+    enabled: true
+    max_operations: 5000
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	if !scenarioCfg.Linearizability || scenarioCfg.HistoryLimit != 5000 {
+		t.Errorf("unexpected linearizability config: %v, %d", scenarioCfg.Linearizability, scenarioCfg.HistoryLimit)
+	}
+
+	bad := &FileConfig{Scenario: ScenarioConfig{Linearizability: LinearizabilityConfig{Enabled: true, MaxOperations: -1}}}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative max_operations")
+	}
+}
+
+func TestExternalConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  node_count: 5
+  external:
+    - id: redis-1
+      addr: 127.0.0.1:6379
+      start: docker start redis-1
+      stop: docker kill redis-1
+      timeout: 10s
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+	if len(scenarioCfg.External) != 1 {
+		t.Fatalf("expected 1 external node, got %d", len(scenarioCfg.External))
+	}
+	ext := scenarioCfg.External[0]
+	if ext.ID != "redis-1" || ext.Addr != "127.0.0.1:6379" || ext.Commands.Stop != "docker kill redis-1" || ext.Timeout != 10*time.Second {
+		t.Errorf("unexpected external config: %+v", ext)
+	}
+
+	valid := ExternalConfig{ID: "redis-1", Addr: "127.0.0.1:6379"}
+	for _, bad := range []ScenarioConfig{
+		{External: []ExternalConfig{{Addr: "127.0.0.1:6379"}}},
+		{External: []ExternalConfig{{ID: "redis-1", Addr: "localhost"}}},
+		{External: []ExternalConfig{valid, valid}},
+		{External: []ExternalConfig{{ID: "redis-1", Addr: "127.0.0.1:6379", Stop: "docker kill redis-1"}}},
+		{External: []ExternalConfig{{ID: "redis-1", Addr: "127.0.0.1:6379", Suspend: "docker pause redis-1"}}},
+		{External: []ExternalConfig{{ID: "redis-1", Addr: "127.0.0.1:6379", Timeout: "soon"}}},
+		{External: []ExternalConfig{valid}, NodeHTTP: NodeHTTPConfig{Enabled: true}},
+		{External: []ExternalConfig{valid}, RESP: RESPConfig{Addr: ":6379"}},
+	} {
+		cfg := &FileConfig{Scenario: bad}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestParseAttackTypes(t *testing.T) {
+	tests := []struct {
+		input    []string
+		expected []chaos.AttackType
+		hasError bool
+	}{
+		{[]string{"kill"}, []chaos.AttackType{chaos.AttackKill}, false},
+		{[]string{"suspend"}, []chaos.AttackType{chaos.AttackSuspend}, false},
+		{[]string{"delay"}, []chaos.AttackType{chaos.AttackDelay}, false},
+		{[]string{"readonly"}, []chaos.AttackType{chaos.AttackReadOnly}, false},
+		{[]string{"corrupt"}, []chaos.AttackType{chaos.AttackCorrupt}, false},
+		{[]string{"KILL", "SUSPEND"}, []chaos.AttackType{chaos.AttackKill, chaos.AttackSuspend}, false},
+		{[]string{"unknown"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		attacks, err := parseAttackTypes(tt.input)
+		if tt.hasError {
+			if err == nil {
+				t.Errorf("expected error for input %v", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for input %v: %v", tt.input, err)
+			continue
+		}
+		if len(attacks) != len(tt.expected) {
+			t.Errorf("expected %d attacks, got %d", len(tt.expected), len(attacks))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   FileConfig
+		hasError bool
+	}{
+		{
+			name:     "valid config",
+			config:   FileConfig{},
+			hasError: false,
+		},
+		{
+			name: "negative node count",
+			config: FileConfig{
+				Scenario: ScenarioConfig{NodeCount: -1},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative workers",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{Workers: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "invalid write ratio (too high)",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{WriteRatio: 1.5}},
+			},
+			hasError: true,
+		},
+		{
+			name: "invalid write ratio (negative)",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{WriteRatio: -0.1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "invalid key sample rate",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{KeySampleRate: 1.5}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative node limits",
+			config: FileConfig{
+				Scenario: ScenarioConfig{NodeLimits: NodeLimitsConfig{MaxBytes: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative node admission",
+			config: FileConfig{
+				Scenario: ScenarioConfig{NodeAdmission: NodeAdmissionConfig{MaxInFlight: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "reserved reads without room for writes",
+			config: FileConfig{
+				Scenario: ScenarioConfig{NodeAdmission: NodeAdmissionConfig{MaxInFlight: 4, ReservedReads: 4}},
+			},
+			hasError: true,
+		},
+		{
+			name: "unknown WAL sync policy",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Durability: DurabilityConfig{Mode: "wal", Sync: "sometimes"}},
+			},
+			hasError: true,
+		},
+		{
+			name: "loss ratio above 1",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Durability: DurabilityConfig{LossRatio: ptr(1.5)}},
+			},
+			hasError: true,
+		},
+		{
+			name: "unknown durability mode",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Durability: DurabilityConfig{Mode: "disk"}},
+			},
+			hasError: true,
+		},
+		{
+			name: "unknown store",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Store: "btree"},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative replication factor",
+			config: FileConfig{
+				Scenario: ScenarioConfig{ReplicationFactor: ptr(-1)},
+			},
+			hasError: true,
+		},
+		{
+			name: "unknown consistency level",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Consistency: ConsistencyConfig{Read: "two"}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative target rps",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{TargetRPS: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative batch size",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{BatchSize: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "scan ratio above 1",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{ScanRatio: 1.5}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative scan limit",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{ScanLimit: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "namespace with separator",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{Namespaces: []string{"a/b"}}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative hot keys",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Client: ClientConfig{HotKeys: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative chaos targets",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Chaos: ChaosConfig{Targets: -1}},
+			},
+			hasError: true,
+		},
+		{
+			name: "negative max retries",
+			config: FileConfig{
+				Scenario: ScenarioConfig{Recovery: RecoveryConfig{MaxRetries: -1}},
+			},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.hasError && err == nil {
+				t.Error("expected validation error")
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	content := `
+scenario:
+  name: base
+  duration: 30s
+  node_count: 5
+  client:
+    workers: 20
+    write_ratio: 0.5
+  chaos:
+    enabled: true
+    interval: 3s
+profiles:
+  ci:
+    duration: 5s
+    client:
+      workers: 2
+  stress:
+    node_count: 50
+`
+	tmpFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	cfg, err := LoadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	names := cfg.ProfileNames()
+	if len(names) != 2 || names[0] != "ci" || names[1] != "stress" {
+		t.Errorf("expected profiles [ci stress], got %v", names)
+	}
+
+	if err := cfg.ApplyProfile("ci"); err != nil {
+		t.Fatalf("failed to apply profile: %v", err)
+	}
+
+	if cfg.Scenario.Duration != "5s" {
+		t.Errorf("expected duration '5s', got '%s'", cfg.Scenario.Duration)
+	}
+	if cfg.Scenario.Client.Workers != 2 {
+		t.Errorf("expected workers 2, got %d", cfg.Scenario.Client.Workers)
+	}
+	// 上書きされていない値は維持される
+	if cfg.Scenario.Client.WriteRatio != 0.5 {
+		t.Errorf("expected write_ratio 0.5, got %f", cfg.Scenario.Client.WriteRatio)
+	}
+	if cfg.Scenario.NodeCount != 5 {
+		t.Errorf("expected node_count 5, got %d", cfg.Scenario.NodeCount)
+	}
+	if e := cfg.Scenario.Chaos.Enabled; e == nil || !*e {
+		t.Error("expected chaos to remain enabled")
+	}
+}
+
+func TestApplyProfileJSON(t *testing.T) {
+	content := `{
+  "scenario": {"name": "base", "node_count": 3},
+  "profiles": {"dev": {"node_count": 1, "recovery": {"enabled": true}}}
+}`
+	tmpFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	cfg, err := LoadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if err := cfg.ApplyProfile("dev"); err != nil {
+		t.Fatalf("failed to apply profile: %v", err)
+	}
+
+	if cfg.Scenario.NodeCount != 1 {
+		t.Errorf("expected node_count 1, got %d", cfg.Scenario.NodeCount)
+	}
+	if cfg.Scenario.Name != "base" {
+		t.Errorf("expected name 'base', got '%s'", cfg.Scenario.Name)
+	}
+	if e := cfg.Scenario.Recovery.Enabled; e == nil || !*e {
+		t.Error("expected recovery to be enabled")
+	}
+}
+
+func TestApplyProfileUnknown(t *testing.T) {
+	cfg := &FileConfig{}
+	if err := cfg.ApplyProfile("missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestLoadFileStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml typo",
+			file: "config.yaml",
+			content: `
+scenario:
+  client:
+    wirte_ratio: 0.3
+`,
+		},
+		{
+			name:    "json typo",
+			file:    "config.json",
+			content: `{"scenario": {"client": {"wirte_ratio": 0.3}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(tmpFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create temp file: %v", err)
+			}
+
+			// 非厳格モードでは無視される
+			if _, err := LoadFile(tmpFile); err != nil {
+				t.Errorf("unexpected error in non-strict mode: %v", err)
+			}
+
+			opts := DefaultLoadOptions()
+			opts.Strict = true
+			if _, err := LoadFileWithOptions(tmpFile, opts); err == nil {
+				t.Error("expected error for unknown field in strict mode")
+			}
+		})
+	}
+}
+
+func TestApplyProfileStrict(t *testing.T) {
+	content := `
+scenario:
+  name: base
+profiles:
+  ci:
+    node_cout: 3
+`
+	tmpFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	opts := DefaultLoadOptions()
+	opts.Strict = true
+	cfg, err := LoadFileWithOptions(tmpFile, opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if err := cfg.ApplyProfile("ci"); err == nil {
+		t.Error("expected error for unknown field in strict profile")
+	}
+}
+
+func TestLoadFileStrictEmpty(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tmpFile, []byte(""), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	opts := DefaultLoadOptions()
+	opts.Strict = true
+	if _, err := LoadFileWithOptions(tmpFile, opts); err != nil {
+		t.Errorf("unexpected error for empty config: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+	"github.com/nyasuto/chaos-kvs/pkg/toxiproxy"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+)
+
+// 環境変数名
+const (
+	EnvDuration = "CHAOS_KVS_DURATION"
+	EnvNodes    = "CHAOS_KVS_NODES"
+	EnvWorkers  = "CHAOS_KVS_WORKERS"
+	EnvChaos    = "CHAOS_KVS_CHAOS"
+	EnvRecovery = "CHAOS_KVS_RECOVERY"
+)
+
+// Overrides はシナリオ設定に対する部分的な上書き値
+// nil のフィールドは未指定として扱われ、上書きされない
+type Overrides struct {
+	Duration        *time.Duration
+	NodeCount       *int
+	ClientWorkers   *int
+	EnableChaos     *bool
+	EnableRecovery  *bool
+	RESPAddr        *string
+	RESPNodePort    *int
+	NodeHTTP        *bool
+	NodeHTTPPort    *int
+	NodeProcess     *bool
+	Linearizability *bool
+	ToxiproxyURL    *string
+	TraceEndpoint   *string
+}
+
+// Apply は指定された値のみをシナリオ設定に上書きする
+func (o Overrides) Apply(cfg *scenario.Config) {
+	if o.Duration != nil && *o.Duration > 0 {
+		cfg.Duration = *o.Duration
+	}
+	if o.NodeCount != nil && *o.NodeCount > 0 {
+		cfg.NodeCount = *o.NodeCount
+	}
+	if o.ClientWorkers != nil && *o.ClientWorkers > 0 {
+		cfg.ClientWorkers = *o.ClientWorkers
+	}
+	if o.EnableChaos != nil {
+		cfg.EnableChaos = *o.EnableChaos
+	}
+	if o.EnableRecovery != nil {
+		cfg.EnableRecovery = *o.EnableRecovery
+	}
+	if o.RESPAddr != nil {
+		cfg.RESPAddr = *o.RESPAddr
+	}
+	if o.RESPNodePort != nil && *o.RESPNodePort >= 0 {
+		cfg.RESPNodePort = *o.RESPNodePort
+	}
+	if o.NodeHTTP != nil {
+		cfg.NodeHTTP = *o.NodeHTTP
+	}
+	if o.NodeHTTPPort != nil && *o.NodeHTTPPort >= 0 {
+		cfg.NodeHTTPPort = *o.NodeHTTPPort
+	}
+	if o.NodeProcess != nil {
+		cfg.NodeProcess = *o.NodeProcess
+	}
+	if o.Linearizability != nil {
+		cfg.Linearizability = *o.Linearizability
+	}
+	if o.ToxiproxyURL != nil {
+		if cfg.Toxiproxy.URL == "" {
+			cfg.Toxiproxy = toxiproxy.DefaultConfig()
+		}
+		cfg.Toxiproxy.URL = *o.ToxiproxyURL
+	}
+	if o.TraceEndpoint != nil {
+		if cfg.Tracing.Endpoint == "" {
+			cfg.Tracing = tracing.DefaultConfig()
+		}
+		cfg.Tracing.Endpoint = *o.TraceEndpoint
+	}
+}
+
+// OverridesFromEnv は環境変数から上書き値を読み込む
+// lookup には通常 os.LookupEnv を渡す
+func OverridesFromEnv(lookup func(string) (string, bool)) (Overrides, error) {
+	var o Overrides
+
+	if v, ok := lookup(EnvDuration); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s: %w", EnvDuration, err)
+		}
+		o.Duration = &d
+	}
+	if v, ok := lookup(EnvNodes); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s: %w", EnvNodes, err)
+		}
+		o.NodeCount = &n
+	}
+	if v, ok := lookup(EnvWorkers); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s: %w", EnvWorkers, err)
+		}
+		o.ClientWorkers = &n
+	}
+	if v, ok := lookup(EnvChaos); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s: %w", EnvChaos, err)
+		}
+		o.EnableChaos = &b
+	}
+	if v, ok := lookup(EnvRecovery); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s: %w", EnvRecovery, err)
+		}
+		o.EnableRecovery = &b
+	}
+
+	return o, nil
+}
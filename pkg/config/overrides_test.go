@@ -0,0 +1,200 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestOverridesApply(t *testing.T) {
+	cfg := scenario.QuickScenario()
+
+	d := 42 * time.Second
+	nodes := 9
+	chaosOff := false
+	o := Overrides{
+		Duration:    &d,
+		NodeCount:   &nodes,
+		EnableChaos: &chaosOff,
+	}
+	o.Apply(&cfg)
+
+	if cfg.Duration != d {
+		t.Errorf("expected duration %v, got %v", d, cfg.Duration)
+	}
+	if cfg.NodeCount != 9 {
+		t.Errorf("expected node count 9, got %d", cfg.NodeCount)
+	}
+	if cfg.EnableChaos {
+		t.Error("expected chaos to be disabled")
+	}
+	// 未指定の値は維持される
+	if !cfg.EnableRecovery {
+		t.Error("expected recovery to remain enabled")
+	}
+	if cfg.ClientWorkers != scenario.QuickScenario().ClientWorkers {
+		t.Errorf("expected workers to be unchanged, got %d", cfg.ClientWorkers)
+	}
+}
+
+func TestOverridesApplyToxiproxy(t *testing.T) {
+	cfg := scenario.QuickScenario()
+	url := "http://10.0.0.1:8474"
+	Overrides{ToxiproxyURL: &url}.Apply(&cfg)
+	if cfg.Toxiproxy.URL != url || cfg.Toxiproxy.Timeout == 0 {
+		t.Errorf("expected toxiproxy defaults with the URL, got %+v", cfg.Toxiproxy)
+	}
+
+	// 設定ファイルの他の項目は維持する
+	cfg.Toxiproxy.Bandwidth = 64
+	url = "http://10.0.0.2:8474"
+	Overrides{ToxiproxyURL: &url}.Apply(&cfg)
+	if cfg.Toxiproxy.URL != url || cfg.Toxiproxy.Bandwidth != 64 {
+		t.Errorf("expected only the URL to change, got %+v", cfg.Toxiproxy)
+	}
+}
+
+func TestOverridesApplyTraceEndpoint(t *testing.T) {
+	cfg := scenario.QuickScenario()
+	endpoint := "http://10.0.0.1:4318"
+	Overrides{TraceEndpoint: &endpoint}.Apply(&cfg)
+	if cfg.Tracing.Endpoint != endpoint || cfg.Tracing.SampleRate == 0 {
+		t.Errorf("expected tracing defaults with the endpoint, got %+v", cfg.Tracing)
+	}
+
+	// 設定ファイルの他の項目は維持する
+	cfg.Tracing.ServiceName = "chaos-lab"
+	endpoint = "http://10.0.0.2:4318"
+	Overrides{TraceEndpoint: &endpoint}.Apply(&cfg)
+	if cfg.Tracing.Endpoint != endpoint || cfg.Tracing.ServiceName != "chaos-lab" {
+		t.Errorf("expected only the endpoint to change, got %+v", cfg.Tracing)
+	}
+}
+
+func TestOverridesFromEnv(t *testing.T) {
+	o, err := OverridesFromEnv(envLookup(map[string]string{
+		EnvDuration: "1m",
+		EnvWorkers:  "12",
+		EnvRecovery: "false",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if o.Duration == nil || *o.Duration != time.Minute {
+		t.Errorf("expected duration 1m, got %v", o.Duration)
+	}
+	if o.ClientWorkers == nil || *o.ClientWorkers != 12 {
+		t.Errorf("expected workers 12, got %v", o.ClientWorkers)
+	}
+	if o.EnableRecovery == nil || *o.EnableRecovery {
+		t.Errorf("expected recovery false, got %v", o.EnableRecovery)
+	}
+	if o.NodeCount != nil || o.EnableChaos != nil {
+		t.Error("expected unset variables to remain nil")
+	}
+}
+
+func TestOverridesFromEnvInvalid(t *testing.T) {
+	tests := map[string]string{
+		EnvDuration: "soon",
+		EnvNodes:    "many",
+		EnvWorkers:  "1.5",
+		EnvChaos:    "maybe",
+		EnvRecovery: "perhaps",
+	}
+
+	for key, value := range tests {
+		if _, err := OverridesFromEnv(envLookup(map[string]string{key: value})); err == nil {
+			t.Errorf("expected error for %s=%s", key, value)
+		}
+	}
+}
+
+func TestApplyToKeepsBase(t *testing.T) {
+	cfg := &FileConfig{
+		Scenario: ScenarioConfig{
+			NodeCount: 8,
+			Chaos:     ChaosConfig{Enabled: ptr(true)},
+		},
+	}
+
+	base := scenario.StressScenario()
+	result, err := cfg.ApplyTo(base)
+	if err != nil {
+		t.Fatalf("failed to apply config: %v", err)
+	}
+
+	if result.NodeCount != 8 {
+		t.Errorf("expected node count 8, got %d", result.NodeCount)
+	}
+	if result.Name != base.Name {
+		t.Errorf("expected name '%s', got '%s'", base.Name, result.Name)
+	}
+	if result.ClientWorkers != base.ClientWorkers {
+		t.Errorf("expected workers %d, got %d", base.ClientWorkers, result.ClientWorkers)
+	}
+}
+
+func TestApplyToKeepsPresetWithoutSections(t *testing.T) {
+	// chaos・recovery などのセクションを持たないファイル
+	cfg, err := parse([]byte("scenario:\n  node_count: 4\n"), ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	base, ok := scenario.GetPreset("resilience")
+	if !ok {
+		t.Fatal("resilience preset not found")
+	}
+	base.ReplicationFactor = 3
+	base.Consistency = cluster.Consistency{Write: cluster.ConsistencyQuorum, Read: cluster.ConsistencyAll}
+	base.Durability.EphemeralLossRatio = 0.2
+	base.Namespaces = []string{"tenant-a"}
+
+	result, err := cfg.ApplyTo(base)
+	if err != nil {
+		t.Fatalf("failed to apply config: %v", err)
+	}
+
+	if result.NodeCount != 4 {
+		t.Errorf("expected node count 4, got %d", result.NodeCount)
+	}
+	if !result.EnableChaos || !result.EnableRecovery {
+		t.Errorf("expected chaos and recovery to stay enabled, got chaos=%v recovery=%v", result.EnableChaos, result.EnableRecovery)
+	}
+	if result.ReplicationFactor != 3 {
+		t.Errorf("expected replication factor 3, got %d", result.ReplicationFactor)
+	}
+	if result.Consistency != base.Consistency {
+		t.Errorf("expected consistency %+v, got %+v", base.Consistency, result.Consistency)
+	}
+	if result.Durability.EphemeralLossRatio != 0.2 {
+		t.Errorf("expected loss ratio 0.2, got %v", result.Durability.EphemeralLossRatio)
+	}
+	if len(result.Namespaces) != 1 || result.Namespaces[0] != "tenant-a" {
+		t.Errorf("expected namespaces to be kept, got %v", result.Namespaces)
+	}
+
+	// ファイルで明示した値はプリセットより優先する
+	cfg, err = parse([]byte("scenario:\n  replication_factor: 1\n  chaos:\n    enabled: false\n"), ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	result, err = cfg.ApplyTo(base)
+	if err != nil {
+		t.Fatalf("failed to apply config: %v", err)
+	}
+	if result.EnableChaos || !result.EnableRecovery || result.ReplicationFactor != 1 {
+		t.Errorf("expected chaos off, recovery on and factor 1, got chaos=%v recovery=%v factor=%d", result.EnableChaos, result.EnableRecovery, result.ReplicationFactor)
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxRemoteConfigSize はリモート設定の最大サイズ
+const maxRemoteConfigSize = 4 << 20
+
+// cacheMeta はETagキャッシュのメタデータ
+type cacheMeta struct {
+	URL    string `json:"url"`
+	ETag   string `json:"etag"`
+	Format string `json:"format"`
+}
+
+// IsRemote は設定ソースがHTTP(S)のURLかどうかを返す
+func IsRemote(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// Load は設定ソースに応じてローカルファイルまたはURLから設定を読み込む
+func Load(source string, opts LoadOptions) (*FileConfig, error) {
+	if IsRemote(source) {
+		return LoadURL(source, opts)
+	}
+	return LoadFileWithOptions(source, opts)
+}
+
+// LoadURL はHTTP(S)経由で設定を取得する
+// CacheDir が指定されている場合は ETag による条件付きリクエストを行い、
+// 304 Not Modified の場合はキャッシュ済みの内容を使用する
+func LoadURL(rawURL string, opts LoadOptions) (*FileConfig, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var cached *cacheMeta
+	if opts.CacheDir != "" {
+		cached = readCacheMeta(opts.CacheDir, rawURL)
+		if cached != nil && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	client := &http.Client{Timeout: opts.Timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var data []byte
+	var format string
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config response: %w", err)
+		}
+		if len(data) > maxRemoteConfigSize {
+			return nil, fmt.Errorf("config response exceeds %d bytes", maxRemoteConfigSize)
+		}
+		format = detectFormat(u, resp.Header.Get("Content-Type"))
+	case http.StatusNotModified:
+		if cached == nil {
+			return nil, fmt.Errorf("received 304 Not Modified without cached config")
+		}
+		data, err = os.ReadFile(cacheBodyPath(opts.CacheDir, rawURL))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached config: %w", err)
+		}
+		format = cached.Format
+	default:
+		return nil, fmt.Errorf("failed to fetch config: unexpected status %s", resp.Status)
+	}
+
+	if err := verifyChecksum(data, opts.Checksum); err != nil {
+		return nil, err
+	}
+
+	config, err := parse(data, format, opts.Strict)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CacheDir != "" && resp.StatusCode == http.StatusOK {
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			if err := writeCache(opts.CacheDir, cacheMeta{URL: rawURL, ETag: etag, Format: format}, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// detectFormat はURLの拡張子、またはContent-Typeから設定フォーマットを判定する
+// 判定できない場合はYAMLとして扱う（YAMLはJSONの上位互換）
+func detectFormat(u *url.URL, contentType string) string {
+	switch ext := strings.ToLower(path.Ext(u.Path)); ext {
+	case ".yaml", ".yml", ".json":
+		return ext
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if strings.HasSuffix(mediaType, "json") {
+			return ".json"
+		}
+	}
+	return ".yaml"
+}
+
+// verifyChecksum はデータのSHA-256チェックサムを検証する
+func verifyChecksum(data []byte, expected string) error {
+	if expected == "" {
+		return nil
+	}
+
+	expected = strings.TrimPrefix(strings.ToLower(expected), "sha256:")
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if actual != expected {
+		return fmt.Errorf("config checksum mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// cacheKey はURLに対応するキャッシュファイル名のベースを返す
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func cacheMetaPath(dir, rawURL string) string {
+	return filepath.Join(dir, cacheKey(rawURL)+".json")
+}
+
+func cacheBodyPath(dir, rawURL string) string {
+	return filepath.Join(dir, cacheKey(rawURL)+".body")
+}
+
+// readCacheMeta はキャッシュのメタデータを読み込む（存在しなければnil）
+func readCacheMeta(dir, rawURL string) *cacheMeta {
+	data, err := os.ReadFile(cacheMetaPath(dir, rawURL))
+	if err != nil {
+		return nil
+	}
+	var meta cacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.URL != rawURL {
+		return nil
+	}
+	if _, err := os.Stat(cacheBodyPath(dir, rawURL)); err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// writeCache は取得した設定とETagをキャッシュに保存する
+func writeCache(dir string, meta cacheMeta, body []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	if err := os.WriteFile(cacheBodyPath(dir, meta.URL), body, 0644); err != nil {
+		return fmt.Errorf("failed to write config cache: %w", err)
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache metadata: %w", err)
+	}
+	if err := os.WriteFile(cacheMetaPath(dir, meta.URL), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const remoteYAML = `
+scenario:
+  name: remote-scenario
+  node_count: 7
+`
+
+func TestIsRemote(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected bool
+	}{
+		{"https://example.com/scenario.yaml", true},
+		{"http://localhost:8000/scenario.json", true},
+		{"scenario.yaml", false},
+		{"/etc/chaos-kvs/scenario.yaml", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRemote(tt.source); got != tt.expected {
+			t.Errorf("IsRemote(%q) = %v, expected %v", tt.source, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(remoteYAML))
+	}))
+	defer srv.Close()
+
+	cfg, err := LoadURL(srv.URL+"/scenario.yaml", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("failed to load remote config: %v", err)
+	}
+
+	if cfg.Scenario.Name != "remote-scenario" {
+		t.Errorf("expected name 'remote-scenario', got '%s'", cfg.Scenario.Name)
+	}
+	if cfg.Scenario.NodeCount != 7 {
+		t.Errorf("expected node_count 7, got %d", cfg.Scenario.NodeCount)
+	}
+}
+
+func TestLoadURLJSONContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"scenario": {"name": "json-remote"}}`))
+	}))
+	defer srv.Close()
+
+	cfg, err := LoadURL(srv.URL+"/config", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("failed to load remote config: %v", err)
+	}
+	if cfg.Scenario.Name != "json-remote" {
+		t.Errorf("expected name 'json-remote', got '%s'", cfg.Scenario.Name)
+	}
+}
+
+func TestLoadURLChecksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(remoteYAML))
+	}))
+	defer srv.Close()
+
+	sum := sha256.Sum256([]byte(remoteYAML))
+
+	opts := DefaultLoadOptions()
+	opts.Checksum = "sha256:" + hex.EncodeToString(sum[:])
+	if _, err := LoadURL(srv.URL+"/scenario.yaml", opts); err != nil {
+		t.Errorf("expected checksum to match: %v", err)
+	}
+
+	opts.Checksum = "0000"
+	if _, err := LoadURL(srv.URL+"/scenario.yaml", opts); err == nil {
+		t.Error("expected error for checksum mismatch")
+	}
+}
+
+func TestLoadURLETagCache(t *testing.T) {
+	var hits, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(remoteYAML))
+	}))
+	defer srv.Close()
+
+	opts := DefaultLoadOptions()
+	opts.CacheDir = t.TempDir()
+
+	for i := range 2 {
+		cfg, err := LoadURL(srv.URL+"/scenario.yaml", opts)
+		if err != nil {
+			t.Fatalf("request %d: failed to load remote config: %v", i, err)
+		}
+		if cfg.Scenario.Name != "remote-scenario" {
+			t.Errorf("request %d: expected name 'remote-scenario', got '%s'", i, cfg.Scenario.Name)
+		}
+	}
+
+	if hits.Load() != 2 {
+		t.Errorf("expected 2 requests, got %d", hits.Load())
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("expected 1 not-modified response, got %d", notModified.Load())
+	}
+}
+
+func TestLoadURLErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	if _, err := LoadURL(srv.URL+"/missing.yaml", DefaultLoadOptions()); err == nil {
+		t.Error("expected error for 404 response")
+	}
+}
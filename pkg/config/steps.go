@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// StepConfig はシナリオのステップの設定（load・wait・inject・heal・assert のいずれか1つを指定する）
+type StepConfig struct {
+	Name   string            `yaml:"name" json:"name"` // ログ・レポートに表示する名前（省略可）
+	Load   *LoadStepConfig   `yaml:"load" json:"load"`
+	Wait   *WaitStepConfig   `yaml:"wait" json:"wait"`
+	Inject *InjectStepConfig `yaml:"inject" json:"inject"`
+	Heal   *SelectorConfig   `yaml:"heal" json:"heal"`
+	Assert *ConditionConfig  `yaml:"assert" json:"assert"`
+}
+
+// LoadStepConfig はワーカー数を変えて一定時間負荷をかけるステップ
+type LoadStepConfig struct {
+	Workers  int    `yaml:"workers" json:"workers"`   // ワーカー数（省略時は変えない）
+	Duration string `yaml:"duration" json:"duration"` // 負荷をかける時間（例: 30s）
+}
+
+// WaitStepConfig は一定時間、または条件を満たすまで待つステップ
+type WaitStepConfig struct {
+	Duration string           `yaml:"duration" json:"duration"` // 待つ時間（until 指定時は上限、省略で無制限）
+	Until    *ConditionConfig `yaml:"until" json:"until"`       // 満たすまで待つ条件
+}
+
+// InjectStepConfig は選択したノードに障害を注入するステップ
+type InjectStepConfig struct {
+	Attack         string `yaml:"attack" json:"attack"` // kill, suspend, delay, readonly, corrupt
+	Delay          string `yaml:"delay" json:"delay"`   // delay の遅延（省略時はカオスモンキーの設定値）
+	SelectorConfig `yaml:",inline"`
+}
+
+// SelectorConfig はステップの対象ノードの選び方（省略時は全ノード）
+type SelectorConfig struct {
+	Nodes []string `yaml:"nodes" json:"nodes"` // ノードID
+	Zone  string   `yaml:"zone" json:"zone"`   // ゾーン
+	Count int      `yaml:"count" json:"count"` // 一致したノードから無作為に選ぶ数（0で全て）
+}
+
+// ConditionConfig はステップで判定する条件（assertions の項目と実行中のノード数の下限）
+type ConditionConfig struct {
+	AssertionsConfig `yaml:",inline"`
+	MinNodesRunning  int `yaml:"min_nodes_running" json:"min_nodes_running"`
+}
+
+// parseSteps はステップの設定を変換し、検証する
+func parseSteps(list []StepConfig) ([]scenario.Step, error) {
+	steps := make([]scenario.Step, 0, len(list))
+	for i, c := range list {
+		step, err := c.toStep()
+		if err == nil {
+			err = step.Validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid steps[%d]: %w", i, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// toStep は設定をステップに変換する
+func (c StepConfig) toStep() (scenario.Step, error) {
+	step := scenario.Step{Name: c.Name}
+	kinds := 0
+	var err error
+
+	if c.Load != nil {
+		kinds++
+		step.Kind = scenario.StepLoad
+		step.Workers = c.Load.Workers
+		step.Duration, err = parseStepDuration("load.duration", c.Load.Duration)
+	}
+	if c.Wait != nil {
+		kinds++
+		step.Kind = scenario.StepWait
+		step.Duration, err = parseStepDuration("wait.duration", c.Wait.Duration)
+		if err == nil && c.Wait.Until != nil {
+			step.Condition, err = c.Wait.Until.toCondition()
+		}
+	}
+	if c.Inject != nil {
+		kinds++
+		step.Kind = scenario.StepInject
+		step.Target = c.Inject.toSelector()
+		var attacks []chaos.AttackType
+		if attacks, err = parseAttackTypes([]string{c.Inject.Attack}); err == nil {
+			step.Attack = attacks[0]
+			step.Delay, err = parseStepDuration("inject.delay", c.Inject.Delay)
+		}
+	}
+	if c.Heal != nil {
+		kinds++
+		step.Kind = scenario.StepHeal
+		step.Target = c.Heal.toSelector()
+	}
+	if c.Assert != nil {
+		kinds++
+		step.Kind = scenario.StepAssert
+		step.Condition, err = c.Assert.toCondition()
+	}
+
+	if kinds != 1 {
+		return step, fmt.Errorf("exactly one of load, wait, inject, heal or assert is required")
+	}
+	return step, err
+}
+
+// toSelector は選び方を変換する
+func (c SelectorConfig) toSelector() scenario.Selector {
+	return scenario.Selector{Nodes: c.Nodes, Zone: c.Zone, Count: c.Count}
+}
+
+// toCondition は条件を変換する
+func (c ConditionConfig) toCondition() (scenario.Condition, error) {
+	if c.MaxErrorRate < 0 || c.MaxErrorRate > 1 {
+		return scenario.Condition{}, fmt.Errorf("max_error_rate must be between 0 and 1")
+	}
+	if c.MinThroughput < 0 {
+		return scenario.Condition{}, fmt.Errorf("min_throughput must be non-negative")
+	}
+	assertions, err := c.AssertionsConfig.ApplyTo(scenario.Assertions{})
+	if err != nil {
+		return scenario.Condition{}, err
+	}
+	return scenario.Condition{Assertions: assertions, MinNodesRunning: c.MinNodesRunning}, nil
+}
+
+// parseStepDuration はステップの時間をパースする（空の場合は0）
+func parseStepDuration(field, s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+func TestStepsConfig(t *testing.T) {
+	data := []byte(`
+scenario:
+  zones: [zone-a, zone-b]
+  steps:
+    - load: {workers: 4, duration: 10s}
+    - name: zone outage
+      inject: {attack: kill, zone: zone-a, count: 1}
+    - inject: {attack: delay, delay: 50ms, nodes: [node-2]}
+    - wait: {duration: 30s, until: {min_nodes_running: 5}}
+    - heal: {}
+    - wait: {duration: 5s}
+    - assert: {max_error_rate: 0.05, max_p99_latency: 20ms}
+`)
+	cfg, err := parse(data, ".yaml", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	scenarioCfg, err := cfg.ToScenarioConfig()
+	if err != nil {
+		t.Fatalf("failed to convert config: %v", err)
+	}
+
+	want := []scenario.Step{
+		{Kind: scenario.StepLoad, Workers: 4, Duration: 10 * time.Second},
+		{Kind: scenario.StepInject, Name: "zone outage", Attack: chaos.AttackKill, Target: scenario.Selector{Zone: "zone-a", Count: 1}},
+		{Kind: scenario.StepInject, Attack: chaos.AttackDelay, Delay: 50 * time.Millisecond, Target: scenario.Selector{Nodes: []string{"node-2"}}},
+		{Kind: scenario.StepWait, Duration: 30 * time.Second, Condition: scenario.Condition{MinNodesRunning: 5}},
+		{Kind: scenario.StepHeal},
+		{Kind: scenario.StepWait, Duration: 5 * time.Second},
+		{Kind: scenario.StepAssert, Condition: scenario.Condition{Assertions: scenario.Assertions{MaxErrorRate: 0.05, MaxP99Latency: 20 * time.Millisecond}}},
+	}
+	if !reflect.DeepEqual(scenarioCfg.Steps, want) {
+		t.Errorf("expected %+v, got %+v", want, scenarioCfg.Steps)
+	}
+	if scenarioCfg.Duration != 0 {
+		t.Errorf("expected the steps to decide the duration, got %v", scenarioCfg.Duration)
+	}
+
+	cfg.Scenario.Duration = "1m"
+	if scenarioCfg, _ := cfg.ToScenarioConfig(); scenarioCfg.Duration != time.Minute {
+		t.Errorf("expected an explicit duration to be kept, got %v", scenarioCfg.Duration)
+	}
+}
+
+func TestStepsConfigJSON(t *testing.T) {
+	data := []byte(`{"scenario": {"steps": [
+		{"inject": {"attack": "suspend", "zone": "zone-b"}},
+		{"assert": {"min_nodes_running": 2, "max_error_rate": 0.1}}
+	]}}`)
+	cfg, err := parse(data, ".json", true)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	steps, err := parseSteps(cfg.Scenario.Steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].Target.Zone != "zone-b" || steps[1].Condition.MinNodesRunning != 2 || steps[1].Condition.MaxErrorRate != 0.1 {
+		t.Errorf("unexpected steps: %+v", steps)
+	}
+}
+
+func TestStepsConfigInvalid(t *testing.T) {
+	tests := []struct {
+		step StepConfig
+		want string
+	}{
+		{StepConfig{}, "exactly one of"},
+		{StepConfig{Load: &LoadStepConfig{Duration: "1s"}, Heal: &SelectorConfig{}}, "exactly one of"},
+		{StepConfig{Load: &LoadStepConfig{Duration: "soon"}}, "invalid load.duration"},
+		{StepConfig{Load: &LoadStepConfig{}}, "positive duration"},
+		{StepConfig{Wait: &WaitStepConfig{}}, "duration or a condition"},
+		{StepConfig{Inject: &InjectStepConfig{Attack: "flood"}}, "unknown attack type"},
+		{StepConfig{Inject: &InjectStepConfig{Attack: "kill", SelectorConfig: SelectorConfig{Count: -1}}}, "count must be non-negative"},
+		{StepConfig{Assert: &ConditionConfig{}}, "requires a condition"},
+		{StepConfig{Assert: &ConditionConfig{AssertionsConfig: AssertionsConfig{MaxErrorRate: 2}}}, "max_error_rate"},
+	}
+	for _, tt := range tests {
+		cfg := &FileConfig{Scenario: ScenarioConfig{Steps: []StepConfig{{Wait: &WaitStepConfig{Duration: "1s"}}, tt.step}}}
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), "steps[1]") || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q for %+v, got %v", tt.want, tt.step, err)
+		}
+	}
+}
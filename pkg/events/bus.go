@@ -0,0 +1,313 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultBufferSize = 100
+
+// Filter reports whether a subscriber should receive an event.
+// Filters run inside Publish, so they must be fast and must not call back into the bus.
+type Filter func(Event) bool
+
+// ByType returns a filter that matches any of the given event types (all events when empty)
+func ByType(types ...EventType) Filter {
+	if len(types) == 0 {
+		return nil
+	}
+	set := make(map[EventType]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	return func(e Event) bool { return set[e.Type] }
+}
+
+// ByNode returns a filter that matches events for any of the given nodes (all events when empty)
+func ByNode(nodeIDs ...string) Filter {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		set[id] = true
+	}
+	return func(e Event) bool { return set[e.NodeID] }
+}
+
+// All returns a filter that matches events accepted by every non-nil filter
+func All(filters ...Filter) Filter {
+	var active []Filter
+	for _, f := range filters {
+		if f != nil {
+			active = append(active, f)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	return func(e Event) bool {
+		for _, f := range active {
+			if !f(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Policy decides what Publish does when a subscriber's buffer is full
+type Policy int
+
+const (
+	// DropNewest discards the event being published (the default)
+	DropNewest Policy = iota
+	// DropOldest discards the oldest buffered event to make room, so a slow
+	// subscriber always sees the most recent events
+	DropOldest
+	// Block waits until the subscriber has room or unsubscribes. Use it for
+	// consumers that must not lose events; a stalled consumer stalls publishers.
+	Block
+)
+
+// String returns the policy name
+func (p Policy) String() string {
+	switch p {
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	case Block:
+		return "block"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// ParsePolicy parses a policy name as returned by Policy.String
+func ParsePolicy(s string) (Policy, error) {
+	switch s {
+	case "", "drop-newest":
+		return DropNewest, nil
+	case "drop-oldest":
+		return DropOldest, nil
+	case "block":
+		return Block, nil
+	default:
+		return DropNewest, fmt.Errorf("unknown backpressure policy: %s (expected drop-newest, drop-oldest or block)", s)
+	}
+}
+
+// SubscribeOptions configures a subscription
+type SubscribeOptions struct {
+	Filter     Filter      // Events to receive (nil for all)
+	Policy     Policy      // Behavior when the buffer is full
+	BufferSize int         // Channel buffer size (0 for the bus default)
+	OnDrop     func(Event) // Called for each dropped event (optional, must be fast)
+}
+
+// subscriber is a single subscription
+type subscriber struct {
+	ch      chan Event
+	options SubscribeOptions
+	dropped atomic.Uint64
+	total   *atomic.Uint64 // Bus-wide drop counter
+
+	// mu guards sends against close; done releases publishers blocked on a full channel
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+// send delivers an event according to the subscriber's policy
+func (s *subscriber) send(event Event) {
+	if s.options.Filter != nil && !s.options.Filter(event) {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- event:
+		return
+	default:
+	}
+
+	switch s.options.Policy {
+	case Block:
+		select {
+		case s.ch <- event:
+		case <-s.done:
+			s.drop(event)
+		}
+	case DropOldest:
+		select {
+		case oldest := <-s.ch:
+			s.drop(oldest)
+		default:
+		}
+		select {
+		case s.ch <- event:
+		default:
+			s.drop(event)
+		}
+	default:
+		s.drop(event)
+	}
+}
+
+// drop counts an undelivered event
+func (s *subscriber) drop(event Event) {
+	s.dropped.Add(1)
+	s.total.Add(1)
+	if s.options.OnDrop != nil {
+		s.options.OnDrop(event)
+	}
+}
+
+// close releases blocked publishers and closes the channel
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.ch)
+	})
+}
+
+// Bus is a simple pub/sub event bus
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[<-chan Event]*subscriber
+	bufferSize  int
+	history     *History
+	dropped     atomic.Uint64
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[<-chan Event]*subscriber),
+		bufferSize:  defaultBufferSize,
+	}
+}
+
+// Subscribe returns a channel that receives events
+func (b *Bus) Subscribe() <-chan Event {
+	return b.SubscribeWith(SubscribeOptions{})
+}
+
+// SubscribeFiltered returns a channel that receives only events accepted by filter
+// (every event when filter is nil). Filtered-out events do not use the channel's buffer.
+func (b *Bus) SubscribeFiltered(filter Filter) <-chan Event {
+	return b.SubscribeWith(SubscribeOptions{Filter: filter})
+}
+
+// SubscribeWith returns a channel configured by options
+func (b *Bus) SubscribeWith(options SubscribeOptions) <-chan Event {
+	size := options.BufferSize
+	if size <= 0 {
+		size = b.bufferSize
+	}
+	sub := &subscriber{
+		ch:      make(chan Event, size),
+		options: options,
+		total:   &b.dropped,
+		done:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub.ch] = sub
+	return sub.ch
+}
+
+// SetHistory makes the bus record every published event in h (nil to stop recording).
+// Unlike a subscriber, the history is updated synchronously and never drops events.
+func (b *Bus) SetHistory(h *History) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.history = h
+}
+
+// History returns the attached history, or nil if none is set
+func (b *Bus) History() *History {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.history
+}
+
+// Unsubscribe removes a subscriber channel
+func (b *Bus) Unsubscribe(ch <-chan Event) {
+	b.mu.Lock()
+	sub, ok := b.subscribers[ch]
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+
+	if ok {
+		sub.close()
+	}
+}
+
+// Publish sends an event to all subscribers. When a subscriber's buffer is
+// full, its policy decides whether the event is dropped or Publish waits.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	history := b.history
+	subs := make([]*subscriber, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		subs = append(subs, sub)
+	}
+	b.mu.RUnlock()
+
+	if history != nil {
+		history.Add(event)
+	}
+	for _, sub := range subs {
+		sub.send(event)
+	}
+}
+
+// Dropped returns the number of events dropped for a subscriber channel
+// (0 once it has been unsubscribed)
+func (b *Bus) Dropped(ch <-chan Event) uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if sub, ok := b.subscribers[ch]; ok {
+		return sub.dropped.Load()
+	}
+	return 0
+}
+
+// TotalDropped returns the number of events dropped across all subscribers,
+// including ones that have since unsubscribed
+func (b *Bus) TotalDropped() uint64 {
+	return b.dropped.Load()
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Bus) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
+
+// Close closes all subscriber channels
+func (b *Bus) Close() {
+	b.mu.Lock()
+	subs := b.subscribers
+	b.subscribers = make(map[<-chan Event]*subscriber)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+}
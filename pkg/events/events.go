@@ -0,0 +1,308 @@
+// Package events provides an event system for chaos and recovery notifications.
+package events
+
+import "time"
+
+// EventType represents the type of event
+type EventType string
+
+const (
+	// EventChaosAttack is emitted when a chaos attack is executed
+	EventChaosAttack EventType = "chaos_attack"
+	// EventChaosResume is emitted when a suspended node is auto-resumed by chaos
+	EventChaosResume EventType = "chaos_resume"
+	// EventRecoveryStart is emitted when recovery attempts to restore a node
+	EventRecoveryStart EventType = "recovery_start"
+	// EventRecoverySuccess is emitted when recovery successfully restores a node
+	EventRecoverySuccess EventType = "recovery_success"
+	// EventRecoveryFailed is emitted when recovery fails to restore a node
+	EventRecoveryFailed EventType = "recovery_failed"
+	// EventNodeStarted is emitted when a node is started after the cluster is up
+	EventNodeStarted EventType = "node_started"
+	// EventNodeStopped is emitted when a node is stopped outside of chaos (e.g. removed)
+	EventNodeStopped EventType = "node_stopped"
+	// EventNodeScaled is emitted when the cluster's node count is changed
+	EventNodeScaled EventType = "node_scaled"
+	// EventClientErrorBurst is emitted when the client's error rate crosses its burst threshold
+	EventClientErrorBurst EventType = "client_error_burst"
+	// EventSLOViolation is emitted when a measured value breaks a service level objective
+	EventSLOViolation EventType = "slo_violation"
+	// EventMetricsSnapshot is emitted periodically with the current workload metrics
+	EventMetricsSnapshot EventType = "metrics_snapshot"
+	// EventScenarioStarted is emitted when a scenario has set up its cluster and starts the workload
+	EventScenarioStarted EventType = "scenario_started"
+	// EventScenarioFinished is emitted when a scenario completes or is interrupted
+	EventScenarioFinished EventType = "scenario_finished"
+)
+
+// Types lists every event type
+var Types = []EventType{
+	EventChaosAttack, EventChaosResume,
+	EventRecoveryStart, EventRecoverySuccess, EventRecoveryFailed,
+	EventNodeStarted, EventNodeStopped, EventNodeScaled,
+	EventClientErrorBurst, EventSLOViolation, EventMetricsSnapshot,
+	EventScenarioStarted, EventScenarioFinished,
+}
+
+// Valid reports whether t is a known event type
+func (t EventType) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AttackType represents the type of chaos attack
+type AttackType string
+
+const (
+	AttackTypeKill     AttackType = "kill"
+	AttackTypeSuspend  AttackType = "suspend"
+	AttackTypeDelay    AttackType = "delay"
+	AttackTypeReadOnly AttackType = "readonly"
+	AttackTypeCorrupt  AttackType = "corrupt"
+)
+
+// Event represents a chaos or recovery event
+type Event struct {
+	Type      EventType `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	NodeID    string    `json:"node_id"`
+	RunID     string    `json:"run_id,omitempty"` // Set by the API server when several runs share one stream
+	Data      EventData `json:"data,omitempty"`
+}
+
+// EventData contains event-specific data
+type EventData struct {
+	AttackType    AttackType `json:"attack_type,omitempty"`
+	DelayDuration string     `json:"delay_duration,omitempty"`
+	CorruptRate   float64    `json:"corrupt_rate,omitempty"`
+	Attempt       int        `json:"attempt,omitempty"`
+	Error         string     `json:"error,omitempty"`
+
+	// Node lifecycle
+	Reason    string   `json:"reason,omitempty"`
+	NodeCount int      `json:"node_count,omitempty"`
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+
+	// Client errors
+	Errors   uint64 `json:"errors,omitempty"`
+	Requests uint64 `json:"requests,omitempty"`
+	Window   string `json:"window,omitempty"`
+
+	// SLO
+	Metric    string  `json:"metric,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Value     float64 `json:"value,omitempty"`
+
+	// Metrics snapshot (Requests and Errors hold the totals so far, NodeCount the cluster size)
+	RPS          float64 `json:"rps,omitempty"`
+	AvgLatencyMs float64 `json:"avg_latency_ms,omitempty"`
+	P99LatencyMs float64 `json:"p99_latency_ms,omitempty"`
+	ErrorRate    float64 `json:"error_rate,omitempty"`
+	NodesRunning int     `json:"nodes_running,omitempty"`
+	Attacks      uint64  `json:"attacks,omitempty"`
+
+	// Scenario lifecycle (scenario_finished also sets Reason and the metrics totals)
+	Scenario string `json:"scenario,omitempty"`
+}
+
+// MetricsSummary is the state carried by a metrics snapshot event
+type MetricsSummary struct {
+	Requests     uint64
+	Errors       uint64
+	RPS          float64
+	AvgLatency   time.Duration
+	P99Latency   time.Duration
+	ErrorRate    float64
+	NodeCount    int
+	NodesRunning int
+	Attacks      uint64
+}
+
+// NewChaosAttackEvent creates a new chaos attack event
+func NewChaosAttackEvent(nodeID string, attackType AttackType) Event {
+	return Event{
+		Type:      EventChaosAttack,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: EventData{
+			AttackType: attackType,
+		},
+	}
+}
+
+// NewChaosAttackEventWithDelay creates a chaos attack event for delay injection
+func NewChaosAttackEventWithDelay(nodeID string, delay time.Duration) Event {
+	return Event{
+		Type:      EventChaosAttack,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: EventData{
+			AttackType:    AttackTypeDelay,
+			DelayDuration: delay.String(),
+		},
+	}
+}
+
+// NewChaosAttackEventWithCorruption creates a chaos attack event for read corruption
+func NewChaosAttackEventWithCorruption(nodeID string, rate float64) Event {
+	return Event{
+		Type:      EventChaosAttack,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: EventData{
+			AttackType:  AttackTypeCorrupt,
+			CorruptRate: rate,
+		},
+	}
+}
+
+// NewChaosResumeEvent creates a chaos resume event
+func NewChaosResumeEvent(nodeID string) Event {
+	return Event{
+		Type:      EventChaosResume,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+	}
+}
+
+// NewRecoveryStartEvent creates a recovery start event
+func NewRecoveryStartEvent(nodeID string, attempt int) Event {
+	return Event{
+		Type:      EventRecoveryStart,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: EventData{
+			Attempt: attempt,
+		},
+	}
+}
+
+// NewRecoverySuccessEvent creates a recovery success event
+func NewRecoverySuccessEvent(nodeID string) Event {
+	return Event{
+		Type:      EventRecoverySuccess,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+	}
+}
+
+// NewRecoveryFailedEvent creates a recovery failed event
+func NewRecoveryFailedEvent(nodeID string, err error) Event {
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	return Event{
+		Type:      EventRecoveryFailed,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: EventData{
+			Error: errMsg,
+		},
+	}
+}
+
+// NewNodeStartedEvent creates a node started event
+func NewNodeStartedEvent(nodeID string) Event {
+	return Event{
+		Type:      EventNodeStarted,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+	}
+}
+
+// NewNodeStoppedEvent creates a node stopped event with the reason it was stopped
+func NewNodeStoppedEvent(nodeID, reason string) Event {
+	return Event{
+		Type:      EventNodeStopped,
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: EventData{
+			Reason: reason,
+		},
+	}
+}
+
+// NewNodeScaledEvent creates a cluster scaling event with the resulting node count
+func NewNodeScaledEvent(nodeCount int, added, removed []string) Event {
+	return Event{
+		Type:      EventNodeScaled,
+		Timestamp: time.Now(),
+		Data: EventData{
+			NodeCount: nodeCount,
+			Added:     added,
+			Removed:   removed,
+		},
+	}
+}
+
+// NewClientErrorBurstEvent creates a client error burst event for the given window
+func NewClientErrorBurstEvent(errors, requests uint64, window time.Duration) Event {
+	return Event{
+		Type:      EventClientErrorBurst,
+		Timestamp: time.Now(),
+		Data: EventData{
+			Errors:   errors,
+			Requests: requests,
+			Window:   window.String(),
+		},
+	}
+}
+
+// NewSLOViolationEvent creates an SLO violation event for a metric that exceeded its threshold
+func NewSLOViolationEvent(metric string, threshold, value float64) Event {
+	return Event{
+		Type:      EventSLOViolation,
+		Timestamp: time.Now(),
+		Data: EventData{
+			Metric:    metric,
+			Threshold: threshold,
+			Value:     value,
+		},
+	}
+}
+
+// NewMetricsSnapshotEvent creates a metrics snapshot event
+func NewMetricsSnapshotEvent(m MetricsSummary) Event {
+	return Event{
+		Type:      EventMetricsSnapshot,
+		Timestamp: time.Now(),
+		Data: EventData{
+			Requests:     m.Requests,
+			Errors:       m.Errors,
+			RPS:          m.RPS,
+			AvgLatencyMs: float64(m.AvgLatency.Microseconds()) / 1000.0,
+			P99LatencyMs: float64(m.P99Latency.Microseconds()) / 1000.0,
+			ErrorRate:    m.ErrorRate,
+			NodeCount:    m.NodeCount,
+			NodesRunning: m.NodesRunning,
+			Attacks:      m.Attacks,
+		},
+	}
+}
+
+// NewScenarioStartedEvent creates a scenario started event
+func NewScenarioStartedEvent(name string) Event {
+	return Event{
+		Type:      EventScenarioStarted,
+		Timestamp: time.Now(),
+		Data: EventData{
+			Scenario: name,
+		},
+	}
+}
+
+// NewScenarioFinishedEvent creates a scenario finished event with the final metrics.
+// reason is "completed" or "interrupted".
+func NewScenarioFinishedEvent(name, reason string, m MetricsSummary) Event {
+	event := NewMetricsSnapshotEvent(m)
+	event.Type = EventScenarioFinished
+	event.Data.Scenario = name
+	event.Data.Reason = reason
+	return event
+}
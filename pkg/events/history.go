@@ -0,0 +1,87 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the number of events kept by NewHistory when size is not positive
+const DefaultHistorySize = 1000
+
+// History keeps the most recent events in a ring buffer so that late consumers
+// can backfill what they missed. Attach it to a bus with Bus.SetHistory.
+type History struct {
+	mu     sync.RWMutex
+	events []Event
+	next   int  // Index of the next write
+	full   bool // Whether the buffer has wrapped
+}
+
+// NewHistory creates a history holding up to size events
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{events: make([]Event, size)}
+}
+
+// Add records an event, overwriting the oldest one when full
+func (h *History) Add(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Len returns the number of events currently held
+func (h *History) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.full {
+		return len(h.events)
+	}
+	return h.next
+}
+
+// HistoryQuery selects events from a History. Zero fields match everything.
+type HistoryQuery struct {
+	Since  time.Time   // Only events after this time
+	Until  time.Time   // Only events at or before this time
+	Types  []EventType // Only these event types
+	NodeID string      // Only events for this node
+	RunID  string      // Only events from this run
+	Limit  int         // Keep only the newest Limit matches
+}
+
+// Query returns the matching events, oldest first
+func (h *History) Query(q HistoryQuery) []Event {
+	filter := All(ByType(q.Types...), func(e Event) bool {
+		return (q.Since.IsZero() || e.Timestamp.After(q.Since)) &&
+			(q.Until.IsZero() || !e.Timestamp.After(q.Until)) &&
+			(q.NodeID == "" || e.NodeID == q.NodeID) &&
+			(q.RunID == "" || e.RunID == q.RunID)
+	})
+
+	h.mu.RLock()
+	ordered := h.events[:h.next]
+	if h.full {
+		ordered = append(append([]Event{}, h.events[h.next:]...), h.events[:h.next]...)
+	}
+	result := make([]Event, 0, len(ordered))
+	for _, e := range ordered {
+		if filter(e) {
+			result = append(result, e)
+		}
+	}
+	h.mu.RUnlock()
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result
+}
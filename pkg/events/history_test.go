@@ -0,0 +1,68 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistoryWraparound(t *testing.T) {
+	h := NewHistory(3)
+	for _, id := range []string{"node-1", "node-2", "node-3", "node-4"} {
+		h.Add(NewChaosAttackEvent(id, AttackTypeKill))
+	}
+
+	if h.Len() != 3 {
+		t.Errorf("expected 3 events, got %d", h.Len())
+	}
+	got := h.Query(HistoryQuery{})
+	if len(got) != 3 || got[0].NodeID != "node-2" || got[2].NodeID != "node-4" {
+		t.Errorf("expected the 3 newest events oldest first, got %+v", got)
+	}
+}
+
+func TestHistoryQuery(t *testing.T) {
+	base := time.Now()
+	h := NewHistory(10)
+	h.Add(Event{Type: EventChaosAttack, Timestamp: base, NodeID: "node-1", RunID: "a"})
+	h.Add(Event{Type: EventRecoveryStart, Timestamp: base.Add(time.Second), NodeID: "node-1", RunID: "a"})
+	h.Add(Event{Type: EventRecoverySuccess, Timestamp: base.Add(2 * time.Second), NodeID: "node-2", RunID: "b"})
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		want  int
+	}{
+		{"all", HistoryQuery{}, 3},
+		{"since", HistoryQuery{Since: base}, 2},
+		{"until", HistoryQuery{Until: base.Add(time.Second)}, 2},
+		{"types", HistoryQuery{Types: []EventType{EventRecoveryStart, EventRecoverySuccess}}, 2},
+		{"node", HistoryQuery{NodeID: "node-2"}, 1},
+		{"run", HistoryQuery{RunID: "a"}, 2},
+		{"limit", HistoryQuery{Limit: 1}, 1},
+	}
+	for _, tt := range tests {
+		if got := h.Query(tt.query); len(got) != tt.want {
+			t.Errorf("%s: expected %d events, got %d", tt.name, tt.want, len(got))
+		}
+	}
+
+	if got := h.Query(HistoryQuery{Limit: 1}); got[0].Type != EventRecoverySuccess {
+		t.Errorf("expected limit to keep the newest event, got %s", got[0].Type)
+	}
+}
+
+func TestBusHistory(t *testing.T) {
+	bus := NewBus()
+	if bus.History() != nil {
+		t.Error("expected no history by default")
+	}
+
+	h := NewHistory(10)
+	bus.SetHistory(h)
+	bus.Publish(NewChaosAttackEvent("node-1", AttackTypeKill))
+
+	// Recorded synchronously even without subscribers
+	if bus.History().Len() != 1 {
+		t.Errorf("expected 1 recorded event, got %d", h.Len())
+	}
+}
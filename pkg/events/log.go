@@ -0,0 +1,86 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLogLineSize is the longest event log line ReadLog accepts
+const maxLogLineSize = 1 << 20
+
+// WriteLog writes events as JSONL (one JSON-encoded event per line)
+func WriteLog(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for i, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("failed to write event %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ReadLog parses a JSONL event log as written by WriteLog. Blank lines are skipped.
+func ReadLog(r io.Reader) ([]Event, error) {
+	var events []Event
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(text), &event); err != nil {
+			return nil, fmt.Errorf("invalid event at line %d: %w", line, err)
+		}
+		if event.Type == "" {
+			return nil, fmt.Errorf("invalid event at line %d: missing type", line)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return events, nil
+}
+
+// Replay publishes events onto bus, preserving the original gaps between their
+// timestamps divided by speed (2 plays twice as fast). A speed of 0 or less
+// publishes everything without waiting. Events keep their original timestamps.
+// It returns the number of events published before ctx was cancelled.
+func Replay(ctx context.Context, bus *Bus, events []Event, speed float64) (int, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	start := time.Now()
+	origin := events[0].Timestamp
+	for i, event := range events {
+		if speed > 0 {
+			offset := time.Duration(float64(event.Timestamp.Sub(origin)) / speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return i, ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		bus.Publish(event)
+	}
+	return len(events), nil
+}
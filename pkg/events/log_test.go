@@ -0,0 +1,79 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogRoundTrip(t *testing.T) {
+	events := []Event{
+		NewChaosAttackEvent("node-1", AttackTypeKill),
+		NewRecoveryFailedEvent("node-1", errors.New("boom")),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLog(&buf, events); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("expected one line per event, got %d", lines)
+	}
+
+	buf.WriteString("\n")
+	read, err := ReadLog(&buf)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if len(read) != 2 || read[1].Data.Error != "boom" || !read[0].Timestamp.Equal(events[0].Timestamp) {
+		t.Errorf("unexpected events: %+v", read)
+	}
+}
+
+func TestReadLogInvalid(t *testing.T) {
+	for _, input := range []string{"{\"type\":\"chaos_attack\"}\nnot json", `{"node_id":"node-1"}`} {
+		if _, err := ReadLog(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	base := time.Now()
+	events := []Event{
+		{Type: EventChaosAttack, Timestamp: base, NodeID: "node-1"},
+		{Type: EventRecoveryStart, Timestamp: base.Add(200 * time.Millisecond), NodeID: "node-1"},
+		{Type: EventRecoverySuccess, Timestamp: base.Add(400 * time.Millisecond), NodeID: "node-1"},
+	}
+
+	bus := NewBus()
+	ch := bus.Subscribe()
+
+	start := time.Now()
+	n, err := Replay(context.Background(), bus, events, 4)
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 events replayed, got %d, %v", n, err)
+	}
+	// 400ms of events at 4x speed takes about 100ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected accelerated timing around 100ms, took %v", elapsed)
+	}
+	for i := range events {
+		if e := <-ch; e.Type != events[i].Type {
+			t.Errorf("event %d: expected %s, got %s", i, events[i].Type, e.Type)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n, err := Replay(ctx, bus, events, 1); !errors.Is(err, context.Canceled) || n != 0 {
+		t.Errorf("expected cancelled replay, got %d, %v", n, err)
+	}
+
+	if n, err := Replay(context.Background(), bus, events, 0); err != nil || n != 3 {
+		t.Errorf("expected instant replay of 3 events, got %d, %v", n, err)
+	}
+}
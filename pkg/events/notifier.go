@@ -0,0 +1,103 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NotifierKind selects the chat service a notifier posts to
+type NotifierKind string
+
+const (
+	NotifierSlack   NotifierKind = "slack"
+	NotifierDiscord NotifierKind = "discord"
+)
+
+// DefaultNotifyTypes are the events posted when NotifierConfig.Types is empty
+var DefaultNotifyTypes = []EventType{EventScenarioStarted, EventScenarioFinished, EventSLOViolation}
+
+// DefaultNotifyTemplate renders one line per event. Templates are executed
+// with the Event, so fields such as {{.Type}}, {{.NodeID}} and {{.Data.Scenario}}
+// are available.
+const DefaultNotifyTemplate = `[chaos-kvs] {{.Type}}` +
+	`{{with .Data.Scenario}} "{{.}}"{{end}}` +
+	`{{with .NodeID}} on {{.}}{{end}}` +
+	`{{with .Data.Reason}} ({{.}}){{end}}` +
+	`{{with .Data.Metric}}: {{.}} = {{$.Data.Value}} (threshold {{$.Data.Threshold}}){{end}}` +
+	`{{if .Data.Requests}}: {{.Data.Requests}} requests, {{.Data.Errors}} errors{{end}}`
+
+// NotifierConfig configures a chat notifier
+type NotifierConfig struct {
+	Kind     NotifierKind // slack or discord
+	URL      string       // Incoming webhook URL
+	Types    []EventType  // Events to post (DefaultNotifyTypes when empty)
+	Template string       // text/template for the message (DefaultNotifyTemplate when empty)
+}
+
+// Validate checks the kind, URL and template
+func (c NotifierConfig) Validate() error {
+	if c.Kind != NotifierSlack && c.Kind != NotifierDiscord {
+		return fmt.Errorf("unknown notifier kind: %s (expected slack or discord)", c.Kind)
+	}
+	if c.URL == "" {
+		return fmt.Errorf("%s notifier URL is required", c.Kind)
+	}
+	for _, t := range c.Types {
+		if !t.Valid() {
+			return fmt.Errorf("unknown event type: %s", t)
+		}
+	}
+	_, err := c.template()
+	return err
+}
+
+// template parses the configured or default message template
+func (c NotifierConfig) template() (*template.Template, error) {
+	text := c.Template
+	if text == "" {
+		text = DefaultNotifyTemplate
+	}
+	tmpl, err := template.New("notify").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// NewNotifier subscribes to bus and posts the selected events to a Slack or
+// Discord incoming webhook, one message per event. Close the returned sink to
+// flush pending messages.
+func NewNotifier(bus *Bus, config NotifierConfig) (*WebhookSink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	tmpl, _ := config.template()
+
+	types := config.Types
+	if len(types) == 0 {
+		types = DefaultNotifyTypes
+	}
+
+	// Slack expects {"text": ...} and Discord {"content": ...}
+	field := "text"
+	if config.Kind == NotifierDiscord {
+		field = "content"
+	}
+
+	webhook := DefaultWebhookConfig(config.URL)
+	webhook.Filter = ByType(types...)
+	webhook.Encode = func(batch []Event) ([]byte, error) {
+		lines := make([]string, 0, len(batch))
+		for _, event := range batch {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, event); err != nil {
+				return nil, fmt.Errorf("failed to render notification: %w", err)
+			}
+			lines = append(lines, b.String())
+		}
+		return json.Marshal(map[string]string{field: strings.Join(lines, "\n")})
+	}
+	return NewWebhookSink(bus, webhook)
+}
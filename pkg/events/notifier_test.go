@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestNotifierConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  NotifierConfig
+		wantErr bool
+	}{
+		{"slack", NotifierConfig{Kind: NotifierSlack, URL: "http://example.com"}, false},
+		{"discord with template", NotifierConfig{Kind: NotifierDiscord, URL: "http://example.com", Template: "{{.Type}}"}, false},
+		{"unknown kind", NotifierConfig{Kind: "teams", URL: "http://example.com"}, true},
+		{"missing URL", NotifierConfig{Kind: NotifierSlack}, true},
+		{"unknown event type", NotifierConfig{Kind: NotifierSlack, URL: "http://example.com", Types: []EventType{"node_quarantined"}}, true},
+		{"bad template", NotifierConfig{Kind: NotifierSlack, URL: "http://example.com", Template: "{{.Type"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNotifierPostsMessages(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []map[string]string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	bus := NewBus()
+	slack, err := NewNotifier(bus, NotifierConfig{Kind: NotifierSlack, URL: ts.URL})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+	discord, err := NewNotifier(bus, NotifierConfig{
+		Kind:     NotifierDiscord,
+		URL:      ts.URL,
+		Types:    []EventType{EventChaosAttack},
+		Template: "{{.NodeID}} hit by {{.Data.AttackType}}",
+	})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+
+	bus.Publish(NewScenarioStartedEvent("nightly"))
+	bus.Publish(NewChaosAttackEvent("node-1", AttackTypeKill))
+	bus.Publish(NewSLOViolationEvent("error_rate", 0.01, 0.2))
+
+	for _, sink := range []*WebhookSink{slack, discord} {
+		if err := sink.Close(context.Background()); err != nil {
+			t.Fatalf("failed to close notifier: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var texts, contents []string
+	for _, b := range bodies {
+		if v, ok := b["text"]; ok {
+			texts = append(texts, v)
+		}
+		if v, ok := b["content"]; ok {
+			contents = append(contents, v)
+		}
+	}
+
+	want := []string{
+		`[chaos-kvs] scenario_started "nightly"`,
+		`[chaos-kvs] slo_violation: error_rate = 0.2 (threshold 0.01)`,
+	}
+	if len(texts) != 2 || texts[0] != want[0] || texts[1] != want[1] {
+		t.Errorf("expected slack messages %q, got %q", want, texts)
+	}
+	if len(contents) != 1 || contents[0] != "node-1 hit by kill" {
+		t.Errorf("expected discord message for the attack, got %q", contents)
+	}
+}
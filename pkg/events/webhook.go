@@ -0,0 +1,225 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WebhookConfig configures a WebhookSink
+type WebhookConfig struct {
+	URL     string            // Endpoint that receives POSTed events
+	Headers map[string]string // Extra request headers (e.g. Authorization)
+	Filter  Filter            // Events to deliver (nil for all)
+
+	// Batching: a batch is sent when it reaches BatchSize events or FlushInterval
+	// has passed since its first event. With BatchSize 1 each event is sent as a
+	// JSON object; otherwise batches are sent as a JSON array.
+	BatchSize     int
+	FlushInterval time.Duration
+
+	// Delivery
+	Timeout      time.Duration // Per-request timeout
+	MaxRetries   int           // Retries after the first attempt for network errors, 429 and 5xx
+	RetryBackoff time.Duration // Initial backoff, doubled after each retry
+	HTTPClient   *http.Client  // Custom HTTP client (optional, overrides Timeout)
+
+	// Encode builds the request body for a batch (optional). By default events
+	// are sent as JSON as described above.
+	Encode func(batch []Event) ([]byte, error)
+}
+
+// DefaultWebhookConfig returns the default webhook settings for url
+func DefaultWebhookConfig(url string) WebhookConfig {
+	return WebhookConfig{
+		URL:           url,
+		BatchSize:     1,
+		FlushInterval: time.Second,
+		Timeout:       5 * time.Second,
+		MaxRetries:    3,
+		RetryBackoff:  500 * time.Millisecond,
+	}
+}
+
+// WebhookStats contains delivery counters of a WebhookSink
+type WebhookStats struct {
+	Delivered uint64 // Events accepted by the endpoint
+	Failed    uint64 // Events dropped after exhausting retries
+}
+
+// WebhookSink forwards events from a bus to an HTTP endpoint
+type WebhookSink struct {
+	config WebhookConfig
+	client *http.Client
+	bus    *Bus
+	ch     <-chan Event
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+
+	delivered atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// NewWebhookSink subscribes to bus and starts delivering events to config.URL
+func NewWebhookSink(bus *Bus, config WebhookConfig) (*WebhookSink, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = 1
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &WebhookSink{
+		config: config,
+		client: client,
+		bus:    bus,
+		ch:     bus.SubscribeFiltered(config.Filter),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.loop()
+	return s, nil
+}
+
+// Stats returns the delivery counters
+func (s *WebhookSink) Stats() WebhookStats {
+	return WebhookStats{
+		Delivered: s.delivered.Load(),
+		Failed:    s.failed.Load(),
+	}
+}
+
+// Close unsubscribes from the bus and sends any pending events. If ctx expires
+// first, in-flight deliveries are aborted.
+func (s *WebhookSink) Close(ctx context.Context) error {
+	s.once.Do(func() { s.bus.Unsubscribe(s.ch) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+// loop batches events and delivers them until the subscription is closed
+func (s *WebhookSink) loop() {
+	defer close(s.done)
+	defer s.cancel()
+
+	var batch []Event
+	timer := time.NewTimer(s.config.FlushInterval)
+	timer.Stop()
+
+	flush := func() {
+		if len(batch) > 0 {
+			s.deliver(batch)
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-s.ch:
+			if !ok {
+				timer.Stop()
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= s.config.BatchSize {
+				timer.Stop()
+				flush()
+			} else if len(batch) == 1 {
+				timer.Reset(s.config.FlushInterval)
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff
+func (s *WebhookSink) deliver(batch []Event) {
+	var body []byte
+	var err error
+	switch {
+	case s.config.Encode != nil:
+		body, err = s.config.Encode(batch)
+	case s.config.BatchSize == 1:
+		body, err = json.Marshal(batch[0])
+	default:
+		body, err = json.Marshal(batch)
+	}
+	if err != nil {
+		s.failed.Add(uint64(len(batch)))
+		return
+	}
+
+	backoff := s.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			s.delivered.Add(uint64(len(batch)))
+			return
+		}
+		if !retry || attempt >= s.config.MaxRetries {
+			s.failed.Add(uint64(len(batch)))
+			return
+		}
+
+		select {
+		case <-s.ctx.Done():
+			s.failed.Add(uint64(len(batch)))
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one request and reports whether a failure is worth retrying
+func (s *WebhookSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return s.ctx.Err() == nil, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSinkRequiresURL(t *testing.T) {
+	if _, err := NewWebhookSink(NewBus(), DefaultWebhookConfig("")); err == nil {
+		t.Error("expected error for empty URL")
+	}
+}
+
+func TestWebhookSinkDelivers(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+	var calls atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first request to exercise retries
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected custom header, got %q", r.Header.Get("Authorization"))
+		}
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("expected single event object: %v", err)
+		}
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	bus := NewBus()
+	config := DefaultWebhookConfig(ts.URL)
+	config.Headers = map[string]string{"Authorization": "Bearer secret"}
+	config.Filter = ByType(EventChaosAttack)
+	config.RetryBackoff = time.Millisecond
+	sink, err := NewWebhookSink(bus, config)
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	bus.Publish(NewChaosAttackEvent("node-1", AttackTypeKill))
+	bus.Publish(NewRecoverySuccessEvent("node-1"))
+	bus.Publish(NewChaosAttackEvent("node-2", AttackTypeSuspend))
+
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close sink: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0].NodeID != "node-1" || received[1].NodeID != "node-2" {
+		t.Errorf("expected 2 chaos events in order, got %+v", received)
+	}
+	if stats := sink.Stats(); stats.Delivered != 2 || stats.Failed != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestWebhookSinkBatches(t *testing.T) {
+	var batches atomic.Int32
+	var events atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []Event
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("expected event array: %v", err)
+		}
+		batches.Add(1)
+		events.Add(int32(len(batch)))
+	}))
+	defer ts.Close()
+
+	bus := NewBus()
+	config := DefaultWebhookConfig(ts.URL)
+	config.BatchSize = 2
+	config.FlushInterval = 20 * time.Millisecond
+	sink, err := NewWebhookSink(bus, config)
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(NewChaosAttackEvent("node-1", AttackTypeKill))
+	}
+
+	// The third event is flushed by the interval rather than by Close
+	deadline := time.Now().Add(time.Second)
+	for events.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = sink.Close(context.Background())
+
+	if batches.Load() != 2 || events.Load() != 3 {
+		t.Errorf("expected 3 events in 2 batches, got %d in %d", events.Load(), batches.Load())
+	}
+}
+
+func TestWebhookSinkGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	bus := NewBus()
+	config := DefaultWebhookConfig(ts.URL)
+	config.RetryBackoff = time.Millisecond
+	sink, _ := NewWebhookSink(bus, config)
+
+	bus.Publish(NewChaosAttackEvent("node-1", AttackTypeKill))
+	_ = sink.Close(context.Background())
+
+	// Client errors are not retried
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls.Load())
+	}
+	if stats := sink.Stats(); stats.Failed != 1 {
+		t.Errorf("expected 1 failed event, got %+v", stats)
+	}
+}
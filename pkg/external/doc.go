@@ -0,0 +1,40 @@
+// Package external lets the chaos, recovery and scenario framework target
+// processes running outside chaos-kvs, such as a Redis server in a local
+// container, instead of only the in-process nodes.
+//
+// # Basic Usage
+//
+//	config := external.DefaultConfig()
+//	config.ID = "redis-1"
+//	config.Addr = "127.0.0.1:6379"
+//	config.Commands = external.Commands{
+//	    Start:   "docker start redis-1",
+//	    Stop:    "docker kill redis-1",
+//	    Suspend: "docker pause redis-1",
+//	    Resume:  "docker unpause redis-1",
+//	}
+//
+//	n := node.New(config.ID)
+//	n.SetBackend(external.New(config))
+//
+// A Process is a node.Backend: the node runs the matching command before it
+// changes state, so a kill attack runs Stop, recovery runs Start, and so on.
+// If a command fails, the node keeps its state and the attack or recovery is
+// reported as failed. The load generator reaches the process itself over RESP
+// at Addr (see resp.Client), so the traffic sees the real outage.
+//
+// # Commands
+//
+// Each command is run with "sh -c" and a timeout, with these variables added
+// to the environment:
+//
+//   - CHAOS_KVS_NODE: the node ID
+//   - CHAOS_KVS_ADDR: the node address
+//   - CHAOS_KVS_DELAY_MS: the delay to inject in milliseconds (Delay only,
+//     0 clears it)
+//
+// An empty Start command means the process is started outside chaos-kvs and
+// starting it succeeds without doing anything. Any other empty command makes
+// that operation fail, so attacks that cannot be carried out show up as
+// failures instead of being silently skipped.
+package external
@@ -0,0 +1,139 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// log はコンポーネント名 "external" を付けてログを出力する子ロガー
+var log = logger.With("external")
+
+// maxOutputInError はエラーに含めるコマンドの出力の最大バイト数
+const maxOutputInError = 256
+
+// 環境変数名
+const (
+	EnvNode    = "CHAOS_KVS_NODE"
+	EnvAddr    = "CHAOS_KVS_ADDR"
+	EnvDelayMS = "CHAOS_KVS_DELAY_MS"
+)
+
+// Commands は外部のプロセスを操作するシェルコマンド
+type Commands struct {
+	Start   string // 起動（空の場合は外部で起動済みとみなし何もしない）
+	Stop    string // 強制停止（kill 攻撃）
+	Suspend string // 一時停止（suspend 攻撃）
+	Resume  string // 再開
+	Delay   string // 遅延の注入（CHAOS_KVS_DELAY_MS が0の場合は解除）
+}
+
+// Config は外部のプロセスの設定
+type Config struct {
+	ID       string        // ノードID
+	Addr     string        // RESPで接続するアドレス（例: 127.0.0.1:6379）
+	Commands Commands      // 操作するコマンド
+	Timeout  time.Duration // 1つのコマンドの上限時間（0で無制限）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Process はシェルコマンドで操作する外部のプロセス
+type Process struct {
+	config Config
+}
+
+// Ensure Process implements node.Backend
+var _ node.Backend = (*Process)(nil)
+
+// New は外部のプロセスを作成する
+func New(config Config) *Process {
+	return &Process{config: config}
+}
+
+// ID はノードIDを返す
+func (p *Process) ID() string {
+	return p.config.ID
+}
+
+// Addr はRESPで接続するアドレスを返す
+func (p *Process) Addr() string {
+	return p.config.Addr
+}
+
+// Start はプロセスを起動する
+func (p *Process) Start(ctx context.Context) error {
+	if p.config.Commands.Start == "" {
+		return nil
+	}
+	return p.run(ctx, "start", p.config.Commands.Start)
+}
+
+// Stop はプロセスを強制停止する
+func (p *Process) Stop() error {
+	return p.run(context.Background(), "stop", p.config.Commands.Stop)
+}
+
+// Suspend はプロセスを一時停止する
+func (p *Process) Suspend() error {
+	return p.run(context.Background(), "suspend", p.config.Commands.Suspend)
+}
+
+// Resume は一時停止したプロセスを再開する
+func (p *Process) Resume() error {
+	return p.run(context.Background(), "resume", p.config.Commands.Resume)
+}
+
+// SetDelay はプロセスに遅延を注入する（0で解除）
+func (p *Process) SetDelay(d time.Duration) error {
+	return p.run(context.Background(), "delay", p.config.Commands.Delay,
+		EnvDelayMS+"="+strconv.FormatInt(d.Milliseconds(), 10))
+}
+
+// run はコマンドを sh -c で実行する。失敗した場合は出力の先頭をエラーに含める
+func (p *Process) run(ctx context.Context, op, command string, env ...string) error {
+	if command == "" {
+		return fmt.Errorf("no %s command configured for %s", op, p.config.ID)
+	}
+	if p.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), EnvNode+"="+p.config.ID, EnvAddr+"="+p.config.Addr)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		out = bytes.TrimSpace(out)
+		if len(out) > maxOutputInError {
+			out = append(out[:maxOutputInError], "..."...)
+		}
+		if len(out) > 0 {
+			return fmt.Errorf("%s command failed: %w: %s", op, err, out)
+		}
+		return fmt.Errorf("%s command failed: %w", op, err)
+	}
+
+	log.Debug(p.config.ID, "Ran %s command in %v", op, time.Since(start).Round(time.Millisecond))
+	return nil
+}
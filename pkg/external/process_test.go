@@ -0,0 +1,135 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// newTestProcess は各コマンドが操作名と環境変数を log に追記するプロセスを作成する
+func newTestProcess(t *testing.T) (*Process, string) {
+	t.Helper()
+	logFile := filepath.Join(t.TempDir(), "ops.log")
+	record := func(op string) string {
+		return "echo " + op + " $" + EnvNode + " $" + EnvAddr + " $" + EnvDelayMS + " >> " + logFile
+	}
+
+	config := DefaultConfig()
+	config.ID = "redis-1"
+	config.Addr = "127.0.0.1:6379"
+	config.Commands = Commands{
+		Start:   record("start"),
+		Stop:    record("stop"),
+		Suspend: record("suspend"),
+		Resume:  record("resume"),
+		Delay:   record("delay"),
+	}
+	return New(config), logFile
+}
+
+// readOps は記録された操作を行ごとに返す
+func readOps(t *testing.T, logFile string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestProcessCommands(t *testing.T) {
+	p, logFile := newTestProcess(t)
+
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetDelay(150 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"start redis-1 127.0.0.1:6379",
+		"delay redis-1 127.0.0.1:6379 150",
+		"stop redis-1 127.0.0.1:6379",
+	}
+	got := readOps(t, logFile)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestProcessAsNodeBackend(t *testing.T) {
+	p, logFile := newTestProcess(t)
+	n := node.New(p.ID())
+	n.SetBackend(p)
+
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readOps(t, logFile)
+	if len(got) != 3 || !strings.HasPrefix(got[1], "suspend") || !strings.HasPrefix(got[2], "resume") {
+		t.Errorf("unexpected operations: %q", got)
+	}
+}
+
+func TestProcessMissingCommands(t *testing.T) {
+	config := DefaultConfig()
+	config.ID = "redis-1"
+	p := New(config)
+
+	// 起動のコマンドがない場合は外部で起動済みとみなす
+	if err := p.Start(context.Background()); err != nil {
+		t.Errorf("expected start without command to succeed: %v", err)
+	}
+	if err := p.Stop(); err == nil || !strings.Contains(err.Error(), "no stop command") {
+		t.Errorf("expected missing stop command error, got %v", err)
+	}
+	if err := p.SetDelay(time.Second); err == nil {
+		t.Error("expected missing delay command error")
+	}
+}
+
+func TestProcessCommandFailure(t *testing.T) {
+	config := DefaultConfig()
+	config.ID = "redis-1"
+	config.Commands.Stop = "echo 'No such container: redis-1' >&2; exit 1"
+	p := New(config)
+
+	err := p.Stop()
+	if err == nil || !strings.Contains(err.Error(), "No such container") {
+		t.Errorf("expected error with command output, got %v", err)
+	}
+}
+
+func TestProcessTimeout(t *testing.T) {
+	config := DefaultConfig()
+	config.ID = "redis-1"
+	config.Commands.Stop = "sleep 5"
+	config.Timeout = 50 * time.Millisecond
+	p := New(config)
+
+	start := time.Now()
+	err := p.Stop()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("command was not killed on timeout (took %v)", elapsed)
+	}
+}
@@ -0,0 +1,32 @@
+// Package history はシナリオ実行結果の履歴を管理する。
+//
+// 完了したシナリオの結果と、実行中に発生したカオス/復旧イベントの
+// タイムラインを保持し、過去の実験の参照や比較に使用する。
+// ディレクトリを指定した場合は各実行がJSONファイルとして保存され、
+// 再起動後も読み込まれる。
+//
+// Database を指定した場合は SQLite に実行記録・秒ごとのメトリクス
+// （metrics_snapshot イベント）・イベントを保存し、DB の Runs・Metrics・
+// Events でインデックスを使って検索できる。SQLite のドライバは cgo を
+// 使用するため cgo を有効にしたビルドでのみ組み込まれ、CGO_ENABLED=0 で
+// ビルドした場合は依存に含まれず、Database を指定するとエラーになる。
+//
+// # 使用例
+//
+//	store, err := history.NewStore(history.DefaultConfig())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	recorder := history.NewRecorder(bus)
+//	result, _ := engine.Run(ctx)
+//	timeline := recorder.Stop()
+//
+//	run := history.NewRun(config, result, timeline)
+//	_ = store.Add(run)
+//
+//	// SQLite に保存し、実行のメトリクスを検索する
+//	store, _ = history.NewStore(history.Config{Database: "history.db"})
+//	defer store.Close()
+//	points, _ := store.DB().Metrics(run.ID)
+package history
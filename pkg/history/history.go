@@ -0,0 +1,332 @@
+package history
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// log はコンポーネント名 "history" を付けてログを出力する子ロガー
+var log = logger.With("history")
+
+// Config は履歴ストアの設定
+type Config struct {
+	Dir      string // 保存先ディレクトリ（空でメモリのみ）
+	Database string // 保存先の SQLite データベースのパス（空で使用しない、Dir とは併用不可）
+	MaxRuns  int    // 保持する最大実行数（0で無制限）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Dir:      "",
+		Database: "",
+		MaxRuns:  100,
+	}
+}
+
+// Run は1回のシナリオ実行の記録
+type Run struct {
+	ID       string           `json:"id"`
+	Config   scenario.Config  `json:"config"`
+	Result   *scenario.Result `json:"result"`
+	Timeline []events.Event   `json:"timeline"`
+}
+
+// Summary は実行一覧用の概要
+type Summary struct {
+	ID            string        `json:"id"`
+	ScenarioName  string        `json:"scenario_name"`
+	StartTime     time.Time     `json:"start_time"`
+	EndTime       time.Time     `json:"end_time"`
+	Duration      time.Duration `json:"duration"`
+	Interrupted   bool          `json:"interrupted"`
+	TotalRequests uint64        `json:"total_requests"`
+	ErrorRate     float64       `json:"error_rate"`
+	TotalAttacks  uint64        `json:"total_attacks"`
+	EventCount    int           `json:"event_count"`
+}
+
+// NewRun は新しいIDを割り当てた実行記録を作成する
+func NewRun(config scenario.Config, result *scenario.Result, timeline []events.Event) *Run {
+	start := time.Now()
+	if result != nil {
+		start = result.StartTime
+	}
+	return &Run{
+		ID:       NewRunID(start),
+		Config:   config,
+		Result:   result,
+		Timeline: timeline,
+	}
+}
+
+// NewRunID は開始時刻とランダムな接尾辞から実行IDを生成する
+func NewRunID(t time.Time) string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return t.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// Summary は実行の概要を返す
+func (r *Run) Summary() Summary {
+	s := Summary{
+		ID:         r.ID,
+		EventCount: len(r.Timeline),
+	}
+	if r.Result != nil {
+		s.ScenarioName = r.Result.ScenarioName
+		s.StartTime = r.Result.StartTime
+		s.EndTime = r.Result.EndTime
+		s.Duration = r.Result.Duration
+		s.Interrupted = r.Result.Interrupted
+		s.TotalRequests = r.Result.TotalRequests
+		s.ErrorRate = r.Result.ErrorRate
+		s.TotalAttacks = r.Result.TotalAttacks
+	}
+	return s
+}
+
+// Store は実行履歴を保持する
+type Store struct {
+	config Config
+	db     *DB // Database 指定時の保存先
+
+	mu   sync.RWMutex
+	runs []*Run // 古い順
+	byID map[string]*Run
+}
+
+// NewStore は新しい履歴ストアを作成する
+// Dir・Database が指定されている場合は既存の実行記録を読み込む
+func NewStore(config Config) (*Store, error) {
+	s := &Store{
+		config: config,
+		byID:   make(map[string]*Run),
+	}
+
+	if config.Dir != "" && config.Database != "" {
+		return nil, fmt.Errorf("history dir and database cannot be used together")
+	}
+	if config.Database != "" {
+		db, err := OpenDB(config.Database)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.loadDB(db); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create history dir: %w", err)
+		}
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// load はディレクトリから実行記録を読み込む
+func (s *Store) load() error {
+	entries, err := os.ReadDir(s.config.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read history dir: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.config.Dir, e.Name()))
+		if err != nil {
+			log.Warn("", "Failed to read run %s: %v", e.Name(), err)
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil || run.ID == "" {
+			log.Warn("", "Skipping invalid run file %s", e.Name())
+			continue
+		}
+		s.runs = append(s.runs, &run)
+		s.byID[run.ID] = &run
+	}
+
+	sort.Slice(s.runs, func(i, j int) bool {
+		return s.runs[i].Summary().StartTime.Before(s.runs[j].Summary().StartTime)
+	})
+	s.evict()
+
+	log.Info("", "Loaded %d runs from %s", len(s.runs), s.config.Dir)
+	return nil
+}
+
+// loadDB はデータベースから実行記録を読み込む
+func (s *Store) loadDB(db *DB) error {
+	runs, err := db.Load()
+	if err != nil {
+		return err
+	}
+	s.db = db
+	for _, run := range runs {
+		s.runs = append(s.runs, run)
+		s.byID[run.ID] = run
+	}
+	s.evict()
+
+	log.Info("", "Loaded %d runs from %s", len(s.runs), s.config.Database)
+	return nil
+}
+
+// Add は実行記録を追加する
+func (s *Store) Add(run *Run) error {
+	if run == nil || run.ID == "" {
+		return fmt.Errorf("run must have an ID")
+	}
+
+	if s.config.Dir != "" {
+		data, err := json.MarshalIndent(run, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode run: %w", err)
+		}
+		if err := os.WriteFile(s.runPath(run.ID), data, 0644); err != nil {
+			return fmt.Errorf("failed to write run: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byID[run.ID]; exists {
+		return fmt.Errorf("run %s already exists", run.ID)
+	}
+	if s.db != nil {
+		if err := s.db.Save(run); err != nil {
+			return err
+		}
+	}
+	s.runs = append(s.runs, run)
+	s.byID[run.ID] = run
+	s.evict()
+	return nil
+}
+
+// evict は上限を超えた古い実行記録を削除する（ロック保持中に呼ぶ）
+func (s *Store) evict() {
+	if s.config.MaxRuns <= 0 {
+		return
+	}
+	for len(s.runs) > s.config.MaxRuns {
+		old := s.runs[0]
+		s.runs = s.runs[1:]
+		delete(s.byID, old.ID)
+		if s.config.Dir != "" {
+			_ = os.Remove(s.runPath(old.ID))
+		}
+		if s.db != nil {
+			if err := s.db.Delete(old.ID); err != nil {
+				log.Warn("", "Failed to evict run %s: %v", old.ID, err)
+			}
+		}
+	}
+}
+
+// runPath は実行記録の保存パスを返す
+func (s *Store) runPath(id string) string {
+	return filepath.Join(s.config.Dir, id+".json")
+}
+
+// Get はIDで実行記録を取得する
+func (s *Store) Get(id string) (*Run, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	run, ok := s.byID[id]
+	return run, ok
+}
+
+// List は実行の概要を新しい順に返す
+func (s *Store) List() []Summary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summaries := make([]Summary, 0, len(s.runs))
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		summaries = append(summaries, s.runs[i].Summary())
+	}
+	return summaries
+}
+
+// Len は保持している実行数を返す
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.runs)
+}
+
+// DB は Database 指定時の保存先を返す（秒ごとのメトリクス・イベントの検索用、未指定の場合は nil）
+func (s *Store) DB() *DB {
+	return s.db
+}
+
+// Close は保存先のデータベースを閉じる（Database 未指定の場合は何もしない）
+func (s *Store) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Recorder はイベントバスを購読し、実行中のタイムラインを記録する
+type Recorder struct {
+	bus    *events.Bus
+	ch     <-chan events.Event
+	done   chan struct{}
+	mu     sync.Mutex
+	events []events.Event
+}
+
+// NewRecorder はイベントの記録を開始する
+// タイムラインが欠けないよう、記録が追いつくまで発行側を待たせる
+func NewRecorder(bus *events.Bus) *Recorder {
+	r := &Recorder{
+		bus:  bus,
+		ch:   bus.SubscribeWith(events.SubscribeOptions{Policy: events.Block}),
+		done: make(chan struct{}),
+	}
+	go r.loop()
+	return r
+}
+
+// loop はイベントを受信して記録する
+func (r *Recorder) loop() {
+	defer close(r.done)
+	for event := range r.ch {
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}
+}
+
+// Stop は記録を終了し、記録したイベントを返す
+func (r *Recorder) Stop() []events.Event {
+	r.bus.Unsubscribe(r.ch)
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events
+}
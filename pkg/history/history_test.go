@@ -0,0 +1,164 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/scenario"
+)
+
+// newTestRun はテスト用の実行記録を作成する
+func newTestRun(name string, start time.Time) *Run {
+	result := &scenario.Result{
+		ScenarioName:  name,
+		StartTime:     start,
+		EndTime:       start.Add(time.Second),
+		Duration:      time.Second,
+		TotalRequests: 100,
+	}
+	timeline := []events.Event{events.NewChaosAttackEvent("node-1", events.AttackTypeKill)}
+	return NewRun(scenario.DefaultConfig(), result, timeline)
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.Dir != "" {
+		t.Errorf("expected empty dir, got %s", config.Dir)
+	}
+	if config.MaxRuns != 100 {
+		t.Errorf("expected max runs 100, got %d", config.MaxRuns)
+	}
+}
+
+func TestStoreAddGetList(t *testing.T) {
+	store, err := NewStore(DefaultConfig())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	now := time.Now()
+	first := newTestRun("first", now)
+	second := newTestRun("second", now.Add(time.Minute))
+
+	if err := store.Add(first); err != nil {
+		t.Fatalf("failed to add run: %v", err)
+	}
+	if err := store.Add(second); err != nil {
+		t.Fatalf("failed to add run: %v", err)
+	}
+	if err := store.Add(first); err == nil {
+		t.Error("expected error when adding duplicate run")
+	}
+
+	run, ok := store.Get(first.ID)
+	if !ok {
+		t.Fatal("expected run to be found")
+	}
+	if len(run.Timeline) != 1 {
+		t.Errorf("expected 1 timeline event, got %d", len(run.Timeline))
+	}
+	if _, ok := store.Get("missing"); ok {
+		t.Error("expected missing run to not be found")
+	}
+
+	list := store.List()
+	if len(list) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(list))
+	}
+	if list[0].ScenarioName != "second" {
+		t.Errorf("expected newest run first, got %s", list[0].ScenarioName)
+	}
+	if list[0].TotalRequests != 100 || list[0].EventCount != 1 {
+		t.Errorf("unexpected summary: %+v", list[0])
+	}
+}
+
+func TestStoreMaxRuns(t *testing.T) {
+	store, err := NewStore(Config{MaxRuns: 2})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	now := time.Now()
+	runs := []*Run{
+		newTestRun("a", now),
+		newTestRun("b", now.Add(time.Second)),
+		newTestRun("c", now.Add(2*time.Second)),
+	}
+	for _, run := range runs {
+		if err := store.Add(run); err != nil {
+			t.Fatalf("failed to add run: %v", err)
+		}
+	}
+
+	if store.Len() != 2 {
+		t.Errorf("expected 2 runs, got %d", store.Len())
+	}
+	if _, ok := store.Get(runs[0].ID); ok {
+		t.Error("expected oldest run to be evicted")
+	}
+}
+
+func TestStorePersistence(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{Dir: dir, MaxRuns: 10}
+
+	store, err := NewStore(config)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	run := newTestRun("persisted", time.Now())
+	if err := store.Add(run); err != nil {
+		t.Fatalf("failed to add run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, run.ID+".json")); err != nil {
+		t.Fatalf("expected run file to exist: %v", err)
+	}
+
+	// 不正なファイルは読み込み時にスキップされる
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewStore(config)
+	if err != nil {
+		t.Fatalf("failed to reload store: %v", err)
+	}
+	if reloaded.Len() != 1 {
+		t.Fatalf("expected 1 run after reload, got %d", reloaded.Len())
+	}
+	got, ok := reloaded.Get(run.ID)
+	if !ok {
+		t.Fatal("expected run to be reloaded")
+	}
+	if got.Result.ScenarioName != "persisted" {
+		t.Errorf("expected scenario name 'persisted', got %s", got.Result.ScenarioName)
+	}
+	if len(got.Timeline) != 1 || got.Timeline[0].Type != events.EventChaosAttack {
+		t.Errorf("unexpected timeline: %+v", got.Timeline)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	recorder := NewRecorder(bus)
+	bus.Publish(events.NewChaosAttackEvent("node-1", events.AttackTypeKill))
+	bus.Publish(events.NewRecoverySuccessEvent("node-1"))
+
+	// 配送を待つ
+	time.Sleep(20 * time.Millisecond)
+
+	timeline := recorder.Stop()
+	if len(timeline) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(timeline))
+	}
+	if bus.SubscriberCount() != 0 {
+		t.Errorf("expected recorder to unsubscribe, got %d subscribers", bus.SubscriberCount())
+	}
+}
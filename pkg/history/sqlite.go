@@ -0,0 +1,451 @@
+package history
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// sqliteHeader は SQLite のデータベースファイルの先頭の16バイト
+const sqliteHeader = "SQLite format 3\x00"
+
+// schema は実行記録・秒ごとのメトリクス・イベントのテーブル
+// 時刻・経過時間は Unix ナノ秒、設定・結果・イベントは JSON で保存する
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id             TEXT PRIMARY KEY,
+	scenario       TEXT NOT NULL,
+	start_time     INTEGER NOT NULL,
+	end_time       INTEGER NOT NULL,
+	duration       INTEGER NOT NULL,
+	interrupted    INTEGER NOT NULL,
+	total_requests INTEGER NOT NULL,
+	error_rate     REAL NOT NULL,
+	total_attacks  INTEGER NOT NULL,
+	event_count    INTEGER NOT NULL,
+	config         TEXT NOT NULL,
+	result         TEXT
+);
+CREATE INDEX IF NOT EXISTS runs_start_time ON runs (start_time);
+CREATE INDEX IF NOT EXISTS runs_scenario ON runs (scenario, start_time);
+
+CREATE TABLE IF NOT EXISTS metrics (
+	run_id         TEXT NOT NULL,
+	time           INTEGER NOT NULL,
+	requests       INTEGER NOT NULL,
+	errors         INTEGER NOT NULL,
+	rps            REAL NOT NULL,
+	avg_latency_ms REAL NOT NULL,
+	p99_latency_ms REAL NOT NULL,
+	error_rate     REAL NOT NULL,
+	nodes_running  INTEGER NOT NULL,
+	attacks        INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS metrics_run_time ON metrics (run_id, time);
+
+CREATE TABLE IF NOT EXISTS events (
+	run_id  TEXT NOT NULL,
+	seq     INTEGER NOT NULL,
+	type    TEXT NOT NULL,
+	time    INTEGER NOT NULL,
+	node_id TEXT NOT NULL,
+	event   TEXT NOT NULL,
+	PRIMARY KEY (run_id, seq)
+);
+CREATE INDEX IF NOT EXISTS events_type_time ON events (type, time);
+CREATE INDEX IF NOT EXISTS events_node_time ON events (node_id, time);
+`
+
+// MetricsPoint は実行中に記録されたメトリクスのスナップショット（metrics_snapshot イベント、シナリオの MetricsInterval ごと）
+type MetricsPoint struct {
+	Time         time.Time `json:"time"`
+	Requests     uint64    `json:"requests"` // その時点までの累計
+	Errors       uint64    `json:"errors"`   // その時点までの累計
+	RPS          float64   `json:"rps"`
+	AvgLatencyMs float64   `json:"avg_latency_ms"`
+	P99LatencyMs float64   `json:"p99_latency_ms"`
+	ErrorRate    float64   `json:"error_rate"`
+	NodesRunning int       `json:"nodes_running"`
+	Attacks      uint64    `json:"attacks"`
+}
+
+// RunQuery は実行記録の検索条件（ゼロ値の項目は絞り込まない）
+type RunQuery struct {
+	Scenario string    // シナリオ名
+	Since    time.Time // 開始時刻の下限（この時刻を含む）
+	Until    time.Time // 開始時刻の上限（この時刻を含まない）
+	Limit    int       // 返す最大件数
+}
+
+// EventQuery はイベントの検索条件（ゼロ値の項目は絞り込まない）
+type EventQuery struct {
+	RunID  string
+	Types  []events.EventType
+	NodeID string
+	Since  time.Time // 発生時刻の下限（この時刻を含む）
+	Until  time.Time // 発生時刻の上限（この時刻を含まない）
+	Limit  int       // 返す最大件数
+}
+
+// DB は実行記録・秒ごとのメトリクス・イベントを SQLite に保存し、インデックスを使って検索する
+// ドライバは cgo を有効にしたビルドでのみ組み込むため、cgo を無効にしてビルドした場合は開くことができない
+type DB struct {
+	db *sql.DB
+}
+
+// OpenDB は SQLite のデータベースを開き、テーブルがなければ作成する
+func OpenDB(path string) (*DB, error) {
+	if !sqliteEnabled {
+		return nil, fmt.Errorf("history database %s requires a build with cgo enabled", path)
+	}
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize history database %s: %w", path, err)
+	}
+	return &DB{db: db}, nil
+}
+
+// IsDatabase は path が SQLite のデータベースファイルかを返す
+func IsDatabase(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, len(sqliteHeader))
+	if _, err := f.Read(header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, []byte(sqliteHeader))
+}
+
+// Close はデータベースを閉じる
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Save は実行記録を保存する
+// タイムラインのイベントに加え、metrics_snapshot イベントを秒ごとのメトリクスとして保存する
+func (d *DB) Save(run *Run) error {
+	config, err := json.Marshal(run.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode run config: %w", err)
+	}
+	var result []byte
+	if run.Result != nil {
+		if result, err = json.Marshal(run.Result); err != nil {
+			return fmt.Errorf("failed to encode run result: %w", err)
+		}
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save run %s: %w", run.ID, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	s := run.Summary()
+	if _, err := tx.Exec(`INSERT INTO runs (id, scenario, start_time, end_time, duration, interrupted,
+		total_requests, error_rate, total_attacks, event_count, config, result)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.ID, s.ScenarioName, unixNano(s.StartTime), unixNano(s.EndTime), int64(s.Duration), s.Interrupted,
+		int64(s.TotalRequests), s.ErrorRate, int64(s.TotalAttacks), s.EventCount, string(config), nullString(result),
+	); err != nil {
+		return fmt.Errorf("failed to save run %s: %w", run.ID, err)
+	}
+
+	for i, event := range run.Timeline {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO events (run_id, seq, type, time, node_id, event) VALUES (?, ?, ?, ?, ?, ?)`,
+			run.ID, i, string(event.Type), unixNano(event.Timestamp), event.NodeID, string(data),
+		); err != nil {
+			return fmt.Errorf("failed to save events of run %s: %w", run.ID, err)
+		}
+
+		if event.Type != events.EventMetricsSnapshot {
+			continue
+		}
+		m := event.Data
+		if _, err := tx.Exec(`INSERT INTO metrics (run_id, time, requests, errors, rps, avg_latency_ms,
+			p99_latency_ms, error_rate, nodes_running, attacks) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			run.ID, unixNano(event.Timestamp), int64(m.Requests), int64(m.Errors), m.RPS, m.AvgLatencyMs,
+			m.P99LatencyMs, m.ErrorRate, m.NodesRunning, int64(m.Attacks),
+		); err != nil {
+			return fmt.Errorf("failed to save metrics of run %s: %w", run.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save run %s: %w", run.ID, err)
+	}
+	return nil
+}
+
+// Delete は実行記録とそのメトリクス・イベントを削除する
+func (d *DB) Delete(id string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to delete run %s: %w", id, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range []string{"events", "metrics"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE run_id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete run %s: %w", id, err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM runs WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete run %s: %w", id, err)
+	}
+	return tx.Commit()
+}
+
+// Load は全ての実行記録をタイムライン付きで開始時刻の古い順に返す
+func (d *DB) Load() ([]*Run, error) {
+	rows, err := d.db.Query("SELECT id, config, result FROM runs ORDER BY start_time, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []*Run
+	byID := make(map[string]*Run)
+	for rows.Next() {
+		var (
+			run    Run
+			config string
+			result sql.NullString
+		)
+		if err := rows.Scan(&run.ID, &config, &result); err != nil {
+			return nil, fmt.Errorf("failed to load runs: %w", err)
+		}
+		if err := json.Unmarshal([]byte(config), &run.Config); err != nil {
+			log.Warn("", "Skipping run %s with invalid config: %v", run.ID, err)
+			continue
+		}
+		if result.Valid {
+			if err := json.Unmarshal([]byte(result.String), &run.Result); err != nil {
+				log.Warn("", "Skipping run %s with invalid result: %v", run.ID, err)
+				continue
+			}
+		}
+		runs = append(runs, &run)
+		byID[run.ID] = &run
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load runs: %w", err)
+	}
+
+	timeline, err := d.db.Query("SELECT run_id, event FROM events ORDER BY run_id, seq")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	defer func() { _ = timeline.Close() }()
+	for timeline.Next() {
+		var id, data string
+		if err := timeline.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+		run, ok := byID[id]
+		if !ok {
+			continue
+		}
+		var event events.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode event of run %s: %w", id, err)
+		}
+		run.Timeline = append(run.Timeline, event)
+	}
+	if err := timeline.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	return runs, nil
+}
+
+// Runs は条件に一致する実行の概要を新しい順に返す
+func (d *DB) Runs(q RunQuery) ([]Summary, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if q.Scenario != "" {
+		where = append(where, "scenario = ?")
+		args = append(args, q.Scenario)
+	}
+	where, args = timeRange(where, args, "start_time", q.Since, q.Until)
+
+	rows, err := d.db.Query(`SELECT id, scenario, start_time, end_time, duration, interrupted,
+		total_requests, error_rate, total_attacks, event_count FROM runs`+
+		whereClause(where)+" ORDER BY start_time DESC, id DESC"+limitClause(q.Limit), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	summaries := []Summary{}
+	for rows.Next() {
+		var (
+			s                    Summary
+			start, end, duration int64
+			requests, attacks    int64
+		)
+		if err := rows.Scan(&s.ID, &s.ScenarioName, &start, &end, &duration, &s.Interrupted,
+			&requests, &s.ErrorRate, &attacks, &s.EventCount); err != nil {
+			return nil, fmt.Errorf("failed to query runs: %w", err)
+		}
+		s.StartTime = fromUnixNano(start)
+		s.EndTime = fromUnixNano(end)
+		s.Duration = time.Duration(duration)
+		s.TotalRequests = uint64(requests)
+		s.TotalAttacks = uint64(attacks)
+		summaries = append(summaries, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	return summaries, nil
+}
+
+// Metrics は実行の秒ごとのメトリクスを古い順に返す
+func (d *DB) Metrics(runID string) ([]MetricsPoint, error) {
+	rows, err := d.db.Query(`SELECT time, requests, errors, rps, avg_latency_ms, p99_latency_ms,
+		error_rate, nodes_running, attacks FROM metrics WHERE run_id = ? ORDER BY time`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	points := []MetricsPoint{}
+	for rows.Next() {
+		var (
+			p                         MetricsPoint
+			t, requests, errs, attack int64
+		)
+		if err := rows.Scan(&t, &requests, &errs, &p.RPS, &p.AvgLatencyMs, &p.P99LatencyMs,
+			&p.ErrorRate, &p.NodesRunning, &attack); err != nil {
+			return nil, fmt.Errorf("failed to query metrics: %w", err)
+		}
+		p.Time = fromUnixNano(t)
+		p.Requests = uint64(requests)
+		p.Errors = uint64(errs)
+		p.Attacks = uint64(attack)
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %w", err)
+	}
+	return points, nil
+}
+
+// Events は条件に一致するイベントを発生順に返す
+// 返すイベントの RunID には記録した実行のIDを設定する
+func (d *DB) Events(q EventQuery) ([]events.Event, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if q.RunID != "" {
+		where = append(where, "run_id = ?")
+		args = append(args, q.RunID)
+	}
+	if len(q.Types) > 0 {
+		where = append(where, "type IN (?"+strings.Repeat(", ?", len(q.Types)-1)+")")
+		for _, t := range q.Types {
+			args = append(args, string(t))
+		}
+	}
+	if q.NodeID != "" {
+		where = append(where, "node_id = ?")
+		args = append(args, q.NodeID)
+	}
+	where, args = timeRange(where, args, "time", q.Since, q.Until)
+
+	rows, err := d.db.Query("SELECT run_id, event FROM events"+whereClause(where)+
+		" ORDER BY time, run_id, seq"+limitClause(q.Limit), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	list := []events.Event{}
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to query events: %w", err)
+		}
+		var event events.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode event of run %s: %w", id, err)
+		}
+		event.RunID = id
+		list = append(list, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	return list, nil
+}
+
+// timeRange は時刻の範囲の条件を追加する
+func timeRange(where []string, args []any, column string, since, until time.Time) ([]string, []any) {
+	if !since.IsZero() {
+		where = append(where, column+" >= ?")
+		args = append(args, since.UnixNano())
+	}
+	if !until.IsZero() {
+		where = append(where, column+" < ?")
+		args = append(args, until.UnixNano())
+	}
+	return where, args
+}
+
+// whereClause は条件を AND で結合した WHERE 句を返す（条件がなければ空）
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(where, " AND ")
+}
+
+// limitClause は件数の上限の LIMIT 句を返す（0以下で空）
+func limitClause(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+// unixNano は時刻を Unix ナノ秒で返す（ゼロ値は0）
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano は Unix ナノ秒を時刻に戻す（0はゼロ値）
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// nullString は空のデータを NULL として保存する
+func nullString(data []byte) sql.NullString {
+	return sql.NullString{String: string(data), Valid: data != nil}
+}
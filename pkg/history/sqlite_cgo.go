@@ -0,0 +1,10 @@
+//go:build cgo
+
+package history
+
+import (
+	_ "github.com/mattn/go-sqlite3" // database/sql の "sqlite3" ドライバ
+)
+
+// sqliteEnabled は SQLite のドライバを組み込んでいるか（cgo を有効にしたビルドのみ）
+const sqliteEnabled = true
@@ -0,0 +1,6 @@
+//go:build !cgo
+
+package history
+
+// sqliteEnabled は SQLite のドライバを組み込んでいるか（cgo を無効にしたビルドでは組み込まない）
+const sqliteEnabled = false
@@ -0,0 +1,18 @@
+//go:build !cgo
+
+package history
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenDBWithoutCgo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	if _, err := OpenDB(path); err == nil {
+		t.Fatal("expected error opening database without cgo")
+	}
+	if _, err := NewStore(Config{Database: path}); err == nil {
+		t.Fatal("expected error creating store with database without cgo")
+	}
+}
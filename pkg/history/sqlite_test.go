@@ -0,0 +1,206 @@
+//go:build cgo
+
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// newMetricsRun はメトリクスのスナップショットを含む実行記録を作成する
+func newMetricsRun(name string, start time.Time) *Run {
+	run := newTestRun(name, start)
+	for i := 1; i <= 3; i++ {
+		snapshot := events.NewMetricsSnapshotEvent(events.MetricsSummary{
+			Requests:     uint64(i * 100),
+			Errors:       uint64(i),
+			RPS:          100,
+			P99Latency:   time.Duration(i) * time.Millisecond,
+			NodesRunning: 3,
+		})
+		snapshot.Timestamp = start.Add(time.Duration(i) * time.Second)
+		run.Timeline = append(run.Timeline, snapshot)
+	}
+	run.Timeline[0].Timestamp = start.Add(1500 * time.Millisecond)
+	return run
+}
+
+func TestDBSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	start := time.Unix(1700000000, 0)
+	run := newMetricsRun("saved", start)
+	if err := db.Save(run); err != nil {
+		t.Fatalf("failed to save run: %v", err)
+	}
+	if err := db.Save(run); err == nil {
+		t.Error("expected error when saving a duplicate run")
+	}
+	if !IsDatabase(path) {
+		t.Error("expected the file to be detected as a database")
+	}
+
+	runs, err := db.Load()
+	if err != nil {
+		t.Fatalf("failed to load runs: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	got := runs[0]
+	if got.ID != run.ID || got.Result.ScenarioName != "saved" || !got.Result.StartTime.Equal(start) {
+		t.Errorf("unexpected run: %+v", got.Result)
+	}
+	if got.Config.NodeCount != run.Config.NodeCount {
+		t.Errorf("expected the config to be kept, got %+v", got.Config)
+	}
+	if len(got.Timeline) != 4 || got.Timeline[0].Type != events.EventChaosAttack {
+		t.Errorf("expected the timeline in its recorded order, got %+v", got.Timeline)
+	}
+
+	if err := db.Delete(run.ID); err != nil {
+		t.Fatalf("failed to delete run: %v", err)
+	}
+	if runs, _ := db.Load(); len(runs) != 0 {
+		t.Errorf("expected no runs after delete, got %d", len(runs))
+	}
+	if points, _ := db.Metrics(run.ID); len(points) != 0 {
+		t.Errorf("expected the metrics to be deleted, got %d", len(points))
+	}
+}
+
+func TestDBQueries(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	start := time.Unix(1700000000, 0)
+	runs := []*Run{
+		newMetricsRun("nightly", start),
+		newMetricsRun("smoke", start.Add(time.Hour)),
+		newMetricsRun("nightly", start.Add(2*time.Hour)),
+	}
+	for _, run := range runs {
+		if err := db.Save(run); err != nil {
+			t.Fatalf("failed to save run: %v", err)
+		}
+	}
+
+	all, err := db.Runs(RunQuery{})
+	if err != nil {
+		t.Fatalf("failed to query runs: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != runs[2].ID || all[0].TotalRequests != 100 || all[0].EventCount != 4 {
+		t.Errorf("expected all runs newest first, got %+v", all)
+	}
+	nightly, _ := db.Runs(RunQuery{Scenario: "nightly", Limit: 1})
+	if len(nightly) != 1 || nightly[0].ID != runs[2].ID {
+		t.Errorf("expected the latest nightly run, got %+v", nightly)
+	}
+	ranged, _ := db.Runs(RunQuery{Since: start.Add(time.Minute), Until: start.Add(2 * time.Hour)})
+	if len(ranged) != 1 || ranged[0].ID != runs[1].ID {
+		t.Errorf("expected the run in range, got %+v", ranged)
+	}
+
+	points, err := db.Metrics(runs[0].ID)
+	if err != nil {
+		t.Fatalf("failed to query metrics: %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("expected 3 metrics points, got %d", len(points))
+	}
+	if p := points[2]; p.Requests != 300 || p.Errors != 3 || p.P99LatencyMs != 3 || p.NodesRunning != 3 || !p.Time.Equal(start.Add(3*time.Second)) {
+		t.Errorf("unexpected metrics point: %+v", p)
+	}
+
+	attacks, err := db.Events(EventQuery{Types: []events.EventType{events.EventChaosAttack}})
+	if err != nil {
+		t.Fatalf("failed to query events: %v", err)
+	}
+	if len(attacks) != 3 || attacks[0].RunID != runs[0].ID || attacks[0].NodeID != "node-1" {
+		t.Errorf("expected one attack per run, got %+v", attacks)
+	}
+	first, _ := db.Events(EventQuery{RunID: runs[0].ID, Since: start.Add(time.Second), Until: start.Add(2 * time.Second)})
+	if len(first) != 2 || first[0].Type != events.EventMetricsSnapshot || first[1].Type != events.EventChaosAttack {
+		t.Errorf("expected the events in time order, got %+v", first)
+	}
+	limited, _ := db.Events(EventQuery{NodeID: "node-1", Limit: 2})
+	if len(limited) != 2 {
+		t.Errorf("expected 2 events, got %d", len(limited))
+	}
+}
+
+func TestStoreDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	config := Config{Database: path, MaxRuns: 2}
+
+	store, err := NewStore(config)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	start := time.Now()
+	runs := []*Run{
+		newMetricsRun("a", start),
+		newMetricsRun("b", start.Add(time.Second)),
+		newMetricsRun("c", start.Add(2*time.Second)),
+	}
+	for _, run := range runs {
+		if err := store.Add(run); err != nil {
+			t.Fatalf("failed to add run: %v", err)
+		}
+	}
+	if store.DB() == nil {
+		t.Fatal("expected the store to expose its database")
+	}
+	if points, _ := store.DB().Metrics(runs[0].ID); len(points) != 0 {
+		t.Errorf("expected the evicted run to be deleted, got %d points", len(points))
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("failed to close store: %v", err)
+	}
+
+	reloaded, err := NewStore(config)
+	if err != nil {
+		t.Fatalf("failed to reload store: %v", err)
+	}
+	defer func() { _ = reloaded.Close() }()
+	list := reloaded.List()
+	if len(list) != 2 || list[0].ID != runs[2].ID || list[1].ID != runs[1].ID {
+		t.Fatalf("expected the 2 newest runs after reload, got %+v", list)
+	}
+	got, _ := reloaded.Get(runs[2].ID)
+	if len(got.Timeline) != 4 {
+		t.Errorf("expected the timeline to be reloaded, got %d events", len(got.Timeline))
+	}
+
+	if _, err := NewStore(Config{Dir: t.TempDir(), Database: path}); err == nil {
+		t.Error("expected error when both dir and database are set")
+	}
+	if memory, _ := NewStore(DefaultConfig()); memory.DB() != nil || memory.Close() != nil {
+		t.Error("expected a memory store to have no database")
+	}
+}
+
+func TestIsDatabase(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "run.json")
+	if err := os.WriteFile(text, []byte(`{"id": "x"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{text, dir, filepath.Join(dir, "missing.db")} {
+		if IsDatabase(path) {
+			t.Errorf("expected %s not to be a database", path)
+		}
+	}
+}
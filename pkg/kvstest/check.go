@@ -0,0 +1,253 @@
+package kvstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 検査する不変条件の名前（Failure.Invariant）
+const (
+	InvariantOpError     = "operation error"  // 操作がエラーを返した
+	InvariantRead        = "read consistency" // get の結果がそれまでの操作と一致しない
+	InvariantGetAfterSet = "get-after-set"    // set の直後の get が設定した値を返さない
+	InvariantDelete      = "delete semantics" // delete の直後の get がキーを返す
+	InvariantSize        = "size consistency" // キーの数がそれまでの操作と一致しない
+)
+
+// Failure は不変条件を破った操作
+type Failure struct {
+	Ops       []Op   // 失敗した操作までの操作列
+	Index     int    // 失敗した操作の位置
+	Invariant string // 破られた不変条件
+	Detail    string
+	Seed      int64 // Run で生成した操作列の乱数シード（Check では0）
+}
+
+func (f *Failure) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s violated at op %d (%s): %s", f.Invariant, f.Index, f.Ops[f.Index], f.Detail)
+	if f.Seed != 0 {
+		fmt.Fprintf(&b, " (seed %d)", f.Seed)
+	}
+	b.WriteString("\noperations:")
+	for i, op := range f.Ops {
+		fmt.Fprintf(&b, "\n  %d: %s", i, op)
+	}
+	return b.String()
+}
+
+// Check は空のストア s に ops を順に適用し、参照モデル（map）と比べて不変条件を検査する
+// 最初に破られた不変条件を *Failure として返す
+func Check(ctx context.Context, s Store, ops []Op) error {
+	model := make(map[string][]byte)
+	sizer, _ := s.(Sizer)
+
+	fail := func(i int, invariant, format string, args ...any) error {
+		return &Failure{
+			Ops:       append([]Op{}, ops[:i+1]...),
+			Index:     i,
+			Invariant: invariant,
+			Detail:    fmt.Sprintf(format, args...),
+		}
+	}
+
+	for i, op := range ops {
+		switch op.Kind {
+		case OpSet:
+			if err := s.Set(ctx, op.Key, op.Value); err != nil {
+				return fail(i, InvariantOpError, "set failed: %v", err)
+			}
+			model[op.Key] = append([]byte{}, op.Value...)
+			if detail, err := expect(ctx, s, op.Key, op.Value, true); err != nil {
+				return fail(i, InvariantOpError, "get after set failed: %v", err)
+			} else if detail != "" {
+				return fail(i, InvariantGetAfterSet, "%s", detail)
+			}
+
+		case OpGet:
+			want, exists := model[op.Key]
+			if detail, err := expect(ctx, s, op.Key, want, exists); err != nil {
+				return fail(i, InvariantOpError, "get failed: %v", err)
+			} else if detail != "" {
+				return fail(i, InvariantRead, "%s", detail)
+			}
+
+		case OpDelete:
+			if err := s.Delete(ctx, op.Key); err != nil {
+				return fail(i, InvariantOpError, "delete failed: %v", err)
+			}
+			delete(model, op.Key)
+			if detail, err := expect(ctx, s, op.Key, nil, false); err != nil {
+				return fail(i, InvariantOpError, "get after delete failed: %v", err)
+			} else if detail != "" {
+				return fail(i, InvariantDelete, "%s", detail)
+			}
+
+		default:
+			return fail(i, InvariantOpError, "unknown operation kind %d", op.Kind)
+		}
+
+		if sizer != nil {
+			size, err := sizer.Size(ctx)
+			if errors.Is(err, ErrSizeUnsupported) {
+				sizer = nil
+				continue
+			}
+			if err != nil {
+				return fail(i, InvariantOpError, "size failed: %v", err)
+			}
+			if size != len(model) {
+				return fail(i, InvariantSize, "expected %d keys, got %d", len(model), size)
+			}
+		}
+	}
+	return nil
+}
+
+// expect は key の値が want（exists が false の場合は存在しないこと）と一致するかを調べ、
+// 一致しない場合はその内容を返す
+func expect(ctx context.Context, s Store, key string, want []byte, exists bool) (string, error) {
+	got, ok, err := s.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case exists && !ok:
+		return fmt.Sprintf("expected %q, got missing key", want), nil
+	case !exists && ok:
+		return fmt.Sprintf("expected missing key, got %q", got), nil
+	case exists && !bytes.Equal(got, want):
+		return fmt.Sprintf("expected %q, got %q", want, got), nil
+	}
+	return "", nil
+}
+
+// Config は Run の設定
+type Config struct {
+	Iterations  int           // 生成する操作列の数
+	Ops         int           // 操作列あたりの操作数
+	Seed        int64         // 乱数シード（0で現在時刻から決める。失敗時に表示する）
+	Gen         GenConfig     // 操作の生成設定
+	ShrinkLimit int           // 失敗した操作列を縮小するときに試す操作列の上限（0で縮小しない）
+	Timeout     time.Duration // 操作列1件の検査の上限時間（0で無制限）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Iterations:  100,
+		Ops:         50,
+		Gen:         DefaultGenConfig(),
+		ShrinkLimit: 500,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// Run はランダムな操作列を Iterations 件生成し、それぞれを newStore で作った空のストアで検査する
+// 失敗した場合は操作列を縮小してから、シードとともに t.Fatal で報告する
+func Run(t testing.TB, newStore func(t testing.TB) Store, config Config) {
+	t.Helper()
+	if f := run(t, newStore, config); f != nil {
+		t.Fatal(f)
+	}
+}
+
+// run は Run の本体で、最初の失敗を縮小して返す
+func run(t testing.TB, newStore func(t testing.TB) Store, config Config) *Failure {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	r := rand.New(rand.NewSource(seed))
+
+	for range config.Iterations {
+		ops := Generate(r, config.Ops, config.Gen)
+		f := check(t, newStore, ops, config.Timeout)
+		if f == nil {
+			continue
+		}
+		f = shrink(t, newStore, f, config)
+		f.Seed = seed
+		return f
+	}
+	return nil
+}
+
+// check は新しいストアで ops を検査する
+func check(t testing.TB, newStore func(t testing.TB) Store, ops []Op, timeout time.Duration) *Failure {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var f *Failure
+	if err := Check(ctx, newStore(t), ops); err != nil && !errors.As(err, &f) {
+		f = &Failure{Ops: ops, Index: len(ops) - 1, Invariant: InvariantOpError, Detail: err.Error()}
+	}
+	return f
+}
+
+// shrink は失敗を再現する最小の操作列を探す
+// 失敗した操作より後を捨て、1件ずつ取り除いても失敗が再現するものを残す
+func shrink(t testing.TB, newStore func(t testing.TB) Store, f *Failure, config Config) *Failure {
+	attempts := 0
+	for changed := true; changed && attempts < config.ShrinkLimit; {
+		changed = false
+		for i := 0; i < len(f.Ops) && attempts < config.ShrinkLimit; {
+			candidate := append(append([]Op{}, f.Ops[:i]...), f.Ops[i+1:]...)
+			attempts++
+			if smaller := check(t, newStore, candidate, config.Timeout); smaller != nil && smaller.Invariant == f.Invariant {
+				f = smaller
+				changed = true
+				continue
+			}
+			i++
+		}
+	}
+	return f
+}
+
+// Fuzz はファズの入力を操作列に変換して、newStore で作った空のストアで検査するターゲットを登録する
+// 代表的な操作列をシードとして追加する
+func Fuzz(f *testing.F, newStore func(t testing.TB) Store, config GenConfig) {
+	for _, seed := range fuzzSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := Decode(data, config)
+		if err := Check(context.Background(), newStore(t), ops); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// fuzzSeeds はファズのシード（上書き・削除・空の値・削除済みキーへの再設定）を返す
+func fuzzSeeds() [][]byte {
+	const (
+		get, set, del = 0, 1, 2
+		key1          = 3 // キー番号1の種類のオフセット
+	)
+	seeds := [][]byte{
+		{set, 3, 'a', 'b', 'c', get},
+		{set, 1, 'x', set, 2, 'y', 'z', get, del, get},
+		{set, 0, del, set, 0, get},
+		{set + key1, 1, 'v', del, get + key1, del + key1, get + key1},
+	}
+	// 多くのキーに書いてから一部を消す
+	var many []byte
+	for i := range 8 {
+		many = append(many, byte(set+3*i), 1, byte('a'+i))
+	}
+	for i := range 4 {
+		many = append(many, byte(del+3*i))
+	}
+	return append(seeds, many)
+}
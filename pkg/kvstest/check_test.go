@@ -0,0 +1,205 @@
+package kvstest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// newNode は起動済みのノードを Store として返す
+func newNode(t testing.TB) Store {
+	n := node.New("kvstest")
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	t.Cleanup(func() { _ = n.Stop() })
+	return Node(n)
+}
+
+// mapStore はテスト用のマップのストア
+// 各フックで不具合を注入する
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+
+	dropSet    func(key string, value []byte) bool // true の場合は書き込みを捨てる
+	keepDelete bool                                // 削除しない
+	sizeOffset int                                 // Size に足す値
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: make(map[string][]byte)}
+}
+
+func (s *mapStore) store() Funcs {
+	return Funcs{
+		GetFunc: func(_ context.Context, key string) ([]byte, bool, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			v, ok := s.data[key]
+			return v, ok, nil
+		},
+		SetFunc: func(_ context.Context, key string, value []byte) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.dropSet == nil || !s.dropSet(key, value) {
+				s.data[key] = value
+			}
+			return nil
+		},
+		DeleteFunc: func(_ context.Context, key string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if !s.keepDelete {
+				delete(s.data, key)
+			}
+			return nil
+		},
+		SizeFunc: func(context.Context) (int, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return len(s.data) + s.sizeOffset, nil
+		},
+	}
+}
+
+func TestRunNode(t *testing.T) {
+	config := DefaultConfig()
+	config.Seed = 1
+	Run(t, newNode, config)
+}
+
+func TestCheckPasses(t *testing.T) {
+	ops := []Op{
+		{Kind: OpGet, Key: "a"},
+		{Kind: OpSet, Key: "a", Value: []byte("1")},
+		{Kind: OpSet, Key: "a", Value: []byte{}},
+		{Kind: OpGet, Key: "a"},
+		{Kind: OpDelete, Key: "a"},
+		{Kind: OpDelete, Key: "a"},
+		{Kind: OpGet, Key: "a"},
+	}
+	if err := Check(context.Background(), newMapStore().store(), ops); err != nil {
+		t.Errorf("expected a correct store to pass: %v", err)
+	}
+}
+
+func TestCheckDetectsViolations(t *testing.T) {
+	tests := []struct {
+		name      string
+		store     func() *mapStore
+		ops       []Op
+		invariant string
+	}{
+		{
+			name: "lost write",
+			store: func() *mapStore {
+				s := newMapStore()
+				s.dropSet = func(key string, _ []byte) bool { return key == "b" }
+				return s
+			},
+			ops:       []Op{{Kind: OpSet, Key: "a", Value: []byte("1")}, {Kind: OpSet, Key: "b", Value: []byte("2")}},
+			invariant: InvariantGetAfterSet,
+		},
+		{
+			name: "delete ignored",
+			store: func() *mapStore {
+				s := newMapStore()
+				s.keepDelete = true
+				return s
+			},
+			ops:       []Op{{Kind: OpSet, Key: "a", Value: []byte("1")}, {Kind: OpDelete, Key: "a"}},
+			invariant: InvariantDelete,
+		},
+		{
+			name: "wrong size",
+			store: func() *mapStore {
+				s := newMapStore()
+				s.sizeOffset = 1
+				return s
+			},
+			ops:       []Op{{Kind: OpGet, Key: "a"}},
+			invariant: InvariantSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(context.Background(), tt.store().store(), tt.ops)
+			var f *Failure
+			if !errors.As(err, &f) {
+				t.Fatalf("expected a failure, got %v", err)
+			}
+			if f.Invariant != tt.invariant || f.Index != len(tt.ops)-1 {
+				t.Errorf("expected %s at op %d, got %s at op %d", tt.invariant, len(tt.ops)-1, f.Invariant, f.Index)
+			}
+		})
+	}
+}
+
+func TestCheckReadConsistency(t *testing.T) {
+	// 書き込みの後に別のキーへの書き込みで値が壊れるストア
+	s := newMapStore()
+	funcs := s.store()
+	set := funcs.SetFunc
+	funcs.SetFunc = func(ctx context.Context, key string, value []byte) error {
+		if key == "b" {
+			s.mu.Lock()
+			s.data["a"] = []byte("corrupt")
+			s.mu.Unlock()
+		}
+		return set(ctx, key, value)
+	}
+
+	err := Check(context.Background(), funcs, []Op{
+		{Kind: OpSet, Key: "a", Value: []byte("1")},
+		{Kind: OpSet, Key: "b", Value: []byte("2")},
+		{Kind: OpGet, Key: "a"},
+	})
+	var f *Failure
+	if !errors.As(err, &f) || f.Invariant != InvariantRead {
+		t.Fatalf("expected a read consistency failure, got %v", err)
+	}
+	if !strings.Contains(f.Error(), `expected "1", got "corrupt"`) {
+		t.Errorf("expected the mismatch in the message, got %s", f.Error())
+	}
+}
+
+func TestCheckSizeUnsupported(t *testing.T) {
+	funcs := newMapStore().store()
+	funcs.SizeFunc = nil
+	if err := Check(context.Background(), funcs, []Op{{Kind: OpSet, Key: "a", Value: []byte("1")}}); err != nil {
+		t.Errorf("expected size to be skipped, got %v", err)
+	}
+}
+
+func TestRunShrinks(t *testing.T) {
+	// 空の値の書き込みだけを失う不具合は、空の値の set 1件まで縮小できる
+	newStore := func(testing.TB) Store {
+		s := newMapStore()
+		s.dropSet = func(_ string, value []byte) bool { return len(value) == 0 }
+		return s.store()
+	}
+
+	config := DefaultConfig()
+	config.Seed = 42
+	config.Gen.MaxValueLen = 2
+	f := run(t, newStore, config)
+	if f == nil {
+		t.Fatal("expected the property to fail")
+	}
+	if len(f.Ops) != 1 || f.Ops[0].Kind != OpSet || len(f.Ops[0].Value) != 0 {
+		t.Errorf("expected a single empty set after shrinking, got %v", f.Ops)
+	}
+	if f.Seed != 42 || !strings.Contains(f.Error(), "seed 42") {
+		t.Errorf("expected the seed to be reported, got %s", f.Error())
+	}
+}
+
+func FuzzNode(f *testing.F) {
+	Fuzz(f, newNode, DefaultGenConfig())
+}
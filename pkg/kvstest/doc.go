@@ -0,0 +1,44 @@
+// Package kvstest is a property-based and fuzz testing harness for
+// key-value stores.
+//
+// Operations (get, set, delete) are generated randomly with Generate or
+// decoded from fuzz input with Decode, applied to the store under test and
+// compared with a reference map. Check verifies these invariants after each
+// operation:
+//
+//   - read consistency: a get returns what the previous operations imply
+//   - get-after-set: a get right after a set returns the value just set
+//   - delete semantics: a deleted key is gone, and deleting a missing key
+//     succeeds
+//   - size consistency: the number of keys matches, for stores that
+//     implement Sizer
+//
+// The harness only needs the Store interface, so it runs unchanged against
+// a single Node, a store sharded over a cluster, or a replicated cluster
+// behind a network API. Wrap a node with Node, or anything else with Funcs.
+//
+// # Property Tests
+//
+// Run generates many operation sequences, each against a fresh store, and
+// shrinks the first failing sequence before reporting it with its seed:
+//
+//	func TestStore(t *testing.T) {
+//	    kvstest.Run(t, func(t testing.TB) kvstest.Store {
+//	        n := node.New("test")
+//	        _ = n.Start(context.Background())
+//	        t.Cleanup(func() { _ = n.Stop() })
+//	        return kvstest.Node(n)
+//	    }, kvstest.DefaultConfig())
+//	}
+//
+// # Fuzzing
+//
+// Fuzz registers seeds and a fuzz target that decodes the input into
+// operations:
+//
+//	func FuzzStore(f *testing.F) {
+//	    kvstest.Fuzz(f, newStore, kvstest.DefaultGenConfig())
+//	}
+//
+// Run it with go test -fuzz=FuzzStore.
+package kvstest
@@ -0,0 +1,121 @@
+package kvstest
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// OpKind は操作の種類
+type OpKind uint8
+
+const (
+	OpGet OpKind = iota
+	OpSet
+	OpDelete
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpGet:
+		return "get"
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Op はストアへの操作1件
+type Op struct {
+	Kind  OpKind
+	Key   string
+	Value []byte // OpSet のみ
+}
+
+func (o Op) String() string {
+	if o.Kind == OpSet {
+		return fmt.Sprintf("set %s=%q", o.Key, o.Value)
+	}
+	return fmt.Sprintf("%s %s", o.Kind, o.Key)
+}
+
+// GenConfig は操作の生成の設定
+type GenConfig struct {
+	Keys        int // キーの種類の数（少ないほど同じキーへの操作が重なる）
+	MaxValueLen int // 値の最大長（空の値も生成する）
+}
+
+// DefaultGenConfig はデフォルトの生成設定を返す
+func DefaultGenConfig() GenConfig {
+	return GenConfig{
+		Keys:        8,
+		MaxValueLen: 16,
+	}
+}
+
+// withDefaults は0の項目をデフォルト値で埋める
+func (c GenConfig) withDefaults() GenConfig {
+	defaults := DefaultGenConfig()
+	if c.Keys <= 0 {
+		c.Keys = defaults.Keys
+	}
+	if c.MaxValueLen < 0 {
+		c.MaxValueLen = 0
+	}
+	return c
+}
+
+// key は番号 i のキー名を返す
+func key(i int) string {
+	return fmt.Sprintf("key-%d", i)
+}
+
+// Generate は r から n 件の操作を生成する
+// 比率は set 40%・get 40%・delete 20%
+func Generate(r *rand.Rand, n int, config GenConfig) []Op {
+	config = config.withDefaults()
+
+	ops := make([]Op, n)
+	for i := range ops {
+		op := Op{Key: key(r.Intn(config.Keys))}
+		switch p := r.Intn(10); {
+		case p < 4:
+			op.Kind = OpSet
+			op.Value = make([]byte, r.Intn(config.MaxValueLen+1))
+			r.Read(op.Value)
+		case p < 8:
+			op.Kind = OpGet
+		default:
+			op.Kind = OpDelete
+		}
+		ops[i] = op
+	}
+	return ops
+}
+
+// Decode はファズの入力から操作を決定的に組み立てる
+// 1バイト目で種類とキーを、set の場合は続く1バイトで長さを決めて値を読む（入力の末尾で打ち切る）
+func Decode(data []byte, config GenConfig) []Op {
+	config = config.withDefaults()
+
+	var ops []Op
+	for len(data) > 0 {
+		b := int(data[0])
+		data = data[1:]
+		op := Op{Kind: OpKind(b % 3), Key: key(b / 3 % config.Keys)}
+		if op.Kind == OpSet {
+			n := 0
+			if len(data) > 0 {
+				n = int(data[0]) % (config.MaxValueLen + 1)
+				data = data[1:]
+			}
+			n = min(n, len(data))
+			op.Value = append([]byte{}, data[:n]...)
+			data = data[n:]
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
@@ -0,0 +1,71 @@
+package kvstest
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	config := GenConfig{Keys: 3, MaxValueLen: 4}
+	ops := Generate(rand.New(rand.NewSource(1)), 500, config)
+
+	if len(ops) != 500 {
+		t.Fatalf("expected 500 operations, got %d", len(ops))
+	}
+	kinds := map[OpKind]int{}
+	keys := map[string]bool{}
+	for _, op := range ops {
+		kinds[op.Kind]++
+		keys[op.Key] = true
+		if len(op.Value) > config.MaxValueLen {
+			t.Errorf("value longer than %d: %v", config.MaxValueLen, op)
+		}
+		if op.Kind != OpSet && op.Value != nil {
+			t.Errorf("expected no value for %v", op)
+		}
+	}
+	if len(keys) != 3 {
+		t.Errorf("expected operations on 3 keys, got %v", keys)
+	}
+	if kinds[OpSet] == 0 || kinds[OpGet] == 0 || kinds[OpDelete] == 0 {
+		t.Errorf("expected every kind of operation, got %v", kinds)
+	}
+
+	again := Generate(rand.New(rand.NewSource(1)), 500, config)
+	if !reflect.DeepEqual(ops, again) {
+		t.Error("expected the same seed to generate the same operations")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	data := []byte{
+		1, 2, 'h', 'i', // set key-0="hi"
+		3,    // get key-1
+		2,    // delete key-0
+		4, 9, // set key-1 with a length past the end of the input
+	}
+	ops := Decode(data, DefaultGenConfig())
+
+	want := []Op{
+		{Kind: OpSet, Key: "key-0", Value: []byte("hi")},
+		{Kind: OpGet, Key: "key-1"},
+		{Kind: OpDelete, Key: "key-0"},
+		{Kind: OpSet, Key: "key-1", Value: []byte{}},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("expected %v, got %v", want, ops)
+	}
+	if ops := Decode(nil, DefaultGenConfig()); len(ops) != 0 {
+		t.Errorf("expected no operations for empty input, got %v", ops)
+	}
+}
+
+func TestOpString(t *testing.T) {
+	if s := (Op{Kind: OpSet, Key: "k", Value: []byte("v")}).String(); s != `set k="v"` {
+		t.Errorf("unexpected string: %s", s)
+	}
+	if s := (Op{Kind: OpDelete, Key: "k"}).String(); s != "delete k" {
+		t.Errorf("unexpected string: %s", s)
+	}
+}
@@ -0,0 +1,79 @@
+package kvstest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// Store は検査対象のキーバリューストアの操作
+// ノード・シャーディングしたストア・レプリケーションしたクラスタなどをアダプターで包んで渡す
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Sizer はキーの数を返せるストア
+// Store が実装している場合は操作のたびにサイズの一貫性を検査する
+type Sizer interface {
+	Size(ctx context.Context) (int, error)
+}
+
+// ErrSizeUnsupported は Size を実装していないことを表す（サイズの検査を省略する）
+var ErrSizeUnsupported = errors.New("kvstest: size is not supported")
+
+// Funcs は関数を組み合わせて Store を作るアダプター
+// SizeFunc が nil の場合はサイズを検査しない
+type Funcs struct {
+	GetFunc    func(ctx context.Context, key string) ([]byte, bool, error)
+	SetFunc    func(ctx context.Context, key string, value []byte) error
+	DeleteFunc func(ctx context.Context, key string) error
+	SizeFunc   func(ctx context.Context) (int, error)
+}
+
+func (f Funcs) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return f.GetFunc(ctx, key)
+}
+
+func (f Funcs) Set(ctx context.Context, key string, value []byte) error {
+	return f.SetFunc(ctx, key, value)
+}
+
+func (f Funcs) Delete(ctx context.Context, key string) error {
+	return f.DeleteFunc(ctx, key)
+}
+
+func (f Funcs) Size(ctx context.Context) (int, error) {
+	if f.SizeFunc == nil {
+		return 0, ErrSizeUnsupported
+	}
+	return f.SizeFunc(ctx)
+}
+
+// Node は起動済みのノードを Store として包む
+func Node(n *node.Node) Store {
+	return nodeStore{n}
+}
+
+// nodeStore はノードの Store
+type nodeStore struct {
+	n *node.Node
+}
+
+func (s nodeStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.n.GetContext(ctx, key)
+}
+
+func (s nodeStore) Set(ctx context.Context, key string, value []byte) error {
+	return s.n.SetContext(ctx, key, value)
+}
+
+func (s nodeStore) Delete(ctx context.Context, key string) error {
+	return s.n.DeleteContext(ctx, key)
+}
+
+func (s nodeStore) Size(context.Context) (int, error) {
+	return s.n.Size(), nil
+}
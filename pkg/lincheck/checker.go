@@ -0,0 +1,270 @@
+package lincheck
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultMaxSteps は1つのパーティション（ノードとキーの組）の探索の上限のデフォルト
+	DefaultMaxSteps = 1000000
+
+	// maxViolations は結果に含める違反の最大数
+	maxViolations = 10
+
+	// maxViolationOps は違反ごとに結果に含める操作の最大数
+	maxViolationOps = 50
+)
+
+// Violation は線形化可能でなかったノードとキーの組
+type Violation struct {
+	Node            string      `json:"node"`
+	Key             string      `json:"key"`
+	TotalOperations int         `json:"total_operations"`
+	Operations      []Operation `json:"operations"` // 呼び出した順の操作（先頭の maxViolationOps 件）
+}
+
+// CheckResult は線形化可能性の検査結果
+type CheckResult struct {
+	Linearizable bool        `json:"linearizable"`  // 違反が見つからなかったか
+	Operations   int         `json:"operations"`    // 検査した操作の数
+	Partitions   int         `json:"partitions"`    // 検査したノードとキーの組の数
+	Unknown      int         `json:"unknown"`       // 探索の上限に達し判定できなかった組の数
+	ViolatedKeys int         `json:"violated_keys"` // 線形化可能でなかった組の数
+	Violations   []Violation `json:"violations"`    // 線形化可能でなかった組（先頭の maxViolations 件）
+	Truncated    bool        `json:"truncated"`     // 上限に達して記録しなかった操作があるか
+}
+
+// partitionKey は履歴を分割する単位（ノードとキーの組）
+type partitionKey struct {
+	node, key string
+}
+
+// Check は履歴をノードとキーの組ごとのレジスタとして線形化可能かを検査する
+// maxSteps は組ごとの探索の上限（0以下で無制限）
+func Check(ops []Operation, maxSteps int) *CheckResult {
+	partitions := make(map[partitionKey][]Operation)
+	var order []partitionKey
+	for _, op := range ops {
+		if op.Failed && op.Kind == KindGet {
+			continue // 失敗した読み込みは状態に影響しない
+		}
+		k := partitionKey{op.Node, op.Key}
+		if _, ok := partitions[k]; !ok {
+			order = append(order, k)
+		}
+		partitions[k] = append(partitions[k], op)
+	}
+
+	result := &CheckResult{Operations: len(ops), Partitions: len(partitions)}
+	for _, k := range order {
+		part := partitions[k]
+		// 読み込みのない組は常に線形化可能
+		if !slices.ContainsFunc(part, func(op Operation) bool { return op.Kind == KindGet }) {
+			continue
+		}
+
+		ok, complete := checkPartition(part, maxSteps)
+		switch {
+		case !complete:
+			result.Unknown++
+		case !ok:
+			result.ViolatedKeys++
+			if len(result.Violations) < maxViolations {
+				v := Violation{Node: k.node, Key: k.key, TotalOperations: len(part)}
+				v.Operations = slices.Clone(part[:min(len(part), maxViolationOps)])
+				slices.SortStableFunc(v.Operations, func(a, b Operation) int {
+					return cmp.Compare(a.Call, b.Call)
+				})
+				result.Violations = append(result.Violations, v)
+			}
+		}
+	}
+	result.Linearizable = result.ViolatedKeys == 0
+	return result
+}
+
+// register はレジスタ（1つのキー）の状態
+type register struct {
+	exists bool
+	value  uint64
+}
+
+// step は状態 s で操作 op が結果どおりに実行できるかと、実行後の状態を返す
+func step(s register, op Operation) (register, bool) {
+	if op.Kind == KindSet {
+		return register{exists: true, value: op.Value}, true
+	}
+	if op.Found != s.exists || op.Found && op.Value != s.value {
+		return s, false
+	}
+	return s, true
+}
+
+// entry は操作の呼び出しまたは応答のイベント。探索中は双方向リストでつなぐ
+type entry struct {
+	op         int // 操作の番号
+	call       bool
+	time       time.Duration
+	match      *entry // 呼び出しの場合は対応する応答
+	prev, next *entry
+}
+
+// cacheEntry は探索済みの（線形化した操作の集合, 状態）の組
+type cacheEntry struct {
+	linearized bitset
+	state      register
+}
+
+// checkPartition は1つのレジスタの履歴が線形化可能かを探索する
+// 探索が上限に達した場合は complete が false になる
+func checkPartition(ops []Operation, maxSteps int) (ok, complete bool) {
+	events := make([]*entry, 0, 2*len(ops))
+	for i, op := range ops {
+		ret := op.Return
+		if op.Failed {
+			ret = math.MaxInt64 // 反映されたかが不明な書き込みは、最後に線形化してもよい
+		}
+		r := &entry{op: i, time: ret}
+		events = append(events, &entry{op: i, call: true, time: op.Call, match: r}, r)
+	}
+	// 同時刻の場合は呼び出しを先にし、並行している操作として扱う
+	slices.SortStableFunc(events, func(a, b *entry) int {
+		if c := cmp.Compare(a.time, b.time); c != 0 {
+			return c
+		}
+		switch {
+		case a.call && !b.call:
+			return -1
+		case !a.call && b.call:
+			return 1
+		}
+		return 0
+	})
+
+	head := &entry{}
+	prev := head
+	for _, e := range events {
+		e.prev = prev
+		prev.next = e
+		prev = e
+	}
+
+	type frame struct {
+		e     *entry
+		state register
+	}
+	var (
+		calls      []frame
+		state      register
+		linearized = newBitset(len(ops))
+		cache      = make(map[uint64][]cacheEntry)
+	)
+
+	e := head.next
+	for steps := 0; head.next != nil; steps++ {
+		if maxSteps > 0 && steps >= maxSteps {
+			return false, false
+		}
+
+		if e.call {
+			if next, ok := step(state, ops[e.op]); ok {
+				lin := linearized.clone().set(e.op)
+				if cacheAdd(cache, cacheEntry{lin, next}) {
+					calls = append(calls, frame{e, state})
+					state = next
+					linearized.set(e.op)
+					lift(e)
+					e = head.next
+					continue
+				}
+			}
+			e = e.next
+			continue
+		}
+
+		// 線形化していない操作の応答に達したため、直前の選択を取り消す
+		if len(calls) == 0 {
+			return false, true
+		}
+		top := calls[len(calls)-1]
+		calls = calls[:len(calls)-1]
+		e, state = top.e, top.state
+		linearized.clear(e.op)
+		unlift(e)
+		e = e.next
+	}
+	return true, true
+}
+
+// lift は呼び出し e と対応する応答をリストから外す
+func lift(e *entry) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	m := e.match
+	m.prev.next = m.next
+	if m.next != nil {
+		m.next.prev = m.prev
+	}
+}
+
+// unlift は lift で外した呼び出し e と対応する応答をリストに戻す
+func unlift(e *entry) {
+	m := e.match
+	m.prev.next = m
+	if m.next != nil {
+		m.next.prev = m
+	}
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// cacheAdd は探索済みでなければ追加して true を返す
+func cacheAdd(cache map[uint64][]cacheEntry, ce cacheEntry) bool {
+	h := ce.linearized.hash()
+	for _, existing := range cache[h] {
+		if existing.state == ce.state && existing.linearized.equals(ce.linearized) {
+			return false
+		}
+	}
+	cache[h] = append(cache[h], ce)
+	return true
+}
+
+// bitset は線形化した操作の集合
+type bitset []uint64
+
+// newBitset は n 個の要素を持てる空の集合を作成する
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) clone() bitset {
+	return slices.Clone(b)
+}
+
+func (b bitset) set(i int) bitset {
+	b[i/64] |= 1 << (i % 64)
+	return b
+}
+
+func (b bitset) clear(i int) bitset {
+	b[i/64] &^= 1 << (i % 64)
+	return b
+}
+
+func (b bitset) equals(other bitset) bool {
+	return slices.Equal(b, other)
+}
+
+// hash は集合のハッシュ値を返す（FNV-1a と同じ係数で各ワードを混ぜる）
+func (b bitset) hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, w := range b {
+		h ^= w
+		h *= 1099511628211
+	}
+	return h
+}
@@ -0,0 +1,127 @@
+package lincheck
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// set は書き込みの操作を作成する（時刻はミリ秒）
+func set(node, key string, value uint64, call, ret int) Operation {
+	return Operation{Node: node, Key: key, Kind: KindSet, Value: value,
+		Call: time.Duration(call) * time.Millisecond, Return: time.Duration(ret) * time.Millisecond}
+}
+
+// get は値が見つかった読み込みの操作を作成する（value が0の場合は見つからなかった読み込み）
+func get(node, key string, value uint64, call, ret int) Operation {
+	return Operation{Node: node, Key: key, Kind: KindGet, Value: value, Found: value != 0,
+		Call: time.Duration(call) * time.Millisecond, Return: time.Duration(ret) * time.Millisecond}
+}
+
+// failed は操作をエラーで終わったものにする
+func failed(op Operation) Operation {
+	op.Failed = true
+	return op
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []Operation
+		want bool
+	}{
+		{"sequential", []Operation{
+			get("n", "k", 0, 0, 1), set("n", "k", 1, 2, 3), get("n", "k", 1, 4, 5),
+		}, true},
+		{"stale read", []Operation{
+			set("n", "k", 1, 0, 1), set("n", "k", 2, 2, 3), get("n", "k", 1, 4, 5),
+		}, false},
+		{"read concurrent with write sees new value", []Operation{
+			set("n", "k", 1, 0, 10), get("n", "k", 1, 1, 2),
+		}, true},
+		{"read concurrent with write sees old value", []Operation{
+			set("n", "k", 1, 0, 10), get("n", "k", 0, 1, 2),
+		}, true},
+		{"reads disagree on order", []Operation{
+			set("n", "k", 1, 0, 10), set("n", "k", 2, 0, 10),
+			get("n", "k", 1, 11, 12), get("n", "k", 2, 13, 14), get("n", "k", 1, 15, 16),
+		}, false},
+		{"value never written", []Operation{
+			set("n", "k", 1, 0, 1), get("n", "k", 9, 2, 3),
+		}, false},
+		{"missing after write", []Operation{
+			set("n", "k", 1, 0, 1), get("n", "k", 0, 2, 3),
+		}, false},
+		{"failed write may have been applied", []Operation{
+			failed(set("n", "k", 1, 0, 1)), get("n", "k", 1, 2, 3),
+		}, true},
+		{"failed write may not have been applied", []Operation{
+			failed(set("n", "k", 1, 0, 1)), get("n", "k", 0, 2, 3),
+		}, true},
+		{"failed write cannot be undone", []Operation{
+			failed(set("n", "k", 1, 0, 1)), get("n", "k", 1, 2, 3), get("n", "k", 0, 4, 5),
+		}, false},
+		{"failed read is ignored", []Operation{
+			set("n", "k", 1, 0, 1), failed(get("n", "k", 0, 2, 3)),
+		}, true},
+		{"nodes are independent", []Operation{
+			set("node-1", "k", 1, 0, 1), get("node-2", "k", 0, 2, 3),
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(tt.ops, DefaultMaxSteps)
+			if result.Linearizable != tt.want {
+				t.Errorf("expected linearizable=%v, got %+v", tt.want, result)
+			}
+			if result.Operations != len(tt.ops) {
+				t.Errorf("expected %d operations, got %d", len(tt.ops), result.Operations)
+			}
+		})
+	}
+}
+
+func TestCheckViolations(t *testing.T) {
+	var ops []Operation
+	for i := range maxViolations + 5 {
+		key := fmt.Sprintf("key-%d", i)
+		ops = append(ops, set("n", key, 1, 0, 1), get("n", key, 0, 2, 3))
+	}
+	ops = append(ops, set("n", "ok", 1, 0, 1), get("n", "ok", 1, 2, 3))
+
+	result := Check(ops, DefaultMaxSteps)
+	if result.Linearizable {
+		t.Fatal("expected violations")
+	}
+	if result.Partitions != maxViolations+6 {
+		t.Errorf("expected %d partitions, got %d", maxViolations+6, result.Partitions)
+	}
+	if result.ViolatedKeys != maxViolations+5 {
+		t.Errorf("expected %d violated keys, got %d", maxViolations+5, result.ViolatedKeys)
+	}
+	if len(result.Violations) != maxViolations {
+		t.Fatalf("expected %d violations listed, got %d", maxViolations, len(result.Violations))
+	}
+	v := result.Violations[0]
+	if v.Node != "n" || v.Key != "key-0" || v.TotalOperations != 2 || len(v.Operations) != 2 {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+}
+
+func TestCheckStepLimit(t *testing.T) {
+	// 全ての書き込みが並行しているため、探索には多くの手順が必要になる
+	var ops []Operation
+	for i := range 12 {
+		ops = append(ops, set("n", "k", uint64(i+1), 0, 100))
+	}
+	ops = append(ops, get("n", "k", 99, 101, 102))
+
+	result := Check(ops, 10)
+	if result.Unknown != 1 || !result.Linearizable {
+		t.Errorf("expected the partition to be unknown, got %+v", result)
+	}
+	if result := Check(ops, 0); result.Unknown != 0 || result.Linearizable {
+		t.Errorf("expected a violation without a step limit, got %+v", result)
+	}
+}
@@ -0,0 +1,30 @@
+// Package lincheck records the operations issued by the load generator and
+// checks the recorded history for linearizability.
+//
+// # Recording
+//
+//	rec := lincheck.NewRecorder(lincheck.DefaultConfig())
+//	call, ok := rec.Begin(isWrite)
+//	// ... perform the operation ...
+//	if ok {
+//	    rec.End(lincheck.Operation{Node: id, Key: key, Kind: lincheck.KindSet,
+//	        Value: lincheck.Hash(value), Call: call, Failed: err != nil})
+//	}
+//
+// Values are stored as their FNV-1a hash to keep the history small. Once
+// Config.MaxOperations operations have been started, new reads are no longer
+// recorded; writes are still recorded while any recorded operation is in
+// flight, so a recorded read never observes a write missing from the history.
+//
+// # Checking
+//
+//	result := lincheck.Check(rec.Operations(), lincheck.DefaultMaxSteps)
+//
+// The history is partitioned by node and key, and each partition is checked
+// against a register model with the algorithm of Wing and Gong, using the
+// memoization by Lowe (the approach taken by Porcupine). A key starts out
+// absent. A read that failed has no effect and is dropped; a write that failed
+// may or may not have taken effect, so it is treated as returning at the end
+// of the history. A partition whose search exceeds the step limit is counted
+// as unknown rather than as a violation.
+package lincheck
@@ -0,0 +1,112 @@
+package lincheck
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Kind は操作の種類
+type Kind string
+
+const (
+	KindGet Kind = "get"
+	KindSet Kind = "set"
+)
+
+// Operation は記録した1つの操作
+type Operation struct {
+	Node   string        `json:"node"`
+	Key    string        `json:"key"`
+	Kind   Kind          `json:"kind"`
+	Value  uint64        `json:"value"`  // 値のFNV-1aハッシュ（Get で見つからなかった場合は0）
+	Found  bool          `json:"found"`  // Get で値が見つかったか
+	Failed bool          `json:"failed"` // エラーで終わったか（Set は反映されたかが不明とみなす）
+	Call   time.Duration `json:"call"`   // 呼び出した時刻（記録開始からの経過時間）
+	Return time.Duration `json:"return"` // 応答を受けた時刻（記録開始からの経過時間）
+}
+
+// Hash は値のFNV-1aハッシュを返す
+func Hash(value []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(value)
+	return h.Sum64()
+}
+
+// Config は記録の設定
+type Config struct {
+	MaxOperations int // 記録する操作の上限（0で無制限）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		MaxOperations: 100000,
+	}
+}
+
+// Recorder は並行して実行される操作を記録する
+type Recorder struct {
+	config Config
+	start  time.Time
+
+	mu        sync.Mutex
+	started   int // 記録を開始した操作の数
+	inflight  int // 記録を開始し、まだ終了していない操作の数
+	truncated bool
+	ops       []Operation
+}
+
+// NewRecorder は記録を開始する。操作の時刻はこの時点からの経過時間で記録する
+func NewRecorder(config Config) *Recorder {
+	return &Recorder{config: config, start: time.Now()}
+}
+
+// Begin は操作の呼び出しを記録し、呼び出した時刻を返す
+// 上限に達して記録しない場合は false を返す。true の場合は必ず End を呼ぶ
+func (r *Recorder) Begin(write bool) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.config.MaxOperations > 0 && r.started >= r.config.MaxOperations {
+		r.truncated = true
+		// 記録済みの読み込みが観測しうる書き込みは、上限を超えても記録する
+		if !write || r.inflight == 0 {
+			return 0, false
+		}
+	}
+	r.started++
+	r.inflight++
+	return time.Since(r.start), true
+}
+
+// End は操作の応答を記録する。op.Call には Begin が返した時刻を設定する
+func (r *Recorder) End(op Operation) {
+	op.Return = time.Since(r.start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inflight--
+	r.ops = append(r.ops, op)
+}
+
+// Operations は記録した操作を呼び出した順に返す
+func (r *Recorder) Operations() []Operation {
+	r.mu.Lock()
+	ops := slices.Clone(r.ops)
+	r.mu.Unlock()
+
+	slices.SortStableFunc(ops, func(a, b Operation) int {
+		return cmp.Compare(a.Call, b.Call)
+	})
+	return ops
+}
+
+// Truncated は上限に達して記録しなかった操作があるかを返す
+func (r *Recorder) Truncated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.truncated
+}
@@ -0,0 +1,75 @@
+package lincheck
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	if Hash([]byte("a")) == Hash([]byte("b")) {
+		t.Error("expected different hashes for different values")
+	}
+	if Hash([]byte("a")) != Hash([]byte("a")) {
+		t.Error("expected equal hashes for equal values")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(DefaultConfig())
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call, ok := r.Begin(i%2 == 0)
+			if !ok {
+				t.Error("expected operation to be recorded")
+				return
+			}
+			r.End(Operation{Node: "n", Key: "k", Kind: KindGet, Call: call})
+		}()
+	}
+	wg.Wait()
+
+	ops := r.Operations()
+	if len(ops) != 50 {
+		t.Fatalf("expected 50 operations, got %d", len(ops))
+	}
+	for i, op := range ops {
+		if op.Return < op.Call {
+			t.Errorf("operation %d returned before it was called: %+v", i, op)
+		}
+		if i > 0 && op.Call < ops[i-1].Call {
+			t.Error("expected operations in call order")
+		}
+	}
+	if r.Truncated() {
+		t.Error("expected history not to be truncated")
+	}
+}
+
+func TestRecorderLimit(t *testing.T) {
+	r := NewRecorder(Config{MaxOperations: 2})
+
+	call1, _ := r.Begin(false)
+	call2, _ := r.Begin(false)
+	// 上限に達した後も、記録中の操作があれば書き込みは記録する
+	if _, ok := r.Begin(false); ok {
+		t.Error("expected read over the limit not to be recorded")
+	}
+	call3, ok := r.Begin(true)
+	if !ok {
+		t.Fatal("expected write to be recorded while operations are in flight")
+	}
+	r.End(Operation{Kind: KindGet, Call: call1})
+	r.End(Operation{Kind: KindGet, Call: call2})
+	r.End(Operation{Kind: KindSet, Call: call3})
+
+	if _, ok := r.Begin(true); ok {
+		t.Error("expected write not to be recorded once nothing is in flight")
+	}
+	if len(r.Operations()) != 3 || !r.Truncated() {
+		t.Errorf("expected 3 operations and a truncated history, got %d (truncated: %v)", len(r.Operations()), r.Truncated())
+	}
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize はデフォルトのロガーが保持するログの行数
+const DefaultBufferSize = 1000
+
+// Entry は記録されたログ1行
+type Entry struct {
+	Seq       uint64         `json:"seq"` // 記録順の通し番号（1から）
+	Time      time.Time      `json:"time"`
+	Level     Level          `json:"level"`
+	NodeID    string         `json:"node_id,omitempty"`
+	Component string         `json:"component,omitempty"`
+	EventType string         `json:"event_type,omitempty"`
+	Fields    map[string]any `json:"fields,omitempty"` // 子ロガーのフィールド
+	Message   string         `json:"message"`
+}
+
+// ParseLevel はログレベル名を解析する（大文字小文字を区別しない、warning は warn の別名）
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level: %s", s)
+	}
+}
+
+// MarshalText はログレベルを名前で出力する
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText はログレベル名を解析する
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Buffer は直近のログを保持するリングバッファ
+// 古い行から上書きされる。購読者には新しい行を非同期に通知する
+type Buffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int  // 次に書き込む位置
+	full    bool // 一周したかどうか
+	seq     uint64
+	subs    map[chan Entry]struct{}
+}
+
+// NewBuffer は size 行を保持するバッファを作成する
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Buffer{
+		entries: make([]Entry, size),
+		subs:    make(map[chan Entry]struct{}),
+	}
+}
+
+// add はログを記録し、購読者に通知する（受信が追いつかない購読者には破棄する）
+// 通し番号を付けたログを返す
+func (b *Buffer) add(e Entry) Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e.Seq = b.seq
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	return e
+}
+
+// Entries は minLevel 以上で since より後（ゼロ値の場合は全て）のログを古い順に返す
+func (b *Buffer) Entries(minLevel Level, since time.Time) []Entry {
+	return b.Query(Query{MinLevel: minLevel, Since: since})
+}
+
+// Query は Buffer から取り出すログの条件（ゼロ値のフィールドは全てに一致する）
+type Query struct {
+	MinLevel   Level     // このレベル以上
+	NodeID     string    // このノードのログのみ
+	Components []string  // これらのコンポーネントのログのみ
+	Since      time.Time // この時刻より後
+	Until      time.Time // この時刻以前
+	Limit      int       // 新しい方から残す件数
+}
+
+// match はログが条件に一致するかを返す
+func (q Query) match(e Entry) bool {
+	if e.Level < q.MinLevel || (q.NodeID != "" && e.NodeID != q.NodeID) {
+		return false
+	}
+	if !e.Time.After(q.Since) || (!q.Until.IsZero() && e.Time.After(q.Until)) {
+		return false
+	}
+	if len(q.Components) > 0 && !slices.Contains(q.Components, e.Component) {
+		return false
+	}
+	return true
+}
+
+// Query は条件に一致するログを古い順に返す
+func (b *Buffer) Query(q Query) []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ordered := b.entries[:b.next]
+	if b.full {
+		ordered = append(append([]Entry{}, b.entries[b.next:]...), b.entries[:b.next]...)
+	}
+
+	result := make([]Entry, 0, len(ordered))
+	for _, e := range ordered {
+		if q.match(e) {
+			result = append(result, e)
+		}
+	}
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result
+}
+
+// Subscribe は新しいログを受け取るチャネルと、購読を解除する関数を返す
+// size は受信側のバッファサイズ
+func (b *Buffer) Subscribe(size int) (<-chan Entry, func()) {
+	ch := make(chan Entry, size)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBufferWraparound(t *testing.T) {
+	b := NewBuffer(3)
+	l := New(io.Discard, LevelDebug)
+	l.SetBuffer(b)
+
+	for _, msg := range []string{"a", "b", "c", "d", "e"} {
+		l.Info("", "%s", msg)
+	}
+
+	entries := b.Entries(LevelDebug, time.Time{})
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []string{"c", "d", "e"} {
+		if entries[i].Message != want {
+			t.Errorf("entry %d: expected %q, got %q", i, want, entries[i].Message)
+		}
+	}
+	if entries[2].Seq != 5 {
+		t.Errorf("expected seq 5, got %d", entries[2].Seq)
+	}
+}
+
+func TestBufferFilter(t *testing.T) {
+	b := NewBuffer(10)
+	l := New(io.Discard, LevelInfo)
+	l.SetBuffer(b)
+
+	l.Debug("", "hidden")
+	l.Info("node-1", "info")
+	l.Warn("node-1", "warn")
+	mark := time.Now()
+	time.Sleep(time.Millisecond)
+	l.Error("", "error")
+
+	if got := len(b.Entries(LevelDebug, time.Time{})); got != 3 {
+		t.Errorf("expected lines below the logger level to be skipped, got %d entries", got)
+	}
+
+	warn := b.Entries(LevelWarn, time.Time{})
+	if len(warn) != 2 || warn[0].NodeID != "node-1" {
+		t.Errorf("unexpected warn entries: %+v", warn)
+	}
+
+	recent := b.Entries(LevelDebug, mark)
+	if len(recent) != 1 || recent[0].Message != "error" {
+		t.Errorf("unexpected entries since mark: %+v", recent)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"node", Query{NodeID: "node-1"}, []string{"info", "warn"}},
+		{"until", Query{Until: mark}, []string{"info", "warn"}},
+		{"limit", Query{MinLevel: LevelInfo, Limit: 2}, []string{"warn", "error"}},
+		{"component", Query{Components: []string{"chaos"}}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, e := range b.Query(tt.query) {
+			got = append(got, e.Message)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBufferSubscribe(t *testing.T) {
+	b := NewBuffer(10)
+	l := New(io.Discard, LevelInfo)
+	l.SetBuffer(b)
+
+	ch, cancel := b.Subscribe(1)
+	l.Info("", "first")
+	l.Info("", "dropped")
+
+	select {
+	case e := <-ch:
+		if e.Message != "first" {
+			t.Errorf("expected first, got %q", e.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for entry")
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after cancel")
+	}
+	l.Info("", "after cancel")
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"Warning": LevelWarn,
+		"error":   LevelError,
+	}
+	for input, want := range tests {
+		if got, err := ParseLevel(input); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestEntryJSON(t *testing.T) {
+	data, err := json.Marshal(Entry{Seq: 1, Level: LevelWarn, Message: "m"})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var decoded map[string]any
+	_ = json.Unmarshal(data, &decoded)
+	if decoded["level"] != "WARN" {
+		t.Errorf("expected level WARN, got %v", decoded["level"])
+	}
+	if _, ok := decoded["node_id"]; ok {
+		t.Error("expected empty node_id to be omitted")
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Level != LevelWarn {
+		t.Errorf("expected level to round-trip, got %v, %v", e.Level, err)
+	}
+}
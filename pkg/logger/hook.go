@@ -0,0 +1,53 @@
+package logger
+
+import "slices"
+
+// Hook は出力したログを他の出力先（イベントバス、WebSocket、外部の収集基盤など）に転送する
+// Fire はログを出力したゴルーチンで同期的に呼び出されるため、時間のかかる処理は非同期に行う
+type Hook interface {
+	Fire(e Entry)
+}
+
+// HookFunc は関数を Hook として使うためのアダプタ
+type HookFunc func(e Entry)
+
+// Fire は f(e) を呼び出す
+func (f HookFunc) Fire(e Entry) {
+	f(e)
+}
+
+// hook は登録されたフック（解除のため登録ごとに区別する）
+type hook struct {
+	minLevel Level
+	h        Hook
+}
+
+// fire は minLevel 以上のログをフックに渡す
+func (h *hook) fire(e Entry) {
+	if e.Level >= h.minLevel {
+		h.h.Fire(e)
+	}
+}
+
+// AddHook は minLevel 以上のログを受け取るフックを登録し、登録を解除する関数を返す
+// フックは親子のロガーで共有され、間引きで出力しなかったログは渡されない
+func (l *Logger) AddHook(minLevel Level, h Hook) (remove func()) {
+	entry := &hook{minLevel: minLevel, h: h}
+
+	c := l.core
+	c.mu.Lock()
+	// 呼び出し中のフック一覧を書き換えないよう、常に新しいスライスを作る
+	c.hooks = append(slices.Clip(c.hooks), entry)
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.hooks = slices.DeleteFunc(slices.Clone(c.hooks), func(x *hook) bool { return x == entry })
+	}
+}
+
+// AddHook はデフォルトのロガーにフックを登録する
+func AddHook(minLevel Level, h Hook) (remove func()) {
+	return Default.AddHook(minLevel, h)
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestLoggerHook(t *testing.T) {
+	l := New(io.Discard, LevelDebug)
+	l.SetBuffer(NewBuffer(10))
+
+	var mu sync.Mutex
+	var all, warnings []Entry
+	removeAll := l.AddHook(LevelDebug, HookFunc(func(e Entry) {
+		mu.Lock()
+		all = append(all, e)
+		mu.Unlock()
+	}))
+	l.AddHook(LevelWarn, HookFunc(func(e Entry) {
+		mu.Lock()
+		warnings = append(warnings, e)
+		mu.Unlock()
+	}))
+
+	child := l.With("chaos")
+	child.Info("node-1", "info message")
+	child.Event(LevelWarn, "node-1", "chaos_attack", "killed %s", "node-1")
+
+	if len(all) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(all))
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	w := warnings[0]
+	if w.Message != "killed node-1" || w.Component != "chaos" || w.EventType != "chaos_attack" || w.NodeID != "node-1" {
+		t.Errorf("unexpected entry: %+v", w)
+	}
+	if w.Seq != 2 {
+		t.Errorf("expected Seq from buffer to be 2, got %d", w.Seq)
+	}
+
+	removeAll()
+	removeAll() // 2回呼んでも問題ない
+	l.Warn("", "after remove")
+	if len(all) != 2 {
+		t.Errorf("removed hook should not be called, got %d entries", len(all))
+	}
+	if len(warnings) != 2 {
+		t.Errorf("remaining hook should be called, got %d warnings", len(warnings))
+	}
+}
+
+func TestLoggerHookCanLog(t *testing.T) {
+	l := New(io.Discard, LevelDebug)
+
+	var forwarded []string
+	l.AddHook(LevelError, HookFunc(func(e Entry) {
+		forwarded = append(forwarded, e.Message)
+		// フック内でのログ出力はデッドロックしない
+		l.Info("", "forwarded %s", e.Message)
+	}))
+
+	l.Error("", "boom")
+	if len(forwarded) != 1 || forwarded[0] != "boom" {
+		t.Errorf("unexpected forwarded entries: %v", forwarded)
+	}
+}
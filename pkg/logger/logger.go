@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level はログレベルを表す
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Logger はスレッドセーフなロガー
+// With で作成した子ロガーは出力先・レベル・形式・バッファを親と共有する
+type Logger struct {
+	core      *core
+	component string  // 出力元のコンポーネント（chaos, recovery など）
+	fields    []Field // 全ての出力に付けるフィールド
+}
+
+// core は親子のロガーで共有する出力設定
+type core struct {
+	mu       sync.Mutex
+	out      io.Writer
+	minLevel Level
+	buffer   *Buffer      // 直近のログの記録先（nilの場合は記録しない）
+	format   Format       // 出力形式
+	handler  slog.Handler // 構造化ログの出力先（FormatText では nil）
+	sampler  *sampler     // メッセージごとの間引き（nilで無効）
+	hooks    []*hook      // 出力したログの転送先
+}
+
+// Recent はデフォルトのロガーが記録する直近のログ
+var Recent = NewBuffer(DefaultBufferSize)
+
+// Default はデフォルトのロガー
+var Default = func() *Logger {
+	l := New(os.Stdout, LevelInfo)
+	l.SetBuffer(Recent)
+	return l
+}()
+
+// New は新しいロガーを作成する
+func New(out io.Writer, minLevel Level) *Logger {
+	return &Logger{
+		core: &core{
+			out:      out,
+			minLevel: minLevel,
+		},
+	}
+}
+
+// With はコンポーネント名とフィールドを付けて出力する子ロガーを返す
+// component が空の場合は親のコンポーネント名を引き継ぎ、フィールドは親のものに追加される
+func (l *Logger) With(component string, fields ...Field) *Logger {
+	if component == "" {
+		component = l.component
+	}
+	return &Logger{
+		core:      l.core,
+		component: component,
+		fields:    append(append([]Field{}, l.fields...), fields...),
+	}
+}
+
+// SetLevel はログレベルを設定する
+func (l *Logger) SetLevel(level Level) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.minLevel = level
+}
+
+// SetBuffer は出力したログを記録するバッファを設定する（nilで記録しない）
+func (l *Logger) SetBuffer(b *Buffer) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.buffer = b
+}
+
+// SetFormat は出力形式を設定する
+// FormatKV・FormatJSON では log/slog のハンドラで1行1レコードの構造化ログを出力する
+func (l *Logger) SetFormat(format Format) {
+	c := l.core
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.format = format
+	c.resetHandler()
+}
+
+// SetOutput は出力先を設定する（ログファイルへの切り替えなど）
+func (l *Logger) SetOutput(out io.Writer) {
+	c := l.core
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.out = out
+	c.resetHandler()
+}
+
+// Output は出力先を返す
+func (l *Logger) Output() io.Writer {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	return l.core.out
+}
+
+// resetHandler は出力形式と出力先に合わせて構造化ログのハンドラを作り直す（c.mu を保持して呼び出す）
+func (c *core) resetHandler() {
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug} // レベルの判定は Logger で行う
+	switch c.format {
+	case FormatKV:
+		c.handler = slog.NewTextHandler(c.out, opts)
+	case FormatJSON:
+		c.handler = slog.NewJSONHandler(c.out, opts)
+	default:
+		c.handler = nil
+	}
+}
+
+// Format は出力形式を返す
+func (l *Logger) Format() Format {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	return l.core.format
+}
+
+// Log は指定されたレベルでログを出力する
+func (l *Logger) Log(level Level, nodeID string, format string, args ...any) {
+	l.Event(level, nodeID, "", format, args...)
+}
+
+// Event はイベントの種類（chaos_attack など）を付けてログを出力する
+func (l *Logger) Event(level Level, nodeID, eventType string, format string, args ...any) {
+	c := l.core
+	c.mu.Lock()
+
+	if level < c.minLevel {
+		c.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	suppressed := 0
+	if c.sampler != nil {
+		var ok bool
+		key := sampleKey{level: level, component: l.component, format: format}
+		if ok, suppressed = c.sampler.allow(key, now); !ok {
+			c.mu.Unlock()
+			return
+		}
+	}
+	msg := fmt.Sprintf(format, args...) + suppressedSuffix(suppressed)
+
+	entry := Entry{
+		Time:      now,
+		Level:     level,
+		NodeID:    nodeID,
+		Component: l.component,
+		EventType: eventType,
+		Fields:    fieldMap(l.fields),
+		Message:   msg,
+	}
+	if c.buffer != nil {
+		entry = c.buffer.add(entry)
+	}
+	c.write(l, entry)
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	// フックはロックを解放してから呼び出す（フック内でログを出力してもデッドロックしない）
+	for _, h := range hooks {
+		h.fire(entry)
+	}
+}
+
+// write はログを出力先に書き込む（c.mu を保持して呼び出す）
+func (c *core) write(l *Logger, e Entry) {
+	if c.handler != nil {
+		record := slog.NewRecord(e.Time, e.Level.slogLevel(), e.Message, 0)
+		record.AddAttrs(l.attrs(e.NodeID, e.EventType)...)
+		_ = c.handler.Handle(context.Background(), record)
+		return
+	}
+
+	timestamp := e.Time.Format("2006-01-02 15:04:05.000")
+	msg := e.Message + formatFields(l.fields)
+	if e.NodeID != "" {
+		_, _ = fmt.Fprintf(c.out, "[%s] [%s] [%s] %s\n", timestamp, e.Level, e.NodeID, msg)
+	} else {
+		_, _ = fmt.Fprintf(c.out, "[%s] [%s] %s\n", timestamp, e.Level, msg)
+	}
+}
+
+// Debug はデバッグログを出力する
+func (l *Logger) Debug(nodeID string, format string, args ...any) {
+	l.Log(LevelDebug, nodeID, format, args...)
+}
+
+// Info は情報ログを出力する
+func (l *Logger) Info(nodeID string, format string, args ...any) {
+	l.Log(LevelInfo, nodeID, format, args...)
+}
+
+// Warn は警告ログを出力する
+func (l *Logger) Warn(nodeID string, format string, args ...any) {
+	l.Log(LevelWarn, nodeID, format, args...)
+}
+
+// Error はエラーログを出力する
+func (l *Logger) Error(nodeID string, format string, args ...any) {
+	l.Log(LevelError, nodeID, format, args...)
+}
+
+// グローバル関数（デフォルトロガーを使用）
+
+// Debug はデバッグログを出力する
+func Debug(nodeID string, format string, args ...any) {
+	Default.Debug(nodeID, format, args...)
+}
+
+// Info は情報ログを出力する
+func Info(nodeID string, format string, args ...any) {
+	Default.Info(nodeID, format, args...)
+}
+
+// Warn は警告ログを出力する
+func Warn(nodeID string, format string, args ...any) {
+	Default.Warn(nodeID, format, args...)
+}
+
+// Error はエラーログを出力する
+func Error(nodeID string, format string, args ...any) {
+	Default.Error(nodeID, format, args...)
+}
+
+// With はデフォルトのロガーにコンポーネント名とフィールドを付けた子ロガーを返す
+func With(component string, fields ...Field) *Logger {
+	return Default.With(component, fields...)
+}
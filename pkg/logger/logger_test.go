@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		level    Level
+		expected string
+	}{
+		{LevelDebug, "DEBUG"},
+		{LevelInfo, "INFO"},
+		{LevelWarn, "WARN"},
+		{LevelError, "ERROR"},
+		{Level(99), "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.level.String(); got != tt.expected {
+			t.Errorf("Level(%d).String() = %s, want %s", tt.level, got, tt.expected)
+		}
+	}
+}
+
+func TestLoggerOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelDebug)
+
+	l.Debug("node-1", "debug message")
+	l.Info("node-1", "info message")
+	l.Warn("node-1", "warn message")
+	l.Error("node-1", "error message")
+
+	output := buf.String()
+
+	if !strings.Contains(output, "[DEBUG]") {
+		t.Error("expected DEBUG log")
+	}
+	if !strings.Contains(output, "[INFO]") {
+		t.Error("expected INFO log")
+	}
+	if !strings.Contains(output, "[WARN]") {
+		t.Error("expected WARN log")
+	}
+	if !strings.Contains(output, "[ERROR]") {
+		t.Error("expected ERROR log")
+	}
+	if !strings.Contains(output, "[node-1]") {
+		t.Error("expected node ID in log")
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelWarn)
+
+	l.Debug("", "debug message")
+	l.Info("", "info message")
+	l.Warn("", "warn message")
+	l.Error("", "error message")
+
+	output := buf.String()
+
+	if strings.Contains(output, "[DEBUG]") {
+		t.Error("DEBUG should be filtered")
+	}
+	if strings.Contains(output, "[INFO]") {
+		t.Error("INFO should be filtered")
+	}
+	if !strings.Contains(output, "[WARN]") {
+		t.Error("expected WARN log")
+	}
+	if !strings.Contains(output, "[ERROR]") {
+		t.Error("expected ERROR log")
+	}
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelError)
+
+	l.Info("", "should not appear")
+
+	if strings.Contains(buf.String(), "should not appear") {
+		t.Error("INFO should be filtered at ERROR level")
+	}
+
+	l.SetLevel(LevelInfo)
+	l.Info("", "should appear")
+
+	if !strings.Contains(buf.String(), "should appear") {
+		t.Error("INFO should appear after SetLevel")
+	}
+}
+
+func TestLoggerWithoutNodeID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelInfo)
+
+	l.Info("", "message without node")
+
+	output := buf.String()
+
+	// Should not have empty brackets
+	if strings.Contains(output, "[]") {
+		t.Error("should not have empty brackets for nodeID")
+	}
+	if !strings.Contains(output, "message without node") {
+		t.Error("expected message in output")
+	}
+}
+
+func TestLoggerFormatArgs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelInfo)
+
+	l.Info("node-1", "count: %d, name: %s", 42, "test")
+
+	output := buf.String()
+
+	if !strings.Contains(output, "count: 42, name: test") {
+		t.Errorf("expected formatted message, got: %s", output)
+	}
+}
+
+func TestLoggerStructuredFormats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelInfo)
+	l.SetBuffer(NewBuffer(10))
+
+	l.SetFormat(FormatJSON)
+	l.With("chaos").Event(LevelWarn, "node-1", "chaos_attack", "killed node %s", "node-1")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":      "WARN",
+		"msg":        "killed node node-1",
+		"node_id":    "node-1",
+		"component":  "chaos",
+		"event_type": "chaos_attack",
+	}
+	for k, v := range want {
+		if record[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, record[k])
+		}
+	}
+
+	buf.Reset()
+	l.SetFormat(FormatKV)
+	l.Info("", "started")
+	if got := buf.String(); !strings.Contains(got, "level=INFO") || !strings.Contains(got, "msg=started") || strings.Contains(got, "node_id") {
+		t.Errorf("unexpected kv output: %q", got)
+	}
+
+	buf.Reset()
+	l.SetFormat(FormatText)
+	l.With("worker").Info("node-2", "plain")
+	if got := buf.String(); !strings.Contains(got, "[INFO] [node-2] plain") {
+		t.Errorf("unexpected text output: %q", got)
+	}
+
+	entries := l.core.buffer.Entries(LevelDebug, time.Time{})
+	if len(entries) != 3 || entries[0].Component != "chaos" || entries[0].EventType != "chaos_attack" {
+		t.Errorf("expected fields to be recorded in the buffer, got %+v", entries)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Format
+	}{
+		{"", FormatText},
+		{"text", FormatText},
+		{"kv", FormatKV},
+		{"logfmt", FormatKV},
+		{"JSON", FormatJSON},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestLoggerWith(t *testing.T) {
+	buf := &bytes.Buffer{}
+	parent := New(buf, LevelInfo)
+	parent.SetBuffer(NewBuffer(10))
+
+	child := parent.With("cluster", Field{Key: "cluster", Value: "c1"})
+	grandchild := child.With("", Field{Key: "shard", Value: 2})
+
+	grandchild.Info("node-1", "rebalanced")
+	if got := buf.String(); !strings.Contains(got, "[node-1] rebalanced cluster=c1 shard=2") {
+		t.Errorf("unexpected text output: %q", got)
+	}
+
+	// Children share the parent's level and format
+	parent.SetLevel(LevelWarn)
+	parent.SetFormat(FormatJSON)
+	buf.Reset()
+	child.Info("", "filtered")
+	child.Warn("", "kept")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected one JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["component"] != "cluster" || record["cluster"] != "c1" || record["msg"] != "kept" {
+		t.Errorf("unexpected record: %v", record)
+	}
+
+	entries := parent.core.buffer.Entries(LevelDebug, time.Time{})
+	if len(entries) != 2 || entries[0].Component != "cluster" || entries[0].Fields["shard"] != 2 {
+		t.Errorf("expected component and fields in the buffer, got %+v", entries)
+	}
+}
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat はローテーション済みファイル名に付ける時刻の形式（名前順が時刻順になる）
+const backupTimeFormat = "20060102-150405.000"
+
+// FileConfig はログファイルへの出力とローテーションの設定
+type FileConfig struct {
+	Path           string        // 出力先のファイル（空の場合は標準出力）
+	MaxSize        int64         // 書き込みでこのバイト数を超える場合にローテーションする（0で無制限）
+	RotateInterval time.Duration // ファイルを開いてからこの期間が経つとローテーションする（0で無効）
+	MaxBackups     int           // 残すローテーション済みファイルの数（0で無制限）
+	MaxAge         time.Duration // これより古いローテーション済みファイルを削除する（0で無制限）
+}
+
+// DefaultFileConfig はデフォルト設定を返す
+func DefaultFileConfig() FileConfig {
+	return FileConfig{
+		MaxSize:    100 << 20, // 100MB
+		MaxBackups: 10,
+	}
+}
+
+// RotatingFile はサイズ・時間でローテーションするログファイル
+// ローテーション時は現在のファイルを <Path>.<時刻> に改名し、新しいファイルを開く
+type RotatingFile struct {
+	mu     sync.Mutex
+	config FileConfig
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	now func() time.Time // テストで時刻を差し替えるため
+}
+
+// OpenFile はログファイルを追記モードで開く（ディレクトリがなければ作成する）
+func OpenFile(config FileConfig) (*RotatingFile, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+	f := &RotatingFile{config: config, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open は設定されたパスのファイルを開く（f.mu を保持して呼び出す）
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.config.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.now()
+	return nil
+}
+
+// Write はログを書き込む。サイズまたは期間の上限に達する場合は先にローテーションする
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// shouldRotate は n バイトを書き込む前にローテーションが必要かを返す
+// 空のファイルはサイズの上限を超える1行でもそのまま書き込む
+func (f *RotatingFile) shouldRotate(n int) bool {
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+int64(n) > f.config.MaxSize {
+		return true
+	}
+	return f.config.RotateInterval > 0 && f.now().Sub(f.opened) >= f.config.RotateInterval
+}
+
+// Rotate は現在のファイルを改名して新しいファイルを開く
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// rotate はローテーションし、保持期間・数を超えたファイルを削除する（f.mu を保持して呼び出す）
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	now := f.now()
+	backup := f.config.Path + "." + now.Format(backupTimeFormat)
+	if err := os.Rename(f.config.Path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.prune(now)
+}
+
+// prune は MaxBackups・MaxAge を超えたローテーション済みファイルを古い順に削除する
+func (f *RotatingFile) prune(now time.Time) error {
+	backups, err := f.Backups()
+	if err != nil {
+		return err
+	}
+
+	prefix := f.config.Path + "."
+	for i, path := range backups {
+		expired := false
+		if f.config.MaxBackups > 0 && len(backups)-i > f.config.MaxBackups {
+			expired = true
+		}
+		if f.config.MaxAge > 0 {
+			t, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(path, prefix), now.Location())
+			if err == nil && now.Sub(t) > f.config.MaxAge {
+				expired = true
+			}
+		}
+		if expired {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove old log file: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Backups はローテーション済みファイルのパスを古い順に返す
+func (f *RotatingFile) Backups() ([]string, error) {
+	matches, err := filepath.Glob(f.config.Path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := f.config.Path + "."
+	backups := matches[:0]
+	for _, path := range matches {
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(path, prefix)); err == nil {
+			backups = append(backups, path)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Close はファイルを閉じる
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "chaos.log")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f, err := OpenFile(FileConfig{Path: path, MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	f.now = func() time.Time { return now }
+
+	// Each write exceeds what is left of MaxSize, so every write after the first rotates
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		now = now.Add(time.Second)
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	backups, err := f.Backups()
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups to be kept, got %v", backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != "second\n" {
+		t.Errorf("expected oldest kept backup to hold the second line, got %q", data)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "fourth\n" {
+		t.Errorf("expected current file to hold the last line, got %q", data)
+	}
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chaos.log")
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	f, err := OpenFile(FileConfig{Path: path, RotateInterval: time.Hour, MaxAge: 90 * time.Minute})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	f.now = func() time.Time { return now }
+	f.opened = now
+
+	write := func(line string) {
+		t.Helper()
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	write("a\n")
+	now = now.Add(30 * time.Minute)
+	write("b\n") // Same file
+	now = now.Add(31 * time.Minute)
+	write("c\n") // Rotated at 01:01
+	now = now.Add(time.Hour)
+	write("d\n") // Rotated at 02:01
+	now = now.Add(time.Hour)
+	write("e\n") // Rotated at 03:01; the 01:01 backup is past MaxAge
+
+	backups, err := f.Backups()
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(backups) != 2 || !strings.HasSuffix(backups[0], ".20260102-020100.000") {
+		t.Fatalf("expected the 02:01 and 03:01 backups, got %v", backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != "c\n" {
+		t.Errorf("unexpected backup content: %q", data)
+	}
+}
+
+func TestLoggerSetOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chaos.log")
+	f, err := OpenFile(FileConfig{Path: path})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	l := New(os.Stdout, LevelInfo)
+	l.SetOutput(f)
+	l.SetFormat(FormatJSON)
+	l.With("recovery").Info("node-1", "resumed")
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), `"component":"recovery"`) {
+		t.Errorf("expected JSON log in file, got %q", data)
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("expected write after close to fail")
+	}
+}
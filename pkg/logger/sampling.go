@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// SamplingConfig は同じメッセージが大量に出力される場合の間引きの設定
+// メッセージはレベル・コンポーネント・書式文字列の組で区別する（引数の値は区別しない）
+type SamplingConfig struct {
+	First      int           // 期間ごとにそのまま出力する件数
+	Thereafter int           // First を超えた後は Thereafter 件に1件だけ出力する（0以下で出力しない）
+	Period     time.Duration // 件数を数え直す期間（0で数え直さない）
+}
+
+// DefaultSamplingConfig はデフォルト設定を返す（1秒ごとに最初の1件、以降は100件に1件）
+func DefaultSamplingConfig() SamplingConfig {
+	return SamplingConfig{
+		First:      1,
+		Thereafter: 100,
+		Period:     time.Second,
+	}
+}
+
+// sampleKey は間引きでメッセージを区別するキー
+type sampleKey struct {
+	level     Level
+	component string
+	format    string
+}
+
+// sampleCounter はメッセージごとの期間内の件数
+type sampleCounter struct {
+	start      time.Time // 期間の開始時刻
+	count      int       // 期間内の件数
+	suppressed int       // 最後に出力してから間引いた件数
+}
+
+// sampler はメッセージごとに件数を数えて間引く（core.mu を保持して使用する）
+type sampler struct {
+	config   SamplingConfig
+	counters map[sampleKey]*sampleCounter
+	dropped  uint64
+}
+
+// newSampler は間引きを作成する
+func newSampler(config SamplingConfig) *sampler {
+	return &sampler{config: config, counters: make(map[sampleKey]*sampleCounter)}
+}
+
+// allow はメッセージを出力するかを判定し、出力する場合は直前に間引いた件数を返す
+func (s *sampler) allow(key sampleKey, now time.Time) (bool, int) {
+	c, ok := s.counters[key]
+	if !ok {
+		c = &sampleCounter{start: now}
+		s.counters[key] = c
+	}
+	if s.config.Period > 0 && now.Sub(c.start) >= s.config.Period {
+		c.start = now
+		c.count = 0
+	}
+	c.count++
+
+	n := c.count - s.config.First
+	if n > 0 && (s.config.Thereafter <= 0 || n%s.config.Thereafter != 0) {
+		c.suppressed++
+		s.dropped++
+		return false, 0
+	}
+	suppressed := c.suppressed
+	c.suppressed = 0
+	return true, suppressed
+}
+
+// SetSampling はメッセージごとの間引きを設定する（nilで無効）
+// 高頻度の警告が出力を埋め尽くしたり、出力のコストで負荷試験のタイミングが歪んだりするのを防ぐ
+func (l *Logger) SetSampling(config *SamplingConfig) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	if config == nil {
+		l.core.sampler = nil
+		return
+	}
+	l.core.sampler = newSampler(*config)
+}
+
+// Dropped は間引きで出力しなかったログの件数を返す
+func (l *Logger) Dropped() uint64 {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	if l.core.sampler == nil {
+		return 0
+	}
+	return l.core.sampler.dropped
+}
+
+// suppressedSuffix は間引いた件数をメッセージの後ろに付ける文字列を返す
+func suppressedSuffix(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d similar messages suppressed)", n)
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplerAllow(t *testing.T) {
+	s := newSampler(SamplingConfig{First: 2, Thereafter: 3, Period: time.Second})
+	key := sampleKey{level: LevelWarn, format: "node %s is down"}
+	start := time.Now()
+
+	var allowed []int
+	for i := 1; i <= 8; i++ {
+		if ok, _ := s.allow(key, start); ok {
+			allowed = append(allowed, i)
+		}
+	}
+	// 最初の2件と、以降の3件に1件（5件目・8件目）
+	want := []int{1, 2, 5, 8}
+	if len(allowed) != len(want) {
+		t.Fatalf("allowed = %v, want %v", allowed, want)
+	}
+	for i := range want {
+		if allowed[i] != want[i] {
+			t.Fatalf("allowed = %v, want %v", allowed, want)
+		}
+	}
+	if s.dropped != 4 {
+		t.Errorf("dropped = %d, want 4", s.dropped)
+	}
+
+	// 期間が過ぎると数え直し、間引いた件数を返す
+	s.allow(key, start) // 9件目は間引かれる
+	ok, suppressed := s.allow(key, start.Add(time.Second))
+	if !ok || suppressed != 1 {
+		t.Errorf("after period: ok=%v suppressed=%d, want true 1", ok, suppressed)
+	}
+
+	// キーが異なるメッセージは別に数える
+	if ok, _ := s.allow(sampleKey{level: LevelError, format: "node %s is down"}, start); !ok {
+		t.Error("different level should be counted separately")
+	}
+}
+
+func TestLoggerSampling(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New(buf, LevelDebug)
+	l.SetSampling(&SamplingConfig{First: 1, Thereafter: 10})
+
+	for i := range 20 {
+		l.Warn("node-1", "node %d is down", i)
+	}
+	l.Info("node-1", "other message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[1], "node 10 is down (9 similar messages suppressed)") {
+		t.Errorf("expected suppressed count in %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "other message") {
+		t.Errorf("expected unrelated message to be logged, got %q", lines[2])
+	}
+	if got := l.Dropped(); got != 18 {
+		t.Errorf("Dropped() = %d, want 18", got)
+	}
+
+	// 子ロガーは親と間引きを共有し、コンポーネントごとに数える
+	child := l.With("chaos")
+	child.Warn("node-1", "node %d is down", 0)
+	if got := l.Dropped(); got != 18 {
+		t.Errorf("child message with new component should be logged, Dropped() = %d", got)
+	}
+
+	l.SetSampling(nil)
+	buf.Reset()
+	for range 5 {
+		l.Warn("node-1", "node is down")
+	}
+	if got := strings.Count(buf.String(), "node is down"); got != 5 {
+		t.Errorf("sampling disabled: expected 5 lines, got %d", got)
+	}
+	if got := l.Dropped(); got != 0 {
+		t.Errorf("Dropped() after disabling = %d, want 0", got)
+	}
+}
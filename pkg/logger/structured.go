@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Format はログの出力形式
+type Format int
+
+const (
+	FormatText Format = iota // [時刻] [レベル] [ノードID] メッセージ
+	FormatKV                 // key=value 形式（logfmt）
+	FormatJSON               // 1行1オブジェクトのJSON
+)
+
+// String は出力形式名を返す
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatKV:
+		return "kv"
+	case FormatJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFormat は出力形式名を解析する（空の場合は text、logfmt は kv の別名）
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "text":
+		return FormatText, nil
+	case "kv", "logfmt":
+		return FormatKV, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format: %s (expected text, kv or json)", s)
+	}
+}
+
+// Field は子ロガーが全ての出力に付けるキーと値
+type Field struct {
+	Key   string
+	Value any
+}
+
+// attrs は空でない標準のフィールドと子ロガーのフィールドを slog の属性に変換する
+func (l *Logger) attrs(nodeID, eventType string) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 3+len(l.fields))
+	if nodeID != "" {
+		attrs = append(attrs, slog.String("node_id", nodeID))
+	}
+	if l.component != "" {
+		attrs = append(attrs, slog.String("component", l.component))
+	}
+	if eventType != "" {
+		attrs = append(attrs, slog.String("event_type", eventType))
+	}
+	for _, f := range l.fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	return attrs
+}
+
+// fieldMap はバッファに記録するためにフィールドをマップに変換する（空の場合は nil）
+func fieldMap(fields []Field) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+// formatFields はテキスト形式でメッセージの後ろに付ける " key=value" を返す
+func formatFields(fields []Field) string {
+	var b strings.Builder
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	return b.String()
+}
+
+// slogLevel はログレベルを slog のレベルに変換する
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
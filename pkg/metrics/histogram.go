@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets はレイテンシのヒストグラムのデフォルトのバケット上限
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// Histogram は所要時間を固定のバケットで集計する
+// 全ての操作はアトミックで、並行に呼び出せる
+type Histogram struct {
+	bounds []time.Duration
+	counts []atomic.Uint64 // バケットごとの件数（末尾は上限超過）
+	sumNs  atomic.Int64
+}
+
+// NewHistogram はバケット上限を指定してヒストグラムを作成する（空の場合はデフォルト）
+func NewHistogram(bounds []time.Duration) *Histogram {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	sorted := append([]time.Duration{}, bounds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &Histogram{
+		bounds: sorted,
+		counts: make([]atomic.Uint64, len(sorted)+1),
+	}
+}
+
+// Observe は所要時間を記録する
+func (h *Histogram) Observe(d time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })
+	h.counts[i].Add(1)
+	h.sumNs.Add(int64(d))
+}
+
+// HistogramSnapshot はヒストグラムのある時点の値
+type HistogramSnapshot struct {
+	Bounds     []time.Duration // バケット上限
+	Cumulative []uint64        // 各上限以下の件数（累積）
+	Count      uint64          // 全件数
+	Sum        time.Duration   // 合計
+}
+
+// Snapshot は現在の値を返す
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Bounds:     h.bounds,
+		Cumulative: make([]uint64, len(h.bounds)),
+		Sum:        time.Duration(h.sumNs.Load()),
+	}
+	for i := range h.counts {
+		s.Count += h.counts[i].Load()
+		if i < len(h.bounds) {
+			s.Cumulative[i] = s.Count
+		}
+	}
+	return s
+}
+
+// Mean は平均を返す（記録がない場合は0）
+func (s HistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Sub は prev（同じヒストグラムの以前の Snapshot）からの差分を返す
+// 2つの時点の間に記録した分だけを集計できる
+func (s HistogramSnapshot) Sub(prev HistogramSnapshot) HistogramSnapshot {
+	if len(prev.Cumulative) != len(s.Cumulative) {
+		return s
+	}
+	d := HistogramSnapshot{
+		Bounds:     s.Bounds,
+		Cumulative: make([]uint64, len(s.Cumulative)),
+		Count:      s.Count - prev.Count,
+		Sum:        s.Sum - prev.Sum,
+	}
+	for i := range s.Cumulative {
+		d.Cumulative[i] = s.Cumulative[i] - prev.Cumulative[i]
+	}
+	return d
+}
+
+// Quantile は q（0.0〜1.0）の分位数を、そこに当たる記録を含むバケットの上限で返す
+// 記録がない場合は0、最大の上限を超えるバケットに当たる場合は最大の上限を返す
+func (s HistogramSnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 || len(s.Bounds) == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(s.Count)))
+	rank = min(max(rank, 1), s.Count)
+	i := sort.Search(len(s.Cumulative), func(i int) bool { return s.Cumulative[i] >= rank })
+	if i == len(s.Bounds) {
+		i--
+	}
+	return s.Bounds[i]
+}
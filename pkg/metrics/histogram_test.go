@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]time.Duration{10 * time.Millisecond, time.Millisecond})
+
+	h.Observe(500 * time.Microsecond)
+	h.Observe(time.Millisecond)
+	h.Observe(5 * time.Millisecond)
+	h.Observe(time.Second)
+
+	s := h.Snapshot()
+	if s.Bounds[0] != time.Millisecond {
+		t.Errorf("expected bounds to be sorted, got %v", s.Bounds)
+	}
+	if s.Cumulative[0] != 2 || s.Cumulative[1] != 3 {
+		t.Errorf("expected cumulative counts [2 3], got %v", s.Cumulative)
+	}
+	if s.Count != 4 {
+		t.Errorf("expected count 4, got %d", s.Count)
+	}
+	if want := 1006500 * time.Microsecond; s.Sum != want {
+		t.Errorf("expected sum %v, got %v", want, s.Sum)
+	}
+	if want := 1006500 * time.Microsecond / 4; s.Mean() != want {
+		t.Errorf("expected mean %v, got %v", want, s.Mean())
+	}
+}
+
+func TestHistogramDefaultBuckets(t *testing.T) {
+	s := NewHistogram(nil).Snapshot()
+	if len(s.Bounds) != len(DefaultLatencyBuckets) || s.Mean() != 0 {
+		t.Errorf("unexpected empty snapshot: %+v", s)
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := NewHistogram([]time.Duration{time.Millisecond, 10 * time.Millisecond})
+	if q := h.Snapshot().Quantile(0.99); q != 0 {
+		t.Errorf("expected 0 without observations, got %v", q)
+	}
+
+	for range 99 {
+		h.Observe(500 * time.Microsecond)
+	}
+	h.Observe(5 * time.Millisecond)
+	before := h.Snapshot()
+	if q := before.Quantile(0.5); q != time.Millisecond {
+		t.Errorf("expected p50 of 1ms, got %v", q)
+	}
+	if q := before.Quantile(0.99); q != time.Millisecond {
+		t.Errorf("expected p99 of 1ms, got %v", q)
+	}
+
+	// 差分は後から記録した分だけを集計する
+	h.Observe(5 * time.Millisecond)
+	h.Observe(time.Second)
+	window := h.Snapshot().Sub(before)
+	if window.Count != 2 || window.Sum != time.Second+5*time.Millisecond {
+		t.Errorf("unexpected window: %+v", window)
+	}
+	if q := window.Quantile(0.5); q != 10*time.Millisecond {
+		t.Errorf("expected p50 of 10ms in the window, got %v", q)
+	}
+	if q := window.Quantile(0.99); q != 10*time.Millisecond {
+		t.Errorf("expected p99 capped at the largest bound, got %v", q)
+	}
+}
@@ -38,6 +38,7 @@ type Metrics struct {
 	successRequests atomic.Uint64
 	failedRequests  atomic.Uint64
 	totalLatencyNs  atomic.Uint64
+	latency         *Histogram // 開始からの全てのリクエストのレイテンシ（Reset でリセットしない）
 
 	mu                sync.RWMutex
 	startTime         time.Time
@@ -62,6 +63,7 @@ func NewWithConfig(config Config) *Metrics {
 	return &Metrics{
 		startTime:         now,
 		lastResetTime:     now,
+		latency:           NewHistogram(nil),
 		latencies:         make([]time.Duration, 0, maxSamples),
 		maxLatencySamples: maxSamples,
 	}
@@ -72,6 +74,7 @@ func (m *Metrics) RecordSuccess(latency time.Duration) {
 	m.totalRequests.Add(1)
 	m.successRequests.Add(1)
 	m.totalLatencyNs.Add(uint64(latency.Nanoseconds()))
+	m.latency.Observe(latency)
 
 	m.mu.Lock()
 	m.windowRequests++
@@ -86,6 +89,7 @@ func (m *Metrics) RecordFailure(latency time.Duration) {
 	m.totalRequests.Add(1)
 	m.failedRequests.Add(1)
 	m.totalLatencyNs.Add(uint64(latency.Nanoseconds()))
+	m.latency.Observe(latency)
 
 	m.mu.Lock()
 	m.windowRequests++
@@ -161,6 +165,12 @@ func (m *Metrics) P99Latency() time.Duration {
 	return sorted[idx]
 }
 
+// Latency は開始からの全てのリクエスト（失敗を含む）のレイテンシのヒストグラムを返す
+// 2つの時点の Latency の差分（HistogramSnapshot.Sub）から、その間のP99などを求められる
+func (m *Metrics) Latency() HistogramSnapshot {
+	return m.latency.Snapshot()
+}
+
 // ErrorRate はエラー率を返す（0.0〜1.0）
 func (m *Metrics) ErrorRate() float64 {
 	total := m.totalRequests.Load()
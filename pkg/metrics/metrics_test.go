@@ -148,3 +148,22 @@ func TestMetricsSnapshot(t *testing.T) {
 		t.Errorf("expected 1 failed, got %d", snap.FailedRequests)
 	}
 }
+
+func TestMetricsLatency(t *testing.T) {
+	m := New()
+	m.RecordSuccess(time.Millisecond)
+	m.RecordFailure(100 * time.Millisecond)
+
+	before := m.Latency()
+	if before.Count != 2 {
+		t.Errorf("expected failures to be included, got %d observations", before.Count)
+	}
+
+	// Reset はヒストグラムをリセットしない
+	m.Reset()
+	m.RecordSuccess(time.Second)
+	window := m.Latency().Sub(before)
+	if window.Count != 1 || window.Sum != time.Second {
+		t.Errorf("expected only the latest request in the window, got %+v", window)
+	}
+}
@@ -0,0 +1,72 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOverloaded は処理中の操作が Admission の上限に達したため、ノードが操作を受け付けなかったことを表す
+var ErrOverloaded = errors.New("node is overloaded")
+
+// Admission はノードが同時に処理する操作の上限（ゼロ値で制限しない）
+// 上限に達している間に届いた操作は待たせずに ErrOverloaded で打ち切る（ロードシェディング）
+type Admission struct {
+	MaxInFlight int // 同時に処理する操作の上限（注入された遅延を待っている操作を含む）
+
+	// ReservedReads は MaxInFlight のうち読み取りだけに使う枠
+	// 書き込みは処理中の操作が MaxInFlight-ReservedReads に達した時点で打ち切り、読み取りを優先する
+	ReservedReads int
+}
+
+// Enabled は上限が設定されているかを返す
+func (a Admission) Enabled() bool {
+	return a.MaxInFlight > 0
+}
+
+// limit は読み取り・書き込みそれぞれが受け付けられる処理中の操作の数
+func (a Admission) limit(write bool) int64 {
+	if write {
+		return int64(max(a.MaxInFlight-a.ReservedReads, 0))
+	}
+	return int64(a.MaxInFlight)
+}
+
+// SetAdmission は同時に処理する操作の上限を設定する（ゼロ値で解除）
+// 設定時点で処理中の操作は打ち切らない
+func (n *Node) SetAdmission(a Admission) {
+	if !a.Enabled() {
+		n.admission.Store(nil)
+		return
+	}
+	n.admission.Store(&a)
+}
+
+// Admission は同時に処理する操作の上限を返す
+func (n *Node) Admission() Admission {
+	if a := n.admission.Load(); a != nil {
+		return *a
+	}
+	return Admission{}
+}
+
+// InFlight は処理中の操作の数を返す
+func (n *Node) InFlight() int {
+	return int(n.inflight.Load())
+}
+
+// admit は操作を処理中として数え、上限に達している場合は数えずに ErrOverloaded を返す
+// 受け付けた場合は操作の終了時に release を呼び出す
+func (n *Node) admit(write bool) error {
+	inflight := n.inflight.Add(1)
+	if a := n.admission.Load(); a != nil && inflight > a.limit(write) {
+		n.inflight.Add(-1)
+		n.shed.Add(1)
+		return fmt.Errorf("node %s: %w", n.id, ErrOverloaded)
+	}
+	return nil
+}
+
+// release は admit で受け付けた操作の終了を記録する
+func (n *Node) release() {
+	n.inflight.Add(-1)
+}
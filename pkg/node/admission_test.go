@@ -0,0 +1,110 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+// newAdmissionNode は仮想時計で遅延を待つ、同時に処理する操作の上限を設定したノードを起動する
+func newAdmissionNode(t *testing.T, a Admission) (*Node, *clock.Simulated) {
+	t.Helper()
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	t.Cleanup(func() { _ = n.Stop() })
+	n.SetDelay(time.Second)
+	n.SetAdmission(a)
+	return n, clk
+}
+
+// holdReads は遅延を待つ読み取りを count 個処理中にし、遅延を進めて終了させる関数を返す
+func holdReads(t *testing.T, n *Node, clk *clock.Simulated, count int) (finish func()) {
+	t.Helper()
+	done := make(chan error, count)
+	for i := 0; i < count; i++ {
+		go func() {
+			_, _, err := n.GetContext(context.Background(), "key")
+			done <- err
+		}()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, count); err != nil {
+		t.Fatalf("reads did not start: %v", err)
+	}
+	return func() {
+		clk.Advance(time.Second)
+		for i := 0; i < count; i++ {
+			if err := <-done; err != nil {
+				t.Errorf("expected the admitted read to succeed, got %v", err)
+			}
+		}
+	}
+}
+
+func TestNodeAdmissionShed(t *testing.T) {
+	n, clk := newAdmissionNode(t, Admission{MaxInFlight: 2})
+	finish := holdReads(t, n, clk, 2)
+
+	if n.InFlight() != 2 {
+		t.Errorf("expected 2 operations in flight, got %d", n.InFlight())
+	}
+	if _, _, err := n.GetContext(context.Background(), "key"); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("expected a read above the limit to be shed, got %v", err)
+	}
+	if err := n.Delete("key"); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("expected a delete above the limit to be shed, got %v", err)
+	}
+	finish()
+
+	if n.InFlight() != 0 {
+		t.Errorf("expected no operations in flight, got %d", n.InFlight())
+	}
+	if stats := n.Stats(); stats.Shed != 2 || stats.Gets != 2 {
+		t.Errorf("expected 2 shed and 2 admitted operations, got %+v", stats)
+	}
+}
+
+func TestNodeAdmissionReservedReads(t *testing.T) {
+	n, clk := newAdmissionNode(t, Admission{MaxInFlight: 2, ReservedReads: 1})
+	finish := holdReads(t, n, clk, 1)
+	defer finish()
+
+	// 読み取りの枠は書き込みに使えない
+	if err := n.MSet(map[string][]byte{"a": nil}); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("expected a write to be shed before reads, got %v", err)
+	}
+	read := make(chan error, 1)
+	go func() {
+		_, err := n.MGetContext(context.Background(), []string{"a", "b"})
+		read <- err
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 2); err != nil {
+		t.Fatalf("expected the read to use the reserved slot: %v", err)
+	}
+	clk.Advance(time.Second)
+	if err := <-read; err != nil {
+		t.Errorf("expected the read to succeed, got %v", err)
+	}
+}
+
+func TestNodeAdmissionDisabled(t *testing.T) {
+	n, clk := newAdmissionNode(t, Admission{MaxInFlight: 1})
+	n.SetAdmission(Admission{})
+	if n.Admission().Enabled() {
+		t.Error("expected the zero value to disable admission control")
+	}
+	holdReads(t, n, clk, 3)()
+	if n.Stats().Shed != 0 {
+		t.Errorf("expected nothing to be shed, got %d", n.Stats().Shed)
+	}
+}
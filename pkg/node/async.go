@@ -0,0 +1,94 @@
+package node
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+// afterDelay は設定された分布から引いた遅延の後に op を呼び出す（遅延がない場合はその場で呼び出す）
+// 遅延はタイマー（Clock.AfterFunc）で待つため、待っている間は呼び出し元を含むどのゴルーチンも使わない
+// 遅延の途中で ctx が終わった場合は op の代わりに fail を ctx のエラーで呼び出す（どちらか一方を1度だけ呼び出す）
+func (n *Node) afterDelay(ctx context.Context, op func(), fail func(error)) {
+	d, c := n.sampleDelay()
+	if d <= 0 {
+		if err := ctx.Err(); err != nil {
+			fail(err)
+			return
+		}
+		op()
+		return
+	}
+
+	// タイマーと ctx の終了のどちらが先に来ても、もう一方を止めてから1度だけ呼び出す
+	var (
+		once    sync.Once
+		timer   clock.Timer
+		stopCtx func() bool
+	)
+	ready := make(chan struct{})
+	timer = c.AfterFunc(d, func() {
+		<-ready
+		stopCtx()
+		once.Do(op)
+	})
+	stopCtx = context.AfterFunc(ctx, func() {
+		<-ready
+		timer.Stop()
+		once.Do(func() { fail(ctx.Err()) })
+	})
+	close(ready)
+}
+
+// GetAsync は GetContext と同じくキーに対応する値を取得し、結果で done を呼び出す
+// 注入された遅延は呼び出し元で待たずにタイマーで待つため、done は遅延の後にタイマーのゴルーチンから呼ばれる
+// （遅延がない場合・Admission で断った場合は戻る前に呼ばれる）
+func (n *Node) GetAsync(ctx context.Context, key string, done func(value []byte, found bool, err error)) {
+	if err := n.admit(false); err != nil {
+		done(nil, false, err)
+		return
+	}
+	n.afterDelay(ctx, func() {
+		value, _, found := n.lookup(key)
+		n.release()
+		done(value, found, nil)
+	}, func(err error) {
+		n.release()
+		done(nil, false, err)
+	})
+}
+
+// SetAsync は SetContext と同じくキーに値を設定し、結果で done を呼び出す
+// 遅延の待ち方と done を呼び出すゴルーチンは GetAsync と同じ
+func (n *Node) SetAsync(ctx context.Context, key string, value []byte, done func(err error)) {
+	if err := n.admit(true); err != nil {
+		done(err)
+		return
+	}
+	n.afterDelay(ctx, func() {
+		err := n.store(key, value, 0)
+		n.release()
+		done(err)
+	}, func(err error) {
+		n.release()
+		done(err)
+	})
+}
+
+// DeleteAsync は DeleteContext と同じくキーを削除し、結果で done を呼び出す
+// 遅延の待ち方と done を呼び出すゴルーチンは GetAsync と同じ
+func (n *Node) DeleteAsync(ctx context.Context, key string, done func(err error)) {
+	if err := n.admit(true); err != nil {
+		done(err)
+		return
+	}
+	n.afterDelay(ctx, func() {
+		err := n.erase(key)
+		n.release()
+		done(err)
+	}, func(err error) {
+		n.release()
+		done(err)
+	})
+}
@@ -0,0 +1,55 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+func TestNodeAsyncDelay(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	n.SetDelay(time.Second)
+
+	// 遅延の間は呼び出し元を待たせず、時計が進んでから完了する
+	done := make(chan error, 1)
+	n.SetAsync(context.Background(), "key", []byte("v"), func(err error) { done <- err })
+	select {
+	case <-done:
+		t.Fatal("expected the write to wait for the delay")
+	case <-time.After(20 * time.Millisecond):
+	}
+	clk.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("SetAsync failed: %v", err)
+	}
+
+	read := make(chan string, 1)
+	n.GetAsync(context.Background(), "key", func(value []byte, found bool, err error) { read <- string(value) })
+	clk.Advance(time.Second)
+	if value := <-read; value != "v" {
+		t.Errorf("expected the written value, got %q", value)
+	}
+
+	// 遅延の途中で ctx が終わった場合は削除せずに ctx のエラーで完了する
+	ctx, cancel := context.WithCancel(context.Background())
+	n.DeleteAsync(ctx, "key", func(err error) { done <- err })
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	clk.Advance(time.Second)
+	n.SetDelay(0)
+	if _, ok := n.Get("key"); !ok {
+		t.Error("expected the cancelled delete not to remove the key")
+	}
+	if inflight := n.inflight.Load(); inflight != 0 {
+		t.Errorf("expected every admitted operation to be released, got %d", inflight)
+	}
+}
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"bytes"
+	"context"
+)
+
+// MGet は複数のキーの値をまとめて取得し、存在するキーの値を返す
+func (n *Node) MGet(keys []string) map[string][]byte {
+	values, _ := n.MGetContext(context.Background(), keys)
+	return values
+}
+
+// MGetContext は複数のキーの値をまとめて取得し、存在するキーの値を返す
+// 注入された遅延は1回だけ待ち、全てのキーを同じ時点の状態から読む
+// 稼働中でない場合は Get と同じく値なしを返す。遅延の途中で ctx がキャンセルされた場合はそのエラーを返す
+// Admission では1つの操作として数える
+func (n *Node) MGetContext(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if err := n.admit(false); err != nil {
+		return nil, err
+	}
+	defer n.release()
+	if err := n.applyDelay(ctx); err != nil {
+		return nil, err
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	values := make(map[string][]byte, len(keys))
+	if !n.status.Available() {
+		n.rejected.Add(uint64(len(keys)))
+		return values, nil
+	}
+
+	now := n.clock.Now()
+	n.gets.Add(uint64(len(keys)))
+	for _, key := range keys {
+		counters := n.namespaces.of(key)
+		if counters != nil {
+			counters.gets.Add(1)
+		}
+		value, exists := n.data[key]
+		if !exists || n.expiredAt(key, now) {
+			continue
+		}
+		values[key] = n.corrupt(value)
+		n.hits.Add(1)
+		if counters != nil {
+			counters.hits.Add(1)
+		}
+		n.touch(key)
+	}
+	return values, nil
+}
+
+// MSet は複数のキーに値をまとめて設定する
+func (n *Node) MSet(entries map[string][]byte) error {
+	return n.MSetContext(context.Background(), entries)
+}
+
+// MSetContext は複数のキーに値をまとめて設定する
+// 注入された遅延は1回だけ待ち、全てのキーを1度に書き込む（上限による削除は書き込み後に行う）
+// いずれかの値が上限を超える場合・全体で MaxMemory を超える場合は何も書き込まずにエラーを返す。Admission では1つの操作として数える
+func (n *Node) MSetContext(ctx context.Context, entries map[string][]byte) error {
+	if err := n.admit(true); err != nil {
+		return err
+	}
+	defer n.release()
+	if err := n.applyDelay(ctx); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.writable(len(entries)); err != nil {
+		return err
+	}
+
+	var records []walRecord
+	for key, value := range entries {
+		if err := n.checkSize(key, value); err != nil {
+			return err
+		}
+		if n.wal != nil {
+			records = append(records, walRecord{Op: "set", Key: key, Value: value})
+		}
+	}
+	if err := n.checkCapacity(entries); err != nil {
+		return err
+	}
+	if err := n.logWrites(records...); err != nil {
+		return err
+	}
+	for key, value := range entries {
+		n.set(key, value, 0)
+	}
+	n.evict()
+	return nil
+}
+
+// MSetMissing は entries のうち保持していない（期限切れを含む）キーだけを1度に書き込み、書き込んだ数を返す
+// キーを保持しているかの確認と書き込みを同じロックの中で行うため、確認の前に書き込まれた値を上書きしない
+// ノード間でキーを移すための操作で（cluster の Rebalance）、注入された遅延と Admission は適用しない
+// 書き込むキーのいずれかが上限を超える場合・全体で MaxMemory を超える場合は何も書き込まずにエラーを返す
+func (n *Node) MSetMissing(entries map[string][]byte) (int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.writable(len(entries)); err != nil {
+		return 0, err
+	}
+
+	now := n.clock.Now()
+	missing := make(map[string][]byte)
+	var records []walRecord
+	for key, value := range entries {
+		if _, exists := n.data[key]; exists && !n.expiredAt(key, now) {
+			continue
+		}
+		if err := n.checkSize(key, value); err != nil {
+			return 0, err
+		}
+		missing[key] = value
+		if n.wal != nil {
+			records = append(records, walRecord{Op: "set", Key: key, Value: value})
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+	if err := n.checkCapacity(missing); err != nil {
+		return 0, err
+	}
+	if err := n.logWrites(records...); err != nil {
+		return 0, err
+	}
+	for key, value := range missing {
+		n.set(key, value, 0)
+	}
+	n.evict()
+	return len(missing), nil
+}
+
+// MDeleteUnchanged は entries のうち値が entries の値のままのキーだけを1度に削除し、削除した数を返す
+// 値の確認と削除を同じロックの中で行うため、entries の値を読んだ後に書き込まれたキーは削除しない
+// ノード間でキーを移すための操作で（cluster の Rebalance）、注入された遅延と Admission は適用しない
+func (n *Node) MDeleteUnchanged(entries map[string][]byte) (int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.writable(len(entries)); err != nil {
+		return 0, err
+	}
+
+	var keys []string
+	var records []walRecord
+	for key, value := range entries {
+		if current, exists := n.data[key]; !exists || !bytes.Equal(current, value) {
+			continue
+		}
+		keys = append(keys, key)
+		if n.wal != nil {
+			records = append(records, walRecord{Op: "delete", Key: key})
+		}
+	}
+	if err := n.logWrites(records...); err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		n.deletes.Add(1)
+		if counters := n.namespaces.of(key); counters != nil {
+			counters.deletes.Add(1)
+		}
+		n.remove(key)
+		if n.crdt != nil {
+			n.crdt.remove(key)
+		}
+	}
+	return len(keys), nil
+}
@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNodeMGetMSet(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	err := n.MSet(map[string][]byte{"a": []byte("1"), "b": []byte("2")})
+	if err != nil {
+		t.Fatalf("failed to set keys: %v", err)
+	}
+	values := n.MGet([]string{"a", "b", "missing"})
+	if len(values) != 2 || string(values["a"]) != "1" || string(values["b"]) != "2" {
+		t.Errorf("expected the two set keys, got %v", values)
+	}
+
+	stats := n.Stats()
+	if stats.Sets != 2 || stats.Gets != 3 || stats.Hits != 2 {
+		t.Errorf("expected per-key stats, got %+v", stats)
+	}
+
+	// 上限を超える値があれば何も書き込まない
+	n.SetLimits(Limits{MaxBytes: 8})
+	if err := n.MSet(map[string][]byte{"c": []byte("3"), "d": []byte("too large value")}); err == nil {
+		t.Error("expected an oversized value to fail the batch")
+	}
+	if _, ok := n.Get("c"); ok {
+		t.Error("expected no key of the failed batch to be written")
+	}
+}
+
+func TestNodeBatchNotRunning(t *testing.T) {
+	n := New("test-node-1")
+	if err := n.MSet(map[string][]byte{"a": []byte("1")}); err == nil {
+		t.Error("expected MSet on a stopped node to fail")
+	}
+	if values := n.MGet([]string{"a", "b"}); len(values) != 0 {
+		t.Errorf("expected no values from a stopped node, got %v", values)
+	}
+	if n.Stats().Rejected != 3 {
+		t.Errorf("expected 3 rejected keys, got %d", n.Stats().Rejected)
+	}
+}
+
+func TestNodeBatchDelay(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	n.SetDelay(50 * time.Millisecond)
+
+	// 遅延はキーごとではなくバッチごとに1回だけ待つ
+	start := time.Now()
+	if err := n.MSet(map[string][]byte{"a": nil, "b": nil, "c": nil, "d": nil}); err != nil {
+		t.Fatalf("failed to set keys: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 150*time.Millisecond {
+		t.Errorf("expected a single delay for the batch, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := n.MGetContext(ctx, []string{"a"}); err == nil {
+		t.Error("expected a canceled context to abort the delayed batch")
+	}
+}
+
+func TestNodeMSetMissingAndMDeleteUnchanged(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("a", []byte("current"))
+	written, err := n.MSetMissing(map[string][]byte{"a": []byte("stale"), "b": []byte("v")})
+	if err != nil || written != 1 {
+		t.Fatalf("expected only the missing key to be written, got %d (%v)", written, err)
+	}
+	if value, _ := n.Get("a"); string(value) != "current" {
+		t.Errorf("expected the existing value to be kept, got %q", value)
+	}
+
+	removed, err := n.MDeleteUnchanged(map[string][]byte{"a": []byte("stale"), "b": []byte("v")})
+	if err != nil || removed != 1 {
+		t.Fatalf("expected only the unchanged key to be removed, got %d (%v)", removed, err)
+	}
+	if _, ok := n.Get("a"); !ok {
+		t.Error("expected the changed key to be kept")
+	}
+	if _, ok := n.Get("b"); ok {
+		t.Error("expected the unchanged key to be removed")
+	}
+
+	_ = n.SetReadOnly(true)
+	if _, err := n.MSetMissing(map[string][]byte{"c": []byte("v")}); err == nil {
+		t.Error("expected MSetMissing to fail on a read-only node")
+	}
+}
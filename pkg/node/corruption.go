@@ -0,0 +1,41 @@
+package node
+
+import (
+	"math/rand"
+)
+
+// SetCorruption は読み取りのうち rate の割合で、値の1バイトを反転して返すようにする（0以下で無効）
+// 格納した値は変更しないため、同じキーを読み直すと正しい値が返りうる
+// Get・MGet が対象で、Scan・Export・スナップショットは影響を受けない
+func (n *Node) SetCorruption(rate float64) {
+	rate = min(max(rate, 0), 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.corruption = rate
+	if rate > 0 {
+		log.Info(n.id, "Corrupting %.0f%% of reads", rate*100)
+	} else {
+		log.Info(n.id, "Corruption cleared")
+	}
+}
+
+// Corruption は値を壊して返す読み取りの割合を返す
+func (n *Node) Corruption() float64 {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.corruption
+}
+
+// corrupt は SetCorruption の割合で value の1バイトを反転した複製を返す（mu を保持して呼ぶ）
+// 壊さない場合と空の値は value をそのまま返す
+func (n *Node) corrupt(value []byte) []byte {
+	if n.corruption <= 0 || len(value) == 0 || rand.Float64() >= n.corruption {
+		return value
+	}
+	c := make([]byte, len(value))
+	copy(c, value)
+	c[rand.Intn(len(c))] ^= 0xff
+	n.corrupted.Add(1)
+	return c
+}
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestNodeSetCorruption(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	value := []byte("value")
+	_ = n.Set("key", value)
+	_ = n.Set("empty", nil)
+
+	n.SetCorruption(1)
+	if n.Corruption() != 1 {
+		t.Errorf("expected corruption rate 1, got %v", n.Corruption())
+	}
+
+	// 1バイトだけ反転した値を返し、格納した値は変更しない
+	got, ok := n.Get("key")
+	if !ok || len(got) != len(value) || bytes.Equal(got, value) {
+		t.Fatalf("expected a corrupted value, got %q (%v)", got, ok)
+	}
+	diff := 0
+	for i := range got {
+		if got[i] != value[i] {
+			diff++
+		}
+	}
+	if diff != 1 {
+		t.Errorf("expected exactly one corrupted byte, got %d", diff)
+	}
+	if values := n.MGet([]string{"key"}); bytes.Equal(values["key"], value) {
+		t.Error("expected MGet to corrupt the value too")
+	}
+	if got, ok := n.Get("empty"); !ok || len(got) != 0 {
+		t.Errorf("expected an empty value to be returned as is, got %q (%v)", got, ok)
+	}
+	if got := n.Stats().Corrupted; got != 2 {
+		t.Errorf("expected 2 corrupted reads, got %d", got)
+	}
+
+	n.SetCorruption(0)
+	if got, _ := n.Get("key"); !bytes.Equal(got, value) {
+		t.Errorf("expected the stored value to be intact, got %q", got)
+	}
+}
+
+func TestNodeSetCorruptionClamp(t *testing.T) {
+	n := New("test-node-1")
+	n.SetCorruption(2)
+	if n.Corruption() != 1 {
+		t.Errorf("expected rate above 1 to be clamped to 1, got %v", n.Corruption())
+	}
+	n.SetCorruption(-1)
+	if n.Corruption() != 0 {
+		t.Errorf("expected negative rate to be clamped to 0, got %v", n.Corruption())
+	}
+}
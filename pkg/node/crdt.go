@@ -0,0 +1,248 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// StoreKind はノードのデータの保持方法
+type StoreKind string
+
+const (
+	StoreMap  StoreKind = "map"  // キーごとに最後に書き込まれた値だけを保持する（既定）
+	StoreCRDT StoreKind = "crdt" // LWW レジスタと OR-Set で保持し、他のノードの状態とマージできる
+)
+
+// ParseStoreKind は文字列のデータの保持方法をパースする（空で StoreMap）
+func ParseStoreKind(s string) (StoreKind, error) {
+	switch StoreKind(strings.ToLower(s)) {
+	case "", StoreMap:
+		return StoreMap, nil
+	case StoreCRDT:
+		return StoreCRDT, nil
+	default:
+		return "", fmt.Errorf("unknown store kind: %s", s)
+	}
+}
+
+// Timestamp は LWW レジスタの書き込みの順序
+// Wall が同じ場合は Node の大きい方を新しいとみなし、全てのノードで同じ順序になる
+type Timestamp struct {
+	Wall int64  `json:"wall"` // 書き込んだノードの時計（ナノ秒、ノードごとに単調増加）
+	Node string `json:"node"`
+}
+
+// After は t が o より新しいかを返す
+func (t Timestamp) After(o Timestamp) bool {
+	if t.Wall != o.Wall {
+		return t.Wall > o.Wall
+	}
+	return t.Node > o.Node
+}
+
+// Register は1キーの LWW レジスタと、キーの有無を決める OR-Set のタグ
+// 削除は観測した追加のタグのみを打ち消すため、並行した書き込みがあればキーは残る
+type Register struct {
+	Value   []byte              `json:"value"`
+	Time    Timestamp           `json:"time"`    // Value を書き込んだ時刻
+	Adds    map[string]struct{} `json:"adds"`    // 書き込みごとのタグ
+	Removes map[string]struct{} `json:"removes"` // 削除で打ち消したタグ
+
+	// マージした全てのレジスタの書き込みを観測したバージョン（Value の書き込みより新しい場合がある）
+	Version Version `json:"version,omitempty"`
+}
+
+// Live はキーが存在するか（打ち消されていない追加のタグがあるか）を返す
+func (r *Register) Live() bool {
+	for tag := range r.Adds {
+		if _, removed := r.Removes[tag]; !removed {
+			return true
+		}
+	}
+	return false
+}
+
+// clone はレジスタの複製を返す
+func (r *Register) clone() *Register {
+	c := &Register{
+		Value:   append([]byte(nil), r.Value...),
+		Time:    r.Time,
+		Adds:    make(map[string]struct{}, len(r.Adds)),
+		Removes: make(map[string]struct{}, len(r.Removes)),
+		Version: r.Version.clone(),
+	}
+	for tag := range r.Adds {
+		c.Adds[tag] = struct{}{}
+	}
+	for tag := range r.Removes {
+		c.Removes[tag] = struct{}{}
+	}
+	return c
+}
+
+// Merge は o をレジスタにマージし、値の異なる書き込みを LWW で解決したかを返す
+func (r *Register) Merge(o *Register) (conflict bool) {
+	conflict = r.Live() && o.Live() && !bytes.Equal(r.Value, o.Value)
+	if o.Time.After(r.Time) {
+		r.Value = append([]byte(nil), o.Value...)
+		r.Time = o.Time
+	}
+	for tag := range o.Adds {
+		r.Adds[tag] = struct{}{}
+	}
+	for tag := range o.Removes {
+		r.Removes[tag] = struct{}{}
+	}
+	r.Version = r.Version.merge(o.Version)
+	return conflict
+}
+
+// CRDTState はマージでやり取りするノードのデータの状態（キーごとのレジスタ）
+type CRDTState map[string]*Register
+
+// crdtStore は StoreCRDT のノードが data と合わせて保持するレジスタ（Node.mu で保護する）
+type crdtStore struct {
+	regs map[string]*Register
+	wall int64  // 最後に使った時刻（書き込みごとに単調増加させる）
+	seq  uint64 // タグの連番
+}
+
+// newCRDTStore は data の全てのキーを versions のバージョンで now に書き込んだものとしてレジスタを作成する
+func newCRDTStore(nodeID string, data map[string][]byte, versions map[string]Version, now int64) *crdtStore {
+	s := &crdtStore{regs: make(map[string]*Register, len(data))}
+	for key, value := range data {
+		s.write(nodeID, key, value, versions[key], now)
+	}
+	return s
+}
+
+// stamp は now 以降で前回より新しい時刻を返す
+func (s *crdtStore) stamp(now int64) int64 {
+	s.wall = max(s.wall+1, now)
+	return s.wall
+}
+
+// write はキーへの書き込みを新しいタグで記録する
+func (s *crdtStore) write(nodeID, key string, value []byte, version Version, now int64) {
+	r, ok := s.regs[key]
+	if !ok {
+		r = &Register{Adds: make(map[string]struct{}), Removes: make(map[string]struct{})}
+		s.regs[key] = r
+	}
+	s.seq++
+	r.Adds[fmt.Sprintf("%s:%d", nodeID, s.seq)] = struct{}{}
+	r.Value = value
+	r.Time = Timestamp{Wall: s.stamp(now), Node: nodeID}
+	r.Version = version
+}
+
+// remove はキーの観測済みの追加のタグを打ち消す
+func (s *crdtStore) remove(key string) {
+	r, ok := s.regs[key]
+	if !ok {
+		return
+	}
+	for tag := range r.Adds {
+		r.Removes[tag] = struct{}{}
+	}
+}
+
+// forget はキーのレジスタを捨てる（TTL・上限による削除。他のノードとのマージで再び書き込まれうる）
+func (s *crdtStore) forget(key string) {
+	delete(s.regs, key)
+}
+
+// SetStoreKind はデータの保持方法を切り替える
+// StoreCRDT に切り替えた時点のデータは、その時刻にこのノードで書き込んだものとして扱う
+func (n *Node) SetStoreKind(kind StoreKind) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	switch {
+	case kind == StoreCRDT && n.crdt == nil:
+		n.crdt = newCRDTStore(n.id, n.data, n.versions, n.clock.Now().UnixNano())
+	case kind != StoreCRDT:
+		n.crdt = nil
+	}
+}
+
+// StoreKind はデータの保持方法を返す
+func (n *Node) StoreKind() StoreKind {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.crdt != nil {
+		return StoreCRDT
+	}
+	return StoreMap
+}
+
+// State は StoreCRDT のノードのデータの状態の複製を返す（StoreMap の場合は nil）
+// 状態にかかわらず取得できる
+func (n *Node) State() CRDTState {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.crdt == nil {
+		return nil
+	}
+	state := make(CRDTState, len(n.crdt.regs))
+	for key, r := range n.crdt.regs {
+		state[key] = r.clone()
+	}
+	return state
+}
+
+// Merge は他のノードの状態をマージし、値が変わったキーの数を返す（状態にかかわらずマージする）
+// 値は新しい書き込みが勝ち（LWW）、キーは並行した削除より書き込みが勝つ（OR-Set）
+func (n *Node) Merge(state CRDTState) (int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.crdt == nil {
+		return 0, fmt.Errorf("node %s does not use the %s store", n.id, StoreCRDT)
+	}
+
+	changed := 0
+	var records []walRecord
+	for key, remote := range state {
+		r, ok := n.crdt.regs[key]
+		if !ok {
+			r = &Register{Adds: make(map[string]struct{}), Removes: make(map[string]struct{})}
+			n.crdt.regs[key] = r
+		}
+		if r.Merge(remote) {
+			n.conflicts.Add(1)
+		}
+		// 受け取った時刻より後に書き込むことで、このノードの以降の書き込みを新しくする
+		n.crdt.wall = max(n.crdt.wall, remote.Time.Wall)
+
+		old, exists := n.data[key]
+		switch live := r.Live(); {
+		case live && (!exists || !bytes.Equal(old, r.Value)):
+			if exists {
+				n.bytes -= entrySize(key, old)
+			}
+			n.data[key] = r.Value
+			n.bytes += entrySize(key, r.Value)
+			delete(n.expiry, key)
+			n.touch(key)
+			changed++
+			records = append(records, walRecord{Op: "set", Key: key, Value: r.Value})
+		case !live && exists:
+			n.remove(key)
+			changed++
+			records = append(records, walRecord{Op: "delete", Key: key})
+		}
+		if r.Live() {
+			if n.versions == nil {
+				n.versions = make(map[string]Version)
+			}
+			n.versions[key] = r.Version.clone()
+		}
+	}
+	// マージした結果は他のノードに残っているため、記録に失敗してもマージは取り消さない
+	if err := n.logWrites(records...); err != nil {
+		log.Warn(n.id, "Failed to log %d merged keys: %v", len(records), err)
+	}
+	n.evict()
+	return changed, nil
+}
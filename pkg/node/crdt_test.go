@@ -0,0 +1,175 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+// newCRDTNode は start の仮想時計で動作する StoreCRDT のノードを起動する
+func newCRDTNode(t *testing.T, id string, start time.Time) (*Node, *clock.Simulated) {
+	t.Helper()
+	clk := clock.NewSimulated(start)
+	n := New(id)
+	n.SetClock(clk)
+	n.SetStoreKind(StoreCRDT)
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	t.Cleanup(func() { _ = n.Stop() })
+	return n, clk
+}
+
+// syncNodes は a と b の状態を互いにマージする
+func syncNodes(t *testing.T, a, b *Node) {
+	t.Helper()
+	stateA, stateB := a.State(), b.State()
+	if _, err := a.Merge(stateB); err != nil {
+		t.Fatalf("failed to merge into %s: %v", a.ID(), err)
+	}
+	if _, err := b.Merge(stateA); err != nil {
+		t.Fatalf("failed to merge into %s: %v", b.ID(), err)
+	}
+}
+
+func TestParseStoreKind(t *testing.T) {
+	for s, want := range map[string]StoreKind{"": StoreMap, "map": StoreMap, "CRDT": StoreCRDT} {
+		if got, err := ParseStoreKind(s); err != nil || got != want {
+			t.Errorf("ParseStoreKind(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseStoreKind("btree"); err == nil {
+		t.Error("expected an unknown store kind to fail")
+	}
+}
+
+func TestTimestampAfter(t *testing.T) {
+	if !(Timestamp{Wall: 2, Node: "a"}).After(Timestamp{Wall: 1, Node: "b"}) {
+		t.Error("expected the later wall time to win")
+	}
+	if !(Timestamp{Wall: 1, Node: "b"}).After(Timestamp{Wall: 1, Node: "a"}) {
+		t.Error("expected the larger node ID to break ties")
+	}
+}
+
+func TestNodeCRDTLastWriterWins(t *testing.T) {
+	a, _ := newCRDTNode(t, "node-1", time.Unix(1, 0))
+	b, _ := newCRDTNode(t, "node-2", time.Unix(2, 0))
+
+	_ = a.Set("key", []byte("from-a"))
+	_ = b.Set("key", []byte("from-b"))
+	_ = a.Set("only-a", []byte("a"))
+
+	changed, err := a.Merge(b.State())
+	if err != nil {
+		t.Fatalf("failed to merge: %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("expected 1 changed key, got %d", changed)
+	}
+	_, _ = b.Merge(a.State())
+
+	for _, n := range []*Node{a, b} {
+		if value, _ := n.Get("key"); string(value) != "from-b" {
+			t.Errorf("%s: expected the later write to win, got %q", n.ID(), value)
+		}
+		if value, _ := n.Get("only-a"); string(value) != "a" {
+			t.Errorf("%s: expected the key written on one node to be merged, got %q", n.ID(), value)
+		}
+	}
+	// b には a が解決済みの値が届くため、競合は a でのみ数える
+	if a.Stats().Conflicts != 1 || b.Stats().Conflicts != 0 {
+		t.Errorf("expected 1 resolved conflict on node-1 only, got %d and %d", a.Stats().Conflicts, b.Stats().Conflicts)
+	}
+
+	// 受け取った時刻より後に書き込むため、時計が遅れているノードの以降の書き込みが勝つ
+	_ = a.Set("key", []byte("again-a"))
+	syncNodes(t, a, b)
+	if value, _ := b.Get("key"); string(value) != "again-a" {
+		t.Errorf("expected the write after the merge to win, got %q", value)
+	}
+}
+
+func TestNodeCRDTAddWins(t *testing.T) {
+	a, _ := newCRDTNode(t, "node-1", time.Unix(1, 0))
+	b, _ := newCRDTNode(t, "node-2", time.Unix(1, 0))
+
+	_ = a.Set("removed", []byte("v1"))
+	_ = a.Set("concurrent", []byte("v1"))
+	syncNodes(t, a, b)
+
+	// 観測した書き込みだけを打ち消すため、並行した書き込みがあればキーは残る
+	_ = a.Delete("removed")
+	_ = a.Delete("concurrent")
+	_ = b.Set("concurrent", []byte("v2"))
+	syncNodes(t, a, b)
+
+	for _, n := range []*Node{a, b} {
+		if _, ok := n.Get("removed"); ok {
+			t.Errorf("%s: expected the deleted key to be removed", n.ID())
+		}
+		if value, ok := n.Get("concurrent"); !ok || string(value) != "v2" {
+			t.Errorf("%s: expected the concurrent write to survive the delete, got %q (%v)", n.ID(), value, ok)
+		}
+	}
+}
+
+func TestNodeCRDTConvergence(t *testing.T) {
+	nodes := make([]*Node, 3)
+	for i := range nodes {
+		nodes[i], _ = newCRDTNode(t, []string{"node-1", "node-2", "node-3"}[i], time.Unix(int64(i), 0))
+	}
+	_ = nodes[0].Set("x", []byte("0"))
+	_ = nodes[1].Set("x", []byte("1"))
+	_ = nodes[2].Set("y", []byte("2"))
+	_ = nodes[1].Delete("y") // 観測していない書き込みは打ち消さない
+
+	// マージの順序にかかわらず同じ状態に収束する
+	for _, pair := range [][2]int{{2, 0}, {0, 1}, {1, 2}, {2, 0}} {
+		syncNodes(t, nodes[pair[0]], nodes[pair[1]])
+	}
+	for _, n := range nodes {
+		x, _ := n.Get("x")
+		y, _ := n.Get("y")
+		if string(x) != "1" || string(y) != "2" || n.Size() != 2 {
+			t.Errorf("%s: expected x=1 and y=2, got x=%q y=%q size=%d", n.ID(), x, y, n.Size())
+		}
+	}
+}
+
+func TestNodeStoreKind(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	if n.StoreKind() != StoreMap || n.State() != nil {
+		t.Error("expected the map store by default")
+	}
+	if _, err := n.Merge(CRDTState{}); err == nil {
+		t.Error("expected merging into a map store to fail")
+	}
+
+	_ = n.Set("existing", []byte("value"))
+	n.SetStoreKind(StoreCRDT)
+	if n.StoreKind() != StoreCRDT {
+		t.Fatalf("expected the CRDT store, got %s", n.StoreKind())
+	}
+	state := n.State()
+	if r, ok := state["existing"]; !ok || !r.Live() || string(r.Value) != "value" {
+		t.Errorf("expected the existing data in the state, got %+v", state)
+	}
+
+	// 上限による削除はレジスタも捨てる
+	n.SetLimits(Limits{MaxKeys: 1})
+	_ = n.Set("newer", []byte("value"))
+	if _, ok := n.State()["existing"]; ok {
+		t.Error("expected the evicted key to be dropped from the state")
+	}
+
+	n.SetStoreKind(StoreMap)
+	if n.State() != nil {
+		t.Error("expected no state after switching back to the map store")
+	}
+}
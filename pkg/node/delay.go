@@ -0,0 +1,153 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DelayDistribution は操作ごとに注入する遅延の分布
+type DelayDistribution int
+
+const (
+	DelayConstant    DelayDistribution = iota // 常に Base
+	DelayUniform                              // Base ± Jitter の一様分布
+	DelayExponential                          // 平均 Base の指数分布
+	DelayPareto                               // 最小 Base・形状 Shape のパレート分布（裾の重い分布）
+)
+
+// String は分布の名前を返す
+func (d DelayDistribution) String() string {
+	switch d {
+	case DelayConstant:
+		return "constant"
+	case DelayUniform:
+		return "uniform"
+	case DelayExponential:
+		return "exponential"
+	case DelayPareto:
+		return "pareto"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultParetoShape は Shape を省略したパレート分布の形状（平均は Base の3倍）
+const DefaultParetoShape = 1.5
+
+// DelayProfile は操作ごとに注入する遅延の分布と、そのパラメータ
+// ゼロ値は遅延なし。Max が正の場合は引いた遅延を Max で打ち切る
+type DelayProfile struct {
+	Distribution DelayDistribution
+	Base         time.Duration // constant の遅延・uniform の中央・exponential の平均・pareto の最小
+	Jitter       time.Duration // uniform の中央からの幅
+	Shape        float64       // pareto の形状（0以下で DefaultParetoShape。小さいほど裾が重い）
+	Max          time.Duration // 遅延の上限（0で制限しない）
+}
+
+// Enabled は遅延を注入するかを返す
+func (p DelayProfile) Enabled() bool {
+	return p.Base > 0 || (p.Distribution == DelayUniform && p.Jitter > 0)
+}
+
+// Validate はプロファイルが有効かを確認する
+func (p DelayProfile) Validate() error {
+	switch {
+	case p.Distribution < DelayConstant || p.Distribution > DelayPareto:
+		return fmt.Errorf("unknown delay distribution: %d", p.Distribution)
+	case p.Base < 0 || p.Jitter < 0 || p.Max < 0:
+		return fmt.Errorf("delay must be non-negative")
+	case p.Shape < 0:
+		return fmt.Errorf("pareto shape must be non-negative")
+	}
+	return nil
+}
+
+// String はプロファイルを表す文字列を返す（constant は遅延そのもの）
+func (p DelayProfile) String() string {
+	var s string
+	switch p.Distribution {
+	case DelayUniform:
+		s = fmt.Sprintf("uniform(%v±%v)", p.Base, p.Jitter)
+	case DelayExponential:
+		s = fmt.Sprintf("exponential(mean %v)", p.Base)
+	case DelayPareto:
+		s = fmt.Sprintf("pareto(min %v, shape %g)", p.Base, p.shape())
+	default:
+		s = p.Base.String()
+	}
+	if p.Max > 0 {
+		s += fmt.Sprintf(" max %v", p.Max)
+	}
+	return s
+}
+
+// shape はパレート分布の形状を返す
+func (p DelayProfile) shape() float64 {
+	if p.Shape <= 0 {
+		return DefaultParetoShape
+	}
+	return p.Shape
+}
+
+// sample は分布から1回分の遅延を引く
+func (p DelayProfile) sample() time.Duration {
+	var d float64
+	switch p.Distribution {
+	case DelayUniform:
+		d = float64(p.Base) + (rand.Float64()*2-1)*float64(p.Jitter)
+	case DelayExponential:
+		d = rand.ExpFloat64() * float64(p.Base)
+	case DelayPareto:
+		// 逆関数法: U が (0,1] の一様分布のとき Base / U^(1/Shape) はパレート分布に従う
+		d = float64(p.Base) / math.Pow(1-rand.Float64(), 1/p.shape())
+	default:
+		d = float64(p.Base)
+	}
+	if p.Max > 0 && d > float64(p.Max) {
+		d = float64(p.Max)
+	}
+	if d <= 0 {
+		return 0
+	}
+	if d >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// SetDelayProfile は操作ごとに注入する遅延の分布を設定する（ゼロ値で遅延を解除）
+// 外部の実体が設定されている場合は分布によらず Base の固定の遅延を設定し、その設定に失敗した場合は遅延を変更しない
+func (n *Node) SetDelayProfile(p DelayProfile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+
+	if n.backend != nil {
+		if err := n.backend.SetDelay(p.Base); err != nil {
+			return fmt.Errorf("failed to set delay on backend: %w", err)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.delay = p
+	n.delayBackend = n.backend != nil
+	if p.Enabled() {
+		log.Info(n.id, "Delay set to %v", p)
+	} else {
+		log.Info(n.id, "Delay cleared")
+	}
+	return nil
+}
+
+// DelayProfile は現在の遅延の分布を返す
+func (n *Node) DelayProfile() DelayProfile {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.delay
+}
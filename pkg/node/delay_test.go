@@ -0,0 +1,118 @@
+package node
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelayProfileSample(t *testing.T) {
+	const samples = 10000
+	base := 10 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		profile  DelayProfile
+		min, max time.Duration
+	}{
+		{"constant", DelayProfile{Base: base}, base, base},
+		{"uniform", DelayProfile{Distribution: DelayUniform, Base: base, Jitter: 4 * time.Millisecond}, 6 * time.Millisecond, 14 * time.Millisecond},
+		{"uniform clamped at zero", DelayProfile{Distribution: DelayUniform, Base: time.Millisecond, Jitter: 5 * time.Millisecond}, 0, 6 * time.Millisecond},
+		{"exponential", DelayProfile{Distribution: DelayExponential, Base: base, Max: time.Second}, 0, time.Second},
+		{"pareto", DelayProfile{Distribution: DelayPareto, Base: base, Max: time.Second}, base, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range samples {
+				if d := tt.profile.sample(); d < tt.min || d > tt.max {
+					t.Fatalf("sample %v out of [%v, %v]", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestDelayProfileMean(t *testing.T) {
+	const samples = 20000
+	base := 10 * time.Millisecond
+
+	mean := func(p DelayProfile) time.Duration {
+		var total time.Duration
+		for range samples {
+			total += p.sample()
+		}
+		return total / samples
+	}
+
+	// 指数分布の平均は Base、形状 3 のパレート分布の平均は Base の 1.5 倍
+	if got := mean(DelayProfile{Distribution: DelayExponential, Base: base}); got < 9*time.Millisecond || got > 11*time.Millisecond {
+		t.Errorf("expected exponential mean near %v, got %v", base, got)
+	}
+	if got := mean(DelayProfile{Distribution: DelayPareto, Base: base, Shape: 3}); got < 14*time.Millisecond || got > 16*time.Millisecond {
+		t.Errorf("expected pareto mean near 15ms, got %v", got)
+	}
+}
+
+func TestDelayProfileValidate(t *testing.T) {
+	invalid := []DelayProfile{
+		{Distribution: DelayDistribution(99)},
+		{Base: -time.Millisecond},
+		{Distribution: DelayUniform, Jitter: -time.Millisecond},
+		{Max: -time.Millisecond},
+		{Distribution: DelayPareto, Shape: -1},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", p)
+		}
+	}
+	if err := (DelayProfile{Distribution: DelayPareto, Base: time.Millisecond}).Validate(); err != nil {
+		t.Errorf("expected a valid profile, got %v", err)
+	}
+}
+
+func TestDelayProfileString(t *testing.T) {
+	tests := []struct {
+		profile  DelayProfile
+		expected string
+	}{
+		{DelayProfile{Base: 100 * time.Millisecond}, "100ms"},
+		{DelayProfile{Distribution: DelayUniform, Base: 100 * time.Millisecond, Jitter: 20 * time.Millisecond}, "uniform(100ms±20ms)"},
+		{DelayProfile{Distribution: DelayExponential, Base: 50 * time.Millisecond}, "exponential(mean 50ms)"},
+		{DelayProfile{Distribution: DelayPareto, Base: 10 * time.Millisecond, Max: time.Second}, "pareto(min 10ms, shape 1.5) max 1s"},
+	}
+	for _, tt := range tests {
+		if got := tt.profile.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestNodeSetDelayProfile(t *testing.T) {
+	n := New("test-node-1")
+
+	p := DelayProfile{Distribution: DelayExponential, Base: 20 * time.Millisecond}
+	if err := n.SetDelayProfile(p); err != nil {
+		t.Fatalf("SetDelayProfile failed: %v", err)
+	}
+	if n.DelayProfile() != p || n.Delay() != 20*time.Millisecond {
+		t.Errorf("expected profile %v, got %v (delay %v)", p, n.DelayProfile(), n.Delay())
+	}
+
+	if err := n.SetDelayProfile(DelayProfile{Base: -time.Millisecond}); err == nil {
+		t.Error("expected an invalid profile to be rejected")
+	}
+	if n.DelayProfile() != p {
+		t.Error("expected an invalid profile to leave the delay unchanged")
+	}
+
+	// SetDelay は固定の遅延を設定する
+	n.SetDelay(30 * time.Millisecond)
+	if got := n.DelayProfile(); got != (DelayProfile{Base: 30 * time.Millisecond}) {
+		t.Errorf("expected a constant 30ms profile, got %v", got)
+	}
+	n.SetDelay(0)
+	if n.DelayProfile().Enabled() {
+		t.Error("expected the delay to be cleared")
+	}
+}
@@ -0,0 +1,238 @@
+// Package node provides an in-memory key-value store node implementation.
+//
+// Each Node represents a single KVS instance that can store and retrieve
+// key-value pairs. Nodes are thread-safe and support concurrent access.
+//
+// # Basic Usage
+//
+//	n := node.New("node-1")
+//	if err := n.Start(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer n.Stop()
+//
+//	// Store a value
+//	if err := n.Set("key", []byte("value")); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Retrieve a value
+//	if value, ok := n.Get("key"); ok {
+//	    fmt.Println(string(value))
+//	}
+//
+// # Node Lifecycle
+//
+// A Node must be started before it can accept read/write operations.
+// The lifecycle is: Stopped -> Running -> Stopped.
+//
+// SetReadOnly(true) moves a running node to ReadOnly, where Get, MGet and Scan
+// keep working but Set, MSet and Delete fail with an error wrapping
+// ErrReadOnly and count as rejected. SetReadOnly(false) makes it writable
+// again. Status.Available reports whether a node serves reads.
+//
+// # Expiration
+//
+// SetWithTTL stores a value that expires after the given duration. Expired
+// keys are hidden from Get, Keys, Size and Export immediately and removed by
+// a background sweeper every SweepInterval while the node is running; the
+// sweeper is only started once a key with a TTL is written and it uses the
+// node's clock. Stats().Expired counts the removed keys.
+//
+//	_ = n.SetWithTTL("session", []byte("token"), 30*time.Second)
+//	remaining, ok := n.TTL("session")
+//
+// Set, Delete and Import clear a key's TTL, and Export does not carry TTLs.
+//
+// # Memory Limits
+//
+// SetLimits bounds the number of keys and the total size of keys and values
+// a node holds. When a write exceeds a limit the node evicts the least
+// recently read or written keys until it fits again, and Stats().Evicted
+// counts them. A single value larger than MaxBytes is rejected. Without
+// limits the node does not track access order.
+//
+//	n.SetLimits(node.Limits{MaxKeys: 100000, MaxBytes: 64 << 20})
+//
+// MemoryUsage estimates the memory a node's data takes: Bytes plus a fixed
+// per-key overhead. MaxMemory caps it without evicting anything; a write that
+// would grow the usage beyond the cap fails with an error wrapping
+// ErrCapacity, and Stats().Refused counts the refused keys.
+//
+//	n.SetLimits(node.Limits{MaxMemory: 32 << 20})
+//	if err := n.Set("key", value); errors.Is(err, node.ErrCapacity) {
+//		// the node is full
+//	}
+//
+// # Batches
+//
+// MGet and MSet read or write several keys under one lock and pay the
+// node's delay once per call. MGet returns only the keys it found; MSet
+// checks every value against the limits before writing any of them.
+//
+//	_ = n.MSet(map[string][]byte{"a": []byte("1"), "b": []byte("2")})
+//	values := n.MGet([]string{"a", "b", "c"}) // {"a": "1", "b": "2"}
+//
+// MSetMissing and MDeleteUnchanged are the conditional batches used to move
+// keys between nodes: the first writes only keys the node does not hold, the
+// second deletes only keys whose value still matches. Neither waits for the
+// injected delay nor counts against Admission.
+//
+// # Durability
+//
+// By default a stopped node keeps its data in memory and serves it again
+// after Start. SetDurability changes that: DurabilityEphemeral drops all data
+// on every restart, and DurabilitySnapshot writes the data to a file every
+// Interval and reloads the last snapshot in Start, so writes after it are
+// lost when the node is killed. Stats().Lost counts the keys lost on
+// restarts and Stats().Snapshots the snapshots written; Persist writes one
+// immediately.
+//
+//	n.SetDurability(node.Durability{Mode: node.DurabilitySnapshot, Path: "node-1.json"})
+//
+// DurabilityWAL appends every write to a write-ahead log and replays it in
+// Start, compacting the log to the replayed data. Records carry the TTL's
+// expiry, keys removed by expiry or eviction are logged as deletes, merges
+// are logged key by key, and Import compacts the log to the imported data,
+// so none of them come back on a restart. Stop flushes the log before
+// closing it, while Kill (used by the chaos kill attack) drops the buffer
+// the way a crash would. Sync decides how much a kill loses:
+// WALSyncAlways fsyncs before acknowledging each write, WALSyncInterval
+// flushes the log every Interval, and WALSyncNone never flushes it, so
+// only writes that filled the buffer survive.
+//
+//	n.SetDurability(node.Durability{Mode: node.DurabilityWAL, Path: "node-1.wal", Sync: node.WALSyncAlways})
+//
+// EphemeralLossRatio sits between the memory and ephemeral modes: a node
+// with the memory durability loses that fraction of its keys, picked at
+// random, on every restart. CRDT stores forget the lost keys without
+// tombstones, so merges from other nodes can bring them back.
+//
+//	n.SetDurability(node.Durability{EphemeralLossRatio: 0.1})
+//
+// # Prefix Scans
+//
+// Scan returns the entries whose keys start with a prefix, sorted by key and
+// capped at a limit. It holds the read lock while walking every key, so
+// scans delay writers far longer than point reads do:
+//
+//	for _, e := range n.Scan("user:", 100) {
+//	    fmt.Println(e.Key, string(e.Value))
+//	}
+//
+// # Admission Control
+//
+// SetAdmission bounds the operations a node processes at once, counting those
+// still waiting out an injected delay. Instead of queueing, an operation above
+// MaxInFlight fails immediately with an error wrapping ErrOverloaded, and
+// Stats().Shed counts it. ReservedReads keeps part of the limit for reads so
+// that writes are shed first:
+//
+//	n.SetAdmission(node.Admission{MaxInFlight: 64, ReservedReads: 16})
+//	if _, _, err := n.GetContext(ctx, "key"); errors.Is(err, node.ErrOverloaded) {
+//	    // back off and retry
+//	}
+//
+// # CRDT Store
+//
+// SetStoreKind(StoreCRDT) keeps, alongside the plain map, a last-writer-wins
+// register per key whose presence is tracked as an observed-remove set.
+// State returns a copy of the registers and Merge folds another node's state
+// in: the newer write wins, ties are broken by node ID, and a delete only
+// removes the writes it has observed, so a concurrent write survives it.
+// Stats().Conflicts counts merges that resolved differing values.
+//
+//	a.SetStoreKind(node.StoreCRDT)
+//	b.SetStoreKind(node.StoreCRDT)
+//	_, _ = a.Merge(b.State())
+//
+// # Versions
+//
+// Every stored value carries a Version, a vector clock counting the writes
+// each node has made to the key. GetWithVersion returns it with the value,
+// and Compare tells whether two replicas of a key are equal, one is behind
+// the other, or they diverged concurrently. CRDT merges combine the clocks,
+// so a merged key has observed the writes of both nodes. Deleting a key
+// drops its version.
+//
+//	_, va, _ := a.GetWithVersion("key")
+//	_, vb, _ := b.GetWithVersion("key")
+//	diverged := va.Compare(vb) == node.VersionConcurrent
+//
+// # Delay Profiles
+//
+// SetDelay injects the same delay into every operation. SetDelayProfile
+// draws a new delay per operation from a distribution instead: constant,
+// uniform jitter around Base, exponential with mean Base, or pareto with
+// minimum Base, whose heavy tail reproduces the rare slow requests of real
+// systems. Max caps the drawn delay. Delay returns the profile's Base.
+//
+// Every operation, Delete included, waits out its delay on a clock timer
+// before taking the node's lock, so a delayed operation never holds up the
+// others; cancelling its context ends the wait early. The Context methods
+// wait in the calling goroutine. GetAsync, SetAsync and DeleteAsync return at
+// once and call their done callback when the timer fires, so no goroutine is
+// parked for the delay:
+//
+//	n.SetAsync(ctx, "key", []byte("v"), func(err error) { /* after the delay */ })
+//
+//	_ = n.SetDelayProfile(node.DelayProfile{
+//		Distribution: node.DelayPareto,
+//		Base:         5 * time.Millisecond,
+//		Max:          time.Second,
+//	})
+//
+// # Corruption
+//
+// SetCorruption makes a fraction of Get and MGet calls return the value with
+// one byte flipped, without touching the stored value, so consistency
+// checkers can catch silent corruption and not only unavailability.
+// Stats().Corrupted counts the corrupted reads; a rate of 0 turns it off.
+//
+//	n.SetCorruption(0.01) // corrupt 1% of reads
+//
+// # External Backends
+//
+// SetBackend attaches a Backend, such as an external.Process, that is driven
+// along with the node: Start, Stop, Suspend, Resume and SetDelay call the
+// backend first and leave the node unchanged if it fails. Chaos attacks and
+// recovery therefore act on the real process behind the node.
+// A delay set on a node with a backend is injected by the backend only, so
+// calls to the node itself are not delayed a second time.
+//
+// # Statistics
+//
+// Stats returns the node's own counters: gets with their hits and misses,
+// sets, deletes, scans, and operations rejected while the node was not
+// running. HitRate and Ops summarize them, and OpsPerSecond reports the rate
+// over the window since its previous call (at least a second), so polling it
+// shows which nodes are hot during a chaos run.
+//
+//	stats := n.Stats()
+//	fmt.Printf("%.0f ops/s, %.1f%% hits\n", n.OpsPerSecond(), stats.HitRate()*100)
+//
+// # Namespaces
+//
+// Several simulated tenants can share a node through namespaces. Namespace
+// declares a namespace, rejecting an empty name or one that contains
+// NamespaceSeparator, and returns a view that prefixes its keys:
+//
+//	orders, err := n.Namespace("orders")
+//	if err != nil {
+//		return err
+//	}
+//	_ = orders.Set("42", []byte("paid"))
+//	fmt.Println(orders.Size(), n.NamespaceStats()["orders"].Sets)
+//
+// Once declared, a key stored as "ns/key" (see NamespaceKey) belongs to
+// namespace ns whichever path wrote it, so keys sent over HTTP or RESP are
+// counted the same way. Keys under an undeclared prefix are ordinary keys.
+// NamespaceStats reports the keys and bytes held by each declared namespace
+// along with its gets, hits, sets and deletes; Namespace.Size and
+// Namespace.Stats scan only their own namespace.
+//
+// # Thread Safety
+//
+// All operations on a Node are protected by a RWMutex, allowing concurrent
+// reads while serializing writes.
+package node
@@ -0,0 +1,240 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+// DefaultPersistInterval は DurabilitySnapshot のノードがスナップショットを書き出すデフォルトの間隔
+const DefaultPersistInterval = time.Second
+
+// DurabilityMode は停止・再起動をまたいだデータの扱い
+type DurabilityMode string
+
+const (
+	DurabilityMemory    DurabilityMode = "memory"    // 停止してもメモリ上のデータを保持する（既定）
+	DurabilityEphemeral DurabilityMode = "ephemeral" // 再起動のたびに全てのデータを失う
+	DurabilitySnapshot  DurabilityMode = "snapshot"  // 一定間隔でファイルに書き出し、起動時に最後のスナップショットを読み込む
+	DurabilityWAL       DurabilityMode = "wal"       // 書き込みを先行書き込みログに追記し、起動時にログを再生する
+)
+
+// ParseDurabilityMode は文字列のデータの扱いをパースする（空で DurabilityMemory）
+func ParseDurabilityMode(s string) (DurabilityMode, error) {
+	switch mode := DurabilityMode(strings.ToLower(s)); mode {
+	case "":
+		return DurabilityMemory, nil
+	case DurabilityMemory, DurabilityEphemeral, DurabilitySnapshot, DurabilityWAL:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown durability mode: %s", s)
+	}
+}
+
+// Durability はノードのデータの永続性の設定（ゼロ値で DurabilityMemory）
+// DurabilitySnapshot では最後のスナップショットより後の書き込みが、DurabilityWAL ではログに書き出していない書き込みが強制停止（Kill）で失われる
+type Durability struct {
+	Mode     DurabilityMode
+	Path     string        // DurabilitySnapshot のスナップショットファイル、DurabilityWAL のログファイル
+	Interval time.Duration // スナップショット・WALSyncInterval のログを書き出す間隔（0で DefaultPersistInterval）
+	Sync     WALSync       // DurabilityWAL のログを書き出す頻度（空で WALSyncInterval）
+
+	// EphemeralLossRatio は DurabilityMemory のノードが再起動のたびに失うキーの割合（0〜1、0で全て保持する）
+	// 失うキーは再起動ごとにランダムに選ぶ。DurabilityEphemeral は割合1と同じで、他のモードでは使わない
+	EphemeralLossRatio float64
+}
+
+// Validate は Mode・Interval・Sync が有効かを確かめる（Path はファイルを読み込む起動時に確かめる）
+func (d Durability) Validate() error {
+	if _, err := ParseDurabilityMode(string(d.Mode)); err != nil {
+		return err
+	}
+	if d.Interval < 0 {
+		return fmt.Errorf("persist interval must be non-negative")
+	}
+	if _, err := ParseWALSync(string(d.Sync)); err != nil {
+		return err
+	}
+	if d.EphemeralLossRatio < 0 || d.EphemeralLossRatio > 1 {
+		return fmt.Errorf("ephemeral loss ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// interval はスナップショットを書き出す間隔を返す
+func (d Durability) interval() time.Duration {
+	if d.Interval > 0 {
+		return d.Interval
+	}
+	return DefaultPersistInterval
+}
+
+// SetDurability はデータの永続性を設定する（稼働中のノードには次の起動から反映する）
+func (n *Node) SetDurability(d Durability) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.durability = d
+}
+
+// Durability はデータの永続性の設定を返す
+func (n *Node) Durability() Durability {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.durability
+}
+
+// Persist は期限切れでない全データをスナップショットファイルに書き出す（状態にかかわらず書き出す）
+// 一時ファイルに書いてから置き換えるため、書き出し中に停止しても直前のスナップショットは壊れない。TTL は含まない
+func (n *Node) Persist() error {
+	d := n.Durability()
+	if d.Mode != DurabilitySnapshot {
+		return fmt.Errorf("node %s does not use the %s durability", n.id, DurabilitySnapshot)
+	}
+
+	n.persistMu.Lock()
+	defer n.persistMu.Unlock()
+	data, err := json.Marshal(n.Export())
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot of node %s: %w", n.id, err)
+	}
+	tmp := d.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot of node %s: %w", n.id, err)
+	}
+	if err := os.Rename(tmp, d.Path); err != nil {
+		return fmt.Errorf("failed to write snapshot of node %s: %w", n.id, err)
+	}
+	n.snapshots.Add(1)
+	return nil
+}
+
+// loadDurable は DurabilitySnapshot のスナップショット・DurabilityWAL のログから起動時のデータを読み込む
+// DurabilityWAL では期限のあるキーの期限も返す（スナップショットは期限を含まない）
+// それ以外のノードとファイルがまだない場合は nil を返す
+func (n *Node) loadDurable() (map[string][]byte, map[string]time.Time, error) {
+	d := n.Durability()
+	if d.Mode != DurabilitySnapshot && d.Mode != DurabilityWAL {
+		return nil, nil, nil
+	}
+	if d.Path == "" {
+		return nil, nil, fmt.Errorf("node %s uses the %s durability without a path", n.id, d.Mode)
+	}
+	if d.Mode == DurabilityWAL {
+		data, expiry, err := replayWAL(d.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to replay WAL of node %s: %w", n.id, err)
+		}
+		return data, expiry, nil
+	}
+	raw, err := os.ReadFile(d.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot of node %s: %w", n.id, err)
+	}
+	data := make(map[string][]byte)
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode snapshot of node %s: %w", n.id, err)
+	}
+	return data, nil, nil
+}
+
+// restore は起動時に Durability に従ってデータを置き換え、失ったキーを数える（mu を書き込みロックした状態で呼び出す）
+// snapshot は loadDurable で読み込んだデータ。まだ書き出していない場合（nil）は、最初の起動では起動前のデータを残し、再起動では全て失う
+// expiry は snapshot のキーの期限で、既に期限を過ぎたキーは読み込まない
+func (n *Node) restore(snapshot map[string][]byte, expiry map[string]time.Time) {
+	var lost int
+	now := n.clock.Now()
+	switch n.durability.Mode {
+	case "", DurabilityMemory:
+		ratio := n.durability.EphemeralLossRatio
+		if n.incarnations == 0 || ratio <= 0 {
+			return
+		}
+		for key := range n.data {
+			if n.expiredAt(key, now) || rand.Float64() >= ratio {
+				continue
+			}
+			n.remove(key)
+			if n.crdt != nil {
+				n.crdt.forget(key) // 削除ではないため、他のノードとのマージで再び書き込まれうる
+			}
+			lost++
+		}
+	case DurabilityEphemeral:
+		if n.incarnations == 0 {
+			return // 最初の起動では起動前に書き込まれたデータを残す
+		}
+		for key := range n.data {
+			if !n.expiredAt(key, now) {
+				lost++
+			}
+		}
+		n.replace(make(map[string][]byte))
+	case DurabilitySnapshot, DurabilityWAL:
+		if snapshot == nil {
+			if n.incarnations == 0 {
+				return
+			}
+			snapshot = make(map[string][]byte)
+		}
+		for key, value := range n.data {
+			if saved, ok := snapshot[key]; !n.expiredAt(key, now) && (!ok || !bytes.Equal(saved, value)) {
+				lost++
+			}
+		}
+		n.replace(snapshot)
+		for key, exp := range expiry {
+			if _, ok := n.data[key]; !ok {
+				continue
+			}
+			if !now.Before(exp) {
+				n.remove(key)
+				if n.crdt != nil {
+					n.crdt.forget(key)
+				}
+				continue
+			}
+			if n.expiry == nil {
+				n.expiry = make(map[string]time.Time)
+			}
+			n.expiry[key] = exp
+		}
+	default:
+		return
+	}
+	if lost > 0 {
+		n.lost.Add(uint64(lost))
+		log.Warn(n.id, "Lost %d keys on restart (%s durability)", lost, n.durability.Mode)
+	}
+}
+
+// persist は ctx がキャンセルされるまで一定間隔でスナップショットを書き出す
+// 一時停止中は書き出さない
+func (n *Node) persist(ctx context.Context, c clock.Clock, interval time.Duration) {
+	ticker := c.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+			if !n.Status().Available() {
+				continue
+			}
+			if err := n.Persist(); err != nil {
+				log.Warn(n.id, "Failed to persist snapshot: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+func TestParseDurabilityMode(t *testing.T) {
+	for s, want := range map[string]DurabilityMode{"": DurabilityMemory, "ephemeral": DurabilityEphemeral, "Snapshot": DurabilitySnapshot} {
+		if got, err := ParseDurabilityMode(s); err != nil || got != want {
+			t.Errorf("ParseDurabilityMode(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseDurabilityMode("disk"); err == nil {
+		t.Error("expected an unknown mode to fail")
+	}
+	if err := (Durability{Interval: -time.Second}).Validate(); err == nil {
+		t.Error("expected a negative interval to be invalid")
+	}
+	if err := (Durability{EphemeralLossRatio: 1.5}).Validate(); err == nil {
+		t.Error("expected a loss ratio above 1 to be invalid")
+	}
+	n := New("test-node-1")
+	n.SetDurability(Durability{Mode: DurabilitySnapshot})
+	if err := n.Start(context.Background()); err == nil {
+		t.Error("expected the snapshot mode without a path to fail to start")
+	}
+}
+
+// restart はノードを停止して再び起動する
+func restart(t *testing.T, n *Node) {
+	t.Helper()
+	if err := n.Stop(); err != nil {
+		t.Fatalf("failed to stop node: %v", err)
+	}
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to restart node: %v", err)
+	}
+}
+
+// crash はノードを強制停止（Kill）してから起動し直す
+func crash(t *testing.T, n *Node) {
+	t.Helper()
+	if err := n.Kill(); err != nil {
+		t.Fatalf("failed to kill node: %v", err)
+	}
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to restart node: %v", err)
+	}
+}
+
+func TestNodeDurabilityMemory(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("key", []byte("value"))
+	restart(t, n)
+	if _, ok := n.Get("key"); !ok || n.Stats().Lost != 0 {
+		t.Error("expected the data to survive a restart by default")
+	}
+}
+
+func TestNodeDurabilityEphemeral(t *testing.T) {
+	n := New("test-node-1")
+	n.SetDurability(Durability{Mode: DurabilityEphemeral})
+	n.Import(map[string][]byte{"imported": []byte("v")})
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	// 最初の起動では起動前のデータを残す
+	if _, ok := n.Get("imported"); !ok {
+		t.Error("expected the data imported before the first start to be kept")
+	}
+	_ = n.Set("key", []byte("value"))
+	restart(t, n)
+	if n.Size() != 0 || n.Stats().Lost != 2 {
+		t.Errorf("expected all 2 keys to be lost, got %d keys and %d lost", n.Size(), n.Stats().Lost)
+	}
+}
+
+func TestNodeDurabilityEphemeralLossRatio(t *testing.T) {
+	const keys = 1000
+	n := New("test-node-1")
+	n.SetDurability(Durability{EphemeralLossRatio: 0.3})
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	for i := range keys {
+		_ = n.Set(fmt.Sprintf("key-%d", i), []byte("value"))
+	}
+	restart(t, n)
+
+	// 約3割のキーを失い、失った数と残った数の合計は元のキーの数になる
+	lost := n.Stats().Lost
+	if lost < 200 || lost > 400 {
+		t.Errorf("expected about 300 lost keys, got %d", lost)
+	}
+	if n.Size()+int(lost) != keys {
+		t.Errorf("expected %d kept and lost keys, got %d kept and %d lost", keys, n.Size(), lost)
+	}
+	var bytes int64
+	for key, value := range n.Export() {
+		bytes += entrySize(key, value)
+	}
+	if n.Bytes() != bytes {
+		t.Errorf("expected %d bytes after the loss, got %d", bytes, n.Bytes())
+	}
+}
+
+func TestNodeDurabilityMemoryKeepsData(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("key", []byte("value"))
+	restart(t, n)
+	if n.Size() != 1 || n.Stats().Lost != 0 {
+		t.Errorf("expected the memory durability to keep all keys, got %d keys and %d lost", n.Size(), n.Stats().Lost)
+	}
+}
+
+func TestNodeDurabilitySnapshot(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	path := filepath.Join(t.TempDir(), "node.json")
+	n := New("test-node-1")
+	n.SetClock(clk)
+	n.SetDurability(Durability{Mode: DurabilitySnapshot, Path: path, Interval: time.Second})
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("saved", []byte("v1"))
+	_ = n.Set("changed", []byte("v1"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("persister did not start: %v", err)
+	}
+	clk.Advance(time.Second)
+	for n.Stats().Snapshots == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// スナップショットより後の書き込みは停止で失われる
+	_ = n.Set("changed", []byte("v2"))
+	_ = n.Set("unsaved", []byte("v1"))
+	restart(t, n)
+
+	if value, _ := n.Get("saved"); string(value) != "v1" {
+		t.Errorf("expected the persisted key to survive, got %q", value)
+	}
+	if value, _ := n.Get("changed"); string(value) != "v1" {
+		t.Errorf("expected the value of the last snapshot, got %q", value)
+	}
+	if _, ok := n.Get("unsaved"); ok {
+		t.Error("expected the key written after the snapshot to be lost")
+	}
+	if n.Stats().Lost != 2 {
+		t.Errorf("expected 2 lost keys, got %d", n.Stats().Lost)
+	}
+
+	// 別のノードでも同じファイルから読み込める
+	other := New("test-node-2")
+	other.SetDurability(Durability{Mode: DurabilitySnapshot, Path: path})
+	_ = other.Start(context.Background())
+	defer func() { _ = other.Stop() }()
+	if other.Size() != 2 {
+		t.Errorf("expected 2 keys loaded from the snapshot, got %d", other.Size())
+	}
+}
+
+func TestNodePersistRequiresSnapshotMode(t *testing.T) {
+	if err := New("test-node-1").Persist(); err == nil {
+		t.Error("expected persisting without the snapshot mode to fail")
+	}
+}
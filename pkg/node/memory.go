@@ -0,0 +1,48 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCapacity は書き込むと MemoryUsage が Limits.MaxMemory を超えるため、ノードが書き込みを拒否したことを表す
+var ErrCapacity = errors.New("node is over memory capacity")
+
+// entryOverhead はキーごとにキーと値のバイト列以外で使うメモリの概算（マップのエントリ・スライスのヘッダ・バージョン）
+const entryOverhead = 64
+
+// memoryUsage は bytes のキーと値を keys 個保持するときのメモリ使用量の概算
+func memoryUsage(keys int, bytes int64) int64 {
+	return bytes + int64(keys)*entryOverhead
+}
+
+// MemoryUsage は保持するデータのメモリ使用量の概算を返す
+// キーと値の合計バイト数（Bytes）にキーごとの管理用のメモリを加えたもので、期限切れで未削除のキーを含む
+func (n *Node) MemoryUsage() int64 {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return memoryUsage(len(n.data), n.bytes)
+}
+
+// checkCapacity は entries を書き込んでも MemoryUsage が MaxMemory を超えないかを確かめる（mu を保持して呼ぶ）
+// 超える場合は書き込み全体を ErrCapacity で拒否する。上書きで使用量が減る書き込みは常に受け付ける
+func (n *Node) checkCapacity(entries map[string][]byte) error {
+	if n.limits.MaxMemory <= 0 {
+		return nil
+	}
+	keys, bytes := len(n.data), n.bytes
+	for key, value := range entries {
+		if old, exists := n.data[key]; exists {
+			bytes -= entrySize(key, old)
+		} else {
+			keys++
+		}
+		bytes += entrySize(key, value)
+	}
+	usage := memoryUsage(keys, bytes)
+	if usage <= n.limits.MaxMemory || usage <= memoryUsage(len(n.data), n.bytes) {
+		return nil
+	}
+	n.refused.Add(uint64(len(entries)))
+	return fmt.Errorf("%w: node %s would use %d of %d bytes", ErrCapacity, n.id, usage, n.limits.MaxMemory)
+}
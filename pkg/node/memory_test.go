@@ -0,0 +1,56 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNodeMemoryUsage(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	if n.MemoryUsage() != 0 {
+		t.Errorf("expected no memory usage on an empty node, got %d", n.MemoryUsage())
+	}
+	_ = n.Set("key", []byte("value"))
+	if want := int64(len("key")+len("value")) + entryOverhead; n.MemoryUsage() != want {
+		t.Errorf("expected %d bytes, got %d", want, n.MemoryUsage())
+	}
+	_ = n.Delete("key")
+	if n.MemoryUsage() != 0 {
+		t.Errorf("expected the usage to drop after a delete, got %d", n.MemoryUsage())
+	}
+}
+
+func TestNodeMaxMemory(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	// 1キー分の使用量は 1+10+64 = 75 バイト
+	n.SetLimits(Limits{MaxMemory: 160})
+	_ = n.Set("a", make([]byte, 10))
+	_ = n.Set("b", make([]byte, 10))
+	if err := n.Set("c", make([]byte, 10)); !errors.Is(err, ErrCapacity) {
+		t.Fatalf("expected ErrCapacity, got %v", err)
+	}
+	if n.Size() != 2 || n.Stats().Evicted != 0 {
+		t.Errorf("expected the write to be refused without evicting, got %d keys and %d evicted", n.Size(), n.Stats().Evicted)
+	}
+
+	// 使用量の増えない上書きは受け付ける
+	if err := n.Set("a", make([]byte, 5)); err != nil {
+		t.Errorf("expected a shrinking overwrite to succeed, got %v", err)
+	}
+	if err := n.MSet(map[string][]byte{"b": []byte("x"), "d": make([]byte, 10)}); !errors.Is(err, ErrCapacity) {
+		t.Errorf("expected the batch over capacity to fail, got %v", err)
+	}
+	if value, _ := n.Get("b"); len(value) != 10 {
+		t.Error("expected no key of the refused batch to be written")
+	}
+	if refused := n.Stats().Refused; refused != 3 {
+		t.Errorf("expected 3 refused keys, got %d", refused)
+	}
+}
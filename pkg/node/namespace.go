@@ -0,0 +1,205 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// NamespaceSeparator は格納するキーで名前空間とキーを区切る文字列
+const NamespaceSeparator = "/"
+
+// NamespaceKey は名前空間 ns のキー key を、ノードに格納するキーにする（ns が空の場合は key のまま）
+func NamespaceKey(ns, key string) string {
+	if ns == "" {
+		return key
+	}
+	return ns + NamespaceSeparator + key
+}
+
+// SplitNamespace は格納したキーを名前空間とキーに分ける（区切りを含まない場合は名前空間が空）
+func SplitNamespace(stored string) (ns, key string) {
+	ns, key, ok := strings.Cut(stored, NamespaceSeparator)
+	if !ok {
+		return "", stored
+	}
+	return ns, key
+}
+
+// NamespaceStats は名前空間ごとのデータ量と操作回数
+type NamespaceStats struct {
+	Keys    int   // 期限切れでないキーの数
+	Bytes   int64 // キーと値の合計バイト数（名前空間を付けたキーで数える）
+	Gets    uint64
+	Hits    uint64
+	Sets    uint64
+	Deletes uint64
+}
+
+// namespaceCounters は名前空間ごとの操作回数
+type namespaceCounters struct {
+	gets, hits, sets, deletes atomic.Uint64
+}
+
+// namespaceTable は Node.Namespace で登録した名前空間ごとの操作回数の表（ゼロ値で使える）
+// 操作ごとに参照するため、ノードの mu とは別に sync.Map で保持する
+type namespaceTable struct {
+	counters sync.Map // 名前空間 → *namespaceCounters
+}
+
+// declare は名前空間を登録する（登録済みの場合は何もしない）
+func (t *namespaceTable) declare(name string) {
+	if _, ok := t.counters.Load(name); !ok {
+		t.counters.LoadOrStore(name, &namespaceCounters{})
+	}
+}
+
+// of はキーの名前空間の操作回数を返す（登録していない名前空間のキー・名前空間のないキーは nil）
+// "/" を含むだけの通常のキーを名前空間として数えないよう、新しい名前空間は作らない
+func (t *namespaceTable) of(key string) *namespaceCounters {
+	ns, _ := SplitNamespace(key)
+	if ns == "" {
+		return nil
+	}
+	if c, ok := t.counters.Load(ns); ok {
+		return c.(*namespaceCounters)
+	}
+	return nil
+}
+
+// ValidateNamespace は名前空間の名前が空でなく NamespaceSeparator を含まないかを確かめる
+func ValidateNamespace(name string) error {
+	if name == "" || strings.Contains(name, NamespaceSeparator) {
+		return fmt.Errorf("namespace must be non-empty and must not contain %q: %q", NamespaceSeparator, name)
+	}
+	return nil
+}
+
+// Namespace はノードの中の論理的な名前空間（テナント）
+// キーは NamespaceKey で名前空間を前に付けて格納するため、同じノードの他の名前空間とキーが衝突しない
+type Namespace struct {
+	node *Node
+	name string
+}
+
+// Namespace は名前空間 name を登録し、その操作を返す（name は NamespaceSeparator を含まない空でない文字列）
+// 登録した名前空間の "name/" で始まるキーは、どの経路で書き込まれても NamespaceStats に数える
+func (n *Node) Namespace(name string) (*Namespace, error) {
+	if err := ValidateNamespace(name); err != nil {
+		return nil, err
+	}
+	n.namespaces.declare(name)
+	return &Namespace{node: n, name: name}, nil
+}
+
+// Name は名前空間の名前を返す
+func (ns *Namespace) Name() string {
+	return ns.name
+}
+
+// Get は名前空間のキーの値を取得する
+func (ns *Namespace) Get(key string) ([]byte, bool) {
+	return ns.node.Get(NamespaceKey(ns.name, key))
+}
+
+// GetContext は名前空間のキーの値を取得する（遅延と Admission の扱いは Node.GetContext と同じ）
+func (ns *Namespace) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	return ns.node.GetContext(ctx, NamespaceKey(ns.name, key))
+}
+
+// Set は名前空間のキーに値を設定する
+func (ns *Namespace) Set(key string, value []byte) error {
+	return ns.node.Set(NamespaceKey(ns.name, key), value)
+}
+
+// SetContext は名前空間のキーに値を設定する（遅延と Admission・上限の扱いは Node.SetContext と同じ）
+func (ns *Namespace) SetContext(ctx context.Context, key string, value []byte) error {
+	return ns.node.SetContext(ctx, NamespaceKey(ns.name, key), value)
+}
+
+// Delete は名前空間のキーを削除する
+func (ns *Namespace) Delete(key string) error {
+	return ns.node.Delete(NamespaceKey(ns.name, key))
+}
+
+// DeleteContext は名前空間のキーを削除する（遅延と Admission の扱いは Node.DeleteContext と同じ）
+func (ns *Namespace) DeleteContext(ctx context.Context, key string) error {
+	return ns.node.DeleteContext(ctx, NamespaceKey(ns.name, key))
+}
+
+// Scan は名前空間の prefix で始まるキーと値を、名前空間を除いたキーの昇順に最大 limit 件返す
+func (ns *Namespace) Scan(prefix string, limit int) []Entry {
+	entries := ns.node.Scan(NamespaceKey(ns.name, prefix), limit)
+	for i := range entries {
+		_, entries[i].Key = SplitNamespace(entries[i].Key)
+	}
+	return entries
+}
+
+// Size は名前空間の期限切れでないキーの数を返す
+func (ns *Namespace) Size() int {
+	keys, _ := ns.node.namespaceUsage(ns.name)
+	return keys
+}
+
+// Stats は名前空間のデータ量と操作回数を返す
+func (ns *Namespace) Stats() NamespaceStats {
+	var stats NamespaceStats
+	if c, ok := ns.node.namespaces.counters.Load(ns.name); ok {
+		stats = c.(*namespaceCounters).stats()
+	}
+	stats.Keys, stats.Bytes = ns.node.namespaceUsage(ns.name)
+	return stats
+}
+
+// namespaceUsage は名前空間 name の期限切れでないキーの数と合計バイト数を返す
+func (n *Node) namespaceUsage(name string) (keys int, bytes int64) {
+	prefix := name + NamespaceSeparator
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	now := n.clock.Now()
+	for key, value := range n.data {
+		if strings.HasPrefix(key, prefix) && !n.expiredAt(key, now) {
+			keys++
+			bytes += entrySize(key, value)
+		}
+	}
+	return keys, bytes
+}
+
+// stats は操作回数を NamespaceStats にする（データ量は含まない）
+func (c *namespaceCounters) stats() NamespaceStats {
+	return NamespaceStats{
+		Gets:    c.gets.Load(),
+		Hits:    c.hits.Load(),
+		Sets:    c.sets.Load(),
+		Deletes: c.deletes.Load(),
+	}
+}
+
+// NamespaceStats は Namespace で登録した名前空間ごとのデータ量と操作回数を返す
+// 登録していない名前空間のキー・名前空間のないキーは含まない
+func (n *Node) NamespaceStats() map[string]NamespaceStats {
+	stats := make(map[string]NamespaceStats)
+	n.namespaces.counters.Range(func(name, value any) bool {
+		stats[name.(string)] = value.(*namespaceCounters).stats()
+		return true
+	})
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	now := n.clock.Now()
+	for key, value := range n.data {
+		ns, _ := SplitNamespace(key)
+		s, declared := stats[ns]
+		if !declared || n.expiredAt(key, now) {
+			continue
+		}
+		s.Keys++
+		s.Bytes += entrySize(key, value)
+		stats[ns] = s
+	}
+	return stats
+}
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNamespaceKey(t *testing.T) {
+	if got := NamespaceKey("tenant-a", "key"); got != "tenant-a/key" {
+		t.Errorf("NamespaceKey = %q, want tenant-a/key", got)
+	}
+	if got := NamespaceKey("", "key"); got != "key" {
+		t.Errorf("NamespaceKey without a namespace = %q, want key", got)
+	}
+	if ns, key := SplitNamespace("tenant-a/key/with/slashes"); ns != "tenant-a" || key != "key/with/slashes" {
+		t.Errorf("SplitNamespace = %q, %q", ns, key)
+	}
+	if ns, key := SplitNamespace("plain"); ns != "" || key != "plain" {
+		t.Errorf("SplitNamespace without a separator = %q, %q", ns, key)
+	}
+}
+
+func TestNodeNamespace(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	a, err := n.Namespace("a")
+	if err != nil {
+		t.Fatalf("Namespace failed: %v", err)
+	}
+	b, err := n.Namespace("b")
+	if err != nil {
+		t.Fatalf("Namespace failed: %v", err)
+	}
+	_ = a.Set("key", []byte("from-a"))
+	_ = b.Set("key", []byte("from-b"))
+	_ = b.Set("other", []byte("v"))
+	_ = n.Set("plain", []byte("v"))
+
+	// 同じキーでも名前空間ごとに別の値を持つ
+	if value, ok := a.Get("key"); !ok || string(value) != "from-a" {
+		t.Errorf("expected a's value, got %q (%v)", value, ok)
+	}
+	if value, ok := b.Get("key"); !ok || string(value) != "from-b" {
+		t.Errorf("expected b's value, got %q (%v)", value, ok)
+	}
+	if _, ok := a.Get("other"); ok {
+		t.Error("expected b's key not to be visible in a")
+	}
+	if value, ok := n.Get("a/key"); !ok || string(value) != "from-a" {
+		t.Errorf("expected the namespaced key to be stored with its prefix, got %q (%v)", value, ok)
+	}
+
+	entries := b.Scan("", 0)
+	if len(entries) != 2 || entries[0].Key != "key" || entries[1].Key != "other" {
+		t.Errorf("expected b's keys without the prefix, got %+v", entries)
+	}
+
+	_ = b.Delete("other")
+	if b.Size() != 1 || a.Size() != 1 || n.Size() != 3 {
+		t.Errorf("expected 1 key in each namespace and 3 in the node, got %d, %d, %d", a.Size(), b.Size(), n.Size())
+	}
+}
+
+func TestNodeNamespaceStats(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	a, _ := n.Namespace("a")
+	if _, err := n.Namespace("b"); err != nil {
+		t.Fatalf("Namespace failed: %v", err)
+	}
+	_ = a.Set("k1", []byte("value"))
+	_ = a.Set("k2", []byte("value"))
+	a.Get("k1")
+	a.Get("missing")
+	_ = a.Delete("k2")
+	n.MGet([]string{"b/k1"})
+	_ = n.Set("plain", []byte("v"))
+	_ = n.Set("x/undeclared", []byte("v"))
+
+	stats := n.NamespaceStats()
+	if len(stats) != 2 {
+		t.Fatalf("expected stats for namespaces a and b, got %+v", stats)
+	}
+	want := NamespaceStats{Keys: 1, Bytes: entrySize("a/k1", []byte("value")), Gets: 2, Hits: 1, Sets: 2, Deletes: 1}
+	if got := a.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := stats["b"]; got != (NamespaceStats{Gets: 1}) {
+		t.Errorf("expected one missed get in b, got %+v", got)
+	}
+}
+
+func TestNodeNamespaceInvalid(t *testing.T) {
+	n := New("test-node-1")
+
+	for _, name := range []string{"", "a/b", "/"} {
+		if _, err := n.Namespace(name); err == nil {
+			t.Errorf("expected Namespace(%q) to fail", name)
+		}
+	}
+	if stats := n.NamespaceStats(); len(stats) != 0 {
+		t.Errorf("expected no namespaces to be declared, got %+v", stats)
+	}
+}
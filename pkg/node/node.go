@@ -0,0 +1,917 @@
+package node
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+// log はコンポーネント名 "node" を付けてログを出力する子ロガー
+var log = logger.With("node")
+
+// Store はKVSの基本操作を定義するインターフェース
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte) error
+	Delete(key string) error
+	Keys() []string
+	Size() int
+}
+
+// Ensure Node implements Store
+var _ Store = (*Node)(nil)
+
+// Status はノードの状態を表す
+type Status int
+
+const (
+	StatusStopped Status = iota
+	StatusRunning
+	StatusSuspended
+	StatusReadOnly // 読み取りのみを受け付ける（書き込みの障害）
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusStopped:
+		return "stopped"
+	case StatusRunning:
+		return "running"
+	case StatusSuspended:
+		return "suspended"
+	case StatusReadOnly:
+		return "readonly"
+	default:
+		return "unknown"
+	}
+}
+
+// Available は読み取りを受け付ける状態（StatusRunning・StatusReadOnly）かを返す
+func (s Status) Available() bool {
+	return s == StatusRunning || s == StatusReadOnly
+}
+
+// LabelZone はノードの配置ゾーンを表すラベルキー
+const LabelZone = "zone"
+
+// SweepInterval は期限切れのキーを削除する間隔
+const SweepInterval = time.Second
+
+// Stats はノードが受け付けた操作の回数
+type Stats struct {
+	Gets      uint64 // Get の回数（稼働中のみ）
+	Hits      uint64 // 値が見つかった Get の回数
+	Misses    uint64 // 値が見つからなかった Get の回数（Gets - Hits）
+	Sets      uint64 // Set の回数（稼働中のみ）
+	Deletes   uint64 // Delete の回数（稼働中のみ）
+	Scans     uint64 // Scan の回数（稼働中のみ）
+	Rejected  uint64 // 稼働中でない・読み取り専用のため失敗した操作の回数
+	Shed      uint64 // Admission の上限に達したため ErrOverloaded で打ち切った操作の回数
+	Expired   uint64 // TTL の経過で削除したキーの数
+	Evicted   uint64 // Limits を超えたため削除したキーの数
+	Lost      uint64 // Durability により再起動で失ったキーの数
+	Snapshots uint64 // Durability のスナップショットを書き出した回数
+	Conflicts uint64 // Merge で値の異なる書き込みを LWW で解決した回数
+	Refused   uint64 // Limits.MaxMemory を超えるため ErrCapacity で拒否したキーの書き込みの回数
+	Corrupted uint64 // SetCorruption により値を壊して返した読み取りの回数
+}
+
+// Ops は稼働中に受け付けた読み書きの操作（Get・Set・Delete・Scan）の回数を返す
+func (s Stats) Ops() uint64 {
+	return s.Gets + s.Sets + s.Deletes + s.Scans
+}
+
+// HitRate は Get のうち値が見つかった割合を返す（Get がない場合は0）
+func (s Stats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// Limits はノードが保持するデータの上限（ゼロ値の項目は制限しない）
+// 上限を超えた場合は最も長く読み書きされていないキーから削除する
+type Limits struct {
+	MaxKeys  int   // キー数の上限
+	MaxBytes int64 // キーと値の合計バイト数の上限
+
+	// MaxMemory は MemoryUsage の上限。超える書き込みはキーを削除せずに ErrCapacity で拒否する
+	MaxMemory int64
+}
+
+// Enabled はキーを削除するいずれかの上限が設定されているかを返す（MaxMemory は含めない）
+func (l Limits) Enabled() bool {
+	return l.MaxKeys > 0 || l.MaxBytes > 0
+}
+
+// entrySize はキーと値が占めるバイト数
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
+
+// Backend はノードの状態の変更に合わせて操作する外部の実体（コンテナやプロセスなど）
+// 設定したノードは状態を変更する前に対応するメソッドを呼び、エラーの場合は状態を変更しない
+type Backend interface {
+	Start(ctx context.Context) error
+	Stop() error
+	Suspend() error
+	Resume() error
+	SetDelay(d time.Duration) error
+}
+
+// Node はインメモリKVSの単一ノードを表す
+type Node struct {
+	id           string
+	status       Status
+	delay        DelayProfile
+	delayBackend bool // 遅延を backend が注入しているか（ノード自身の呼び出しには遅延をかけない）
+	labels       map[string]string
+	incarnations int         // 起動した回数
+	clock        clock.Clock // 遅延の待機に使う時計
+
+	// backend の呼び出しは時間がかかりうるため mu を保持せず、opMu で状態の変更どうしを直列化する
+	opMu    sync.Mutex
+	backend Backend
+
+	gets, hits, sets, deletes, scans, rejected, shed, expired, evicted, conflicts, lost, snapshots, refused, corrupted atomic.Uint64
+
+	// 操作ごとに参照するため、同時に処理する操作の上限と処理中の操作の数はロックを取らずに扱う
+	admission atomic.Pointer[Admission] // nil で制限しない
+	inflight  atomic.Int64
+
+	rate opsRate // OpsPerSecond の前回の計測
+
+	namespaces namespaceTable // 名前空間ごとの操作回数
+
+	mu      sync.RWMutex
+	data    map[string][]byte
+	bytes   int64                // data のキーと値の合計バイト数
+	expiry  map[string]time.Time // TTL を設定したキーの期限
+	sweeper context.Context      // 期限切れのキーを削除するゴルーチンが動作している ctx
+	limits  Limits
+	crdt    *crdtStore // StoreCRDT の場合のレジスタ（StoreMap の場合は nil）
+
+	// data のキーごとのバージョン。書き込みのたびに新しい Version に差し替え、格納した Version は変更しない
+	versions map[string]Version
+
+	// 値を壊して返す読み取りの割合（0で壊さない）
+	corruption float64
+
+	durability Durability
+	persistMu  sync.Mutex // スナップショットの書き出しどうしを直列化する
+	wal        *walLog    // DurabilityWAL の稼働中のログ（それ以外は nil）
+
+	// Get は mu の読み取りロックで並行するため、読み書きの順序は lruMu で保護する（mu の後に取る）
+	lruMu    sync.Mutex
+	lru      *list.List               // 最近読み書きした順のキー（Limits が無効の場合は nil）
+	lruIndex map[string]*list.Element // キーごとの lru の要素
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New は新しいノードを作成する
+func New(id string) *Node {
+	return &Node{
+		id:     id,
+		status: StatusStopped,
+		clock:  clock.Real(),
+		data:   make(map[string][]byte),
+	}
+}
+
+// SetClock は注入された遅延の待機に使う時計を設定する（nil で実時間）
+func (n *Node) SetClock(c clock.Clock) {
+	if c == nil {
+		c = clock.Real()
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.clock = c
+}
+
+// ID はノードIDを返す
+func (n *Node) ID() string {
+	return n.id
+}
+
+// SetBackend はノードの状態の変更に合わせて操作する外部の実体を設定する（nil で解除）
+func (n *Node) SetBackend(b Backend) {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+	n.backend = b
+}
+
+// Backend は設定された外部の実体を返す（未設定の場合は nil）
+func (n *Node) Backend() Backend {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+	return n.backend
+}
+
+// Start はノードを起動する
+// 停止中のノードだけを起動する（一時停止中のノードは Resume で再開する）
+func (n *Node) Start(ctx context.Context) error {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+
+	if status := n.Status(); status != StatusStopped {
+		return fmt.Errorf("node %s is already started (%s)", n.id, status)
+	}
+	snapshot, expiry, err := n.loadDurable()
+	if err != nil {
+		return err
+	}
+	if n.backend != nil {
+		if err := n.backend.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start backend of node %s: %w", n.id, err)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.restore(snapshot, expiry)
+	if n.durability.Mode == DurabilityWAL {
+		policy, _ := ParseWALSync(string(n.durability.Sync))
+		if n.wal, err = openWAL(n.durability.Path, policy, n.data, n.expiry); err != nil {
+			return fmt.Errorf("failed to open WAL of node %s: %w", n.id, err)
+		}
+	}
+	n.ctx, n.cancel = context.WithCancel(ctx)
+	n.status = StatusRunning
+	n.incarnations++
+	if len(n.expiry) > 0 {
+		n.startSweeper()
+	}
+	switch {
+	case n.durability.Mode == DurabilitySnapshot:
+		go n.persist(n.ctx, n.clock, n.durability.interval())
+	case n.wal != nil && n.wal.sync == WALSyncInterval:
+		go n.syncWAL(n.ctx, n.clock, n.wal, n.durability.interval())
+	}
+
+	log.Info(n.id, "Node started")
+	return nil
+}
+
+// Stop はノードを停止する
+// DurabilityWAL のノードはログのバッファを書き出してから閉じるため、停止までの書き込みを再起動で失わない
+func (n *Node) Stop() error {
+	return n.stop(true)
+}
+
+// Kill はノードを強制停止する（プロセスのクラッシュを再現する）
+// Stop と異なり DurabilityWAL のログのバッファを書き出さずに捨てるため、最後の同期より後の書き込みは再起動で失われる
+func (n *Node) Kill() error {
+	return n.stop(false)
+}
+
+// stop はノードを停止する。graceful が false の場合は WAL のバッファを捨てる
+func (n *Node) stop(graceful bool) error {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+
+	if n.Status() == StatusStopped {
+		return fmt.Errorf("node %s is already stopped", n.id)
+	}
+	if n.backend != nil {
+		if err := n.backend.Stop(); err != nil {
+			return fmt.Errorf("failed to stop backend of node %s: %w", n.id, err)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.cancel != nil {
+		n.cancel()
+	}
+	if n.wal != nil {
+		closeWAL := n.wal.close
+		if !graceful {
+			closeWAL = n.wal.abandon
+		}
+		if err := closeWAL(); err != nil {
+			log.Warn(n.id, "Failed to close WAL: %v", err)
+		}
+		n.wal = nil
+	}
+	n.status = StatusStopped
+
+	if graceful {
+		log.Info(n.id, "Node stopped")
+	} else {
+		log.Info(n.id, "Node killed")
+	}
+	return nil
+}
+
+// Status はノードの現在のステータスを返す
+func (n *Node) Status() Status {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.status
+}
+
+// Incarnations はノードを起動した回数を返す（再起動のたびに増える）
+func (n *Node) Incarnations() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.incarnations
+}
+
+// Stats は操作回数を返す
+func (n *Node) Stats() Stats {
+	// Get は gets を hits より先に数えるため、hits から読み込めば Misses は負にならない
+	hits := n.hits.Load()
+	gets := n.gets.Load()
+	return Stats{
+		Gets:      gets,
+		Hits:      hits,
+		Misses:    gets - hits,
+		Sets:      n.sets.Load(),
+		Deletes:   n.deletes.Load(),
+		Scans:     n.scans.Load(),
+		Rejected:  n.rejected.Load(),
+		Shed:      n.shed.Load(),
+		Expired:   n.expired.Load(),
+		Evicted:   n.evicted.Load(),
+		Lost:      n.lost.Load(),
+		Snapshots: n.snapshots.Load(),
+		Conflicts: n.conflicts.Load(),
+		Refused:   n.refused.Load(),
+		Corrupted: n.corrupted.Load(),
+	}
+}
+
+// Suspend はノードを一時停止する
+func (n *Node) Suspend() error {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+
+	if n.Status() != StatusRunning {
+		return fmt.Errorf("node %s is not running", n.id)
+	}
+	if n.backend != nil {
+		if err := n.backend.Suspend(); err != nil {
+			return fmt.Errorf("failed to suspend backend of node %s: %w", n.id, err)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.status = StatusSuspended
+	log.Info(n.id, "Node suspended")
+	return nil
+}
+
+// Resume は一時停止中のノードを再開する
+func (n *Node) Resume() error {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+
+	if n.Status() != StatusSuspended {
+		return fmt.Errorf("node %s is not suspended", n.id)
+	}
+	if n.backend != nil {
+		if err := n.backend.Resume(); err != nil {
+			return fmt.Errorf("failed to resume backend of node %s: %w", n.id, err)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.status = StatusRunning
+	log.Info(n.id, "Node resumed")
+	return nil
+}
+
+// SetDelay はレスポンス遅延を固定の d に設定する（0以下で解除）
+// 外部の実体が設定されている場合、その遅延の設定に失敗すると警告を出力して遅延を変更しない
+func (n *Node) SetDelay(d time.Duration) {
+	if err := n.SetDelayProfile(DelayProfile{Base: max(d, 0)}); err != nil {
+		log.Warn(n.id, "Failed to set delay: %v", err)
+	}
+}
+
+// Delay は現在の遅延設定の Base（固定の遅延・分布の中央・平均・最小）を返す
+func (n *Node) Delay() time.Duration {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.delay.Base
+}
+
+// SetLabel はノードにラベルを設定する（空の値で削除）
+func (n *Node) SetLabel(key, value string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if value == "" {
+		delete(n.labels, key)
+		return
+	}
+	if n.labels == nil {
+		n.labels = make(map[string]string)
+	}
+	n.labels[key] = value
+}
+
+// Label はラベルの値を返す（未設定の場合は空文字）
+func (n *Node) Label(key string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.labels[key]
+}
+
+// Labels は全てのラベルのコピーを返す
+func (n *Node) Labels() map[string]string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	labels := make(map[string]string, len(n.labels))
+	for k, v := range n.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// applyDelay は設定された分布から引いた遅延を、呼び出し元のゴルーチンで待つ（同期の操作で使う）
+// 待機は mu を保持せずにタイマーで行うため、遅延中の操作が他の操作のロックを妨げることはない
+// 遅延中に ctx がキャンセルされた場合はそのエラーを返す。呼び出し元を使わずに待つには afterDelay を使う
+func (n *Node) applyDelay(ctx context.Context) error {
+	d, c := n.sampleDelay()
+	if d <= 0 {
+		return nil
+	}
+	return clock.Sleep(ctx, c, d)
+}
+
+// sampleDelay は設定された分布から1回分の遅延を引き、待つのに使う時計とともに返す
+func (n *Node) sampleDelay() (time.Duration, clock.Clock) {
+	n.mu.RLock()
+	p, c := n.delay, n.clock
+	if n.delayBackend {
+		p = DelayProfile{}
+	}
+	n.mu.RUnlock()
+	return p.sample(), c
+}
+
+// Get はキーに対応する値を取得する
+func (n *Node) Get(key string) ([]byte, bool) {
+	value, exists, _ := n.GetContext(context.Background(), key)
+	return value, exists
+}
+
+// GetContext はキーに対応する値を取得する
+// 注入された遅延の途中で ctx がキャンセルされた場合はそのエラーを返す
+// Admission の上限に達している場合は ErrOverloaded を返す
+func (n *Node) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	value, _, exists, err := n.get(ctx, key)
+	return value, exists, err
+}
+
+// get はキーに対応する値と、複製していないバージョンを取得する
+func (n *Node) get(ctx context.Context, key string) ([]byte, Version, bool, error) {
+	if err := n.admit(false); err != nil {
+		return nil, nil, false, err
+	}
+	defer n.release()
+	if err := n.applyDelay(ctx); err != nil {
+		return nil, nil, false, err
+	}
+	value, version, exists := n.lookup(key)
+	return value, version, exists, nil
+}
+
+// lookup は遅延を待たずにキーに対応する値と、複製していないバージョンを取得する（稼働中でない場合は値なし）
+func (n *Node) lookup(key string) ([]byte, Version, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if !n.status.Available() {
+		n.rejected.Add(1)
+		return nil, nil, false
+	}
+
+	n.gets.Add(1)
+	counters := n.namespaces.of(key)
+	if counters != nil {
+		counters.gets.Add(1)
+	}
+	value, exists := n.data[key]
+	if exists && n.expiredAt(key, n.clock.Now()) {
+		value, exists = nil, false
+	}
+	if !exists {
+		return nil, nil, false
+	}
+	n.hits.Add(1)
+	if counters != nil {
+		counters.hits.Add(1)
+	}
+	n.touch(key)
+	return n.corrupt(value), n.versions[key], true
+}
+
+// Set はキーに値を設定する
+func (n *Node) Set(key string, value []byte) error {
+	return n.SetContext(context.Background(), key, value)
+}
+
+// SetContext はキーに値を設定する
+// 注入された遅延の途中で ctx がキャンセルされた場合はそのエラーを返す
+func (n *Node) SetContext(ctx context.Context, key string, value []byte) error {
+	return n.SetWithTTLContext(ctx, key, value, 0)
+}
+
+// SetWithTTL はキーに ttl 経過後に期限切れになる値を設定する（0以下で期限なし）
+func (n *Node) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	return n.SetWithTTLContext(context.Background(), key, value, ttl)
+}
+
+// SetWithTTLContext はキーに ttl 経過後に期限切れになる値を設定する（0以下で期限なし）
+// 期限切れのキーは Get から見えなくなり、SweepInterval ごとに削除される
+// 注入された遅延の途中で ctx がキャンセルされた場合はそのエラーを返す
+// Admission の上限に達している場合は ErrOverloaded を、Limits.MaxMemory を超える場合は ErrCapacity を返す
+func (n *Node) SetWithTTLContext(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := n.admit(true); err != nil {
+		return err
+	}
+	defer n.release()
+	if err := n.applyDelay(ctx); err != nil {
+		return err
+	}
+	return n.store(key, value, ttl)
+}
+
+// store は遅延を待たずにキーに ttl 経過後に期限切れになる値を設定する（0以下で期限なし）
+func (n *Node) store(key string, value []byte, ttl time.Duration) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.writable(1); err != nil {
+		return err
+	}
+
+	if err := n.checkSize(key, value); err != nil {
+		return err
+	}
+	if err := n.checkCapacity(map[string][]byte{key: value}); err != nil {
+		return err
+	}
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = n.clock.Now().Add(ttl)
+	}
+	if err := n.logWrites(setRecord(key, value, expiry)); err != nil {
+		return err
+	}
+	n.set(key, value, ttl)
+	n.evict()
+	return nil
+}
+
+// checkSize は1つのキーと値が MaxBytes を超えていないかを確かめる
+func (n *Node) checkSize(key string, value []byte) error {
+	if n.limits.MaxBytes > 0 && entrySize(key, value) > n.limits.MaxBytes {
+		return fmt.Errorf("value of key %s exceeds the limit of %d bytes on node %s", key, n.limits.MaxBytes, n.id)
+	}
+	return nil
+}
+
+// set はキーに値を書き込む（n.mu を書き込みロックした状態で呼び出し、上限を超えた分は呼び出し側で削除する）
+func (n *Node) set(key string, value []byte, ttl time.Duration) {
+	n.sets.Add(1)
+	if counters := n.namespaces.of(key); counters != nil {
+		counters.sets.Add(1)
+	}
+	if old, exists := n.data[key]; exists {
+		n.bytes -= entrySize(key, old)
+	}
+	n.data[key] = value
+	n.bytes += entrySize(key, value)
+	if n.versions == nil {
+		n.versions = make(map[string]Version)
+	}
+	n.versions[key] = n.versions[key].bump(n.id)
+	n.touch(key)
+	if n.crdt != nil {
+		n.crdt.write(n.id, key, value, n.versions[key], n.clock.Now().UnixNano())
+	}
+	if ttl <= 0 {
+		delete(n.expiry, key)
+	} else {
+		if n.expiry == nil {
+			n.expiry = make(map[string]time.Time)
+		}
+		n.expiry[key] = n.clock.Now().Add(ttl)
+		n.startSweeper()
+	}
+}
+
+// SetLimits は保持するデータの上限を設定する（ゼロ値で解除）
+// 設定時点で上限を超えている場合は直ちに削除する。設定前のキーの読み書きの順序は記録していないため任意の順に削除する
+func (n *Node) SetLimits(l Limits) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.limits = l
+
+	n.lruMu.Lock()
+	if !l.Enabled() {
+		n.lru, n.lruIndex = nil, nil
+	} else if n.lru == nil {
+		n.resetLRU()
+	}
+	n.lruMu.Unlock()
+
+	n.evict()
+}
+
+// Limits は保持するデータの上限を返す
+func (n *Node) Limits() Limits {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.limits
+}
+
+// Bytes は保持するキーと値の合計バイト数を返す（期限切れで未削除のキーを含む）
+func (n *Node) Bytes() int64 {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.bytes
+}
+
+// resetLRU は data の全てのキーで読み書きの順序を作り直す（mu と lruMu を保持して呼ぶ）
+func (n *Node) resetLRU() {
+	n.lru = list.New()
+	n.lruIndex = make(map[string]*list.Element, len(n.data))
+	for key := range n.data {
+		n.lruIndex[key] = n.lru.PushFront(key)
+	}
+}
+
+// touch はキーを最近読み書きしたものとして記録する（mu を保持して呼ぶ）
+func (n *Node) touch(key string) {
+	n.lruMu.Lock()
+	defer n.lruMu.Unlock()
+	if n.lru == nil {
+		return
+	}
+	if e, ok := n.lruIndex[key]; ok {
+		n.lru.MoveToFront(e)
+		return
+	}
+	n.lruIndex[key] = n.lru.PushFront(key)
+}
+
+// remove はキーをデータ・期限・読み書きの順序から削除する（mu を保持して呼ぶ）
+func (n *Node) remove(key string) {
+	value, exists := n.data[key]
+	if !exists {
+		return
+	}
+	delete(n.data, key)
+	delete(n.expiry, key)
+	delete(n.versions, key)
+	n.bytes -= entrySize(key, value)
+
+	n.lruMu.Lock()
+	defer n.lruMu.Unlock()
+	if e, ok := n.lruIndex[key]; ok {
+		n.lru.Remove(e)
+		delete(n.lruIndex, key)
+	}
+}
+
+// evict は上限を超えている間、最も長く読み書きされていないキーを削除する（mu を保持して呼ぶ）
+func (n *Node) evict() {
+	if !n.limits.Enabled() {
+		return
+	}
+	var evicted []string
+	for (n.limits.MaxKeys > 0 && len(n.data) > n.limits.MaxKeys) ||
+		(n.limits.MaxBytes > 0 && n.bytes > n.limits.MaxBytes) {
+		n.lruMu.Lock()
+		oldest := n.lru.Back()
+		n.lruMu.Unlock()
+		if oldest == nil {
+			break
+		}
+		key := oldest.Value.(string)
+		n.remove(key)
+		if n.crdt != nil {
+			n.crdt.forget(key)
+		}
+		evicted = append(evicted, key)
+	}
+	if len(evicted) > 0 {
+		n.logRemovals(evicted)
+		n.evicted.Add(uint64(len(evicted)))
+		log.Debug(n.id, "Evicted %d keys", len(evicted))
+	}
+}
+
+// TTL はキーが期限切れになるまでの残り時間を返す（キーがないか期限がない場合は false）
+func (n *Node) TTL(key string) (time.Duration, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	exp, ok := n.expiry[key]
+	if !ok {
+		return 0, false
+	}
+	remaining := exp.Sub(n.clock.Now())
+	if remaining <= 0 {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// expiredAt はキーが now の時点で期限切れかを返す（mu を保持して呼ぶ）
+func (n *Node) expiredAt(key string, now time.Time) bool {
+	exp, ok := n.expiry[key]
+	return ok && !now.Before(exp)
+}
+
+// startSweeper は稼働中の ctx で期限切れのキーを削除するゴルーチンを起動する（mu を保持して呼ぶ）
+// TTL を設定したキーがあるノードでのみ起動し、ノードの停止で終了する
+func (n *Node) startSweeper() {
+	if n.ctx == nil || n.sweeper == n.ctx {
+		return
+	}
+	n.sweeper = n.ctx
+	go n.sweep(n.ctx, n.clock)
+}
+
+// sweep は ctx がキャンセルされるまで SweepInterval ごとに期限切れのキーを削除する
+// 一時停止中は削除しない
+func (n *Node) sweep(ctx context.Context, c clock.Clock) {
+	ticker := c.NewTicker(SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+			if n.Status().Available() {
+				n.SweepExpired()
+			}
+		}
+	}
+}
+
+// SweepExpired は期限切れのキーを削除して削除した数を返す（状態にかかわらず削除する）
+func (n *Node) SweepExpired() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.clock.Now()
+	var expired []string
+	for key, exp := range n.expiry {
+		if now.Before(exp) {
+			continue
+		}
+		n.remove(key)
+		if n.crdt != nil {
+			n.crdt.forget(key)
+		}
+		expired = append(expired, key)
+	}
+	if len(expired) > 0 {
+		n.logRemovals(expired)
+		n.expired.Add(uint64(len(expired)))
+		log.Debug(n.id, "Expired %d keys", len(expired))
+	}
+	return len(expired)
+}
+
+// Delete はキーを削除する
+func (n *Node) Delete(key string) error {
+	return n.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext はキーを削除する
+// Get・Set と同じく注入された遅延を待ってから削除し、遅延の途中で ctx がキャンセルされた場合はそのエラーを返す
+// Admission の上限に達している場合は ErrOverloaded を返す
+func (n *Node) DeleteContext(ctx context.Context, key string) error {
+	if err := n.admit(true); err != nil {
+		return err
+	}
+	defer n.release()
+	if err := n.applyDelay(ctx); err != nil {
+		return err
+	}
+	return n.erase(key)
+}
+
+// erase は遅延を待たずにキーを削除する
+func (n *Node) erase(key string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.writable(1); err != nil {
+		return err
+	}
+
+	if err := n.logWrites(walRecord{Op: "delete", Key: key}); err != nil {
+		return err
+	}
+	n.deletes.Add(1)
+	if counters := n.namespaces.of(key); counters != nil {
+		counters.deletes.Add(1)
+	}
+	n.remove(key)
+	if n.crdt != nil {
+		n.crdt.remove(key)
+	}
+	return nil
+}
+
+// Keys は期限切れでない全てのキーを返す
+func (n *Node) Keys() []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	now := n.clock.Now()
+	keys := make([]string, 0, len(n.data))
+	for k := range n.data {
+		if !n.expiredAt(k, now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Export は期限切れでない全データのコピーを返す（状態にかかわらず取得できる）
+// TTL は含まないため、Import した先では期限のない値になる
+func (n *Node) Export() map[string][]byte {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	now := n.clock.Now()
+	data := make(map[string][]byte, len(n.data))
+	for k, v := range n.data {
+		if !n.expiredAt(k, now) {
+			data[k] = append([]byte(nil), v...)
+		}
+	}
+	return data
+}
+
+// Import はデータを data のコピーで置き換える（状態にかかわらず置き換え、TTL は解除する）
+// 上限を超える場合は任意の順に削除する。StoreCRDT の場合は置き換えた時刻にこのノードで書き込んだものとして扱う
+// DurabilityWAL のノードでは先行書き込みログを置き換えたデータで詰め直す
+func (n *Node) Import(data map[string][]byte) {
+	copied := make(map[string][]byte, len(data))
+	for k, v := range data {
+		copied[k] = append([]byte(nil), v...)
+	}
+
+	count := len(copied)
+	n.mu.Lock()
+	n.replace(copied)
+	n.mu.Unlock()
+
+	log.Info(n.id, "Imported %d keys", count)
+}
+
+// replace はデータを data で置き換え、TTL を解除して先行書き込みログを詰め直す（mu を書き込みロックした状態で呼び出す）
+func (n *Node) replace(data map[string][]byte) {
+	var bytes int64
+	for k, v := range data {
+		bytes += entrySize(k, v)
+	}
+	n.replaceVersions(data)
+	n.data = data
+	n.bytes = bytes
+	n.expiry = nil
+	n.lruMu.Lock()
+	if n.lru != nil {
+		n.resetLRU()
+	}
+	n.lruMu.Unlock()
+	if n.crdt != nil {
+		n.crdt = newCRDTStore(n.id, n.data, n.versions, n.clock.Now().UnixNano())
+	}
+	n.evict()
+	n.rewriteWAL()
+}
+
+// Size は期限切れでないキーの数を返す
+func (n *Node) Size() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	now := n.clock.Now()
+	size := len(n.data)
+	for k := range n.expiry {
+		if n.expiredAt(k, now) {
+			size--
+		}
+	}
+	return size
+}
@@ -0,0 +1,803 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+func TestNewNode(t *testing.T) {
+	n := New("test-node-1")
+
+	if n.ID() != "test-node-1" {
+		t.Errorf("expected ID 'test-node-1', got '%s'", n.ID())
+	}
+
+	if n.Status() != StatusStopped {
+		t.Errorf("expected status Stopped, got %v", n.Status())
+	}
+}
+
+func TestNodeStartStop(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+
+	// Start
+	if err := n.Start(ctx); err != nil {
+		t.Errorf("failed to start node: %v", err)
+	}
+
+	if n.Status() != StatusRunning {
+		t.Errorf("expected status Running, got %v", n.Status())
+	}
+
+	// Double start should fail
+	if err := n.Start(ctx); err == nil {
+		t.Error("expected error when starting already running node")
+	}
+
+	// Stop
+	if err := n.Stop(); err != nil {
+		t.Errorf("failed to stop node: %v", err)
+	}
+
+	if n.Status() != StatusStopped {
+		t.Errorf("expected status Stopped, got %v", n.Status())
+	}
+
+	// Double stop should fail
+	if err := n.Stop(); err == nil {
+		t.Error("expected error when stopping already stopped node")
+	}
+}
+
+func TestNodeGetSet(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+
+	// Set before start should fail
+	if err := n.Set("key1", []byte("value1")); err == nil {
+		t.Error("expected error when setting on stopped node")
+	}
+
+	// Get before start should return false
+	if _, ok := n.Get("key1"); ok {
+		t.Error("expected Get to return false on stopped node")
+	}
+
+	// Start node
+	_ = n.Start(ctx)
+
+	// Set
+	if err := n.Set("key1", []byte("value1")); err != nil {
+		t.Errorf("failed to set: %v", err)
+	}
+
+	// Get
+	value, ok := n.Get("key1")
+	if !ok {
+		t.Error("expected Get to return true")
+	}
+	if string(value) != "value1" {
+		t.Errorf("expected 'value1', got '%s'", string(value))
+	}
+
+	// Get non-existent key
+	if _, ok := n.Get("nonexistent"); ok {
+		t.Error("expected Get to return false for non-existent key")
+	}
+}
+
+func TestNodeDelete(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+	_ = n.Start(ctx)
+
+	_ = n.Set("key1", []byte("value1"))
+
+	if err := n.Delete("key1"); err != nil {
+		t.Errorf("failed to delete: %v", err)
+	}
+
+	if _, ok := n.Get("key1"); ok {
+		t.Error("expected key to be deleted")
+	}
+}
+
+func TestNodeKeys(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+	_ = n.Start(ctx)
+
+	_ = n.Set("key1", []byte("value1"))
+	_ = n.Set("key2", []byte("value2"))
+	_ = n.Set("key3", []byte("value3"))
+
+	keys := n.Keys()
+	if len(keys) != 3 {
+		t.Errorf("expected 3 keys, got %d", len(keys))
+	}
+}
+
+func TestNodeSize(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+	_ = n.Start(ctx)
+
+	if n.Size() != 0 {
+		t.Errorf("expected size 0, got %d", n.Size())
+	}
+
+	_ = n.Set("key1", []byte("value1"))
+	_ = n.Set("key2", []byte("value2"))
+
+	if n.Size() != 2 {
+		t.Errorf("expected size 2, got %d", n.Size())
+	}
+}
+
+func TestNodeConcurrentAccess(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+	_ = n.Start(ctx)
+
+	var wg sync.WaitGroup
+	numGoroutines := 100
+
+	// Concurrent writes
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a' + i%26))
+			_ = n.Set(key, []byte("value"))
+		}(i)
+	}
+
+	// Concurrent reads
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a' + i%26))
+			n.Get(key)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestNodeSuspendResume(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+
+	// Suspend before start should fail
+	if err := n.Suspend(); err == nil {
+		t.Error("expected error when suspending stopped node")
+	}
+
+	// Start node
+	_ = n.Start(ctx)
+
+	// Suspend
+	if err := n.Suspend(); err != nil {
+		t.Errorf("failed to suspend node: %v", err)
+	}
+
+	if n.Status() != StatusSuspended {
+		t.Errorf("expected status Suspended, got %v", n.Status())
+	}
+
+	// 一時停止中のノードは起動し直さない（Resume で再開する）
+	if err := n.Start(ctx); err == nil {
+		t.Error("expected error when starting suspended node")
+	}
+
+	// Double suspend should fail
+	if err := n.Suspend(); err == nil {
+		t.Error("expected error when suspending already suspended node")
+	}
+
+	// Operations on suspended node should fail/return empty
+	if _, ok := n.Get("key1"); ok {
+		t.Error("expected Get to return false on suspended node")
+	}
+	if err := n.Set("key1", []byte("value1")); err == nil {
+		t.Error("expected error when setting on suspended node")
+	}
+
+	// Resume
+	if err := n.Resume(); err != nil {
+		t.Errorf("failed to resume node: %v", err)
+	}
+
+	if n.Status() != StatusRunning {
+		t.Errorf("expected status Running after resume, got %v", n.Status())
+	}
+
+	// Double resume should fail
+	if err := n.Resume(); err == nil {
+		t.Error("expected error when resuming non-suspended node")
+	}
+
+	// Operations should work again
+	if err := n.Set("key1", []byte("value1")); err != nil {
+		t.Errorf("expected Set to succeed after resume: %v", err)
+	}
+}
+
+func TestNodeDelay(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+	_ = n.Start(ctx)
+
+	// Initially no delay
+	if n.Delay() != 0 {
+		t.Errorf("expected no delay initially, got %v", n.Delay())
+	}
+
+	// Set delay
+	delay := 50 * time.Millisecond
+	n.SetDelay(delay)
+
+	if n.Delay() != delay {
+		t.Errorf("expected delay %v, got %v", delay, n.Delay())
+	}
+
+	// Verify delay is applied
+	_ = n.Set("key1", []byte("value1"))
+
+	start := time.Now()
+	n.Get("key1")
+	elapsed := time.Since(start)
+
+	if elapsed < delay {
+		t.Errorf("expected delay of at least %v, got %v", delay, elapsed)
+	}
+
+	// Clear delay
+	n.SetDelay(0)
+	if n.Delay() != 0 {
+		t.Error("expected delay to be cleared")
+	}
+}
+
+func TestNodeDelaySimulatedClock(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	n.SetDelay(time.Hour)
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := n.GetContext(context.Background(), "key1")
+		done <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the delay to wait on the clock: %v", err)
+	}
+	select {
+	case <-done:
+		t.Fatal("expected the request to wait for the virtual delay")
+	default:
+	}
+
+	clk.Advance(time.Hour)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNodeDeleteDelay(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("key1", []byte("value1"))
+	n.SetDelay(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- n.DeleteContext(ctx, "key1") }()
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	if err := clk.BlockUntil(waitCtx, 1); err != nil {
+		t.Fatalf("expected the delete to wait on the clock: %v", err)
+	}
+
+	// 遅延中の削除はロックを保持しない
+	if n.Size() != 1 {
+		t.Errorf("expected the key to remain while the delete waits, got size %d", n.Size())
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n.Size() != 1 {
+		t.Error("expected a canceled delete not to remove the key")
+	}
+
+	go func() { done <- n.DeleteContext(context.Background(), "key1") }()
+	if err := clk.BlockUntil(waitCtx, 1); err != nil {
+		t.Fatalf("expected the delete to wait on the clock: %v", err)
+	}
+	clk.Advance(time.Hour)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if n.Size() != 0 {
+		t.Error("expected the key to be deleted after the delay")
+	}
+}
+
+func TestNodeTTL(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.SetWithTTL("short", []byte("a"), time.Second)
+	_ = n.SetWithTTL("long", []byte("b"), time.Minute)
+	_ = n.SetWithTTL("reset", []byte("c"), time.Second)
+	_ = n.Set("reset", []byte("d"))
+	_ = n.SetWithTTL("permanent", []byte("e"), 0)
+
+	if ttl, ok := n.TTL("short"); !ok || ttl != time.Second {
+		t.Errorf("expected a TTL of 1s, got %v (%v)", ttl, ok)
+	}
+	if _, ok := n.TTL("reset"); ok {
+		t.Error("expected Set to clear the TTL")
+	}
+	if _, ok := n.TTL("permanent"); ok {
+		t.Error("expected no TTL for a zero duration")
+	}
+
+	clk.Advance(time.Second)
+	if _, ok := n.Get("short"); ok {
+		t.Error("expected the expired key to be hidden")
+	}
+	if _, ok := n.TTL("short"); ok {
+		t.Error("expected no TTL for the expired key")
+	}
+	if n.Size() != 3 || len(n.Keys()) != 3 || len(n.Export()) != 3 {
+		t.Errorf("expected 3 live keys, got size %d, keys %v", n.Size(), n.Keys())
+	}
+	if stats := n.Stats(); stats.Hits != 0 || stats.Expired != 0 {
+		t.Errorf("expected no hits and no removed keys yet, got %+v", stats)
+	}
+
+	if removed := n.SweepExpired(); removed != 1 {
+		t.Errorf("expected 1 key to be removed, got %d", removed)
+	}
+	if n.Stats().Expired != 1 {
+		t.Errorf("expected 1 expired key, got %d", n.Stats().Expired)
+	}
+	if value, ok := n.Get("long"); !ok || string(value) != "b" {
+		t.Errorf("expected the long-lived key to remain, got %q (%v)", value, ok)
+	}
+}
+
+func TestNodeTTLSweeper(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	_ = n.Start(context.Background())
+
+	if clk.Waiters() != 0 {
+		t.Fatal("expected no sweeper before a key with a TTL is written")
+	}
+	_ = n.SetWithTTL("key1", []byte("value1"), time.Second)
+	_ = n.SetWithTTL("key2", []byte("value2"), time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the sweeper to wait on the clock: %v", err)
+	}
+	clk.Advance(SweepInterval)
+	for n.Stats().Expired != 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected the sweeper to remove both keys, got %+v", n.Stats())
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if n.Size() != 0 {
+		t.Errorf("expected an empty store, got %d keys", n.Size())
+	}
+
+	// 停止でスイーパーは終了し、再起動で TTL のキーが残っていれば再開する
+	_ = n.SetWithTTL("key3", []byte("value3"), time.Second)
+	_ = n.Stop()
+	for clk.Waiters() != 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected the sweeper to stop with the node")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("expected the sweeper to restart: %v", err)
+	}
+}
+
+func TestNodeLimitsMaxKeys(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	n.SetLimits(Limits{MaxKeys: 3})
+
+	_ = n.Set("a", []byte("1"))
+	_ = n.Set("b", []byte("2"))
+	_ = n.Set("c", []byte("3"))
+	n.Get("a") // b が最も長く使われていないキーになる
+	_ = n.Set("d", []byte("4"))
+
+	if _, ok := n.Get("b"); ok {
+		t.Error("expected the least recently used key to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := n.Get(key); !ok {
+			t.Errorf("expected %s to remain", key)
+		}
+	}
+	if n.Size() != 3 || n.Stats().Evicted != 1 {
+		t.Errorf("expected 3 keys and 1 eviction, got %d keys and %+v", n.Size(), n.Stats())
+	}
+
+	// 上書きはキー数を増やさない
+	_ = n.Set("a", []byte("5"))
+	if n.Stats().Evicted != 1 {
+		t.Errorf("expected no eviction on overwrite, got %d", n.Stats().Evicted)
+	}
+}
+
+func TestNodeLimitsMaxBytes(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	n.SetLimits(Limits{MaxBytes: 20})
+
+	_ = n.Set("k1", make([]byte, 8))
+	_ = n.Set("k2", make([]byte, 8))
+	if n.Bytes() != 20 || n.Stats().Evicted != 0 {
+		t.Fatalf("expected 20 bytes without eviction, got %d bytes and %+v", n.Bytes(), n.Stats())
+	}
+	_ = n.Set("k3", make([]byte, 2))
+	if _, ok := n.Get("k1"); ok || n.Bytes() != 14 {
+		t.Errorf("expected k1 to be evicted, got %d bytes", n.Bytes())
+	}
+
+	if err := n.Set("big", make([]byte, 20)); err == nil {
+		t.Error("expected a value larger than the limit to be rejected")
+	}
+	if n.Size() != 2 {
+		t.Errorf("expected the rejected value to leave the data unchanged, got %v", n.Keys())
+	}
+
+	_ = n.Delete("k2")
+	if n.Bytes() != 4 {
+		t.Errorf("expected delete to release the bytes, got %d", n.Bytes())
+	}
+}
+
+func TestNodeSetLimits(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	for i := range 10 {
+		_ = n.Set(fmt.Sprintf("key-%d", i), []byte("value"))
+	}
+	n.SetLimits(Limits{MaxKeys: 4})
+	if n.Size() != 4 || n.Stats().Evicted != 6 {
+		t.Errorf("expected existing keys to be evicted down to the limit, got %d keys", n.Size())
+	}
+	if n.Limits().MaxKeys != 4 {
+		t.Errorf("unexpected limits: %+v", n.Limits())
+	}
+
+	n.Import(map[string][]byte{"a": nil, "b": nil, "c": nil, "d": nil, "e": nil})
+	if n.Size() != 4 || n.Bytes() != 4 {
+		t.Errorf("expected imported data to be evicted down to the limit, got %d keys and %d bytes", n.Size(), n.Bytes())
+	}
+
+	n.SetLimits(Limits{})
+	for i := range 10 {
+		_ = n.Set(fmt.Sprintf("key-%d", i), []byte("value"))
+	}
+	if n.Size() != 14 {
+		t.Errorf("expected no limit after clearing, got %d keys", n.Size())
+	}
+}
+
+func TestNodeLimitsConcurrentAccess(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	n.SetLimits(Limits{MaxKeys: 50, MaxBytes: 1000})
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				key := fmt.Sprintf("key-%d", (w*500+i)%200)
+				if i%3 == 0 {
+					_ = n.Delete(key)
+				} else if i%2 == 0 {
+					_ = n.Set(key, []byte("value"))
+				} else {
+					n.Get(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n.Size() > 50 || n.Bytes() > 1000 {
+		t.Errorf("expected the limits to hold, got %d keys and %d bytes", n.Size(), n.Bytes())
+	}
+	var bytes int64
+	for k, v := range n.Export() {
+		bytes += int64(len(k) + len(v))
+	}
+	if bytes != n.Bytes() {
+		t.Errorf("expected %d bytes to be tracked, got %d", bytes, n.Bytes())
+	}
+}
+
+func TestNodeLabels(t *testing.T) {
+	n := New("test-node")
+
+	if n.Label(LabelZone) != "" {
+		t.Error("expected no zone label initially")
+	}
+
+	n.SetLabel(LabelZone, "zone-a")
+	n.SetLabel("rack", "r1")
+	if n.Label(LabelZone) != "zone-a" {
+		t.Errorf("expected zone-a, got %s", n.Label(LabelZone))
+	}
+
+	labels := n.Labels()
+	if len(labels) != 2 {
+		t.Errorf("expected 2 labels, got %d", len(labels))
+	}
+	// 返されたマップの変更はノードに影響しない
+	labels["rack"] = "r2"
+	if n.Label("rack") != "r1" {
+		t.Error("expected Labels to return a copy")
+	}
+
+	n.SetLabel("rack", "")
+	if _, ok := n.Labels()["rack"]; ok {
+		t.Error("expected label to be removed")
+	}
+}
+
+func TestNodeStats(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+
+	// 停止中の操作は拒否として数える
+	_ = n.Set("key", []byte("value"))
+	if stats := n.Stats(); stats.Rejected != 1 || stats.Sets != 0 {
+		t.Errorf("expected 1 rejected operation, got %+v", stats)
+	}
+
+	if err := n.Start(ctx); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	_ = n.Set("key", []byte("value"))
+	n.Get("key")
+	n.Get("missing")
+	_ = n.Delete("key")
+
+	want := Stats{Gets: 2, Hits: 1, Misses: 1, Sets: 1, Deletes: 1, Rejected: 1}
+	stats := n.Stats()
+	if stats != want {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+	if stats.Ops() != 4 || stats.HitRate() != 0.5 {
+		t.Errorf("expected 4 operations and a hit rate of 0.5, got %d and %v", stats.Ops(), stats.HitRate())
+	}
+	if (Stats{}).HitRate() != 0 {
+		t.Error("expected no hit rate without gets")
+	}
+}
+
+func TestNodeIncarnations(t *testing.T) {
+	n := New("test-node-1")
+	ctx := context.Background()
+
+	if n.Incarnations() != 0 {
+		t.Errorf("expected 0 incarnations before start, got %d", n.Incarnations())
+	}
+	for i := 0; i < 3; i++ {
+		if err := n.Start(ctx); err != nil {
+			t.Fatalf("failed to start node: %v", err)
+		}
+		if err := n.Stop(); err != nil {
+			t.Fatalf("failed to stop node: %v", err)
+		}
+	}
+	if n.Incarnations() != 3 {
+		t.Errorf("expected 3 incarnations, got %d", n.Incarnations())
+	}
+}
+
+func TestNodeExportImport(t *testing.T) {
+	src := New("test-node-1")
+	if err := src.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	_ = src.Set("a", []byte("1"))
+	_ = src.Set("b", []byte("2"))
+
+	data := src.Export()
+	data["a"][0] = 'x' // エクスポートはコピー
+	if v, _ := src.Get("a"); string(v) != "1" {
+		t.Errorf("expected export to be a copy, got %s", v)
+	}
+
+	// 停止中のノードにも取り込める
+	dst := New("test-node-2")
+	dst.Import(src.Export())
+	if dst.Size() != 2 {
+		t.Errorf("expected 2 keys, got %d", dst.Size())
+	}
+	if err := dst.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	if v, ok := dst.Get("b"); !ok || string(v) != "2" {
+		t.Errorf("expected imported value 2, got %s", v)
+	}
+}
+
+func TestNodeContextOps(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	n.SetDelay(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := n.SetContext(ctx, "key1", []byte("value1")); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if _, _, err := n.GetContext(ctx, "key1"); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected cancelled delay to return early, took %v", elapsed)
+	}
+	if n.Size() != 0 {
+		t.Error("expected cancelled set to leave the node unchanged")
+	}
+
+	n.SetDelay(0)
+	if err := n.SetContext(context.Background(), "key1", []byte("value1")); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if v, ok, err := n.GetContext(context.Background(), "key1"); err != nil || !ok || string(v) != "value1" {
+		t.Errorf("unexpected get result: %q, %v, %v", v, ok, err)
+	}
+}
+
+// fakeBackend は呼び出された操作を記録し、fail に含まれる操作を失敗させる Backend
+type fakeBackend struct {
+	calls []string
+	fail  map[string]bool
+}
+
+func (b *fakeBackend) do(op string) error {
+	b.calls = append(b.calls, op)
+	if b.fail[op] {
+		return errors.New(op + " failed")
+	}
+	return nil
+}
+
+func (b *fakeBackend) Start(context.Context) error { return b.do("start") }
+func (b *fakeBackend) Stop() error                 { return b.do("stop") }
+func (b *fakeBackend) Suspend() error              { return b.do("suspend") }
+func (b *fakeBackend) Resume() error               { return b.do("resume") }
+func (b *fakeBackend) SetDelay(d time.Duration) error {
+	return b.do("delay=" + d.String())
+}
+
+func TestNodeBackend(t *testing.T) {
+	n := New("node-1")
+	b := &fakeBackend{fail: map[string]bool{}}
+	n.SetBackend(b)
+	if n.Backend() != b {
+		t.Fatal("expected backend to be set")
+	}
+
+	ctx := context.Background()
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	n.SetDelay(time.Second)
+	// 遅延は外部の実体が注入するため、ノード自身の呼び出しには遅延をかけない
+	start := time.Now()
+	n.Get("key")
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected no in-process delay with a backend, took %v", elapsed)
+	}
+	if n.Delay() != time.Second {
+		t.Errorf("expected delay to be reported, got %v", n.Delay())
+	}
+	if err := n.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	// 状態が変わらない操作は外部の実体を呼び出さない
+	if err := n.Stop(); err == nil {
+		t.Error("expected error when stopping a stopped node")
+	}
+
+	want := "start suspend resume delay=1s stop"
+	if got := strings.Join(b.calls, " "); got != want {
+		t.Errorf("expected calls %q, got %q", want, got)
+	}
+}
+
+func TestNodeBackendFailure(t *testing.T) {
+	n := New("node-1")
+	b := &fakeBackend{fail: map[string]bool{"start": true}}
+	n.SetBackend(b)
+
+	if err := n.Start(context.Background()); err == nil {
+		t.Fatal("expected start to fail")
+	}
+	if n.Status() != StatusStopped || n.Incarnations() != 0 {
+		t.Errorf("expected node to stay stopped, got %s", n.Status())
+	}
+
+	b.fail = map[string]bool{"stop": true, "delay=1s": true}
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Stop(); err == nil {
+		t.Error("expected stop to fail")
+	}
+	if n.Status() != StatusRunning {
+		t.Errorf("expected node to stay running, got %s", n.Status())
+	}
+	n.SetDelay(time.Second)
+	if n.Delay() != 0 {
+		t.Errorf("expected delay to stay unset, got %v", n.Delay())
+	}
+
+	n.SetBackend(nil)
+	if err := n.Stop(); err != nil {
+		t.Errorf("expected stop without backend to succeed: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package node
+
+import (
+	"sync"
+	"time"
+)
+
+// minRateWindow は OpsPerSecond が操作数を数え直す最小の区間
+const minRateWindow = time.Second
+
+// opsRate は OpsPerSecond の前回の計測
+type opsRate struct {
+	mu    sync.Mutex
+	at    time.Time // 前回の計測の時刻（ゼロ値でまだ計測していない）
+	ops   uint64    // 前回の計測の時点の Stats().Ops()
+	value float64   // 直近の区間の秒間の操作数
+}
+
+// OpsPerSecond は直近の秒間の操作数を返す
+// 前回の計測から minRateWindow 以上経過していれば、その区間の操作数から計算し直す
+// 経過していない場合は前回の値を返すため、数秒おきに呼び出すとその間隔の秒間の操作数になる
+// 最初の呼び出しは計測を始めるだけで0を返す
+func (n *Node) OpsPerSecond() float64 {
+	n.mu.RLock()
+	now := n.clock.Now()
+	n.mu.RUnlock()
+	ops := n.Stats().Ops()
+
+	r := &n.rate
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.at.IsZero() {
+		r.at, r.ops = now, ops
+		return 0
+	}
+	if elapsed := now.Sub(r.at); elapsed >= minRateWindow {
+		r.value = float64(ops-r.ops) / elapsed.Seconds()
+		r.at, r.ops = now, ops
+	}
+	return r.value
+}
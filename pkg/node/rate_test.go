@@ -0,0 +1,40 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+func TestNodeOpsPerSecond(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	if rate := n.OpsPerSecond(); rate != 0 {
+		t.Errorf("expected the first call to start measuring, got %v", rate)
+	}
+	for i := 0; i < 10; i++ {
+		_ = n.Set("key", []byte("value"))
+	}
+
+	// 区間が短い間は前回の値を返す
+	clk.Advance(500 * time.Millisecond)
+	if rate := n.OpsPerSecond(); rate != 0 {
+		t.Errorf("expected the previous rate within the window, got %v", rate)
+	}
+	clk.Advance(1500 * time.Millisecond)
+	if rate := n.OpsPerSecond(); rate != 5 {
+		t.Errorf("expected 10 operations over 2s to be 5 ops/s, got %v", rate)
+	}
+
+	n.Get("key")
+	clk.Advance(time.Second)
+	if rate := n.OpsPerSecond(); rate != 1 {
+		t.Errorf("expected the rate of the latest window only, got %v", rate)
+	}
+}
@@ -0,0 +1,52 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly は読み取り専用のノードが書き込みを受け付けなかったことを表す
+var ErrReadOnly = errors.New("node is read-only")
+
+// SetReadOnly は稼働中のノードを読み取り専用にする（false で稼働中に戻す）
+// 読み取り専用の間は Get・MGet・Scan を受け付け、Set・MSet・Delete を ErrReadOnly で失敗させる
+// 外部の実体（Backend）には読み取り専用の操作がないため、Backend を設定したノードでは失敗する
+func (n *Node) SetReadOnly(readOnly bool) error {
+	n.opMu.Lock()
+	defer n.opMu.Unlock()
+
+	from, to := StatusRunning, StatusReadOnly
+	if !readOnly {
+		from, to = to, from
+	}
+	if status := n.Status(); status != from {
+		return fmt.Errorf("node %s is %s, not %s", n.id, status, from)
+	}
+	if n.backend != nil {
+		return fmt.Errorf("backend of node %s does not support the %s state", n.id, StatusReadOnly)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.status = to
+	if readOnly {
+		log.Info(n.id, "Node is now read-only")
+	} else {
+		log.Info(n.id, "Node is writable again")
+	}
+	return nil
+}
+
+// writable は書き込みを受け付けられるかを確かめ、受け付けない場合は count 件の拒否として数える（mu を保持して呼ぶ）
+func (n *Node) writable(count int) error {
+	switch n.status {
+	case StatusRunning:
+		return nil
+	case StatusReadOnly:
+		n.rejected.Add(uint64(count))
+		return fmt.Errorf("%w: %s", ErrReadOnly, n.id)
+	default:
+		n.rejected.Add(uint64(count))
+		return fmt.Errorf("node %s is not running", n.id)
+	}
+}
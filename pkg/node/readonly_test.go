@@ -0,0 +1,69 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNodeSetReadOnly(t *testing.T) {
+	n := New("test-node-1")
+	if err := n.SetReadOnly(true); err == nil {
+		t.Error("expected a stopped node not to become read-only")
+	}
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("key", []byte("value"))
+	if err := n.SetReadOnly(true); err != nil {
+		t.Fatalf("failed to make the node read-only: %v", err)
+	}
+	if n.Status() != StatusReadOnly || !n.Status().Available() || n.Status().String() != "readonly" {
+		t.Errorf("expected the read-only status, got %s", n.Status())
+	}
+
+	// 読み取りは受け付け、書き込みは ErrReadOnly で失敗させる
+	if value, ok := n.Get("key"); !ok || string(value) != "value" {
+		t.Errorf("expected reads to succeed, got %q (%v)", value, ok)
+	}
+	if len(n.MGet([]string{"key"})) != 1 || len(n.Scan("k", 0)) != 1 {
+		t.Error("expected batch reads and scans to succeed")
+	}
+	for name, err := range map[string]error{
+		"Set":    n.Set("key", []byte("other")),
+		"MSet":   n.MSet(map[string][]byte{"a": nil, "b": nil}),
+		"Delete": n.Delete("key"),
+	} {
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: expected ErrReadOnly, got %v", name, err)
+		}
+	}
+	if stats := n.Stats(); stats.Rejected != 4 || stats.Sets != 1 {
+		t.Errorf("expected 4 rejected writes, got %+v", stats)
+	}
+	if err := n.Suspend(); err == nil {
+		t.Error("expected a read-only node not to be suspended")
+	}
+	if err := n.SetReadOnly(true); err == nil {
+		t.Error("expected making a read-only node read-only again to fail")
+	}
+
+	if err := n.SetReadOnly(false); err != nil {
+		t.Fatalf("failed to make the node writable: %v", err)
+	}
+	if err := n.Set("key", []byte("other")); err != nil || n.Status() != StatusRunning {
+		t.Errorf("expected writes to succeed again, got %v (%s)", err, n.Status())
+	}
+}
+
+func TestNodeReadOnlyStop(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	_ = n.SetReadOnly(true)
+	if err := n.Start(context.Background()); err == nil {
+		t.Error("expected starting a read-only node to fail")
+	}
+	if err := n.Stop(); err != nil || n.Status() != StatusStopped {
+		t.Errorf("expected a read-only node to stop, got %v (%s)", err, n.Status())
+	}
+}
@@ -0,0 +1,55 @@
+package node
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Entry は Scan が返すキーと値
+type Entry struct {
+	Key   string
+	Value []byte
+}
+
+// Scan は prefix で始まるキーと値をキーの昇順に最大 limit 件返す（limit が0以下で全件）
+func (n *Node) Scan(prefix string, limit int) []Entry {
+	entries, _ := n.ScanContext(context.Background(), prefix, limit)
+	return entries
+}
+
+// ScanContext は prefix で始まるキーと値をキーの昇順に最大 limit 件返す（limit が0以下で全件）
+// 全てのキーを読み取りロックを保持したまま走査するため、キー数に比例して書き込みを待たせる
+// 読み書きの順序（LRU）は更新しない。稼働中でない場合は Get と同じく空を返す
+// 遅延の途中で ctx がキャンセルされた場合、Admission の上限に達している場合はそのエラーを返す
+func (n *Node) ScanContext(ctx context.Context, prefix string, limit int) ([]Entry, error) {
+	if err := n.admit(false); err != nil {
+		return nil, err
+	}
+	defer n.release()
+	if err := n.applyDelay(ctx); err != nil {
+		return nil, err
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if !n.status.Available() {
+		n.rejected.Add(1)
+		return nil, nil
+	}
+
+	n.scans.Add(1)
+	now := n.clock.Now()
+	var entries []Entry
+	for key, value := range n.data {
+		if strings.HasPrefix(key, prefix) && !n.expiredAt(key, now) {
+			entries = append(entries, Entry{Key: key, Value: value})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
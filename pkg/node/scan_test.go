@@ -0,0 +1,71 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNodeScan(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	for _, key := range []string{"user:3", "user:1", "order:1", "user:2", "user:10"} {
+		_ = n.Set(key, []byte("v-"+key))
+	}
+	_ = n.SetWithTTL("user:0", []byte("expired"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	entries := n.Scan("user:", 0)
+	var keys []string
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	if want := []string{"user:1", "user:10", "user:2", "user:3"}; len(keys) != len(want) {
+		t.Fatalf("expected %v, got %v", want, keys)
+	} else {
+		for i := range want {
+			if keys[i] != want[i] {
+				t.Errorf("expected %v in key order, got %v", want, keys)
+				break
+			}
+		}
+	}
+	if string(entries[0].Value) != "v-user:1" {
+		t.Errorf("expected the value of user:1, got %q", entries[0].Value)
+	}
+
+	if entries := n.Scan("user:", 2); len(entries) != 2 || entries[1].Key != "user:10" {
+		t.Errorf("expected the first 2 keys, got %+v", entries)
+	}
+	if entries := n.Scan("missing:", 10); len(entries) != 0 {
+		t.Errorf("expected no keys, got %+v", entries)
+	}
+	if n.Stats().Scans != 3 {
+		t.Errorf("expected 3 scans, got %d", n.Stats().Scans)
+	}
+}
+
+func TestNodeScanNotRunning(t *testing.T) {
+	n := New("test-node-1")
+	if entries := n.Scan("", 0); entries != nil {
+		t.Errorf("expected no entries from a stopped node, got %+v", entries)
+	}
+	if n.Stats().Rejected != 1 || n.Stats().Scans != 0 {
+		t.Errorf("expected the scan to be rejected, got %+v", n.Stats())
+	}
+}
+
+func TestNodeScanDelay(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+	n.SetDelay(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := n.ScanContext(ctx, "", 0); err == nil {
+		t.Error("expected a canceled context to abort the delayed scan")
+	}
+}
@@ -0,0 +1,120 @@
+package node
+
+import (
+	"bytes"
+	"context"
+)
+
+// Version はキーの値のバージョン（書き込んだノードごとの書き込み回数のベクタークロック）
+// 同じキーの2つのバージョンを Compare で比べ、レプリカの食い違いや並行した書き込みを見分ける
+type Version map[string]uint64
+
+// Ordering は2つのバージョンの前後関係
+type Ordering int
+
+const (
+	VersionEqual      Ordering = iota // 同じ書き込みを観測している
+	VersionBefore                     // 相手の書き込みの一部を観測していない
+	VersionAfter                      // 相手の全ての書き込みと、それより後の書き込みを観測している
+	VersionConcurrent                 // 互いに相手の観測していない書き込みがある
+)
+
+// String は前後関係の名前を返す
+func (o Ordering) String() string {
+	switch o {
+	case VersionEqual:
+		return "equal"
+	case VersionBefore:
+		return "before"
+	case VersionAfter:
+		return "after"
+	default:
+		return "concurrent"
+	}
+}
+
+// Compare は v を o と比べた前後関係を返す
+func (v Version) Compare(o Version) Ordering {
+	var before, after bool
+	for id, count := range v {
+		if count > o[id] {
+			after = true
+		}
+	}
+	for id, count := range o {
+		if count > v[id] {
+			before = true
+		}
+	}
+	switch {
+	case before && after:
+		return VersionConcurrent
+	case before:
+		return VersionBefore
+	case after:
+		return VersionAfter
+	default:
+		return VersionEqual
+	}
+}
+
+// clone はバージョンの複製を返す（nil は nil のまま）
+func (v Version) clone() Version {
+	if v == nil {
+		return nil
+	}
+	c := make(Version, len(v))
+	for id, count := range v {
+		c[id] = count
+	}
+	return c
+}
+
+// bump は nodeID の書き込みを1回加えた新しいバージョンを返す（v は変更しない）
+func (v Version) bump(nodeID string) Version {
+	c := v.clone()
+	if c == nil {
+		c = make(Version, 1)
+	}
+	c[nodeID]++
+	return c
+}
+
+// merge は v と o の両方の書き込みを観測した新しいバージョンを返す（v は変更しない）
+func (v Version) merge(o Version) Version {
+	c := v.clone()
+	for id, count := range o {
+		if c == nil {
+			c = make(Version, len(o))
+		}
+		c[id] = max(c[id], count)
+	}
+	return c
+}
+
+// GetWithVersion はキーに対応する値とそのバージョンを取得する
+func (n *Node) GetWithVersion(key string) ([]byte, Version, bool) {
+	value, version, exists, _ := n.GetWithVersionContext(context.Background(), key)
+	return value, version, exists
+}
+
+// GetWithVersionContext はキーに対応する値とそのバージョンの複製を取得する
+// 稼働中でない場合・遅延と Admission の扱いは GetContext と同じ
+func (n *Node) GetWithVersionContext(ctx context.Context, key string) ([]byte, Version, bool, error) {
+	value, version, exists, err := n.get(ctx, key)
+	return value, version.clone(), exists, err
+}
+
+// replaceVersions は data で置き換える前に、値の変わらないキーのバージョンを引き継ぐ（mu を保持して呼ぶ）
+// 値の変わるキーと新しいキーは、このノードで書き込んだものとしてバージョンを進める
+func (n *Node) replaceVersions(data map[string][]byte) {
+	versions := make(map[string]Version, len(data))
+	for key, value := range data {
+		version := n.versions[key]
+		if old, ok := n.data[key]; !ok || version == nil || !bytes.Equal(old, value) {
+			version = version.bump(n.id)
+		}
+		versions[key] = version
+	}
+	n.versions = versions
+}
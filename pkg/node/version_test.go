@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		v, o Version
+		want Ordering
+	}{
+		{nil, nil, VersionEqual},
+		{Version{"a": 1}, Version{"a": 1}, VersionEqual},
+		{Version{"a": 1}, Version{"a": 2}, VersionBefore},
+		{Version{"a": 2, "b": 1}, Version{"a": 2}, VersionAfter},
+		{nil, Version{"a": 1}, VersionBefore},
+		{Version{"a": 2}, Version{"a": 1, "b": 1}, VersionConcurrent},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Compare(tt.o); got != tt.want {
+			t.Errorf("%v.Compare(%v) = %s, want %s", tt.v, tt.o, got, tt.want)
+		}
+	}
+}
+
+func TestNodeGetWithVersion(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	if _, version, ok := n.GetWithVersion("key"); ok || version != nil {
+		t.Errorf("expected no version for a missing key, got %v", version)
+	}
+
+	_ = n.Set("key", []byte("v1"))
+	_, v1, _ := n.GetWithVersion("key")
+	_ = n.MSet(map[string][]byte{"key": []byte("v2")})
+	value, v2, ok := n.GetWithVersion("key")
+	if !ok || string(value) != "v2" {
+		t.Fatalf("expected the latest value, got %q (%v)", value, ok)
+	}
+	if v1["test-node-1"] != 1 || v2["test-node-1"] != 2 || v1.Compare(v2) != VersionBefore {
+		t.Errorf("expected each write to advance the version, got %v then %v", v1, v2)
+	}
+
+	// 返したバージョンを変更しても保持しているバージョンは変わらない
+	v2["test-node-1"] = 10
+	if _, version, _ := n.GetWithVersion("key"); version["test-node-1"] != 2 {
+		t.Errorf("expected the stored version to be unaffected, got %v", version)
+	}
+
+	// 削除したキーのバージョンは捨てる
+	_ = n.Delete("key")
+	_ = n.Set("key", []byte("v3"))
+	if _, version, _ := n.GetWithVersion("key"); version["test-node-1"] != 1 {
+		t.Errorf("expected the version to restart after a delete, got %v", version)
+	}
+}
+
+func TestNodeImportKeepsVersions(t *testing.T) {
+	n := New("test-node-1")
+	_ = n.Start(context.Background())
+	defer func() { _ = n.Stop() }()
+
+	_ = n.Set("same", []byte("v"))
+	_ = n.Set("changed", []byte("v"))
+	n.Import(map[string][]byte{"same": []byte("v"), "changed": []byte("w"), "new": []byte("x")})
+
+	for key, want := range map[string]uint64{"same": 1, "changed": 2, "new": 1} {
+		if _, version, _ := n.GetWithVersion(key); version["test-node-1"] != want {
+			t.Errorf("%s: expected version %d, got %v", key, want, version)
+		}
+	}
+}
+
+func TestNodeCRDTMergesVersions(t *testing.T) {
+	a, _ := newCRDTNode(t, "node-1", time.Unix(1, 0))
+	b, _ := newCRDTNode(t, "node-2", time.Unix(2, 0))
+
+	_ = a.Set("key", []byte("from-a"))
+	_ = b.Set("key", []byte("from-b"))
+	_, va, _ := a.GetWithVersion("key")
+	_, vb, _ := b.GetWithVersion("key")
+	if va.Compare(vb) != VersionConcurrent {
+		t.Fatalf("expected concurrent versions before the merge, got %s", va.Compare(vb))
+	}
+
+	syncNodes(t, a, b)
+	_, va, _ = a.GetWithVersion("key")
+	_, vb, _ = b.GetWithVersion("key")
+	if va.Compare(vb) != VersionEqual || va["node-1"] != 1 || va["node-2"] != 1 {
+		t.Errorf("expected both nodes to observe both writes, got %v and %v", va, vb)
+	}
+
+	_ = b.Set("key", []byte("again-b"))
+	_, vb, _ = b.GetWithVersion("key")
+	if va.Compare(vb) != VersionBefore {
+		t.Errorf("expected the write after the merge to be newer, got %s", va.Compare(vb))
+	}
+}
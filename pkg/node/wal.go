@@ -0,0 +1,267 @@
+package node
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+// WALSync は DurabilityWAL のノードが先行書き込みログをファイルに書き出して fsync する頻度
+type WALSync string
+
+const (
+	WALSyncInterval WALSync = "interval" // Durability.Interval ごとにまとめて書き出す（既定、最後の同期より後の書き込みを強制停止で失う）
+	WALSyncAlways   WALSync = "always"   // 書き込みごとに書き出してから応答する（強制停止でも失わない）
+	WALSyncNone     WALSync = "none"     // バッファが一杯になったときだけ書き出し、fsync しない
+)
+
+// ParseWALSync は文字列の fsync の頻度をパースする（空で WALSyncInterval）
+func ParseWALSync(s string) (WALSync, error) {
+	switch policy := WALSync(strings.ToLower(s)); policy {
+	case "":
+		return WALSyncInterval, nil
+	case WALSyncInterval, WALSyncAlways, WALSyncNone:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown WAL sync policy: %s", s)
+	}
+}
+
+// walRecord は先行書き込みログの1行（JSON）
+type walRecord struct {
+	Op     string `json:"op"` // set, delete
+	Key    string `json:"key"`
+	Value  []byte `json:"value,omitempty"`
+	Expiry int64  `json:"expiry,omitempty"` // set の期限（ノードの時計の UnixNano、0で期限なし）
+}
+
+// setRecord は key に value を期限 expiry で書き込むレコードを返す（expiry がゼロ値で期限なし）
+func setRecord(key string, value []byte, expiry time.Time) walRecord {
+	r := walRecord{Op: "set", Key: key, Value: value}
+	if !expiry.IsZero() {
+		r.Expiry = expiry.UnixNano()
+	}
+	return r
+}
+
+// walLog は追記専用の先行書き込みログ
+// 書き出していないバッファはノードの停止（kill）で失われる
+type walLog struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	w    *bufio.Writer
+	enc  *json.Encoder
+	sync WALSync
+
+	closed bool
+}
+
+// openWAL は data を期限 expiry で書き込んだ状態に先行書き込みログを詰め直し、追記用に開く
+func openWAL(path string, policy WALSync, data map[string][]byte, expiry map[string]time.Time) (*walLog, error) {
+	l := &walLog{path: path, sync: policy}
+	if err := l.rewrite(data, expiry); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// rewrite はログを data を期限 expiry で書き込んだ状態に詰め直し、以降の追記先にする
+// 詰め直したログは一時ファイルに書いてから置き換えるため、途中で失敗しても元のログは壊れない
+// 元のログの書き出していないバッファは詰め直したログに含まれるため捨てる
+func (l *walLog) rewrite(data map[string][]byte, expiry map[string]time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return os.ErrClosed
+	}
+
+	tmp := l.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for key, value := range data {
+		if err := enc.Encode(setRecord(key, value, expiry[key])); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := errors.Join(w.Flush(), f.Sync(), f.Close()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return err
+	}
+
+	if f, err = os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+		return err
+	}
+	if l.f != nil {
+		_ = l.f.Close()
+	}
+	l.f, l.w = f, bufio.NewWriter(f)
+	l.enc = json.NewEncoder(l.w)
+	return nil
+}
+
+// append はレコードをログに追記する（WALSyncAlways の場合は fsync まで行う）
+func (l *walLog) append(records ...walRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return os.ErrClosed
+	}
+	for _, r := range records {
+		if err := l.enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	if l.sync == WALSyncAlways {
+		return l.flushLocked()
+	}
+	return nil
+}
+
+// flush はバッファをファイルに書き出して fsync する
+func (l *walLog) flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.flushLocked()
+}
+
+func (l *walLog) flushLocked() error {
+	if l.closed {
+		return nil
+	}
+	if err := l.w.Flush(); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+// close はバッファをファイルに書き出してから閉じる（正常な停止、WALSyncNone 以外は fsync も行う）
+func (l *walLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	err := l.w.Flush()
+	if err == nil && l.sync != WALSyncNone {
+		err = l.f.Sync()
+	}
+	l.closed = true
+	return errors.Join(err, l.f.Close())
+}
+
+// abandon はバッファを書き出さずにファイルを閉じる（プロセスのクラッシュを再現する）
+func (l *walLog) abandon() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.f.Close()
+}
+
+// replayWAL は先行書き込みログを先頭から適用したデータと、期限のあるキーの期限を返す（ファイルがない場合は nil）
+// 書き込みの途中で停止した最後の行は読み飛ばす
+func replayWAL(path string) (map[string][]byte, map[string]time.Time, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	data := make(map[string][]byte)
+	expiry := make(map[string]time.Time)
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var r walRecord
+		if err := dec.Decode(&r); err != nil {
+			break
+		}
+		switch r.Op {
+		case "set":
+			data[r.Key] = r.Value
+			if r.Expiry != 0 {
+				expiry[r.Key] = time.Unix(0, r.Expiry)
+			} else {
+				delete(expiry, r.Key)
+			}
+		case "delete":
+			delete(data, r.Key)
+			delete(expiry, r.Key)
+		}
+	}
+	return data, expiry, nil
+}
+
+// syncWAL は ctx がキャンセルされるまで一定間隔で先行書き込みログを書き出す
+func (n *Node) syncWAL(ctx context.Context, c clock.Clock, l *walLog, interval time.Duration) {
+	ticker := c.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+			if err := l.flush(); err != nil {
+				log.Warn(n.id, "Failed to sync WAL: %v", err)
+			}
+		}
+	}
+}
+
+// logWrites は DurabilityWAL のノードで書き込みをログに記録する（mu を書き込みロックした状態で呼び出す）
+func (n *Node) logWrites(records ...walRecord) error {
+	if n.wal == nil {
+		return nil
+	}
+	if err := n.wal.append(records...); err != nil {
+		return fmt.Errorf("failed to write WAL of node %s: %w", n.id, err)
+	}
+	return nil
+}
+
+// logRemovals は期限切れ・上限でキーを削除したことをログに記録する（mu を書き込みロックした状態で呼び出す）
+// 書き込みの応答ではない削除のため、記録に失敗しても削除は取り消さずに警告する
+func (n *Node) logRemovals(keys []string) {
+	if n.wal == nil || len(keys) == 0 {
+		return
+	}
+	records := make([]walRecord, len(keys))
+	for i, key := range keys {
+		records[i] = walRecord{Op: "delete", Key: key}
+	}
+	if err := n.logWrites(records...); err != nil {
+		log.Warn(n.id, "Failed to log %d removed keys: %v", len(keys), err)
+	}
+}
+
+// rewriteWAL は DurabilityWAL のノードでログを現在のデータで詰め直す（mu を書き込みロックした状態で呼び出す）
+// Import のようにデータをまとめて置き換えた後に呼び、置き換える前のキーを再生で戻さない
+func (n *Node) rewriteWAL() {
+	if n.wal == nil {
+		return
+	}
+	if err := n.wal.rewrite(n.data, n.expiry); err != nil {
+		log.Warn(n.id, "Failed to rewrite WAL: %v", err)
+	}
+}
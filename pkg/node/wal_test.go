@@ -0,0 +1,220 @@
+package node
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+)
+
+// newWALNode は先行書き込みログを記録するノードを仮想時計で起動する
+func newWALNode(t *testing.T, path string, sync WALSync) (*Node, *clock.Simulated) {
+	t.Helper()
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	n := New("test-node-1")
+	n.SetClock(clk)
+	n.SetDurability(Durability{Mode: DurabilityWAL, Path: path, Interval: time.Second, Sync: sync})
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	t.Cleanup(func() { _ = n.Stop() })
+	return n, clk
+}
+
+func TestParseWALSync(t *testing.T) {
+	for s, want := range map[string]WALSync{"": WALSyncInterval, "always": WALSyncAlways, "NONE": WALSyncNone} {
+		if got, err := ParseWALSync(s); err != nil || got != want {
+			t.Errorf("ParseWALSync(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if err := (Durability{Mode: DurabilityWAL, Sync: "sometimes"}).Validate(); err == nil {
+		t.Error("expected an unknown sync policy to be invalid")
+	}
+}
+
+func TestNodeWALSyncAlways(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.wal")
+	n, _ := newWALNode(t, path, WALSyncAlways)
+
+	_ = n.Set("a", []byte("1"))
+	_ = n.MSet(map[string][]byte{"b": []byte("2"), "c": []byte("3")})
+	_ = n.Delete("c")
+	restart(t, n)
+
+	if n.Size() != 2 || n.Stats().Lost != 0 {
+		t.Errorf("expected every acknowledged write to survive, got %d keys and %d lost", n.Size(), n.Stats().Lost)
+	}
+	if value, _ := n.Get("b"); string(value) != "2" {
+		t.Errorf("expected the replayed value, got %q", value)
+	}
+
+	// 再生後のログは詰め直され、以降の書き込みも追記される
+	_ = n.Set("d", []byte("4"))
+	restart(t, n)
+	if n.Size() != 3 {
+		t.Errorf("expected 3 keys after the second restart, got %d", n.Size())
+	}
+}
+
+func TestNodeWALSyncInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.wal")
+	n, clk := newWALNode(t, path, WALSyncInterval)
+
+	_ = n.Set("synced", []byte("1"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("WAL sync did not start: %v", err)
+	}
+	clk.Advance(time.Second)
+	for {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 同期より後の書き込みは強制停止で失われる
+	_ = n.Set("unsynced", []byte("2"))
+	crash(t, n)
+	if _, ok := n.Get("synced"); !ok {
+		t.Error("expected the synced write to survive")
+	}
+	if _, ok := n.Get("unsynced"); ok {
+		t.Error("expected the write after the last sync to be lost")
+	}
+	if n.Stats().Lost != 1 {
+		t.Errorf("expected 1 lost key, got %d", n.Stats().Lost)
+	}
+}
+
+func TestNodeWALStopKeepsBuffered(t *testing.T) {
+	for _, sync := range []WALSync{WALSyncInterval, WALSyncNone} {
+		t.Run(string(sync), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "node.wal")
+			n, _ := newWALNode(t, path, sync)
+
+			// 同期の前に停止しても、正常な停止ではバッファを書き出すため失わない
+			_ = n.Set("a", []byte("1"))
+			_ = n.Set("b", []byte("2"))
+			_ = n.Set("last", []byte("3"))
+			_ = n.Delete("a")
+			_ = n.SetWithTTL("expiring", []byte("4"), time.Hour)
+			restart(t, n)
+
+			if value, ok := n.Get("last"); !ok || string(value) != "3" {
+				t.Errorf("expected the last write to survive a stop, got %q, %v", value, ok)
+			}
+			if _, ok := n.Get("a"); ok {
+				t.Error("expected the delete before the stop to survive")
+			}
+			if ttl, ok := n.TTL("expiring"); !ok || ttl <= 0 {
+				t.Errorf("expected the TTL to survive a stop, got %v, %v", ttl, ok)
+			}
+			if n.Size() != 3 || n.Stats().Lost != 0 {
+				t.Errorf("expected 3 keys and nothing lost, got %d keys and %d lost", n.Size(), n.Stats().Lost)
+			}
+
+			// 強制停止ではバッファのレコードを失う
+			_ = n.Set("killed", []byte("5"))
+			crash(t, n)
+			if _, ok := n.Get("killed"); ok {
+				t.Error("expected the buffered write to be lost on kill")
+			}
+			if value, _ := n.Get("last"); string(value) != "3" {
+				t.Errorf("expected the compacted log to keep earlier writes, got %q", value)
+			}
+		})
+	}
+}
+
+func TestReplayWALTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.wal")
+	content := `{"op":"set","key":"a","value":"MQ=="}` + "\n" + `{"op":"set","key":"b","val`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := replayWAL(path)
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if len(data) != 1 || string(data["a"]) != "1" {
+		t.Errorf("expected the complete record only, got %v", data)
+	}
+	if data, _, err := replayWAL(filepath.Join(t.TempDir(), "missing.wal")); data != nil || err != nil {
+		t.Errorf("expected no data for a missing log, got %v, %v", data, err)
+	}
+}
+
+func TestNodeWALRestartTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.wal")
+	n, clk := newWALNode(t, path, WALSyncAlways)
+
+	_ = n.Set("plain", []byte("v"))
+	_ = n.SetWithTTL("swept", []byte("v"), 10*time.Second)
+	_ = n.SetWithTTL("late", []byte("v"), time.Minute)
+	restart(t, n)
+
+	// 期限は再生後も残る
+	if ttl, ok := n.TTL("late"); !ok || ttl != time.Minute {
+		t.Errorf("expected the TTL to survive the restart, got %v (%v)", ttl, ok)
+	}
+
+	// 期限切れで削除したキーは再生で戻らない
+	clk.Advance(11 * time.Second)
+	n.SweepExpired()
+	restart(t, n)
+	if _, ok := n.Get("swept"); ok {
+		t.Error("expected the swept key not to come back")
+	}
+
+	// 停止している間に期限を過ぎたキーも読み込まない
+	_ = n.Stop()
+	clk.Advance(time.Minute)
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("failed to restart node: %v", err)
+	}
+	if _, ok := n.Get("late"); ok || n.Size() != 1 {
+		t.Errorf("expected only the key without a TTL, got %d keys", n.Size())
+	}
+}
+
+func TestNodeWALRestartEviction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.wal")
+	n, _ := newWALNode(t, path, WALSyncAlways)
+
+	n.SetLimits(Limits{MaxKeys: 1})
+	_ = n.Set("a", []byte("1"))
+	_ = n.Set("b", []byte("2"))
+	n.SetLimits(Limits{})
+	restart(t, n)
+
+	if _, ok := n.Get("a"); ok || n.Size() != 1 {
+		t.Errorf("expected the evicted key not to come back, got %d keys", n.Size())
+	}
+}
+
+func TestNodeWALRestartImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.wal")
+	n, _ := newWALNode(t, path, WALSyncAlways)
+
+	_ = n.Set("a", []byte("1"))
+	n.Import(map[string][]byte{"x": []byte("imported")})
+	restart(t, n)
+	if _, ok := n.Get("a"); ok {
+		t.Error("expected the key replaced by Import not to come back")
+	}
+	if value, _ := n.Get("x"); string(value) != "imported" {
+		t.Errorf("expected the imported value, got %q", value)
+	}
+
+	// 詰め直したログにも以降の書き込みを追記する
+	_ = n.Set("y", []byte("2"))
+	restart(t, n)
+	if n.Size() != 2 {
+		t.Errorf("expected 2 keys, got %d", n.Size())
+	}
+}
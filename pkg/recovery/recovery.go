@@ -6,12 +6,16 @@ import (
 	"sync/atomic"
 	"time"
 
-	"chaos-kvs/internal/cluster"
-	"chaos-kvs/internal/events"
-	"chaos-kvs/internal/logger"
-	"chaos-kvs/internal/node"
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
 )
 
+// log はコンポーネント名 "recovery" を付けてログを出力する子ロガー
+var log = logger.With("recovery")
+
 // Config はRecoveryManagerの設定
 type Config struct {
 	HealthCheckInterval time.Duration // ヘルスチェック間隔
@@ -20,6 +24,7 @@ type Config struct {
 	AutoRestart         bool          // 停止ノードの自動再起動
 	AutoResume          bool          // 一時停止ノードの自動再開
 	ClearDelay          bool          // 遅延設定のクリア
+	Clock               clock.Clock   // ヘルスチェックと復旧待ちの時間を測る時計（nilで実時間、SetConfig では変更できない）
 }
 
 // DefaultConfig はデフォルト設定を返す
@@ -55,6 +60,7 @@ type Manager struct {
 	config   Config
 	cluster  *cluster.Cluster
 	eventBus *events.Bus
+	clock    clock.Clock // New で決め、SetConfig では変更しない
 
 	running atomic.Bool
 	ctx     context.Context
@@ -68,7 +74,12 @@ type Manager struct {
 
 // New は新しいRecoveryManagerを作成する
 func New(c *cluster.Cluster, config Config) *Manager {
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.Real()
+	}
 	return &Manager{
+		clock:      clk,
 		config:     config,
 		cluster:    c,
 		nodeStates: make(map[string]*NodeState),
@@ -98,7 +109,7 @@ func (m *Manager) Start(ctx context.Context) {
 	m.wg.Add(1)
 	go m.healthCheckLoop()
 
-	logger.Info("", "RecoveryManager started (interval: %v, delay: %v)",
+	log.Info("", "RecoveryManager started (interval: %v, delay: %v)",
 		m.config.HealthCheckInterval, m.config.RecoveryDelay)
 }
 
@@ -115,7 +126,7 @@ func (m *Manager) Stop() {
 	stats := m.stats
 	m.mu.RUnlock()
 
-	logger.Info("", "RecoveryManager stopped (recoveries: %d success, %d failed)",
+	log.Info("", "RecoveryManager stopped (recoveries: %d success, %d failed)",
 		stats.SuccessRecoveries, stats.FailedRecoveries)
 }
 
@@ -123,14 +134,14 @@ func (m *Manager) Stop() {
 func (m *Manager) healthCheckLoop() {
 	defer m.wg.Done()
 
-	ticker := time.NewTicker(m.config.HealthCheckInterval)
+	ticker := m.clock.NewTicker(m.config.HealthCheckInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-m.ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.Chan():
 			m.checkAndRecover()
 		}
 	}
@@ -139,7 +150,7 @@ func (m *Manager) healthCheckLoop() {
 // checkAndRecover は全ノードをチェックし、必要に応じて復旧する
 func (m *Manager) checkAndRecover() {
 	nodes := m.cluster.Nodes()
-	now := time.Now()
+	now := m.clock.Now()
 
 	for _, n := range nodes {
 		m.checkNode(n, now)
@@ -164,7 +175,7 @@ func (m *Manager) checkNode(n *node.Node, now time.Time) {
 		m.handleRunningNode(n, state, now)
 	case node.StatusStopped:
 		m.handleStoppedNode(n, state, now)
-	case node.StatusSuspended:
+	case node.StatusSuspended, node.StatusReadOnly:
 		m.handleSuspendedNode(n, state, now)
 	}
 }
@@ -176,7 +187,7 @@ func (m *Manager) handleRunningNode(n *node.Node, state *NodeState, now time.Tim
 	// 遅延クリア
 	if m.config.ClearDelay && n.Delay() > 0 {
 		n.SetDelay(0)
-		logger.Info("", "RecoveryManager: cleared delay on node %s", n.ID())
+		log.Info("", "RecoveryManager: cleared delay on node %s", n.ID())
 	}
 
 	// 復旧完了を記録
@@ -185,7 +196,7 @@ func (m *Manager) handleRunningNode(n *node.Node, state *NodeState, now time.Tim
 		state.IsRecovered = true
 		m.stats.SuccessRecoveries++
 		shouldPublish = true
-		logger.Info("", "RecoveryManager: node %s recovered successfully", n.ID())
+		log.Event(logger.LevelInfo, n.ID(), string(events.EventRecoverySuccess), "RecoveryManager: node %s recovered successfully", n.ID())
 	}
 
 	state.LastSeen = now
@@ -211,7 +222,7 @@ func (m *Manager) handleStoppedNode(n *node.Node, state *NodeState, now time.Tim
 		state.FailedAt = now
 		m.stats.CurrentlyFailed++
 		m.mu.Unlock()
-		logger.Warn("", "RecoveryManager: detected stopped node %s", n.ID())
+		log.Warn("", "RecoveryManager: detected stopped node %s", n.ID())
 		return
 	}
 
@@ -240,7 +251,7 @@ func (m *Manager) handleStoppedNode(n *node.Node, state *NodeState, now time.Tim
 		m.mu.Lock()
 		m.stats.FailedRecoveries++
 		m.mu.Unlock()
-		logger.Error("", "RecoveryManager: failed to restart node %s: %v", n.ID(), err)
+		log.Event(logger.LevelError, n.ID(), string(events.EventRecoveryFailed), "RecoveryManager: failed to restart node %s: %v", n.ID(), err)
 		m.publishEvent(events.NewRecoveryFailedEvent(n.ID(), err))
 		return
 	}
@@ -250,10 +261,10 @@ func (m *Manager) handleStoppedNode(n *node.Node, state *NodeState, now time.Tim
 	state.FailedAt = time.Time{}
 	m.mu.Unlock()
 
-	logger.Info("", "RecoveryManager: restarted node %s (attempt %d)", n.ID(), retryCount)
+	log.Info("", "RecoveryManager: restarted node %s (attempt %d)", n.ID(), retryCount)
 }
 
-// handleSuspendedNode は一時停止中のノードを処理する
+// handleSuspendedNode は一時停止中・読み取り専用のノードを処理する
 func (m *Manager) handleSuspendedNode(n *node.Node, state *NodeState, now time.Time) {
 	if !m.config.AutoResume {
 		return
@@ -265,7 +276,7 @@ func (m *Manager) handleSuspendedNode(n *node.Node, state *NodeState, now time.T
 	if state.FailedAt.IsZero() {
 		state.FailedAt = now
 		m.mu.Unlock()
-		logger.Warn("", "RecoveryManager: detected suspended node %s", n.ID())
+		log.Warn("", "RecoveryManager: detected %s node %s", n.Status(), n.ID())
 		return
 	}
 
@@ -283,12 +294,16 @@ func (m *Manager) handleSuspendedNode(n *node.Node, state *NodeState, now time.T
 
 	m.publishEvent(events.NewRecoveryStartEvent(n.ID(), retryCount))
 
-	// 再開を試みる
-	if err := n.Resume(); err != nil {
+	// 再開を試みる（読み取り専用は書き込みを再び受け付ける）
+	resume := n.Resume
+	if n.Status() == node.StatusReadOnly {
+		resume = func() error { return n.SetReadOnly(false) }
+	}
+	if err := resume(); err != nil {
 		m.mu.Lock()
 		m.stats.FailedRecoveries++
 		m.mu.Unlock()
-		logger.Error("", "RecoveryManager: failed to resume node %s: %v", n.ID(), err)
+		log.Event(logger.LevelError, n.ID(), string(events.EventRecoveryFailed), "RecoveryManager: failed to resume node %s: %v", n.ID(), err)
 		m.publishEvent(events.NewRecoveryFailedEvent(n.ID(), err))
 		return
 	}
@@ -297,7 +312,7 @@ func (m *Manager) handleSuspendedNode(n *node.Node, state *NodeState, now time.T
 	m.stats.SuccessRecoveries++
 	m.mu.Unlock()
 
-	logger.Info("", "RecoveryManager: resumed node %s", n.ID())
+	log.Event(logger.LevelInfo, n.ID(), string(events.EventRecoverySuccess), "RecoveryManager: resumed node %s", n.ID())
 	m.publishEvent(events.NewRecoverySuccessEvent(n.ID()))
 }
 
@@ -2,11 +2,14 @@ package recovery
 
 import (
 	"context"
+	"errors"
+	"runtime"
 	"testing"
 	"time"
 
-	"chaos-kvs/internal/cluster"
-	"chaos-kvs/internal/node"
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -71,6 +74,33 @@ func TestManagerStartStop(t *testing.T) {
 	}
 }
 
+// failingBackend は起動に失敗する backend
+type failingBackend struct{}
+
+func (failingBackend) Start(context.Context) error  { return errors.New("start failed") }
+func (failingBackend) Stop() error                  { return nil }
+func (failingBackend) Suspend() error               { return nil }
+func (failingBackend) Resume() error                { return nil }
+func (failingBackend) SetDelay(time.Duration) error { return nil }
+
+// newSimulatedManager は仮想時計で動く、Start していないマネージャーを作成する
+// テストは healthChecks でヘルスチェックを1回ずつ進めるため、実時間を待たない
+func newSimulatedManager(c *cluster.Cluster, config Config) (*Manager, *clock.Simulated) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	config.Clock = clk
+	m := New(c, config)
+	m.ctx = context.Background()
+	return m, clk
+}
+
+// healthChecks は仮想時計をヘルスチェック間隔ずつ進めながら count 回チェックする
+func healthChecks(m *Manager, clk *clock.Simulated, count int) {
+	for range count {
+		clk.Advance(m.config.HealthCheckInterval)
+		m.checkAndRecover()
+	}
+}
+
 func TestManagerAutoRestart(t *testing.T) {
 	c := cluster.New()
 	_ = c.CreateNodes(1, "node")
@@ -82,11 +112,7 @@ func TestManagerAutoRestart(t *testing.T) {
 	config.RecoveryDelay = 100 * time.Millisecond
 	config.AutoRestart = true
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	// ノードを停止
 	nodes := c.Nodes()
@@ -95,13 +121,13 @@ func TestManagerAutoRestart(t *testing.T) {
 	}
 	_ = nodes[0].Stop()
 
+	// 検出してから RecoveryDelay が経つまでは再起動しない
+	healthChecks(manager, clk, 2)
 	if nodes[0].Status() != node.StatusStopped {
-		t.Error("expected node to be stopped")
+		t.Errorf("expected node to stay stopped before the recovery delay, got %v", nodes[0].Status())
 	}
 
-	// 自動復旧を待つ
-	time.Sleep(300 * time.Millisecond)
-
+	healthChecks(manager, clk, 1)
 	if nodes[0].Status() != node.StatusRunning {
 		t.Errorf("expected node to be running after recovery, got %v", nodes[0].Status())
 	}
@@ -118,11 +144,7 @@ func TestManagerAutoResume(t *testing.T) {
 	config.RecoveryDelay = 100 * time.Millisecond
 	config.AutoResume = true
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	// ノードをsuspend
 	nodes := c.Nodes()
@@ -135,8 +157,7 @@ func TestManagerAutoResume(t *testing.T) {
 		t.Error("expected node to be suspended")
 	}
 
-	// 自動復旧を待つ
-	time.Sleep(300 * time.Millisecond)
+	healthChecks(manager, clk, 3)
 
 	if nodes[0].Status() != node.StatusRunning {
 		t.Errorf("expected node to be running after recovery, got %v", nodes[0].Status())
@@ -153,11 +174,7 @@ func TestManagerClearDelay(t *testing.T) {
 	config.HealthCheckInterval = 50 * time.Millisecond
 	config.ClearDelay = true
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	// ノードに遅延を設定
 	nodes := c.Nodes()
@@ -170,8 +187,7 @@ func TestManagerClearDelay(t *testing.T) {
 		t.Error("expected delay to be set")
 	}
 
-	// 遅延クリアを待つ
-	time.Sleep(150 * time.Millisecond)
+	healthChecks(manager, clk, 1)
 
 	if nodes[0].Delay() != 0 {
 		t.Error("expected delay to be cleared")
@@ -190,26 +206,25 @@ func TestManagerMaxRetries(t *testing.T) {
 	config.MaxRetries = 2
 	config.AutoRestart = true
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	nodes := c.Nodes()
 	if len(nodes) == 0 {
 		t.Fatal("expected at least one node")
 	}
 
-	// ノードを停止（再起動されても再度停止される場合をシミュレート）
+	// 再起動に失敗し続けるノードをシミュレートするため、停止後に起動できない backend を設定する
 	_ = nodes[0].Stop()
+	nodes[0].SetBackend(failingBackend{})
 
-	// 複数回のリトライを待つ
-	time.Sleep(400 * time.Millisecond)
+	healthChecks(manager, clk, 20)
 
 	stats := manager.Stats()
-	if stats.TotalRecoveries == 0 {
-		t.Error("expected at least one recovery attempt")
+	if stats.TotalRecoveries != 2 {
+		t.Errorf("expected recovery attempts to stop at 2, got %d", stats.TotalRecoveries)
+	}
+	if stats.FailedRecoveries != 2 {
+		t.Errorf("expected 2 failed recoveries, got %d", stats.FailedRecoveries)
 	}
 }
 
@@ -223,22 +238,17 @@ func TestManagerStats(t *testing.T) {
 	config.HealthCheckInterval = 50 * time.Millisecond
 	config.RecoveryDelay = 100 * time.Millisecond
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	// ノードをsuspend
 	nodes := c.Nodes()
 	_ = nodes[0].Suspend()
 
-	// 復旧を待つ
-	time.Sleep(300 * time.Millisecond)
+	healthChecks(manager, clk, 3)
 
 	stats := manager.Stats()
-	if stats.TotalRecoveries == 0 {
-		t.Error("expected total recoveries > 0")
+	if stats.TotalRecoveries != 1 || stats.SuccessRecoveries != 1 {
+		t.Errorf("expected one successful recovery, got %+v", stats)
 	}
 }
 
@@ -290,18 +300,14 @@ func TestManagerDisabledAutoRestart(t *testing.T) {
 	config.RecoveryDelay = 50 * time.Millisecond
 	config.AutoRestart = false
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	// ノードを停止
 	nodes := c.Nodes()
 	_ = nodes[0].Stop()
 
 	// 待機しても復旧しないはず
-	time.Sleep(200 * time.Millisecond)
+	healthChecks(manager, clk, 4)
 
 	if nodes[0].Status() != node.StatusStopped {
 		t.Error("expected node to remain stopped when AutoRestart is disabled")
@@ -319,20 +325,48 @@ func TestManagerDisabledAutoResume(t *testing.T) {
 	config.RecoveryDelay = 50 * time.Millisecond
 	config.AutoResume = false
 
-	manager := New(c, config)
-
-	ctx := context.Background()
-	manager.Start(ctx)
-	defer manager.Stop()
+	manager, clk := newSimulatedManager(c, config)
 
 	// ノードをsuspend
 	nodes := c.Nodes()
 	_ = nodes[0].Suspend()
 
 	// 待機しても復旧しないはず
-	time.Sleep(200 * time.Millisecond)
+	healthChecks(manager, clk, 4)
 
 	if nodes[0].Status() != node.StatusSuspended {
 		t.Error("expected node to remain suspended when AutoResume is disabled")
 	}
 }
+
+func TestManagerSimulatedClock(t *testing.T) {
+	c := cluster.New()
+	_ = c.CreateNodes(1, "node")
+	_ = c.StartAll(context.Background())
+	defer func() { _ = c.StopAll() }()
+
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	config := DefaultConfig()
+	config.Clock = clk
+
+	manager := New(c, config)
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clk.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("health check ticker was not registered: %v", err)
+	}
+
+	// 実時間では数秒かかる検出と RecoveryDelay（2秒）を、仮想時計を1間隔ずつ進めて再現する
+	nodes := c.Nodes()
+	_ = nodes[0].Stop()
+	for nodes[0].Status() != node.StatusRunning {
+		if ctx.Err() != nil {
+			t.Fatal("expected the node to be restarted by the ticker-driven health check")
+		}
+		clk.Advance(config.HealthCheckInterval)
+		runtime.Gosched()
+	}
+}
@@ -0,0 +1,85 @@
+package scenario
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Assertions は実行結果が満たすべき条件（ゼロ値の項目は判定しない）
+// 満たされなかった条件は Result.AssertionFailures に記録され、SLO違反のイベントとして発行される
+type Assertions struct {
+	MaxErrorRate  float64       // エラー率の上限（0.01で1%）
+	MaxAvgLatency time.Duration // 平均レイテンシの上限
+	MaxP99Latency time.Duration // P99レイテンシの上限
+	MinThroughput float64       // スループット（秒間リクエスト数）の下限
+}
+
+// AssertionFailure は満たされなかった条件
+// レイテンシの値はミリ秒
+type AssertionFailure struct {
+	Metric    string  `json:"metric"` // error_rate, avg_latency_ms, p99_latency_ms, throughput, nodes_running（ステップの条件のみ）
+	Threshold float64 `json:"threshold"`
+	Value     float64 `json:"value"`
+}
+
+// String は条件と実際の値を返す
+func (f AssertionFailure) String() string {
+	op := "<="
+	if f.Metric == "throughput" || f.Metric == "nodes_running" {
+		op = ">="
+	}
+	return fmt.Sprintf("%s %s %s (actual %s)", f.Metric, op, formatValue(f.Threshold), formatValue(f.Value))
+}
+
+// formatValue は小数点以下4桁までに丸めた値を指数表記を使わずに返す
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// Check は結果が満たさない条件を返す
+func (a Assertions) Check(r *Result) []AssertionFailure {
+	var failures []AssertionFailure
+	if a.MaxErrorRate > 0 && r.ErrorRate > a.MaxErrorRate {
+		failures = append(failures, AssertionFailure{"error_rate", a.MaxErrorRate, r.ErrorRate})
+	}
+	if a.MaxAvgLatency > 0 && r.AvgLatency > a.MaxAvgLatency {
+		failures = append(failures, AssertionFailure{"avg_latency_ms", milliseconds(a.MaxAvgLatency), milliseconds(r.AvgLatency)})
+	}
+	if a.MaxP99Latency > 0 && r.P99Latency > a.MaxP99Latency {
+		failures = append(failures, AssertionFailure{"p99_latency_ms", milliseconds(a.MaxP99Latency), milliseconds(r.P99Latency)})
+	}
+	if a.MinThroughput > 0 && r.Throughput() < a.MinThroughput {
+		failures = append(failures, AssertionFailure{"throughput", a.MinThroughput, r.Throughput()})
+	}
+	return failures
+}
+
+// conditions は判定する条件を「指標 演算子 閾値」の文字列で返す
+func (a Assertions) conditions() []string {
+	var conditions []string
+	if a.MaxErrorRate > 0 {
+		conditions = append(conditions, "error_rate <= "+formatValue(a.MaxErrorRate))
+	}
+	if a.MaxAvgLatency > 0 {
+		conditions = append(conditions, fmt.Sprintf("avg_latency <= %v", a.MaxAvgLatency))
+	}
+	if a.MaxP99Latency > 0 {
+		conditions = append(conditions, fmt.Sprintf("p99_latency <= %v", a.MaxP99Latency))
+	}
+	if a.MinThroughput > 0 {
+		conditions = append(conditions, fmt.Sprintf("throughput >= %s req/s", formatValue(a.MinThroughput)))
+	}
+	return conditions
+}
+
+// milliseconds は期間をミリ秒で返す
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Passed は全ての条件を満たし、全てのステップが成功したかを返す
+func (r *Result) Passed() bool {
+	return len(r.AssertionFailures) == 0 && r.FailedStep() == nil
+}
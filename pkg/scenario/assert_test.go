@@ -0,0 +1,63 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssertionsCheck(t *testing.T) {
+	r := testResult() // エラー率 1%、スループット 100 req/s
+	r.AvgLatency = 2 * time.Millisecond
+	r.P99Latency = 20 * time.Millisecond
+
+	if failures := (Assertions{}).Check(r); len(failures) != 0 {
+		t.Errorf("zero assertions should not fail: %v", failures)
+	}
+
+	passing := Assertions{
+		MaxErrorRate:  0.05,
+		MaxAvgLatency: 5 * time.Millisecond,
+		MaxP99Latency: 50 * time.Millisecond,
+		MinThroughput: 50,
+	}
+	if failures := passing.Check(r); len(failures) != 0 {
+		t.Errorf("expected no failures, got %v", failures)
+	}
+
+	failing := Assertions{
+		MaxErrorRate:  0.005,
+		MaxAvgLatency: time.Millisecond,
+		MaxP99Latency: 10 * time.Millisecond,
+		MinThroughput: 200,
+	}
+	failures := failing.Check(r)
+	if len(failures) != 4 {
+		t.Fatalf("expected 4 failures, got %v", failures)
+	}
+	want := AssertionFailure{Metric: "p99_latency_ms", Threshold: 10, Value: 20}
+	if failures[2] != want {
+		t.Errorf("expected %+v, got %+v", want, failures[2])
+	}
+	if s := failures[3].String(); s != "throughput >= 200 (actual 100)" {
+		t.Errorf("unexpected failure string: %s", s)
+	}
+}
+
+func TestReportAssertionFailures(t *testing.T) {
+	r := testResult()
+	if !r.Passed() || strings.Contains(r.Report(), "ASSERTIONS FAILED") {
+		t.Error("result without failures should pass and omit the section")
+	}
+
+	r.AssertionFailures = []AssertionFailure{{Metric: "error_rate", Threshold: 0.005, Value: 0.01}}
+	if r.Passed() {
+		t.Error("result with failures should not pass")
+	}
+	if !strings.Contains(r.Report(), "error_rate <= 0.005 (actual 0.01)") {
+		t.Errorf("report should list assertion failures:\n%s", r.Report())
+	}
+	if len(r.Summary().AssertionFailures) != 1 {
+		t.Error("summary should keep assertion failures")
+	}
+}
@@ -0,0 +1,75 @@
+package scenario
+
+import (
+	"fmt"
+
+	"github.com/nyasuto/chaos-kvs/pkg/autoscale"
+	"github.com/nyasuto/chaos-kvs/pkg/client"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+)
+
+// AutoscaleReport は負荷に合わせたノード数の増減の結果
+type AutoscaleReport struct {
+	ScaleUps   uint64 `json:"scale_ups"`   // ノードを増やした回数
+	ScaleDowns uint64 `json:"scale_downs"` // ノードを減らした回数
+	Failures   uint64 `json:"failures"`    // 失敗した増減の回数
+	MinNodes   int    `json:"min_nodes"`   // 実行中の最小のノード数
+	MaxNodes   int    `json:"max_nodes"`   // 実行中の最大のノード数
+	FinalNodes int    `json:"final_nodes"` // 終了時のノード数
+}
+
+// validateAutoscale は Config.Autoscale がシナリオで使えるかを確かめる
+func (e *Engine) validateAutoscale() error {
+	if e.config.Autoscale.MaxNodes == 0 {
+		return nil
+	}
+	if len(e.config.External) > 0 {
+		return fmt.Errorf("autoscaling cannot be used with external nodes")
+	}
+	return e.config.Autoscale.Validate()
+}
+
+// newAutoscaler は c のノード数を cl の負荷に合わせて増減する Controller を作成する（Config.Autoscale が無効の場合は nil）
+func (e *Engine) newAutoscaler(c *cluster.Cluster, cl *client.Client) *autoscale.Controller {
+	config := e.config.Autoscale
+	if config.MaxNodes == 0 {
+		return nil
+	}
+	config.Prefix = nodePrefix
+	config.Clock = e.config.Clock
+	return autoscale.New(c, cl.Metrics(), config)
+}
+
+// autoscaleReport はノード数の増減の結果をまとめる
+func (e *Engine) autoscaleReport() *AutoscaleReport {
+	stats := e.autoscaler.Stats()
+	report := &AutoscaleReport{
+		ScaleUps:   stats.ScaleUps,
+		ScaleDowns: stats.ScaleDowns,
+		Failures:   stats.Failures,
+		MinNodes:   stats.MinNodes,
+		MaxNodes:   stats.MaxNodes,
+		FinalNodes: e.cluster.Size(),
+	}
+	// 一度も評価しなかった場合は開始時から変わっていない
+	if report.MinNodes == 0 {
+		report.MinNodes, report.MaxNodes = report.FinalNodes, report.FinalNodes
+	}
+	return report
+}
+
+// autoscaleRows はノード数の増減の結果を項目にする
+func (r *Result) autoscaleRows() [][2]string {
+	a := r.Autoscale
+	rows := [][2]string{
+		{"Scale Ups", fmt.Sprint(a.ScaleUps)},
+		{"Scale Downs", fmt.Sprint(a.ScaleDowns)},
+	}
+	if a.Failures > 0 {
+		rows = append(rows, [2]string{"Failures", fmt.Sprint(a.Failures)})
+	}
+	return append(rows,
+		[2]string{"Nodes", fmt.Sprintf("%d-%d", a.MinNodes, a.MaxNodes)},
+		[2]string{"Final Nodes", fmt.Sprint(a.FinalNodes)},
+	)
+}
@@ -0,0 +1,246 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CapacitySearch は持続可能な最大スループットの探索の設定
+// 目標の秒間リクエスト数を MinRPS から倍々に上げ、SLO を満たさなくなったら
+// 最後に満たした目標との間を二分探索する
+type CapacitySearch struct {
+	SLO           Assertions    // 各試行が満たすべき条件
+	MinRPS        float64       // 最初に試す目標
+	MaxRPS        float64       // 試す目標の上限
+	Precision     float64       // 満たした目標と満たさなかった目標の差がこの比率以下になったら終える（0.05で5%）
+	MaxProbes     int           // 1回の探索の試行数の上限
+	ProbeDuration time.Duration // 1回の試行の実行時間
+	MinAchieved   float64       // 目標に対して達成すべきスループットの比率（0.9で90%、0で判定しない）
+}
+
+// DefaultCapacitySearch はデフォルトの探索設定（P99 20ms 未満・エラー率1%未満）を返す
+func DefaultCapacitySearch() CapacitySearch {
+	return CapacitySearch{
+		SLO: Assertions{
+			MaxP99Latency: 20 * time.Millisecond,
+			MaxErrorRate:  0.01,
+		},
+		MinRPS:        100,
+		MaxRPS:        100000,
+		Precision:     0.05,
+		MaxProbes:     12,
+		ProbeDuration: 5 * time.Second,
+		MinAchieved:   0.9,
+	}
+}
+
+// Validate は探索設定を検証する
+func (s CapacitySearch) Validate() error {
+	if len(s.SLO.conditions()) == 0 {
+		return fmt.Errorf("capacity search requires an SLO")
+	}
+	if s.MinRPS <= 0 || s.MaxRPS < s.MinRPS {
+		return fmt.Errorf("capacity search requires 0 < min rps <= max rps")
+	}
+	if s.Precision <= 0 || s.Precision >= 1 {
+		return fmt.Errorf("capacity search precision must be between 0 and 1")
+	}
+	if s.MaxProbes <= 0 {
+		return fmt.Errorf("capacity search requires a positive number of probes")
+	}
+	if s.ProbeDuration <= 0 {
+		return fmt.Errorf("capacity search requires a positive probe duration")
+	}
+	if s.MinAchieved < 0 || s.MinAchieved > 1 {
+		return fmt.Errorf("capacity search min achieved must be between 0 and 1")
+	}
+	return nil
+}
+
+// CapacityProbe は目標の秒間リクエスト数での1回の試行
+type CapacityProbe struct {
+	TargetRPS  float64            `json:"target_rps"`
+	Throughput float64            `json:"throughput"` // 達成した秒間リクエスト数
+	ErrorRate  float64            `json:"error_rate"`
+	AvgLatency time.Duration      `json:"avg_latency"`
+	P99Latency time.Duration      `json:"p99_latency"`
+	Attacks    uint64             `json:"attacks"`
+	Passed     bool               `json:"passed"`
+	Failures   []AssertionFailure `json:"failures,omitempty"` // 満たさなかった条件（達成したスループットの不足は "throughput"）
+}
+
+// CapacityRun は1回の探索の結果
+type CapacityRun struct {
+	Chaos  bool            `json:"chaos"`   // カオス注入ありで探索したか
+	MaxRPS float64         `json:"max_rps"` // SLO を満たした最大の目標（満たした目標がなければ0）
+	Probes []CapacityProbe `json:"probes"`  // 試行した順
+}
+
+// CapacityResult はカオスなし・ありの持続可能な最大スループット
+type CapacityResult struct {
+	ScenarioName string       `json:"scenario_name"`
+	SLO          []string     `json:"slo"`             // 試行が満たすべき条件
+	Baseline     CapacityRun  `json:"baseline"`        // カオス注入なし
+	Chaos        *CapacityRun `json:"chaos,omitempty"` // カオス注入あり（シナリオでカオスが無効の場合は nil）
+}
+
+// FindCapacity は config のシナリオを目標の秒間リクエスト数を変えて繰り返し実行し、
+// SLO を満たす最大の目標をカオス注入なし・あり（config.EnableChaos の場合）で探索する
+// 試行では config の Duration・Steps・Assertions・Notifiers を使わない
+func FindCapacity(ctx context.Context, config Config, search CapacitySearch) (*CapacityResult, error) {
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &CapacityResult{ScenarioName: config.Name, SLO: search.SLO.conditions()}
+
+	baseline := config
+	baseline.EnableChaos = false
+	run, err := searchCapacity(ctx, baseline, search)
+	if err != nil {
+		return nil, err
+	}
+	result.Baseline = *run
+
+	if config.EnableChaos {
+		run, err := searchCapacity(ctx, config, search)
+		if err != nil {
+			return nil, err
+		}
+		result.Chaos = run
+	}
+	return result, nil
+}
+
+// searchCapacity は1つの設定で SLO を満たす最大の目標を探索する
+func searchCapacity(ctx context.Context, config Config, search CapacitySearch) (*CapacityRun, error) {
+	run := &CapacityRun{Chaos: config.EnableChaos, Probes: []CapacityProbe{}}
+	maxRPS, err := search.search(func(rps float64) (bool, error) {
+		p, err := runProbe(ctx, config, search, rps)
+		if err != nil {
+			return false, err
+		}
+		run.Probes = append(run.Probes, *p)
+		return p.Passed, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	run.MaxRPS = maxRPS
+	return run, nil
+}
+
+// search は probe が満たす最大の目標を探索する（満たす目標がなければ0）
+func (s CapacitySearch) search(probe func(rps float64) (bool, error)) (float64, error) {
+	probes := 0
+	try := func(rps float64) (bool, error) {
+		probes++
+		return probe(rps)
+	}
+
+	// 倍々に上げて、満たさなくなる目標を見つける
+	passed, failed := 0.0, 0.0
+	for rps := s.MinRPS; probes < s.MaxProbes; rps = min(rps*2, s.MaxRPS) {
+		ok, err := try(rps)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			failed = rps
+			break
+		}
+		passed = rps
+		if rps >= s.MaxRPS {
+			break
+		}
+	}
+
+	// 満たした目標と満たさなかった目標の間を二分探索する
+	for passed > 0 && failed > 0 && (failed-passed)/failed > s.Precision && probes < s.MaxProbes {
+		mid := (passed + failed) / 2
+		ok, err := try(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			passed = mid
+		} else {
+			failed = mid
+		}
+	}
+	return passed, nil
+}
+
+// runProbe は目標の秒間リクエスト数でシナリオを1回実行し、SLO を満たしたかを判定する
+func runProbe(ctx context.Context, config Config, search CapacitySearch, rps float64) (*CapacityProbe, error) {
+	config.Duration = search.ProbeDuration
+	config.TargetRPS = rps
+	config.Steps = nil
+	config.Assertions = Assertions{}
+	config.Notifiers = nil
+
+	log.Info("", "Capacity probe at %.0f req/s (chaos: %v)", rps, config.EnableChaos)
+	result, err := New(config).Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("capacity probe at %.0f req/s failed: %w", rps, err)
+	}
+	if result.Interrupted {
+		return nil, fmt.Errorf("capacity search interrupted at %.0f req/s", rps)
+	}
+
+	p := &CapacityProbe{
+		TargetRPS:  rps,
+		Throughput: result.Throughput(),
+		ErrorRate:  result.ErrorRate,
+		AvgLatency: result.AvgLatency,
+		P99Latency: result.P99Latency,
+		Attacks:    result.TotalAttacks,
+		Failures:   search.SLO.Check(result),
+	}
+	if search.MinAchieved > 0 && p.Throughput < rps*search.MinAchieved {
+		p.Failures = append(p.Failures, AssertionFailure{Metric: "throughput", Threshold: rps * search.MinAchieved, Value: p.Throughput})
+	}
+	p.Passed = len(p.Failures) == 0
+	return p, nil
+}
+
+// Report は探索結果をフォーマットして返す
+func (r *CapacityResult) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CAPACITY: %s\n", r.ScenarioName)
+	fmt.Fprintf(&b, "  SLO: %s\n", strings.Join(r.SLO, ", "))
+	for _, run := range []*CapacityRun{&r.Baseline, r.Chaos} {
+		if run == nil {
+			continue
+		}
+		label := "without chaos"
+		if run.Chaos {
+			label = "with chaos"
+		}
+		fmt.Fprintf(&b, "\n  Max sustainable throughput %s: %s\n", label, run.maxRPSString())
+		fmt.Fprintf(&b, "    %10s %12s %10s %12s %8s  %s\n", "Target", "Achieved", "Errors", "P99", "Attacks", "Result")
+		for _, p := range run.Probes {
+			status := "ok"
+			if !p.Passed {
+				failures := make([]string, 0, len(p.Failures))
+				for _, f := range p.Failures {
+					failures = append(failures, f.String())
+				}
+				status = "failed " + strings.Join(failures, ", ")
+			}
+			fmt.Fprintf(&b, "    %10.0f %12.1f %9.2f%% %12v %8d  %s\n",
+				p.TargetRPS, p.Throughput, p.ErrorRate*100, p.P99Latency.Round(time.Microsecond), p.Attacks, status)
+		}
+	}
+	return b.String()
+}
+
+// maxRPSString は最大の目標を表示用に返す
+func (run *CapacityRun) maxRPSString() string {
+	if run.MaxRPS <= 0 {
+		return "none (the SLO was not met at the minimum rate)"
+	}
+	return fmt.Sprintf("%.0f req/s", run.MaxRPS)
+}
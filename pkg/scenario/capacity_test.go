@@ -0,0 +1,145 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCapacitySearchValidate(t *testing.T) {
+	if err := DefaultCapacitySearch().Validate(); err != nil {
+		t.Errorf("expected the default search to be valid, got %v", err)
+	}
+
+	invalid := []func(*CapacitySearch){
+		func(s *CapacitySearch) { s.SLO = Assertions{} },
+		func(s *CapacitySearch) { s.MinRPS = 0 },
+		func(s *CapacitySearch) { s.MaxRPS = s.MinRPS / 2 },
+		func(s *CapacitySearch) { s.Precision = 1 },
+		func(s *CapacitySearch) { s.MaxProbes = 0 },
+		func(s *CapacitySearch) { s.ProbeDuration = 0 },
+		func(s *CapacitySearch) { s.MinAchieved = 1.5 },
+	}
+	for i, modify := range invalid {
+		s := DefaultCapacitySearch()
+		modify(&s)
+		if err := s.Validate(); err == nil {
+			t.Errorf("case %d: expected %+v to be invalid", i, s)
+		}
+	}
+}
+
+func TestCapacitySearchRates(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  float64 // probe は limit 以下の目標で成功する
+		probes int
+		want   float64
+		tried  []float64
+	}{
+		{"binary search", 300, 20, 300, []float64{100, 200, 400, 300, 350, 325, 312.5}},
+		{"max rate", 1e9, 20, 800, []float64{100, 200, 400, 800}},
+		{"nothing passes", 50, 20, 0, []float64{100}},
+		{"probe limit", 300, 4, 300, []float64{100, 200, 400, 300}},
+	}
+	for _, tt := range tests {
+		s := DefaultCapacitySearch()
+		s.MaxRPS = 800
+		s.MaxProbes = tt.probes
+
+		var tried []float64
+		got, err := s.search(func(rps float64) (bool, error) {
+			tried = append(tried, rps)
+			return rps <= tt.limit, nil
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		if len(tried) != len(tt.tried) {
+			t.Errorf("%s: expected probes %v, got %v", tt.name, tt.tried, tried)
+			continue
+		}
+		for i := range tried {
+			if tried[i] != tt.tried[i] {
+				t.Errorf("%s: expected probes %v, got %v", tt.name, tt.tried, tried)
+				break
+			}
+		}
+	}
+
+	failing := errors.New("probe failed")
+	if _, err := DefaultCapacitySearch().search(func(float64) (bool, error) { return false, failing }); !errors.Is(err, failing) {
+		t.Errorf("expected the probe error, got %v", err)
+	}
+}
+
+func TestFindCapacity(t *testing.T) {
+	config := QuickScenario()
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.ChaosInterval = 50 * time.Millisecond
+	config.Steps = []Step{{Kind: StepAssert}} // 試行では使わない
+
+	search := DefaultCapacitySearch()
+	search.SLO = Assertions{MaxErrorRate: 1}
+	search.MinRPS = 50
+	search.MaxRPS = 100
+	search.ProbeDuration = 200 * time.Millisecond
+	search.MinAchieved = 0.1
+
+	result, err := FindCapacity(context.Background(), config, search)
+	if err != nil {
+		t.Fatalf("failed to find capacity: %v", err)
+	}
+	if result.Baseline.Chaos || result.Baseline.MaxRPS != 100 || len(result.Baseline.Probes) != 2 {
+		t.Errorf("unexpected baseline: %+v", result.Baseline)
+	}
+	for _, p := range result.Baseline.Probes {
+		if p.Attacks != 0 || !p.Passed || p.Throughput <= 0 {
+			t.Errorf("expected a passing probe without attacks, got %+v", p)
+		}
+	}
+	if result.Chaos == nil || !result.Chaos.Chaos || len(result.Chaos.Probes) == 0 {
+		t.Fatalf("expected a search with chaos, got %+v", result.Chaos)
+	}
+	if attacks := result.Chaos.Probes[0].Attacks; attacks == 0 {
+		t.Error("expected attacks during the probes with chaos")
+	}
+
+	report := result.Report()
+	for _, s := range []string{"CAPACITY: quick", "SLO: error_rate <= 1", "without chaos: 100 req/s", "with chaos:"} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report should contain %q:\n%s", s, report)
+		}
+	}
+
+	config.EnableChaos = false
+	search.SLO = Assertions{MaxP99Latency: time.Nanosecond}
+	search.MinAchieved = 0
+	result, err = FindCapacity(context.Background(), config, search)
+	if err != nil {
+		t.Fatalf("failed to find capacity: %v", err)
+	}
+	if result.Chaos != nil || result.Baseline.MaxRPS != 0 || len(result.Baseline.Probes) != 1 || result.Baseline.Probes[0].Passed {
+		t.Errorf("expected the SLO to fail at the minimum rate without a chaos search, got %+v", result)
+	}
+	if !strings.Contains(result.Report(), "none (the SLO was not met") {
+		t.Errorf("unexpected report:\n%s", result.Report())
+	}
+}
+
+func TestFindCapacityInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	search := DefaultCapacitySearch()
+	search.ProbeDuration = time.Second
+	if _, err := FindCapacity(ctx, QuickScenario(), search); err == nil {
+		t.Error("expected an interrupted search to fail")
+	}
+}
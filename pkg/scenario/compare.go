@@ -0,0 +1,129 @@
+package scenario
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Thresholds は結果の比較で回帰とみなす変化の大きさ（0の項目は判定しない）
+type Thresholds struct {
+	Throughput float64 // スループット（秒間リクエスト数）の低下率（0.1で10%）
+	ErrorRate  float64 // エラー率の増加幅（0.01で1ポイント）
+	AvgLatency float64 // 平均レイテンシの増加率
+	P99Latency float64 // P99レイテンシの増加率
+}
+
+// DefaultThresholds はデフォルトの閾値を返す
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Throughput: 0.1,
+		ErrorRate:  0.01,
+		AvgLatency: 0.2,
+		P99Latency: 0.2,
+	}
+}
+
+// MetricDelta は1つの指標の比較結果
+type MetricDelta struct {
+	Name       string  `json:"name"`
+	Unit       string  `json:"unit,omitempty"`
+	Base       float64 `json:"base"`
+	Current    float64 `json:"current"`
+	Delta      float64 `json:"delta"`  // Current - Base
+	Change     float64 `json:"change"` // Base に対する変化率（Base が0の場合は0）
+	Regression bool    `json:"regression"`
+}
+
+// Comparison は2つの結果の比較
+type Comparison struct {
+	Base    string        `json:"base"`    // 比較元のシナリオ名
+	Current string        `json:"current"` // 比較先のシナリオ名
+	Metrics []MetricDelta `json:"metrics"`
+}
+
+// Compare は base に対する current の指標の変化を求め、閾値を超えた悪化を回帰として示す
+func Compare(base, current *Result, t Thresholds) *Comparison {
+	c := &Comparison{Base: base.ScenarioName, Current: current.ScenarioName}
+
+	add := func(name, unit string, b, cur float64, regression func(d MetricDelta) bool) {
+		d := MetricDelta{Name: name, Unit: unit, Base: b, Current: cur, Delta: cur - b}
+		if b != 0 {
+			d.Change = d.Delta / b
+		}
+		if regression != nil {
+			d.Regression = regression(d)
+		}
+		c.Metrics = append(c.Metrics, d)
+	}
+	// increase は変化率が閾値を超えて増えた場合に回帰とする
+	increase := func(threshold float64) func(MetricDelta) bool {
+		return func(d MetricDelta) bool { return threshold > 0 && d.Base > 0 && d.Change > threshold }
+	}
+
+	add("Throughput", "req/s", base.Throughput(), current.Throughput(), func(d MetricDelta) bool {
+		return t.Throughput > 0 && d.Base > 0 && -d.Change > t.Throughput
+	})
+	add("Error Rate", "%", base.ErrorRate*100, current.ErrorRate*100, func(d MetricDelta) bool {
+		return t.ErrorRate > 0 && d.Delta > t.ErrorRate*100
+	})
+	add("Avg Latency", "us", microseconds(base.AvgLatency), microseconds(current.AvgLatency), increase(t.AvgLatency))
+	add("P99 Latency", "us", microseconds(base.P99Latency), microseconds(current.P99Latency), increase(t.P99Latency))
+	add("Total Requests", "", float64(base.TotalRequests), float64(current.TotalRequests), nil)
+	add("Total Attacks", "", float64(base.TotalAttacks), float64(current.TotalAttacks), nil)
+	add("Failed Recoveries", "", float64(base.FailedRecoveries), float64(current.FailedRecoveries), nil)
+	return c
+}
+
+// microseconds は期間をマイクロ秒で返す
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// Throughput は実行時間あたりのリクエスト数（秒間）を返す
+func (r *Result) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.TotalRequests) / r.Duration.Seconds()
+}
+
+// Regressions は回帰と判定された指標を返す
+func (c *Comparison) Regressions() []MetricDelta {
+	var regressions []MetricDelta
+	for _, m := range c.Metrics {
+		if m.Regression {
+			regressions = append(regressions, m)
+		}
+	}
+	return regressions
+}
+
+// Report は比較結果を表形式でフォーマットして返す
+func (c *Comparison) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "COMPARISON: %s -> %s\n", c.Base, c.Current)
+	fmt.Fprintf(&b, "  %-18s %14s %14s %14s %9s\n", "Metric", "Base", "Current", "Delta", "Change")
+	for _, m := range c.Metrics {
+		name := m.Name
+		if m.Unit != "" {
+			name += " (" + m.Unit + ")"
+		}
+		change := "-"
+		if m.Base != 0 {
+			change = fmt.Sprintf("%+.1f%%", m.Change*100)
+		}
+		mark := ""
+		if m.Regression {
+			mark = "  REGRESSION"
+		}
+		fmt.Fprintf(&b, "  %-18s %14.2f %14.2f %+14.2f %9s%s\n", name, m.Base, m.Current, m.Delta, change, mark)
+	}
+
+	if n := len(c.Regressions()); n > 0 {
+		fmt.Fprintf(&b, "\n%d regression(s) detected\n", n)
+	} else {
+		b.WriteString("\nNo regressions detected\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	base := testResult()
+	base.AvgLatency = 2 * time.Millisecond
+	base.P99Latency = 10 * time.Millisecond
+
+	current := testResult()
+	current.TotalRequests = 800 // スループット 20% 低下
+	current.ErrorRate = 0.015   // 0.5ポイント増加（閾値未満）
+	current.AvgLatency = 3 * time.Millisecond
+	current.P99Latency = 11 * time.Millisecond
+
+	c := Compare(base, current, DefaultThresholds())
+
+	metrics := make(map[string]MetricDelta)
+	for _, m := range c.Metrics {
+		metrics[m.Name] = m
+	}
+	if m := metrics["Throughput"]; m.Base != 100 || m.Current != 80 || !m.Regression {
+		t.Errorf("unexpected throughput delta: %+v", m)
+	}
+	if m := metrics["Error Rate"]; m.Regression {
+		t.Errorf("error rate increase below threshold should not be a regression: %+v", m)
+	}
+	if m := metrics["Avg Latency"]; m.Change != 0.5 || !m.Regression {
+		t.Errorf("unexpected avg latency delta: %+v", m)
+	}
+	if m := metrics["P99 Latency"]; m.Regression {
+		t.Errorf("p99 increase of 10%% should not be a regression: %+v", m)
+	}
+	if n := len(c.Regressions()); n != 2 {
+		t.Errorf("expected 2 regressions, got %d", n)
+	}
+
+	report := c.Report()
+	if !strings.Contains(report, "REGRESSION") || !strings.Contains(report, "2 regression(s) detected") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+
+	// 閾値0の項目は判定しない
+	if n := len(Compare(base, current, Thresholds{}).Regressions()); n != 0 {
+		t.Errorf("expected no regressions with zero thresholds, got %d", n)
+	}
+	if report := Compare(base, base, DefaultThresholds()).Report(); !strings.Contains(report, "No regressions") {
+		t.Errorf("unexpected report for identical results:\n%s", report)
+	}
+}
@@ -0,0 +1,82 @@
+// Package scenario は統合シナリオ実行機能を提供する。
+//
+// シナリオエンジンはChaosMonkey、RecoveryManager、Clientを
+// 連携させて統合テストを実行する。
+//
+// # 機能
+//
+// - シナリオ定義と実行
+// - 定義済みプリセットシナリオ
+// - 実行結果のレポート生成
+//
+// # プリセットシナリオ
+//
+// - basic: カオスなしの基本負荷テスト
+// - resilience: ノードkillと復旧のテスト
+// - latency: レイテンシ注入テスト
+// - stress: 高負荷ストレステスト
+// - quick: 短時間の動作確認
+//
+// # 使用例
+//
+//	config := scenario.ResilienceScenario()
+//	engine := scenario.New(config)
+//	result, err := engine.Run(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(result.Report())
+//
+// # ステップ
+//
+// Config.Steps を指定すると、負荷の開始後に load・wait・inject・heal・assert の
+// ステップを順に実行する。Duration が0の場合は全てのステップを終えた時点で終了し、
+// 失敗したステップがあれば Result.Passed は false を返す。
+//
+// # 攻撃の記録と再生
+//
+// Result.Attacks にはカオスモンキーが注入した攻撃の対象・種類・時刻が記録される。
+// 別の実行の Config.ChaosReplay に渡すと、乱数で選ぶ代わりに同じ攻撃を同じ時刻に再生する。
+//
+// # データの保持方法の比較
+//
+// 負荷生成はキーを担当するノードにリクエストを送る。Config.ReplicationFactor を2以上にすると、
+// キーをリングに並ぶその数のノードに書き込み、読み取りはそのどれかから行うため、
+// 攻撃で停止したノードのキーも残りのノードから読める。Config.Consistency で書き込み（W）と読み取り（R）が
+// 応答を待つノードの数を ONE・QUORUM・ALL から選び、レプリカを停止させる攻撃の下での可用性と整合性を比べられる。
+// Config.Store に node.StoreCRDT を指定すると、Config.SyncInterval ごとに稼働中のノードの状態をマージし、
+// 停止・一時停止の間の書き込みの競合を後勝ちで解決する。Config.Store を指定した場合は
+// 終了時のノードの間で一致しないキーの数を Result.Replicas に含め、node.StoreMap と比べられる。
+// Config.Latency でゾーンの間の遅延を設定すると、同期と負荷生成のリクエストの転送が遅れ、
+// 複数のデータセンターにまたがる構成での収束の遅れを再現できる。
+//
+// # データの永続性
+//
+// Config.Durability でノードを再起動したときのデータの扱いを選ぶ。node.DurabilityEphemeral は
+// 再起動のたびにデータを失い、node.DurabilitySnapshot は一定間隔で書き出したスナップショットを読み込む。
+// node.DurabilityWAL は書き込みを追記したログを再生し、Durability.Sync でログを書き出す頻度を選ぶ。
+// Durability.EphemeralLossRatio は memory のノードが再起動のたびにランダムに失うキーの割合で、
+// 全て保持する memory と全て失う ephemeral の間の永続性を模擬する。
+// kill による停止から復旧するまでに失ったキーの数を Result.Durability に含め、モードの違いを比べられる。
+//
+// # オートスケール
+//
+// Config.Autoscale の MaxNodes を指定すると、負荷生成の区間ごとの1ノードあたりのRPSとP99に合わせて
+// MinNodes から MaxNodes の間でノードを増減する。追加・削除のたびにキーをレプリカに移すため、
+// カオス注入の下でノード数の変化に追従できるか（エラー率・失ったキー）を確かめられる。
+// 増減の回数と実行中のノード数の範囲は Result.Autoscale に含める。
+//
+// # 最大スループットの探索
+//
+// FindCapacity は Config.TargetRPS を変えてシナリオを繰り返し実行し、
+// CapacitySearch.SLO を満たす最大の秒間リクエスト数をカオス注入なし・ありで探索する。
+//
+// # 外部連携の設定
+//
+// Config の External・Toxiproxy・Tracing・Autoscale はそれぞれ pkg/external・pkg/toxiproxy・
+// pkg/tracing・pkg/autoscale の設定で、各パッケージの DefaultConfig から組み立てられる。
+// Result.Linearizability は pkg/lincheck の検査結果。
+//
+//	config.Tracing = tracing.DefaultConfig()
+//	config.Tracing.Endpoint = "http://127.0.0.1:4318"
+package scenario
@@ -0,0 +1,78 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// DurabilityReport はノードのデータの永続性と、再起動をまたいだデータの残り具合
+type DurabilityReport struct {
+	Mode      node.DurabilityMode `json:"mode"`
+	Restarts  int                 `json:"restarts"`  // 停止したノードを再び起動した回数の合計
+	LostKeys  uint64              `json:"lost_keys"` // 再起動で失ったキーの数の合計
+	Snapshots uint64              `json:"snapshots"` // 書き出したスナップショットの数の合計
+	Keys      int                 `json:"keys"`      // 終了時にノードが保持しているキーの数の合計
+}
+
+// setupDurability は c のノードにデータの永続性を設定する
+// スナップショット・ログのディレクトリが指定されていない場合は一時ディレクトリを作成し、removePersistDir で削除する
+func (e *Engine) setupDurability(c *cluster.Cluster, d node.Durability) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	if d.Mode == node.DurabilitySnapshot || d.Mode == node.DurabilityWAL {
+		if d.Path == "" {
+			dir, err := os.MkdirTemp("", "chaos-kvs-"+string(d.Mode)+"-")
+			if err != nil {
+				return fmt.Errorf("failed to create %s directory: %w", d.Mode, err)
+			}
+			e.persistDir, d.Path = dir, dir
+		} else if err := os.MkdirAll(d.Path, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", d.Mode, err)
+		}
+	}
+	c.SetNodeDurability(d)
+	return nil
+}
+
+// removePersistDir は setupDurability で作成した一時ディレクトリを削除する
+func (e *Engine) removePersistDir() {
+	if e.persistDir == "" {
+		return
+	}
+	if err := os.RemoveAll(e.persistDir); err != nil {
+		log.Warn("", "Failed to remove snapshot directory: %v", err)
+	}
+	e.persistDir = ""
+}
+
+// durabilityReport は再起動をまたいだデータの残り具合をまとめる
+func (e *Engine) durabilityReport() *DurabilityReport {
+	mode, _ := node.ParseDurabilityMode(string(e.config.Durability.Mode))
+	report := &DurabilityReport{Mode: mode}
+	for _, n := range e.cluster.Nodes() {
+		stats := n.Stats()
+		report.Restarts += max(n.Incarnations()-1, 0)
+		report.LostKeys += stats.Lost
+		report.Snapshots += stats.Snapshots
+		report.Keys += n.Size()
+	}
+	return report
+}
+
+// durabilityRows はデータの永続性と残り具合を項目にする
+func (r *Result) durabilityRows() [][2]string {
+	d := r.Durability
+	rows := [][2]string{
+		{"Mode", string(d.Mode)},
+		{"Restarts", fmt.Sprint(d.Restarts)},
+		{"Lost Keys", fmt.Sprint(d.LostKeys)},
+	}
+	if d.Mode == node.DurabilitySnapshot {
+		rows = append(rows, [2]string{"Snapshots", fmt.Sprint(d.Snapshots)})
+	}
+	return append(rows, [2]string{"Keys", fmt.Sprint(d.Keys)})
+}
@@ -0,0 +1,364 @@
+package scenario
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/client"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/lincheck"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/recovery"
+)
+
+// nodePrefix はシナリオで作成するノードのIDの接頭辞（node-1, node-2, ...）
+const nodePrefix = "node"
+
+// maxPlanAttacks は実行計画のレポートに列挙する攻撃の最大数
+const maxPlanAttacks = 20
+
+// PlannedNode は作成するノード
+type PlannedNode struct {
+	ID   string
+	Zone string // 割り当てるゾーン（ゾーン未設定の場合は空）
+	RESP string // ノードごとのRESPリスナーのアドレス（無効の場合は空）
+	HTTP string // ノードごとのHTTPサーバーのアドレス（無効の場合は空、ポートが実行時に決まる場合は 127.0.0.1:0）
+
+	External string // 外部のプロセスのアドレス（in-process のノードの場合は空）
+}
+
+// Phase は実行計画の段階（Start・End はシナリオ開始からの経過時間）
+type Phase struct {
+	Name        string
+	Start       time.Duration
+	End         time.Duration
+	Description string
+}
+
+// Plan は設定から求めたシナリオの実行計画
+// 攻撃の時刻は決まっているが、攻撃の種類と対象ノードは実行時に無作為に選ばれる
+type Plan struct {
+	Config  Config
+	Nodes   []PlannedNode
+	Phases  []Phase
+	Attacks []time.Duration // 攻撃する時刻（シナリオ開始からの経過時間）
+}
+
+// NewPlan は設定から実行計画を求める。ノードやゴルーチンは作成しない
+func NewPlan(cfg Config) *Plan {
+	p := &Plan{Config: cfg}
+
+	if len(cfg.External) > 0 {
+		// クラスタと同じくID順（長さ→辞書順）にゾーンを割り当てる
+		for _, ext := range cfg.External {
+			p.Nodes = append(p.Nodes, PlannedNode{ID: ext.ID, External: ext.Addr})
+		}
+		sort.SliceStable(p.Nodes, func(i, j int) bool {
+			a, b := p.Nodes[i].ID, p.Nodes[j].ID
+			if len(a) != len(b) {
+				return len(a) < len(b)
+			}
+			return a < b
+		})
+		for i := range p.Nodes {
+			if len(cfg.Zones) > 0 {
+				p.Nodes[i].Zone = cfg.Zones[i%len(cfg.Zones)]
+			}
+		}
+	}
+
+	count := cfg.NodeCount
+	if len(cfg.External) > 0 {
+		count = 0 // 外部のノードを使う場合は NodeCount のノードを作成しない
+	}
+	for i := range count {
+		n := PlannedNode{ID: fmt.Sprintf("%s-%d", nodePrefix, i+1)}
+		if len(cfg.Zones) > 0 {
+			n.Zone = cfg.Zones[i%len(cfg.Zones)]
+		}
+		if cfg.RESPNodePort > 0 {
+			n.RESP = fmt.Sprintf(":%d", cfg.RESPNodePort+i)
+		}
+		if cfg.NodeHTTP {
+			port := 0
+			if cfg.NodeHTTPPort > 0 {
+				port = cfg.NodeHTTPPort + i
+			}
+			n.HTTP = fmt.Sprintf("127.0.0.1:%d", port)
+		}
+		if cfg.NodeProcess {
+			n.HTTP = "127.0.0.1:0" // 子プロセスが空いているポートで待ち受ける
+		}
+		p.Nodes = append(p.Nodes, n)
+	}
+
+	setup := fmt.Sprintf("%d nodes created and started", cfg.NodeCount)
+	if cfg.NodeProcess {
+		setup = fmt.Sprintf("%d nodes created and started as separate processes", cfg.NodeCount)
+	}
+	if len(cfg.External) > 0 {
+		setup = fmt.Sprintf("%d external nodes started and checked over RESP", len(cfg.External))
+	}
+	if len(cfg.Zones) > 0 {
+		setup += fmt.Sprintf(" across %d zones", len(cfg.Zones))
+	}
+	if l := cfg.NodeLimits; l.Enabled() {
+		var limits []string
+		if l.MaxKeys > 0 {
+			limits = append(limits, fmt.Sprintf("%d keys", l.MaxKeys))
+		}
+		if l.MaxBytes > 0 {
+			limits = append(limits, fmt.Sprintf("%d bytes", l.MaxBytes))
+		}
+		setup += ", LRU eviction above " + strings.Join(limits, " or ") + " per node"
+	}
+	if l := cfg.NodeLimits; l.MaxMemory > 0 {
+		setup += fmt.Sprintf(", writes refused above %d bytes of memory per node", l.MaxMemory)
+	}
+	if a := cfg.NodeAdmission; a.Enabled() {
+		setup += fmt.Sprintf(", load shedding above %d in-flight operations per node", a.MaxInFlight)
+		if a.ReservedReads > 0 {
+			setup += fmt.Sprintf(" (%d reserved for reads)", a.ReservedReads)
+		}
+	}
+	switch d := cfg.Durability; d.Mode {
+	case "", node.DurabilityMemory:
+		if d.EphemeralLossRatio > 0 {
+			setup += fmt.Sprintf(", nodes that lose %.0f%% of their keys on restart", d.EphemeralLossRatio*100)
+		}
+	case node.DurabilityEphemeral:
+		setup += ", ephemeral nodes that lose their data on restart"
+	case node.DurabilitySnapshot:
+		interval := d.Interval
+		if interval <= 0 {
+			interval = node.DefaultPersistInterval
+		}
+		setup += fmt.Sprintf(", node snapshots every %v reloaded on restart", interval)
+	case node.DurabilityWAL:
+		interval := d.Interval
+		if interval <= 0 {
+			interval = node.DefaultPersistInterval
+		}
+		switch policy, _ := node.ParseWALSync(string(d.Sync)); policy {
+		case node.WALSyncAlways:
+			setup += ", write-ahead logs synced on every write"
+		case node.WALSyncNone:
+			setup += ", write-ahead logs never synced"
+		default:
+			setup += fmt.Sprintf(", write-ahead logs synced every %v", interval)
+		}
+	}
+	if cfg.Store == node.StoreCRDT {
+		setup += ", CRDT stores"
+		if interval := cfg.SyncInterval; interval >= 0 {
+			if interval == 0 {
+				interval = DefaultSyncInterval
+			}
+			setup += fmt.Sprintf(" synced every %v", interval)
+		}
+	}
+	if cfg.ReplicationFactor > 1 {
+		setup += fmt.Sprintf(", keys written to %d replicas (%s)", cfg.ReplicationFactor, cfg.Consistency)
+	}
+	if m := cfg.Latency; m.Enabled() {
+		setup += ", " + describeLatency(m)
+	}
+	if a := cfg.Autoscale; a.MaxNodes > 0 {
+		setup += fmt.Sprintf(", autoscaled between %d and %d nodes", max(a.MinNodes, 1), a.MaxNodes)
+	}
+	if cfg.RESPAddr != "" {
+		setup += fmt.Sprintf(", RESP on %s", cfg.RESPAddr)
+	}
+	if cfg.Tracing.Endpoint != "" {
+		setup += ", spans exported over OTLP to " + cfg.Tracing.Endpoint
+	}
+	load := fmt.Sprintf("%d workers, write ratio %.0f%%", cfg.ClientWorkers, cfg.WriteRatio*100)
+	if cfg.BatchSize > 1 {
+		load += fmt.Sprintf(", batches of %d keys", cfg.BatchSize)
+	}
+	if cfg.ScanRatio > 0 {
+		limit := cfg.ScanLimit
+		if limit <= 0 {
+			limit = client.DefaultScanLimit
+		}
+		load += fmt.Sprintf(", %.0f%% prefix scans of up to %d keys", cfg.ScanRatio*100, limit)
+	}
+	if len(cfg.Namespaces) > 0 {
+		load += ", spread across namespaces " + strings.Join(cfg.Namespaces, ", ")
+	}
+	switch {
+	case len(cfg.External) > 0:
+		load += ", over RESP to external nodes"
+	case cfg.NodeHTTP:
+		load += ", over loopback HTTP"
+	case cfg.NodeProcess:
+		load += ", over HTTP to node processes"
+	}
+	if cfg.Toxiproxy.URL != "" {
+		load += ", through toxiproxy at " + cfg.Toxiproxy.URL
+	}
+	// ステップのみで終了を決める場合は、条件を待つ時間を上限で数えた目安の時間で終える
+	end := cfg.Duration
+	if len(cfg.Steps) > 0 {
+		load += fmt.Sprintf(", driven by %d steps", len(cfg.Steps))
+		if end <= 0 {
+			end = stepsDuration(cfg.Steps)
+		}
+	}
+	p.Phases = append(p.Phases,
+		Phase{"setup", 0, 0, setup},
+		Phase{"load", 0, end, load},
+	)
+
+	// 攻撃の記録を再生する場合は記録された時刻に攻撃する（終了以降の攻撃は注入されない）
+	// それ以外はカオスモンキーは開始から ChaosInterval ごとに攻撃する（設定時間ちょうどの攻撃は終了と競合するため含めない）
+	if cfg.EnableChaos && len(cfg.ChaosReplay) > 0 {
+		for _, a := range cfg.ChaosReplay {
+			if a.At < end && (len(p.Attacks) == 0 || p.Attacks[len(p.Attacks)-1] != a.At) {
+				p.Attacks = append(p.Attacks, a.At)
+			}
+		}
+		p.Phases = append(p.Phases, Phase{"chaos", 0, end, fmt.Sprintf(
+			"%d attacks replayed from %d recorded attack(s)", len(p.Attacks), len(cfg.ChaosReplay))})
+	} else if cfg.EnableChaos && cfg.ChaosInterval > 0 {
+		for at := cfg.ChaosInterval; at < end; at += cfg.ChaosInterval {
+			p.Attacks = append(p.Attacks, at)
+		}
+		p.Phases = append(p.Phases, Phase{"chaos", cfg.ChaosInterval, end, fmt.Sprintf(
+			"%d attacks every %v on %d node(s), types: %s",
+			len(p.Attacks), cfg.ChaosInterval, cfg.ChaosTargets, attackTypeList(cfg))})
+	}
+	if cfg.EnableRecovery {
+		p.Phases = append(p.Phases, Phase{"recovery", 0, end, fmt.Sprintf(
+			"health check every %v, recover after %v, up to %d retries",
+			recovery.DefaultConfig().HealthCheckInterval, cfg.RecoveryDelay, cfg.MaxRetries)})
+	}
+	teardown := "stop components and nodes"
+	if cfg.Linearizability {
+		limit := cfg.HistoryLimit
+		if limit <= 0 {
+			limit = lincheck.DefaultConfig().MaxOperations
+		}
+		teardown += fmt.Sprintf(", check linearizability of up to %d recorded operations", limit)
+	}
+	p.Phases = append(p.Phases, Phase{"teardown", end, end, teardown})
+
+	return p
+}
+
+// attackTypeList は有効な攻撃タイプをカンマ区切りで返す
+func attackTypeList(cfg Config) string {
+	names := make([]string, 0, len(cfg.AttackTypes))
+	for _, a := range cfg.AttackTypes {
+		names = append(names, a.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// Report は実行計画をテキストで返す
+func (p *Plan) Report() string {
+	cfg := p.Config
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\n%s\n", strings.Repeat("=", 80))
+	fmt.Fprintf(&b, "                         SCENARIO PLAN: %s (dry run)\n", cfg.Name)
+	fmt.Fprintf(&b, "%s\n", strings.Repeat("=", 80))
+
+	fmt.Fprintf(&b, "\nSCENARIO\n--------\n")
+	if cfg.Description != "" {
+		fmt.Fprintf(&b, "  Description:  %s\n", cfg.Description)
+	}
+	if len(cfg.Steps) > 0 && cfg.Duration <= 0 {
+		fmt.Fprintf(&b, "  Duration:     until all steps finish (about %v)\n", stepsDuration(cfg.Steps))
+	} else {
+		fmt.Fprintf(&b, "  Duration:     %v\n", cfg.Duration)
+	}
+	fmt.Fprintf(&b, "  Chaos:        %v\n", cfg.EnableChaos)
+	fmt.Fprintf(&b, "  Recovery:     %v\n", cfg.EnableRecovery)
+	if len(cfg.Notifiers) > 0 {
+		fmt.Fprintf(&b, "  Notifiers:    %d\n", len(cfg.Notifiers))
+	}
+
+	fmt.Fprintf(&b, "\nPHASES\n------\n")
+	for _, ph := range p.Phases {
+		fmt.Fprintf(&b, "  %-9s %8v - %-8v %s\n", ph.Name, ph.Start, ph.End, ph.Description)
+	}
+
+	if cfg.EnableChaos {
+		fmt.Fprintf(&b, "\nSCHEDULED ATTACKS\n-----------------\n")
+		switch {
+		case len(p.Attacks) > 0:
+		case len(cfg.ChaosReplay) > 0:
+			fmt.Fprintf(&b, "  (none: all recorded attacks are after the end of the scenario)\n")
+		default:
+			fmt.Fprintf(&b, "  (none: chaos interval %v is not shorter than the duration)\n", cfg.ChaosInterval)
+		}
+		for i, at := range p.Attacks {
+			if i == maxPlanAttacks {
+				fmt.Fprintf(&b, "  ... and %d more\n", len(p.Attacks)-maxPlanAttacks)
+				break
+			}
+			fmt.Fprintf(&b, "  #%-4d %8v\n", i+1, at)
+		}
+		switch {
+		case len(p.Attacks) == 0:
+		case len(cfg.ChaosReplay) > 0:
+			fmt.Fprintf(&b, "  Attack types and target nodes are replayed from the recorded attacks.\n")
+		default:
+			fmt.Fprintf(&b, "  Attack types and target nodes are chosen at random when the scenario runs.\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "\nNODE LAYOUT\n-----------\n")
+	for _, n := range p.Nodes {
+		line := fmt.Sprintf("  %-20s", n.ID)
+		if n.Zone != "" {
+			line += " zone=" + n.Zone
+		}
+		if n.RESP != "" {
+			line += " resp=" + n.RESP
+		}
+		if n.HTTP != "" {
+			line += " http=" + n.HTTP
+		}
+		if n.External != "" {
+			line += " external=" + n.External
+		}
+		fmt.Fprintln(&b, strings.TrimRight(line, " "))
+	}
+
+	if len(cfg.Steps) > 0 {
+		fmt.Fprintf(&b, "\nSTEPS\n-----\n")
+		for i, s := range cfg.Steps {
+			line := s.String()
+			if s.Name != "" {
+				line = s.Name + ": " + line
+			}
+			fmt.Fprintf(&b, "  #%-4d %s\n", i+1, line)
+		}
+	}
+
+	if conditions := cfg.Assertions.conditions(); len(conditions) > 0 {
+		fmt.Fprintf(&b, "\nASSERTIONS\n----------\n")
+		for _, c := range conditions {
+			fmt.Fprintf(&b, "  %s\n", c)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", strings.Repeat("=", 80))
+	return b.String()
+}
+
+// describeLatency はゾーン・ノードの間の遅延を説明する
+func describeLatency(m cluster.LatencyMatrix) string {
+	desc := fmt.Sprintf("%v intra-zone and %v inter-zone latency", m.IntraZone, m.InterZone)
+	if len(m.Links) > 0 {
+		desc += fmt.Sprintf(" with %d link override(s)", len(m.Links))
+	}
+	if m.Coordinator != "" {
+		desc += ", requests forwarded from " + m.Coordinator
+	}
+	return desc
+}
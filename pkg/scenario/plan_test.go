@@ -0,0 +1,292 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/autoscale"
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/external"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/toxiproxy"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+)
+
+func TestNewPlan(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Duration = 10 * time.Second
+	cfg.NodeCount = 3
+	cfg.Zones = []string{"zone-a", "zone-b"}
+	cfg.ChaosInterval = 3 * time.Second
+	cfg.AttackTypes = []chaos.AttackType{chaos.AttackKill}
+	cfg.RESPNodePort = 7001
+	cfg.NodeHTTP = true
+	cfg.NodeHTTPPort = 8101
+
+	p := NewPlan(cfg)
+
+	want := []PlannedNode{
+		{ID: "node-1", Zone: "zone-a", RESP: ":7001", HTTP: "127.0.0.1:8101"},
+		{ID: "node-2", Zone: "zone-b", RESP: ":7002", HTTP: "127.0.0.1:8102"},
+		{ID: "node-3", Zone: "zone-a", RESP: ":7003", HTTP: "127.0.0.1:8103"},
+	}
+	if len(p.Nodes) != len(want) {
+		t.Fatalf("expected %d nodes, got %d", len(want), len(p.Nodes))
+	}
+	for i, n := range want {
+		if p.Nodes[i] != n {
+			t.Errorf("node %d: expected %+v, got %+v", i, n, p.Nodes[i])
+		}
+	}
+
+	attacks := []time.Duration{3 * time.Second, 6 * time.Second, 9 * time.Second}
+	if len(p.Attacks) != len(attacks) {
+		t.Fatalf("expected attacks at %v, got %v", attacks, p.Attacks)
+	}
+	for i, at := range attacks {
+		if p.Attacks[i] != at {
+			t.Errorf("attack %d: expected %v, got %v", i, at, p.Attacks[i])
+		}
+	}
+
+	var names []string
+	for _, ph := range p.Phases {
+		names = append(names, ph.Name)
+	}
+	if got := strings.Join(names, ","); got != "setup,load,chaos,recovery,teardown" {
+		t.Errorf("unexpected phases: %s", got)
+	}
+
+	report := p.Report()
+	for _, s := range []string{"SCENARIO PLAN: default", "3 attacks every 3s", "node-2               zone=zone-b resp=:7002"} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report should contain %q:\n%s", s, report)
+		}
+	}
+}
+
+func TestNewPlanWithoutChaos(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.EnableChaos = false
+	cfg.EnableRecovery = false
+
+	p := NewPlan(cfg)
+	if len(p.Attacks) != 0 {
+		t.Errorf("expected no attacks, got %v", p.Attacks)
+	}
+	if len(p.Phases) != 3 {
+		t.Errorf("expected setup, load and teardown phases, got %+v", p.Phases)
+	}
+	if strings.Contains(p.Report(), "SCHEDULED ATTACKS") {
+		t.Error("report should omit attacks when chaos is disabled")
+	}
+}
+
+func TestNewPlanChaosReplay(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Duration = 10 * time.Second
+	cfg.ChaosReplay = []chaos.Attack{
+		{At: 2 * time.Second, NodeID: "node-1", Type: events.AttackTypeKill},
+		{At: 2 * time.Second, NodeID: "node-2", Type: events.AttackTypeKill},
+		{At: 5 * time.Second, NodeID: "node-3", Type: events.AttackTypeSuspend},
+		{At: 12 * time.Second, NodeID: "node-1", Type: events.AttackTypeDelay},
+	}
+
+	p := NewPlan(cfg)
+	if len(p.Attacks) != 2 || p.Attacks[0] != 2*time.Second || p.Attacks[1] != 5*time.Second {
+		t.Errorf("expected the recorded attack times before the end, got %v", p.Attacks)
+	}
+	report := p.Report()
+	for _, s := range []string{"2 attacks replayed from 4 recorded attack(s)", "replayed from the recorded attacks"} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report should contain %q:\n%s", s, report)
+		}
+	}
+}
+
+func TestNewPlanNodeLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.NodeLimits = node.Limits{MaxKeys: 100, MaxBytes: 4096}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "LRU eviction above 100 keys or 4096 bytes per node") {
+		t.Errorf("expected the setup to mention the node limits, got %q", setup)
+	}
+	cfg.NodeLimits = node.Limits{MaxMemory: 1 << 20}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "writes refused above 1048576 bytes of memory per node") || strings.Contains(setup, "LRU") {
+		t.Errorf("expected the setup to mention the memory limit only, got %q", setup)
+	}
+}
+
+func TestNewPlanNodeAdmission(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.NodeAdmission = node.Admission{MaxInFlight: 64, ReservedReads: 16}
+	want := "load shedding above 64 in-flight operations per node (16 reserved for reads)"
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, want) {
+		t.Errorf("expected the setup to mention the admission control, got %q", setup)
+	}
+}
+
+func TestNewPlanDurability(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Durability = node.Durability{Mode: node.DurabilitySnapshot}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "node snapshots every 1s reloaded on restart") {
+		t.Errorf("expected the setup to mention the snapshots, got %q", setup)
+	}
+	cfg.Durability = node.Durability{Mode: node.DurabilityEphemeral}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "ephemeral nodes") {
+		t.Errorf("expected the setup to mention the ephemeral nodes, got %q", setup)
+	}
+	cfg.Durability = node.Durability{Mode: node.DurabilityWAL, Sync: node.WALSyncAlways}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "write-ahead logs synced on every write") {
+		t.Errorf("expected the setup to mention the write-ahead logs, got %q", setup)
+	}
+	cfg.Durability = node.Durability{EphemeralLossRatio: 0.25}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "nodes that lose 25% of their keys on restart") {
+		t.Errorf("expected the setup to mention the loss ratio, got %q", setup)
+	}
+}
+
+func TestNewPlanAutoscale(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Autoscale = autoscale.Config{MaxNodes: 8}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "autoscaled between 1 and 8 nodes") {
+		t.Errorf("expected the setup to mention the autoscaling, got %q", setup)
+	}
+}
+
+func TestNewPlanStore(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Store = node.StoreCRDT
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "CRDT stores synced every 1s") {
+		t.Errorf("expected the setup to mention the store, got %q", setup)
+	}
+}
+
+func TestNewPlanLatency(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Latency = cluster.LatencyMatrix{
+		IntraZone:   time.Millisecond,
+		InterZone:   80 * time.Millisecond,
+		Links:       []cluster.Link{{From: "a", To: "b", Delay: 150 * time.Millisecond}},
+		Coordinator: "a",
+	}
+	want := "1ms intra-zone and 80ms inter-zone latency with 1 link override(s), requests forwarded from a"
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, want) {
+		t.Errorf("expected the setup to mention the latency, got %q", setup)
+	}
+}
+
+func TestNewPlanBatchSize(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BatchSize = 16
+	if load := NewPlan(cfg).Phases[1].Description; !strings.Contains(load, "batches of 16 keys") {
+		t.Errorf("expected the load to mention the batch size, got %q", load)
+	}
+}
+
+func TestNewPlanScanRatio(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ScanRatio = 0.1
+	if load := NewPlan(cfg).Phases[1].Description; !strings.Contains(load, "10% prefix scans of up to 100 keys") {
+		t.Errorf("expected the load to mention the scans, got %q", load)
+	}
+}
+
+func TestNewPlanReplicationFactor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ReplicationFactor = 3
+	cfg.Consistency = cluster.Consistency{Write: cluster.ConsistencyQuorum}
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "keys written to 3 replicas (W=quorum R=one)") {
+		t.Errorf("expected the setup to mention the replicas, got %q", setup)
+	}
+}
+
+func TestNewPlanNamespaces(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Namespaces = []string{"tenant-a", "tenant-b"}
+	if load := NewPlan(cfg).Phases[1].Description; !strings.Contains(load, "spread across namespaces tenant-a, tenant-b") {
+		t.Errorf("expected the load to mention the namespaces, got %q", load)
+	}
+}
+
+func TestNewPlanToxiproxy(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.NodeHTTP = true
+	cfg.Toxiproxy = toxiproxy.DefaultConfig()
+
+	load := NewPlan(cfg).Phases[1]
+	if load.Name != "load" || !strings.Contains(load.Description, "over loopback HTTP, through toxiproxy at http://127.0.0.1:8474") {
+		t.Errorf("unexpected load phase: %+v", load)
+	}
+}
+
+func TestNewPlanNodeProcess(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.NodeCount = 2
+	cfg.NodeProcess = true
+
+	p := NewPlan(cfg)
+	if setup := p.Phases[0].Description; !strings.Contains(setup, "2 nodes created and started as separate processes") {
+		t.Errorf("unexpected setup phase: %s", setup)
+	}
+	if load := p.Phases[1].Description; !strings.Contains(load, "over HTTP to node processes") {
+		t.Errorf("unexpected load phase: %s", load)
+	}
+	if p.Nodes[0].HTTP != "127.0.0.1:0" {
+		t.Errorf("expected node process address, got %q", p.Nodes[0].HTTP)
+	}
+}
+
+func TestNewPlanTracing(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Tracing = tracing.DefaultConfig()
+
+	if setup := NewPlan(cfg).Phases[0].Description; !strings.Contains(setup, "spans exported over OTLP to http://127.0.0.1:4318") {
+		t.Errorf("unexpected setup phase: %s", setup)
+	}
+}
+
+func TestNewPlanLinearizability(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Linearizability = true
+
+	p := NewPlan(cfg)
+	teardown := p.Phases[len(p.Phases)-1]
+	if teardown.Name != "teardown" || !strings.Contains(teardown.Description, "up to 100000 recorded operations") {
+		t.Errorf("unexpected teardown phase: %+v", teardown)
+	}
+
+	cfg.HistoryLimit = 500
+	if detail := NewPlan(cfg).Phases[len(p.Phases)-1].Description; !strings.Contains(detail, "up to 500 recorded") {
+		t.Errorf("unexpected teardown description: %s", detail)
+	}
+}
+
+func TestNewPlanExternal(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Zones = []string{"zone-a", "zone-b"}
+	cfg.External = []external.Config{
+		{ID: "redis-10", Addr: "127.0.0.1:6381"},
+		{ID: "redis-2", Addr: "127.0.0.1:6380"},
+	}
+
+	p := NewPlan(cfg)
+
+	want := []PlannedNode{
+		{ID: "redis-2", Zone: "zone-a", External: "127.0.0.1:6380"},
+		{ID: "redis-10", Zone: "zone-b", External: "127.0.0.1:6381"},
+	}
+	if len(p.Nodes) != len(want) {
+		t.Fatalf("expected %d nodes, got %+v", len(want), p.Nodes)
+	}
+	for i, n := range want {
+		if p.Nodes[i] != n {
+			t.Errorf("node %d: expected %+v, got %+v", i, n, p.Nodes[i])
+		}
+	}
+	if report := p.Report(); !strings.Contains(report, "external=127.0.0.1:6380") {
+		t.Errorf("expected external address in report:\n%s", report)
+	}
+}
@@ -3,7 +3,7 @@ package scenario
 import (
 	"time"
 
-	"chaos-kvs/internal/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
 )
 
 // BasicScenario は基本的なシナリオ設定を返す
@@ -0,0 +1,104 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// DefaultSyncInterval は StoreCRDT のノードの状態をマージするデフォルトの間隔
+const DefaultSyncInterval = time.Second
+
+// ReplicaReport はノードのデータの保持方法と、ノードの間のデータの収束の状況
+// 負荷生成はリクエストごとにノードを選ぶため、同じキーが複数のノードに書き込まれる
+type ReplicaReport struct {
+	Store         node.StoreKind `json:"store"`
+	Syncs         uint64         `json:"syncs"`          // 実行した同期（アンチエントロピー）の回数
+	Merged        uint64         `json:"merged"`         // 同期で値が変わったキーの数の合計
+	Conflicts     uint64         `json:"conflicts"`      // 同期で値の異なる書き込みを LWW で解決した回数
+	Keys          int            `json:"keys"`           // 終了時に稼働中のノードのいずれかにあるキーの数
+	DivergentKeys int            `json:"divergent_keys"` // 終了時に稼働中のノードの間で値が一致しないキーの数
+}
+
+// syncInterval は同期の間隔を返す（0以下で同期しない）
+func (e *Engine) syncInterval() time.Duration {
+	if e.config.SyncInterval == 0 {
+		return DefaultSyncInterval
+	}
+	return e.config.SyncInterval
+}
+
+// syncReplicas は終了まで一定間隔で稼働中のノードの状態をマージする
+// 停止・一時停止したノードは同期に参加せず、復旧後の同期で追いつく
+// ノードの間に遅延がある場合は、全ての状態が届くまで次の同期を始めない
+func (e *Engine) syncReplicas(ctx context.Context, interval time.Duration) {
+	ticker := e.config.Clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+			changed, err := e.cluster.SyncContext(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return // 終了による配送の中断
+				}
+				log.Warn("", "Failed to sync replicas: %v", err)
+				continue
+			}
+			e.syncs.Add(1)
+			e.merged.Add(uint64(changed))
+		}
+	}
+}
+
+// replicaReport はノードの間のデータの収束の状況をまとめる
+// 計測中に書き込まれないようにクライアントを先に停止する
+func (e *Engine) replicaReport() *ReplicaReport {
+	e.client.Stop()
+
+	store := e.config.Store
+	if store == "" {
+		store = node.StoreMap
+	}
+	report := &ReplicaReport{
+		Store:         store,
+		Syncs:         e.syncs.Load(),
+		Merged:        e.merged.Load(),
+		DivergentKeys: e.cluster.Divergence(),
+	}
+	keys := make(map[string]struct{})
+	for _, n := range e.cluster.Nodes() {
+		report.Conflicts += n.Stats().Conflicts
+		if n.Status() != node.StatusRunning {
+			continue
+		}
+		for _, key := range n.Keys() {
+			keys[key] = struct{}{}
+		}
+	}
+	report.Keys = len(keys)
+	return report
+}
+
+// replicaRows はデータの保持方法と収束の状況を項目にする
+func (r *Result) replicaRows() [][2]string {
+	rep := r.Replicas
+	rows := [][2]string{{"Store", string(rep.Store)}}
+	if rep.Store == node.StoreCRDT {
+		rows = append(rows,
+			[2]string{"Syncs", fmt.Sprint(rep.Syncs)},
+			[2]string{"Merged Keys", fmt.Sprint(rep.Merged)},
+			[2]string{"Conflicts", fmt.Sprint(rep.Conflicts)},
+		)
+	}
+	divergent := fmt.Sprint(rep.DivergentKeys)
+	if rep.Keys > 0 {
+		divergent = fmt.Sprintf("%d of %d (%.2f%%)", rep.DivergentKeys, rep.Keys, float64(rep.DivergentKeys)/float64(rep.Keys)*100)
+	}
+	return append(rows, [2]string{"Divergent Keys", divergent})
+}
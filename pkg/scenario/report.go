@@ -0,0 +1,285 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportFormat はレポートの出力形式
+type ReportFormat string
+
+const (
+	ReportText     ReportFormat = "text"
+	ReportJSON     ReportFormat = "json"
+	ReportMarkdown ReportFormat = "md"
+	ReportHTML     ReportFormat = "html"
+)
+
+// ParseReportFormat は出力形式名を解析する（空の場合は text、markdown は md の別名）
+func ParseReportFormat(s string) (ReportFormat, error) {
+	switch strings.ToLower(s) {
+	case "", "text", "txt":
+		return ReportText, nil
+	case "json":
+		return ReportJSON, nil
+	case "md", "markdown":
+		return ReportMarkdown, nil
+	case "html":
+		return ReportHTML, nil
+	default:
+		return "", fmt.Errorf("unknown report format: %s (expected text, json, md or html)", s)
+	}
+}
+
+// ContentType は出力形式のMIMEタイプを返す
+func (f ReportFormat) ContentType() string {
+	switch f {
+	case ReportJSON:
+		return "application/json"
+	case ReportMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ReportHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Extension はレポートを保存する際のファイル拡張子を返す
+func (f ReportFormat) Extension() string {
+	if f == ReportText {
+		return "txt"
+	}
+	return string(f)
+}
+
+// WriteReport は指定した形式でレポートを書き出す
+func (r *Result) WriteReport(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportText:
+		_, err := io.WriteString(w, r.Report()+"\n")
+		return err
+	case ReportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case ReportMarkdown:
+		_, err := io.WriteString(w, r.Markdown())
+		return err
+	case ReportHTML:
+		return htmlReport.Execute(w, r.reportView())
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// Markdown は結果をMarkdownの表でフォーマットして返す
+func (r *Result) Markdown() string {
+	v := r.reportView()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Scenario Report: %s\n\n", v.Name)
+	for _, section := range v.Sections {
+		fmt.Fprintf(&b, "## %s\n\n| Item | Value |\n| --- | --- |\n", section.Title)
+		for _, row := range section.Rows {
+			fmt.Fprintf(&b, "| %s | %s |\n", row[0], escapeMarkdown(row[1]))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// escapeMarkdown は表のセルを壊す文字をエスケープする
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// reportSection はレポートの1セクション（項目と値の組）
+type reportSection struct {
+	Title string
+	Rows  [][2]string
+}
+
+// reportView はMarkdown・HTMLで共通のレポート内容
+type reportView struct {
+	Name     string
+	Sections []reportSection
+}
+
+// reportView はテキストレポートと同じ項目をセクションごとにまとめる
+func (r *Result) reportView() reportView {
+	view := reportView{
+		Name: r.ScenarioName,
+		Sections: []reportSection{
+			{"Execution Summary", [][2]string{
+				{"Start Time", r.StartTime.Format("2006-01-02 15:04:05")},
+				{"End Time", r.EndTime.Format("2006-01-02 15:04:05")},
+				{"Duration", r.Duration.Round(time.Millisecond).String()},
+				{"Status", r.status()},
+			}},
+			{"Traffic Metrics", [][2]string{
+				{"Total Requests", fmt.Sprint(r.TotalRequests)},
+				{"Success", fmt.Sprint(r.SuccessRequests)},
+				{"Failed", fmt.Sprint(r.FailedRequests)},
+				{"Error Rate", fmt.Sprintf("%.2f%%", r.ErrorRate*100)},
+				{"Avg Latency", r.AvgLatency.Round(time.Microsecond).String()},
+				{"P99 Latency", r.P99Latency.Round(time.Microsecond).String()},
+			}},
+			{"Chaos Statistics", [][2]string{
+				{"Total Attacks", fmt.Sprint(r.TotalAttacks)},
+			}},
+			{"Recovery Statistics", [][2]string{
+				{"Total Recoveries", fmt.Sprint(r.TotalRecoveries)},
+				{"Successful", fmt.Sprint(r.SuccessRecoveries)},
+				{"Failed", fmt.Sprint(r.FailedRecoveries)},
+			}},
+		},
+	}
+	if r.CapacityFailures > 0 {
+		traffic := &view.Sections[1]
+		traffic.Rows = slices.Insert(traffic.Rows, 3, [2]string{"Capacity Failures", fmt.Sprint(r.CapacityFailures)})
+	}
+	if r.TraceID != "" {
+		summary := &view.Sections[0]
+		summary.Rows = append(summary.Rows, [2]string{"Trace ID", r.TraceID})
+	}
+	if len(r.FinalNodeStatus) > 0 {
+		nodes := make([][2]string, 0, len(r.FinalNodeStatus))
+		for _, id := range r.sortedNodeIDs() {
+			nodes = append(nodes, [2]string{id, r.FinalNodeStatus[id]})
+		}
+		view.Sections = append(view.Sections, reportSection{"Final Node Status", nodes})
+	}
+	if len(r.AssertionFailures) > 0 {
+		rows := make([][2]string, 0, len(r.AssertionFailures))
+		for _, f := range r.AssertionFailures {
+			rows = append(rows, [2]string{f.Metric, f.String()})
+		}
+		view.Sections = append(view.Sections, reportSection{"Assertions Failed", rows})
+	}
+	if len(r.Steps) > 0 {
+		view.Sections = append(view.Sections, reportSection{"Steps", r.stepRows()})
+	}
+	if r.HotKeys != nil {
+		view.Sections = append(view.Sections, reportSection{"Hot Keys", r.hotKeyRows()})
+	}
+	if r.Scans != nil {
+		view.Sections = append(view.Sections, reportSection{"Scans", r.scanRows()})
+	}
+	if r.Durability != nil {
+		view.Sections = append(view.Sections, reportSection{"Durability", r.durabilityRows()})
+	}
+	if r.Replicas != nil {
+		view.Sections = append(view.Sections, reportSection{"Replicas", r.replicaRows()})
+	}
+	if r.Autoscale != nil {
+		view.Sections = append(view.Sections, reportSection{"Autoscale", r.autoscaleRows()})
+	}
+	if r.Linearizability != nil {
+		view.Sections = append(view.Sections, reportSection{"Linearizability", r.linearizabilityRows()})
+	}
+	if len(r.RecentWarnings) > 0 {
+		view.Sections = append(view.Sections, reportSection{"Recent Warnings", r.warningRows()})
+	}
+	return view
+}
+
+// warningRows は直近の警告ログを「時刻 レベル [ノード]」とメッセージの組にする
+func (r *Result) warningRows() [][2]string {
+	rows := make([][2]string, 0, len(r.RecentWarnings))
+	for _, e := range r.RecentWarnings {
+		label := fmt.Sprintf("%s %-5s", e.Time.Format("15:04:05.000"), e.Level)
+		if e.NodeID != "" {
+			label += " [" + e.NodeID + "]"
+		}
+		rows = append(rows, [2]string{label, e.Message})
+	}
+	return rows
+}
+
+// linearizabilityRows は線形化可能性の検査結果と違反したノード・キーの組を項目にする
+func (r *Result) linearizabilityRows() [][2]string {
+	check := r.Linearizability
+	status := "linearizable"
+	switch {
+	case !check.Linearizable:
+		status = "violated"
+	case check.Unknown > 0:
+		status = "unknown"
+	}
+	operations := fmt.Sprint(check.Operations)
+	if check.Truncated {
+		operations += " (history truncated)"
+	}
+
+	rows := [][2]string{
+		{"Status", status},
+		{"Operations", operations},
+		{"Keys Checked", fmt.Sprint(check.Partitions)},
+		{"Violated Keys", fmt.Sprint(check.ViolatedKeys)},
+		{"Unknown Keys", fmt.Sprint(check.Unknown)},
+	}
+	for _, v := range check.Violations {
+		rows = append(rows, [2]string{v.Node + "/" + v.Key, fmt.Sprintf("%d operations", v.TotalOperations)})
+	}
+	return rows
+}
+
+// hotKeyRows はホットキーの集計と、アクセス数の多いキーごとの「アクセス数（割合）・失敗数・主なノード」を項目にする
+func (r *Result) hotKeyRows() [][2]string {
+	hot := r.HotKeys
+	rows := [][2]string{
+		{"Sampled Requests", fmt.Sprintf("%d (%.0f%%)", hot.Sampled, hot.SampleRate*100)},
+		{"Distinct Keys", fmt.Sprint(hot.DistinctKeys)},
+		{"Skew (Gini)", fmt.Sprintf("%.3f", hot.Skew)},
+	}
+	for _, k := range hot.TopKeys {
+		rows = append(rows, [2]string{k.Key, fmt.Sprintf("%d (%.2f%%), %d failed, mostly %s",
+			k.Accesses, k.Share*100, k.Failures, k.TopNode)})
+	}
+	return rows
+}
+
+// sortedNodeIDs は最終状態のノードIDを番号順に返す
+func (r *Result) sortedNodeIDs() []string {
+	ids := make([]string, 0, len(r.FinalNodeStatus))
+	for id := range r.FinalNodeStatus {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) < len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// htmlReport は単体で閲覧できるHTMLレポートのテンプレート
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Scenario Report: {{.Name}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 2rem; color: #1f2937; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; min-width: 24rem; }
+th, td { border: 1px solid #d1d5db; padding: 0.3rem 0.8rem; text-align: left; }
+th { background: #f3f4f6; }
+</style>
+</head>
+<body>
+<h1>Scenario Report: {{.Name}}</h1>
+{{range .Sections}}<h2>{{.Title}}</h2>
+<table>
+{{range .Rows}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
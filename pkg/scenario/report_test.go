@@ -0,0 +1,222 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/client"
+	"github.com/nyasuto/chaos-kvs/pkg/lincheck"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+func testResult() *Result {
+	return &Result{
+		ScenarioName:    "test<1>",
+		StartTime:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		EndTime:         time.Date(2026, 1, 2, 3, 4, 15, 0, time.UTC),
+		Duration:        10 * time.Second,
+		TotalRequests:   1000,
+		SuccessRequests: 990,
+		FailedRequests:  10,
+		ErrorRate:       0.01,
+		TotalAttacks:    5,
+		FinalNodeStatus: map[string]string{
+			"node-10": "Stopped",
+			"node-2":  "Running",
+		},
+	}
+}
+
+func TestParseReportFormat(t *testing.T) {
+	tests := map[string]ReportFormat{
+		"":         ReportText,
+		"text":     ReportText,
+		"JSON":     ReportJSON,
+		"markdown": ReportMarkdown,
+		"html":     ReportHTML,
+	}
+	for input, want := range tests {
+		if got, err := ParseReportFormat(input); err != nil || got != want {
+			t.Errorf("ParseReportFormat(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseReportFormat("pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if ext := ReportText.Extension(); ext != "txt" {
+		t.Errorf("expected txt extension, got %s", ext)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	result := testResult()
+
+	for _, format := range []ReportFormat{ReportText, ReportJSON, ReportMarkdown, ReportHTML} {
+		var buf bytes.Buffer
+		if err := result.WriteReport(&buf, format); err != nil {
+			t.Fatalf("%s: failed to write report: %v", format, err)
+		}
+		out := buf.String()
+
+		if !strings.Contains(out, "1000") {
+			t.Errorf("%s: report should contain total requests", format)
+		}
+		// ノードは番号順に並ぶ
+		if format != ReportJSON && strings.Index(out, "node-2") > strings.Index(out, "node-10") {
+			t.Errorf("%s: expected node-2 before node-10", format)
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = result.WriteReport(&buf, ReportJSON)
+	var decoded Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.TotalAttacks != 5 {
+		t.Errorf("expected JSON report to round-trip, got %+v, %v", decoded, err)
+	}
+
+	buf.Reset()
+	_ = result.WriteReport(&buf, ReportMarkdown)
+	if !strings.Contains(buf.String(), "| Error Rate | 1.00% |") {
+		t.Errorf("unexpected markdown report:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	_ = result.WriteReport(&buf, ReportHTML)
+	if !strings.Contains(buf.String(), "test&lt;1&gt;") {
+		t.Error("expected scenario name to be escaped in HTML report")
+	}
+
+	if err := result.WriteReport(&buf, ReportFormat("pdf")); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestReportRecentWarnings(t *testing.T) {
+	result := testResult()
+	if strings.Contains(result.Report(), "RECENT WARNINGS") {
+		t.Error("expected no warnings section without warnings")
+	}
+
+	result.RecentWarnings = []logger.Entry{{
+		Time:    time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC),
+		Level:   logger.LevelWarn,
+		NodeID:  "node-2",
+		Message: "ChaosMonkey: killed node node-2",
+	}}
+	if report := result.Report(); !strings.Contains(report, "RECENT WARNINGS") ||
+		!strings.Contains(report, "03:04:06.000 WARN  [node-2]  ChaosMonkey: killed node node-2") {
+		t.Errorf("unexpected text report:\n%s", report)
+	}
+	if md := result.Markdown(); !strings.Contains(md, "## Recent Warnings") {
+		t.Errorf("unexpected markdown report:\n%s", md)
+	}
+}
+
+func TestReportLinearizability(t *testing.T) {
+	result := testResult()
+	if strings.Contains(result.Report(), "LINEARIZABILITY") {
+		t.Error("expected no linearizability section without a check")
+	}
+
+	result.Linearizability = &lincheck.CheckResult{
+		Operations:   1000,
+		Partitions:   20,
+		ViolatedKeys: 1,
+		Violations:   []lincheck.Violation{{Node: "node-1", Key: "key-7", TotalOperations: 12}},
+		Truncated:    true,
+	}
+	report := result.Report()
+	for _, want := range []string{"LINEARIZABILITY", "Status:              violated", "1000 (history truncated)", "node-1/key-7:        12 operations"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected text report to contain %q:\n%s", want, report)
+		}
+	}
+	if md := result.Markdown(); !strings.Contains(md, "## Linearizability") || !strings.Contains(md, "| node-1/key-7 | 12 operations |") {
+		t.Errorf("unexpected markdown report:\n%s", md)
+	}
+
+	summary := result.Summary()
+	if summary.Linearizability.Violations != nil || summary.Linearizability.ViolatedKeys != 1 {
+		t.Errorf("expected summary to keep counts only, got %+v", summary.Linearizability)
+	}
+	if len(result.Linearizability.Violations) != 1 {
+		t.Error("Summary should not modify the original result")
+	}
+}
+
+func TestReportHotKeys(t *testing.T) {
+	result := testResult()
+	if strings.Contains(result.Report(), "HOT KEYS") {
+		t.Error("expected no hot key section without sampling")
+	}
+
+	result.HotKeys = &client.HotKeyReport{
+		SampleRate:   0.1,
+		Sampled:      500,
+		DistinctKeys: 120,
+		Skew:         0.4567,
+		TopKeys:      []client.HotKey{{Key: "key-42", Accesses: 50, Share: 0.1, Failures: 3, TopNode: "node-2"}},
+	}
+	report := result.Report()
+	for _, want := range []string{"HOT KEYS", "Sampled Requests:    500 (10%)", "Skew (Gini):         0.457", "key-42:              50 (10.00%), 3 failed, mostly node-2"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected text report to contain %q:\n%s", want, report)
+		}
+	}
+	if md := result.Markdown(); !strings.Contains(md, "## Hot Keys") || !strings.Contains(md, "| key-42 |") {
+		t.Errorf("unexpected markdown report:\n%s", md)
+	}
+
+	summary := result.Summary()
+	if summary.HotKeys.TopKeys != nil || summary.HotKeys.Skew != 0.4567 {
+		t.Errorf("expected summary to keep the totals only, got %+v", summary.HotKeys)
+	}
+	if len(result.HotKeys.TopKeys) != 1 {
+		t.Error("Summary should not modify the original result")
+	}
+}
+
+func TestResultSummary(t *testing.T) {
+	result := testResult()
+	result.RecentWarnings = []logger.Entry{{Level: logger.LevelWarn, Message: "warning"}}
+
+	summary := result.Summary()
+	if summary.TotalRequests != 1000 || summary.FinalNodeStatus != nil || summary.RecentWarnings != nil {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if len(result.FinalNodeStatus) != 2 {
+		t.Error("Summary should not modify the original result")
+	}
+
+	for _, format := range []ReportFormat{ReportText, ReportMarkdown} {
+		var buf bytes.Buffer
+		if err := summary.WriteReport(&buf, format); err != nil {
+			t.Fatalf("%s: failed to write report: %v", format, err)
+		}
+		out := strings.ToLower(buf.String())
+		if strings.Contains(out, "final node status") || strings.Contains(out, "node-2") {
+			t.Errorf("%s: summary report should not contain node status:\n%s", format, buf.String())
+		}
+		if !strings.Contains(out, "traffic metrics") {
+			t.Errorf("%s: summary report should contain traffic metrics", format)
+		}
+	}
+}
+
+func TestReportTraceID(t *testing.T) {
+	result := testResult()
+	if strings.Contains(result.Report(), "Trace ID") {
+		t.Error("expected no trace ID without tracing")
+	}
+
+	result.TraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	if report := result.Report(); !strings.Contains(report, "Trace ID:       4bf92f3577b34da6a3ce929d0e0e4736\n\nTRAFFIC METRICS") {
+		t.Errorf("expected trace ID in the execution summary:\n%s", report)
+	}
+	if md := result.Markdown(); !strings.Contains(md, "| Trace ID | 4bf92f3577b34da6a3ce929d0e0e4736 |") {
+		t.Errorf("unexpected markdown report:\n%s", md)
+	}
+}
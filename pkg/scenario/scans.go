@@ -0,0 +1,49 @@
+package scenario
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/client"
+)
+
+// ScanReport はキーの前方一致のスキャンのリクエストだけのメトリクス
+// スキャンは読み取りロックを長く保持するため、点の読み書きとは別に遅延注入の影響を比べる
+type ScanReport struct {
+	Limit      int           `json:"limit"` // 1回のスキャンで返すキーの上限
+	Requests   uint64        `json:"requests"`
+	Failed     uint64        `json:"failed"`
+	AvgLatency time.Duration `json:"avg_latency"`
+	P99Latency time.Duration `json:"p99_latency"`
+}
+
+// scanReport はクライアントのスキャンのメトリクスをまとめる（スキャンを送っていない場合は nil）
+func (e *Engine) scanReport() *ScanReport {
+	m := e.client.ScanMetrics()
+	if m == nil {
+		return nil
+	}
+	limit := e.config.ScanLimit
+	if limit <= 0 {
+		limit = client.DefaultScanLimit
+	}
+	return &ScanReport{
+		Limit:      limit,
+		Requests:   m.TotalRequests(),
+		Failed:     m.FailedRequests(),
+		AvgLatency: m.AverageLatency(),
+		P99Latency: m.P99Latency(),
+	}
+}
+
+// scanRows はスキャンのメトリクスを項目にする
+func (r *Result) scanRows() [][2]string {
+	s := r.Scans
+	return [][2]string{
+		{"Limit", fmt.Sprintf("%d keys", s.Limit)},
+		{"Requests", fmt.Sprint(s.Requests)},
+		{"Failed", fmt.Sprint(s.Failed)},
+		{"Avg Latency", s.AvgLatency.String()},
+		{"P99 Latency", s.P99Latency.String()},
+	}
+}
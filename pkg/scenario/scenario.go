@@ -0,0 +1,1466 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/nodehttp"
+	"github.com/nyasuto/chaos-kvs/internal/procnode"
+	"github.com/nyasuto/chaos-kvs/internal/resp"
+	"github.com/nyasuto/chaos-kvs/pkg/autoscale"
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/client"
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/external"
+	"github.com/nyasuto/chaos-kvs/pkg/lincheck"
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/recovery"
+	"github.com/nyasuto/chaos-kvs/pkg/toxiproxy"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+	"github.com/nyasuto/chaos-kvs/pkg/worker"
+)
+
+// log はコンポーネント名 "scenario" を付けてログを出力する子ロガー
+var log = logger.With("scenario")
+
+// Config はシナリオの設定
+type Config struct {
+	Name        string        // シナリオ名
+	Description string        // 説明
+	Duration    time.Duration // 実行時間
+	NodeCount   int           // ノード数
+	Zones       []string      // ノードを割り当てるゾーン（空で割り当てなし）
+	NodeLimits  node.Limits   // ノードごとのデータの上限（ゼロ値で無制限、超えると最も長く使われていないキーを削除する）
+
+	// NodeAdmission はノードごとに同時に処理する操作の上限（ゼロ値で無制限）
+	// 上限を超えた操作は待たせずに node.ErrOverloaded で失敗させ、過負荷を実際のサーバーのように再現する
+	NodeAdmission node.Admission
+
+	// Store はノードのデータの保持方法（空で node.StoreMap）
+	// 空でない場合は終了時のノードの間のデータの収束の状況を Result.Replicas に含め、保持方法による違いを比べられる
+	Store node.StoreKind
+
+	// SyncInterval は Store が node.StoreCRDT の場合に稼働中のノードの状態をマージする間隔
+	// （0で DefaultSyncInterval、負の値で同期しない）
+	SyncInterval time.Duration
+
+	// ReplicationFactor は負荷生成の書き込みでキーを書き込むノードの数（0・1で担当ノードのみ）
+	// キーは担当ノードから cluster.Router のリングに並ぶノードに書き込み、読み取りはそのどれかから行う
+	ReplicationFactor int
+
+	// Consistency は ReplicationFactor のノードへの読み書きで応答を待つノードの数（ゼロ値で書き込み・読み取りとも1つ）
+	// レプリカを停止させる攻撃の下で ONE・QUORUM・ALL の可用性と整合性を比べる
+	Consistency cluster.Consistency
+
+	// Durability はノードのデータの永続性（ゼロ値で停止してもメモリ上のデータを保持する）
+	// node.DurabilitySnapshot では Path をディレクトリとしてノードごとにスナップショットを書き出し（空で一時ディレクトリ）、
+	// kill による停止で失ったキーの数を Result.Durability に含める。EphemeralLossRatio は memory のノードが再起動で失うキーの割合
+	Durability node.Durability
+
+	// Latency はゾーン・ノードの間の通信の遅延（ゼロ値で遅延なし）
+	// ノードの状態の同期と、Latency.Coordinator から各ノードへの負荷生成のリクエストの転送に加える
+	Latency cluster.LatencyMatrix
+
+	// クライアント設定
+	ClientWorkers int     // ワーカー数
+	WriteRatio    float64 // 書き込み比率
+	TargetRPS     float64 // 秒間リクエスト数の目標（0でワーカーが処理できるだけ送る）
+	BatchSize     int     // 1リクエストでまとめて読み書きするキーの数（0・1で1キーずつ、Linearizability と併用不可）
+
+	// ScanRatio はキーの前方一致のスキャンとして送るリクエストの割合（0で送らない）
+	// スキャンは最大 ScanLimit 件（0で client.DefaultScanLimit）のキーを読み取り、Result.Scans に別に集計する
+	ScanRatio float64
+	ScanLimit int
+
+	// Namespaces は負荷生成のリクエストを振り分ける名前空間（空で名前空間を付けない）
+	// 複数のテナントが同じノードを共有する状況を再現する
+	Namespaces []string
+
+	// カオス設定
+	EnableChaos   bool               // カオス注入を有効化
+	ChaosInterval time.Duration      // 攻撃間隔
+	ChaosTargets  int                // 同時攻撃対象数
+	AttackTypes   []chaos.AttackType // 有効な攻撃タイプ
+	ChaosReplay   []chaos.Attack     // 再生する攻撃の記録（空で ChaosInterval ごとにランダムに攻撃する）
+
+	// 復旧設定
+	EnableRecovery bool          // 復旧を有効化
+	RecoveryDelay  time.Duration // 復旧までの待機時間
+	MaxRetries     int           // 最大リトライ回数
+
+	// Autoscale は負荷生成のRPS・P99に合わせてノード数を増減する設定（MaxNodes が0で無効、External と併用不可）
+	// 追加したノードの接頭辞と時計はシナリオのものを使い、増減の結果を Result.Autoscale に含める
+	Autoscale autoscale.Config
+
+	// MetricsInterval はメトリクスのスナップショットをイベントとして発行する間隔
+	// （0で DefaultMetricsInterval、負の値で無効）
+	MetricsInterval time.Duration
+
+	// Notifiers は選択したイベントを投稿するSlack・Discordの通知先
+	Notifiers []events.NotifierConfig
+
+	// ReportWarnings は結果に含める実行中の直近の警告以上のログの件数（0で含めない）
+	// ログは logger.Recent から取得するため、同時に実行中の他のシナリオのログも含まれうる
+	ReportWarnings int
+
+	// Assertions は実行結果が満たすべき条件
+	Assertions Assertions
+
+	// RESPAddr はクラスタ全体のRESP（Redisプロトコル）リスナーのアドレス（空で無効）
+	// キーのハッシュで担当ノードに振り分けるため、redis-cli や redis-benchmark から操作できる
+	RESPAddr string
+
+	// RESPNodePort はノードごとのRESPリスナーの開始ポート（0で無効）
+	// node-N は RESPNodePort+N-1 で待ち受ける（実行中に追加したノードには作成しない）
+	RESPNodePort int
+
+	// NodeHTTP はノードごとにループバックのHTTPサーバーを立て、負荷生成のリクエストをTCP経由で送るか
+	// 停止したノードへの接続はリセットされ、注入した遅延は実際のネットワークの遅延として現れる
+	// （実行中に追加したノードには作成せず、直接呼び出す）
+	NodeHTTP bool
+
+	// NodeHTTPPort はノードごとのHTTPサーバーの開始ポート（0で空いているポート）
+	// node-N は 127.0.0.1:NodeHTTPPort+N-1 で待ち受ける
+	NodeHTTPPort int
+
+	// NodeProcess はノードごとに子プロセスを起動してHTTPで公開し、負荷生成のリクエストをそれらに送るか
+	// kill 攻撃はプロセスを強制終了し、復旧はプロセスを再起動する（メモリ上のデータは失われる）
+	// （実行中に追加したノードにはプロセスを作成せず、直接呼び出す）
+	NodeProcess bool
+
+	// NodeCommand はノードの子プロセスのコマンドと引数（--id・--addr を追加して実行する）
+	// 空の場合は実行中のバイナリの "node-server" サブコマンドを使う
+	NodeCommand []string
+
+	// External はシェルコマンドで操作する外部のプロセス（Redis のコンテナなど）をノードとして使う設定
+	// 指定した場合は NodeCount の代わりにこれらのノードでクラスタを構成し、負荷生成はRESPで各プロセスに送る
+	// 終了時には停止・一時停止したプロセスを起動・再開し、注入した遅延を解除する
+	External []external.Config
+
+	// Toxiproxy はノードへの接続をToxiproxyのプロキシ経由にし、攻撃をソケット層の障害として注入する設定（URL が空で無効）
+	// NodeHTTP・NodeProcess・External でノードをTCPで公開している場合のみ使える
+	Toxiproxy toxiproxy.Config
+
+	// Linearizability は負荷生成の操作の履歴を記録し、終了時に線形化可能性を検査するか
+	// キーは空の状態から始まるとみなすため、外部のノードは空のデータで起動しておく
+	Linearizability bool
+
+	// HistoryLimit は記録する操作の上限（0で lincheck のデフォルト）
+	HistoryLimit int
+
+	// KeySampleRate は負荷生成のリクエストのうちキーごとのアクセス数を記録する割合（0で記録しない）
+	// 記録した場合はアクセス数の多いキーとキー空間の偏りを Result.HotKeys に含める
+	KeySampleRate float64
+
+	// HotKeys は Result.HotKeys に含めるアクセス数の多いキーの数（0で client.DefaultHotKeys）
+	HotKeys int
+
+	// Tracing はシナリオの段階・攻撃・復旧・サンプリングしたリクエストをスパンとしてOTLPで送る設定（Endpoint が空で無効）
+	Tracing tracing.Config
+
+	// Steps は負荷の開始後に順に実行するステップ（空で Duration の間カオスモンキーに任せて負荷をかける）
+	// Duration が0の場合は全てのステップを終えた時点で、正の場合はその経過か全てのステップの終了の早い方で終了する
+	Steps []Step
+
+	// Clock は実行時間・ノードの遅延・攻撃間隔・ヘルスチェック・メトリクスの発行間隔を測る時計（nilで実時間）
+	// clock.Simulated を指定すると、テストは時計を進めるだけで実行を完了できる
+	Clock clock.Clock
+}
+
+// externalPingTimeout は外部のノード・Toxiproxyへの疎通確認の上限時間
+const externalPingTimeout = 5 * time.Second
+
+// DefaultMetricsInterval はメトリクスのスナップショットイベントのデフォルトの発行間隔
+const DefaultMetricsInterval = 5 * time.Second
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Name:           "default",
+		Description:    "Default scenario",
+		Duration:       10 * time.Second,
+		NodeCount:      5,
+		ClientWorkers:  10,
+		WriteRatio:     0.5,
+		EnableChaos:    true,
+		ChaosInterval:  2 * time.Second,
+		ChaosTargets:   1,
+		AttackTypes:    []chaos.AttackType{chaos.AttackKill, chaos.AttackSuspend, chaos.AttackDelay},
+		EnableRecovery: true,
+		RecoveryDelay:  1 * time.Second,
+		MaxRetries:     3,
+	}
+}
+
+// Result はシナリオ実行結果
+type Result struct {
+	ScenarioName string
+	StartTime    time.Time
+	EndTime      time.Time
+	Duration     time.Duration
+	Interrupted  bool // 設定時間の経過前に中断されたか
+
+	// メトリクス
+	TotalRequests   uint64
+	SuccessRequests uint64
+	FailedRequests  uint64
+	ErrorRate       float64
+	AvgLatency      time.Duration
+	P99Latency      time.Duration
+
+	// CapacityFailures は FailedRequests のうち、ノードのメモリ使用量の上限（Config.NodeLimits.MaxMemory）で失敗した数
+	CapacityFailures uint64
+
+	// カオス統計
+	TotalAttacks uint64
+
+	// Attacks はカオスモンキーが注入した攻撃の記録（ステップ・APIからの注入は含まない）
+	// Config.ChaosReplay に渡すと同じ攻撃を同じ間隔で再生できる
+	Attacks []chaos.Attack
+
+	// 復旧統計
+	TotalRecoveries   uint64
+	SuccessRecoveries uint64
+	FailedRecoveries  uint64
+
+	// ノード状態
+	FinalNodeStatus map[string]string
+
+	// RecentWarnings は実行中に記録された直近の警告以上のログ（Config.ReportWarnings 件まで）
+	RecentWarnings []logger.Entry
+
+	// AssertionFailures は満たされなかった Config.Assertions の条件
+	AssertionFailures []AssertionFailure
+
+	// Linearizability は操作の履歴の線形化可能性の検査結果（Config.Linearizability が false の場合は nil）
+	Linearizability *lincheck.CheckResult
+
+	// TraceID は実行を記録したトレースのID（Config.Tracing が無効の場合は空）
+	TraceID string
+
+	// Steps は実行した Config.Steps の結果（失敗したステップ以降は含まない）
+	Steps []StepResult
+
+	// HotKeys はアクセス数の多いキーとキー空間の偏り（Config.KeySampleRate が0の場合は nil）
+	HotKeys *client.HotKeyReport
+
+	// Replicas はノードの間のデータの収束の状況（Config.Store が空の場合は nil）
+	Replicas *ReplicaReport
+
+	// Scans はスキャンのリクエストだけのメトリクス（Config.ScanRatio が0の場合は nil）
+	Scans *ScanReport
+
+	// Durability は再起動をまたいだデータの残り具合（Config.Durability の Mode が空で EphemeralLossRatio が0の場合は nil）
+	Durability *DurabilityReport
+
+	// Autoscale は負荷に合わせたノード数の増減の結果（Config.Autoscale が無効の場合は nil）
+	Autoscale *AutoscaleReport
+}
+
+// Engine はシナリオ実行エンジン
+type Engine struct {
+	config   Config
+	eventBus *events.Bus
+
+	cluster  *cluster.Cluster
+	client   *client.Client
+	monkey   *chaos.Monkey
+	recovery *recovery.Manager
+
+	// autoscaler は負荷に合わせてノード数を増減する（Config.Autoscale が無効の場合は nil）
+	autoscaler *autoscale.Controller
+
+	resp     []*resp.Server
+	nodeHTTP []*nodehttp.Server
+	closeKV  []func() // ノードごとのリクエストの送り先の後始末（プロキシの削除を含む）
+	procs    []*procnode.Process
+	history  *lincheck.Recorder
+	steps    []StepResult
+	syncs    atomic.Uint64 // 実行したノードの状態の同期の回数
+	merged   atomic.Uint64 // 同期で値が変わったキーの数の合計
+
+	// persistDir はスナップショットを書き出す一時ディレクトリ（作成していない場合は空）
+	persistDir string
+
+	mu      sync.RWMutex
+	running bool
+	cancel  context.CancelFunc
+	stopReq bool // セットアップ中に停止要求を受けたか
+}
+
+// New は新しいEngineを作成する
+func New(config Config) *Engine {
+	if config.Clock == nil {
+		config.Clock = clock.Real()
+	}
+	return &Engine{
+		config: config,
+	}
+}
+
+// SetEventBus はイベントバスを設定する
+func (e *Engine) SetEventBus(bus *events.Bus) {
+	e.eventBus = bus
+}
+
+// Run はシナリオを実行する
+func (e *Engine) Run(ctx context.Context) (*Result, error) {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("scenario is already running")
+	}
+	e.running = true
+	e.stopReq = false
+	e.steps = nil
+	e.syncs.Store(0)
+	e.merged.Store(0)
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		e.running = false
+		e.mu.Unlock()
+	}()
+
+	log.Info("", "=== Scenario '%s' started ===", e.config.Name)
+	log.Info("", "Description: %s", e.config.Description)
+
+	result := &Result{
+		ScenarioName: e.config.Name,
+		StartTime:    e.config.Clock.Now(),
+	}
+
+	// 通知はセットアップ前に開始し、終了イベントを送り終えてから閉じる
+	// 攻撃・復旧のトレースもイベントから記録するため、バスを用意する
+	if (len(e.config.Notifiers) > 0 || e.config.Tracing.Endpoint != "") && e.eventBus == nil {
+		e.eventBus = events.NewBus()
+	}
+	notifiers, err := e.startNotifiers()
+	if err != nil {
+		return nil, err
+	}
+	defer closeNotifiers(notifiers)
+
+	// トレースはセットアップから後片付けまでを1つのルートスパンにまとめ、最後に送り切る
+	tracer := e.startTracer()
+	defer closeTracer(tracer)
+	root := tracer.Start("scenario "+e.config.Name, tracing.String("scenario.name", e.config.Name))
+	if root != nil {
+		result.TraceID = root.TraceID().String()
+		log.Info("", "Trace ID: %s", result.TraceID)
+	}
+
+	// セットアップ
+	span := root.Child("setup")
+	if err := e.setup(ctx); err != nil {
+		e.removePersistDir()
+		span.SetError(err)
+		span.End()
+		root.SetError(err)
+		root.End()
+		return nil, fmt.Errorf("setup failed: %w", err)
+	}
+	span.SetAttributes(tracing.Int("cluster.node_count", int64(e.cluster.Size())))
+	span.End()
+	defer func() {
+		span := root.Child("teardown")
+		e.teardown()
+		span.End()
+		root.End()
+	}()
+	e.publish(events.NewScenarioStartedEvent(e.config.Name))
+
+	// シナリオ実行（ステップのみで終了を決める場合は時間の上限を設けない）
+	scenarioCtx, cancel := context.WithCancel(ctx)
+	if e.config.Duration > 0 || len(e.config.Steps) == 0 {
+		scenarioCtx, cancel = clock.WithTimeout(ctx, e.config.Clock, e.config.Duration)
+	}
+	defer cancel()
+
+	e.mu.Lock()
+	e.cancel = cancel
+	if e.stopReq {
+		cancel()
+	}
+	e.mu.Unlock()
+
+	load := root.Child("load")
+	e.client.SetTraceParent(load)
+	traced := tracing.TraceEvents(e.eventBus, load)
+	e.runScenario(scenarioCtx, load)
+	traced.Close()
+	load.End()
+
+	e.mu.Lock()
+	e.cancel = nil
+	e.mu.Unlock()
+
+	// 結果収集
+	result.EndTime = e.config.Clock.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+	result.Interrupted = scenarioCtx.Err() == context.Canceled
+	e.collectResults(result)
+	e.checkLinearizability(result, root)
+	result.AssertionFailures = e.config.Assertions.Check(result)
+	for _, s := range result.Steps {
+		result.AssertionFailures = append(result.AssertionFailures, s.Failures...)
+	}
+	root.SetAttributes(
+		tracing.Bool("scenario.interrupted", result.Interrupted),
+		tracing.Int("scenario.requests", int64(result.TotalRequests)),
+		tracing.Int("scenario.failed_requests", int64(result.FailedRequests)),
+		tracing.Int("scenario.attacks", int64(result.TotalAttacks)),
+	)
+	if n := len(result.AssertionFailures); n > 0 {
+		root.SetError(fmt.Errorf("%d assertion(s) failed", n))
+	}
+	for _, f := range result.AssertionFailures {
+		log.Warn("", "Assertion failed: %s", f)
+		e.publish(events.NewSLOViolationEvent(f.Metric, f.Threshold, f.Value))
+	}
+
+	reason := "completed"
+	if result.Interrupted {
+		reason = "interrupted"
+	}
+	e.publish(events.NewScenarioFinishedEvent(e.config.Name, reason, e.metricsSummary()))
+
+	log.Info("", "=== Scenario '%s' completed ===", e.config.Name)
+
+	return result, nil
+}
+
+// notifierCloseTimeout は終了時に未送信の通知を送り切るまで待つ最大時間
+const notifierCloseTimeout = 10 * time.Second
+
+// startNotifiers は設定された通知先へのイベント投稿を開始する
+func (e *Engine) startNotifiers() ([]*events.WebhookSink, error) {
+	var sinks []*events.WebhookSink
+	for _, config := range e.config.Notifiers {
+		sink, err := events.NewNotifier(e.eventBus, config)
+		if err != nil {
+			closeNotifiers(sinks)
+			return nil, fmt.Errorf("invalid notifier: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// startTracer はトレースの送信先が設定されていれば Tracer を作成する（無効の場合は nil）
+func (e *Engine) startTracer() *tracing.Tracer {
+	if e.config.Tracing.Endpoint == "" {
+		return nil
+	}
+	return tracing.New(e.config.Tracing)
+}
+
+// closeTracer は送信待ちのスパンを送り切る
+func closeTracer(tracer *tracing.Tracer) {
+	if tracer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifierCloseTimeout)
+	defer cancel()
+	if err := tracer.Close(ctx); err != nil {
+		log.Warn("", "Tracer did not finish exporting: %v", err)
+	}
+	stats := tracer.Stats()
+	if stats.Dropped > 0 {
+		log.Warn("", "Dropped %d sampled span(s) while the export queue was full (lower the sample rate to keep them)", stats.Dropped)
+	}
+	if stats.Failed > 0 {
+		log.Warn("", "Failed to export %d span(s) to %s", stats.Failed, tracer.URL())
+	}
+	log.Info("", "Exported %d span(s) to %s", stats.Exported, tracer.URL())
+}
+
+// closeNotifiers は未送信の通知を送り切ってから購読を終了する
+func closeNotifiers(sinks []*events.WebhookSink) {
+	if len(sinks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifierCloseTimeout)
+	defer cancel()
+	for _, sink := range sinks {
+		if err := sink.Close(ctx); err != nil {
+			log.Warn("", "Notifier did not finish sending: %v", err)
+		}
+		if stats := sink.Stats(); stats.Failed > 0 {
+			log.Warn("", "Failed to deliver %d notification(s)", stats.Failed)
+		}
+	}
+}
+
+// publish はイベントバスが設定されていればイベントを発行する
+func (e *Engine) publish(event events.Event) {
+	if e.eventBus != nil {
+		e.eventBus.Publish(event)
+	}
+}
+
+// setup はシナリオ実行前のセットアップ
+func (e *Engine) setup(ctx context.Context) error {
+	for i, step := range e.config.Steps {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("invalid step %d: %w", i+1, err)
+		}
+	}
+	if e.config.Linearizability && e.config.BatchSize > 1 {
+		return fmt.Errorf("linearizability cannot be checked with batched requests")
+	}
+	if e.config.ReplicationFactor > 1 && e.config.BatchSize > 1 {
+		return fmt.Errorf("batched requests cannot be replicated")
+	}
+	if err := e.validateAutoscale(); err != nil {
+		return err
+	}
+
+	// クラスタ作成
+	c := cluster.New()
+	c.SetClock(e.config.Clock)
+	c.SetNodeLimits(e.config.NodeLimits)
+	c.SetNodeAdmission(e.config.NodeAdmission)
+	store, err := node.ParseStoreKind(string(e.config.Store))
+	if err != nil {
+		return err
+	}
+	if store == node.StoreCRDT && (e.config.NodeProcess || len(e.config.External) > 0) {
+		return fmt.Errorf("the %s store cannot be used with node processes or external nodes", node.StoreCRDT)
+	}
+	c.SetStoreKind(store)
+	c.SetReplicationFactor(e.config.ReplicationFactor)
+	if err := e.config.Consistency.Validate(); err != nil {
+		return err
+	}
+	c.SetConsistency(e.config.Consistency)
+	if mode := e.config.Durability.Mode; mode != "" && mode != node.DurabilityMemory && (e.config.NodeProcess || len(e.config.External) > 0) {
+		return fmt.Errorf("the %s durability cannot be used with node processes or external nodes", mode)
+	}
+	if e.config.Durability.EphemeralLossRatio > 0 && (e.config.NodeProcess || len(e.config.External) > 0) {
+		return fmt.Errorf("the ephemeral loss ratio cannot be used with node processes or external nodes")
+	}
+	if err := e.setupDurability(c, e.config.Durability); err != nil {
+		return err
+	}
+	if err := e.config.Latency.Validate(); err != nil {
+		return err
+	}
+	c.SetLatencyMatrix(e.config.Latency)
+	if e.config.Toxiproxy.URL != "" && !e.config.NodeHTTP && !e.config.NodeProcess && len(e.config.External) == 0 {
+		return fmt.Errorf("toxiproxy requires node HTTP, node processes or external nodes")
+	}
+	if e.config.NodeProcess && (e.config.RESPAddr != "" || e.config.RESPNodePort > 0 || e.config.NodeHTTP || len(e.config.External) > 0) {
+		return fmt.Errorf("node processes cannot be combined with RESP or node HTTP listeners or external nodes")
+	}
+	if len(e.config.External) > 0 {
+		if e.config.RESPAddr != "" || e.config.RESPNodePort > 0 || e.config.NodeHTTP {
+			return fmt.Errorf("external nodes cannot be combined with RESP or node HTTP listeners")
+		}
+		for _, ext := range e.config.External {
+			n := node.New(ext.ID)
+			n.SetBackend(external.New(ext))
+			if err := c.AddNode(n); err != nil {
+				return fmt.Errorf("failed to add external node: %w", err)
+			}
+		}
+	} else if err := c.CreateNodes(e.config.NodeCount, nodePrefix); err != nil {
+		return fmt.Errorf("failed to create nodes: %w", err)
+	}
+	c.AssignZones(e.config.Zones)
+	var procs []*procnode.Process
+	if e.config.NodeProcess {
+		var err error
+		if procs, err = e.attachProcesses(c); err != nil {
+			return err
+		}
+	}
+	if err := c.StartAll(ctx); err != nil {
+		stopProcesses(procs)
+		return fmt.Errorf("failed to start nodes: %w", err)
+	}
+	if e.eventBus != nil {
+		c.SetEventBus(e.eventBus)
+	}
+
+	// クライアント
+	clientConfig := client.DefaultConfig()
+	clientConfig.NumWorkers = e.config.ClientWorkers
+	clientConfig.WriteRatio = e.config.WriteRatio
+	clientConfig.KeySampleRate = e.config.KeySampleRate
+	clientConfig.TargetRPS = e.config.TargetRPS
+	clientConfig.BatchSize = e.config.BatchSize
+	clientConfig.ScanRatio = e.config.ScanRatio
+	clientConfig.ScanLimit = e.config.ScanLimit
+	clientConfig.Namespaces = e.config.Namespaces
+	cl := client.New(c, clientConfig)
+	if e.eventBus != nil {
+		cl.SetEventBus(e.eventBus)
+	}
+	var history *lincheck.Recorder
+	if e.config.Linearizability {
+		historyConfig := lincheck.DefaultConfig()
+		if e.config.HistoryLimit > 0 {
+			historyConfig.MaxOperations = e.config.HistoryLimit
+		}
+		history = lincheck.NewRecorder(historyConfig)
+		cl.SetRecorder(history)
+	}
+	// 外部のプロセスは起動・再開してから切り離し、停止したままにしない
+	abort := func(err error) error {
+		restoreExternal(c)
+		_ = c.StopAll()
+		stopProcesses(procs)
+		return err
+	}
+	var proxies *toxiproxy.Client
+	if e.config.Toxiproxy.URL != "" {
+		proxies = toxiproxy.NewClient(e.config.Toxiproxy)
+		pingCtx, cancel := context.WithTimeout(ctx, externalPingTimeout)
+		_, err := proxies.Version(pingCtx)
+		cancel()
+		if err != nil {
+			return abort(fmt.Errorf("toxiproxy is not reachable at %s: %w", e.config.Toxiproxy.URL, err))
+		}
+	}
+	if e.config.NodeHTTP {
+		if err := e.startNodeHTTP(ctx, c, cl, proxies); err != nil {
+			return abort(err)
+		}
+	}
+	if e.config.NodeProcess {
+		if err := e.connectProcesses(ctx, c, cl, procs, proxies); err != nil {
+			return abort(err)
+		}
+	}
+	if len(e.config.External) > 0 {
+		if err := e.connectExternal(ctx, c, cl, proxies); err != nil {
+			return abort(err)
+		}
+	}
+
+	// カオスモンキー
+	// 手動での障害注入に使用するため、カオス無効時も作成する（Startはしない）
+	chaosConfig := chaos.DefaultConfig()
+	chaosConfig.Interval = e.config.ChaosInterval
+	chaosConfig.TargetCount = e.config.ChaosTargets
+	chaosConfig.AttackTypes = e.config.AttackTypes
+	chaosConfig.Clock = e.config.Clock
+	chaosConfig.Replay = e.config.ChaosReplay
+	monkey := chaos.New(c, chaosConfig)
+	if e.eventBus != nil {
+		monkey.SetEventBus(e.eventBus)
+	}
+
+	// 復旧マネージャー
+	var rm *recovery.Manager
+	if e.config.EnableRecovery {
+		recoveryConfig := recovery.DefaultConfig()
+		recoveryConfig.RecoveryDelay = e.config.RecoveryDelay
+		recoveryConfig.MaxRetries = e.config.MaxRetries
+		recoveryConfig.Clock = e.config.Clock
+		rm = recovery.New(c, recoveryConfig)
+		if e.eventBus != nil {
+			rm.SetEventBus(e.eventBus)
+		}
+	}
+
+	e.mu.Lock()
+	e.cluster = c
+	e.client = cl
+	e.monkey = monkey
+	e.recovery = rm
+	e.autoscaler = e.newAutoscaler(c, cl)
+	e.history = history
+	e.procs = procs
+	e.mu.Unlock()
+
+	// RESPリスナー
+	if err := e.startRESP(ctx); err != nil {
+		e.teardown()
+		return err
+	}
+
+	return nil
+}
+
+// startRESP は設定されたRESPリスナー（クラスタ全体・ノードごと）を開始する
+func (e *Engine) startRESP(ctx context.Context) error {
+	var servers []*resp.Server
+	start := func(addr string, router resp.Router) error {
+		config := resp.DefaultConfig()
+		config.Addr = addr
+		s := resp.New(config, router)
+		if err := s.Start(ctx); err != nil {
+			for _, started := range servers {
+				started.Stop()
+			}
+			return fmt.Errorf("failed to start RESP server: %w", err)
+		}
+		servers = append(servers, s)
+		return nil
+	}
+
+	if e.config.RESPAddr != "" {
+		if err := start(e.config.RESPAddr, resp.ClusterRouter(e.cluster)); err != nil {
+			return err
+		}
+	}
+	if e.config.RESPNodePort > 0 {
+		for i := range e.config.NodeCount {
+			n, ok := e.cluster.GetNode(fmt.Sprintf("%s-%d", nodePrefix, i+1))
+			if !ok {
+				continue
+			}
+			if err := start(fmt.Sprintf(":%d", e.config.RESPNodePort+i), resp.NodeRouter(n)); err != nil {
+				return err
+			}
+		}
+	}
+
+	e.mu.Lock()
+	e.resp = servers
+	e.mu.Unlock()
+	return nil
+}
+
+// startNodeHTTP はノードごとのHTTPサーバーを開始し、クライアントのリクエストをそれらに送るよう設定する
+// proxies が nil でない場合は、各サーバーへのプロキシを経由させる
+func (e *Engine) startNodeHTTP(ctx context.Context, c *cluster.Cluster, cl *client.Client, proxies *toxiproxy.Client) error {
+	var servers []*nodehttp.Server
+	var closers []func()
+	cleanup := func() {
+		for _, closeFn := range closers {
+			closeFn()
+		}
+		for _, started := range servers {
+			started.Stop()
+		}
+	}
+	kv := make(map[*node.Node]client.KV)
+	for i := range e.config.NodeCount {
+		n, ok := c.GetNode(fmt.Sprintf("%s-%d", nodePrefix, i+1))
+		if !ok {
+			continue
+		}
+		config := nodehttp.DefaultConfig()
+		if e.config.NodeHTTPPort > 0 {
+			config.Addr = fmt.Sprintf("127.0.0.1:%d", e.config.NodeHTTPPort+i)
+		}
+		s := nodehttp.New(n, config)
+		if err := s.Start(ctx); err != nil {
+			cleanup()
+			return fmt.Errorf("failed to start node HTTP server: %w", err)
+		}
+		servers = append(servers, s)
+
+		addr, err := e.proxy(ctx, proxies, n, strings.TrimPrefix(s.URL(), "http://"), &closers)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		hc := nodehttp.NewClient("http://"+addr, cl.Workers())
+		closers = append(closers, hc.Close)
+		kv[n] = hc
+	}
+
+	e.mu.Lock()
+	e.nodeHTTP = servers
+	e.mu.Unlock()
+	e.useTransport(cl, kv, closers)
+	return nil
+}
+
+// attachProcesses はシナリオのノードごとに子プロセスを作成し、ノードの backend にする（起動はしない）
+func (e *Engine) attachProcesses(c *cluster.Cluster) ([]*procnode.Process, error) {
+	command := e.config.NodeCommand
+	if len(command) == 0 {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate node process command: %w", err)
+		}
+		command = []string{exe, "node-server"}
+	}
+
+	var procs []*procnode.Process
+	for i := range e.config.NodeCount {
+		n, ok := c.GetNode(fmt.Sprintf("%s-%d", nodePrefix, i+1))
+		if !ok {
+			continue
+		}
+		config := procnode.DefaultConfig()
+		config.ID = n.ID()
+		config.Command = command
+		p := procnode.New(config)
+		n.SetBackend(p)
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// connectProcesses はクライアントのリクエストをノードの子プロセスにHTTPで送るよう設定する
+// proxies が nil でない場合は、各プロセスへのプロキシを経由させる
+func (e *Engine) connectProcesses(ctx context.Context, c *cluster.Cluster, cl *client.Client, procs []*procnode.Process, proxies *toxiproxy.Client) error {
+	var closers []func()
+	cleanup := func() {
+		for _, closeFn := range closers {
+			closeFn()
+		}
+	}
+	kv := make(map[*node.Node]client.KV)
+	for _, p := range procs {
+		n, ok := c.GetNode(p.ID())
+		if !ok {
+			continue
+		}
+		addr, err := e.proxy(ctx, proxies, n, p.Addr(), &closers)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		hc := nodehttp.NewClient("http://"+addr, cl.Workers())
+		closers = append(closers, hc.Close)
+		kv[n] = hc
+	}
+
+	e.useTransport(cl, kv, closers)
+	return nil
+}
+
+// stopProcesses はノードの子プロセスを強制終了する
+// restoreExternal でノードから切り離した後も、プロセスを残さないようにする
+func stopProcesses(procs []*procnode.Process) {
+	for _, p := range procs {
+		if err := p.Stop(); err != nil {
+			log.Warn(p.ID(), "Failed to stop node process: %v", err)
+		}
+	}
+}
+
+// connectExternal は外部のノードに接続できることを確かめ、クライアントのリクエストをRESPで送るよう設定する
+// proxies が nil でない場合は、各ノードへのプロキシを経由させる
+func (e *Engine) connectExternal(ctx context.Context, c *cluster.Cluster, cl *client.Client, proxies *toxiproxy.Client) error {
+	var closers []func()
+	cleanup := func() {
+		for _, closeFn := range closers {
+			closeFn()
+		}
+	}
+	kv := make(map[*node.Node]client.KV)
+	for _, ext := range e.config.External {
+		n, ok := c.GetNode(ext.ID)
+		if !ok {
+			continue
+		}
+		addr, err := e.proxy(ctx, proxies, n, ext.Addr, &closers)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		rc := resp.NewClient(addr, cl.Workers())
+		closers = append(closers, rc.Close)
+
+		pingCtx, cancel := context.WithTimeout(ctx, externalPingTimeout)
+		err = rc.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("external node %s is not reachable at %s: %w", ext.ID, addr, err)
+		}
+		kv[n] = rc
+	}
+
+	e.useTransport(cl, kv, closers)
+	return nil
+}
+
+// proxy は proxies が nil でない場合、upstream へのプロキシを作成してノードの backend にし、負荷の送り先のアドレスを返す
+// ノードに設定済みの backend はプロキシと合わせて操作する。プロキシの削除は closers に追加する
+func (e *Engine) proxy(ctx context.Context, proxies *toxiproxy.Client, n *node.Node, upstream string, closers *[]func()) (string, error) {
+	if proxies == nil {
+		return upstream, nil
+	}
+	p, err := proxies.CreateProxy(ctx, "chaos-kvs-"+n.ID(), upstream)
+	if err != nil {
+		return "", err
+	}
+	*closers = append(*closers, func() {
+		if err := p.Delete(); err != nil {
+			log.Warn(n.ID(), "Failed to delete proxy: %v", err)
+		}
+	})
+	p.Wrap(n.Backend())
+	n.SetBackend(p)
+	return p.Listen(), nil
+}
+
+// useTransport はクライアントのリクエストを kv のノードごとの送り先に送るよう設定する
+// kv にないノード（実行中に追加されたノードなど）は直接呼び出す
+func (e *Engine) useTransport(cl *client.Client, kv map[*node.Node]client.KV, closers []func()) {
+	cl.SetTransport(func(n *node.Node) client.KV {
+		if t, ok := kv[n]; ok {
+			return t
+		}
+		return n
+	})
+
+	e.mu.Lock()
+	e.closeKV = closers
+	e.mu.Unlock()
+}
+
+// restoreExternal は外部のノード・プロキシの遅延を解除し、停止・一時停止したプロセスを起動・再開してから切り離す
+// 以降のノードの停止は外部のプロセスに影響しない
+func restoreExternal(c *cluster.Cluster) {
+	for _, n := range c.Nodes() {
+		if n.Backend() == nil {
+			continue
+		}
+		if n.Delay() > 0 {
+			n.SetDelay(0)
+		}
+		var err error
+		switch n.Status() {
+		case node.StatusSuspended:
+			err = n.Resume()
+		case node.StatusStopped:
+			err = n.Start(context.Background())
+		}
+		if err != nil {
+			log.Warn(n.ID(), "Failed to restore external node: %v", err)
+		}
+		n.SetBackend(nil)
+	}
+}
+
+// teardown はシナリオ実行後のクリーンアップ
+func (e *Engine) teardown() {
+	// 後片付けの間にノードを増減しないよう、最初に止める
+	if e.autoscaler != nil {
+		e.autoscaler.Stop()
+	}
+	e.mu.Lock()
+	servers := e.resp
+	e.resp = nil
+	e.mu.Unlock()
+	for _, s := range servers {
+		s.Stop()
+	}
+	if e.client != nil {
+		e.client.Stop()
+	}
+
+	// クライアントの停止後に止め、停止中のリクエストを失敗として記録しないようにする
+	e.mu.Lock()
+	httpServers, closeKV := e.nodeHTTP, e.closeKV
+	e.nodeHTTP, e.closeKV = nil, nil
+	e.mu.Unlock()
+	for _, closeFn := range closeKV {
+		closeFn()
+	}
+	for _, s := range httpServers {
+		s.Stop()
+	}
+
+	if e.monkey != nil {
+		e.monkey.Stop()
+	}
+	if e.recovery != nil {
+		e.recovery.Stop()
+	}
+	if e.cluster != nil {
+		restoreExternal(e.cluster)
+		_ = e.cluster.StopAll()
+	}
+	e.mu.Lock()
+	procs := e.procs
+	e.procs = nil
+	e.mu.Unlock()
+	stopProcesses(procs)
+	e.removePersistDir()
+}
+
+// runScenario はシナリオのメイン処理。ステップは parent の子スパンに記録する
+func (e *Engine) runScenario(ctx context.Context, parent *tracing.Span) {
+	// ステップを指定した場合は全てのステップを終えた時点でも終了する
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// クライアント開始
+	e.client.Start(ctx)
+
+	// カオス開始
+	if e.config.EnableChaos {
+		e.monkey.Start(ctx)
+	}
+
+	// 復旧開始
+	if e.recovery != nil {
+		e.recovery.Start(ctx)
+	}
+
+	// ノード数の増減開始
+	if e.autoscaler != nil {
+		e.autoscaler.Start(ctx)
+	}
+
+	// メトリクスのスナップショット発行
+	var wg sync.WaitGroup
+	if interval := e.metricsInterval(); e.eventBus != nil && interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.publishMetrics(ctx, interval)
+		}()
+	}
+
+	// ノードの状態の同期
+	if interval := e.syncInterval(); e.config.Store == node.StoreCRDT && interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.syncReplicas(ctx, interval)
+		}()
+	}
+
+	if len(e.config.Steps) > 0 {
+		e.runSteps(ctx, parent)
+		cancel()
+	}
+
+	// 終了まで待機
+	<-ctx.Done()
+	wg.Wait()
+
+	log.Info("", "Scenario duration completed, stopping components...")
+}
+
+// publishMetrics は終了まで一定間隔でメトリクスのスナップショットをイベントバスに発行する
+// 購読側がメトリクスを個別にポーリングせずに状態を追えるようにする
+func (e *Engine) publishMetrics(ctx context.Context, interval time.Duration) {
+	ticker := e.config.Clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+			e.eventBus.Publish(events.NewMetricsSnapshotEvent(e.metricsSummary()))
+		}
+	}
+}
+
+// metricsInterval はスナップショットの発行間隔を返す（0以下で無効）
+func (e *Engine) metricsInterval() time.Duration {
+	if e.config.MetricsInterval == 0 {
+		return DefaultMetricsInterval
+	}
+	return e.config.MetricsInterval
+}
+
+// metricsSummary は現在のメトリクス・ノード数・攻撃数をまとめる
+func (e *Engine) metricsSummary() events.MetricsSummary {
+	snapshot := e.client.Metrics().Snapshot()
+	summary := events.MetricsSummary{
+		Requests:   snapshot.TotalRequests,
+		Errors:     snapshot.FailedRequests,
+		RPS:        snapshot.RPS,
+		AvgLatency: snapshot.AverageLatency,
+		P99Latency: snapshot.P99Latency,
+		ErrorRate:  snapshot.ErrorRate,
+		Attacks:    e.monkey.AttackCount(),
+	}
+	for _, n := range e.cluster.Nodes() {
+		summary.NodeCount++
+		if n.Status() == node.StatusRunning {
+			summary.NodesRunning++
+		}
+	}
+	return summary
+}
+
+// collectResults は結果を収集する
+func (e *Engine) collectResults(result *Result) {
+	// メトリクススナップショット
+	snapshot := e.client.Metrics().Snapshot()
+	result.TotalRequests = snapshot.TotalRequests
+	result.SuccessRequests = snapshot.SuccessRequests
+	result.FailedRequests = snapshot.FailedRequests
+	result.ErrorRate = snapshot.ErrorRate
+	result.AvgLatency = snapshot.AverageLatency
+	result.P99Latency = snapshot.P99Latency
+	result.CapacityFailures = e.client.CapacityFailures()
+
+	// カオス統計
+	if e.monkey != nil {
+		result.TotalAttacks = e.monkey.AttackCount()
+		result.Attacks = e.monkey.Recorded()
+	}
+
+	// 復旧統計
+	if e.recovery != nil {
+		stats := e.recovery.Stats()
+		result.TotalRecoveries = stats.TotalRecoveries
+		result.SuccessRecoveries = stats.SuccessRecoveries
+		result.FailedRecoveries = stats.FailedRecoveries
+	}
+
+	// ノード数の増減
+	if e.autoscaler != nil {
+		result.Autoscale = e.autoscaleReport()
+	}
+
+	// ステップ
+	e.mu.RLock()
+	result.Steps = e.steps
+	e.mu.RUnlock()
+
+	// ホットキー
+	if stats := e.client.KeyStats(); stats != nil {
+		result.HotKeys = stats.Report(e.config.HotKeys)
+	}
+
+	// スキャン
+	result.Scans = e.scanReport()
+
+	// 再起動をまたいだデータの残り具合
+	if e.config.Durability.Mode != "" || e.config.Durability.EphemeralLossRatio > 0 {
+		result.Durability = e.durabilityReport()
+	}
+
+	// ノードの間のデータの収束
+	if e.config.Store != "" {
+		result.Replicas = e.replicaReport()
+	}
+
+	// ノード状態
+	result.FinalNodeStatus = make(map[string]string)
+	for _, n := range e.cluster.Nodes() {
+		result.FinalNodeStatus[n.ID()] = n.Status().String()
+	}
+
+	// 直近の警告ログ
+	if e.config.ReportWarnings > 0 {
+		result.RecentWarnings = logger.Recent.Query(logger.Query{
+			MinLevel: logger.LevelWarn,
+			Since:    result.StartTime,
+			Limit:    e.config.ReportWarnings,
+		})
+	}
+}
+
+// checkLinearizability は記録した操作の履歴を検査し、結果に含める
+// 記録中の操作がないようにクライアントを先に停止する。検査の区間は parent の子スパンに記録する
+func (e *Engine) checkLinearizability(result *Result, parent *tracing.Span) {
+	if e.history == nil {
+		return
+	}
+	e.client.Stop()
+
+	span := parent.Child("check linearizability")
+	check := lincheck.Check(e.history.Operations(), lincheck.DefaultMaxSteps)
+	check.Truncated = e.history.Truncated()
+	result.Linearizability = check
+	span.SetAttributes(
+		tracing.Int("lincheck.operations", int64(check.Operations)),
+		tracing.Bool("lincheck.linearizable", check.Linearizable),
+		tracing.Int("lincheck.violated_keys", int64(check.ViolatedKeys)),
+	)
+	span.End()
+
+	switch {
+	case !check.Linearizable:
+		log.Warn("", "Linearizability violated on %d of %d key(s)", check.ViolatedKeys, check.Partitions)
+	case check.Unknown > 0:
+		log.Warn("", "Linearizability could not be decided on %d of %d key(s)", check.Unknown, check.Partitions)
+	default:
+		log.Info("", "History of %d operation(s) is linearizable", check.Operations)
+	}
+}
+
+// Report は結果をフォーマットして返す
+func (r *Result) Report() string {
+	report := fmt.Sprintf(`
+================================================================================
+                         SCENARIO REPORT: %s
+================================================================================
+
+EXECUTION SUMMARY
+-----------------
+  Start Time:     %s
+  End Time:       %s
+  Duration:       %v
+  Status:         %s
+%s
+TRAFFIC METRICS
+---------------
+  Total Requests:   %d
+  Success:          %d
+  Failed:           %d
+%s  Error Rate:       %.2f%%
+  Avg Latency:      %v
+  P99 Latency:      %v
+
+CHAOS STATISTICS
+----------------
+  Total Attacks:    %d
+
+RECOVERY STATISTICS
+-------------------
+  Total Recoveries:   %d
+  Successful:         %d
+  Failed:             %d
+`,
+		r.ScenarioName,
+		r.StartTime.Format("2006-01-02 15:04:05"),
+		r.EndTime.Format("2006-01-02 15:04:05"),
+		r.Duration.Round(time.Millisecond),
+		r.status(),
+		r.traceLine(),
+		r.TotalRequests,
+		r.SuccessRequests,
+		r.FailedRequests,
+		r.capacityLine(),
+		r.ErrorRate*100,
+		r.AvgLatency.Round(time.Microsecond),
+		r.P99Latency.Round(time.Microsecond),
+		r.TotalAttacks,
+		r.TotalRecoveries,
+		r.SuccessRecoveries,
+		r.FailedRecoveries,
+	)
+
+	if len(r.FinalNodeStatus) > 0 {
+		report += "\nFINAL NODE STATUS\n-----------------\n"
+		for _, nodeID := range r.sortedNodeIDs() {
+			report += fmt.Sprintf("  %-20s %s\n", nodeID+":", r.FinalNodeStatus[nodeID])
+		}
+	}
+
+	if len(r.AssertionFailures) > 0 {
+		report += "\nASSERTIONS FAILED\n-----------------\n"
+		for _, f := range r.AssertionFailures {
+			report += fmt.Sprintf("  %s\n", f)
+		}
+	}
+
+	if len(r.Steps) > 0 {
+		report += "\nSTEPS\n-----\n"
+		for _, row := range r.stepRows() {
+			report += fmt.Sprintf("  %-14s %s\n", row[0], row[1])
+		}
+	}
+
+	if r.HotKeys != nil {
+		report += "\nHOT KEYS\n--------\n"
+		for _, row := range r.hotKeyRows() {
+			report += fmt.Sprintf("  %-20s %s\n", row[0]+":", row[1])
+		}
+	}
+
+	if r.Scans != nil {
+		report += "\nSCANS\n-----\n"
+		for _, row := range r.scanRows() {
+			report += fmt.Sprintf("  %-20s %s\n", row[0]+":", row[1])
+		}
+	}
+
+	if r.Durability != nil {
+		report += "\nDURABILITY\n----------\n"
+		for _, row := range r.durabilityRows() {
+			report += fmt.Sprintf("  %-20s %s\n", row[0]+":", row[1])
+		}
+	}
+
+	if r.Replicas != nil {
+		report += "\nREPLICAS\n--------\n"
+		for _, row := range r.replicaRows() {
+			report += fmt.Sprintf("  %-20s %s\n", row[0]+":", row[1])
+		}
+	}
+
+	if r.Autoscale != nil {
+		report += "\nAUTOSCALE\n---------\n"
+		for _, row := range r.autoscaleRows() {
+			report += fmt.Sprintf("  %-20s %s\n", row[0]+":", row[1])
+		}
+	}
+
+	if r.Linearizability != nil {
+		report += "\nLINEARIZABILITY\n---------------\n"
+		for _, row := range r.linearizabilityRows() {
+			report += fmt.Sprintf("  %-20s %s\n", row[0]+":", row[1])
+		}
+	}
+
+	if len(r.RecentWarnings) > 0 {
+		report += "\nRECENT WARNINGS\n---------------\n"
+		for _, row := range r.warningRows() {
+			report += fmt.Sprintf("  %s  %s\n", row[0], row[1])
+		}
+	}
+
+	report += "\n================================================================================"
+
+	return report
+}
+
+// Summary は全体の集計値と満たされなかった条件のみを残した結果を返す（ノードごとの最終状態・直近の警告・違反した操作・ホットキー・攻撃の記録を除く）
+func (r *Result) Summary() *Result {
+	s := *r
+	s.FinalNodeStatus = nil
+	s.RecentWarnings = nil
+	s.Attacks = nil
+	if r.Linearizability != nil {
+		check := *r.Linearizability
+		check.Violations = nil
+		s.Linearizability = &check
+	}
+	if r.HotKeys != nil {
+		hot := *r.HotKeys
+		hot.TopKeys = nil
+		s.HotKeys = &hot
+	}
+	return &s
+}
+
+// traceLine はトレースIDがあればテキストレポートの行として返す
+func (r *Result) traceLine() string {
+	if r.TraceID == "" {
+		return ""
+	}
+	return fmt.Sprintf("  Trace ID:       %s\n", r.TraceID)
+}
+
+// capacityLine はメモリ使用量の上限で失敗したリクエストの行を返す（失敗がない場合は空）
+func (r *Result) capacityLine() string {
+	if r.CapacityFailures == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Capacity Failures: %d\n", r.CapacityFailures)
+}
+
+// status は実行結果の状態を文字列で返す
+func (r *Result) status() string {
+	if r.Interrupted {
+		return "interrupted"
+	}
+	return "completed"
+}
+
+// Stop は実行中のシナリオを中断する
+// Run は途中までの結果を返して終了する。実行中でない場合は false を返す
+func (e *Engine) Stop() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.running {
+		return false
+	}
+
+	log.Info("", "Scenario '%s' stop requested", e.config.Name)
+	if e.cancel != nil {
+		e.cancel()
+	} else {
+		e.stopReq = true
+	}
+	return true
+}
+
+// RESPAddrs は実行中のRESPリスナーのアドレスを返す（クラスタ全体のリスナーが先頭）
+func (e *Engine) RESPAddrs() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	addrs := make([]string, 0, len(e.resp))
+	for _, s := range e.resp {
+		addrs = append(addrs, s.Addr())
+	}
+	return addrs
+}
+
+// NodeHTTPURLs は実行中のノードごとのHTTPサーバーのURLを返す（ノードの作成順）
+func (e *Engine) NodeHTTPURLs() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	urls := make([]string, 0, len(e.nodeHTTP))
+	for _, s := range e.nodeHTTP {
+		urls = append(urls, s.URL())
+	}
+	return urls
+}
+
+// IsRunning は実行中かどうかを返す
+func (e *Engine) IsRunning() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.running
+}
+
+// ChaosStats はカオス統計を返す
+func (e *Engine) ChaosStats() *chaos.Stats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.monkey == nil {
+		return nil
+	}
+	stats := e.monkey.Stats()
+	return &stats
+}
+
+// RecoveryStats は復旧統計を返す
+func (e *Engine) RecoveryStats() *recovery.Stats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.recovery == nil {
+		return nil
+	}
+	stats := e.recovery.Stats()
+	return &stats
+}
+
+// Metrics はクライアントメトリクスを返す
+func (e *Engine) Metrics() *metrics.Snapshot {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.client == nil {
+		return nil
+	}
+	snapshot := e.client.Metrics().Snapshot()
+	return &snapshot
+}
+
+// PoolMetrics はクライアントのワーカープールのメトリクスを返す（未実行の場合はnil）
+func (e *Engine) PoolMetrics() *worker.PoolMetrics {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.client == nil {
+		return nil
+	}
+	m := e.client.PoolMetrics()
+	return &m
+}
+
+// Monkey はカオスモンキーを返す（手動での障害注入用、セットアップ前はnil）
+func (e *Engine) Monkey() *chaos.Monkey {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.monkey
+}
+
+// Cluster はクラスタを返す
+func (e *Engine) Cluster() *cluster.Cluster {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.cluster
+}
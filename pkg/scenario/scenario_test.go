@@ -0,0 +1,1411 @@
+package scenario
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/internal/procnode"
+	"github.com/nyasuto/chaos-kvs/internal/resp"
+	"github.com/nyasuto/chaos-kvs/pkg/autoscale"
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/cluster"
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+	"github.com/nyasuto/chaos-kvs/pkg/external"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/toxiproxy"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+)
+
+// nodeProcessEnv はテストバイナリをノードの子プロセスとして動かす環境変数
+const nodeProcessEnv = "CHAOS_KVS_SCENARIO_NODE_PROCESS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(nodeProcessEnv) == "1" {
+		if err := procnode.RunServer(os.Args[1:]); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.Name != "default" {
+		t.Errorf("expected name 'default', got '%s'", config.Name)
+	}
+	if config.NodeCount != 5 {
+		t.Errorf("expected node count 5, got %d", config.NodeCount)
+	}
+	if !config.EnableChaos {
+		t.Error("expected chaos to be enabled")
+	}
+	if !config.EnableRecovery {
+		t.Error("expected recovery to be enabled")
+	}
+}
+
+func TestNewEngine(t *testing.T) {
+	config := DefaultConfig()
+	engine := New(config)
+
+	if engine == nil {
+		t.Fatal("expected non-nil engine")
+	}
+	if engine.IsRunning() {
+		t.Error("expected engine to not be running initially")
+	}
+}
+
+func TestEngineRunBasic(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 1 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+
+	engine := New(config)
+	ctx := context.Background()
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+
+	if result.ScenarioName != "basic" {
+		t.Errorf("expected scenario name 'basic', got '%s'", result.ScenarioName)
+	}
+	if result.TotalRequests == 0 {
+		t.Error("expected some requests to be executed")
+	}
+	if result.TotalAttacks != 0 {
+		t.Error("expected no attacks in basic scenario")
+	}
+}
+
+func TestEngineLinearizability(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 500 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.Linearizability = true
+	config.HistoryLimit = 5000
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+
+	check := result.Linearizability
+	if check == nil {
+		t.Fatal("expected a linearizability result")
+	}
+	if !check.Linearizable || len(check.Violations) != 0 {
+		t.Errorf("expected a run without chaos to be linearizable, got %+v", check.Violations)
+	}
+	if check.Operations < 5000 || check.Partitions == 0 {
+		t.Errorf("expected the history to reach the limit, got %d operations on %d keys", check.Operations, check.Partitions)
+	}
+	if !check.Truncated {
+		t.Error("expected the history to be truncated at the limit")
+	}
+}
+
+func TestEngineHotKeys(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 300 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.KeySampleRate = 0.5
+	config.HotKeys = 3
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+
+	hot := result.HotKeys
+	if hot == nil {
+		t.Fatal("expected a hot key report")
+	}
+	if hot.Sampled == 0 || hot.Sampled > result.TotalRequests || len(hot.TopKeys) != 3 {
+		t.Errorf("unexpected hot key report: %+v", hot)
+	}
+	if !strings.Contains(result.Report(), "HOT KEYS") {
+		t.Error("expected the report to contain the hot keys")
+	}
+
+	config.KeySampleRate = 0
+	if result, _ := New(config).Run(context.Background()); result.HotKeys != nil {
+		t.Errorf("expected no hot key report without sampling, got %+v", result.HotKeys)
+	}
+}
+
+func TestEngineNodeLimits(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 300 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.WriteRatio = 1
+	config.NodeLimits = node.Limits{MaxKeys: 10}
+
+	engine := New(config)
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	var evicted uint64
+	for _, n := range engine.Cluster().Nodes() {
+		if n.Size() > 10 {
+			t.Errorf("expected %s to hold at most 10 keys, got %d", n.ID(), n.Size())
+		}
+		evicted += n.Stats().Evicted
+	}
+	if evicted == 0 {
+		t.Error("expected keys to be evicted under the write load")
+	}
+}
+
+func TestEngineNodeMaxMemory(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 300 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.WriteRatio = 1
+	config.EnableChaos = false
+	config.NodeLimits = node.Limits{MaxMemory: 4096}
+
+	engine := New(config)
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if result.CapacityFailures == 0 || result.CapacityFailures > result.FailedRequests {
+		t.Errorf("expected capacity failures among the %d failed requests, got %d", result.FailedRequests, result.CapacityFailures)
+	}
+	for _, n := range engine.Cluster().Nodes() {
+		if n.MemoryUsage() > 4096 || n.Stats().Evicted != 0 {
+			t.Errorf("expected %s to refuse writes without evicting, got %d bytes and %d evicted", n.ID(), n.MemoryUsage(), n.Stats().Evicted)
+		}
+	}
+	if !strings.Contains(result.Report(), "Capacity Failures:") {
+		t.Error("expected the report to include the capacity failures")
+	}
+}
+
+func TestEngineStore(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 300 * time.Millisecond
+	config.NodeCount = 3
+	config.ClientWorkers = 2
+	config.WriteRatio = 1
+	config.EnableChaos = false
+
+	config.Store = node.StoreMap
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if rep := result.Replicas; rep == nil || rep.Store != node.StoreMap || rep.DivergentKeys == 0 || rep.Syncs != 0 {
+		t.Errorf("expected the map stores to diverge without syncs, got %+v", rep)
+	}
+	if !strings.Contains(result.Report(), "REPLICAS") {
+		t.Error("expected the report to include the replicas")
+	}
+
+	config.Store = node.StoreCRDT
+	config.SyncInterval = 50 * time.Millisecond
+	engine := New(config)
+	result, err = engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	rep := result.Replicas
+	if rep == nil || rep.Store != node.StoreCRDT || rep.Syncs == 0 || rep.Merged == 0 {
+		t.Fatalf("expected the CRDT stores to be synced, got %+v", rep)
+	}
+	// 最後の同期以降の書き込みの分だけ一致しないため、同期すれば収束する
+	if _, err := engine.Cluster().Sync(); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if got := engine.Cluster().Divergence(); got != 0 {
+		t.Errorf("expected the CRDT stores to converge, got %d divergent keys", got)
+	}
+
+	config.NodeProcess = true
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected the CRDT store to be rejected with node processes")
+	}
+}
+
+func TestEngineLatency(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 300 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.Zones = []string{"zone-a", "zone-b"}
+	config.Store = node.StoreCRDT
+	config.SyncInterval = 50 * time.Millisecond
+	config.Latency = cluster.LatencyMatrix{InterZone: 20 * time.Millisecond, Coordinator: "zone-a"}
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	// zone-b のノードへのリクエストはゾーンをまたいで転送される
+	if result.P99Latency < 20*time.Millisecond {
+		t.Errorf("expected cross-zone forwarding to show in the P99 latency, got %v", result.P99Latency)
+	}
+	if result.Replicas == nil || result.Replicas.Syncs == 0 {
+		t.Errorf("expected the stores to be synced across zones, got %+v", result.Replicas)
+	}
+
+	config.Latency.Links = []cluster.Link{{From: "zone-a"}}
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected an invalid latency matrix to be rejected")
+	}
+}
+
+func TestEngineBatchSize(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 200 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.BatchSize = 8
+
+	engine := New(config)
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	var ops uint64
+	for _, n := range engine.Cluster().Nodes() {
+		stats := n.Stats()
+		ops += stats.Gets + stats.Sets
+	}
+	if result.TotalRequests == 0 || ops < result.TotalRequests*8 {
+		t.Errorf("expected 8 keys per request, got %d operations for %d requests", ops, result.TotalRequests)
+	}
+
+	config.Linearizability = true
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected batched requests to be rejected with linearizability")
+	}
+}
+
+func TestEngineScanRatio(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 200 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.ScanRatio = 0.5
+	config.ScanLimit = 20
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if s := result.Scans; s == nil || s.Limit != 20 || s.Requests == 0 || s.Requests >= result.TotalRequests {
+		t.Errorf("expected part of the requests to be scans, got %+v of %d", s, result.TotalRequests)
+	}
+	if !strings.Contains(result.Report(), "SCANS") {
+		t.Error("expected the report to include the scans")
+	}
+}
+
+func TestEngineReplicationFactor(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 200 * time.Millisecond
+	config.NodeCount = 3
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.ReplicationFactor = 2
+
+	engine := New(config)
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if f := engine.Cluster().ReplicationFactor(); f != 2 {
+		t.Errorf("expected replication factor 2, got %d", f)
+	}
+
+	config.Consistency = cluster.Consistency{Write: cluster.ConsistencyAll, Read: cluster.ConsistencyQuorum}
+	engine = New(config)
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if got := engine.Cluster().Consistency(); got != config.Consistency {
+		t.Errorf("expected %v, got %v", config.Consistency, got)
+	}
+	config.Consistency.Read = "two"
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected an unknown consistency level to be rejected")
+	}
+	config.Consistency = cluster.Consistency{}
+
+	config.BatchSize = 4
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected batched requests to be rejected with replication")
+	}
+}
+
+func TestEngineAutoscale(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 500 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.Autoscale = autoscale.Config{
+		MinNodes:   2,
+		MaxNodes:   4,
+		ScaleUpRPS: 1,
+		Interval:   50 * time.Millisecond,
+	}
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	a := result.Autoscale
+	if a == nil || a.ScaleUps != 2 || a.MinNodes != 2 || a.MaxNodes != 4 || a.FinalNodes != 4 {
+		t.Fatalf("expected the cluster to grow to 4 nodes, got %+v", a)
+	}
+	if len(result.FinalNodeStatus) != 4 {
+		t.Errorf("expected 4 nodes in the final status, got %v", result.FinalNodeStatus)
+	}
+	if !strings.Contains(result.Report(), "AUTOSCALE") {
+		t.Error("expected the report to include the autoscaling")
+	}
+
+	config.Autoscale.MinNodes = 5
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected max nodes below min nodes to be rejected")
+	}
+}
+
+func TestEngineDurability(t *testing.T) {
+	steps := []Step{
+		{Kind: StepLoad, Duration: 100 * time.Millisecond},
+		{Kind: StepInject, Attack: chaos.AttackKill, Target: Selector{Nodes: []string{"node-1"}}},
+		{Kind: StepHeal, Target: Selector{Nodes: []string{"node-1"}}},
+	}
+	for _, mode := range []node.DurabilityMode{node.DurabilityMemory, node.DurabilityEphemeral, node.DurabilitySnapshot, node.DurabilityWAL} {
+		config := stepsConfig(steps...)
+		config.EnableChaos = false
+		config.WriteRatio = 1
+		config.Durability = node.Durability{Mode: mode, Interval: time.Hour}
+
+		engine := New(config)
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Fatalf("%s: failed to run scenario: %v", mode, err)
+		}
+		d := result.Durability
+		if d == nil || d.Mode != mode || d.Restarts != 1 {
+			t.Fatalf("%s: expected one restart in the report, got %+v", mode, d)
+		}
+		// メモリ上のデータは残り、スナップショット・ログを書き出す前の書き込みは失われる
+		if lost := d.LostKeys > 0; lost != (mode != node.DurabilityMemory) {
+			t.Errorf("%s: unexpected lost keys %d", mode, d.LostKeys)
+		}
+		if !strings.Contains(result.Report(), "DURABILITY") {
+			t.Errorf("%s: expected the report to include the durability", mode)
+		}
+		if engine.persistDir != "" {
+			t.Errorf("%s: expected the snapshot directory to be removed", mode)
+		}
+	}
+}
+
+func TestEngineEphemeralLossRatio(t *testing.T) {
+	config := stepsConfig(
+		Step{Kind: StepLoad, Duration: 100 * time.Millisecond},
+		Step{Kind: StepInject, Attack: chaos.AttackKill, Target: Selector{Nodes: []string{"node-1"}}},
+		Step{Kind: StepHeal, Target: Selector{Nodes: []string{"node-1"}}},
+	)
+	config.EnableChaos = false
+	config.WriteRatio = 1
+	config.Durability = node.Durability{EphemeralLossRatio: 1}
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	d := result.Durability
+	if d == nil || d.Mode != node.DurabilityMemory || d.Restarts != 1 {
+		t.Fatalf("expected one restart of memory nodes in the report, got %+v", d)
+	}
+	if d.LostKeys == 0 {
+		t.Error("expected the restarted node to lose its keys")
+	}
+}
+
+func TestEngineChaosReplay(t *testing.T) {
+	config := QuickScenario()
+	config.Duration = 500 * time.Millisecond
+	config.NodeCount = 3
+	config.ClientWorkers = 2
+	config.ChaosInterval = 100 * time.Millisecond
+	config.AttackTypes = []chaos.AttackType{chaos.AttackDelay, chaos.AttackKill}
+	config.EnableRecovery = false
+
+	recorded, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if len(recorded.Attacks) == 0 {
+		t.Fatal("expected the attacks to be recorded")
+	}
+
+	// 記録より長い間隔にしても、再生では記録した時刻に同じ攻撃を注入する
+	config.Duration = time.Second
+	config.ChaosInterval = time.Hour
+	config.ChaosReplay = recorded.Attacks
+	replayed, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to replay scenario: %v", err)
+	}
+	if len(replayed.Attacks) != len(recorded.Attacks) {
+		t.Fatalf("expected %d replayed attacks, got %+v", len(recorded.Attacks), replayed.Attacks)
+	}
+	for i, a := range replayed.Attacks {
+		want := recorded.Attacks[i]
+		if a.NodeID != want.NodeID || a.Type != want.Type || a.Delay != want.Delay || a.At < want.At {
+			t.Errorf("attack %d: expected %+v, got %+v", i, want, a)
+		}
+	}
+	if replayed.TotalAttacks != recorded.TotalAttacks {
+		t.Errorf("expected %d attacks, got %d", recorded.TotalAttacks, replayed.TotalAttacks)
+	}
+	if replayed.Summary().Attacks != nil {
+		t.Error("expected the summary to omit the attack record")
+	}
+}
+
+func TestEngineRunWithChaos(t *testing.T) {
+	config := QuickScenario()
+	config.Duration = 2 * time.Second
+	config.ChaosInterval = 500 * time.Millisecond
+
+	engine := New(config)
+	ctx := context.Background()
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+
+	if result.TotalAttacks == 0 {
+		t.Error("expected some attacks to be executed")
+	}
+}
+
+func TestEngineRunWithRecovery(t *testing.T) {
+	clk := clock.NewSimulated(time.Now())
+	config := Config{
+		Name:           "recovery-test",
+		Description:    "Test recovery",
+		Duration:       10 * time.Second,
+		NodeCount:      3,
+		ClientWorkers:  2,
+		WriteRatio:     0.5,
+		EnableChaos:    true,
+		ChaosInterval:  500 * time.Millisecond,
+		ChaosTargets:   1,
+		AttackTypes:    []chaos.AttackType{chaos.AttackSuspend},
+		EnableRecovery: true,
+		RecoveryDelay:  300 * time.Millisecond,
+		MaxRetries:     3,
+		Clock:          clk,
+	}
+
+	result := runSimulated(t, New(config), clk, 250*time.Millisecond)
+
+	// 10秒間の仮想時間で攻撃とヘルスチェックが繰り返されるため、復旧が発生しているはず
+	if result.TotalAttacks == 0 {
+		t.Error("expected some attacks to be executed")
+	}
+	if result.TotalRecoveries == 0 {
+		t.Error("expected some recoveries to be executed")
+	}
+	if result.Duration < config.Duration {
+		t.Errorf("expected the virtual duration to reach %v, got %v", config.Duration, result.Duration)
+	}
+}
+
+// runSimulated は仮想時計でシナリオを実行し、終了するまで step ずつ時計を進める
+func runSimulated(t *testing.T, engine *Engine, clk *clock.Simulated, step time.Duration) *Result {
+	t.Helper()
+
+	done := make(chan struct{})
+	var result *Result
+	var err error
+	go func() {
+		result, err = engine.Run(context.Background())
+		close(done)
+	}()
+
+	deadline := time.After(30 * time.Second)
+	for {
+		select {
+		case <-done:
+			if err != nil {
+				t.Fatalf("failed to run scenario: %v", err)
+			}
+			return result
+		case <-deadline:
+			t.Fatal("timeout waiting for the simulated scenario to finish")
+		default:
+			clk.Advance(step)
+			runtime.Gosched()
+		}
+	}
+}
+
+func TestEngineDoubleRun(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 2 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+
+	engine := New(config)
+	ctx := context.Background()
+
+	// 最初の実行を開始
+	done := make(chan struct{})
+	var firstResult *Result
+	var firstErr error
+
+	go func() {
+		firstResult, firstErr = engine.Run(ctx)
+		close(done)
+	}()
+
+	// 少し待ってから二重実行を試みる
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := engine.Run(ctx)
+	if err == nil {
+		t.Error("expected error when running already running scenario")
+	}
+
+	<-done
+	if firstErr != nil {
+		t.Errorf("first run failed: %v", firstErr)
+	}
+	if firstResult == nil {
+		t.Error("expected first result to be non-nil")
+	}
+}
+
+func TestResultReport(t *testing.T) {
+	result := &Result{
+		ScenarioName:      "test",
+		StartTime:         time.Now(),
+		EndTime:           time.Now().Add(10 * time.Second),
+		Duration:          10 * time.Second,
+		TotalRequests:     1000,
+		SuccessRequests:   990,
+		FailedRequests:    10,
+		ErrorRate:         0.01,
+		AvgLatency:        5 * time.Millisecond,
+		P99Latency:        20 * time.Millisecond,
+		TotalAttacks:      5,
+		TotalRecoveries:   3,
+		SuccessRecoveries: 3,
+		FailedRecoveries:  0,
+		FinalNodeStatus: map[string]string{
+			"node-1": "Running",
+			"node-2": "Running",
+		},
+	}
+
+	report := result.Report()
+
+	// レポートに必要な情報が含まれているか確認
+	if !strings.Contains(report, "test") {
+		t.Error("report should contain scenario name")
+	}
+	if !strings.Contains(report, "1000") {
+		t.Error("report should contain total requests")
+	}
+	if !strings.Contains(report, "1.00%") {
+		t.Error("report should contain error rate")
+	}
+	if !strings.Contains(report, "node-1") {
+		t.Error("report should contain node status")
+	}
+}
+
+func TestPresets(t *testing.T) {
+	presets := ListPresets()
+
+	if len(presets) != 5 {
+		t.Errorf("expected 5 presets, got %d", len(presets))
+	}
+
+	for _, name := range presets {
+		config, ok := GetPreset(name)
+		if !ok {
+			t.Errorf("failed to get preset '%s'", name)
+			continue
+		}
+		if config.Name != name {
+			t.Errorf("expected preset name '%s', got '%s'", name, config.Name)
+		}
+	}
+}
+
+func TestGetPresetNotFound(t *testing.T) {
+	_, ok := GetPreset("nonexistent")
+	if ok {
+		t.Error("expected GetPreset to return false for nonexistent preset")
+	}
+}
+
+func TestBasicScenario(t *testing.T) {
+	config := BasicScenario()
+
+	if config.EnableChaos {
+		t.Error("basic scenario should not enable chaos")
+	}
+	if config.EnableRecovery {
+		t.Error("basic scenario should not enable recovery")
+	}
+}
+
+func TestResilienceScenario(t *testing.T) {
+	config := ResilienceScenario()
+
+	if !config.EnableChaos {
+		t.Error("resilience scenario should enable chaos")
+	}
+	if len(config.AttackTypes) != 1 || config.AttackTypes[0] != chaos.AttackKill {
+		t.Error("resilience scenario should only use kill attack")
+	}
+	if !config.EnableRecovery {
+		t.Error("resilience scenario should enable recovery")
+	}
+}
+
+func TestLatencyScenario(t *testing.T) {
+	config := LatencyScenario()
+
+	if len(config.AttackTypes) != 1 || config.AttackTypes[0] != chaos.AttackDelay {
+		t.Error("latency scenario should only use delay attack")
+	}
+}
+
+func TestStressScenario(t *testing.T) {
+	config := StressScenario()
+
+	if config.ClientWorkers < 50 {
+		t.Error("stress scenario should have many workers")
+	}
+	if len(config.AttackTypes) != 3 {
+		t.Error("stress scenario should use all attack types")
+	}
+}
+
+func TestQuickScenario(t *testing.T) {
+	config := QuickScenario()
+
+	if config.Duration > 10*time.Second {
+		t.Error("quick scenario should be short")
+	}
+}
+
+func TestEngineContextCancel(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 10 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+
+	engine := New(config)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	var result *Result
+	var err error
+
+	go func() {
+		result, err = engine.Run(ctx)
+		close(done)
+	}()
+
+	// 少し待ってからキャンセル
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+
+	<-done
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected result to be non-nil")
+	}
+	if result.Duration >= config.Duration {
+		t.Error("expected scenario to be cancelled early")
+	}
+}
+
+func TestEngineStop(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 10 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+
+	engine := New(config)
+
+	if engine.Stop() {
+		t.Error("expected Stop to return false when not running")
+	}
+
+	done := make(chan struct{})
+	var result *Result
+	var err error
+
+	go func() {
+		result, err = engine.Run(context.Background())
+		close(done)
+	}()
+
+	time.Sleep(300 * time.Millisecond)
+	if !engine.Stop() {
+		t.Error("expected Stop to return true while running")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for scenario to stop")
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Interrupted {
+		t.Error("expected result to be marked as interrupted")
+	}
+	if result.Duration >= config.Duration {
+		t.Error("expected scenario to be stopped early")
+	}
+	if !strings.Contains(result.Report(), "interrupted") {
+		t.Error("report should contain interrupted status")
+	}
+}
+
+func TestEngineMetricsSnapshots(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 350 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.MetricsInterval = 100 * time.Millisecond
+
+	bus := events.NewBus()
+	ch := bus.SubscribeFiltered(events.ByType(events.EventMetricsSnapshot))
+	engine := New(config)
+	engine.SetEventBus(bus)
+
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	bus.Close()
+
+	var snapshots []events.Event
+	for e := range ch {
+		snapshots = append(snapshots, e)
+	}
+	if len(snapshots) < 2 {
+		t.Fatalf("expected at least 2 snapshots, got %d", len(snapshots))
+	}
+	last := snapshots[len(snapshots)-1].Data
+	if last.Requests == 0 || last.NodeCount != 2 || last.NodesRunning != 2 {
+		t.Errorf("unexpected snapshot data: %+v", last)
+	}
+}
+
+func TestEngineNotifiers(t *testing.T) {
+	var mu sync.Mutex
+	var messages []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		messages = append(messages, body["text"])
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	config := BasicScenario()
+	config.Duration = 200 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 1
+	config.Notifiers = []events.NotifierConfig{
+		{Kind: events.NotifierSlack, URL: ts.URL, Template: "{{.Type}} {{.Data.Scenario}}"},
+	}
+
+	if _, err := New(config).Run(context.Background()); err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"scenario_started basic", "scenario_finished basic"}
+	if len(messages) != 2 || messages[0] != want[0] || messages[1] != want[1] {
+		t.Errorf("expected %q, got %q", want, messages)
+	}
+
+	config.Notifiers[0].Kind = "teams"
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected error for invalid notifier")
+	}
+}
+
+func TestEngineRESP(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 5 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 1
+	config.RESPAddr = "127.0.0.1:0"
+
+	engine := New(config)
+	done := make(chan error, 1)
+	go func() {
+		_, err := engine.Run(context.Background())
+		done <- err
+	}()
+
+	var addrs []string
+	for deadline := time.Now().Add(5 * time.Second); len(addrs) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("RESP server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+		addrs = engine.RESPAddrs()
+	}
+
+	conn, err := net.Dial("tcp", addrs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := io.WriteString(conn, "SET foo bar\r\nGET foo\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	var replies []string
+	for range 3 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, strings.TrimSpace(line))
+	}
+	if strings.Join(replies, " ") != "+OK $3 bar" {
+		t.Errorf("unexpected replies: %q", replies)
+	}
+
+	engine.Stop()
+	if err := <-done; err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if len(engine.RESPAddrs()) != 0 {
+		t.Error("RESP servers should be stopped after the run")
+	}
+}
+
+func TestEngineRESPListenError(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = lis.Close() }()
+
+	config := BasicScenario()
+	config.NodeCount = 1
+	config.RESPAddr = lis.Addr().String()
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected error when the RESP address is in use")
+	}
+}
+
+func TestEngineNodeHTTP(t *testing.T) {
+	config := BasicScenario()
+	config.Duration = 5 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.EnableRecovery = false
+	config.NodeHTTP = true
+
+	engine := New(config)
+	done := make(chan *Result, 1)
+	go func() {
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Errorf("failed to run scenario: %v", err)
+		}
+		done <- result
+	}()
+
+	var urls []string
+	for deadline := time.Now().Add(5 * time.Second); len(urls) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("node HTTP servers did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+		urls = engine.NodeHTTPURLs()
+	}
+	if len(urls) != 2 {
+		t.Fatalf("expected 2 node HTTP servers, got %v", urls)
+	}
+
+	// 停止したノードへのリクエストは接続のリセットとして失敗する
+	time.Sleep(100 * time.Millisecond)
+	n, ok := engine.Cluster().GetNode("node-1")
+	if !ok {
+		t.Fatal("node-1 not found")
+	}
+	if err := n.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	engine.Stop()
+	result := <-done
+	if result == nil {
+		return
+	}
+	if result.SuccessRequests == 0 {
+		t.Error("expected successful requests over HTTP")
+	}
+	if result.FailedRequests == 0 {
+		t.Error("expected requests to the killed node to fail")
+	}
+	if len(engine.NodeHTTPURLs()) != 0 {
+		t.Error("node HTTP servers should be stopped after the run")
+	}
+}
+
+func TestEngineNodeHTTPListenError(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = lis.Close() }()
+
+	config := BasicScenario()
+	config.NodeCount = 1
+	config.NodeHTTP = true
+	config.NodeHTTPPort = lis.Addr().(*net.TCPAddr).Port
+	if _, err := New(config).Run(context.Background()); err == nil {
+		t.Error("expected error when the node HTTP port is in use")
+	}
+}
+
+func TestEngineNodeProcess(t *testing.T) {
+	t.Setenv(nodeProcessEnv, "1")
+
+	config := BasicScenario()
+	config.Duration = 5 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.EnableRecovery = false
+	config.NodeProcess = true
+	config.NodeCommand = []string{os.Args[0]}
+
+	engine := New(config)
+	done := make(chan *Result, 1)
+	go func() {
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Errorf("failed to run scenario: %v", err)
+		}
+		done <- result
+	}()
+
+	for deadline := time.Now().Add(10 * time.Second); engine.Cluster() == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("node processes did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	var procs []*procnode.Process
+	for _, n := range engine.Cluster().Nodes() {
+		p, ok := n.Backend().(*procnode.Process)
+		if !ok || p.Pid() == 0 {
+			t.Fatalf("expected %s to run as a process", n.ID())
+		}
+		procs = append(procs, p)
+	}
+
+	// kill 攻撃はプロセスを強制終了し、リクエストは失敗する
+	time.Sleep(100 * time.Millisecond)
+	if err := engine.Monkey().Inject("node-1", chaos.AttackKill); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range procs {
+		if killed := p.Pid() == 0; killed != (p.ID() == "node-1") {
+			t.Errorf("unexpected %s process state after killing node-1 (pid %d)", p.ID(), p.Pid())
+		}
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	engine.Stop()
+	result := <-done
+	if result == nil {
+		return
+	}
+	if result.SuccessRequests == 0 {
+		t.Error("expected successful requests to the node processes")
+	}
+	if result.FailedRequests == 0 {
+		t.Error("expected requests to the killed process to fail")
+	}
+	for _, p := range procs {
+		if p.Pid() != 0 {
+			t.Errorf("expected %s process to be stopped after the run", p.ID())
+		}
+	}
+}
+
+func TestEngineNodeProcessErrors(t *testing.T) {
+	config := BasicScenario()
+	config.NodeCount = 1
+	config.NodeProcess = true
+	config.NodeHTTP = true
+	if _, err := New(config).Run(context.Background()); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("expected combination error, got %v", err)
+	}
+
+	config.NodeHTTP = false
+	config.NodeCommand = []string{"sh", "-c", "exit 1", "sh"}
+	if _, err := New(config).Run(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to start nodes") {
+		t.Errorf("expected start error, got %v", err)
+	}
+}
+
+// startExternalBackend は外部のプロセスの代わりに、ノードをRESPで公開するサーバーを起動する
+func startExternalBackend(t *testing.T) (*node.Node, string) {
+	t.Helper()
+	backend := node.New("backend")
+	if err := backend.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	config := resp.DefaultConfig()
+	config.Addr = "127.0.0.1:0"
+	s := resp.New(config, resp.NodeRouter(backend))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+	return backend, s.Addr()
+}
+
+func TestEngineExternal(t *testing.T) {
+	backend, addr := startExternalBackend(t)
+	logFile := filepath.Join(t.TempDir(), "ops.log")
+	record := func(op string) string { return "echo " + op + " >> " + logFile }
+
+	ext := external.DefaultConfig()
+	ext.ID = "redis-1"
+	ext.Addr = addr
+	ext.Commands = external.Commands{Start: record("start"), Stop: record("stop")}
+
+	config := BasicScenario()
+	config.Duration = 5 * time.Second
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.EnableRecovery = false
+	config.External = []external.Config{ext}
+
+	engine := New(config)
+	done := make(chan *Result, 1)
+	go func() {
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Errorf("failed to run scenario: %v", err)
+		}
+		done <- result
+	}()
+
+	var n *node.Node
+	for deadline := time.Now().Add(5 * time.Second); n == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("external node was not created")
+		}
+		time.Sleep(10 * time.Millisecond)
+		if c := engine.Cluster(); c != nil {
+			n, _ = c.GetNode("redis-1")
+		}
+	}
+	time.Sleep(200 * time.Millisecond)
+	if err := n.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Stop()
+	result := <-done
+	if result == nil {
+		return
+	}
+	if result.SuccessRequests == 0 || backend.Stats().Sets == 0 {
+		t.Errorf("expected requests to reach the external backend (success: %d, backend sets: %d)",
+			result.SuccessRequests, backend.Stats().Sets)
+	}
+	if engine.Cluster().Size() != 1 {
+		t.Errorf("expected only the external node in the cluster, got %d nodes", engine.Cluster().Size())
+	}
+
+	// 起動・kill・終了時の復元（停止したプロセスの起動）の順にコマンドが実行される
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(data)); strings.Join(got, " ") != "start stop start" {
+		t.Errorf("expected commands start stop start, got %q", got)
+	}
+	if n.Backend() != nil {
+		t.Error("external backend should be detached after the run")
+	}
+}
+
+func TestEngineExternalUnreachable(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	ext := external.DefaultConfig()
+	ext.ID = "redis-1"
+	ext.Addr = addr
+
+	config := BasicScenario()
+	config.External = []external.Config{ext}
+	_, err = New(config).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Errorf("expected unreachable error, got %v", err)
+	}
+}
+
+// fakeToxiproxy はリクエストを記録し、プロキシの待ち受けアドレスとして転送先をそのまま返すToxiproxyのAPI
+type fakeToxiproxy struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func startFakeToxiproxy(t *testing.T) (*fakeToxiproxy, string) {
+	t.Helper()
+	f := &fakeToxiproxy{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Name, Upstream string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.mu.Lock()
+		f.requests = append(f.requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+body.Name))
+		f.mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte(`{"version":"2.9.0"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/proxies":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]string{"name": body.Name, "listen": body.Upstream})
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+// has は記録したリクエストに request があるかを返す
+func (f *fakeToxiproxy) has(request string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.requests {
+		if r == request {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEngineToxiproxy(t *testing.T) {
+	f, url := startFakeToxiproxy(t)
+
+	config := BasicScenario()
+	config.Duration = 5 * time.Second
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.NodeHTTP = true
+	config.Toxiproxy = toxiproxy.DefaultConfig()
+	config.Toxiproxy.URL = url
+
+	engine := New(config)
+	done := make(chan *Result, 1)
+	go func() {
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Errorf("failed to run scenario: %v", err)
+		}
+		done <- result
+	}()
+
+	for deadline := time.Now().Add(5 * time.Second); len(engine.NodeHTTPURLs()) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("node HTTP servers did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, want := range []string{"GET /version", "POST /proxies chaos-kvs-node-1", "POST /proxies chaos-kvs-node-2"} {
+		if !f.has(want) {
+			t.Errorf("expected request %q", want)
+		}
+	}
+
+	// 攻撃はプロキシへの障害として注入する
+	time.Sleep(100 * time.Millisecond)
+	if err := engine.Monkey().Inject("node-1", chaos.AttackKill); err != nil {
+		t.Fatal(err)
+	}
+	if !f.has("POST /proxies/chaos-kvs-node-1/toxics chaos-kvs-reset") {
+		t.Error("expected the kill attack to add a reset toxic")
+	}
+
+	engine.Stop()
+	if result := <-done; result != nil && result.SuccessRequests == 0 {
+		t.Error("expected successful requests through the proxies")
+	}
+	if !f.has("DELETE /proxies/chaos-kvs-node-1") || !f.has("DELETE /proxies/chaos-kvs-node-2") {
+		t.Error("expected proxies to be deleted after the run")
+	}
+	n, _ := engine.Cluster().GetNode("node-1")
+	if n.Backend() != nil {
+		t.Error("expected the proxy to be detached from the node after the run")
+	}
+}
+
+func TestEngineToxiproxyErrors(t *testing.T) {
+	config := BasicScenario()
+	config.NodeCount = 1
+	config.Toxiproxy = toxiproxy.DefaultConfig()
+	if _, err := New(config).Run(context.Background()); err == nil || !strings.Contains(err.Error(), "requires node HTTP") {
+		t.Errorf("expected error without node HTTP, got %v", err)
+	}
+
+	config.NodeHTTP = true
+	config.Toxiproxy.URL = "http://127.0.0.1:1"
+	if _, err := New(config).Run(context.Background()); err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Errorf("expected unreachable error, got %v", err)
+	}
+}
+
+// tracedSpan はOTLPで受け取ったスパンのうちテストで確かめる項目
+type tracedSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+}
+
+// startTraceCollector はOTLP/HTTPの受信先の代わりに、受け取ったスパンを記録するサーバーを起動する
+func startTraceCollector(t *testing.T) (func() []tracedSpan, string) {
+	t.Helper()
+	var mu sync.Mutex
+	var spans []tracedSpan
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []tracedSpan `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return func() []tracedSpan {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]tracedSpan(nil), spans...)
+	}, ts.URL
+}
+
+func TestEngineTracing(t *testing.T) {
+	spans, url := startTraceCollector(t)
+
+	config := BasicScenario()
+	config.Duration = 300 * time.Millisecond
+	config.NodeCount = 2
+	config.ClientWorkers = 2
+	config.EnableChaos = false
+	config.Tracing = tracing.DefaultConfig()
+	config.Tracing.Endpoint = url
+	config.Tracing.SampleRate = 0.5
+
+	engine := New(config)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = engine.Monkey().Inject("node-1", chaos.AttackKill)
+	}()
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string][]tracedSpan)
+	for _, s := range spans() {
+		if s.TraceID != result.TraceID {
+			t.Fatalf("expected every span in trace %s, got %+v", result.TraceID, s)
+		}
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+	root := byName["scenario basic"]
+	if len(root) != 1 {
+		t.Fatalf("expected one root span, got %v", byName)
+	}
+	for _, name := range []string{"setup", "load", "teardown"} {
+		if got := byName[name]; len(got) != 1 || got[0].ParentSpanID != root[0].SpanID {
+			t.Errorf("expected %s phase under the root span, got %+v", name, got)
+		}
+	}
+	load := byName["load"][0]
+	if kill := byName["attack kill"]; len(kill) != 1 || kill[0].ParentSpanID != load.SpanID {
+		t.Errorf("expected kill attack under the load span, got %+v", kill)
+	}
+	if len(byName["GET"])+len(byName["SET"]) == 0 {
+		t.Error("expected sampled request spans")
+	}
+}
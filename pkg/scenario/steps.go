@@ -0,0 +1,426 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+	"github.com/nyasuto/chaos-kvs/pkg/clock"
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+	"github.com/nyasuto/chaos-kvs/pkg/tracing"
+)
+
+// StepKind はステップの種類
+type StepKind string
+
+const (
+	StepLoad   StepKind = "load"   // ワーカー数を変えて一定時間負荷をかける
+	StepWait   StepKind = "wait"   // 一定時間、または条件を満たすまで待つ
+	StepInject StepKind = "inject" // 選択したノードに障害を注入する
+	StepHeal   StepKind = "heal"   // 選択したノードの障害を取り除く
+	StepAssert StepKind = "assert" // その時点で条件を満たすかを判定する
+)
+
+// stepPollInterval は wait のステップが条件を確かめる間隔
+const stepPollInterval = 100 * time.Millisecond
+
+// Step はシナリオの手順の1つ
+// Config.Steps を指定すると、エンジンは負荷の開始後にステップを順に実行する
+type Step struct {
+	Kind StepKind
+	Name string // ログ・レポートに表示する名前（省略可）
+
+	Duration time.Duration // load: 負荷をかける時間、wait: 待つ時間（Condition 指定時は上限、0で無制限）
+	Workers  int           // load: ワーカー数（0で変えない）
+
+	Attack chaos.AttackType // inject: 注入する障害
+	Delay  time.Duration    // inject: delay の遅延（0でカオスモンキーの設定値）
+	Target Selector         // inject・heal: 対象のノード
+
+	Condition Condition // assert: 判定する条件、wait: 満たすまで待つ条件（ゼロ値で時間だけ待つ）
+}
+
+// Selector はステップの対象ノードの選び方（ゼロ値で全ノード）
+type Selector struct {
+	Nodes []string // ノードID（空で全ノード）
+	Zone  string   // ゾーン（空で全ゾーン）
+	Count int      // 一致したノードから無作為に選ぶ数（0で全て）
+}
+
+// Condition はステップで判定する条件（ゼロ値の項目は判定しない）
+// メトリクスは負荷の開始からの累計で判定する
+type Condition struct {
+	Assertions
+	MinNodesRunning int // 実行中のノード数の下限
+}
+
+// StepResult はステップの実行結果（Start・Duration はナノ秒）
+type StepResult struct {
+	Index       int                `json:"index"`
+	Kind        StepKind           `json:"kind"`
+	Name        string             `json:"name,omitempty"`
+	Description string             `json:"description"`
+	Start       time.Duration      `json:"start"` // 負荷の開始からの経過時間
+	Duration    time.Duration      `json:"duration"`
+	Targets     []string           `json:"targets,omitempty"`  // inject・heal で操作したノード
+	Failures    []AssertionFailure `json:"failures,omitempty"` // 満たされなかった条件
+	Error       string             `json:"error,omitempty"`    // ステップが失敗した理由（以降のステップは実行しない）
+}
+
+// Validate はステップの設定を検証する
+func (s Step) Validate() error {
+	switch s.Kind {
+	case StepLoad:
+		if s.Workers < 0 {
+			return fmt.Errorf("load workers must be non-negative")
+		}
+		if s.Duration <= 0 {
+			return fmt.Errorf("load requires a positive duration")
+		}
+	case StepWait:
+		if s.Duration < 0 {
+			return fmt.Errorf("wait duration must be non-negative")
+		}
+		if s.Duration == 0 && s.Condition == (Condition{}) {
+			return fmt.Errorf("wait requires a duration or a condition")
+		}
+	case StepInject:
+		switch s.Attack {
+		case chaos.AttackKill, chaos.AttackSuspend, chaos.AttackDelay, chaos.AttackReadOnly, chaos.AttackCorrupt:
+		default:
+			return fmt.Errorf("unknown attack type: %s", s.Attack)
+		}
+		if s.Delay < 0 {
+			return fmt.Errorf("inject delay must be non-negative")
+		}
+	case StepHeal:
+	case StepAssert:
+		if s.Condition == (Condition{}) {
+			return fmt.Errorf("assert requires a condition")
+		}
+	default:
+		return fmt.Errorf("unknown step kind: %q", s.Kind)
+	}
+	if s.Target.Count < 0 {
+		return fmt.Errorf("target count must be non-negative")
+	}
+	if s.Condition.MinNodesRunning < 0 {
+		return fmt.Errorf("min_nodes_running must be non-negative")
+	}
+	return nil
+}
+
+// String はステップの内容を返す
+func (s Step) String() string {
+	switch s.Kind {
+	case StepLoad:
+		if s.Workers > 0 {
+			return fmt.Sprintf("load %v with %d workers", s.Duration, s.Workers)
+		}
+		return fmt.Sprintf("load %v", s.Duration)
+	case StepWait:
+		if s.Condition == (Condition{}) {
+			return fmt.Sprintf("wait %v", s.Duration)
+		}
+		if s.Duration > 0 {
+			return fmt.Sprintf("wait until %s (timeout %v)", s.Condition, s.Duration)
+		}
+		return fmt.Sprintf("wait until %s", s.Condition)
+	case StepInject:
+		attack := s.Attack.String()
+		if s.Attack == chaos.AttackDelay && s.Delay > 0 {
+			attack = fmt.Sprintf("delay %v", s.Delay)
+		}
+		return fmt.Sprintf("inject %s into %s", attack, s.Target)
+	case StepHeal:
+		return fmt.Sprintf("heal %s", s.Target)
+	case StepAssert:
+		return fmt.Sprintf("assert %s", s.Condition)
+	default:
+		return string(s.Kind)
+	}
+}
+
+// String は選び方を返す
+func (s Selector) String() string {
+	var b strings.Builder
+	switch {
+	case len(s.Nodes) > 0:
+		b.WriteString(strings.Join(s.Nodes, ","))
+	case s.Zone != "":
+		b.WriteString("nodes")
+	default:
+		b.WriteString("all nodes")
+	}
+	if s.Zone != "" {
+		fmt.Fprintf(&b, " in zone %s", s.Zone)
+	}
+	if s.Count > 0 {
+		fmt.Fprintf(&b, " (%d at random)", s.Count)
+	}
+	return b.String()
+}
+
+// String は条件をカンマ区切りで返す
+func (c Condition) String() string {
+	conditions := c.Assertions.conditions()
+	if c.MinNodesRunning > 0 {
+		conditions = append(conditions, fmt.Sprintf("nodes_running >= %d", c.MinNodesRunning))
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// matches はノードが選び方に一致するかを返す
+func (s Selector) matches(n *node.Node) bool {
+	if s.Zone != "" && n.Label(node.LabelZone) != s.Zone {
+		return false
+	}
+	if len(s.Nodes) == 0 {
+		return true
+	}
+	for _, id := range s.Nodes {
+		if n.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// runSteps は Config.Steps を順に実行して結果を記録する
+// ステップが失敗した場合、または ctx が終了した場合は残りのステップを実行しない
+func (e *Engine) runSteps(ctx context.Context, parent *tracing.Span) {
+	start := e.config.Clock.Now()
+	for i, step := range e.config.Steps {
+		label := step.String()
+		if step.Name != "" {
+			label = step.Name + ": " + label
+		}
+		log.Info("", "Step %d/%d: %s", i+1, len(e.config.Steps), label)
+
+		span := parent.Child("step "+string(step.Kind),
+			tracing.Int("step.index", int64(i)),
+			tracing.String("step.description", step.String()))
+		stepStart := e.config.Clock.Now()
+		r := StepResult{
+			Index:       i,
+			Kind:        step.Kind,
+			Name:        step.Name,
+			Description: step.String(),
+			Start:       stepStart.Sub(start),
+		}
+		err := e.runStep(ctx, step, start, &r)
+		r.Duration = e.config.Clock.Since(stepStart)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = errors.New("scenario ended before the step finished")
+			}
+			r.Error = err.Error()
+			span.SetError(err)
+		}
+		for _, f := range r.Failures {
+			log.Warn("", "Step %d: condition failed: %s", i+1, f)
+		}
+		span.End()
+
+		e.mu.Lock()
+		e.steps = append(e.steps, r)
+		e.mu.Unlock()
+
+		if err != nil {
+			log.Warn("", "Step %d failed, skipping the remaining steps: %v", i+1, err)
+			return
+		}
+	}
+	log.Info("", "All %d steps completed", len(e.config.Steps))
+}
+
+// runStep はステップを1つ実行する。start は負荷の開始時刻
+func (e *Engine) runStep(ctx context.Context, step Step, start time.Time, r *StepResult) error {
+	switch step.Kind {
+	case StepLoad:
+		if step.Workers > 0 {
+			e.client.SetWorkers(step.Workers)
+		}
+		return clock.Sleep(ctx, e.config.Clock, step.Duration)
+
+	case StepWait:
+		if step.Condition == (Condition{}) {
+			return clock.Sleep(ctx, e.config.Clock, step.Duration)
+		}
+		return e.waitFor(ctx, step, start, r)
+
+	case StepInject:
+		return e.inject(step, r)
+
+	case StepHeal:
+		return e.heal(step, r)
+
+	case StepAssert:
+		r.Failures = e.checkCondition(step.Condition, start)
+	}
+	return nil
+}
+
+// waitFor は条件を満たすまで待つ。上限時間を過ぎた場合は満たされなかった条件を記録してエラーを返す
+func (e *Engine) waitFor(ctx context.Context, step Step, start time.Time, r *StepResult) error {
+	waitCtx, cancel := ctx, context.CancelFunc(func() {})
+	if step.Duration > 0 {
+		waitCtx, cancel = clock.WithTimeout(ctx, e.config.Clock, step.Duration)
+	}
+	defer cancel()
+
+	for {
+		failures := e.checkCondition(step.Condition, start)
+		if len(failures) == 0 {
+			return nil
+		}
+		if err := clock.Sleep(waitCtx, e.config.Clock, stepPollInterval); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.Failures = failures
+			return fmt.Errorf("condition not met within %v", step.Duration)
+		}
+	}
+}
+
+// checkCondition は現在のメトリクス・ノード数が満たさない条件を返す
+func (e *Engine) checkCondition(c Condition, start time.Time) []AssertionFailure {
+	snapshot := e.client.Metrics().Snapshot()
+	current := &Result{
+		TotalRequests: snapshot.TotalRequests,
+		ErrorRate:     snapshot.ErrorRate,
+		AvgLatency:    snapshot.AverageLatency,
+		P99Latency:    snapshot.P99Latency,
+		Duration:      e.config.Clock.Since(start),
+	}
+	failures := c.Assertions.Check(current)
+	if running := e.cluster.RunningCount(); c.MinNodesRunning > 0 && running < c.MinNodesRunning {
+		failures = append(failures, AssertionFailure{"nodes_running", float64(c.MinNodesRunning), float64(running)})
+	}
+	return failures
+}
+
+// inject は選択した実行中のノードに障害を注入する
+func (e *Engine) inject(step Step, r *StepResult) error {
+	targets := e.selectNodes(step.Target, func(n *node.Node) bool {
+		return n.Status() == node.StatusRunning
+	})
+	if len(targets) == 0 {
+		return fmt.Errorf("no running node matches %s", step.Target)
+	}
+
+	for _, n := range targets {
+		var err error
+		if step.Attack == chaos.AttackDelay && step.Delay > 0 {
+			err = e.monkey.InjectDelay(n.ID(), step.Delay)
+		} else {
+			err = e.monkey.Inject(n.ID(), step.Attack)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to inject %s into %s: %w", step.Attack, n.ID(), err)
+		}
+		r.Targets = append(r.Targets, n.ID())
+	}
+	return nil
+}
+
+// heal は選択したノードのうち停止・一時停止・読み取り専用・遅延・破損しているものを起動・再開し、遅延と破損を解除する
+// 障害のあるノードが選ばれなかった場合は何もしない
+func (e *Engine) heal(step Step, r *StepResult) error {
+	targets := e.selectNodes(step.Target, func(n *node.Node) bool {
+		return n.Status() != node.StatusRunning || n.Delay() > 0 || n.Corruption() > 0
+	})
+
+	for _, n := range targets {
+		var err error
+		switch n.Status() {
+		case node.StatusSuspended, node.StatusReadOnly:
+			err = e.monkey.Resume(n.ID())
+		case node.StatusStopped:
+			err = e.cluster.StartNode(n.ID())
+		}
+		if err == nil && n.Delay() > 0 {
+			err = e.monkey.InjectDelay(n.ID(), 0)
+		}
+		if err == nil && n.Corruption() > 0 {
+			err = e.monkey.InjectCorruption(n.ID(), 0)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to heal %s: %w", n.ID(), err)
+		}
+		r.Targets = append(r.Targets, n.ID())
+	}
+	return nil
+}
+
+// selectNodes は選び方と eligible に一致するノードをID順に返す（Count 指定時は無作為に選ぶ）
+func (e *Engine) selectNodes(s Selector, eligible func(*node.Node) bool) []*node.Node {
+	var nodes []*node.Node
+	for _, n := range e.cluster.Nodes() {
+		if s.matches(n) && eligible(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	if s.Count > 0 && s.Count < len(nodes) {
+		rand.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+		nodes = nodes[:s.Count]
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i].ID(), nodes[j].ID()
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return nodes
+}
+
+// stepsDuration はステップの load・wait の時間の合計を返す（条件を待つ時間は上限で数える）
+func stepsDuration(steps []Step) time.Duration {
+	var total time.Duration
+	for _, s := range steps {
+		if s.Kind == StepLoad || s.Kind == StepWait {
+			total += s.Duration
+		}
+	}
+	return total
+}
+
+// FailedStep は失敗したステップを返す（全て成功した場合は nil）
+func (r *Result) FailedStep() *StepResult {
+	for i := range r.Steps {
+		if r.Steps[i].Error != "" {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+// stepRows はステップの結果を「番号 経過時間」と内容の組にする
+func (r *Result) stepRows() [][2]string {
+	rows := make([][2]string, 0, len(r.Steps))
+	for _, s := range r.Steps {
+		label := fmt.Sprintf("#%-3d +%v", s.Index+1, s.Start.Round(time.Millisecond))
+		text := s.Description
+		if s.Name != "" {
+			text = s.Name + ": " + text
+		}
+		if len(s.Targets) > 0 {
+			text += " -> " + strings.Join(s.Targets, ",")
+		}
+		for _, f := range s.Failures {
+			text += "; failed " + f.String()
+		}
+		if s.Error != "" {
+			text += "; error: " + s.Error
+		}
+		rows = append(rows, [2]string{label, text})
+	}
+	return rows
+}
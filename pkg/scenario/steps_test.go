@@ -0,0 +1,207 @@
+package scenario
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/chaos"
+)
+
+// stepsConfig はカオス・復旧なしでステップだけを実行する設定を返す
+func stepsConfig(steps ...Step) Config {
+	config := BasicScenario()
+	config.Duration = 0
+	config.NodeCount = 3
+	config.ClientWorkers = 2
+	config.Zones = []string{"zone-a", "zone-b"}
+	config.Steps = steps
+	return config
+}
+
+func TestStepValidate(t *testing.T) {
+	valid := []Step{
+		{Kind: StepLoad, Duration: time.Second},
+		{Kind: StepWait, Duration: time.Second},
+		{Kind: StepWait, Condition: Condition{MinNodesRunning: 1}},
+		{Kind: StepInject, Attack: chaos.AttackDelay, Delay: time.Millisecond},
+		{Kind: StepHeal},
+		{Kind: StepAssert, Condition: Condition{Assertions: Assertions{MaxErrorRate: 0.1}}},
+	}
+	for _, s := range valid {
+		if err := s.Validate(); err != nil {
+			t.Errorf("expected %s to be valid, got %v", s, err)
+		}
+	}
+
+	invalid := []Step{
+		{Kind: "sleep"},
+		{Kind: StepLoad},
+		{Kind: StepLoad, Duration: time.Second, Workers: -1},
+		{Kind: StepWait},
+		{Kind: StepInject, Attack: chaos.AttackType(9)},
+		{Kind: StepInject, Target: Selector{Count: -1}},
+		{Kind: StepAssert},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", s)
+		}
+	}
+}
+
+func TestStepString(t *testing.T) {
+	tests := []struct {
+		step Step
+		want string
+	}{
+		{Step{Kind: StepLoad, Duration: 10 * time.Second, Workers: 4}, "load 10s with 4 workers"},
+		{Step{Kind: StepWait, Duration: time.Second}, "wait 1s"},
+		{Step{Kind: StepWait, Duration: 30 * time.Second, Condition: Condition{MinNodesRunning: 3}}, "wait until nodes_running >= 3 (timeout 30s)"},
+		{Step{Kind: StepInject, Attack: chaos.AttackKill, Target: Selector{Zone: "a", Count: 1}}, "inject kill into nodes in zone a (1 at random)"},
+		{Step{Kind: StepInject, Attack: chaos.AttackDelay, Delay: 50 * time.Millisecond, Target: Selector{Nodes: []string{"node-1", "node-2"}}}, "inject delay 50ms into node-1,node-2"},
+		{Step{Kind: StepHeal}, "heal all nodes"},
+		{Step{Kind: StepAssert, Condition: Condition{Assertions: Assertions{MaxErrorRate: 0.05, MaxP99Latency: 20 * time.Millisecond}}}, "assert error_rate <= 0.05, p99_latency <= 20ms"},
+	}
+	for _, tt := range tests {
+		if got := tt.step.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestEngineRunSteps(t *testing.T) {
+	config := stepsConfig(
+		Step{Kind: StepLoad, Duration: 50 * time.Millisecond, Workers: 1},
+		Step{Kind: StepInject, Attack: chaos.AttackSuspend, Target: Selector{Nodes: []string{"node-1"}}},
+		Step{Kind: StepAssert, Name: "all up", Condition: Condition{MinNodesRunning: 3}},
+		Step{Kind: StepHeal},
+		Step{Kind: StepWait, Duration: time.Second, Condition: Condition{MinNodesRunning: 3}},
+		Step{Kind: StepInject, Attack: chaos.AttackKill, Target: Selector{Zone: "zone-a", Count: 1}},
+		Step{Kind: StepInject, Attack: chaos.AttackDelay, Delay: time.Millisecond, Target: Selector{Zone: "zone-b"}},
+	)
+
+	start := time.Now()
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the scenario to end after the steps, took %v", elapsed)
+	}
+	if result.Interrupted {
+		t.Error("finishing the steps should not count as an interruption")
+	}
+	if len(result.Steps) != 7 {
+		t.Fatalf("expected 7 step results, got %+v", result.Steps)
+	}
+	if result.TotalRequests == 0 {
+		t.Error("expected load during the steps")
+	}
+
+	if got := result.Steps[1].Targets; len(got) != 1 || got[0] != "node-1" {
+		t.Errorf("expected node-1 to be suspended, got %v", got)
+	}
+	assert := result.Steps[2]
+	if len(assert.Failures) != 1 || assert.Failures[0].Metric != "nodes_running" || assert.Failures[0].Value != 2 {
+		t.Errorf("expected nodes_running to fail with 2 nodes, got %+v", assert.Failures)
+	}
+	if got := result.Steps[3].Targets; len(got) != 1 || got[0] != "node-1" {
+		t.Errorf("expected node-1 to be healed, got %v", got)
+	}
+	if len(result.Steps[4].Failures) != 0 || result.Steps[4].Error != "" {
+		t.Errorf("expected the wait to succeed, got %+v", result.Steps[4])
+	}
+	// zone-a は node-1・node-3、zone-b は node-2
+	if got := result.Steps[5].Targets; len(got) != 1 || (got[0] != "node-1" && got[0] != "node-3") {
+		t.Errorf("expected one node in zone-a to be killed, got %v", got)
+	}
+	if got := result.Steps[6].Targets; len(got) != 1 || got[0] != "node-2" {
+		t.Errorf("expected node-2 to be delayed, got %v", got)
+	}
+	if result.TotalAttacks != 3 {
+		t.Errorf("expected 3 attacks, got %d", result.TotalAttacks)
+	}
+
+	if result.Passed() || len(result.AssertionFailures) != 1 {
+		t.Errorf("expected the failed step condition in the assertion failures, got %+v", result.AssertionFailures)
+	}
+	report := result.Report()
+	for _, s := range []string{"STEPS", "all up: assert nodes_running >= 3; failed nodes_running >= 3 (actual 2)", "inject suspend into node-1 -> node-1"} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report should contain %q:\n%s", s, report)
+		}
+	}
+}
+
+func TestEngineRunStepsFailure(t *testing.T) {
+	config := stepsConfig(
+		Step{Kind: StepWait, Duration: 200 * time.Millisecond, Condition: Condition{MinNodesRunning: 4}},
+		Step{Kind: StepInject, Attack: chaos.AttackKill},
+	)
+
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if len(result.Steps) != 1 {
+		t.Fatalf("expected the steps after the failure to be skipped, got %+v", result.Steps)
+	}
+	failed := result.FailedStep()
+	if failed == nil || !strings.Contains(failed.Error, "not met within 200ms") || len(failed.Failures) != 1 {
+		t.Errorf("expected the wait to time out, got %+v", failed)
+	}
+	if result.Passed() {
+		t.Error("expected a failed step to fail the scenario")
+	}
+	if result.TotalAttacks != 0 {
+		t.Errorf("expected no attacks, got %d", result.TotalAttacks)
+	}
+}
+
+func TestEngineRunStepsDuration(t *testing.T) {
+	config := stepsConfig(Step{Kind: StepLoad, Duration: time.Minute})
+	config.Duration = 200 * time.Millisecond
+
+	start := time.Now()
+	result, err := New(config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run scenario: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the duration to bound the steps, took %v", elapsed)
+	}
+	if result.Interrupted {
+		t.Error("reaching the duration should not count as an interruption")
+	}
+	if failed := result.FailedStep(); failed == nil || !strings.Contains(failed.Error, "scenario ended") {
+		t.Errorf("expected the unfinished step to fail, got %+v", result.Steps)
+	}
+}
+
+func TestEngineRunStepsInvalid(t *testing.T) {
+	config := stepsConfig(Step{Kind: StepAssert})
+	if _, err := New(config).Run(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid step 1") {
+		t.Errorf("expected invalid step error, got %v", err)
+	}
+}
+
+func TestNewPlanSteps(t *testing.T) {
+	cfg := stepsConfig(
+		Step{Kind: StepLoad, Duration: 10 * time.Second, Workers: 4},
+		Step{Kind: StepInject, Name: "zone outage", Attack: chaos.AttackKill, Target: Selector{Zone: "zone-a"}},
+		Step{Kind: StepWait, Duration: 20 * time.Second, Condition: Condition{MinNodesRunning: 3}},
+	)
+
+	p := NewPlan(cfg)
+	if load := p.Phases[1]; load.End != 30*time.Second || !strings.Contains(load.Description, "driven by 3 steps") {
+		t.Errorf("expected the load phase to cover the steps, got %+v", load)
+	}
+	report := p.Report()
+	for _, s := range []string{"until all steps finish (about 30s)", "#2    zone outage: inject kill into nodes in zone zone-a"} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report should contain %q:\n%s", s, report)
+		}
+	}
+}
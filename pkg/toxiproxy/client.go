@@ -0,0 +1,191 @@
+package toxiproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config はToxiproxyのサーバーとプロキシの設定
+type Config struct {
+	URL       string        // APIのURL（例: http://127.0.0.1:8474）
+	Listen    string        // プロキシが待ち受けるホスト（ポートは空いているものを使う）
+	Bandwidth int           // 遅延の注入時に合わせてかける帯域の上限（KB/秒、0で制限しない）
+	Timeout   time.Duration // 1つのAPIリクエストの上限時間（0で無制限）
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		URL:     "http://127.0.0.1:8474",
+		Listen:  "127.0.0.1",
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Toxic はプロキシの接続にかける障害
+type Toxic struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`   // latency, bandwidth, reset_peer, timeout など
+	Stream     string         `json:"stream"` // upstream（クライアントからの送信）または downstream（応答）
+	Toxicity   float64        `json:"toxicity"`
+	Attributes map[string]int `json:"attributes"`
+}
+
+// APIError はToxiproxyのAPIが返したエラー
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("toxiproxy: %s (status %d)", e.Message, e.Status)
+}
+
+// isNotFound は err がプロキシ・障害が存在しないことを示すかを返す
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// Client はToxiproxyのAPIのクライアント
+type Client struct {
+	config Config
+	http   *http.Client
+}
+
+// NewClient はToxiproxyのAPIのクライアントを作成する
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// URL はAPIのURLを返す
+func (c *Client) URL() string {
+	return c.config.URL
+}
+
+// Version はサーバーのバージョンを返す（接続できることの確認に使う）
+func (c *Client) Version(ctx context.Context) (string, error) {
+	data, err := c.request(ctx, http.MethodGet, "/version", nil)
+	if err != nil {
+		return "", err
+	}
+	var v struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &v) == nil && v.Version != "" {
+		return v.Version, nil
+	}
+	return strings.TrimSpace(string(data)), nil // 古いサーバーはプレーンテキストで返す
+}
+
+// CreateProxy は upstream に転送するプロキシを作成する
+// 同じ名前のプロキシ（前回の実行の残りなど）がある場合は削除してから作成する
+func (c *Client) CreateProxy(ctx context.Context, name, upstream string) (*Proxy, error) {
+	if err := c.DeleteProxy(ctx, name); err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{
+		"name":     name,
+		"listen":   c.config.Listen + ":0",
+		"upstream": upstream,
+		"enabled":  true,
+	}
+	var created struct {
+		Listen string `json:"listen"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/proxies", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create proxy %s: %w", name, err)
+	}
+	return &Proxy{client: c, name: name, listen: created.Listen, upstream: upstream}, nil
+}
+
+// DeleteProxy はプロキシを削除する。存在しない場合は何もしない
+func (c *Client) DeleteProxy(ctx context.Context, name string) error {
+	err := c.do(ctx, http.MethodDelete, "/proxies/"+url.PathEscape(name), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete proxy %s: %w", name, err)
+	}
+	return nil
+}
+
+// AddToxic はプロキシに障害を追加する
+func (c *Client) AddToxic(ctx context.Context, proxy string, toxic Toxic) error {
+	if err := c.do(ctx, http.MethodPost, "/proxies/"+url.PathEscape(proxy)+"/toxics", toxic, nil); err != nil {
+		return fmt.Errorf("failed to add %s toxic to %s: %w", toxic.Type, proxy, err)
+	}
+	return nil
+}
+
+// RemoveToxic はプロキシから障害を取り除く。存在しない場合は何もしない
+func (c *Client) RemoveToxic(ctx context.Context, proxy, toxic string) error {
+	err := c.do(ctx, http.MethodDelete, "/proxies/"+url.PathEscape(proxy)+"/toxics/"+url.PathEscape(toxic), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to remove toxic %s from %s: %w", toxic, proxy, err)
+	}
+	return nil
+}
+
+// do はAPIにリクエストを送り、成功した場合は応答を out にデコードする
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	data, err := c.request(ctx, method, path, in)
+	if err != nil {
+		return err
+	}
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// request はAPIにリクエストを送り、応答の本文を返す。失敗の応答は *APIError にする
+func (c *Client) request(ctx context.Context, method, path string, in any) ([]byte, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.config.URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			message = apiErr.Error
+		}
+		return nil, &APIError{Status: resp.StatusCode, Message: message}
+	}
+	return data, nil
+}
@@ -0,0 +1,183 @@
+package toxiproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeServer はプロキシと障害を記録するだけのToxiproxyのAPI
+type fakeServer struct {
+	mu       sync.Mutex
+	proxies  map[string]map[string]Toxic // プロキシ名 → 障害名 → 障害
+	nextPort int
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, *Client) {
+	t.Helper()
+	f := &fakeServer{proxies: make(map[string]map[string]Toxic), nextPort: 20000}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"2.9.0"}`))
+	})
+	mux.HandleFunc("POST /proxies", func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Name, Listen, Upstream string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.proxies[body.Name]; ok {
+			writeError(w, http.StatusConflict, "proxy already exists")
+			return
+		}
+		f.proxies[body.Name] = make(map[string]Toxic)
+		f.nextPort++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"name": body.Name, "listen": fmt.Sprintf("127.0.0.1:%d", f.nextPort), "upstream": body.Upstream,
+		})
+	})
+	mux.HandleFunc("DELETE /proxies/{name}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.proxies[r.PathValue("name")]; !ok {
+			writeError(w, http.StatusNotFound, "proxy not found")
+			return
+		}
+		delete(f.proxies, r.PathValue("name"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /proxies/{name}/toxics", func(w http.ResponseWriter, r *http.Request) {
+		var toxic Toxic
+		_ = json.NewDecoder(r.Body).Decode(&toxic)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		toxics, ok := f.proxies[r.PathValue("name")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "proxy not found")
+			return
+		}
+		if _, ok := toxics[toxic.Name]; ok {
+			writeError(w, http.StatusConflict, "toxic already exists")
+			return
+		}
+		toxics[toxic.Name] = toxic
+		_ = json.NewEncoder(w).Encode(toxic)
+	})
+	mux.HandleFunc("DELETE /proxies/{name}/toxics/{toxic}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		toxics, ok := f.proxies[r.PathValue("name")]
+		if _, found := toxics[r.PathValue("toxic")]; !ok || !found {
+			writeError(w, http.StatusNotFound, "toxic not found")
+			return
+		}
+		delete(toxics, r.PathValue("toxic"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	config := DefaultConfig()
+	config.URL = srv.URL
+	return f, NewClient(config)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": message, "status": status})
+}
+
+// toxics はプロキシの障害を返す（プロキシがない場合は nil）
+func (f *fakeServer) toxics(proxy string) map[string]Toxic {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	toxics, ok := f.proxies[proxy]
+	if !ok {
+		return nil
+	}
+	out := make(map[string]Toxic, len(toxics))
+	for name, toxic := range toxics {
+		out[name] = toxic
+	}
+	return out
+}
+
+func TestClientVersion(t *testing.T) {
+	_, client := newFakeServer(t)
+	version, err := client.Version(context.Background())
+	if err != nil || version != "2.9.0" {
+		t.Errorf("expected version 2.9.0, got %q (%v)", version, err)
+	}
+
+	// 古いサーバーはプレーンテキストで返す
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("2.1.4\n"))
+	}))
+	defer srv.Close()
+	config := DefaultConfig()
+	config.URL = srv.URL
+	if version, err := NewClient(config).Version(context.Background()); err != nil || version != "2.1.4" {
+		t.Errorf("expected version 2.1.4, got %q (%v)", version, err)
+	}
+}
+
+func TestClientCreateProxy(t *testing.T) {
+	f, client := newFakeServer(t)
+	ctx := context.Background()
+
+	proxy, err := client.CreateProxy(ctx, "p", "127.0.0.1:6379")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proxy.Name() != "p" || proxy.Upstream() != "127.0.0.1:6379" || proxy.Listen() != "127.0.0.1:20001" {
+		t.Errorf("unexpected proxy: %s %s %s", proxy.Name(), proxy.Upstream(), proxy.Listen())
+	}
+
+	// 残っていたプロキシは作り直す
+	if err := client.AddToxic(ctx, "p", Toxic{Name: "old", Type: "latency"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateProxy(ctx, "p", "127.0.0.1:6379"); err != nil {
+		t.Fatal(err)
+	}
+	if toxics := f.toxics("p"); toxics == nil || len(toxics) != 0 {
+		t.Errorf("expected a fresh proxy, got toxics %v", toxics)
+	}
+
+	if err := proxy.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if f.toxics("p") != nil {
+		t.Error("expected proxy to be deleted")
+	}
+	if err := proxy.Delete(); err != nil {
+		t.Errorf("expected deleting a missing proxy to succeed: %v", err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	_, client := newFakeServer(t)
+	ctx := context.Background()
+
+	err := client.AddToxic(ctx, "missing", Toxic{Name: "t", Type: "latency"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound || apiErr.Message != "proxy not found" {
+		t.Errorf("expected a not found API error, got %v", err)
+	}
+	if err := client.RemoveToxic(ctx, "missing", "t"); err != nil {
+		t.Errorf("expected removing a missing toxic to succeed: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.URL = "http://127.0.0.1:1"
+	if _, err := NewClient(config).Version(ctx); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+}
@@ -0,0 +1,34 @@
+// Package toxiproxy injects network faults at the socket layer by driving a
+// Toxiproxy server (https://github.com/Shopify/toxiproxy) through its HTTP
+// API.
+//
+// # Basic Usage
+//
+//	client := toxiproxy.NewClient(toxiproxy.DefaultConfig())
+//	proxy, err := client.CreateProxy(ctx, "chaos-kvs-node-1", upstreamAddr)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer proxy.Delete()
+//
+//	proxy.Wrap(n.Backend()) // keep an existing backend, if any
+//	n.SetBackend(proxy)
+//	// send the load to proxy.Listen() instead of upstreamAddr
+//
+// A Proxy is a node.Backend, so chaos attacks and recovery on the node are
+// carried out as toxics on the connections to it:
+//
+//   - Stop adds a reset_peer toxic, so requests fail with a connection reset
+//   - Suspend adds a latency toxic long enough to hold all data, which is
+//     delivered when Resume removes it
+//   - SetDelay adds a latency toxic to the responses, together with a
+//     bandwidth toxic when Config.Bandwidth is set
+//
+// A wrapped backend, such as an external.Process, is driven as well, except
+// for SetDelay: the delay is injected by Toxiproxy only. Start and Resume
+// remove the matching toxics.
+//
+// Toxiproxy has to reach the upstream addresses and chaos-kvs has to reach
+// the proxies, so it normally runs on the same host (or in a container on the
+// host network).
+package toxiproxy
@@ -0,0 +1,132 @@
+package toxiproxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// プロキシに追加する障害の名前
+const (
+	toxicReset     = "chaos-kvs-reset"
+	toxicSuspend   = "chaos-kvs-suspend"
+	toxicLatency   = "chaos-kvs-latency"
+	toxicBandwidth = "chaos-kvs-bandwidth"
+)
+
+// suspendLatency は一時停止中にデータを保持する遅延（Resume で取り除くまで届かない長さ）
+const suspendLatency = 24 * time.Hour
+
+// Proxy はノードへの接続を中継するToxiproxyのプロキシ
+// node.Backend としてノードに設定すると、攻撃・復旧を接続への障害として注入する
+type Proxy struct {
+	client   *Client
+	name     string
+	listen   string
+	upstream string
+	next     node.Backend
+}
+
+// Ensure Proxy implements node.Backend
+var _ node.Backend = (*Proxy)(nil)
+
+// Name はプロキシの名前を返す
+func (p *Proxy) Name() string {
+	return p.name
+}
+
+// Listen はプロキシが待ち受けるアドレス（負荷の送り先）を返す
+func (p *Proxy) Listen() string {
+	return p.listen
+}
+
+// Upstream は転送先のアドレスを返す
+func (p *Proxy) Upstream() string {
+	return p.upstream
+}
+
+// Wrap はプロキシと合わせて操作する backend を設定する（ノードに設定済みの外部のプロセスなど、nil で解除）
+// 遅延はプロキシのみで注入し、backend の SetDelay は呼ばない
+func (p *Proxy) Wrap(next node.Backend) {
+	p.next = next
+}
+
+// Delete はプロキシを削除する
+func (p *Proxy) Delete() error {
+	return p.client.DeleteProxy(context.Background(), p.name)
+}
+
+// Start は接続のリセットを解除する
+func (p *Proxy) Start(ctx context.Context) error {
+	if p.next != nil {
+		if err := p.next.Start(ctx); err != nil {
+			return err
+		}
+	}
+	return p.client.RemoveToxic(ctx, p.name, toxicReset)
+}
+
+// Stop は以降の接続をリセットする（kill 攻撃）
+func (p *Proxy) Stop() error {
+	if p.next != nil {
+		if err := p.next.Stop(); err != nil {
+			return err
+		}
+	}
+	return p.client.AddToxic(context.Background(), p.name, Toxic{
+		Name: toxicReset, Type: "reset_peer", Stream: "upstream", Toxicity: 1,
+		Attributes: map[string]int{"timeout": 0},
+	})
+}
+
+// Suspend は Resume まで接続のデータを保持する（suspend 攻撃）
+func (p *Proxy) Suspend() error {
+	if p.next != nil {
+		if err := p.next.Suspend(); err != nil {
+			return err
+		}
+	}
+	return p.client.AddToxic(context.Background(), p.name, Toxic{
+		Name: toxicSuspend, Type: "latency", Stream: "upstream", Toxicity: 1,
+		Attributes: map[string]int{"latency": int(suspendLatency.Milliseconds())},
+	})
+}
+
+// Resume は保持していたデータを届け、以降のデータを通す
+func (p *Proxy) Resume() error {
+	if err := p.client.RemoveToxic(context.Background(), p.name, toxicSuspend); err != nil {
+		return err
+	}
+	if p.next != nil {
+		return p.next.Resume()
+	}
+	return nil
+}
+
+// SetDelay は応答に遅延（と設定された帯域の上限）をかける（0で解除）
+func (p *Proxy) SetDelay(d time.Duration) error {
+	ctx := context.Background()
+	for _, name := range []string{toxicLatency, toxicBandwidth} {
+		if err := p.client.RemoveToxic(ctx, p.name, name); err != nil {
+			return err
+		}
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	if err := p.client.AddToxic(ctx, p.name, Toxic{
+		Name: toxicLatency, Type: "latency", Stream: "downstream", Toxicity: 1,
+		Attributes: map[string]int{"latency": int(d.Milliseconds())},
+	}); err != nil {
+		return err
+	}
+	if rate := p.client.config.Bandwidth; rate > 0 {
+		return p.client.AddToxic(ctx, p.name, Toxic{
+			Name: toxicBandwidth, Type: "bandwidth", Stream: "downstream", Toxicity: 1,
+			Attributes: map[string]int{"rate": rate},
+		})
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package toxiproxy
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/node"
+)
+
+// recordingBackend は呼び出しを記録する backend
+type recordingBackend struct {
+	calls []string
+	fail  string
+}
+
+func (b *recordingBackend) do(op string) error {
+	b.calls = append(b.calls, op)
+	if op == b.fail {
+		return errors.New(op + " failed")
+	}
+	return nil
+}
+
+func (b *recordingBackend) Start(context.Context) error { return b.do("start") }
+func (b *recordingBackend) Stop() error                 { return b.do("stop") }
+func (b *recordingBackend) Suspend() error              { return b.do("suspend") }
+func (b *recordingBackend) Resume() error               { return b.do("resume") }
+func (b *recordingBackend) SetDelay(time.Duration) error {
+	return b.do("delay")
+}
+
+// toxicNames はプロキシの障害の名前を並べて返す
+func toxicNames(f *fakeServer, proxy string) string {
+	var names []string
+	for name := range f.toxics(proxy) {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ",")
+}
+
+func TestProxyBackend(t *testing.T) {
+	f, client := newFakeServer(t)
+	client.config.Bandwidth = 64
+	proxy, err := client.CreateProxy(context.Background(), "chaos-kvs-node-1", "127.0.0.1:8101")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := node.New("node-1")
+	n.SetBackend(proxy)
+	ctx := context.Background()
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name string
+		do   func() error
+		want string
+	}{
+		{"kill", n.Stop, toxicReset},
+		{"restart", func() error { return n.Start(ctx) }, ""},
+		{"suspend", n.Suspend, toxicSuspend},
+		{"resume", n.Resume, ""},
+		{"delay", func() error { n.SetDelay(50 * time.Millisecond); return nil }, toxicBandwidth + "," + toxicLatency},
+		{"change delay", func() error { n.SetDelay(80 * time.Millisecond); return nil }, toxicBandwidth + "," + toxicLatency},
+		{"clear delay", func() error { n.SetDelay(0); return nil }, ""},
+	}
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := toxicNames(f, proxy.Name()); got != step.want {
+			t.Errorf("%s: expected toxics %q, got %q", step.name, step.want, got)
+		}
+	}
+
+	n.SetDelay(80 * time.Millisecond)
+	toxics := f.toxics(proxy.Name())
+	if latency := toxics[toxicLatency]; latency.Stream != "downstream" || latency.Attributes["latency"] != 80 {
+		t.Errorf("unexpected latency toxic: %+v", latency)
+	}
+	if bandwidth := toxics[toxicBandwidth]; bandwidth.Attributes["rate"] != 64 {
+		t.Errorf("unexpected bandwidth toxic: %+v", bandwidth)
+	}
+}
+
+func TestProxyWrap(t *testing.T) {
+	f, client := newFakeServer(t)
+	proxy, err := client.CreateProxy(context.Background(), "p", "127.0.0.1:6379")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := &recordingBackend{}
+	proxy.Wrap(next)
+
+	ctx := context.Background()
+	if err := proxy.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := proxy.SetDelay(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := proxy.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	if err := proxy.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	// backend が失敗した場合は障害を追加しない
+	next.fail = "stop"
+	if err := proxy.Stop(); err == nil {
+		t.Error("expected stop to fail")
+	}
+	if got := toxicNames(f, "p"); got != toxicLatency {
+		t.Errorf("expected only the latency toxic, got %q", got)
+	}
+
+	// 遅延はプロキシのみで注入する
+	if got := strings.Join(next.calls, " "); got != "start suspend resume stop" {
+		t.Errorf("unexpected backend calls: %q", got)
+	}
+}
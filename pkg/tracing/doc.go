@@ -0,0 +1,38 @@
+// Package tracing records spans of a scenario run and exports them with
+// OTLP/HTTP (JSON encoding), so a run can be explored as a trace in Jaeger,
+// Grafana Tempo or any OpenTelemetry collector.
+//
+// # Basic Usage
+//
+//	config := tracing.DefaultConfig()
+//	config.Endpoint = "http://127.0.0.1:4318" // spans are POSTed to /v1/traces
+//	tracer := tracing.New(config)
+//	defer tracer.Close(ctx)
+//
+//	root := tracer.Start("scenario", tracing.String("scenario.name", "quick"))
+//	setup := root.Child("setup")
+//	// ...
+//	setup.End()
+//	root.End()
+//
+// Spans are queued when they end and exported in batches by a background
+// goroutine. A nil *Tracer and a nil *Span are valid and record nothing, so
+// callers need not check whether tracing is enabled.
+//
+// # Chaos Events
+//
+// TraceEvents subscribes to an events.Bus and records chaos attacks and
+// recoveries as child spans of a parent span. An attack span lasts until the
+// node is resumed or recovered, is attacked again, or the tracer of events is
+// closed; a recovery span lasts from the attempt until it succeeds or fails,
+// and is a child of the attack it recovers from. Node scaling, client error
+// bursts and SLO violations are added to the parent span as span events.
+//
+// # Sampling
+//
+// Span.Sample creates a child span with the probability Config.SampleRate,
+// so a load generator can trace a fraction of its requests without exporting
+// every one. When the queue is full, sampled spans are dropped and counted in
+// Stats; other spans, such as phases and attacks, are few and are always
+// kept so the structure of the trace stays intact.
+package tracing
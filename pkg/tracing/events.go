@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// eventTypes は EventTracer が記録するイベント
+var eventTypes = []events.EventType{
+	events.EventChaosAttack, events.EventChaosResume,
+	events.EventRecoveryStart, events.EventRecoverySuccess, events.EventRecoveryFailed,
+	events.EventNodeScaled, events.EventClientErrorBurst, events.EventSLOViolation,
+}
+
+// EventTracer はイベントバスのカオス・復旧のイベントをスパンとして記録する
+type EventTracer struct {
+	bus    *events.Bus
+	ch     <-chan events.Event
+	parent *Span
+	done   chan struct{}
+
+	// 以下は loop のゴルーチンのみが触る
+	attacks    map[string]*Span // ノードごとの継続中の攻撃
+	recoveries map[string]*Span // ノードごとの実行中の復旧
+}
+
+// TraceEvents は bus を購読し、カオス・復旧のイベントを parent の子スパンとして記録する
+// parent が nil の場合は購読しない
+func TraceEvents(bus *events.Bus, parent *Span) *EventTracer {
+	t := &EventTracer{
+		bus:        bus,
+		parent:     parent,
+		done:       make(chan struct{}),
+		attacks:    make(map[string]*Span),
+		recoveries: make(map[string]*Span),
+	}
+	if bus == nil || parent == nil {
+		close(t.done)
+		return t
+	}
+	// 取りこぼすとスパンが閉じないため、購読側が追いつくまで発行を待たせる
+	t.ch = bus.SubscribeWith(events.SubscribeOptions{
+		Filter:     events.ByType(eventTypes...),
+		Policy:     events.Block,
+		BufferSize: 1024,
+	})
+	go t.loop()
+	return t
+}
+
+// Close は購読を終了し、受信済みのイベントを記録してから継続中のスパンを現在時刻で終了する
+func (t *EventTracer) Close() {
+	if t.ch != nil {
+		t.bus.Unsubscribe(t.ch)
+	}
+	<-t.done
+}
+
+// loop は購読が終了するまでイベントを記録する
+func (t *EventTracer) loop() {
+	defer close(t.done)
+	for event := range t.ch {
+		t.record(event)
+	}
+
+	now := time.Now()
+	for _, s := range t.recoveries {
+		s.EndAt(now)
+	}
+	for _, s := range t.attacks {
+		s.SetAttributes(Bool("chaos.recovered", false))
+		s.EndAt(now)
+	}
+}
+
+// record はイベントに応じてスパンを開始・終了する
+func (t *EventTracer) record(event events.Event) {
+	id := event.NodeID
+	at := event.Timestamp
+	switch event.Type {
+	case events.EventChaosAttack:
+		if s, ok := t.attacks[id]; ok {
+			s.EndAt(at) // 新しい攻撃で置き換わった
+		}
+		attrs := []Attribute{String("node.id", id), String("chaos.attack_type", string(event.Data.AttackType))}
+		if event.Data.DelayDuration != "" {
+			attrs = append(attrs, String("chaos.delay", event.Data.DelayDuration))
+		}
+		t.attacks[id] = t.parent.ChildAt("attack "+string(event.Data.AttackType), at, attrs...)
+
+	case events.EventChaosResume:
+		t.endAttack(id, at, "chaos")
+
+	case events.EventRecoveryStart:
+		if s, ok := t.recoveries[id]; ok {
+			s.EndAt(at)
+		}
+		parent := t.parent
+		if attack, ok := t.attacks[id]; ok {
+			parent = attack
+		}
+		t.recoveries[id] = parent.ChildAt("recovery", at,
+			String("node.id", id), Int("recovery.attempt", int64(event.Data.Attempt)))
+
+	case events.EventRecoverySuccess:
+		if s, ok := t.recoveries[id]; ok {
+			s.EndAt(at)
+			delete(t.recoveries, id)
+		}
+		t.endAttack(id, at, "recovery")
+
+	case events.EventRecoveryFailed:
+		if s, ok := t.recoveries[id]; ok {
+			s.SetError(errors.New(event.Data.Error))
+			s.EndAt(at)
+			delete(t.recoveries, id)
+		}
+
+	case events.EventNodeScaled:
+		t.parent.AddEvent("node scaled", at,
+			Int("cluster.node_count", int64(event.Data.NodeCount)), String("reason", event.Data.Reason))
+
+	case events.EventClientErrorBurst:
+		t.parent.AddEvent("client error burst", at,
+			Int("client.errors", int64(event.Data.Errors)), Int("client.requests", int64(event.Data.Requests)),
+			String("client.window", event.Data.Window))
+
+	case events.EventSLOViolation:
+		t.parent.AddEvent("slo violation", at,
+			String("slo.metric", event.Data.Metric), Float("slo.threshold", event.Data.Threshold),
+			Float("slo.value", event.Data.Value))
+	}
+}
+
+// endAttack はノードの継続中の攻撃のスパンを終了する（by は回復させたもの）
+func (t *EventTracer) endAttack(id string, at time.Time, by string) {
+	s, ok := t.attacks[id]
+	if !ok {
+		return
+	}
+	s.SetAttributes(Bool("chaos.recovered", true), String("chaos.recovered_by", by))
+	s.EndAt(at)
+	delete(t.attacks, id)
+}
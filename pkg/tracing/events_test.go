@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/events"
+)
+
+// newQueueTracer はエクスポートせず、終了したスパンをキューに溜める Tracer を作成する
+func newQueueTracer() *Tracer {
+	return &Tracer{queue: make(chan *Span, 64)}
+}
+
+// ended はキューに溜まった終了済みのスパンを名前ごとに返す
+func ended(tracer *Tracer) map[string][]*Span {
+	spans := make(map[string][]*Span)
+	for {
+		select {
+		case s := <-tracer.queue:
+			spans[s.name] = append(spans[s.name], s)
+		default:
+			return spans
+		}
+	}
+}
+
+// attr はスパンの属性の値を返す
+func attr(s *Span, key string) any {
+	for _, a := range s.attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func TestTraceEvents(t *testing.T) {
+	bus := events.NewBus()
+	tracer := newQueueTracer()
+	load := tracer.Start("load")
+	et := TraceEvents(bus, load)
+
+	bus.Publish(events.NewChaosAttackEvent("node-1", events.AttackTypeKill))
+	bus.Publish(events.NewRecoveryStartEvent("node-1", 1))
+	bus.Publish(events.NewRecoveryFailedEvent("node-1", errors.New("port in use")))
+	bus.Publish(events.NewRecoveryStartEvent("node-1", 2))
+	bus.Publish(events.NewRecoverySuccessEvent("node-1"))
+	bus.Publish(events.NewChaosAttackEvent("node-2", events.AttackTypeSuspend))
+	bus.Publish(events.NewChaosResumeEvent("node-2"))
+	bus.Publish(events.NewChaosAttackEventWithDelay("node-3", 100*time.Millisecond))
+	bus.Publish(events.NewClientErrorBurstEvent(5, 10, time.Second))
+	et.Close()
+
+	spans := ended(tracer)
+	kill := spans["attack kill"]
+	if len(kill) != 1 || kill[0].parentID != load.SpanID() || attr(kill[0], "chaos.recovered_by") != "recovery" {
+		t.Fatalf("unexpected kill attack spans: %+v", kill)
+	}
+	recoveries := spans["recovery"]
+	if len(recoveries) != 2 {
+		t.Fatalf("expected 2 recovery attempts, got %d", len(recoveries))
+	}
+	for _, r := range recoveries {
+		if r.parentID != kill[0].SpanID() {
+			t.Error("expected recovery to be a child of the attack")
+		}
+	}
+	if recoveries[0].err != "port in use" || recoveries[1].err != "" {
+		t.Errorf("unexpected recovery results: %q %q", recoveries[0].err, recoveries[1].err)
+	}
+	if suspend := spans["attack suspend"]; len(suspend) != 1 || attr(suspend[0], "chaos.recovered_by") != "chaos" {
+		t.Errorf("unexpected suspend attack spans: %+v", suspend)
+	}
+	// 回復しないまま終了した攻撃は Close で終了する
+	if delay := spans["attack delay"]; len(delay) != 1 || attr(delay[0], "chaos.recovered") != false || attr(delay[0], "chaos.delay") != "100ms" {
+		t.Errorf("unexpected delay attack spans: %+v", delay)
+	}
+	if len(load.events) != 1 || load.events[0].Name != "client error burst" {
+		t.Errorf("expected error burst span event, got %+v", load.events)
+	}
+	if bus.SubscriberCount() != 0 {
+		t.Error("expected subscription to be closed")
+	}
+}
+
+func TestTraceEventsReplacedAttack(t *testing.T) {
+	bus := events.NewBus()
+	tracer := newQueueTracer()
+	et := TraceEvents(bus, tracer.Start("load"))
+
+	bus.Publish(events.NewChaosAttackEventWithDelay("node-1", time.Millisecond))
+	bus.Publish(events.NewChaosAttackEvent("node-1", events.AttackTypeKill))
+	et.Close()
+
+	spans := ended(tracer)
+	if len(spans["attack delay"]) != 1 || len(spans["attack kill"]) != 1 {
+		t.Errorf("expected the delay attack to end when the node is attacked again: %v", spans)
+	}
+}
+
+func TestTraceEventsDisabled(t *testing.T) {
+	bus := events.NewBus()
+	et := TraceEvents(bus, nil)
+	if bus.SubscriberCount() != 0 {
+		t.Error("expected no subscription without a parent span")
+	}
+	et.Close()
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeSpans(t *testing.T) {
+	tracer := &Tracer{}
+	start := time.Unix(1700000000, 5)
+	root := tracer.Start("root")
+	s := root.ChildAt("GET", start, String("node.id", "node-1"), Int("attempt", 2), Float("ratio", 0.5), Bool("found", true))
+	s.SetKind(KindClient)
+	s.AddEvent("retry", start.Add(time.Millisecond), Int("n", 1))
+	s.SetError(errors.New("connection reset"))
+	s.EndAt(start.Add(time.Second))
+
+	data, err := encodeSpans("chaos-kvs", []*Span{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"chaos-kvs"}}]}`,
+		`"traceId":"` + root.TraceID().String() + `"`,
+		`"parentSpanId":"` + root.SpanID().String() + `"`,
+		`"kind":3`,
+		`"startTimeUnixNano":"1700000000000000005"`,
+		`"endTimeUnixNano":"1700000001000000005"`,
+		`{"key":"attempt","value":{"intValue":"2"}}`,
+		`{"key":"ratio","value":{"doubleValue":0.5}}`,
+		`{"key":"found","value":{"boolValue":true}}`,
+		`"events":[{"timeUnixNano":"1700000000001000005","name":"retry"`,
+		`"status":{"code":2,"message":"connection reset"}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+
+	var req exportRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.ResourceSpans[0].ScopeSpans[0].Scope.Name; got != scopeName {
+		t.Errorf("unexpected scope %q", got)
+	}
+}
+
+func TestEncodeSpansOK(t *testing.T) {
+	s := (&Tracer{}).Start("root")
+	s.End()
+	data, err := encodeSpans("chaos-kvs", []*Span{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"status":{}`) || strings.Contains(string(data), "parentSpanId") {
+		t.Errorf("expected unset status and no parent: %s", data)
+	}
+}
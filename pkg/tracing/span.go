@@ -0,0 +1,223 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID はトレースのID
+type TraceID [16]byte
+
+// String は16進数の文字列を返す
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID はスパンのID
+type SpanID [8]byte
+
+// String は16進数の文字列を返す（ゼロ値は空文字列）
+func (id SpanID) String() string {
+	if id == (SpanID{}) {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// newTraceID は無作為なトレースIDを返す
+func newTraceID() TraceID {
+	var id TraceID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+// newSpanID は無作為なスパンIDを返す
+func newSpanID() SpanID {
+	var id SpanID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+// Kind はスパンの種類（OTLP の SpanKind と同じ値）
+type Kind int
+
+const (
+	KindInternal Kind = 1 // 処理の内部の区間（デフォルト）
+	KindClient   Kind = 3 // 他のサービスへのリクエスト
+)
+
+// Attribute はスパン・スパンイベントの属性
+type Attribute struct {
+	Key   string
+	Value any // string・int64・float64・bool のいずれか
+}
+
+// String は文字列の属性を返す
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int は整数の属性を返す
+func Int(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Float は浮動小数点数の属性を返す
+func Float(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool は真偽値の属性を返す
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Event はスパンの途中の時点で起きた出来事
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []Attribute
+}
+
+// Span はトレースの1つの区間
+// nil の Span は何も記録しない
+type Span struct {
+	tracer   *Tracer
+	traceID  TraceID
+	spanID   SpanID
+	parentID SpanID
+	name     string
+	start    time.Time
+	sampled  bool // Sample で作成した（キューが一杯の場合は捨てる）
+
+	mu         sync.Mutex
+	kind       Kind
+	end        time.Time
+	attributes []Attribute
+	events     []Event
+	err        string // 空でない場合はエラーとして終了した
+	ended      bool
+}
+
+// TraceID はスパンが属するトレースのIDを返す
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.traceID
+}
+
+// SpanID はスパンのIDを返す
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.spanID
+}
+
+// Name はスパンの名前を返す
+func (s *Span) Name() string {
+	if s == nil {
+		return ""
+	}
+	return s.name
+}
+
+// Child は現在時刻に開始する子スパンを作成する
+func (s *Span) Child(name string, attrs ...Attribute) *Span {
+	return s.ChildAt(name, time.Now(), attrs...)
+}
+
+// ChildAt は start に開始した子スパンを作成する
+func (s *Span) ChildAt(name string, start time.Time, attrs ...Attribute) *Span {
+	if s == nil {
+		return nil
+	}
+	return &Span{
+		tracer:     s.tracer,
+		traceID:    s.traceID,
+		spanID:     newSpanID(),
+		parentID:   s.spanID,
+		name:       name,
+		start:      start,
+		kind:       KindInternal,
+		attributes: attrs,
+	}
+}
+
+// Sample は Tracer の SampleRate の確率で子スパンを作成する（作成しない場合は nil）
+// 負荷生成のリクエストのように数の多いスパンに使い、キューが一杯の場合は他のスパンより先に捨てる
+func (s *Span) Sample(name string, attrs ...Attribute) *Span {
+	if s == nil || !s.tracer.sample() {
+		return nil
+	}
+	child := s.Child(name, attrs...)
+	child.sampled = true
+	return child
+}
+
+// SetKind はスパンの種類を設定する
+func (s *Span) SetKind(kind Kind) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.kind = kind
+	s.mu.Unlock()
+}
+
+// SetAttributes はスパンに属性を追加する
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attributes = append(s.attributes, attrs...)
+	s.mu.Unlock()
+}
+
+// AddEvent は at の時点の出来事をスパンに追加する
+func (s *Span) AddEvent(name string, at time.Time, attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, Event{Name: name, Time: at, Attributes: attrs})
+	s.mu.Unlock()
+}
+
+// SetError はスパンをエラーとして記録する（err が nil の場合は何もしない）
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.err = err.Error()
+	s.mu.Unlock()
+}
+
+// End は現在時刻でスパンを終了し、エクスポートの対象にする
+func (s *Span) End() {
+	s.EndAt(time.Now())
+}
+
+// EndAt は end の時刻でスパンを終了し、エクスポートの対象にする（2回目以降は何もしない）
+func (s *Span) EndAt(end time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = end
+	s.mu.Unlock()
+
+	if s.tracer != nil {
+		s.tracer.enqueue(s)
+	}
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilSpan(t *testing.T) {
+	var tracer *Tracer
+	root := tracer.Start("root")
+	if root != nil {
+		t.Fatal("nil tracer should not create spans")
+	}
+
+	// nil のスパンの操作は何もしない
+	child := root.Child("child", String("k", "v"))
+	child.SetKind(KindClient)
+	child.SetAttributes(Int("n", 1))
+	child.AddEvent("event", time.Now())
+	child.SetError(errors.New("boom"))
+	child.End()
+	if child != nil || root.TraceID() != (TraceID{}) || root.Name() != "" {
+		t.Error("expected nil span to record nothing")
+	}
+}
+
+func TestSpanIDs(t *testing.T) {
+	tracer := &Tracer{}
+	root := tracer.Start("root")
+	child := root.Child("child")
+
+	if root.TraceID() == (TraceID{}) || root.SpanID() == (SpanID{}) {
+		t.Fatal("expected random IDs")
+	}
+	if child.TraceID() != root.TraceID() || child.parentID != root.SpanID() || child.SpanID() == root.SpanID() {
+		t.Error("expected child to share the trace and point to its parent")
+	}
+	if len(root.TraceID().String()) != 32 || len(root.SpanID().String()) != 16 {
+		t.Errorf("unexpected ID encoding: %s %s", root.TraceID(), root.SpanID())
+	}
+	if (SpanID{}).String() != "" {
+		t.Error("expected empty parent ID to encode as an empty string")
+	}
+}
+
+func TestSpanEndOnce(t *testing.T) {
+	tracer := &Tracer{queue: make(chan *Span, 4)}
+	s := tracer.Start("root")
+	end := time.Now()
+	s.EndAt(end)
+	s.EndAt(end.Add(time.Second))
+
+	if len(tracer.queue) != 1 {
+		t.Errorf("expected span to be queued once, got %d", len(tracer.queue))
+	}
+	if !s.end.Equal(end) {
+		t.Errorf("expected first end time to be kept, got %v", s.end)
+	}
+}
@@ -0,0 +1,288 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+)
+
+// log はコンポーネント名 "tracing" を付けてログを出力する子ロガー
+var log = logger.With("tracing")
+
+// Config はトレースのエクスポートの設定
+type Config struct {
+	// Endpoint はOTLP/HTTPの受信先のURL（パスが /v1/traces でない場合は付け足す）
+	Endpoint    string
+	ServiceName string            // リソース属性 service.name
+	Headers     map[string]string // リクエストに追加するヘッダー（認証など）
+
+	// SampleRate は負荷生成のリクエストをトレースする割合（0.0〜1.0）
+	SampleRate float64
+
+	QueueSize     int           // エクスポート待ちのスパンの上限（超えた Span.Sample のスパンは捨てる）
+	BatchSize     int           // 1回のリクエストで送るスパンの上限
+	FlushInterval time.Duration // 溜まったスパンを送る間隔
+	Timeout       time.Duration // 1回のリクエストの上限時間
+}
+
+// DefaultConfig はデフォルト設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Endpoint:      "http://127.0.0.1:4318",
+		ServiceName:   "chaos-kvs",
+		SampleRate:    0.01,
+		QueueSize:     4096,
+		BatchSize:     512,
+		FlushInterval: 2 * time.Second,
+		Timeout:       10 * time.Second,
+	}
+}
+
+// Stats はエクスポートの集計値
+type Stats struct {
+	Exported uint64 // 受信先が受け付けたスパン数
+	Dropped  uint64 // キューが一杯・終了後で捨てたスパン数
+	Failed   uint64 // 送信に失敗したスパン数
+}
+
+// Tracer はスパンを作成し、終了したスパンをまとめてエクスポートする
+// nil の Tracer は何も記録しない
+type Tracer struct {
+	config Config
+	url    string
+	client *http.Client
+
+	queue  chan *Span
+	closed atomic.Bool
+
+	// overflow はキューが一杯のときに受け付けたサンプリング以外のスパン
+	// フェーズ・攻撃などのスパンは数が少なく、捨てるとトレースの構造が欠けるため上限を設けない
+	mu       sync.Mutex
+	overflow []*Span
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+
+	exported atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+// New は Tracer を作成し、バックグラウンドでのエクスポートを開始する
+func New(config Config) *Tracer {
+	defaults := DefaultConfig()
+	if config.ServiceName == "" {
+		config.ServiceName = defaults.ServiceName
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+
+	t := &Tracer{
+		config: config,
+		url:    tracesURL(config.Endpoint),
+		client: &http.Client{Timeout: config.Timeout},
+		queue:  make(chan *Span, config.QueueSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go t.loop()
+	return t
+}
+
+// tracesURL はエンドポイントからスパンの送信先のURLを求める
+func tracesURL(endpoint string) string {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if strings.HasSuffix(endpoint, "/v1/traces") {
+		return endpoint
+	}
+	return endpoint + "/v1/traces"
+}
+
+// URL はスパンの送信先のURLを返す
+func (t *Tracer) URL() string {
+	if t == nil {
+		return ""
+	}
+	return t.url
+}
+
+// Start は新しいトレースのルートスパンを現在時刻に開始する
+func (t *Tracer) Start(name string, attrs ...Attribute) *Span {
+	if t == nil {
+		return nil
+	}
+	return &Span{
+		tracer:     t,
+		traceID:    newTraceID(),
+		spanID:     newSpanID(),
+		name:       name,
+		start:      time.Now(),
+		kind:       KindInternal,
+		attributes: attrs,
+	}
+}
+
+// sample はスパンを SampleRate の確率でサンプリングするかを返す
+func (t *Tracer) sample() bool {
+	return t != nil && t.config.SampleRate > 0 && rand.Float64() < t.config.SampleRate
+}
+
+// Stats はエクスポートの集計値を返す
+func (t *Tracer) Stats() Stats {
+	if t == nil {
+		return Stats{}
+	}
+	return Stats{
+		Exported: t.exported.Load(),
+		Dropped:  t.dropped.Load(),
+		Failed:   t.failed.Load(),
+	}
+}
+
+// enqueue は終了したスパンをエクスポート待ちにする
+// キューが一杯の場合、サンプリングしたスパンは捨て、それ以外は overflow に回す
+func (t *Tracer) enqueue(s *Span) {
+	if t.closed.Load() {
+		t.dropped.Add(1)
+		return
+	}
+	select {
+	case t.queue <- s:
+		return
+	default:
+	}
+	if s.sampled {
+		t.dropped.Add(1)
+		return
+	}
+	t.mu.Lock()
+	t.overflow = append(t.overflow, s)
+	t.mu.Unlock()
+}
+
+// takeOverflow は overflow に回したスパンを取り出す
+func (t *Tracer) takeOverflow() []*Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := t.overflow
+	t.overflow = nil
+	return spans
+}
+
+// Close は新しいスパンの受け付けを止め、エクスポート待ちのスパンを送り切る
+// ctx が先に終了した場合は送信を打ち切る
+func (t *Tracer) Close(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.once.Do(func() {
+		t.closed.Store(true)
+		close(t.stop)
+	})
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop はスパンを BatchSize ごと・FlushInterval ごとにまとめて送る
+func (t *Tracer) loop() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.config.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []*Span
+	flush := func() {
+		batch = append(batch, t.takeOverflow()...)
+		for len(batch) > 0 {
+			n := min(len(batch), t.config.BatchSize)
+			t.export(batch[:n])
+			batch = batch[n:]
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) >= t.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-t.stop:
+			for {
+				select {
+				case s := <-t.queue:
+					batch = append(batch, s)
+					if len(batch) >= t.config.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// export はスパンをOTLP/HTTP（JSON）で送る
+func (t *Tracer) export(batch []*Span) {
+	if err := t.send(batch); err != nil {
+		t.failed.Add(uint64(len(batch)))
+		log.Warn("", "Failed to export %d span(s): %v", len(batch), err)
+		return
+	}
+	t.exported.Add(uint64(len(batch)))
+}
+
+// send は1回のリクエストでスパンを送る
+func (t *Tracer) send(batch []*Span) error {
+	body, err := encodeSpans(t.config.ServiceName, batch)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,224 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector はOTLP/HTTPの受信先の代わりに、受け取ったスパンを記録する
+type collector struct {
+	mu      sync.Mutex
+	spans   []otlpSpan
+	headers []http.Header
+	status  int
+}
+
+func startCollector(t *testing.T) (*collector, string) {
+	t.Helper()
+	c := &collector{status: http.StatusOK}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var req exportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.headers = append(c.headers, r.Header.Clone())
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				c.spans = append(c.spans, ss.Spans...)
+			}
+		}
+		w.WriteHeader(c.status)
+	}))
+	t.Cleanup(ts.Close)
+	return c, ts.URL
+}
+
+// byName は受け取ったスパンを名前で引けるようにする
+func (c *collector) byName() map[string]otlpSpan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	spans := make(map[string]otlpSpan)
+	for _, s := range c.spans {
+		spans[s.Name] = s
+	}
+	return spans
+}
+
+func TestTracerExport(t *testing.T) {
+	c, url := startCollector(t)
+	config := DefaultConfig()
+	config.Endpoint = url
+	config.Headers = map[string]string{"Authorization": "Bearer token"}
+	tracer := New(config)
+
+	root := tracer.Start("scenario", String("scenario.name", "quick"))
+	setup := root.Child("setup")
+	setup.End()
+	root.End()
+
+	if err := tracer.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	spans := c.byName()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %v", spans)
+	}
+	if spans["setup"].TraceID != root.TraceID().String() || spans["setup"].ParentSpanID != root.SpanID().String() {
+		t.Errorf("expected setup to be a child of the root span: %+v", spans["setup"])
+	}
+	if spans["scenario"].ParentSpanID != "" {
+		t.Errorf("expected root span without parent, got %q", spans["scenario"].ParentSpanID)
+	}
+	if got := c.headers[0].Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected configured header, got %q", got)
+	}
+	if stats := tracer.Stats(); stats.Exported != 2 || stats.Failed != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// 終了後のスパンは捨てる
+	tracer.Start("late").End()
+	if stats := tracer.Stats(); stats.Dropped != 1 {
+		t.Errorf("expected span after close to be dropped, got %+v", stats)
+	}
+}
+
+func TestTracerBatching(t *testing.T) {
+	c, url := startCollector(t)
+	config := DefaultConfig()
+	config.Endpoint = url
+	config.BatchSize = 2
+	config.FlushInterval = time.Hour
+	tracer := New(config)
+	defer func() { _ = tracer.Close(context.Background()) }()
+
+	root := tracer.Start("root")
+	root.Child("a").End()
+	root.Child("b").End()
+
+	// BatchSize に達したら FlushInterval を待たずに送る
+	for deadline := time.Now().Add(5 * time.Second); len(c.byName()) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatal("batch was not exported")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTracerExportFailure(t *testing.T) {
+	c, url := startCollector(t)
+	c.status = http.StatusServiceUnavailable
+	config := DefaultConfig()
+	config.Endpoint = url
+	tracer := New(config)
+
+	tracer.Start("root").End()
+	if err := tracer.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats := tracer.Stats(); stats.Failed != 1 || stats.Exported != 0 {
+		t.Errorf("expected failed export, got %+v", stats)
+	}
+}
+
+func TestTracerQueueFull(t *testing.T) {
+	// エクスポートのゴルーチンを動かさず、キューを溢れさせる
+	tracer := &Tracer{config: Config{SampleRate: 1}, queue: make(chan *Span, 1)}
+	root := tracer.Start("root")
+	for range 10 {
+		root.Sample("request").End()
+	}
+	for range 3 {
+		root.Child("phase").End()
+	}
+
+	// サンプリングしたスパンのみを捨て、フェーズなどのスパンは残す
+	if stats := tracer.Stats(); stats.Dropped != 9 {
+		t.Errorf("expected 9 sampled spans to be dropped, got %+v", stats)
+	}
+	if overflow := tracer.takeOverflow(); len(overflow) != 3 {
+		t.Errorf("expected 3 phases to be kept, got %d", len(overflow))
+	}
+}
+
+func TestTracerExportOverflow(t *testing.T) {
+	c, url := startCollector(t)
+	config := DefaultConfig()
+	config.Endpoint = url
+	config.BatchSize = 2
+	tracer := New(config)
+
+	tracer.mu.Lock()
+	tracer.overflow = []*Span{tracer.Start("a"), tracer.Start("b"), tracer.Start("c")}
+	tracer.mu.Unlock()
+	if err := tracer.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if spans := c.byName(); len(spans) != 3 || len(c.headers) != 2 {
+		t.Errorf("expected overflow to be exported in batches of 2, got %d spans in %d requests", len(spans), len(c.headers))
+	}
+}
+
+func TestTracerCloseTimeout(t *testing.T) {
+	block := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer ts.Close()
+	defer close(block)
+
+	config := DefaultConfig()
+	config.Endpoint = ts.URL
+	tracer := New(config)
+	tracer.Start("root").End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := tracer.Close(ctx); err == nil {
+		t.Error("expected close to time out while the export is blocked")
+	}
+}
+
+func TestTracesURL(t *testing.T) {
+	tests := map[string]string{
+		"http://127.0.0.1:4318":           "http://127.0.0.1:4318/v1/traces",
+		"http://127.0.0.1:4318/":          "http://127.0.0.1:4318/v1/traces",
+		"https://tempo.example/v1/traces": "https://tempo.example/v1/traces",
+		"https://otlp.example/otlp":       "https://otlp.example/otlp/v1/traces",
+	}
+	for endpoint, want := range tests {
+		if got := tracesURL(endpoint); got != want {
+			t.Errorf("tracesURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestSpanSample(t *testing.T) {
+	var nilSpan *Span
+	if nilSpan.Sample("request") != nil {
+		t.Error("nil span should not sample")
+	}
+
+	tracer := &Tracer{config: Config{SampleRate: 1}}
+	root := tracer.Start("root")
+	s := root.Sample("request")
+	if s == nil || !s.sampled || s.parentID != root.SpanID() {
+		t.Fatalf("expected every span to be sampled at rate 1, got %+v", s)
+	}
+	tracer.config.SampleRate = 0
+	if root.Sample("request") != nil {
+		t.Error("expected no span to be sampled at rate 0")
+	}
+}
@@ -0,0 +1,112 @@
+// Package worker provides a goroutine pool for concurrent job execution.
+//
+// The Pool manages a set of worker goroutines that process submitted jobs.
+// It supports graceful shutdown and context cancellation.
+//
+// # Basic Usage
+//
+//	pool := worker.NewPool(4) // 4 workers
+//	pool.Start(ctx)
+//	defer pool.Stop()
+//
+//	// Submit jobs
+//	for i := 0; i < 100; i++ {
+//	    pool.Submit(func(ctx context.Context) {
+//	        // do work, returning early once ctx is done
+//	    })
+//	}
+//
+// # Configuration
+//
+// Use NewPoolWithConfig for custom settings:
+//
+//	config := worker.PoolConfig{
+//	    NumWorkers:  8,
+//	    QueueFactor: 200, // Queue size = 8 * 200 = 1600
+//	    JobTimeout:  time.Second,
+//	}
+//	pool := worker.NewPoolWithConfig(config)
+//
+// # Batches
+//
+// SubmitBatch enqueues many normal priority jobs with a single reservation and
+// a single lock of one shard, which is cheaper than calling Submit in a loop
+// when jobs are produced in bursts. Jobs beyond the free capacity fall back to
+// the configured backpressure one at a time.
+//
+//	n := pool.SubmitBatch(jobs) // number of jobs submitted
+//
+// # Results
+//
+// SubmitFunc runs a job that returns an error and hands back a channel that
+// receives its outcome, for workloads that need to inspect individual jobs:
+//
+//	if err := <-pool.SubmitFunc(verifyKey); err != nil {
+//	    // handle failure
+//	}
+//
+// # Backpressure
+//
+// PoolConfig.Backpressure decides what Submit does when a queue is full:
+// BackpressureBlock waits for room (the default), BackpressureReject returns
+// false immediately and BackpressureDropOldest discards the oldest queued job
+// of the same priority on the first shard that has one. Stats reports how many jobs were accepted, blocked,
+// rejected and dropped, so shed load is visible. SubmitWait always blocks.
+//
+// # Priorities
+//
+// Jobs can be submitted with a priority. Each priority has its own queue and
+// workers always take high priority jobs first, so control-plane work is not
+// starved behind a flood of load-generator jobs:
+//
+//	pool.SubmitPriority(healthCheck, worker.PriorityHigh)
+//	pool.Submit(request) // PriorityNormal
+//
+// # Work Stealing
+//
+// Instead of one shared channel, each worker owns a local queue (shard) and
+// submissions are spread across shards round-robin, so concurrent submitters
+// and workers rarely contend on the same lock. A worker whose shard is empty
+// steals half of another shard's jobs. Priorities and queue capacity are
+// tracked across all shards: high priority jobs anywhere are taken before
+// normal ones, and each priority holds at most NumWorkers * QueueFactor jobs.
+//
+// # Resizing
+//
+// Resize changes the number of workers at runtime. Extra workers are started
+// immediately; surplus workers retire after finishing their current job. The
+// number of shards and the queue capacity are fixed when the pool is created;
+// extra workers share shards and jobs left on a retired worker's shard are
+// stolen by the others.
+//
+//	pool.Resize(16) // ramp up
+//	pool.Resize(4)  // ramp down
+//
+// # Metrics
+//
+// Metrics returns the busy-worker count, per-priority queue depth, submit
+// counters and histograms of queued time and execution time. Comparing the two
+// histograms tells whether latency comes from the work itself or from queueing.
+//
+// # Panics
+//
+// A panicking job does not kill its worker. The panic is recovered, logged,
+// counted in Stats.Panics and passed to PoolConfig.OnPanic if set.
+//
+// # Graceful Shutdown
+//
+// Stop() waits for all in-flight jobs to complete before returning. Jobs
+// still queued are dropped without running.
+// Every job receives a context that is cancelled when the pool stops (or the
+// context passed to Start() is cancelled), and additionally after
+// PoolConfig.JobTimeout if set. Jobs that honour it, such as requests stuck in
+// an injected delay, let Stop return promptly instead of waiting them out.
+//
+// Drain is the gentler alternative: it stops accepting jobs and lets the
+// queue empty with uncancelled contexts. If its ctx expires first, the jobs
+// still queued are discarded and their number is returned:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	abandoned, err := pool.Drain(ctx)
+package worker
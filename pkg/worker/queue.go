@@ -0,0 +1,104 @@
+package worker
+
+import "sync"
+
+// numPriorities は優先度の数（Priority の値をそのまま添字に使う）
+const numPriorities = 3
+
+// takeOrder はワーカーがジョブを取り出す優先度の順
+var takeOrder = [numPriorities]Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// normalize は未知の優先度を PriorityNormal として扱う
+func normalize(priority Priority) Priority {
+	if priority < 0 || priority >= numPriorities {
+		return PriorityNormal
+	}
+	return priority
+}
+
+// jobRing はジョブのFIFOリングバッファ（満杯になると拡張する）
+type jobRing struct {
+	buf  []Job
+	head int
+	n    int
+}
+
+// push は末尾にジョブを追加する
+func (r *jobRing) push(job Job) {
+	if r.n == len(r.buf) {
+		buf := make([]Job, max(2*len(r.buf), 8))
+		for i := range r.n {
+			buf[i] = r.buf[(r.head+i)%len(r.buf)]
+		}
+		r.buf, r.head = buf, 0
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = job
+	r.n++
+}
+
+// pop は先頭のジョブを取り出す
+func (r *jobRing) pop() (Job, bool) {
+	if r.n == 0 {
+		return nil, false
+	}
+	job := r.buf[r.head]
+	r.buf[r.head] = nil
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+	return job, true
+}
+
+// shard はワーカーごとのローカルキュー
+// 送信はシャードに振り分けられ、所有するワーカーが取り出すほか、手の空いたワーカーが盗む
+type shard struct {
+	mu   sync.Mutex
+	jobs [numPriorities]jobRing
+}
+
+// push は優先度のキューにジョブを追加する
+func (s *shard) push(priority Priority, job Job) {
+	s.mu.Lock()
+	s.jobs[priority].push(job)
+	s.mu.Unlock()
+}
+
+// pushAll は優先度のキューに複数のジョブを1度のロックで追加する
+func (s *shard) pushAll(priority Priority, jobs []Job) {
+	s.mu.Lock()
+	for _, job := range jobs {
+		s.jobs[priority].push(job)
+	}
+	s.mu.Unlock()
+}
+
+// pop は優先度のキューから最も古いジョブを取り出す
+func (s *shard) pop(priority Priority) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[priority].pop()
+}
+
+// stealInto は優先度のキューの半分（最低1件）を古い順に盗む
+// 1件目を返し、残りは dst に移して以降の盗みを減らす
+func (s *shard) stealInto(priority Priority, dst *shard) (Job, bool) {
+	s.mu.Lock()
+	n := (s.jobs[priority].n + 1) / 2
+	if n == 0 {
+		s.mu.Unlock()
+		return nil, false
+	}
+	stolen := make([]Job, n)
+	for i := range stolen {
+		stolen[i], _ = s.jobs[priority].pop()
+	}
+	s.mu.Unlock()
+
+	if len(stolen) > 1 {
+		dst.mu.Lock()
+		for _, job := range stolen[1:] {
+			dst.jobs[priority].push(job)
+		}
+		dst.mu.Unlock()
+	}
+	return stolen[0], true
+}
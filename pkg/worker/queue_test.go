@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJobRing(t *testing.T) {
+	var r jobRing
+	var ran []int
+	for i := range 20 {
+		r.push(func(context.Context) { ran = append(ran, i) })
+		if i%3 == 0 {
+			job, _ := r.pop()
+			job(context.Background())
+		}
+	}
+	for {
+		job, ok := r.pop()
+		if !ok {
+			break
+		}
+		job(context.Background())
+	}
+
+	if len(ran) != 20 {
+		t.Fatalf("expected 20 jobs, got %d", len(ran))
+	}
+	for i, v := range ran {
+		if v != i {
+			t.Fatalf("expected FIFO order, got %v", ran)
+		}
+	}
+}
+
+func TestShardStealInto(t *testing.T) {
+	victim, thief := &shard{}, &shard{}
+	var ran []int
+	for i := range 5 {
+		victim.push(PriorityNormal, func(context.Context) { ran = append(ran, i) })
+	}
+
+	job, ok := victim.stealInto(PriorityNormal, thief)
+	if !ok {
+		t.Fatal("expected to steal a job")
+	}
+	job(context.Background())
+
+	// Half of the jobs (rounded up) move: one is returned, the rest go to the thief
+	if victim.jobs[PriorityNormal].n != 2 || thief.jobs[PriorityNormal].n != 2 {
+		t.Errorf("expected 2 jobs left on each shard, got victim %d, thief %d",
+			victim.jobs[PriorityNormal].n, thief.jobs[PriorityNormal].n)
+	}
+	if _, ok := victim.stealInto(PriorityHigh, thief); ok {
+		t.Error("expected nothing to steal from an empty priority")
+	}
+	if len(ran) != 1 || ran[0] != 0 {
+		t.Errorf("expected the oldest job to be stolen first, got %v", ran)
+	}
+}
+
+func TestNormalizePriority(t *testing.T) {
+	if normalize(Priority(7)) != PriorityNormal || normalize(PriorityLow) != PriorityLow {
+		t.Error("expected unknown priorities to map to normal")
+	}
+}
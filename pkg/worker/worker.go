@@ -0,0 +1,769 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyasuto/chaos-kvs/pkg/logger"
+	"github.com/nyasuto/chaos-kvs/pkg/metrics"
+)
+
+// log はコンポーネント名 "worker" を付けてログを出力する子ロガー
+var log = logger.With("worker")
+
+// Job はワーカーが実行するジョブを表す
+// ctx はプールの停止時、またはジョブのタイムアウト（PoolConfig.JobTimeout）でキャンセルされる
+type Job func(ctx context.Context)
+
+// SubmitFunc の結果として返されるエラー
+var (
+	ErrNotSubmitted = errors.New("job was not submitted")
+	ErrJobPanicked  = errors.New("job panicked")
+)
+
+// Priority はジョブの優先度
+// 優先度ごとに別のキューを持ち、ワーカーは高い優先度のキューから順に取り出す
+type Priority int
+
+const (
+	PriorityNormal Priority = iota // 通常（負荷生成など）
+	PriorityHigh                   // 高（ヘルスチェックや検証読み込みなどの制御系）
+	PriorityLow                    // 低（後回しにしてよいバックグラウンド処理）
+)
+
+// String は優先度名を返す
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityNormal:
+		return "normal"
+	case PriorityLow:
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
+// Backpressure はキューが満杯のときの Submit の振る舞い
+type Backpressure int
+
+const (
+	BackpressureBlock      Backpressure = iota // 空きができるまで待つ
+	BackpressureReject                         // 送信せずに false を返す
+	BackpressureDropOldest                     // 同じ優先度のキューで古いジョブを1件捨てて送信する
+)
+
+// String は戦略名を返す
+func (b Backpressure) String() string {
+	switch b {
+	case BackpressureBlock:
+		return "block"
+	case BackpressureReject:
+		return "reject"
+	case BackpressureDropOldest:
+		return "drop-oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseBackpressure は戦略名を解析する
+func ParseBackpressure(s string) (Backpressure, error) {
+	switch s {
+	case "", "block":
+		return BackpressureBlock, nil
+	case "reject":
+		return BackpressureReject, nil
+	case "drop-oldest":
+		return BackpressureDropOldest, nil
+	default:
+		return BackpressureBlock, fmt.Errorf("unknown backpressure strategy: %s (expected block, reject or drop-oldest)", s)
+	}
+}
+
+// PoolConfig はワーカープールの設定
+type PoolConfig struct {
+	NumWorkers   int           // ワーカー数（0でCPU数）
+	QueueFactor  int           // 優先度ごとのキューサイズ = NumWorkers * QueueFactor
+	Backpressure Backpressure  // キューが満杯のときの Submit の振る舞い
+	JobTimeout   time.Duration // ジョブごとの実行時間の上限（0で無制限）
+
+	// OnPanic はジョブがパニックした際に呼ばれる（省略可）
+	// パニックは回復され、ワーカーは次のジョブの処理を続ける
+	OnPanic func(recovered any, stack []byte)
+}
+
+// DefaultPoolConfig はデフォルト設定を返す
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		NumWorkers:   0,   // CPU数
+		QueueFactor:  100, // デフォルト倍率
+		Backpressure: BackpressureBlock,
+	}
+}
+
+// Stats はジョブ送信の結果ごとの件数
+type Stats struct {
+	Accepted uint64 // キューに入ったジョブ（Blocked を含む）
+	Blocked  uint64 // キューの空きを待ってから入ったジョブ
+	Rejected uint64 // キューが満杯で送信されなかったジョブ
+	Dropped  uint64 // 新しいジョブのために捨てられたキュー内のジョブ
+	Panics   uint64 // 実行中にパニックしたジョブ
+}
+
+// Pool はゴルーチンのプールを管理する
+// キューはワーカーごとのシャードに分かれており、送信はラウンドロビンで振り分けられる
+// 自分のシャードが空のワーカーは他のシャードからジョブを盗む（ワークスティーリング）
+type Pool struct {
+	numWorkers int
+	retire     []chan struct{} // 稼働中のワーカーごとの退役通知（Resizeでの縮小に使用）
+	wg         sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
+	started    bool
+	stopping   atomic.Bool
+	mu         sync.Mutex
+
+	backpressure Backpressure
+	accepted     atomic.Uint64
+	blocked      atomic.Uint64
+	rejected     atomic.Uint64
+	dropped      atomic.Uint64
+	panics       atomic.Uint64
+	onPanic      func(recovered any, stack []byte)
+	jobTimeout   time.Duration
+
+	shards    []*shard                     // ローカルキュー（数は作成時のワーカー数で固定）
+	cursor    atomic.Uint64                // 送信先のシャードを選ぶカウンタ
+	queueSize int64                        // 優先度ごとのキューの容量（全シャードの合計）
+	queued    [numPriorities]atomic.Int64  // 優先度ごとのキュー内のジョブ数
+	idle      atomic.Int64                 // ジョブを待っているワーカー数
+	wake      chan struct{}                // 待っているワーカーへの通知
+	waiting   [numPriorities]atomic.Int64  // キューの空きを待っている送信数
+	space     [numPriorities]chan struct{} // 空きを待っている送信への通知
+
+	busy      atomic.Int64       // キューから取り出して実行を終えていないジョブ数（実行中のワーカー数）
+	queueTime *metrics.Histogram // 送信から実行開始までの待ち時間
+	execTime  *metrics.Histogram // ジョブの実行時間
+}
+
+// NewPool は新しいワーカープールを作成する
+// numWorkers が 0 の場合は CPU 数を使用
+func NewPool(numWorkers int) *Pool {
+	config := DefaultPoolConfig()
+	config.NumWorkers = numWorkers
+	return NewPoolWithConfig(config)
+}
+
+// NewPoolWithConfig は設定を指定してワーカープールを作成する
+func NewPoolWithConfig(config PoolConfig) *Pool {
+	numWorkers := config.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	queueFactor := config.QueueFactor
+	if queueFactor <= 0 {
+		queueFactor = 100
+	}
+	p := &Pool{
+		numWorkers:   numWorkers,
+		shards:       make([]*shard, numWorkers),
+		queueSize:    int64(numWorkers * queueFactor),
+		wake:         make(chan struct{}, numWorkers),
+		backpressure: config.Backpressure,
+		onPanic:      config.OnPanic,
+		jobTimeout:   config.JobTimeout,
+		queueTime:    metrics.NewHistogram(nil),
+		execTime:     metrics.NewHistogram(nil),
+	}
+	for i := range p.shards {
+		p.shards[i] = &shard{}
+	}
+	for i := range p.space {
+		p.space[i] = make(chan struct{}, 1)
+	}
+	return p
+}
+
+// Start はワーカープールを起動する
+func (p *Pool) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return
+	}
+
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	p.started = true
+
+	p.spawn(p.numWorkers)
+
+	log.Info("", "WorkerPool started with %d workers", p.numWorkers)
+}
+
+// spawn はワーカーを n 個追加で起動する（p.mu を保持して呼び出す）
+func (p *Pool) spawn(n int) {
+	for range n {
+		retire := make(chan struct{})
+		home := p.shards[len(p.retire)%len(p.shards)]
+		p.retire = append(p.retire, retire)
+		p.wg.Add(1)
+		go p.worker(home, retire)
+	}
+}
+
+// worker は個々のワーカーゴルーチン
+// home は主に取り出すシャードで、Resize後は複数のワーカーで共有することもある
+// retire が閉じられると、実行中のジョブを終えてから終了する
+func (p *Pool) worker(home *shard, retire <-chan struct{}) {
+	defer p.wg.Done()
+
+	for {
+		job, ok := p.next(home, retire)
+		if !ok {
+			return
+		}
+		p.run(job)
+	}
+}
+
+// run はジョブを実行する
+// パニックしたジョブがワーカーを終了させてプールの容量を減らさないよう、回復して報告する
+// take で増やした実行中のジョブ数は、ジョブが終わってから減らす
+func (p *Pool) run(job Job) {
+	defer p.busy.Add(-1)
+
+	ctx := p.ctx
+	if p.jobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.jobTimeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.reportPanic(r)
+		}
+	}()
+	job(ctx)
+}
+
+// reportPanic は回復したパニックを記録し、コールバックに通知する
+func (p *Pool) reportPanic(r any) {
+	p.panics.Add(1)
+	stack := debug.Stack()
+	log.Error("", "Job panicked: %v", r)
+	if p.onPanic != nil {
+		p.onPanic(r, stack)
+	}
+}
+
+// next は高い優先度のキューから順に次のジョブを取り出す
+// 全てのキューが空の場合はジョブが送信されるまで待つ
+// プールが停止されるとキューに残ったジョブを実行せずに false を返す（残ったジョブは Stop が捨てる）
+// キューに残ったジョブを実行し終えてから停止するには Drain を使う
+func (p *Pool) next(home *shard, retire <-chan struct{}) (Job, bool) {
+	for {
+		select {
+		case <-p.ctx.Done():
+			return nil, false
+		case <-retire:
+			// 自分のシャードに残ったジョブは他のワーカーに任せる
+			p.notify()
+			return nil, false
+		default:
+		}
+
+		if job, ok := p.take(home); ok {
+			return job, true
+		}
+
+		// 待機を宣言してから再確認し、その間に送信されたジョブの通知を取りこぼさない
+		p.idle.Add(1)
+		if job, ok := p.take(home); ok {
+			p.idle.Add(-1)
+			return job, true
+		}
+		select {
+		case <-p.ctx.Done():
+			p.idle.Add(-1)
+			return nil, false
+		case <-retire:
+			p.idle.Add(-1)
+			p.notify()
+			return nil, false
+		case <-p.wake:
+			p.idle.Add(-1)
+		}
+	}
+}
+
+// take は自分のシャード、なければ他のシャードから優先度順にジョブを取り出す
+// キューのジョブ数を減らす前に実行中のジョブ数を増やし、Drain が取り出した直後のジョブを見落とさないようにする
+func (p *Pool) take(home *shard) (Job, bool) {
+	for _, priority := range takeOrder {
+		if p.queued[priority].Load() == 0 {
+			continue
+		}
+		if job, ok := home.pop(priority); ok {
+			p.busy.Add(1)
+			p.release(priority)
+			return job, true
+		}
+		start := int(p.cursor.Load())
+		for i := range p.shards {
+			victim := p.shards[(start+i)%len(p.shards)]
+			if victim == home {
+				continue
+			}
+			if job, ok := victim.stealInto(priority, home); ok {
+				p.busy.Add(1)
+				p.release(priority)
+				// 盗んで移したジョブを待機中のワーカーにも分ける
+				p.notify()
+				return job, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// notify は待機中のワーカーがいれば1つ起こす
+func (p *Pool) notify() {
+	if p.idle.Load() == 0 {
+		return
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// reserve は優先度のキューに空きがあれば1件分を確保する
+func (p *Pool) reserve(priority Priority) bool {
+	for {
+		n := p.queued[priority].Load()
+		if n >= p.queueSize {
+			return false
+		}
+		if p.queued[priority].CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// reserveN は優先度のキューに最大 n 件分の空きを確保し、確保できた件数を返す
+func (p *Pool) reserveN(priority Priority, n int) int {
+	for {
+		queued := p.queued[priority].Load()
+		free := int(min(p.queueSize-queued, int64(n)))
+		if free <= 0 {
+			return 0
+		}
+		if p.queued[priority].CompareAndSwap(queued, queued+int64(free)) {
+			return free
+		}
+	}
+}
+
+// release はキューから取り出した1件分の空きを返し、空きを待つ送信に通知する
+func (p *Pool) release(priority Priority) {
+	p.queued[priority].Add(-1)
+	p.signalSpace(priority)
+}
+
+// signalSpace は空きを待っている送信があれば1つ起こす
+func (p *Pool) signalSpace(priority Priority) {
+	if p.waiting[priority].Load() == 0 {
+		return
+	}
+	select {
+	case p.space[priority] <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue は確保済みの空きにジョブを入れ、待機中のワーカーに通知する
+func (p *Pool) enqueue(priority Priority, job Job) {
+	p.shards[p.cursor.Add(1)%uint64(len(p.shards))].push(priority, job)
+	p.notify()
+}
+
+// enqueueBatch は確保済みの空きに複数のジョブを1つのシャードへまとめて入れる
+func (p *Pool) enqueueBatch(priority Priority, jobs []Job) {
+	p.shards[p.cursor.Add(1)%uint64(len(p.shards))].pushAll(priority, jobs)
+	for range min(len(jobs), len(p.shards)) {
+		p.notify()
+	}
+}
+
+// dropOldest はシャードを順に見て最初に見つかった最も古いジョブを捨て、その空きにジョブを入れる
+func (p *Pool) dropOldest(priority Priority, job Job) bool {
+	start := int(p.cursor.Add(1))
+	for i := range p.shards {
+		s := p.shards[(start+i)%len(p.shards)]
+		if _, ok := s.pop(priority); ok {
+			s.push(priority, job)
+			p.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// Submit はジョブを通常優先度でプールに送信する
+func (p *Pool) Submit(job Job) bool {
+	return p.SubmitPriority(job, PriorityNormal)
+}
+
+// SubmitPriority はジョブを指定した優先度でプールに送信する
+// キューが満杯の場合は設定した Backpressure に従う
+func (p *Pool) SubmitPriority(job Job, priority Priority) bool {
+	ctx, ok := p.accepting()
+	if !ok {
+		return false
+	}
+
+	priority = normalize(priority)
+	job = p.instrument(job)
+	if p.reserve(priority) {
+		p.enqueue(priority, job)
+		p.accepted.Add(1)
+		return true
+	}
+
+	switch p.backpressure {
+	case BackpressureReject:
+		p.rejected.Add(1)
+		return false
+	case BackpressureDropOldest:
+		if p.dropOldest(priority, job) {
+			p.dropped.Add(1)
+			p.accepted.Add(1)
+			return true
+		}
+		p.rejected.Add(1)
+		return false
+	default:
+		return p.wait(ctx, priority, job)
+	}
+}
+
+// accepting はプールがジョブを受け付けているかと、受け付けている間のプールの ctx を返す
+func (p *Pool) accepting() (context.Context, bool) {
+	if p.stopping.Load() {
+		return nil, false
+	}
+	p.mu.Lock()
+	ctx := p.ctx
+	p.mu.Unlock()
+	if ctx == nil {
+		return nil, false
+	}
+	select {
+	case <-ctx.Done():
+		return nil, false
+	default:
+		return ctx, true
+	}
+}
+
+// wait はキューに空きができるまで待ってからジョブを送信する（ctx はプールの ctx）
+func (p *Pool) wait(ctx context.Context, priority Priority, job Job) bool {
+	p.waiting[priority].Add(1)
+	defer p.waiting[priority].Add(-1)
+
+	// 待機を宣言してから確保を試み、その間に空いた分の通知を取りこぼさない
+	for !p.reserve(priority) {
+		select {
+		case <-ctx.Done():
+			p.rejected.Add(1)
+			return false
+		case <-p.space[priority]:
+		}
+	}
+	// 同時に複数の空きができた場合に備え、次の待機者にも確認させる
+	if p.waiting[priority].Load() > 1 {
+		p.signalSpace(priority)
+	}
+
+	p.enqueue(priority, job)
+	p.accepted.Add(1)
+	p.blocked.Add(1)
+	return true
+}
+
+// SubmitBatch は複数のジョブを通常優先度でまとめて送信し、送信できた件数を返す
+// 空きのある分は1度の確保と1つのシャードへの追加で送信するため、1件ずつの Submit より同期が少ない
+// 空きを超えた分は1件ずつ設定した Backpressure に従う
+func (p *Pool) SubmitBatch(jobs []Job) int {
+	if len(jobs) == 0 {
+		return 0
+	}
+	if _, ok := p.accepting(); !ok {
+		return 0
+	}
+
+	n := p.reserveN(PriorityNormal, len(jobs))
+	if n > 0 {
+		batch := make([]Job, n)
+		for i, job := range jobs[:n] {
+			batch[i] = p.instrument(job)
+		}
+		p.enqueueBatch(PriorityNormal, batch)
+		p.accepted.Add(uint64(n))
+	}
+
+	submitted := n
+	for _, job := range jobs[n:] {
+		if p.Submit(job) {
+			submitted++
+		}
+	}
+	return submitted
+}
+
+// SubmitFunc は結果を返すジョブを通常優先度で送信する
+// 返されるチャネルには fn の戻り値が1度だけ送られる。送信できなかった場合は
+// ErrNotSubmitted、パニックした場合は ErrJobPanicked をラップしたエラーが送られる
+// BackpressureDropOldest でキューから捨てられた場合は何も送られないため、待つ側はタイムアウトを設けること
+func (p *Pool) SubmitFunc(fn func(ctx context.Context) error) <-chan error {
+	result := make(chan error, 1)
+	job := func(ctx context.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				p.reportPanic(r)
+				result <- fmt.Errorf("%w: %v", ErrJobPanicked, r)
+			}
+		}()
+		result <- fn(ctx)
+	}
+	if !p.Submit(job) {
+		result <- ErrNotSubmitted
+	}
+	return result
+}
+
+// SubmitWait はジョブを送信し、キューに空きがなければ Backpressure の設定に関わらずブロックする
+func (p *Pool) SubmitWait(job Job) bool {
+	ctx, ok := p.accepting()
+	if !ok {
+		return false
+	}
+
+	job = p.instrument(job)
+	if p.reserve(PriorityNormal) {
+		p.enqueue(PriorityNormal, job)
+		p.accepted.Add(1)
+		return true
+	}
+	return p.wait(ctx, PriorityNormal, job)
+}
+
+// instrument はキューでの待ち時間・実行時間を記録するようジョブを包む
+// 実行中のワーカー数は take・run で数える
+func (p *Pool) instrument(job Job) Job {
+	queued := time.Now()
+	return func(ctx context.Context) {
+		start := time.Now()
+		p.queueTime.Observe(start.Sub(queued))
+		defer func() {
+			p.execTime.Observe(time.Since(start))
+		}()
+		job(ctx)
+	}
+}
+
+// PoolMetrics はワーカープールのある時点のメトリクス
+// レイテンシの原因がノードかキューイングかを切り分けるために使う
+type PoolMetrics struct {
+	Stats
+	Workers   int                       // ワーカー数
+	Busy      int                       // ジョブを実行中のワーカー数
+	Queued    map[Priority]int          // 優先度ごとのキューの深さ
+	QueueTime metrics.HistogramSnapshot // 送信から実行開始までの待ち時間
+	ExecTime  metrics.HistogramSnapshot // ジョブの実行時間
+}
+
+// Utilization はジョブを実行中のワーカーの割合（0-1）を返す
+func (m PoolMetrics) Utilization() float64 {
+	if m.Workers == 0 {
+		return 0
+	}
+	return float64(m.Busy) / float64(m.Workers)
+}
+
+// Metrics は現在のメトリクスを返す
+func (p *Pool) Metrics() PoolMetrics {
+	return PoolMetrics{
+		Stats:   p.Stats(),
+		Workers: p.NumWorkers(),
+		Busy:    int(p.busy.Load()),
+		Queued: map[Priority]int{
+			PriorityHigh:   p.QueueSizeOf(PriorityHigh),
+			PriorityNormal: p.QueueSizeOf(PriorityNormal),
+			PriorityLow:    p.QueueSizeOf(PriorityLow),
+		},
+		QueueTime: p.queueTime.Snapshot(),
+		ExecTime:  p.execTime.Snapshot(),
+	}
+}
+
+// Stats はジョブ送信の結果ごとの件数を返す
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Accepted: p.accepted.Load(),
+		Blocked:  p.blocked.Load(),
+		Rejected: p.rejected.Load(),
+		Dropped:  p.dropped.Load(),
+		Panics:   p.panics.Load(),
+	}
+}
+
+// Stop はワーカープールを停止する
+// 実行中のジョブの終了を待ち、キューに残ったジョブは実行せずに捨てる
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if !p.started {
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	p.stopping.Store(true)
+	if dropped := p.halt(); dropped > 0 {
+		log.Info("", "WorkerPool stopped with %d queued jobs dropped", dropped)
+		return
+	}
+
+	log.Info("", "WorkerPool stopped")
+}
+
+// drainPollInterval は Drain がキューと実行中のジョブを確認する間隔
+const drainPollInterval = 10 * time.Millisecond
+
+// Drain は新しいジョブの受け付けを止め、キューが空になり実行中のジョブが終わるまで待ってから停止する
+// Stop と異なり、待っている間はジョブの ctx をキャンセルしない
+// ctx の期限が先に来た場合はキューに残ったジョブを捨て、その件数と ctx のエラーを返す
+func (p *Pool) Drain(ctx context.Context) (abandoned int, err error) {
+	p.mu.Lock()
+	if !p.started {
+		p.mu.Unlock()
+		return 0, nil
+	}
+	p.mu.Unlock()
+
+	p.stopping.Store(true)
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+wait:
+	for p.QueueSize() > 0 || p.busy.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	abandoned = p.discard()
+	abandoned += p.halt()
+
+	if abandoned > 0 {
+		log.Warn("", "WorkerPool drained with %d abandoned jobs", abandoned)
+	} else {
+		log.Info("", "WorkerPool drained")
+	}
+	return abandoned, err
+}
+
+// discard はキューに残っている全てのジョブを捨て、その件数を返す
+func (p *Pool) discard() int {
+	discarded := 0
+	for _, s := range p.shards {
+		for _, priority := range takeOrder {
+			for {
+				if _, ok := s.pop(priority); !ok {
+					break
+				}
+				p.release(priority)
+				discarded++
+			}
+		}
+	}
+	return discarded
+}
+
+// halt はジョブの ctx をキャンセルし、全てのワーカーの終了を待って停止状態に戻す
+// ワーカーが実行しなかったジョブはキューから捨て、その件数を返す
+func (p *Pool) halt() int {
+	p.cancel()
+	p.wg.Wait()
+	dropped := p.discard()
+
+	p.mu.Lock()
+	p.started = false
+	p.retire = nil
+	p.stopping.Store(false)
+	p.mu.Unlock()
+	return dropped
+}
+
+// Resize は実行中のワーカー数を n に変更する（0以下でCPU数）
+// 増やす場合はワーカーを追加で起動し、減らす場合は余分なワーカーを
+// 実行中のジョブを終えた時点で退役させる。キューの容量は変わらない
+// 起動前に呼び出した場合は Start 時のワーカー数になる
+func (p *Pool) Resize(n int) {
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prev := p.numWorkers
+	p.numWorkers = n
+	if !p.started || p.stopping.Load() || n == prev {
+		return
+	}
+
+	if n > prev {
+		p.spawn(n - prev)
+	} else {
+		for _, retire := range p.retire[n:] {
+			close(retire)
+		}
+		p.retire = p.retire[:n]
+	}
+	log.Info("", "WorkerPool resized from %d to %d workers", prev, n)
+}
+
+// NumWorkers はワーカー数を返す
+func (p *Pool) NumWorkers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.numWorkers
+}
+
+// QueueSize は全ての優先度のキューに溜まっているジョブ数を返す
+func (p *Pool) QueueSize() int {
+	total := 0
+	for _, priority := range takeOrder {
+		total += p.QueueSizeOf(priority)
+	}
+	return total
+}
+
+// QueueSizeOf は指定した優先度のキューに溜まっているジョブ数を返す
+func (p *Pool) QueueSizeOf(priority Priority) int {
+	return int(p.queued[normalize(priority)].Load())
+}
@@ -0,0 +1,782 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewWorkerPool(t *testing.T) {
+	pool := NewPool(4)
+	if pool.NumWorkers() != 4 {
+		t.Errorf("expected 4 workers, got %d", pool.NumWorkers())
+	}
+
+	// Zero should default to CPU count
+	pool2 := NewPool(0)
+	if pool2.NumWorkers() != runtime.NumCPU() {
+		t.Errorf("expected %d workers, got %d", runtime.NumCPU(), pool2.NumWorkers())
+	}
+}
+
+func TestWorkerPoolStartStop(t *testing.T) {
+	pool := NewPool(2)
+	ctx := context.Background()
+
+	pool.Start(ctx)
+	// Double start should be no-op
+	pool.Start(ctx)
+
+	pool.Stop()
+	// Double stop should be no-op
+	pool.Stop()
+}
+
+func TestWorkerPoolSubmit(t *testing.T) {
+	pool := NewPool(2)
+	ctx := context.Background()
+	pool.Start(ctx)
+	defer pool.Stop()
+
+	var counter atomic.Int32
+	done := make(chan struct{})
+
+	for range 10 {
+		pool.Submit(func(context.Context) {
+			counter.Add(1)
+		})
+	}
+
+	go func() {
+		for counter.Load() < 10 {
+			time.Sleep(time.Millisecond)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// Success
+	case <-time.After(time.Second):
+		t.Error("timeout waiting for jobs to complete")
+	}
+
+	if counter.Load() != 10 {
+		t.Errorf("expected 10 jobs completed, got %d", counter.Load())
+	}
+}
+
+func TestWorkerPoolSubmitAfterStop(t *testing.T) {
+	pool := NewPool(2)
+	ctx := context.Background()
+	pool.Start(ctx)
+	pool.Stop()
+
+	// Submit after stop should return false
+	result := pool.Submit(func(context.Context) {})
+	if result {
+		t.Error("expected Submit to return false after stop")
+	}
+}
+
+func TestWorkerPoolQueueSize(t *testing.T) {
+	pool := NewPool(1)
+	ctx := context.Background()
+
+	// Start but with a blocking job
+	pool.Start(ctx)
+	defer pool.Stop()
+
+	// Queue should be empty initially
+	if pool.QueueSize() != 0 {
+		t.Errorf("expected queue size 0, got %d", pool.QueueSize())
+	}
+}
+
+func TestWorkerPoolContextCancel(t *testing.T) {
+	pool := NewPool(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Start(ctx)
+
+	var counter atomic.Int32
+	blocker := make(chan struct{})
+
+	// Submit a blocking job
+	pool.Submit(func(context.Context) {
+		<-blocker
+		counter.Add(1)
+	})
+
+	// Cancel context
+	cancel()
+
+	// Unblock the job
+	close(blocker)
+
+	// Give time for workers to stop
+	time.Sleep(50 * time.Millisecond)
+
+	// Submit after context cancel should fail
+	result := pool.Submit(func(context.Context) {
+		counter.Add(1)
+	})
+	if result {
+		t.Error("expected Submit to return false after context cancel")
+	}
+
+	pool.Stop()
+}
+
+func TestWorkerPoolSubmitWait(t *testing.T) {
+	pool := NewPool(2)
+	ctx := context.Background()
+	pool.Start(ctx)
+	defer pool.Stop()
+
+	var counter atomic.Int32
+
+	// Submit jobs using SubmitWait
+	for range 5 {
+		result := pool.SubmitWait(func(context.Context) {
+			counter.Add(1)
+		})
+		if !result {
+			t.Error("expected SubmitWait to return true")
+		}
+	}
+
+	// Wait for jobs to complete
+	time.Sleep(50 * time.Millisecond)
+
+	if counter.Load() != 5 {
+		t.Errorf("expected 5 jobs completed, got %d", counter.Load())
+	}
+}
+
+func TestWorkerPoolSubmitWaitAfterCancel(t *testing.T) {
+	pool := NewPool(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Start(ctx)
+
+	cancel()
+
+	// SubmitWait after cancel should return false
+	result := pool.SubmitWait(func(context.Context) {})
+	if result {
+		t.Error("expected SubmitWait to return false after cancel")
+	}
+
+	pool.Stop()
+}
+
+func TestWorkerPoolNegativeWorkers(t *testing.T) {
+	// Negative workers should default to CPU count
+	pool := NewPool(-5)
+	if pool.NumWorkers() != runtime.NumCPU() {
+		t.Errorf("expected %d workers for negative input, got %d", runtime.NumCPU(), pool.NumWorkers())
+	}
+}
+
+func TestWorkerPoolConcurrentSubmit(t *testing.T) {
+	pool := NewPool(4)
+	ctx := context.Background()
+	pool.Start(ctx)
+	defer pool.Stop()
+
+	var counter atomic.Int32
+	const numGoroutines = 10
+	const jobsPerGoroutine = 100
+
+	var wg atomic.Int32
+	wg.Store(numGoroutines)
+
+	for range numGoroutines {
+		go func() {
+			for range jobsPerGoroutine {
+				pool.Submit(func(context.Context) {
+					counter.Add(1)
+				})
+			}
+			wg.Add(-1)
+		}()
+	}
+
+	// Wait for all goroutines to finish submitting
+	for wg.Load() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Wait for all jobs to complete
+	time.Sleep(100 * time.Millisecond)
+
+	expected := int32(numGoroutines * jobsPerGoroutine)
+	if counter.Load() != expected {
+		t.Errorf("expected %d jobs completed, got %d", expected, counter.Load())
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	pool := NewPool(2)
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	var running atomic.Int32
+	release := make(chan struct{})
+	blocking := func(context.Context) {
+		running.Add(1)
+		<-release
+		running.Add(-1)
+	}
+	waitRunning := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for running.Load() != want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := running.Load(); got != want {
+			t.Fatalf("expected %d running jobs, got %d", want, got)
+		}
+	}
+
+	for range 6 {
+		pool.Submit(blocking)
+	}
+	waitRunning(2)
+
+	// Growing starts new workers that pick up the queued jobs
+	pool.Resize(4)
+	if pool.NumWorkers() != 4 {
+		t.Errorf("expected 4 workers, got %d", pool.NumWorkers())
+	}
+	waitRunning(4)
+
+	// Shrinking lets in-flight jobs finish before workers retire
+	pool.Resize(1)
+	close(release)
+	waitRunning(0)
+
+	hold := make(chan struct{})
+	defer close(hold)
+	for range 3 {
+		pool.Submit(func(context.Context) {
+			running.Add(1)
+			<-hold
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := running.Load(); got != 1 {
+		t.Errorf("expected 1 running job after shrinking, got %d", got)
+	}
+}
+
+func TestWorkerPoolResizeBeforeStart(t *testing.T) {
+	pool := NewPool(2)
+	pool.Resize(3)
+	if pool.NumWorkers() != 3 {
+		t.Errorf("expected 3 workers, got %d", pool.NumWorkers())
+	}
+	pool.Resize(0)
+	if pool.NumWorkers() != runtime.NumCPU() {
+		t.Errorf("expected %d workers, got %d", runtime.NumCPU(), pool.NumWorkers())
+	}
+}
+
+func TestWorkerPoolPriority(t *testing.T) {
+	pool := NewPool(1)
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	// Occupy the only worker so that the queued jobs are ordered by priority
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) Job {
+		return func(context.Context) {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+	pool.SubmitPriority(record("low"), PriorityLow)
+	pool.Submit(record("normal-1"))
+	pool.SubmitPriority(record("high"), PriorityHigh)
+	pool.Submit(record("normal-2"))
+
+	if pool.QueueSize() != 4 || pool.QueueSizeOf(PriorityHigh) != 1 || pool.QueueSizeOf(PriorityNormal) != 2 {
+		t.Errorf("unexpected queue sizes: total %d, high %d, normal %d",
+			pool.QueueSize(), pool.QueueSizeOf(PriorityHigh), pool.QueueSizeOf(PriorityNormal))
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for pool.QueueSize() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"high", "normal-1", "normal-2", "low"}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+}
+
+func TestWorkerPoolBackpressure(t *testing.T) {
+	tests := []struct {
+		strategy Backpressure
+		want     Stats
+		ran      int // The queued job that runs after the blocker
+	}{
+		{BackpressureReject, Stats{Accepted: 2, Rejected: 1}, 1},
+		{BackpressureDropOldest, Stats{Accepted: 3, Dropped: 1}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			pool := NewPoolWithConfig(PoolConfig{NumWorkers: 1, QueueFactor: 1, Backpressure: tt.strategy})
+			pool.Start(context.Background())
+			defer pool.Stop()
+
+			var mu sync.Mutex
+			var ran []int
+			release := make(chan struct{})
+			started := make(chan struct{})
+			pool.Submit(func(context.Context) {
+				close(started)
+				<-release
+			})
+			<-started
+
+			// The queue holds one job; the second submission hits backpressure
+			for i := range 2 {
+				pool.Submit(func(context.Context) {
+					mu.Lock()
+					ran = append(ran, i+1)
+					mu.Unlock()
+				})
+			}
+			if got := pool.Stats(); got != tt.want {
+				t.Errorf("expected stats %+v, got %+v", tt.want, got)
+			}
+
+			close(release)
+			deadline := time.Now().Add(time.Second)
+			for pool.QueueSize() > 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(ran) != 1 || ran[0] != tt.ran {
+				t.Errorf("expected job %d to run, got %v", tt.ran, ran)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolBlockedStats(t *testing.T) {
+	pool := NewPoolWithConfig(PoolConfig{NumWorkers: 1, QueueFactor: 1})
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+	pool.Submit(func(context.Context) {})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	if !pool.Submit(func(context.Context) {}) {
+		t.Fatal("expected blocked submit to succeed")
+	}
+	if got := pool.Stats(); got.Accepted != 3 || got.Blocked != 1 {
+		t.Errorf("expected 3 accepted with 1 blocked, got %+v", got)
+	}
+}
+
+func TestParseBackpressure(t *testing.T) {
+	for _, b := range []Backpressure{BackpressureBlock, BackpressureReject, BackpressureDropOldest} {
+		got, err := ParseBackpressure(b.String())
+		if err != nil || got != b {
+			t.Errorf("ParseBackpressure(%q) = %v, %v", b.String(), got, err)
+		}
+	}
+	if _, err := ParseBackpressure("shed"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestWorkerPoolMetrics(t *testing.T) {
+	pool := NewPool(2)
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	for range 2 {
+		pool.Submit(func(context.Context) {
+			started <- struct{}{}
+			<-release
+		})
+	}
+	<-started
+	<-started
+	pool.SubmitPriority(func(context.Context) {}, PriorityHigh)
+
+	m := pool.Metrics()
+	if m.Workers != 2 || m.Busy != 2 || m.Utilization() != 1 {
+		t.Errorf("expected 2 of 2 workers busy, got %d of %d", m.Busy, m.Workers)
+	}
+	if m.Queued[PriorityHigh] != 1 || m.Accepted != 3 {
+		t.Errorf("expected 1 queued high priority job and 3 accepted, got %v, %d", m.Queued, m.Accepted)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for pool.Metrics().ExecTime.Count < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	m = pool.Metrics()
+	if m.Busy != 0 || m.ExecTime.Count != 3 || m.QueueTime.Count != 3 {
+		t.Errorf("expected 3 finished jobs and no busy workers, got %+v", m)
+	}
+	if m.ExecTime.Sum < 10*time.Millisecond {
+		t.Errorf("expected blocked jobs to record execution time, got %v", m.ExecTime.Sum)
+	}
+	if m.QueueTime.Sum < 5*time.Millisecond {
+		t.Errorf("expected queued job to record waiting time, got %v", m.QueueTime.Sum)
+	}
+}
+
+func TestWorkerPoolPanicRecovery(t *testing.T) {
+	var mu sync.Mutex
+	var recovered []any
+	pool := NewPoolWithConfig(PoolConfig{
+		NumWorkers: 1,
+		OnPanic: func(r any, stack []byte) {
+			mu.Lock()
+			recovered = append(recovered, r)
+			mu.Unlock()
+			if len(stack) == 0 {
+				t.Error("expected stack trace")
+			}
+		},
+	})
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	pool.Submit(func(context.Context) { panic("boom") })
+
+	// The only worker must survive to run the next job
+	done := make(chan struct{})
+	pool.Submit(func(context.Context) { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not survive the panic")
+	}
+
+	if got := pool.Stats().Panics; got != 1 {
+		t.Errorf("expected 1 panic, got %d", got)
+	}
+	if m := pool.Metrics(); m.Busy != 0 {
+		t.Errorf("expected busy gauge to be released after panic, got %d", m.Busy)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(recovered) != 1 || recovered[0] != "boom" {
+		t.Errorf("expected OnPanic with boom, got %v", recovered)
+	}
+}
+
+func TestWorkerPoolSubmitFunc(t *testing.T) {
+	pool := NewPool(2)
+	pool.Start(context.Background())
+
+	errCheck := errors.New("inconsistent read")
+	ok := pool.SubmitFunc(func(context.Context) error { return nil })
+	failed := pool.SubmitFunc(func(context.Context) error { return errCheck })
+	panicked := pool.SubmitFunc(func(context.Context) error { panic("boom") })
+
+	if err := <-ok; err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+	if err := <-failed; !errors.Is(err, errCheck) {
+		t.Errorf("expected %v, got %v", errCheck, err)
+	}
+	if err := <-panicked; !errors.Is(err, ErrJobPanicked) {
+		t.Errorf("expected ErrJobPanicked, got %v", err)
+	}
+	if got := pool.Stats().Panics; got != 1 {
+		t.Errorf("expected 1 panic, got %d", got)
+	}
+
+	pool.Stop()
+	if err := <-pool.SubmitFunc(func(context.Context) error { return nil }); !errors.Is(err, ErrNotSubmitted) {
+		t.Errorf("expected ErrNotSubmitted after stop, got %v", err)
+	}
+}
+
+func TestWorkerPoolJobContext(t *testing.T) {
+	config := DefaultPoolConfig()
+	config.NumWorkers = 2
+	config.JobTimeout = 20 * time.Millisecond
+	pool := NewPoolWithConfig(config)
+	pool.Start(context.Background())
+
+	if err := <-pool.SubmitFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected job timeout, got %v", err)
+	}
+
+	// Stop cancels jobs still waiting on their context
+	pool = NewPool(1)
+	pool.Start(context.Background())
+	started := make(chan struct{})
+	result := pool.SubmitFunc(func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+	<-started
+
+	begin := time.Now()
+	pool.Stop()
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("expected Stop to cancel the running job, took %v", elapsed)
+	}
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWorkerPoolWorkStealing(t *testing.T) {
+	pool := NewPool(4)
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	// Shrinking leaves shards without a home worker; their jobs must still run
+	pool.Resize(1)
+	var counter atomic.Int32
+	for range 100 {
+		pool.Submit(func(context.Context) { counter.Add(1) })
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for counter.Load() < 100 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if counter.Load() != 100 {
+		t.Errorf("expected 100 jobs completed, got %d", counter.Load())
+	}
+	if pool.QueueSize() != 0 {
+		t.Errorf("expected empty queue, got %d", pool.QueueSize())
+	}
+}
+
+func BenchmarkPoolSubmit(b *testing.B) {
+	pool := NewPool(0)
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	var wg sync.WaitGroup
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			wg.Add(1)
+			if !pool.Submit(func(context.Context) { wg.Done() }) {
+				wg.Done()
+			}
+		}
+	})
+	wg.Wait()
+}
+
+func TestWorkerPoolSubmitBatch(t *testing.T) {
+	pool := NewPoolWithConfig(PoolConfig{NumWorkers: 1, QueueFactor: 3, Backpressure: BackpressureReject})
+	pool.Start(context.Background())
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	var counter atomic.Int32
+	batch := make([]Job, 5)
+	for i := range batch {
+		batch[i] = func(context.Context) { counter.Add(1) }
+	}
+
+	// The queue has room for 3; the rest are rejected one by one
+	if got := pool.SubmitBatch(batch); got != 3 {
+		t.Errorf("expected 3 jobs submitted, got %d", got)
+	}
+	if got := pool.Stats(); got.Accepted != 4 || got.Rejected != 2 {
+		t.Errorf("unexpected stats: %+v", got)
+	}
+	if pool.QueueSize() != 3 {
+		t.Errorf("expected queue size 3, got %d", pool.QueueSize())
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for counter.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if counter.Load() != 3 {
+		t.Errorf("expected 3 jobs completed, got %d", counter.Load())
+	}
+	if got := pool.SubmitBatch(nil); got != 0 {
+		t.Errorf("expected empty batch to submit nothing, got %d", got)
+	}
+}
+
+func TestWorkerPoolDrain(t *testing.T) {
+	pool := NewPool(1)
+	pool.Start(context.Background())
+
+	var counter atomic.Int32
+	var cancelled atomic.Bool
+	for range 5 {
+		pool.Submit(func(ctx context.Context) {
+			time.Sleep(time.Millisecond)
+			if ctx.Err() != nil {
+				cancelled.Store(true)
+			}
+			counter.Add(1)
+		})
+	}
+
+	abandoned, err := pool.Drain(context.Background())
+	if abandoned != 0 || err != nil {
+		t.Errorf("expected a complete drain, got %d abandoned, %v", abandoned, err)
+	}
+	if counter.Load() != 5 {
+		t.Errorf("expected 5 jobs completed, got %d", counter.Load())
+	}
+	if cancelled.Load() {
+		t.Error("expected drained jobs to run with a live context")
+	}
+	if pool.Submit(func(context.Context) {}) {
+		t.Error("expected Submit to fail after Drain")
+	}
+}
+
+func TestWorkerPoolDrainTakenJobs(t *testing.T) {
+	// キューから取り出した直後で実行を始める前のジョブも、Drain は終わるまで待つ
+	for i := range 50 {
+		pool := NewPool(4)
+		pool.Start(context.Background())
+
+		var ran, cancelled atomic.Int32
+		for range 4 {
+			pool.Submit(func(ctx context.Context) {
+				if ctx.Err() != nil {
+					cancelled.Add(1)
+				}
+				ran.Add(1)
+			})
+		}
+
+		if abandoned, err := pool.Drain(context.Background()); abandoned != 0 || err != nil {
+			t.Fatalf("iteration %d: expected a complete drain, got %d abandoned, %v", i, abandoned, err)
+		}
+		if ran.Load() != 4 || cancelled.Load() != 0 {
+			t.Fatalf("iteration %d: expected 4 jobs with a live context, got %d ran, %d cancelled", i, ran.Load(), cancelled.Load())
+		}
+	}
+}
+
+func TestWorkerPoolStopDropsQueued(t *testing.T) {
+	pool := NewPool(1)
+	pool.Start(context.Background())
+
+	started := make(chan struct{})
+	pool.Submit(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	})
+	<-started
+
+	var ran atomic.Int32
+	for range 3 {
+		pool.Submit(func(context.Context) { ran.Add(1) })
+	}
+
+	pool.Stop()
+	if ran.Load() != 0 {
+		t.Errorf("expected queued jobs to be dropped on Stop, but %d ran", ran.Load())
+	}
+	if pool.QueueSize() != 0 {
+		t.Errorf("expected empty queue, got %d", pool.QueueSize())
+	}
+}
+
+func TestWorkerPoolDrainDeadline(t *testing.T) {
+	pool := NewPool(1)
+	pool.Start(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(func(ctx context.Context) {
+		close(started)
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+	})
+	<-started
+	for range 3 {
+		pool.Submit(func(context.Context) {})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	abandoned, err := pool.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if abandoned != 3 {
+		t.Errorf("expected 3 abandoned jobs, got %d", abandoned)
+	}
+	if pool.QueueSize() != 0 {
+		t.Errorf("expected empty queue, got %d", pool.QueueSize())
+	}
+	close(release)
+}
@@ -0,0 +1,1908 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: chaoskvs/v1/chaoskvs.proto
+
+// ChaosKVS のシナリオ制御・障害注入・メトリクス/イベント配信用 gRPC API。
+// HTTP API (/api/...) と同じサーバー状態を操作する。
+//
+// 認証が有効な場合は metadata "authorization: Bearer <token>" を指定する。
+// 参照系は reader 以上、StartScenario / StopScenario / NodeAction は operator ロールが必要。
+
+package chaoskvsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type NodeActionRequest_Action int32
+
+const (
+	NodeActionRequest_ACTION_UNSPECIFIED NodeActionRequest_Action = 0
+	NodeActionRequest_ACTION_KILL        NodeActionRequest_Action = 1
+	NodeActionRequest_ACTION_SUSPEND     NodeActionRequest_Action = 2
+	NodeActionRequest_ACTION_RESUME      NodeActionRequest_Action = 3
+	NodeActionRequest_ACTION_START       NodeActionRequest_Action = 4
+	NodeActionRequest_ACTION_DELAY       NodeActionRequest_Action = 5
+)
+
+// Enum value maps for NodeActionRequest_Action.
+var (
+	NodeActionRequest_Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_KILL",
+		2: "ACTION_SUSPEND",
+		3: "ACTION_RESUME",
+		4: "ACTION_START",
+		5: "ACTION_DELAY",
+	}
+	NodeActionRequest_Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_KILL":        1,
+		"ACTION_SUSPEND":     2,
+		"ACTION_RESUME":      3,
+		"ACTION_START":       4,
+		"ACTION_DELAY":       5,
+	}
+)
+
+func (x NodeActionRequest_Action) Enum() *NodeActionRequest_Action {
+	p := new(NodeActionRequest_Action)
+	*p = x
+	return p
+}
+
+func (x NodeActionRequest_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NodeActionRequest_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_chaoskvs_v1_chaoskvs_proto_enumTypes[0].Descriptor()
+}
+
+func (NodeActionRequest_Action) Type() protoreflect.EnumType {
+	return &file_chaoskvs_v1_chaoskvs_proto_enumTypes[0]
+}
+
+func (x NodeActionRequest_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NodeActionRequest_Action.Descriptor instead.
+func (NodeActionRequest_Action) EnumDescriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{0}
+}
+
+type Status struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Running        bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	ScenarioName   string                 `protobuf:"bytes,2,opt,name=scenario_name,json=scenarioName,proto3" json:"scenario_name,omitempty"`
+	NodeCount      int32                  `protobuf:"varint,3,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	RunningNodes   int32                  `protobuf:"varint,4,opt,name=running_nodes,json=runningNodes,proto3" json:"running_nodes,omitempty"`
+	StoppedNodes   int32                  `protobuf:"varint,5,opt,name=stopped_nodes,json=stoppedNodes,proto3" json:"stopped_nodes,omitempty"`
+	SuspendedNodes int32                  `protobuf:"varint,6,opt,name=suspended_nodes,json=suspendedNodes,proto3" json:"suspended_nodes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Status) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *Status) GetScenarioName() string {
+	if x != nil {
+		return x.ScenarioName
+	}
+	return ""
+}
+
+func (x *Status) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *Status) GetRunningNodes() int32 {
+	if x != nil {
+		return x.RunningNodes
+	}
+	return 0
+}
+
+func (x *Status) GetStoppedNodes() int32 {
+	if x != nil {
+		return x.StoppedNodes
+	}
+	return 0
+}
+
+func (x *Status) GetSuspendedNodes() int32 {
+	if x != nil {
+		return x.SuspendedNodes
+	}
+	return 0
+}
+
+type ListNodesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 状態でフィルタ（running, suspended, stopped）
+	Status []string `protobuf:"bytes,1,rep,name=status,proto3" json:"status,omitempty"`
+	// ゾーンでフィルタ
+	Zone []string `protobuf:"bytes,2,rep,name=zone,proto3" json:"zone,omitempty"`
+	// ラベルでフィルタ（全て一致）
+	Labels map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// id, status, size, delay
+	Sort string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc bool   `protobuf:"varint,5,opt,name=desc,proto3" json:"desc,omitempty"`
+	// 0で無制限
+	Limit         int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesRequest) Reset() {
+	*x = ListNodesRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesRequest) ProtoMessage() {}
+
+func (x *ListNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListNodesRequest) GetStatus() []string {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *ListNodesRequest) GetZone() []string {
+	if x != nil {
+		return x.Zone
+	}
+	return nil
+}
+
+func (x *ListNodesRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListNodesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListNodesRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListNodesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListNodesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListNodesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Nodes []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	// フィルタ後・ページネーション前の件数
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesResponse) Reset() {
+	*x = ListNodesResponse{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesResponse) ProtoMessage() {}
+
+func (x *ListNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListNodesResponse) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *ListNodesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Delay         *durationpb.Duration   `protobuf:"bytes,4,opt,name=delay,proto3" json:"delay,omitempty"`
+	Zone          string                 `protobuf:"bytes,5,opt,name=zone,proto3" json:"zone,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Node) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Node) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Node) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Node) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+func (x *Node) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *Node) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type NodeActionRequest struct {
+	state  protoimpl.MessageState   `protogen:"open.v1"`
+	NodeId string                   `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Action NodeActionRequest_Action `protobuf:"varint,2,opt,name=action,proto3,enum=chaoskvs.v1.NodeActionRequest_Action" json:"action,omitempty"`
+	// ACTION_DELAY の遅延量（未指定で設定値、0で解除）
+	Delay         *durationpb.Duration `protobuf:"bytes,3,opt,name=delay,proto3" json:"delay,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeActionRequest) Reset() {
+	*x = NodeActionRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeActionRequest) ProtoMessage() {}
+
+func (x *NodeActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeActionRequest.ProtoReflect.Descriptor instead.
+func (*NodeActionRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NodeActionRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeActionRequest) GetAction() NodeActionRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return NodeActionRequest_ACTION_UNSPECIFIED
+}
+
+func (x *NodeActionRequest) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+type StartScenarioRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// プリセット名（basic, resilience, latency, stress, quick）
+	Preset string `protobuf:"bytes,1,opt,name=preset,proto3" json:"preset,omitempty"`
+	// 完全なシナリオ設定（設定ファイルの scenario セクションと同じ）
+	Scenario *ScenarioConfig `protobuf:"bytes,2,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	// 優先順位: preset < scenario < duration/nodes
+	Duration      *durationpb.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Nodes         int32                `protobuf:"varint,4,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartScenarioRequest) Reset() {
+	*x = StartScenarioRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartScenarioRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartScenarioRequest) ProtoMessage() {}
+
+func (x *StartScenarioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartScenarioRequest.ProtoReflect.Descriptor instead.
+func (*StartScenarioRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StartScenarioRequest) GetPreset() string {
+	if x != nil {
+		return x.Preset
+	}
+	return ""
+}
+
+func (x *StartScenarioRequest) GetScenario() *ScenarioConfig {
+	if x != nil {
+		return x.Scenario
+	}
+	return nil
+}
+
+func (x *StartScenarioRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *StartScenarioRequest) GetNodes() int32 {
+	if x != nil {
+		return x.Nodes
+	}
+	return 0
+}
+
+type ScenarioConfig struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Name          string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Duration      *durationpb.Duration     `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	NodeCount     int32                    `protobuf:"varint,4,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	Zones         []string                 `protobuf:"bytes,5,rep,name=zones,proto3" json:"zones,omitempty"`
+	Client        *ScenarioConfig_Client   `protobuf:"bytes,6,opt,name=client,proto3" json:"client,omitempty"`
+	Chaos         *ScenarioConfig_Chaos    `protobuf:"bytes,7,opt,name=chaos,proto3" json:"chaos,omitempty"`
+	Recovery      *ScenarioConfig_Recovery `protobuf:"bytes,8,opt,name=recovery,proto3" json:"recovery,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScenarioConfig) Reset() {
+	*x = ScenarioConfig{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScenarioConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScenarioConfig) ProtoMessage() {}
+
+func (x *ScenarioConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScenarioConfig.ProtoReflect.Descriptor instead.
+func (*ScenarioConfig) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScenarioConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScenarioConfig) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ScenarioConfig) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *ScenarioConfig) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *ScenarioConfig) GetZones() []string {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *ScenarioConfig) GetClient() *ScenarioConfig_Client {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *ScenarioConfig) GetChaos() *ScenarioConfig_Chaos {
+	if x != nil {
+		return x.Chaos
+	}
+	return nil
+}
+
+func (x *ScenarioConfig) GetRecovery() *ScenarioConfig_Recovery {
+	if x != nil {
+		return x.Recovery
+	}
+	return nil
+}
+
+type StartScenarioResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scenario      string                 `protobuf:"bytes,1,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	RunId         string                 `protobuf:"bytes,2,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartScenarioResponse) Reset() {
+	*x = StartScenarioResponse{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartScenarioResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartScenarioResponse) ProtoMessage() {}
+
+func (x *StartScenarioResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartScenarioResponse.ProtoReflect.Descriptor instead.
+func (*StartScenarioResponse) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartScenarioResponse) GetScenario() string {
+	if x != nil {
+		return x.Scenario
+	}
+	return ""
+}
+
+func (x *StartScenarioResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type StopScenarioRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopScenarioRequest) Reset() {
+	*x = StopScenarioRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopScenarioRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopScenarioRequest) ProtoMessage() {}
+
+func (x *StopScenarioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopScenarioRequest.ProtoReflect.Descriptor instead.
+func (*StopScenarioRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{9}
+}
+
+type StopScenarioResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Result        *Result                `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopScenarioResponse) Reset() {
+	*x = StopScenarioResponse{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopScenarioResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopScenarioResponse) ProtoMessage() {}
+
+func (x *StopScenarioResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopScenarioResponse.ProtoReflect.Descriptor instead.
+func (*StopScenarioResponse) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StopScenarioResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *StopScenarioResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type Result struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ScenarioName      string                 `protobuf:"bytes,1,opt,name=scenario_name,json=scenarioName,proto3" json:"scenario_name,omitempty"`
+	StartTime         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Duration          *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Interrupted       bool                   `protobuf:"varint,5,opt,name=interrupted,proto3" json:"interrupted,omitempty"`
+	TotalRequests     uint64                 `protobuf:"varint,6,opt,name=total_requests,json=totalRequests,proto3" json:"total_requests,omitempty"`
+	SuccessRequests   uint64                 `protobuf:"varint,7,opt,name=success_requests,json=successRequests,proto3" json:"success_requests,omitempty"`
+	FailedRequests    uint64                 `protobuf:"varint,8,opt,name=failed_requests,json=failedRequests,proto3" json:"failed_requests,omitempty"`
+	ErrorRate         float64                `protobuf:"fixed64,9,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	AvgLatency        *durationpb.Duration   `protobuf:"bytes,10,opt,name=avg_latency,json=avgLatency,proto3" json:"avg_latency,omitempty"`
+	P99Latency        *durationpb.Duration   `protobuf:"bytes,11,opt,name=p99_latency,json=p99Latency,proto3" json:"p99_latency,omitempty"`
+	TotalAttacks      uint64                 `protobuf:"varint,12,opt,name=total_attacks,json=totalAttacks,proto3" json:"total_attacks,omitempty"`
+	TotalRecoveries   uint64                 `protobuf:"varint,13,opt,name=total_recoveries,json=totalRecoveries,proto3" json:"total_recoveries,omitempty"`
+	SuccessRecoveries uint64                 `protobuf:"varint,14,opt,name=success_recoveries,json=successRecoveries,proto3" json:"success_recoveries,omitempty"`
+	FailedRecoveries  uint64                 `protobuf:"varint,15,opt,name=failed_recoveries,json=failedRecoveries,proto3" json:"failed_recoveries,omitempty"`
+	FinalNodeStatus   map[string]string      `protobuf:"bytes,16,rep,name=final_node_status,json=finalNodeStatus,proto3" json:"final_node_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Result) GetScenarioName() string {
+	if x != nil {
+		return x.ScenarioName
+	}
+	return ""
+}
+
+func (x *Result) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Result) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Result) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Result) GetInterrupted() bool {
+	if x != nil {
+		return x.Interrupted
+	}
+	return false
+}
+
+func (x *Result) GetTotalRequests() uint64 {
+	if x != nil {
+		return x.TotalRequests
+	}
+	return 0
+}
+
+func (x *Result) GetSuccessRequests() uint64 {
+	if x != nil {
+		return x.SuccessRequests
+	}
+	return 0
+}
+
+func (x *Result) GetFailedRequests() uint64 {
+	if x != nil {
+		return x.FailedRequests
+	}
+	return 0
+}
+
+func (x *Result) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *Result) GetAvgLatency() *durationpb.Duration {
+	if x != nil {
+		return x.AvgLatency
+	}
+	return nil
+}
+
+func (x *Result) GetP99Latency() *durationpb.Duration {
+	if x != nil {
+		return x.P99Latency
+	}
+	return nil
+}
+
+func (x *Result) GetTotalAttacks() uint64 {
+	if x != nil {
+		return x.TotalAttacks
+	}
+	return 0
+}
+
+func (x *Result) GetTotalRecoveries() uint64 {
+	if x != nil {
+		return x.TotalRecoveries
+	}
+	return 0
+}
+
+func (x *Result) GetSuccessRecoveries() uint64 {
+	if x != nil {
+		return x.SuccessRecoveries
+	}
+	return 0
+}
+
+func (x *Result) GetFailedRecoveries() uint64 {
+	if x != nil {
+		return x.FailedRecoveries
+	}
+	return 0
+}
+
+func (x *Result) GetFinalNodeStatus() map[string]string {
+	if x != nil {
+		return x.FinalNodeStatus
+	}
+	return nil
+}
+
+type GetMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsRequest) ProtoMessage() {}
+
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{12}
+}
+
+type StreamMetricsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 配信間隔（未指定で1秒）
+	Interval      *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsRequest) Reset() {
+	*x = StreamMetricsRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsRequest) ProtoMessage() {}
+
+func (x *StreamMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsRequest.ProtoReflect.Descriptor instead.
+func (*StreamMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamMetricsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type Metrics struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Running         bool                   `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
+	TotalRequests   uint64                 `protobuf:"varint,3,opt,name=total_requests,json=totalRequests,proto3" json:"total_requests,omitempty"`
+	SuccessRequests uint64                 `protobuf:"varint,4,opt,name=success_requests,json=successRequests,proto3" json:"success_requests,omitempty"`
+	FailedRequests  uint64                 `protobuf:"varint,5,opt,name=failed_requests,json=failedRequests,proto3" json:"failed_requests,omitempty"`
+	Rps             float64                `protobuf:"fixed64,6,opt,name=rps,proto3" json:"rps,omitempty"`
+	AvgLatency      *durationpb.Duration   `protobuf:"bytes,7,opt,name=avg_latency,json=avgLatency,proto3" json:"avg_latency,omitempty"`
+	P99Latency      *durationpb.Duration   `protobuf:"bytes,8,opt,name=p99_latency,json=p99Latency,proto3" json:"p99_latency,omitempty"`
+	ErrorRate       float64                `protobuf:"fixed64,9,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Metrics) Reset() {
+	*x = Metrics{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metrics) ProtoMessage() {}
+
+func (x *Metrics) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metrics.ProtoReflect.Descriptor instead.
+func (*Metrics) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Metrics) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Metrics) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *Metrics) GetTotalRequests() uint64 {
+	if x != nil {
+		return x.TotalRequests
+	}
+	return 0
+}
+
+func (x *Metrics) GetSuccessRequests() uint64 {
+	if x != nil {
+		return x.SuccessRequests
+	}
+	return 0
+}
+
+func (x *Metrics) GetFailedRequests() uint64 {
+	if x != nil {
+		return x.FailedRequests
+	}
+	return 0
+}
+
+func (x *Metrics) GetRps() float64 {
+	if x != nil {
+		return x.Rps
+	}
+	return 0
+}
+
+func (x *Metrics) GetAvgLatency() *durationpb.Duration {
+	if x != nil {
+		return x.AvgLatency
+	}
+	return nil
+}
+
+func (x *Metrics) GetP99Latency() *durationpb.Duration {
+	if x != nil {
+		return x.P99Latency
+	}
+	return nil
+}
+
+func (x *Metrics) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+type StreamEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 配信するイベント種別（空で全て）: chaos_attack, chaos_resume, recovery_start, recovery_success, recovery_failed,
+	// node_started, node_stopped, node_scaled, client_error_burst, slo_violation
+	Types         []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StreamEventsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	AttackType    string                 `protobuf:"bytes,4,opt,name=attack_type,json=attackType,proto3" json:"attack_type,omitempty"`
+	DelayDuration string                 `protobuf:"bytes,5,opt,name=delay_duration,json=delayDuration,proto3" json:"delay_duration,omitempty"`
+	Attempt       int32                  `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	NodeCount     int32                  `protobuf:"varint,9,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	Added         []string               `protobuf:"bytes,10,rep,name=added,proto3" json:"added,omitempty"`
+	Removed       []string               `protobuf:"bytes,11,rep,name=removed,proto3" json:"removed,omitempty"`
+	Errors        uint64                 `protobuf:"varint,12,opt,name=errors,proto3" json:"errors,omitempty"`
+	Requests      uint64                 `protobuf:"varint,13,opt,name=requests,proto3" json:"requests,omitempty"`
+	Window        string                 `protobuf:"bytes,14,opt,name=window,proto3" json:"window,omitempty"`
+	Metric        string                 `protobuf:"bytes,15,opt,name=metric,proto3" json:"metric,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,16,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Value         float64                `protobuf:"fixed64,17,opt,name=value,proto3" json:"value,omitempty"`
+	Rps           float64                `protobuf:"fixed64,18,opt,name=rps,proto3" json:"rps,omitempty"`
+	AvgLatencyMs  float64                `protobuf:"fixed64,19,opt,name=avg_latency_ms,json=avgLatencyMs,proto3" json:"avg_latency_ms,omitempty"`
+	P99LatencyMs  float64                `protobuf:"fixed64,20,opt,name=p99_latency_ms,json=p99LatencyMs,proto3" json:"p99_latency_ms,omitempty"`
+	ErrorRate     float64                `protobuf:"fixed64,21,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	NodesRunning  int32                  `protobuf:"varint,22,opt,name=nodes_running,json=nodesRunning,proto3" json:"nodes_running,omitempty"`
+	Attacks       uint64                 `protobuf:"varint,23,opt,name=attacks,proto3" json:"attacks,omitempty"`
+	Scenario      string                 `protobuf:"bytes,24,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *Event) GetAttackType() string {
+	if x != nil {
+		return x.AttackType
+	}
+	return ""
+}
+
+func (x *Event) GetDelayDuration() string {
+	if x != nil {
+		return x.DelayDuration
+	}
+	return ""
+}
+
+func (x *Event) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Event) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Event) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Event) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *Event) GetAdded() []string {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *Event) GetRemoved() []string {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *Event) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *Event) GetRequests() uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *Event) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *Event) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *Event) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Event) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Event) GetRps() float64 {
+	if x != nil {
+		return x.Rps
+	}
+	return 0
+}
+
+func (x *Event) GetAvgLatencyMs() float64 {
+	if x != nil {
+		return x.AvgLatencyMs
+	}
+	return 0
+}
+
+func (x *Event) GetP99LatencyMs() float64 {
+	if x != nil {
+		return x.P99LatencyMs
+	}
+	return 0
+}
+
+func (x *Event) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *Event) GetNodesRunning() int32 {
+	if x != nil {
+		return x.NodesRunning
+	}
+	return 0
+}
+
+func (x *Event) GetAttacks() uint64 {
+	if x != nil {
+		return x.Attacks
+	}
+	return 0
+}
+
+func (x *Event) GetScenario() string {
+	if x != nil {
+		return x.Scenario
+	}
+	return ""
+}
+
+type ScenarioConfig_Client struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       int32                  `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
+	WriteRatio    float64                `protobuf:"fixed64,2,opt,name=write_ratio,json=writeRatio,proto3" json:"write_ratio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScenarioConfig_Client) Reset() {
+	*x = ScenarioConfig_Client{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScenarioConfig_Client) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScenarioConfig_Client) ProtoMessage() {}
+
+func (x *ScenarioConfig_Client) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScenarioConfig_Client.ProtoReflect.Descriptor instead.
+func (*ScenarioConfig_Client) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *ScenarioConfig_Client) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *ScenarioConfig_Client) GetWriteRatio() float64 {
+	if x != nil {
+		return x.WriteRatio
+	}
+	return 0
+}
+
+type ScenarioConfig_Chaos struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Enabled  bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Interval *durationpb.Duration   `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Targets  int32                  `protobuf:"varint,3,opt,name=targets,proto3" json:"targets,omitempty"`
+	// kill, suspend, delay
+	AttackTypes   []string `protobuf:"bytes,4,rep,name=attack_types,json=attackTypes,proto3" json:"attack_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScenarioConfig_Chaos) Reset() {
+	*x = ScenarioConfig_Chaos{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScenarioConfig_Chaos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScenarioConfig_Chaos) ProtoMessage() {}
+
+func (x *ScenarioConfig_Chaos) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScenarioConfig_Chaos.ProtoReflect.Descriptor instead.
+func (*ScenarioConfig_Chaos) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{7, 1}
+}
+
+func (x *ScenarioConfig_Chaos) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ScenarioConfig_Chaos) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *ScenarioConfig_Chaos) GetTargets() int32 {
+	if x != nil {
+		return x.Targets
+	}
+	return 0
+}
+
+func (x *ScenarioConfig_Chaos) GetAttackTypes() []string {
+	if x != nil {
+		return x.AttackTypes
+	}
+	return nil
+}
+
+type ScenarioConfig_Recovery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Delay         *durationpb.Duration   `protobuf:"bytes,2,opt,name=delay,proto3" json:"delay,omitempty"`
+	MaxRetries    int32                  `protobuf:"varint,3,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScenarioConfig_Recovery) Reset() {
+	*x = ScenarioConfig_Recovery{}
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScenarioConfig_Recovery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScenarioConfig_Recovery) ProtoMessage() {}
+
+func (x *ScenarioConfig_Recovery) ProtoReflect() protoreflect.Message {
+	mi := &file_chaoskvs_v1_chaoskvs_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScenarioConfig_Recovery.ProtoReflect.Descriptor instead.
+func (*ScenarioConfig_Recovery) Descriptor() ([]byte, []int) {
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP(), []int{7, 2}
+}
+
+func (x *ScenarioConfig_Recovery) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ScenarioConfig_Recovery) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+func (x *ScenarioConfig_Recovery) GetMaxRetries() int32 {
+	if x != nil {
+		return x.MaxRetries
+	}
+	return 0
+}
+
+var File_chaoskvs_v1_chaoskvs_proto protoreflect.FileDescriptor
+
+const file_chaoskvs_v1_chaoskvs_proto_rawDesc = "" +
+	"\n" +
+	"\x1achaoskvs/v1/chaoskvs.proto\x12\vchaoskvs.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x12\n" +
+	"\x10GetStatusRequest\"\xd9\x01\n" +
+	"\x06Status\x12\x18\n" +
+	"\arunning\x18\x01 \x01(\bR\arunning\x12#\n" +
+	"\rscenario_name\x18\x02 \x01(\tR\fscenarioName\x12\x1d\n" +
+	"\n" +
+	"node_count\x18\x03 \x01(\x05R\tnodeCount\x12#\n" +
+	"\rrunning_nodes\x18\x04 \x01(\x05R\frunningNodes\x12#\n" +
+	"\rstopped_nodes\x18\x05 \x01(\x05R\fstoppedNodes\x12'\n" +
+	"\x0fsuspended_nodes\x18\x06 \x01(\x05R\x0esuspendedNodes\"\x92\x02\n" +
+	"\x10ListNodesRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x03(\tR\x06status\x12\x12\n" +
+	"\x04zone\x18\x02 \x03(\tR\x04zone\x12A\n" +
+	"\x06labels\x18\x03 \x03(\v2).chaoskvs.v1.ListNodesRequest.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\x05 \x01(\bR\x04desc\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\a \x01(\x05R\x06offset\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"R\n" +
+	"\x11ListNodesResponse\x12'\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x11.chaoskvs.v1.NodeR\x05nodes\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xf9\x01\n" +
+	"\x04Node\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12/\n" +
+	"\x05delay\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x05delay\x12\x12\n" +
+	"\x04zone\x18\x05 \x01(\tR\x04zone\x125\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1d.chaoskvs.v1.Node.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9a\x02\n" +
+	"\x11NodeActionRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12=\n" +
+	"\x06action\x18\x02 \x01(\x0e2%.chaoskvs.v1.NodeActionRequest.ActionR\x06action\x12/\n" +
+	"\x05delay\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x05delay\"|\n" +
+	"\x06Action\x12\x16\n" +
+	"\x12ACTION_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vACTION_KILL\x10\x01\x12\x12\n" +
+	"\x0eACTION_SUSPEND\x10\x02\x12\x11\n" +
+	"\rACTION_RESUME\x10\x03\x12\x10\n" +
+	"\fACTION_START\x10\x04\x12\x10\n" +
+	"\fACTION_DELAY\x10\x05\"\xb4\x01\n" +
+	"\x14StartScenarioRequest\x12\x16\n" +
+	"\x06preset\x18\x01 \x01(\tR\x06preset\x127\n" +
+	"\bscenario\x18\x02 \x01(\v2\x1b.chaoskvs.v1.ScenarioConfigR\bscenario\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x14\n" +
+	"\x05nodes\x18\x04 \x01(\x05R\x05nodes\"\xbe\x05\n" +
+	"\x0eScenarioConfig\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x1d\n" +
+	"\n" +
+	"node_count\x18\x04 \x01(\x05R\tnodeCount\x12\x14\n" +
+	"\x05zones\x18\x05 \x03(\tR\x05zones\x12:\n" +
+	"\x06client\x18\x06 \x01(\v2\".chaoskvs.v1.ScenarioConfig.ClientR\x06client\x127\n" +
+	"\x05chaos\x18\a \x01(\v2!.chaoskvs.v1.ScenarioConfig.ChaosR\x05chaos\x12@\n" +
+	"\brecovery\x18\b \x01(\v2$.chaoskvs.v1.ScenarioConfig.RecoveryR\brecovery\x1aC\n" +
+	"\x06Client\x12\x18\n" +
+	"\aworkers\x18\x01 \x01(\x05R\aworkers\x12\x1f\n" +
+	"\vwrite_ratio\x18\x02 \x01(\x01R\n" +
+	"writeRatio\x1a\x95\x01\n" +
+	"\x05Chaos\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x18\n" +
+	"\atargets\x18\x03 \x01(\x05R\atargets\x12!\n" +
+	"\fattack_types\x18\x04 \x03(\tR\vattackTypes\x1av\n" +
+	"\bRecovery\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12/\n" +
+	"\x05delay\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x05delay\x12\x1f\n" +
+	"\vmax_retries\x18\x03 \x01(\x05R\n" +
+	"maxRetries\"J\n" +
+	"\x15StartScenarioResponse\x12\x1a\n" +
+	"\bscenario\x18\x01 \x01(\tR\bscenario\x12\x15\n" +
+	"\x06run_id\x18\x02 \x01(\tR\x05runId\"\x15\n" +
+	"\x13StopScenarioRequest\"Z\n" +
+	"\x14StopScenarioResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12+\n" +
+	"\x06result\x18\x02 \x01(\v2\x13.chaoskvs.v1.ResultR\x06result\"\xd0\x06\n" +
+	"\x06Result\x12#\n" +
+	"\rscenario_name\x18\x01 \x01(\tR\fscenarioName\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12 \n" +
+	"\vinterrupted\x18\x05 \x01(\bR\vinterrupted\x12%\n" +
+	"\x0etotal_requests\x18\x06 \x01(\x04R\rtotalRequests\x12)\n" +
+	"\x10success_requests\x18\a \x01(\x04R\x0fsuccessRequests\x12'\n" +
+	"\x0ffailed_requests\x18\b \x01(\x04R\x0efailedRequests\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\t \x01(\x01R\terrorRate\x12:\n" +
+	"\vavg_latency\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"avgLatency\x12:\n" +
+	"\vp99_latency\x18\v \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"p99Latency\x12#\n" +
+	"\rtotal_attacks\x18\f \x01(\x04R\ftotalAttacks\x12)\n" +
+	"\x10total_recoveries\x18\r \x01(\x04R\x0ftotalRecoveries\x12-\n" +
+	"\x12success_recoveries\x18\x0e \x01(\x04R\x11successRecoveries\x12+\n" +
+	"\x11failed_recoveries\x18\x0f \x01(\x04R\x10failedRecoveries\x12T\n" +
+	"\x11final_node_status\x18\x10 \x03(\v2(.chaoskvs.v1.Result.FinalNodeStatusEntryR\x0ffinalNodeStatus\x1aB\n" +
+	"\x14FinalNodeStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x13\n" +
+	"\x11GetMetricsRequest\"M\n" +
+	"\x14StreamMetricsRequest\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\x81\x03\n" +
+	"\aMetrics\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x18\n" +
+	"\arunning\x18\x02 \x01(\bR\arunning\x12%\n" +
+	"\x0etotal_requests\x18\x03 \x01(\x04R\rtotalRequests\x12)\n" +
+	"\x10success_requests\x18\x04 \x01(\x04R\x0fsuccessRequests\x12'\n" +
+	"\x0ffailed_requests\x18\x05 \x01(\x04R\x0efailedRequests\x12\x10\n" +
+	"\x03rps\x18\x06 \x01(\x01R\x03rps\x12:\n" +
+	"\vavg_latency\x18\a \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"avgLatency\x12:\n" +
+	"\vp99_latency\x18\b \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"p99Latency\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\t \x01(\x01R\terrorRate\"+\n" +
+	"\x13StreamEventsRequest\x12\x14\n" +
+	"\x05types\x18\x01 \x03(\tR\x05types\"\xbd\x05\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x17\n" +
+	"\anode_id\x18\x03 \x01(\tR\x06nodeId\x12\x1f\n" +
+	"\vattack_type\x18\x04 \x01(\tR\n" +
+	"attackType\x12%\n" +
+	"\x0edelay_duration\x18\x05 \x01(\tR\rdelayDuration\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"node_count\x18\t \x01(\x05R\tnodeCount\x12\x14\n" +
+	"\x05added\x18\n" +
+	" \x03(\tR\x05added\x12\x18\n" +
+	"\aremoved\x18\v \x03(\tR\aremoved\x12\x16\n" +
+	"\x06errors\x18\f \x01(\x04R\x06errors\x12\x1a\n" +
+	"\brequests\x18\r \x01(\x04R\brequests\x12\x16\n" +
+	"\x06window\x18\x0e \x01(\tR\x06window\x12\x16\n" +
+	"\x06metric\x18\x0f \x01(\tR\x06metric\x12\x1c\n" +
+	"\tthreshold\x18\x10 \x01(\x01R\tthreshold\x12\x14\n" +
+	"\x05value\x18\x11 \x01(\x01R\x05value\x12\x10\n" +
+	"\x03rps\x18\x12 \x01(\x01R\x03rps\x12$\n" +
+	"\x0eavg_latency_ms\x18\x13 \x01(\x01R\favgLatencyMs\x12$\n" +
+	"\x0ep99_latency_ms\x18\x14 \x01(\x01R\fp99LatencyMs\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\x15 \x01(\x01R\terrorRate\x12#\n" +
+	"\rnodes_running\x18\x16 \x01(\x05R\fnodesRunning\x12\x18\n" +
+	"\aattacks\x18\x17 \x01(\x04R\aattacks\x12\x1a\n" +
+	"\bscenario\x18\x18 \x01(\tR\bscenario2\xdd\x04\n" +
+	"\bChaosKVS\x12?\n" +
+	"\tGetStatus\x12\x1d.chaoskvs.v1.GetStatusRequest\x1a\x13.chaoskvs.v1.Status\x12J\n" +
+	"\tListNodes\x12\x1d.chaoskvs.v1.ListNodesRequest\x1a\x1e.chaoskvs.v1.ListNodesResponse\x12?\n" +
+	"\n" +
+	"NodeAction\x12\x1e.chaoskvs.v1.NodeActionRequest\x1a\x11.chaoskvs.v1.Node\x12V\n" +
+	"\rStartScenario\x12!.chaoskvs.v1.StartScenarioRequest\x1a\".chaoskvs.v1.StartScenarioResponse\x12S\n" +
+	"\fStopScenario\x12 .chaoskvs.v1.StopScenarioRequest\x1a!.chaoskvs.v1.StopScenarioResponse\x12B\n" +
+	"\n" +
+	"GetMetrics\x12\x1e.chaoskvs.v1.GetMetricsRequest\x1a\x14.chaoskvs.v1.Metrics\x12J\n" +
+	"\rStreamMetrics\x12!.chaoskvs.v1.StreamMetricsRequest\x1a\x14.chaoskvs.v1.Metrics0\x01\x12F\n" +
+	"\fStreamEvents\x12 .chaoskvs.v1.StreamEventsRequest\x1a\x12.chaoskvs.v1.Event0\x01B;Z9github.com/nyasuto/chaos-kvs/proto/chaoskvs/v1;chaoskvsv1b\x06proto3"
+
+var (
+	file_chaoskvs_v1_chaoskvs_proto_rawDescOnce sync.Once
+	file_chaoskvs_v1_chaoskvs_proto_rawDescData []byte
+)
+
+func file_chaoskvs_v1_chaoskvs_proto_rawDescGZIP() []byte {
+	file_chaoskvs_v1_chaoskvs_proto_rawDescOnce.Do(func() {
+		file_chaoskvs_v1_chaoskvs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_chaoskvs_v1_chaoskvs_proto_rawDesc), len(file_chaoskvs_v1_chaoskvs_proto_rawDesc)))
+	})
+	return file_chaoskvs_v1_chaoskvs_proto_rawDescData
+}
+
+var file_chaoskvs_v1_chaoskvs_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_chaoskvs_v1_chaoskvs_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_chaoskvs_v1_chaoskvs_proto_goTypes = []any{
+	(NodeActionRequest_Action)(0),   // 0: chaoskvs.v1.NodeActionRequest.Action
+	(*GetStatusRequest)(nil),        // 1: chaoskvs.v1.GetStatusRequest
+	(*Status)(nil),                  // 2: chaoskvs.v1.Status
+	(*ListNodesRequest)(nil),        // 3: chaoskvs.v1.ListNodesRequest
+	(*ListNodesResponse)(nil),       // 4: chaoskvs.v1.ListNodesResponse
+	(*Node)(nil),                    // 5: chaoskvs.v1.Node
+	(*NodeActionRequest)(nil),       // 6: chaoskvs.v1.NodeActionRequest
+	(*StartScenarioRequest)(nil),    // 7: chaoskvs.v1.StartScenarioRequest
+	(*ScenarioConfig)(nil),          // 8: chaoskvs.v1.ScenarioConfig
+	(*StartScenarioResponse)(nil),   // 9: chaoskvs.v1.StartScenarioResponse
+	(*StopScenarioRequest)(nil),     // 10: chaoskvs.v1.StopScenarioRequest
+	(*StopScenarioResponse)(nil),    // 11: chaoskvs.v1.StopScenarioResponse
+	(*Result)(nil),                  // 12: chaoskvs.v1.Result
+	(*GetMetricsRequest)(nil),       // 13: chaoskvs.v1.GetMetricsRequest
+	(*StreamMetricsRequest)(nil),    // 14: chaoskvs.v1.StreamMetricsRequest
+	(*Metrics)(nil),                 // 15: chaoskvs.v1.Metrics
+	(*StreamEventsRequest)(nil),     // 16: chaoskvs.v1.StreamEventsRequest
+	(*Event)(nil),                   // 17: chaoskvs.v1.Event
+	nil,                             // 18: chaoskvs.v1.ListNodesRequest.LabelsEntry
+	nil,                             // 19: chaoskvs.v1.Node.LabelsEntry
+	(*ScenarioConfig_Client)(nil),   // 20: chaoskvs.v1.ScenarioConfig.Client
+	(*ScenarioConfig_Chaos)(nil),    // 21: chaoskvs.v1.ScenarioConfig.Chaos
+	(*ScenarioConfig_Recovery)(nil), // 22: chaoskvs.v1.ScenarioConfig.Recovery
+	nil,                             // 23: chaoskvs.v1.Result.FinalNodeStatusEntry
+	(*durationpb.Duration)(nil),     // 24: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),   // 25: google.protobuf.Timestamp
+}
+var file_chaoskvs_v1_chaoskvs_proto_depIdxs = []int32{
+	18, // 0: chaoskvs.v1.ListNodesRequest.labels:type_name -> chaoskvs.v1.ListNodesRequest.LabelsEntry
+	5,  // 1: chaoskvs.v1.ListNodesResponse.nodes:type_name -> chaoskvs.v1.Node
+	24, // 2: chaoskvs.v1.Node.delay:type_name -> google.protobuf.Duration
+	19, // 3: chaoskvs.v1.Node.labels:type_name -> chaoskvs.v1.Node.LabelsEntry
+	0,  // 4: chaoskvs.v1.NodeActionRequest.action:type_name -> chaoskvs.v1.NodeActionRequest.Action
+	24, // 5: chaoskvs.v1.NodeActionRequest.delay:type_name -> google.protobuf.Duration
+	8,  // 6: chaoskvs.v1.StartScenarioRequest.scenario:type_name -> chaoskvs.v1.ScenarioConfig
+	24, // 7: chaoskvs.v1.StartScenarioRequest.duration:type_name -> google.protobuf.Duration
+	24, // 8: chaoskvs.v1.ScenarioConfig.duration:type_name -> google.protobuf.Duration
+	20, // 9: chaoskvs.v1.ScenarioConfig.client:type_name -> chaoskvs.v1.ScenarioConfig.Client
+	21, // 10: chaoskvs.v1.ScenarioConfig.chaos:type_name -> chaoskvs.v1.ScenarioConfig.Chaos
+	22, // 11: chaoskvs.v1.ScenarioConfig.recovery:type_name -> chaoskvs.v1.ScenarioConfig.Recovery
+	12, // 12: chaoskvs.v1.StopScenarioResponse.result:type_name -> chaoskvs.v1.Result
+	25, // 13: chaoskvs.v1.Result.start_time:type_name -> google.protobuf.Timestamp
+	25, // 14: chaoskvs.v1.Result.end_time:type_name -> google.protobuf.Timestamp
+	24, // 15: chaoskvs.v1.Result.duration:type_name -> google.protobuf.Duration
+	24, // 16: chaoskvs.v1.Result.avg_latency:type_name -> google.protobuf.Duration
+	24, // 17: chaoskvs.v1.Result.p99_latency:type_name -> google.protobuf.Duration
+	23, // 18: chaoskvs.v1.Result.final_node_status:type_name -> chaoskvs.v1.Result.FinalNodeStatusEntry
+	24, // 19: chaoskvs.v1.StreamMetricsRequest.interval:type_name -> google.protobuf.Duration
+	25, // 20: chaoskvs.v1.Metrics.timestamp:type_name -> google.protobuf.Timestamp
+	24, // 21: chaoskvs.v1.Metrics.avg_latency:type_name -> google.protobuf.Duration
+	24, // 22: chaoskvs.v1.Metrics.p99_latency:type_name -> google.protobuf.Duration
+	25, // 23: chaoskvs.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	24, // 24: chaoskvs.v1.ScenarioConfig.Chaos.interval:type_name -> google.protobuf.Duration
+	24, // 25: chaoskvs.v1.ScenarioConfig.Recovery.delay:type_name -> google.protobuf.Duration
+	1,  // 26: chaoskvs.v1.ChaosKVS.GetStatus:input_type -> chaoskvs.v1.GetStatusRequest
+	3,  // 27: chaoskvs.v1.ChaosKVS.ListNodes:input_type -> chaoskvs.v1.ListNodesRequest
+	6,  // 28: chaoskvs.v1.ChaosKVS.NodeAction:input_type -> chaoskvs.v1.NodeActionRequest
+	7,  // 29: chaoskvs.v1.ChaosKVS.StartScenario:input_type -> chaoskvs.v1.StartScenarioRequest
+	10, // 30: chaoskvs.v1.ChaosKVS.StopScenario:input_type -> chaoskvs.v1.StopScenarioRequest
+	13, // 31: chaoskvs.v1.ChaosKVS.GetMetrics:input_type -> chaoskvs.v1.GetMetricsRequest
+	14, // 32: chaoskvs.v1.ChaosKVS.StreamMetrics:input_type -> chaoskvs.v1.StreamMetricsRequest
+	16, // 33: chaoskvs.v1.ChaosKVS.StreamEvents:input_type -> chaoskvs.v1.StreamEventsRequest
+	2,  // 34: chaoskvs.v1.ChaosKVS.GetStatus:output_type -> chaoskvs.v1.Status
+	4,  // 35: chaoskvs.v1.ChaosKVS.ListNodes:output_type -> chaoskvs.v1.ListNodesResponse
+	5,  // 36: chaoskvs.v1.ChaosKVS.NodeAction:output_type -> chaoskvs.v1.Node
+	9,  // 37: chaoskvs.v1.ChaosKVS.StartScenario:output_type -> chaoskvs.v1.StartScenarioResponse
+	11, // 38: chaoskvs.v1.ChaosKVS.StopScenario:output_type -> chaoskvs.v1.StopScenarioResponse
+	15, // 39: chaoskvs.v1.ChaosKVS.GetMetrics:output_type -> chaoskvs.v1.Metrics
+	15, // 40: chaoskvs.v1.ChaosKVS.StreamMetrics:output_type -> chaoskvs.v1.Metrics
+	17, // 41: chaoskvs.v1.ChaosKVS.StreamEvents:output_type -> chaoskvs.v1.Event
+	34, // [34:42] is the sub-list for method output_type
+	26, // [26:34] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_chaoskvs_v1_chaoskvs_proto_init() }
+func file_chaoskvs_v1_chaoskvs_proto_init() {
+	if File_chaoskvs_v1_chaoskvs_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chaoskvs_v1_chaoskvs_proto_rawDesc), len(file_chaoskvs_v1_chaoskvs_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_chaoskvs_v1_chaoskvs_proto_goTypes,
+		DependencyIndexes: file_chaoskvs_v1_chaoskvs_proto_depIdxs,
+		EnumInfos:         file_chaoskvs_v1_chaoskvs_proto_enumTypes,
+		MessageInfos:      file_chaoskvs_v1_chaoskvs_proto_msgTypes,
+	}.Build()
+	File_chaoskvs_v1_chaoskvs_proto = out.File
+	file_chaoskvs_v1_chaoskvs_proto_goTypes = nil
+	file_chaoskvs_v1_chaoskvs_proto_depIdxs = nil
+}
@@ -0,0 +1,416 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: chaoskvs/v1/chaoskvs.proto
+
+// ChaosKVS のシナリオ制御・障害注入・メトリクス/イベント配信用 gRPC API。
+// HTTP API (/api/...) と同じサーバー状態を操作する。
+//
+// 認証が有効な場合は metadata "authorization: Bearer <token>" を指定する。
+// 参照系は reader 以上、StartScenario / StopScenario / NodeAction は operator ロールが必要。
+
+package chaoskvsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ChaosKVS_GetStatus_FullMethodName     = "/chaoskvs.v1.ChaosKVS/GetStatus"
+	ChaosKVS_ListNodes_FullMethodName     = "/chaoskvs.v1.ChaosKVS/ListNodes"
+	ChaosKVS_NodeAction_FullMethodName    = "/chaoskvs.v1.ChaosKVS/NodeAction"
+	ChaosKVS_StartScenario_FullMethodName = "/chaoskvs.v1.ChaosKVS/StartScenario"
+	ChaosKVS_StopScenario_FullMethodName  = "/chaoskvs.v1.ChaosKVS/StopScenario"
+	ChaosKVS_GetMetrics_FullMethodName    = "/chaoskvs.v1.ChaosKVS/GetMetrics"
+	ChaosKVS_StreamMetrics_FullMethodName = "/chaoskvs.v1.ChaosKVS/StreamMetrics"
+	ChaosKVS_StreamEvents_FullMethodName  = "/chaoskvs.v1.ChaosKVS/StreamEvents"
+)
+
+// ChaosKVSClient is the client API for ChaosKVS service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChaosKVSClient interface {
+	// GetStatus はシナリオとクラスタの状態を返す
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// ListNodes はノード一覧を返す（フィルタ・ソート・ページネーション）
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+	// NodeAction はノードへの障害注入・復旧を行う
+	NodeAction(ctx context.Context, in *NodeActionRequest, opts ...grpc.CallOption) (*Node, error)
+	// StartScenario はシナリオをバックグラウンドで開始する
+	StartScenario(ctx context.Context, in *StartScenarioRequest, opts ...grpc.CallOption) (*StartScenarioResponse, error)
+	// StopScenario は実行中のシナリオを中断し、途中までの結果を返す
+	StopScenario(ctx context.Context, in *StopScenarioRequest, opts ...grpc.CallOption) (*StopScenarioResponse, error)
+	// GetMetrics はクライアントメトリクスを返す
+	GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*Metrics, error)
+	// StreamMetrics はメトリクスを一定間隔で配信する
+	StreamMetrics(ctx context.Context, in *StreamMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Metrics], error)
+	// StreamEvents はカオス/復旧イベントを配信する
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type chaosKVSClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChaosKVSClient(cc grpc.ClientConnInterface) ChaosKVSClient {
+	return &chaosKVSClient{cc}
+}
+
+func (c *chaosKVSClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, ChaosKVS_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosKVSClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, ChaosKVS_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosKVSClient) NodeAction(ctx context.Context, in *NodeActionRequest, opts ...grpc.CallOption) (*Node, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Node)
+	err := c.cc.Invoke(ctx, ChaosKVS_NodeAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosKVSClient) StartScenario(ctx context.Context, in *StartScenarioRequest, opts ...grpc.CallOption) (*StartScenarioResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartScenarioResponse)
+	err := c.cc.Invoke(ctx, ChaosKVS_StartScenario_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosKVSClient) StopScenario(ctx context.Context, in *StopScenarioRequest, opts ...grpc.CallOption) (*StopScenarioResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopScenarioResponse)
+	err := c.cc.Invoke(ctx, ChaosKVS_StopScenario_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosKVSClient) GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*Metrics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Metrics)
+	err := c.cc.Invoke(ctx, ChaosKVS_GetMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosKVSClient) StreamMetrics(ctx context.Context, in *StreamMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Metrics], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChaosKVS_ServiceDesc.Streams[0], ChaosKVS_StreamMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamMetricsRequest, Metrics]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChaosKVS_StreamMetricsClient = grpc.ServerStreamingClient[Metrics]
+
+func (c *chaosKVSClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChaosKVS_ServiceDesc.Streams[1], ChaosKVS_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChaosKVS_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+// ChaosKVSServer is the server API for ChaosKVS service.
+// All implementations must embed UnimplementedChaosKVSServer
+// for forward compatibility.
+type ChaosKVSServer interface {
+	// GetStatus はシナリオとクラスタの状態を返す
+	GetStatus(context.Context, *GetStatusRequest) (*Status, error)
+	// ListNodes はノード一覧を返す（フィルタ・ソート・ページネーション）
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	// NodeAction はノードへの障害注入・復旧を行う
+	NodeAction(context.Context, *NodeActionRequest) (*Node, error)
+	// StartScenario はシナリオをバックグラウンドで開始する
+	StartScenario(context.Context, *StartScenarioRequest) (*StartScenarioResponse, error)
+	// StopScenario は実行中のシナリオを中断し、途中までの結果を返す
+	StopScenario(context.Context, *StopScenarioRequest) (*StopScenarioResponse, error)
+	// GetMetrics はクライアントメトリクスを返す
+	GetMetrics(context.Context, *GetMetricsRequest) (*Metrics, error)
+	// StreamMetrics はメトリクスを一定間隔で配信する
+	StreamMetrics(*StreamMetricsRequest, grpc.ServerStreamingServer[Metrics]) error
+	// StreamEvents はカオス/復旧イベントを配信する
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedChaosKVSServer()
+}
+
+// UnimplementedChaosKVSServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChaosKVSServer struct{}
+
+func (UnimplementedChaosKVSServer) GetStatus(context.Context, *GetStatusRequest) (*Status, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedChaosKVSServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedChaosKVSServer) NodeAction(context.Context, *NodeActionRequest) (*Node, error) {
+	return nil, status.Error(codes.Unimplemented, "method NodeAction not implemented")
+}
+func (UnimplementedChaosKVSServer) StartScenario(context.Context, *StartScenarioRequest) (*StartScenarioResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartScenario not implemented")
+}
+func (UnimplementedChaosKVSServer) StopScenario(context.Context, *StopScenarioRequest) (*StopScenarioResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method StopScenario not implemented")
+}
+func (UnimplementedChaosKVSServer) GetMetrics(context.Context, *GetMetricsRequest) (*Metrics, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMetrics not implemented")
+}
+func (UnimplementedChaosKVSServer) StreamMetrics(*StreamMetricsRequest, grpc.ServerStreamingServer[Metrics]) error {
+	return status.Error(codes.Unimplemented, "method StreamMetrics not implemented")
+}
+func (UnimplementedChaosKVSServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Error(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedChaosKVSServer) mustEmbedUnimplementedChaosKVSServer() {}
+func (UnimplementedChaosKVSServer) testEmbeddedByValue()                  {}
+
+// UnsafeChaosKVSServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChaosKVSServer will
+// result in compilation errors.
+type UnsafeChaosKVSServer interface {
+	mustEmbedUnimplementedChaosKVSServer()
+}
+
+func RegisterChaosKVSServer(s grpc.ServiceRegistrar, srv ChaosKVSServer) {
+	// If the following call panics, it indicates UnimplementedChaosKVSServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChaosKVS_ServiceDesc, srv)
+}
+
+func _ChaosKVS_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosKVSServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosKVS_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosKVSServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosKVS_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosKVSServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosKVS_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosKVSServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosKVS_NodeAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosKVSServer).NodeAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosKVS_NodeAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosKVSServer).NodeAction(ctx, req.(*NodeActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosKVS_StartScenario_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartScenarioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosKVSServer).StartScenario(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosKVS_StartScenario_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosKVSServer).StartScenario(ctx, req.(*StartScenarioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosKVS_StopScenario_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopScenarioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosKVSServer).StopScenario(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosKVS_StopScenario_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosKVSServer).StopScenario(ctx, req.(*StopScenarioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosKVS_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosKVSServer).GetMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChaosKVS_GetMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosKVSServer).GetMetrics(ctx, req.(*GetMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChaosKVS_StreamMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChaosKVSServer).StreamMetrics(m, &grpc.GenericServerStream[StreamMetricsRequest, Metrics]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChaosKVS_StreamMetricsServer = grpc.ServerStreamingServer[Metrics]
+
+func _ChaosKVS_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChaosKVSServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChaosKVS_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+// ChaosKVS_ServiceDesc is the grpc.ServiceDesc for ChaosKVS service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChaosKVS_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chaoskvs.v1.ChaosKVS",
+	HandlerType: (*ChaosKVSServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _ChaosKVS_GetStatus_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _ChaosKVS_ListNodes_Handler,
+		},
+		{
+			MethodName: "NodeAction",
+			Handler:    _ChaosKVS_NodeAction_Handler,
+		},
+		{
+			MethodName: "StartScenario",
+			Handler:    _ChaosKVS_StartScenario_Handler,
+		},
+		{
+			MethodName: "StopScenario",
+			Handler:    _ChaosKVS_StopScenario_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _ChaosKVS_GetMetrics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMetrics",
+			Handler:       _ChaosKVS_StreamMetrics_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _ChaosKVS_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "chaoskvs/v1/chaoskvs.proto",
+}